@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"avito-shop/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	healthcheckURL   string
+	healthcheckReady bool
+)
+
+// healthcheckCmd опрашивает /healthz (или /readyz) запущенного сервиса, удобно для HEALTHCHECK в Dockerfile.
+var healthcheckCmd = &cobra.Command{
+	Use:   "healthcheck",
+	Short: "Check that the running HTTP server is healthy",
+	Long: `Check that the running HTTP server is healthy.
+Exits with a non-zero status if /healthz (or /readyz with --ready) does not respond with 200.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		url := healthcheckURL
+		if url == "" {
+			cfg, err := config.MustLoad(cfgFile)
+			if err != nil {
+				return err
+			}
+			host, port, err := net.SplitHostPort(cfg.HTTPServer.ListenAddr())
+			if err != nil {
+				return err
+			}
+			if host == "" || host == "0.0.0.0" || host == "::" {
+				host = "127.0.0.1"
+			}
+			path := "/healthz"
+			if healthcheckReady {
+				path = "/readyz"
+			}
+			url = fmt.Sprintf("http://%s%s", net.JoinHostPort(host, port), path)
+		}
+
+		client := &http.Client{Timeout: 3 * time.Second}
+		resp, err := client.Get(url)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s responded with status %d", url, resp.StatusCode)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(healthcheckCmd)
+
+	healthcheckCmd.Flags().StringVar(&healthcheckURL, "url", "", "full URL to probe (overrides address from config)")
+	healthcheckCmd.Flags().BoolVar(&healthcheckReady, "ready", false, "probe /readyz instead of /healthz")
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"avito-shop/internal/config"
+	_ "avito-shop/internal/service/shop/storage/mysql"
 
 	"github.com/spf13/cobra"
 )
@@ -64,6 +65,12 @@ to quickly create a Cobra application.`,
             FOREIGN KEY (user_id) REFERENCES users(id),
     		UNIQUE unique_user_item (user_id, item_name)
         );`,
+			`CREATE TABLE IF NOT EXISTS schema_migrations (
+            version INT PRIMARY KEY,
+            applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+        );`,
+			// Таблицы выше соответствуют первой версии схемы, остальные миграции сервис применит при запуске.
+			`INSERT IGNORE INTO schema_migrations (version) VALUES (1);`,
 			//`GRANT ALL PRIVILEGES ON test_db.* TO 'user'@'%';`,
 			`CREATE DATABASE IF NOT EXISTS test_db;`,
 			`GRANT ALL PRIVILEGES ON test_db.* TO 'user'@'%';`,
@@ -87,6 +94,12 @@ to quickly create a Cobra application.`,
 			quantity INT DEFAULT 0,
 			UNIQUE unique_user_item (user_id, item_name)
 );`,
+			`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INT PRIMARY KEY,
+			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);`,
+			// Таблицы выше соответствуют первой версии схемы, остальные миграции сервис применит при запуске.
+			`INSERT IGNORE INTO schema_migrations (version) VALUES (1);`,
 		}
 
 		for _, query := range queries {
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"avito-shop/internal/config"
+	"avito-shop/internal/service/shop"
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/spf13/cobra"
+)
+
+// roleCmd назначает пользователю роль, от которой зависят его лимиты переводов (transfer_limits.roles),
+// и записывает смену роли в журнал аудита от имени shop.AuditCLI.
+var roleCmd = &cobra.Command{
+	Use:          "role <username> <role>",
+	Short:        "Назначить пользователю роль",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.MustLoad(cfgFile)
+		if err != nil {
+			return err
+		}
+
+		db, _, err := openDatabase(cfg.DB)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		ctx := context.Background()
+		username, role := args[0], args[1]
+		var (
+			ir     storage.InfoResponse
+			before string
+		)
+		id, err := db.GetInfo(ctx, &ir, username)
+		if err == nil {
+			before, err = db.GetUserRole(ctx, id)
+		}
+		if err == nil {
+			err = db.SetUserRole(ctx, username, role)
+		}
+		if err != nil {
+			if errors.Is(err, storage.ErrUserNotFound) {
+				return fmt.Errorf("user %q not found", username)
+			}
+			return err
+		}
+
+		e := &storage.AuditEntry{Actor: shop.AuditCLI, Action: shop.AuditRoleSet, Target: "user:" + username, CreatedAt: time.Now()}
+		e.Before, _ = json.Marshal(map[string]string{"role": before})
+		e.After, _ = json.Marshal(map[string]string{"role": role})
+		if err = db.AppendAudit(ctx, e); err != nil {
+			return fmt.Errorf("role changed, but the audit entry was not written: %w", err)
+		}
+
+		fmt.Printf("User %s now has role %s\n", username, role)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(roleCmd)
+}
@@ -1,21 +1,42 @@
 package cmd
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
+	"avito-shop/internal/cache"
 	"avito-shop/internal/config"
+	"avito-shop/internal/http-server/api"
+	"avito-shop/internal/http-server/handlers/health"
 	urls "avito-shop/internal/http-server/handlers/url"
 	mwJWT "avito-shop/internal/http-server/middleware"
 	mwLogger "avito-shop/internal/http-server/middleware/logger"
+	mwMetrics "avito-shop/internal/http-server/middleware/metrics"
+	mwOpenAPI "avito-shop/internal/http-server/middleware/openapi"
+	mwRequestInfo "avito-shop/internal/http-server/middleware/requestinfo"
+	mwTracing "avito-shop/internal/http-server/middleware/tracing"
+	"avito-shop/internal/metrics"
 	"avito-shop/internal/service/shop"
+	"avito-shop/internal/service/shop/storage"
 	"avito-shop/internal/service/shop/storage/mysql"
+	"avito-shop/internal/service/shop/storage/postgres"
+	"avito-shop/internal/service/shop/storage/sqlite"
+	"avito-shop/internal/tracing"
 
 	"github.com/spf13/cobra"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 )
 
 const (
@@ -23,24 +44,105 @@ const (
 	envDev   = "dev"
 )
 
+const defaultShutdownTimeout = 15 * time.Second
+
 func setupLogger(env string) *slog.Logger {
 	var logger *slog.Logger
 	switch env {
 	case envLocal:
-		logger = slog.New(
-			slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		logger = slog.New(tracing.NewLogHandler(
+			slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})))
 	case envDev:
-		logger = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		logger = slog.New(tracing.NewLogHandler(
+			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug})))
 	}
 	return logger
 }
 
-func protectedRoutes(r chi.Router, jwtSecret string, handlers *urls.Handlers) {
-	r.Use(mwJWT.JWTMiddleware(jwtSecret))
-	r.Get("/api/info", handlers.Info())
-	r.Post("/api/sendCoin", handlers.SendCoin())
-	r.Get("/api/buy/{item}", handlers.BuyItem())
+// database — хранилище вместе с операциями обслуживания, которые нужны serve.
+type database interface {
+	storage.IStorage
+	Ping(ctx context.Context) error
+	CurrentSchemaVersion(ctx context.Context) (int, error)
+	GetDB() *sql.DB
+	Close() error
+}
+
+// openDatabase подключается к БД, выбранной в db.driver, и возвращает ожидаемую версию схемы.
+func openDatabase(cfg config.DB) (database, int, error) {
+	switch cfg.Driver {
+	case config.DriverMySQL:
+		db, err := mysql.New(cfg)
+		if err != nil {
+			return nil, 0, err
+		}
+		return db, mysql.SchemaVersion, nil
+	case config.DriverPostgres:
+		db, err := postgres.New(cfg)
+		if err != nil {
+			return nil, 0, err
+		}
+		return db, postgres.SchemaVersion, nil
+	case config.DriverSQLite:
+		db, err := sqlite.New(cfg)
+		if err != nil {
+			return nil, 0, err
+		}
+		return db, sqlite.SchemaVersion, nil
+	default:
+		return nil, 0, fmt.Errorf("unknown database driver %q", cfg.Driver)
+	}
+}
+
+// apiServer собирает реализации всех операций api.ServerInterface.
+type apiServer struct {
+	*urls.Handlers
+	health *health.Handlers
+}
+
+func (s apiServer) Liveness(w http.ResponseWriter, r *http.Request) {
+	s.health.Liveness(w, r)
+}
+
+func (s apiServer) Readiness(w http.ResponseWriter, r *http.Request) {
+	s.health.Readiness(w, r)
+}
+
+func (apiServer) Metrics(w http.ResponseWriter, r *http.Request) {
+	metrics.Handler().ServeHTTP(w, r)
+}
+
+// newRouter регистрирует маршруты из api/schema.yaml; маршруты вне спецификации добавлять не нужно.
+func newRouter(log *slog.Logger, httpCfg config.HTTPServer, jwtSecret string, handlers *urls.Handlers, healthHandlers *health.Handlers) (chi.Router, error) {
+	swagger, err := api.GetSwagger()
+	if err != nil {
+		return nil, err
+	}
+	validator, err := mwOpenAPI.NewValidator(swagger, log)
+	if err != nil {
+		return nil, err
+	}
+
+	r := chi.NewRouter()
+	r.Use(mwMetrics.New())
+	r.Use(middleware.Recoverer)
+	r.Use(middleware.RequestID)
+	r.Use(mwRequestInfo.New())
+	r.Use(mwTracing.New())
+	r.Use(mwLogger.New(log))
+	r.Use(middleware.Logger)
+	r.Use(middleware.URLFormat)
+	if httpCfg.ValidateResponses {
+		r.Use(validator.Responses())
+	}
+	r.Use(validator.Requests())
+
+	api.HandlerWithOptions(apiServer{Handlers: handlers, health: healthHandlers}, api.ChiServerOptions{
+		BaseRouter:  r,
+		Middlewares: []api.MiddlewareFunc{mwJWT.SecuredOnly(mwJWT.JWTMiddleware(jwtSecret))},
+	})
+
+	return r, nil
 }
 
 var serveCmd = &cobra.Command{
@@ -56,32 +158,178 @@ var serveCmd = &cobra.Command{
 		log := setupLogger(cfg.Env)
 		log.Info("Start service", slog.String("env", cfg.Env))
 		log.Debug("Debug messages are enabled")
-		db, err := mysql.New(cfg.DB)
+
+		shutdownTracing, err := tracing.Setup(cmd.Context(), cfg.Tracing)
+		if err != nil {
+			log.Error("Failed to setup tracing", "error", err)
+			return err
+		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := shutdownTracing(ctx); err != nil {
+				log.Error("Failed to flush traces", "error", err)
+			}
+		}()
+
+		db, schemaVersion, err := openDatabase(cfg.DB)
 		if err != nil {
 			log.Error("Failed to connect to database", "error", err)
 			return err
 		}
 
-		r := chi.NewRouter()
-		r.Use(middleware.Recoverer)
-		r.Use(mwLogger.New(log))
-		r.Use(middleware.Logger)
-		r.Use(middleware.URLFormat)
+		if err = metrics.RegisterCollector(collectors.NewDBStatsCollector(db.GetDB(), cfg.DB.Database)); err != nil {
+			log.Error("Failed to register database metrics", "error", err)
+			db.Close()
+			return err
+		}
 
-		jwtSecret := cfg.AuthKey
-		service := shop.NewService(db)
-		handlers := urls.NewHandlers(db, service, log)
-		r.Post("/api/auth", handlers.Auth(jwtSecret))
+		store := storage.NewTracedStorage(db, cfg.DB.Driver)
+		shopService := shop.NewService(store)
+		if cfg.PaymentRequests.TTL > 0 {
+			shopService.PaymentRequestTTL = cfg.PaymentRequests.TTL
+		}
+		shopService.Limits = cfg.TransferLimits
+		shopService.Approvals = cfg.TransferApprovals
+		shopService.Events = shop.NewLogEventSink(log)
+		if cfg.TransferFees.Enabled() {
+			if err = shopService.EnableFees(context.Background(), cfg.TransferFees); err != nil {
+				log.Error("Failed to enable transfer fees", "error", err)
+				db.Close()
+				return err
+			}
+		}
+		if cfg.CoinExpiry.Enabled {
+			if err = shopService.EnableCoinExpiry(context.Background(), cfg.CoinExpiry); err != nil {
+				log.Error("Failed to enable coin expiry", "error", err)
+				db.Close()
+				return err
+			}
+		}
+		if len(cfg.Achievements.Rules) > 0 {
+			if err = shopService.EnableAchievements(cfg.Achievements.Rules); err != nil {
+				log.Error("Failed to enable achievements", "error", err)
+				db.Close()
+				return err
+			}
+		}
+		shopService.Leaderboards = cfg.Leaderboards
+		if cfg.Leaderboards.CacheTTL > 0 {
+			// По записи на каждую пару таблицы и периода, включая период за всё время.
+			periods := len(cfg.Leaderboards.Windows)
+			if periods == 0 {
+				periods = len(shop.DefaultLeaderboardWindows)
+			}
+			shopService.LeaderboardCache = cache.NewLRU[*storage.Leaderboard](len(storage.Boards)*(periods+1), cfg.Leaderboards.CacheTTL)
+		}
+		var service shop.IService = shopService
+		if cfg.InfoCache.Enabled {
+			service = shop.NewCachedService(service, cache.NewLRU[*storage.InfoResponse](cfg.InfoCache.Size, cfg.InfoCache.TTL))
+		}
+		handlers := urls.NewHandlers(store, service, log, cfg.AuthKey)
 
-		r.Group(func(r chi.Router) {
-			protectedRoutes(r, jwtSecret, handlers)
+		healthHandlers := health.New(log)
+		healthHandlers.AddCheck("database", db.Ping)
+		healthHandlers.AddCheck("migrations", func(ctx context.Context) error {
+			version, err := db.CurrentSchemaVersion(ctx)
+			if err != nil {
+				return err
+			}
+			if version != schemaVersion {
+				return fmt.Errorf("schema version %d, expected %d", version, schemaVersion)
+			}
+			return nil
 		})
 
-		log.Info("Starting server", "address", cfg.Address)
-		if err = http.ListenAndServe("localhost:8080", r); err != nil {
+		r, err := newRouter(log, cfg.HTTPServer, cfg.AuthKey, handlers, healthHandlers)
+		if err != nil {
+			log.Error("Failed to build router", "error", err)
+			db.Close()
+			return err
+		}
+
+		srv := &http.Server{
+			Addr:              cfg.HTTPServer.ListenAddr(),
+			Handler:           r,
+			ReadTimeout:       cfg.HTTPServer.ReadTimeout,
+			ReadHeaderTimeout: cfg.HTTPServer.ReadHeaderTimeout,
+			WriteTimeout:      cfg.HTTPServer.WriteTimeout,
+			IdleTimeout:       cfg.HTTPServer.IdleTimeout,
+			MaxHeaderBytes:    cfg.HTTPServer.MaxHeaderBytes,
+			ErrorLog:          slog.NewLogLogger(log.Handler(), slog.LevelError),
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		// Фоновые задачи останавливаются вместе с приёмом запросов и завершаются до закрытия БД.
+		jobsCtx, stopJobs := context.WithCancel(ctx)
+		defer stopJobs()
+		var jobs sync.WaitGroup
+		if cfg.Scheduler.Enabled {
+			jobs.Add(1)
+			go func() {
+				defer jobs.Done()
+				shop.NewScheduler(service, cfg.Scheduler.Interval, log).Run(jobsCtx)
+			}()
+		}
+		if cfg.CoinExpiry.Enabled {
+			jobs.Add(1)
+			go func() {
+				defer jobs.Done()
+				shop.NewCoinExpirer(service, cfg.CoinExpiry.Interval, log).Run(jobsCtx)
+			}()
+		}
+		if cfg.TransferApprovals.Enabled() {
+			jobs.Add(1)
+			go func() {
+				defer jobs.Done()
+				shop.NewPendingTransferExpirer(service, cfg.TransferApprovals.Interval, log).Run(jobsCtx)
+			}()
+		}
+
+		serverErr := make(chan error, 1)
+		go func() {
+			log.Info("Starting server", "address", srv.Addr)
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErr <- err
+			}
+			close(serverErr)
+		}()
+
+		select {
+		case err = <-serverErr:
 			log.Error("Failed to start server", "error", err)
-			os.Exit(1)
+			stopJobs()
+			jobs.Wait()
+			db.Close()
+			return err
+		case <-ctx.Done():
+			log.Info("Shutdown signal received, draining connections")
+		}
+		stop()
+
+		healthHandlers.SetReady(false)
+		if drain := cfg.HTTPServer.ReadinessDrain; drain > 0 {
+			time.Sleep(drain)
+		}
+
+		shutdownTimeout := cfg.HTTPServer.ShutdownTimeout
+		if shutdownTimeout <= 0 {
+			shutdownTimeout = defaultShutdownTimeout
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err = srv.Shutdown(shutdownCtx); err != nil {
+			log.Error("Failed to shutdown server gracefully", "error", err)
+		}
+		stopJobs()
+		jobs.Wait()
+		if closeErr := db.Close(); closeErr != nil {
+			log.Error("Failed to close database", "error", closeErr)
 		}
+		log.Info("Server stopped")
 
 		return nil
 	},
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"avito-shop/internal/config"
+	"avito-shop/internal/http-server/api"
+	"avito-shop/internal/http-server/handlers/health"
+	urls "avito-shop/internal/http-server/handlers/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRoutesMatchSpec падает, если маршрут сервера отсутствует в api/schema.yaml или наоборот.
+func TestRoutesMatchSpec(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	r, err := newRouter(log, config.HTTPServer{ValidateResponses: true}, "secret",
+		urls.NewHandlers(nil, nil, log, "secret"), health.New(log))
+	require.NoError(t, err)
+
+	var routes []string
+	err = chi.Walk(r, func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		routes = append(routes, method+" "+route)
+		return nil
+	})
+	require.NoError(t, err)
+
+	swagger, err := api.GetSwagger()
+	require.NoError(t, err)
+
+	var specRoutes []string
+	for path, item := range swagger.Paths.Map() {
+		for method := range item.Operations() {
+			specRoutes = append(specRoutes, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	sort.Strings(routes)
+	sort.Strings(specRoutes)
+	require.Equal(t, specRoutes, routes)
+}
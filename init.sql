@@ -28,6 +28,11 @@ CREATE TABLE IF NOT EXISTS inventory (
                                          FOREIGN KEY (user_id) REFERENCES users(id),
                                          UNIQUE unique_user_item (user_id, item_name)
 );
+CREATE TABLE IF NOT EXISTS schema_migrations (
+                                                 version INT PRIMARY KEY,
+                                                 applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+INSERT IGNORE INTO schema_migrations (version) VALUES (1);
 
 USE test_db;
 CREATE TABLE IF NOT EXISTS users (
@@ -49,4 +54,9 @@ CREATE TABLE IF NOT EXISTS inventory (
                                          quantity INT DEFAULT 0,
                                          UNIQUE unique_user_item (user_id, item_name)
 );
+CREATE TABLE IF NOT EXISTS schema_migrations (
+                                                 version INT PRIMARY KEY,
+                                                 applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+INSERT IGNORE INTO schema_migrations (version) VALUES (1);
 USE Avito;
\ No newline at end of file
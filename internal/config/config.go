@@ -13,20 +13,238 @@ type Config struct {
 	AuthKey    string `mapstructure:"authKey"`
 	HTTPServer `mapstructure:"http_server"`
 	DB         `mapstructure:"db"`
+	Tracing    `mapstructure:"tracing"`
+	InfoCache  `mapstructure:"info_cache"`
+
+	PaymentRequests   `mapstructure:"payment_requests"`
+	Scheduler         `mapstructure:"scheduler"`
+	TransferLimits    `mapstructure:"transfer_limits"`
+	TransferFees      `mapstructure:"transfer_fees"`
+	TransferApprovals `mapstructure:"transfer_approvals"`
+	CoinExpiry        `mapstructure:"coin_expiry"`
+	Leaderboards      `mapstructure:"leaderboards"`
+	Achievements      `mapstructure:"achievements"`
 }
 
 type HTTPServer struct {
-	Address      string        `mapstructure:"address"`
-	WriteTimeout time.Duration `mapstructure:"write_timeout"`
-	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
+	Address           string        `mapstructure:"address"`
+	ReadTimeout       time.Duration `mapstructure:"read_timeout"`
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"`
+	WriteTimeout      time.Duration `mapstructure:"write_timeout"`
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`
+	MaxHeaderBytes    int           `mapstructure:"max_header_bytes"`
+	ShutdownTimeout   time.Duration `mapstructure:"shutdown_timeout"`
+	ReadinessDrain    time.Duration `mapstructure:"readiness_drain"`
+	ValidateResponses bool          `mapstructure:"validate_responses"`
 }
 type DB struct {
+	Driver       string `mapstructure:"driver"`
 	Host         string `mapstructure:"host"`
 	Port         string `mapstructure:"port"`
 	Username     string `mapstructure:"username"`
 	Password     string `mapstructure:"password"`
 	Database     string `mapstructure:"name"`
 	DatabaseTest string `mapstructure:"test_db_name"`
+	SSLMode      string `mapstructure:"sslmode"`
+	Path         string `mapstructure:"path"`
+}
+
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+type Tracing struct {
+	Exporter    string  `mapstructure:"exporter"`
+	Endpoint    string  `mapstructure:"endpoint"`
+	Insecure    bool    `mapstructure:"insecure"`
+	ServiceName string  `mapstructure:"service_name"`
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
+// PaymentRequests настраивает запросы на перевод; при TTL = 0 используется срок по умолчанию.
+type PaymentRequests struct {
+	TTL time.Duration `mapstructure:"ttl"`
+}
+
+// Scheduler настраивает фоновое выполнение отложенных переводов. Его можно включать
+// на всех репликах: один запуск перевода выполняет только одна из них.
+type Scheduler struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// TransferLimits задаёт лимиты переводов: Default действует для всех, Roles переопределяет
+// отдельные значения для ролей пользователей. Нулевой лимит не ограничивает.
+type TransferLimits struct {
+	Default LimitPolicy              `mapstructure:"default"`
+	Roles   map[string]LimitOverride `mapstructure:"roles"`
+}
+
+// LimitPolicy — лимиты одного пользователя в монетах.
+type LimitPolicy struct {
+	PerTransfer   int `mapstructure:"per_transfer"`
+	DailySent     int `mapstructure:"daily_sent"`
+	DailyReceived int `mapstructure:"daily_received"`
+}
+
+// LimitOverride — значения роли, отличающиеся от Default; не заданные поля наследуются.
+type LimitOverride struct {
+	PerTransfer   *int `mapstructure:"per_transfer"`
+	DailySent     *int `mapstructure:"daily_sent"`
+	DailyReceived *int `mapstructure:"daily_received"`
+}
+
+// For возвращает лимиты для роли с учётом её переопределений.
+func (l TransferLimits) For(role string) LimitPolicy {
+	p := l.Default
+	o, ok := l.Roles[role]
+	if !ok {
+		return p
+	}
+	if o.PerTransfer != nil {
+		p.PerTransfer = *o.PerTransfer
+	}
+	if o.DailySent != nil {
+		p.DailySent = *o.DailySent
+	}
+	if o.DailyReceived != nil {
+		p.DailyReceived = *o.DailyReceived
+	}
+	return p
+}
+
+// Enabled сообщает, ограничен ли хоть кто-нибудь.
+func (l TransferLimits) Enabled() bool {
+	if l.Default != (LimitPolicy{}) {
+		return true
+	}
+	for role := range l.Roles {
+		if l.For(role) != (LimitPolicy{}) {
+			return true
+		}
+	}
+	return false
+}
+
+// TransferFees задаёт комиссию за перевод, которую отправитель платит сверх суммы перевода.
+// Комиссия равна Flat + Percent% от суммы (доля округляется вверх); для сумм от Tiers[i].From
+// действуют значения ступени с наибольшим подходящим From. Отправители с ролями из ExemptRoles
+// комиссию не платят. Комиссии зачисляются на служебный счёт Account.
+type TransferFees struct {
+	Account     string `mapstructure:"account"`
+	FeePolicy   `mapstructure:",squash"`
+	Tiers       []FeeTier `mapstructure:"tiers"`
+	ExemptRoles []string  `mapstructure:"exempt_roles"`
+}
+
+// FeePolicy — комиссия в монетах (Flat) и в процентах от суммы (Percent, допускаются дробные).
+type FeePolicy struct {
+	Flat    int     `mapstructure:"flat"`
+	Percent float64 `mapstructure:"percent"`
+}
+
+// FeeTier — комиссия для переводов от From монет.
+type FeeTier struct {
+	From      int `mapstructure:"from"`
+	FeePolicy `mapstructure:",squash"`
+}
+
+// Enabled сообщает, взимается ли комиссия хоть с какого-то перевода.
+func (f TransferFees) Enabled() bool {
+	if f.FeePolicy != (FeePolicy{}) {
+		return true
+	}
+	for _, t := range f.Tiers {
+		if t.FeePolicy != (FeePolicy{}) {
+			return true
+		}
+	}
+	return false
+}
+
+// For возвращает комиссию, действующую для перевода amount.
+func (f TransferFees) For(amount int) FeePolicy {
+	p, from := f.FeePolicy, 0
+	for _, t := range f.Tiers {
+		if t.From <= amount && t.From >= from {
+			p, from = t.FeePolicy, t.From
+		}
+	}
+	return p
+}
+
+// TransferApprovals настраивает одобрение крупных переводов: перевод больше Threshold монет
+// не выполняется сразу, а удерживается до решения пользователя с ролью из ApproverRoles.
+// Неодобренный за TTL перевод истекает, и удержание возвращается отправителю; истёкшие
+// переводы закрываются каждые Interval. Threshold = 0 выключает одобрение.
+type TransferApprovals struct {
+	Threshold     int           `mapstructure:"threshold"`
+	TTL           time.Duration `mapstructure:"ttl"`
+	ApproverRoles []string      `mapstructure:"approver_roles"`
+	Interval      time.Duration `mapstructure:"interval"`
+}
+
+// Enabled сообщает, требуют ли какие-то переводы одобрения.
+func (a TransferApprovals) Enabled() bool {
+	return a.Threshold > 0
+}
+
+// CoinExpiry настраивает сгорание монет: каждая партия сгорает через TTL после начисления.
+// Сгоревшие монеты переводятся на служебный счёт Account. Фоновое сжигание выполняется каждые
+// Interval и безопасно на всех репликах; /api/info показывает сгорания в ближайшие Notice (0 — все).
+type CoinExpiry struct {
+	Enabled  bool          `mapstructure:"enabled"`
+	TTL      time.Duration `mapstructure:"ttl"`
+	Notice   time.Duration `mapstructure:"notice"`
+	Interval time.Duration `mapstructure:"interval"`
+	Account  string        `mapstructure:"account"`
+}
+
+// Leaderboards настраивает таблицы лидеров. Windows — периоды по имени в дополнение к "all"
+// (за всё время); пустой список заменяется неделей и месяцем. Size — число мест, если запрос
+// его не задал. Посчитанные таблицы хранятся в памяти CacheTTL; 0 — без кэша.
+type Leaderboards struct {
+	Windows  map[string]time.Duration `mapstructure:"windows"`
+	Size     int                      `mapstructure:"size"`
+	CacheTTL time.Duration            `mapstructure:"cache_ttl"`
+}
+
+// Achievements задаёт правила выдачи достижений; без правил достижения выключены.
+type Achievements struct {
+	Rules []AchievementRule `mapstructure:"rules"`
+}
+
+// AchievementRule выдаёт достижение Code, когда показатель Metric пользователя достигает Threshold:
+// items — число купленных предметов (только предметов Item, если он задан), sent и received —
+// сколько монет пользователь отправил и получил переводами.
+type AchievementRule struct {
+	Code        string `mapstructure:"code"`
+	Title       string `mapstructure:"title"`
+	Description string `mapstructure:"description"`
+	Metric      string `mapstructure:"metric"`
+	Item        string `mapstructure:"item"`
+	Threshold   int    `mapstructure:"threshold"`
+}
+
+// InfoCache настраивает кэш ответов /api/info. TTL ограничивает устаревание,
+// когда запись меняет другая реплика сервиса; 0 — без ограничения по времени.
+type InfoCache struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Size    int           `mapstructure:"size"`
+	TTL     time.Duration `mapstructure:"ttl"`
+}
+
+// ListenAddr возвращает адрес в формате host:port, допуская в конфиге только порт ("8080").
+func (h HTTPServer) ListenAddr() string {
+	if h.Address == "" {
+		return ":8080"
+	}
+	if !strings.Contains(h.Address, ":") {
+		return ":" + h.Address
+	}
+	return h.Address
 }
 
 func MustLoad(cfgName string) (*Config, error) {
@@ -42,6 +260,15 @@ func MustLoad(cfgName string) (*Config, error) {
 	viper.SetEnvPrefix("SERVICE")
 	viper.AutomaticEnv()
 	viper.SetConfigType("yaml")
+	viper.SetDefault("db.driver", DriverMySQL)
+	viper.SetDefault("scheduler.interval", 30*time.Second)
+	viper.SetDefault("transfer_fees.account", "system")
+	viper.SetDefault("transfer_approvals.ttl", 72*time.Hour)
+	viper.SetDefault("transfer_approvals.approver_roles", []string{"admin"})
+	viper.SetDefault("transfer_approvals.interval", time.Minute)
+	viper.SetDefault("coin_expiry.ttl", 365*24*time.Hour)
+	viper.SetDefault("coin_expiry.interval", time.Hour)
+	viper.SetDefault("coin_expiry.account", "system")
 
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, errors.WithStack(err)
@@ -0,0 +1,6 @@
+// Package api содержит модели и серверный интерфейс, сгенерированные из api/schema.yaml.
+// После изменения спецификации выполните `go generate ./internal/http-server/api`; версия генератора
+// закреплена в директиве, чтобы api.gen.go совпадал на любой машине.
+package api
+
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.4.1 -config ../../../api/oapi-codegen.yaml -o api.gen.go ../../../api/schema.yaml
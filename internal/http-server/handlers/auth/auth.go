@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -10,6 +11,9 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// ErrInvalidPassword — пароль не подходит к существующему пользователю.
+var ErrInvalidPassword = errors.New("invalid password")
+
 type Handlers struct {
 	storage storage.IStorage
 }
@@ -25,27 +29,34 @@ func HashPassword(password string) (string, error) {
 func CheckPassword(hashedPassword, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
-func AuthenticateUser(s storage.IStorage, username, password, authKey string) (string, error) {
-	storedPasswordHash, err := s.CheckAuth(username)
+
+// AuthenticateUser проверяет пароль и выдаёт токен; неизвестный пользователь создаётся,
+// и тогда created равно true. Неверный пароль — ErrInvalidPassword, недопустимое имя нового
+// пользователя — *shop.ValidationError.
+func AuthenticateUser(ctx context.Context, s storage.IStorage, username, password, authKey string) (token string, created bool, err error) {
+	storedPasswordHash, err := s.CheckAuth(ctx, username)
 
 	if errors.Is(err, storage.ErrUserNotFound) {
+		if err = shop.ValidateUsername(username); err != nil {
+			return "", false, err
+		}
 		passwordHash, hashErr := HashPassword(password)
 		if hashErr != nil {
-			return "", fmt.Errorf("failed to hash password: %w", hashErr)
+			return "", false, fmt.Errorf("failed to hash password: %w", hashErr)
 		}
-		if addErr := s.AddNewUser(username, passwordHash); addErr != nil {
-			return "", fmt.Errorf("failed to add new user: %w", addErr)
+		if addErr := s.AddNewUser(ctx, username, passwordHash); addErr != nil {
+			return "", false, fmt.Errorf("failed to add new user: %w", addErr)
 		}
-		fmt.Println("User created:", username)
+		created = true
 	} else if err != nil {
-		return "", fmt.Errorf("failed to check authentication: %w", err)
+		return "", false, fmt.Errorf("failed to check authentication: %w", err)
 	} else if checkErr := CheckPassword(storedPasswordHash, password); checkErr != nil {
-		return "", fmt.Errorf("invalid password: %w", checkErr)
+		return "", false, fmt.Errorf("%w: %w", ErrInvalidPassword, checkErr)
 	}
 
-	token, err := shop.GenerateJWT(authKey, username)
+	token, err = shop.GenerateJWT(authKey, username)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate JWT: %w", err)
+		return "", false, fmt.Errorf("failed to generate JWT: %w", err)
 	}
-	return token, nil
+	return token, created, nil
 }
@@ -0,0 +1,57 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"avito-shop/internal/http-server/handlers/auth"
+	"avito-shop/internal/service/shop"
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticateUser_Registration(t *testing.T) {
+	tests := []struct {
+		name        string
+		username    string
+		wantCreated bool
+		wantErr     error
+	}{
+		{name: "Valid username", username: "alice", wantCreated: true},
+		{name: "Username the recipient check rejects", username: "bad name!", wantErr: shop.ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := &storage.IStorageMock{
+				CheckAuthFunc: func(ctx context.Context, username string) (string, error) {
+					return "", storage.ErrUserNotFound
+				},
+				AddNewUserFunc: func(ctx context.Context, username, password string) error { return nil },
+			}
+
+			token, created, err := auth.AuthenticateUser(context.Background(), mockStorage, tt.username, "secret", "key")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, mockStorage.AddNewUserCalls(), "the user is not created")
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, token)
+			assert.Equal(t, tt.wantCreated, created)
+		})
+	}
+}
+
+func TestAuthenticateUser_InvalidPassword(t *testing.T) {
+	hash, err := auth.HashPassword("secret")
+	require.NoError(t, err)
+	mockStorage := &storage.IStorageMock{
+		CheckAuthFunc: func(ctx context.Context, username string) (string, error) { return hash, nil },
+	}
+
+	_, _, err = auth.AuthenticateUser(context.Background(), mockStorage, "alice", "wrong", "key")
+	assert.ErrorIs(t, err, auth.ErrInvalidPassword)
+}
@@ -0,0 +1,121 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// checkTimeout ограничивает время выполнения всех проверок готовности.
+const checkTimeout = 2 * time.Second
+
+// Check проверяет одну зависимость сервиса (БД, миграции и т.д.).
+type Check func(ctx context.Context) error
+
+type CheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Response struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+type Handlers struct {
+	ready  atomic.Bool
+	mu     sync.RWMutex
+	checks []namedCheck
+	log    *slog.Logger
+}
+
+// New создаёт обработчики проверок. Сервис считается готовым сразу после создания.
+func New(log *slog.Logger) *Handlers {
+	h := &Handlers{log: log}
+	h.ready.Store(true)
+	return h
+}
+
+// AddCheck регистрирует проверку, выполняемую при каждом запросе /readyz.
+func (h *Handlers) AddCheck(name string, check Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks = append(h.checks, namedCheck{name: name, check: check})
+}
+
+// SetReady переключает готовность сервиса, например перед graceful shutdown.
+func (h *Handlers) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// Liveness отвечает 200, пока процесс жив и обрабатывает запросы.
+func (h *Handlers) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, http.StatusOK, Response{Status: StatusOK})
+}
+
+// Readiness выполняет все зарегистрированные проверки и отвечает 503, если хотя бы одна не прошла.
+func (h *Handlers) Readiness(w http.ResponseWriter, r *http.Request) {
+	if !h.ready.Load() {
+		writeResponse(w, http.StatusServiceUnavailable, Response{
+			Status: StatusError,
+			Checks: map[string]CheckResult{"shutdown": {Status: StatusError, Error: "service is shutting down"}},
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+
+	h.mu.RLock()
+	checks := make([]namedCheck, len(h.checks))
+	copy(checks, h.checks)
+	h.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	wg.Add(len(checks))
+	for i, c := range checks {
+		go func(i int, c namedCheck) {
+			defer wg.Done()
+			results[i] = CheckResult{Status: StatusOK}
+			if err := c.check(ctx); err != nil {
+				results[i] = CheckResult{Status: StatusError, Error: err.Error()}
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	resp := Response{Status: StatusOK, Checks: make(map[string]CheckResult, len(checks))}
+	status := http.StatusOK
+	for i, c := range checks {
+		resp.Checks[c.name] = results[i]
+		if results[i].Status != StatusOK {
+			resp.Status = StatusError
+			status = http.StatusServiceUnavailable
+			h.log.WarnContext(r.Context(), "Readiness check failed", slog.String("check", c.name), slog.String("error", results[i].Error))
+		}
+	}
+
+	writeResponse(w, status, resp)
+}
+
+func writeResponse(w http.ResponseWriter, status int, resp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
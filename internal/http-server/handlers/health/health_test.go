@@ -0,0 +1,103 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"avito-shop/internal/http-server/handlers/health"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newHandlers() *health.Handlers {
+	return health.New(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func doRequest(t *testing.T, h http.HandlerFunc) (int, health.Response) {
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var resp health.Response
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	return rr.Code, resp
+}
+
+func TestLiveness(t *testing.T) {
+	h := newHandlers()
+	h.AddCheck("database", func(ctx context.Context) error { return errors.New("down") })
+
+	code, resp := doRequest(t, h.Liveness)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, health.StatusOK, resp.Status)
+}
+
+func TestReadiness_TableDriven(t *testing.T) {
+	tests := []struct {
+		name         string
+		checks       map[string]health.Check
+		notReady     bool
+		expectedCode int
+		expectedBody health.Response
+	}{
+		{
+			name: "All checks pass",
+			checks: map[string]health.Check{
+				"database": func(ctx context.Context) error { return nil },
+			},
+			expectedCode: http.StatusOK,
+			expectedBody: health.Response{
+				Status: health.StatusOK,
+				Checks: map[string]health.CheckResult{"database": {Status: health.StatusOK}},
+			},
+		},
+		{
+			name: "Failed check",
+			checks: map[string]health.Check{
+				"database":   func(ctx context.Context) error { return nil },
+				"migrations": func(ctx context.Context) error { return errors.New("schema version 0, expected 1") },
+			},
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: health.Response{
+				Status: health.StatusError,
+				Checks: map[string]health.CheckResult{
+					"database":   {Status: health.StatusOK},
+					"migrations": {Status: health.StatusError, Error: "schema version 0, expected 1"},
+				},
+			},
+		},
+		{
+			name: "Shutting down",
+			checks: map[string]health.Check{
+				"database": func(ctx context.Context) error { return nil },
+			},
+			notReady:     true,
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: health.Response{
+				Status: health.StatusError,
+				Checks: map[string]health.CheckResult{"shutdown": {Status: health.StatusError, Error: "service is shutting down"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newHandlers()
+			for name, check := range tt.checks {
+				h.AddCheck(name, check)
+			}
+			if tt.notReady {
+				h.SetReady(false)
+			}
+
+			code, resp := doRequest(t, h.Readiness)
+			require.Equal(t, tt.expectedCode, code)
+			require.Equal(t, tt.expectedBody, resp)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package urls
+
+import (
+	"avito-shop/internal/http-server/api"
+	"avito-shop/internal/service/shop"
+	"avito-shop/internal/service/shop/storage"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+func (h *Handlers) ListAuditLog(w http.ResponseWriter, r *http.Request, params api.ListAuditLogParams) {
+	username := r.Context().Value("username").(string)
+
+	filter := auditFilter(params.Actor, params.Action, params.Target, params.From, params.To, params.AfterId)
+	if params.Limit != nil {
+		filter.Limit = *params.Limit
+	}
+	entries, err := h.service.AuditLog(r.Context(), username, filter)
+	if err != nil {
+		h.writeAuditError(r, w, err)
+		return
+	}
+
+	h.writeJSON(r, w, http.StatusOK, entries)
+}
+
+// ExportAuditLog отдаёт журнал построчным JSON по мере чтения из хранилища. Статус ответа
+// отправляется с первой партией, поэтому ошибка посреди выгрузки только обрывает ответ.
+func (h *Handlers) ExportAuditLog(w http.ResponseWriter, r *http.Request, params api.ExportAuditLogParams) {
+	username := r.Context().Value("username").(string)
+
+	filter := auditFilter(params.Actor, params.Action, params.Target, params.From, params.To, params.AfterId)
+	started := false
+	start := func() {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", `attachment; filename="audit.ndjson"`)
+		w.WriteHeader(http.StatusOK)
+		started = true
+	}
+	enc := json.NewEncoder(w)
+	rc := http.NewResponseController(w)
+
+	exported := 0
+	err := h.service.ExportAuditLog(r.Context(), username, filter, func(entries []storage.AuditEntry) error {
+		if !started {
+			start()
+		}
+		for i := range entries {
+			if err := enc.Encode(&entries[i]); err != nil {
+				return err
+			}
+		}
+		exported += len(entries)
+		// Не все обёртки ResponseWriter умеют Flush; тогда ответ уйдёт целиком в конце.
+		_ = rc.Flush()
+		return nil
+	})
+	switch {
+	case err != nil && !started:
+		h.writeAuditError(r, w, err)
+		return
+	case err != nil:
+		h.log.ErrorContext(r.Context(), "Audit log export interrupted", slog.Int("exported", exported), slog.String("error", err.Error()))
+		return
+	case !started:
+		start()
+	}
+
+	h.log.InfoContext(r.Context(), "Audit log exported", slog.Int("entries", exported), slog.String("admin", username))
+}
+
+func (h *Handlers) VerifyAuditLog(w http.ResponseWriter, r *http.Request) {
+	username := r.Context().Value("username").(string)
+
+	res, err := h.service.VerifyAuditLog(r.Context(), username)
+	if err != nil {
+		h.writeAuditError(r, w, err)
+		return
+	}
+	if !res.Valid {
+		h.log.ErrorContext(r.Context(), "Audit log chain is broken", slog.Int("broken_at", res.BrokenAt))
+	}
+
+	h.writeJSON(r, w, http.StatusOK, res)
+}
+
+func auditFilter(actor, action, target *string, from, to *time.Time, afterID *int) storage.AuditFilter {
+	var filter storage.AuditFilter
+	if actor != nil {
+		filter.Actor = *actor
+	}
+	if action != nil {
+		filter.Action = *action
+	}
+	if target != nil {
+		filter.Target = *target
+	}
+	filter.From, filter.To = from, to
+	if afterID != nil {
+		filter.AfterID = *afterID
+	}
+	return filter
+}
+
+// writeAuditError переводит ошибки журнала аудита в статусы ответа.
+func (h *Handlers) writeAuditError(r *http.Request, w http.ResponseWriter, err error) {
+	var ve *shop.ValidationError
+	switch {
+	case errors.As(err, &ve):
+		h.log.WarnContext(r.Context(), "Invalid audit log filter", slog.String("error", ve.Error()))
+		h.writeValidationError(w, ve)
+	case errors.Is(err, shop.ErrForbidden):
+		h.log.WarnContext(r.Context(), "Audit log access forbidden")
+		h.writeErrorResponse(w, "Недостаточно прав.", http.StatusForbidden)
+	default:
+		h.log.ErrorContext(r.Context(), "Failed to read audit log", slog.String("error", err.Error()))
+		h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
+	}
+}
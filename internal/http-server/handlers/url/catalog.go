@@ -0,0 +1,109 @@
+package urls
+
+import (
+	"avito-shop/internal/http-server/api"
+	"avito-shop/internal/service/shop"
+	"avito-shop/internal/service/shop/storage"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+func (h *Handlers) ListItems(w http.ResponseWriter, r *http.Request) {
+	items, err := h.service.Catalog(r.Context())
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "Failed to load catalog", slog.String("error", err.Error()))
+		h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
+		return
+	}
+
+	h.writeJSON(r, w, http.StatusOK, items)
+}
+
+func (h *Handlers) ListOrders(w http.ResponseWriter, r *http.Request) {
+	username := r.Context().Value("username").(string)
+
+	orders, err := h.service.ListOrders(r.Context(), username)
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "Failed to list orders", slog.String("error", err.Error()))
+		h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
+		return
+	}
+	if orders == nil {
+		orders = []storage.Order{}
+	}
+
+	h.writeJSON(r, w, http.StatusOK, orders)
+}
+
+func (h *Handlers) ListItemPrices(w http.ResponseWriter, r *http.Request) {
+	username := r.Context().Value("username").(string)
+
+	prices, err := h.service.ListItemPrices(r.Context(), username)
+	if err != nil {
+		h.writeItemPriceError(r, w, err)
+		return
+	}
+	if prices == nil {
+		prices = []storage.ItemPrice{}
+	}
+
+	h.writeJSON(r, w, http.StatusOK, prices)
+}
+
+func (h *Handlers) CreateItemPrice(w http.ResponseWriter, r *http.Request) {
+	username := r.Context().Value("username").(string)
+
+	var input api.CreateItemPriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.log.WarnContext(r.Context(), "Invalid request body")
+		h.writeErrorResponse(w, "Неверный запрос.", http.StatusBadRequest)
+		return
+	}
+
+	p, err := h.service.ScheduleItemPrice(r.Context(), username, &shop.ItemPriceInput{
+		Item:     input.Item,
+		Price:    input.Price,
+		StartsAt: input.StartsAt,
+		EndsAt:   input.EndsAt,
+	})
+	if err != nil {
+		h.writeItemPriceError(r, w, err)
+		return
+	}
+
+	h.log.InfoContext(r.Context(), "Item price scheduled", slog.Int("id", p.ID), slog.String("item", p.Item), slog.String("admin", username))
+	h.writeJSON(r, w, http.StatusCreated, p)
+}
+
+func (h *Handlers) CancelItemPrice(w http.ResponseWriter, r *http.Request, id int) {
+	username := r.Context().Value("username").(string)
+
+	p, err := h.service.CancelItemPrice(r.Context(), username, id)
+	if err != nil {
+		h.writeItemPriceError(r, w, err)
+		return
+	}
+
+	h.log.InfoContext(r.Context(), "Item price cancelled", slog.Int("id", id), slog.String("item", p.Item), slog.String("admin", username))
+	h.writeJSON(r, w, http.StatusOK, p)
+}
+
+// writeItemPriceError переводит ошибки управления ценами в статусы ответа.
+func (h *Handlers) writeItemPriceError(r *http.Request, w http.ResponseWriter, err error) {
+	var ve *shop.ValidationError
+	switch {
+	case errors.As(err, &ve):
+		h.log.WarnContext(r.Context(), "Invalid item price", slog.String("error", ve.Error()))
+		h.writeValidationError(w, ve)
+	case errors.Is(err, shop.ErrForbidden):
+		h.log.WarnContext(r.Context(), "Item price management forbidden")
+		h.writeErrorResponse(w, "Недостаточно прав.", http.StatusForbidden)
+	case errors.Is(err, shop.ErrItemPriceNotFound):
+		h.writeErrorResponse(w, "Цена не найдена.", http.StatusNotFound)
+	default:
+		h.log.ErrorContext(r.Context(), "Failed to manage item prices", slog.String("error", err.Error()))
+		h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,255 @@
+package urls
+
+import (
+	"avito-shop/internal/http-server/api"
+	"avito-shop/internal/service/shop"
+	"avito-shop/internal/service/shop/storage"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+func (h *Handlers) ListGroups(w http.ResponseWriter, r *http.Request) {
+	username := r.Context().Value("username").(string)
+
+	groups, err := h.service.ListGroups(r.Context(), username)
+	if err != nil {
+		h.writeGroupError(r, w, err)
+		return
+	}
+	if groups == nil {
+		groups = []storage.Group{}
+	}
+
+	h.writeJSON(r, w, http.StatusOK, groups)
+}
+
+func (h *Handlers) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	username := r.Context().Value("username").(string)
+
+	var input api.CreateGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.log.WarnContext(r.Context(), "Invalid request body")
+		h.writeErrorResponse(w, "Неверный запрос.", http.StatusBadRequest)
+		return
+	}
+
+	in := &shop.GroupInput{Name: input.Name}
+	if input.ApprovalsRequired != nil {
+		in.ApprovalsRequired = *input.ApprovalsRequired
+	}
+
+	g, err := h.service.CreateGroup(r.Context(), username, in)
+	if err != nil {
+		h.writeGroupError(r, w, err)
+		return
+	}
+
+	h.log.InfoContext(r.Context(), "Group created", slog.String("group", g.Name), slog.String("owner", username))
+	h.writeJSON(r, w, http.StatusCreated, g)
+}
+
+func (h *Handlers) GetGroup(w http.ResponseWriter, r *http.Request, group string) {
+	username := r.Context().Value("username").(string)
+
+	info, err := h.service.GetGroup(r.Context(), username, group)
+	if err != nil {
+		h.writeGroupError(r, w, err)
+		return
+	}
+
+	h.writeJSON(r, w, http.StatusOK, info)
+}
+
+func (h *Handlers) GroupHistory(w http.ResponseWriter, r *http.Request, group string, params api.GroupHistoryParams) {
+	username := r.Context().Value("username").(string)
+
+	var filter storage.HistoryFilter
+	if params.Category != nil {
+		filter.Category = *params.Category
+	}
+
+	history, err := h.service.GroupHistory(r.Context(), username, group, filter)
+	if err != nil {
+		h.writeGroupError(r, w, err)
+		return
+	}
+
+	h.writeJSON(r, w, http.StatusOK, history)
+}
+
+func (h *Handlers) SetGroupMember(w http.ResponseWriter, r *http.Request, group string) {
+	username := r.Context().Value("username").(string)
+
+	var input api.GroupMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.log.WarnContext(r.Context(), "Invalid request body")
+		h.writeErrorResponse(w, "Неверный запрос.", http.StatusBadRequest)
+		return
+	}
+
+	in := &shop.GroupMemberInput{Username: input.Username}
+	if input.Role != nil {
+		in.Role = *input.Role
+	}
+
+	members, err := h.service.SetGroupMember(r.Context(), username, group, in)
+	if err != nil {
+		h.writeGroupError(r, w, err)
+		return
+	}
+
+	h.log.InfoContext(r.Context(), "Group member set", slog.String("group", group), slog.String("member", in.Username))
+	h.writeJSON(r, w, http.StatusOK, members)
+}
+
+func (h *Handlers) RemoveGroupMember(w http.ResponseWriter, r *http.Request, group string, member string) {
+	username := r.Context().Value("username").(string)
+
+	members, err := h.service.RemoveGroupMember(r.Context(), username, group, member)
+	if err != nil {
+		h.writeGroupError(r, w, err)
+		return
+	}
+
+	h.log.InfoContext(r.Context(), "Group member removed", slog.String("group", group), slog.String("member", member))
+	h.writeJSON(r, w, http.StatusOK, members)
+}
+
+func (h *Handlers) ListGroupPurchases(w http.ResponseWriter, r *http.Request, group string) {
+	username := r.Context().Value("username").(string)
+
+	purchases, err := h.service.ListGroupPurchases(r.Context(), username, group)
+	if err != nil {
+		h.writeGroupError(r, w, err)
+		return
+	}
+	if purchases == nil {
+		purchases = []storage.GroupPurchase{}
+	}
+
+	h.writeJSON(r, w, http.StatusOK, purchases)
+}
+
+func (h *Handlers) ProposeGroupPurchase(w http.ResponseWriter, r *http.Request, group string) {
+	username := r.Context().Value("username").(string)
+
+	var input api.GroupPurchaseRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.log.WarnContext(r.Context(), "Invalid request body")
+		h.writeErrorResponse(w, "Неверный запрос.", http.StatusBadRequest)
+		return
+	}
+
+	p, err := h.service.ProposeGroupPurchase(r.Context(), username, group, input.Item)
+	if err != nil {
+		if errors.Is(err, shop.ErrItemNotFound) {
+			h.log.WarnContext(r.Context(), "Item not found", slog.String("item", input.Item))
+			h.writeErrorResponse(w, fmt.Sprintf("Предмет '%s' не найден.", input.Item), http.StatusBadRequest)
+			return
+		}
+		h.writeGroupError(r, w, err)
+		return
+	}
+
+	h.log.InfoContext(r.Context(), "Group purchase proposed", slog.String("group", group), slog.Int("id", p.ID))
+	h.writeJSON(r, w, http.StatusCreated, p)
+}
+
+func (h *Handlers) ApproveGroupPurchase(w http.ResponseWriter, r *http.Request, group string, id int) {
+	h.resolveGroupPurchase(w, r, group, id, h.service.ApproveGroupPurchase, "Group purchase approved")
+}
+
+func (h *Handlers) CancelGroupPurchase(w http.ResponseWriter, r *http.Request, group string, id int) {
+	h.resolveGroupPurchase(w, r, group, id, h.service.CancelGroupPurchase, "Group purchase cancelled")
+}
+
+// resolveGroupPurchase выполняет действие над покупкой группы и отвечает её итоговым состоянием.
+func (h *Handlers) resolveGroupPurchase(w http.ResponseWriter, r *http.Request, group string, id int,
+	resolve func(ctx context.Context, username, name string, id int) (*storage.GroupPurchase, error), msg string) {
+	username := r.Context().Value("username").(string)
+
+	p, err := resolve(r.Context(), username, group, id)
+	if err != nil {
+		h.writeGroupError(r, w, err)
+		return
+	}
+
+	h.log.InfoContext(r.Context(), msg, slog.String("group", group), slog.Int("id", p.ID), slog.String("status", p.Status))
+	h.writeJSON(r, w, http.StatusOK, p)
+}
+
+func (h *Handlers) SendCoinFromGroup(w http.ResponseWriter, r *http.Request, group string) {
+	username := r.Context().Value("username").(string)
+
+	var input api.SendCoinRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.log.WarnContext(r.Context(), "Invalid request body")
+		h.writeErrorResponse(w, "Неверный запрос.", http.StatusBadRequest)
+		return
+	}
+
+	scr := &storage.SendCoinRequest{ToUser: input.ToUser, Amount: input.Amount}
+	if input.Memo != nil {
+		scr.Memo = *input.Memo
+	}
+	if input.Category != nil {
+		scr.Category = *input.Category
+	}
+
+	if err := h.service.SendFromGroup(r.Context(), username, group, scr); err != nil {
+		if errors.Is(err, shop.ErrUserNotFound) {
+			h.log.WarnContext(r.Context(), "Recipient not found", slog.String("to_user", input.ToUser))
+			h.writeErrorResponse(w, fmt.Sprintf("Пользователь '%s' не найден.", input.ToUser), http.StatusBadRequest)
+			return
+		}
+		h.writeGroupError(r, w, err)
+		return
+	}
+
+	h.log.InfoContext(r.Context(), "Coins transferred from group", slog.String("group", group))
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeGroupError переводит ошибки работы с группами в статусы ответа.
+func (h *Handlers) writeGroupError(r *http.Request, w http.ResponseWriter, err error) {
+	var (
+		ve *shop.ValidationError
+		le *shop.LimitExceededError
+	)
+	switch {
+	case errors.As(err, &ve):
+		h.log.WarnContext(r.Context(), "Invalid group request", slog.String("error", ve.Error()))
+		h.writeValidationError(w, ve)
+	case errors.As(err, &le):
+		h.writeLimitError(r, w, le)
+	case errors.Is(err, shop.ErrInsufficientFunds):
+		h.log.WarnContext(r.Context(), "Insufficient group funds")
+		h.writeErrorResponse(w, "Недостаточно средств.", http.StatusBadRequest)
+	case errors.Is(err, shop.ErrUserNotFound):
+		h.writeErrorResponse(w, "Пользователь не найден.", http.StatusBadRequest)
+	case errors.Is(err, shop.ErrNotGroupMember):
+		h.writeErrorResponse(w, "Пользователь не состоит в группе.", http.StatusBadRequest)
+	case errors.Is(err, shop.ErrForbidden):
+		h.log.WarnContext(r.Context(), "Group operation forbidden")
+		h.writeErrorResponse(w, "Недостаточно прав.", http.StatusForbidden)
+	case errors.Is(err, shop.ErrGroupNotFound):
+		h.writeErrorResponse(w, "Группа не найдена.", http.StatusNotFound)
+	case errors.Is(err, shop.ErrGroupPurchaseNotFound):
+		h.writeErrorResponse(w, "Покупка группы не найдена.", http.StatusNotFound)
+	case errors.Is(err, shop.ErrGroupExists):
+		h.writeErrorResponse(w, "Имя группы уже занято.", http.StatusConflict)
+	case errors.Is(err, shop.ErrLastGroupAdmin):
+		h.writeErrorResponse(w, "В группе должен остаться хотя бы один администратор.", http.StatusConflict)
+	case errors.Is(err, shop.ErrGroupPurchaseNotPending):
+		h.writeErrorResponse(w, "Покупка группы уже выполнена или отменена.", http.StatusConflict)
+	case errors.Is(err, shop.ErrGroupPurchaseAlreadyApproved):
+		h.writeErrorResponse(w, "Покупка уже одобрена вами.", http.StatusConflict)
+	default:
+		h.log.ErrorContext(r.Context(), "Failed to process group request", slog.String("error", err.Error()))
+		h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
+	}
+}
@@ -1,150 +1,478 @@
 package urls
 
 import (
+	"avito-shop/internal/http-server/api"
 	"avito-shop/internal/http-server/handlers/auth"
+	"avito-shop/internal/metrics"
 	"avito-shop/internal/service/shop"
 	"avito-shop/internal/service/shop/storage"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 )
 
-type Response struct {
-	Status string `json:"status"`
-	Error  string `json:"error,omitempty"`
-}
-
+// Handlers реализует операции магазина из api.ServerInterface.
 type Handlers struct {
 	service shop.IService
 	storage storage.IStorage
 	log     *slog.Logger
-}
-type IHandlers interface {
-	Auth(authKey string) http.HandlerFunc
-	Info() http.HandlerFunc
-	SendCoin() http.HandlerFunc
-	BuyItem() http.HandlerFunc
+	authKey string
 }
 
-func NewHandlers(storage storage.IStorage, service shop.IService, log *slog.Logger) *Handlers {
-	return &Handlers{storage: storage, service: service, log: log}
+func NewHandlers(storage storage.IStorage, service shop.IService, log *slog.Logger, authKey string) *Handlers {
+	return &Handlers{storage: storage, service: service, log: log, authKey: authKey}
 }
 
 func (h *Handlers) writeErrorResponse(w http.ResponseWriter, errorMessage string, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(map[string]string{"errors": errorMessage})
+	json.NewEncoder(w).Encode(api.ErrorResponse{Errors: &errorMessage})
 }
 
-func (h *Handlers) Auth(authKey string) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		var input storage.AuthRequest
-		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-			h.log.Warn("Invalid request body", slog.String("error", err.Error()))
-			h.writeErrorResponse(w, "Неверный запрос.", http.StatusBadRequest)
-			return
-		}
-		if input.Username == "" || input.Password == "" {
-			h.log.Warn("Invalid request body")
-			h.writeErrorResponse(w, "Неверный запрос.", http.StatusBadRequest)
-			return
+func (h *Handlers) writeValidationError(w http.ResponseWriter, ve *shop.ValidationError) {
+	errorMessage := "Неверный запрос."
+	fields := make([]api.FieldError, 0, len(ve.Fields))
+	for _, f := range ve.Fields {
+		fields = append(fields, api.FieldError{Field: f.Field, Message: f.Message})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(api.ErrorResponse{Errors: &errorMessage, Fields: &fields})
+}
+
+func (h *Handlers) Auth(w http.ResponseWriter, r *http.Request) {
+	var input api.AuthRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.log.WarnContext(r.Context(), "Invalid request body", slog.String("error", err.Error()))
+		metrics.AuthFailuresTotal.WithLabelValues(metrics.AuthReasonBadRequest).Inc()
+		h.writeErrorResponse(w, "Неверный запрос.", http.StatusBadRequest)
+		return
+	}
+	if input.Username == "" || input.Password == "" {
+		h.log.WarnContext(r.Context(), "Invalid request body")
+		metrics.AuthFailuresTotal.WithLabelValues(metrics.AuthReasonBadRequest).Inc()
+		h.writeErrorResponse(w, "Неверный запрос.", http.StatusBadRequest)
+		return
+	}
+	token, created, err := auth.AuthenticateUser(r.Context(), h.storage, input.Username, input.Password, h.authKey)
+	var ve *shop.ValidationError
+	if errors.As(err, &ve) {
+		h.log.WarnContext(r.Context(), "Invalid username", slog.String("error", ve.Error()))
+		metrics.AuthFailuresTotal.WithLabelValues(metrics.AuthReasonBadRequest).Inc()
+		h.writeValidationError(w, ve)
+		return
+	}
+	if err != nil {
+		h.log.WarnContext(r.Context(), "Authentication failed", slog.String("username", input.Username))
+		metrics.AuthFailuresTotal.WithLabelValues(metrics.AuthReasonInvalidPassword).Inc()
+		if errors.Is(err, auth.ErrInvalidPassword) {
+			h.service.RecordAuth(r.Context(), input.Username, shop.AuditAuthFailed)
 		}
-		token, err := auth.AuthenticateUser(h.storage, input.Username, input.Password, authKey)
-		if err != nil {
-			h.log.Warn("Authentication failed", slog.String("username", input.Username))
-			h.writeErrorResponse(w, "Неавторизован.", http.StatusUnauthorized)
-			return
+		h.writeErrorResponse(w, "Неавторизован.", http.StatusUnauthorized)
+		return
+	}
+	action := shop.AuditAuthLogin
+	if created {
+		action = shop.AuditAuthRegister
+		h.log.InfoContext(r.Context(), "User created", slog.String("username", input.Username))
+	}
+	h.service.RecordAuth(r.Context(), input.Username, action)
+	h.log.InfoContext(r.Context(), "User authenticated successfully", slog.String("username", input.Username))
+	w.Header().Add("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(api.AuthResponse{Token: &token})
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "Failed to encode response", slog.String("error", err.Error()))
+		h.writeErrorResponse(w, fmt.Sprintf("Внутренняя ошибка сервера: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *Handlers) Info(w http.ResponseWriter, r *http.Request, params api.InfoParams) {
+	username := r.Context().Value("username").(string)
+
+	resp, err := h.service.CollectAllInfo(r.Context(), username)
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "Failed to collect user info", slog.String("error", err.Error()))
+		h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
+		return
+	}
+	// По спецификации inventory — массив, поэтому пустой инвентарь отдаём как [], а не null.
+	if resp.Inventory == nil {
+		resp.Inventory = []storage.Inventory{}
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "Failed to encode response", slog.String("error", err.Error()))
+		h.writeErrorResponse(w, fmt.Sprintf("Внутренняя ошибка сервера: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	tag := etag(body)
+	w.Header().Set("ETag", tag)
+	// Ответ зависит от пользователя, поэтому общие кэши его не хранят, а клиент перепроверяет через If-None-Match.
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if params.IfNoneMatch != nil && etagMatches(*params.IfNoneMatch, tag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.Write(append(body, '\n'))
+}
+
+// etag возвращает сильный ETag для тела ответа.
+func etag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches проверяет заголовок If-None-Match по правилам слабого сравнения (RFC 9110, 13.1.2).
+func etagMatches(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == tag {
+			return true
 		}
-		h.log.Info("User authenticated successfully", slog.String("username", input.Username))
-		w.Header().Add("Content-Type", "application/json")
-		err = json.NewEncoder(w).Encode(map[string]string{"token": token})
-		if err != nil {
-			h.log.Error("Failed to encode response", slog.String("error", err.Error()))
+	}
+	return false
+}
+
+func (h *Handlers) SendCoin(w http.ResponseWriter, r *http.Request) {
+	username := r.Context().Value("username").(string)
+
+	var input api.SendCoinRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.log.WarnContext(r.Context(), "Invalid request body")
+		h.writeErrorResponse(w, "Неверный запрос.", http.StatusBadRequest)
+		return
+	}
+
+	scr := &storage.SendCoinRequest{ToUser: input.ToUser, Amount: input.Amount}
+	if input.Memo != nil {
+		scr.Memo = *input.Memo
+	}
+	if input.Category != nil {
+		scr.Category = *input.Category
+	}
+
+	err := h.service.Send(r.Context(), username, scr)
+	if err != nil {
+		var (
+			ve *shop.ValidationError
+			le *shop.LimitExceededError
+			ae *shop.ApprovalRequiredError
+		)
+		switch {
+		case errors.As(err, &ae):
+			h.log.InfoContext(r.Context(), "Transfer held for approval", slog.Int("id", ae.Transfer.ID))
+			h.writeJSON(r, w, http.StatusAccepted, ae.Transfer)
+		case errors.As(err, &ve):
+			h.log.WarnContext(r.Context(), "Invalid transfer", slog.String("error", ve.Error()))
+			h.writeValidationError(w, ve)
+		case errors.As(err, &le):
+			h.writeLimitError(r, w, le)
+		case errors.Is(err, shop.ErrInsufficientFunds):
+			h.log.WarnContext(r.Context(), "Insufficient funds")
+			h.writeErrorResponse(w, "Недостаточно средств.", http.StatusBadRequest)
+		case errors.Is(err, shop.ErrUserNotFound):
+			h.log.WarnContext(r.Context(), "Recipient not found", slog.String("to_user", input.ToUser))
+			h.writeErrorResponse(w, fmt.Sprintf("Пользователь '%s' не найден.", input.ToUser), http.StatusBadRequest)
+		default:
+			h.log.ErrorContext(r.Context(), "Failed to process transaction", slog.String("error", err.Error()))
 			h.writeErrorResponse(w, fmt.Sprintf("Внутренняя ошибка сервера: %s", err.Error()), http.StatusInternalServerError)
-			return
 		}
+		return
 	}
+	h.log.InfoContext(r.Context(), "Coins transferred successfully")
+	w.WriteHeader(http.StatusOK)
 }
 
-func (h *Handlers) Info() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		username := r.Context().Value("username").(string)
-		w.Header().Add("Content-Type", "application/json")
+func (h *Handlers) History(w http.ResponseWriter, r *http.Request, params api.HistoryParams) {
+	username := r.Context().Value("username").(string)
 
-		resp, err := h.service.CollectAllInfo(username)
-		if err != nil {
-			h.log.Error("Failed to collect user info", slog.String("error", err.Error()))
+	var filter storage.HistoryFilter
+	if params.Category != nil {
+		filter.Category = *params.Category
+	}
+
+	history, err := h.service.History(r.Context(), username, filter)
+	if err != nil {
+		var ve *shop.ValidationError
+		switch {
+		case errors.As(err, &ve):
+			h.log.WarnContext(r.Context(), "Invalid history filter", slog.String("error", ve.Error()))
+			h.writeValidationError(w, ve)
+		default:
+			h.log.ErrorContext(r.Context(), "Failed to load coin history", slog.String("error", err.Error()))
 			h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
-			return
 		}
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(history); err != nil {
+		h.log.ErrorContext(r.Context(), "Failed to encode response", slog.String("error", err.Error()))
+	}
+}
+
+func (h *Handlers) BuyItem(w http.ResponseWriter, r *http.Request, item string, params api.BuyItemParams) {
+	username := r.Context().Value("username").(string)
 
-		err = json.NewEncoder(w).Encode(&resp)
-		if err != nil {
-			h.log.Error("Failed to encode response", slog.String("error", err.Error()))
+	if item == "" {
+		h.log.WarnContext(r.Context(), "Bad Request: item is empty")
+		h.writeErrorResponse(w, "Неверный запрос. Предмет не указан.", http.StatusBadRequest)
+		return
+	}
+
+	var promoCode string
+	if params.PromoCode != nil {
+		promoCode = *params.PromoCode
+	}
+
+	err := h.service.Purchase(r.Context(), username, item, promoCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, shop.ErrItemNotFound):
+			h.log.WarnContext(r.Context(), "Item not found", slog.String("item", item))
+			h.writeErrorResponse(w, fmt.Sprintf("Предмет '%s' не найден.", item), http.StatusBadRequest)
+		case errors.Is(err, shop.ErrInsufficientFunds):
+			h.log.WarnContext(r.Context(), "Insufficient funds", slog.String("username", username))
+			h.writeErrorResponse(w, "Недостаточно средств.", http.StatusBadRequest)
+		case isPromoCodeError(err):
+			h.writePromoCodeError(r, w, promoCode, err)
+		default:
+			h.log.ErrorContext(r.Context(), "Failed to process purchase", slog.String("error", err.Error()))
 			h.writeErrorResponse(w, fmt.Sprintf("Внутренняя ошибка сервера: %s", err.Error()), http.StatusInternalServerError)
-			return
 		}
+		return
 	}
+
+	h.log.InfoContext(r.Context(), "Item purchased successfully", slog.String("username", username), slog.String("item", item))
+	w.WriteHeader(http.StatusOK)
 }
 
-func (h *Handlers) SendCoin() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		username := r.Context().Value("username").(string)
+func (h *Handlers) CreatePaymentRequest(w http.ResponseWriter, r *http.Request) {
+	username := r.Context().Value("username").(string)
+
+	var input api.CreatePaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.log.WarnContext(r.Context(), "Invalid request body")
+		h.writeErrorResponse(w, "Неверный запрос.", http.StatusBadRequest)
+		return
+	}
+
+	req := &shop.PaymentRequestInput{FromUser: input.FromUser, Amount: input.Amount}
+	if input.Memo != nil {
+		req.Memo = *input.Memo
+	}
 
-		var input storage.SendCoinRequest
-		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-			h.log.Warn("Invalid request body")
-			h.writeErrorResponse(w, "Неверный запрос.", http.StatusBadRequest)
-			return
+	pr, err := h.service.RequestPayment(r.Context(), username, req)
+	if err != nil {
+		var (
+			ve *shop.ValidationError
+			le *shop.LimitExceededError
+		)
+		switch {
+		case errors.As(err, &ve):
+			h.log.WarnContext(r.Context(), "Invalid payment request", slog.String("error", ve.Error()))
+			h.writeValidationError(w, ve)
+		case errors.As(err, &le):
+			h.writeLimitError(r, w, le)
+		case errors.Is(err, shop.ErrUserNotFound):
+			h.log.WarnContext(r.Context(), "Payer not found", slog.String("from_user", input.FromUser))
+			h.writeErrorResponse(w, fmt.Sprintf("Пользователь '%s' не найден.", input.FromUser), http.StatusBadRequest)
+		default:
+			h.log.ErrorContext(r.Context(), "Failed to create payment request", slog.String("error", err.Error()))
+			h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
 		}
+		return
+	}
+
+	h.log.InfoContext(r.Context(), "Payment request created", slog.Int("id", pr.ID))
+	h.writeJSON(r, w, http.StatusCreated, pr)
+}
 
-		err := h.service.Send(username, &input)
-		if err != nil {
-			switch {
-			case errors.Is(err, shop.ErrInsufficientFunds):
-				h.log.Warn("Insufficient funds")
-				h.writeErrorResponse(w, "Недостаточно средств.", http.StatusBadRequest)
-			default:
-				h.log.Error("Failed to process transaction", slog.String("error", err.Error()))
-				h.writeErrorResponse(w, fmt.Sprintf("Внутренняя ошибка сервера: %s", err.Error()), http.StatusInternalServerError)
-			}
-			return
+func (h *Handlers) ListPaymentRequests(w http.ResponseWriter, r *http.Request, params api.ListPaymentRequestsParams) {
+	username := r.Context().Value("username").(string)
+
+	var direction string
+	if params.Direction != nil {
+		direction = *params.Direction
+	}
+
+	prs, err := h.service.ListPaymentRequests(r.Context(), username, direction)
+	if err != nil {
+		var ve *shop.ValidationError
+		switch {
+		case errors.As(err, &ve):
+			h.log.WarnContext(r.Context(), "Invalid payment request filter", slog.String("error", ve.Error()))
+			h.writeValidationError(w, ve)
+		default:
+			h.log.ErrorContext(r.Context(), "Failed to list payment requests", slog.String("error", err.Error()))
+			h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
 		}
-		h.log.Info("Coins transferred successfully")
-		w.WriteHeader(http.StatusOK)
+		return
 	}
+
+	h.writeJSON(r, w, http.StatusOK, prs)
 }
 
-func (h *Handlers) BuyItem() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		username := r.Context().Value("username").(string)
+func (h *Handlers) AcceptPaymentRequest(w http.ResponseWriter, r *http.Request, id int) {
+	h.resolvePaymentRequest(w, r, id, h.service.AcceptPaymentRequest)
+}
 
-		item := r.PathValue("item")
-		if item == "" {
-			h.log.Warn("Bad Request: item is empty")
-			h.writeErrorResponse(w, "Неверный запрос. Предмет не указан.", http.StatusBadRequest)
-			return
+func (h *Handlers) DeclinePaymentRequest(w http.ResponseWriter, r *http.Request, id int) {
+	h.resolvePaymentRequest(w, r, id, h.service.DeclinePaymentRequest)
+}
+
+func (h *Handlers) CancelPaymentRequest(w http.ResponseWriter, r *http.Request, id int) {
+	h.resolvePaymentRequest(w, r, id, h.service.CancelPaymentRequest)
+}
+
+// resolvePaymentRequest выполняет действие над запросом на перевод и переводит ошибки сервиса в статусы ответа.
+func (h *Handlers) resolvePaymentRequest(w http.ResponseWriter, r *http.Request, id int,
+	action func(ctx context.Context, username string, id int) (*storage.PaymentRequest, error)) {
+	username := r.Context().Value("username").(string)
+
+	pr, err := action(r.Context(), username, id)
+	if err != nil {
+		var (
+			ve *shop.ValidationError
+			le *shop.LimitExceededError
+		)
+		switch {
+		case errors.As(err, &ve):
+			h.log.WarnContext(r.Context(), "Invalid payment request", slog.String("error", ve.Error()))
+			h.writeValidationError(w, ve)
+		case errors.Is(err, shop.ErrPaymentRequestNotFound):
+			h.log.WarnContext(r.Context(), "Payment request not found", slog.Int("id", id))
+			h.writeErrorResponse(w, "Запрос на перевод не найден.", http.StatusNotFound)
+		case errors.Is(err, shop.ErrPaymentRequestNotPending):
+			h.log.WarnContext(r.Context(), "Payment request already resolved", slog.Int("id", id))
+			h.writeErrorResponse(w, "Запрос на перевод уже закрыт.", http.StatusConflict)
+		case errors.Is(err, shop.ErrPaymentRequestExpired):
+			h.log.WarnContext(r.Context(), "Payment request expired", slog.Int("id", id))
+			h.writeErrorResponse(w, "Срок действия запроса на перевод истёк.", http.StatusConflict)
+		case errors.As(err, &le):
+			h.writeLimitError(r, w, le)
+		case errors.Is(err, shop.ErrInsufficientFunds):
+			h.log.WarnContext(r.Context(), "Insufficient funds", slog.String("username", username))
+			h.writeErrorResponse(w, "Недостаточно средств.", http.StatusBadRequest)
+		default:
+			h.log.ErrorContext(r.Context(), "Failed to resolve payment request", slog.String("error", err.Error()))
+			h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
 		}
+		return
+	}
+
+	h.log.InfoContext(r.Context(), "Payment request resolved", slog.Int("id", id), slog.String("status", pr.Status))
+	h.writeJSON(r, w, http.StatusOK, pr)
+}
+
+// writeLimitError отвечает 400 с описанием нарушенного лимита и текущего использования.
+func (h *Handlers) writeLimitError(r *http.Request, w http.ResponseWriter, le *shop.LimitExceededError) {
+	h.log.WarnContext(r.Context(), "Transfer limit exceeded",
+		slog.String("limit", le.Limit), slog.Int("max", le.Max), slog.Int("used", le.Used))
+	h.writeErrorResponse(w, fmt.Sprintf("Перевод отклонён: %s.", le.Error()), http.StatusBadRequest)
+}
+
+func (h *Handlers) writeJSON(r *http.Request, w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.log.ErrorContext(r.Context(), "Failed to encode response", slog.String("error", err.Error()))
+	}
+}
+
+func (h *Handlers) CreateScheduledTransfer(w http.ResponseWriter, r *http.Request) {
+	username := r.Context().Value("username").(string)
+
+	var input api.CreateScheduledTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.log.WarnContext(r.Context(), "Invalid request body")
+		h.writeErrorResponse(w, "Неверный запрос.", http.StatusBadRequest)
+		return
+	}
 
-		err := h.service.Purchase(username, item)
-		if err != nil {
-			switch {
-			case errors.Is(err, shop.ErrItemNotFound):
-				h.log.Warn("Item not found", slog.String("item", item))
-				h.writeErrorResponse(w, fmt.Sprintf("Предмет '%s' не найден.", item), http.StatusBadRequest)
-			case errors.Is(err, shop.ErrInsufficientFunds):
-				h.log.Warn("Insufficient funds", slog.String("username", username))
-				h.writeErrorResponse(w, "Недостаточно средств.", http.StatusBadRequest)
-			default:
-				h.log.Error("Failed to process purchase", slog.String("error", err.Error()))
-				h.writeErrorResponse(w, fmt.Sprintf("Внутренняя ошибка сервера: %s", err.Error()), http.StatusInternalServerError)
-			}
-			return
+	in := &shop.ScheduledTransferInput{
+		SendCoinRequest: storage.SendCoinRequest{ToUser: input.ToUser, Amount: input.Amount},
+		RunAt:           input.RunAt,
+	}
+	if input.Memo != nil {
+		in.Memo = *input.Memo
+	}
+	if input.Category != nil {
+		in.Category = *input.Category
+	}
+	if input.Schedule != nil {
+		in.Schedule = *input.Schedule
+	}
+
+	st, err := h.service.ScheduleTransfer(r.Context(), username, in)
+	if err != nil {
+		var (
+			ve *shop.ValidationError
+			le *shop.LimitExceededError
+		)
+		switch {
+		case errors.As(err, &ve):
+			h.log.WarnContext(r.Context(), "Invalid scheduled transfer", slog.String("error", ve.Error()))
+			h.writeValidationError(w, ve)
+		case errors.Is(err, shop.ErrUserNotFound):
+			h.log.WarnContext(r.Context(), "Recipient not found", slog.String("to_user", input.ToUser))
+			h.writeErrorResponse(w, fmt.Sprintf("Пользователь '%s' не найден.", input.ToUser), http.StatusBadRequest)
+		case errors.As(err, &le):
+			h.writeLimitError(r, w, le)
+		default:
+			h.log.ErrorContext(r.Context(), "Failed to schedule transfer", slog.String("error", err.Error()))
+			h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
 		}
+		return
+	}
+
+	h.log.InfoContext(r.Context(), "Transfer scheduled", slog.Int("id", st.ID), slog.Time("next_run_at", st.NextRunAt))
+	h.writeJSON(r, w, http.StatusCreated, st)
+}
+
+func (h *Handlers) ListScheduledTransfers(w http.ResponseWriter, r *http.Request) {
+	username := r.Context().Value("username").(string)
+
+	sts, err := h.service.ListScheduledTransfers(r.Context(), username)
+	if err != nil {
+		h.log.ErrorContext(r.Context(), "Failed to list scheduled transfers", slog.String("error", err.Error()))
+		h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
+		return
+	}
 
-		h.log.Info("Item purchased successfully", slog.String("username", username), slog.String("item", item))
-		w.WriteHeader(http.StatusOK)
+	h.writeJSON(r, w, http.StatusOK, sts)
+}
+
+func (h *Handlers) CancelScheduledTransfer(w http.ResponseWriter, r *http.Request, id int) {
+	username := r.Context().Value("username").(string)
+
+	st, err := h.service.CancelScheduledTransfer(r.Context(), username, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, shop.ErrScheduledTransferNotFound):
+			h.log.WarnContext(r.Context(), "Scheduled transfer not found", slog.Int("id", id))
+			h.writeErrorResponse(w, "Отложенный перевод не найден.", http.StatusNotFound)
+		case errors.Is(err, shop.ErrScheduledTransferNotActive):
+			h.log.WarnContext(r.Context(), "Scheduled transfer is not active", slog.Int("id", id))
+			h.writeErrorResponse(w, "Отложенный перевод уже выполнен или отменён.", http.StatusConflict)
+		default:
+			h.log.ErrorContext(r.Context(), "Failed to cancel scheduled transfer", slog.String("error", err.Error()))
+			h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
+		}
+		return
 	}
+
+	h.log.InfoContext(r.Context(), "Scheduled transfer cancelled", slog.Int("id", id))
+	h.writeJSON(r, w, http.StatusOK, st)
 }
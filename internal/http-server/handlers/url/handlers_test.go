@@ -1,13 +1,18 @@
 package urls_test
 
 import (
+	"avito-shop/internal/http-server/api"
 	urls "avito-shop/internal/http-server/handlers/url"
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"avito-shop/internal/service/shop"
 	"avito-shop/internal/service/shop/storage"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -19,62 +24,292 @@ type MockService struct {
 	mock.Mock
 }
 
-func (m *MockService) CollectAllInfo(username string) (*storage.InfoResponse, error) {
+func (m *MockService) CollectAllInfo(ctx context.Context, username string) (*storage.InfoResponse, error) {
 	args := m.Called(username)
 	return args.Get(0).(*storage.InfoResponse), args.Error(1)
 }
 
-func (m *MockService) Send(fromUsername string, scr *storage.SendCoinRequest) error {
+func (m *MockService) Send(ctx context.Context, fromUsername string, scr *storage.SendCoinRequest) error {
 	args := m.Called(fromUsername, scr)
 	return args.Error(0)
 }
 
-func (m *MockService) Purchase(username, item string) error {
-	args := m.Called(username, item)
+func (m *MockService) History(ctx context.Context, username string, filter storage.HistoryFilter) (*storage.CoinHistory, error) {
+	args := m.Called(username, filter)
+	return args.Get(0).(*storage.CoinHistory), args.Error(1)
+}
+
+func (m *MockService) Purchase(ctx context.Context, username, item, promoCode string) error {
+	args := m.Called(username, item, promoCode)
 	return args.Error(0)
 }
 
+func (m *MockService) QuotePurchase(ctx context.Context, username, item, promoCode string) (*storage.PriceQuote, error) {
+	args := m.Called(username, item, promoCode)
+	return args.Get(0).(*storage.PriceQuote), args.Error(1)
+}
+
+func (m *MockService) RequestPayment(ctx context.Context, requester string, req *shop.PaymentRequestInput) (*storage.PaymentRequest, error) {
+	args := m.Called(requester, req)
+	return args.Get(0).(*storage.PaymentRequest), args.Error(1)
+}
+
+func (m *MockService) ListPaymentRequests(ctx context.Context, username string, direction string) ([]storage.PaymentRequest, error) {
+	args := m.Called(username, direction)
+	return args.Get(0).([]storage.PaymentRequest), args.Error(1)
+}
+
+func (m *MockService) AcceptPaymentRequest(ctx context.Context, username string, id int) (*storage.PaymentRequest, error) {
+	args := m.Called(username, id)
+	return args.Get(0).(*storage.PaymentRequest), args.Error(1)
+}
+
+func (m *MockService) DeclinePaymentRequest(ctx context.Context, username string, id int) (*storage.PaymentRequest, error) {
+	args := m.Called(username, id)
+	return args.Get(0).(*storage.PaymentRequest), args.Error(1)
+}
+
+func (m *MockService) CancelPaymentRequest(ctx context.Context, username string, id int) (*storage.PaymentRequest, error) {
+	args := m.Called(username, id)
+	return args.Get(0).(*storage.PaymentRequest), args.Error(1)
+}
+
+func (m *MockService) ScheduleTransfer(ctx context.Context, fromUsername string, in *shop.ScheduledTransferInput) (*storage.ScheduledTransfer, error) {
+	args := m.Called(fromUsername, in)
+	return args.Get(0).(*storage.ScheduledTransfer), args.Error(1)
+}
+
+func (m *MockService) ListScheduledTransfers(ctx context.Context, username string) ([]storage.ScheduledTransfer, error) {
+	args := m.Called(username)
+	return args.Get(0).([]storage.ScheduledTransfer), args.Error(1)
+}
+
+func (m *MockService) CancelScheduledTransfer(ctx context.Context, username string, id int) (*storage.ScheduledTransfer, error) {
+	args := m.Called(username, id)
+	return args.Get(0).(*storage.ScheduledTransfer), args.Error(1)
+}
+
+func (m *MockService) RunDueTransfers(ctx context.Context) ([]storage.ScheduledTransfer, error) {
+	args := m.Called()
+	return args.Get(0).([]storage.ScheduledTransfer), args.Error(1)
+}
+
+func (m *MockService) ExpireCoins(ctx context.Context) ([]storage.ExpiredCoins, error) {
+	args := m.Called()
+	return args.Get(0).([]storage.ExpiredCoins), args.Error(1)
+}
+
+func (m *MockService) CreatePromoCode(ctx context.Context, admin string, in *shop.PromoCodeInput) (*storage.PromoCode, error) {
+	args := m.Called(admin, in)
+	return args.Get(0).(*storage.PromoCode), args.Error(1)
+}
+
+func (m *MockService) ListPromoCodes(ctx context.Context, admin string) ([]storage.PromoCode, error) {
+	args := m.Called(admin)
+	return args.Get(0).([]storage.PromoCode), args.Error(1)
+}
+
+func (m *MockService) DisablePromoCode(ctx context.Context, admin, code string) (*storage.PromoCode, error) {
+	args := m.Called(admin, code)
+	return args.Get(0).(*storage.PromoCode), args.Error(1)
+}
+
+func (m *MockService) Catalog(ctx context.Context) ([]storage.CatalogItem, error) {
+	args := m.Called()
+	return args.Get(0).([]storage.CatalogItem), args.Error(1)
+}
+
+func (m *MockService) ListOrders(ctx context.Context, username string) ([]storage.Order, error) {
+	args := m.Called(username)
+	return args.Get(0).([]storage.Order), args.Error(1)
+}
+
+func (m *MockService) ScheduleItemPrice(ctx context.Context, admin string, in *shop.ItemPriceInput) (*storage.ItemPrice, error) {
+	args := m.Called(admin, in)
+	return args.Get(0).(*storage.ItemPrice), args.Error(1)
+}
+
+func (m *MockService) ListItemPrices(ctx context.Context, admin string) ([]storage.ItemPrice, error) {
+	args := m.Called(admin)
+	return args.Get(0).([]storage.ItemPrice), args.Error(1)
+}
+
+func (m *MockService) CancelItemPrice(ctx context.Context, admin string, id int) (*storage.ItemPrice, error) {
+	args := m.Called(admin, id)
+	return args.Get(0).(*storage.ItemPrice), args.Error(1)
+}
+
+func (m *MockService) Leaderboard(ctx context.Context, board, period string, limit int) (*storage.Leaderboard, error) {
+	args := m.Called(board, period, limit)
+	return args.Get(0).(*storage.Leaderboard), args.Error(1)
+}
+
+func (m *MockService) SetLeaderboardOptOut(ctx context.Context, username string, optOut bool) error {
+	args := m.Called(username, optOut)
+	return args.Error(0)
+}
+
+func (m *MockService) CreateGroup(ctx context.Context, username string, in *shop.GroupInput) (*storage.Group, error) {
+	args := m.Called(username, in)
+	return args.Get(0).(*storage.Group), args.Error(1)
+}
+
+func (m *MockService) ListGroups(ctx context.Context, username string) ([]storage.Group, error) {
+	args := m.Called(username)
+	return args.Get(0).([]storage.Group), args.Error(1)
+}
+
+func (m *MockService) GetGroup(ctx context.Context, username, name string) (*storage.GroupInfo, error) {
+	args := m.Called(username, name)
+	return args.Get(0).(*storage.GroupInfo), args.Error(1)
+}
+
+func (m *MockService) GroupHistory(ctx context.Context, username, name string, filter storage.HistoryFilter) (*storage.CoinHistory, error) {
+	args := m.Called(username, name, filter)
+	return args.Get(0).(*storage.CoinHistory), args.Error(1)
+}
+
+func (m *MockService) SetGroupMember(ctx context.Context, username, name string, in *shop.GroupMemberInput) ([]storage.GroupMember, error) {
+	args := m.Called(username, name, in)
+	return args.Get(0).([]storage.GroupMember), args.Error(1)
+}
+
+func (m *MockService) RemoveGroupMember(ctx context.Context, username, name, member string) ([]storage.GroupMember, error) {
+	args := m.Called(username, name, member)
+	return args.Get(0).([]storage.GroupMember), args.Error(1)
+}
+
+func (m *MockService) SendFromGroup(ctx context.Context, username, name string, scr *storage.SendCoinRequest) error {
+	args := m.Called(username, name, scr)
+	return args.Error(0)
+}
+
+func (m *MockService) ProposeGroupPurchase(ctx context.Context, username, name, item string) (*storage.GroupPurchase, error) {
+	args := m.Called(username, name, item)
+	return args.Get(0).(*storage.GroupPurchase), args.Error(1)
+}
+
+func (m *MockService) ApproveGroupPurchase(ctx context.Context, username, name string, id int) (*storage.GroupPurchase, error) {
+	args := m.Called(username, name, id)
+	return args.Get(0).(*storage.GroupPurchase), args.Error(1)
+}
+
+func (m *MockService) CancelGroupPurchase(ctx context.Context, username, name string, id int) (*storage.GroupPurchase, error) {
+	args := m.Called(username, name, id)
+	return args.Get(0).(*storage.GroupPurchase), args.Error(1)
+}
+
+func (m *MockService) ListGroupPurchases(ctx context.Context, username, name string) ([]storage.GroupPurchase, error) {
+	args := m.Called(username, name)
+	return args.Get(0).([]storage.GroupPurchase), args.Error(1)
+}
+
+func (m *MockService) ListPendingTransfers(ctx context.Context, username string) ([]storage.PendingTransfer, error) {
+	args := m.Called(username)
+	return args.Get(0).([]storage.PendingTransfer), args.Error(1)
+}
+
+func (m *MockService) ApprovePendingTransfer(ctx context.Context, approver string, id int) (*storage.PendingTransfer, error) {
+	args := m.Called(approver, id)
+	return args.Get(0).(*storage.PendingTransfer), args.Error(1)
+}
+
+func (m *MockService) RejectPendingTransfer(ctx context.Context, approver string, id int) (*storage.PendingTransfer, error) {
+	args := m.Called(approver, id)
+	return args.Get(0).(*storage.PendingTransfer), args.Error(1)
+}
+
+func (m *MockService) ExpirePendingTransfers(ctx context.Context) ([]storage.PendingTransfer, error) {
+	args := m.Called()
+	return args.Get(0).([]storage.PendingTransfer), args.Error(1)
+}
+
+func (m *MockService) ListUserTransactions(ctx context.Context, admin, username string) ([]storage.Transaction, error) {
+	args := m.Called(admin, username)
+	return args.Get(0).([]storage.Transaction), args.Error(1)
+}
+
+func (m *MockService) ReverseTransfer(ctx context.Context, admin string, in *shop.ReversalInput) (*storage.TransferReversal, error) {
+	args := m.Called(admin, in)
+	return args.Get(0).(*storage.TransferReversal), args.Error(1)
+}
+
+func (m *MockService) RecordAuth(ctx context.Context, username, action string) {
+	m.Called(username, action)
+}
+
+func (m *MockService) AuditLog(ctx context.Context, admin string, filter storage.AuditFilter) ([]storage.AuditEntry, error) {
+	args := m.Called(admin, filter)
+	return args.Get(0).([]storage.AuditEntry), args.Error(1)
+}
+
+func (m *MockService) ExportAuditLog(ctx context.Context, admin string, filter storage.AuditFilter,
+	write func([]storage.AuditEntry) error) error {
+	args := m.Called(admin, filter)
+	if entries, ok := args.Get(0).([]storage.AuditEntry); ok && len(entries) > 0 {
+		if err := write(entries); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockService) VerifyAuditLog(ctx context.Context, admin string) (*shop.AuditVerification, error) {
+	args := m.Called(admin)
+	return args.Get(0).(*shop.AuditVerification), args.Error(1)
+}
+
 type MockStorage struct {
 	mock.Mock
 }
 
-func (m *MockStorage) CheckAuth(username string) (string, error) {
+func (m *MockStorage) CheckAuth(ctx context.Context, username string) (string, error) {
 	args := m.Called(username)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockStorage) AddNewUser(username, password string) error {
+func (m *MockStorage) AddNewUser(ctx context.Context, username, password string) error {
 	args := m.Called(username, password)
 	return args.Error(0)
 }
 
-func (m *MockStorage) GetInfo(ir *storage.InfoResponse, username string) (int, error) {
+func (m *MockStorage) GetInfo(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
 	args := m.Called(ir, username)
 	return args.Int(0), args.Error(1)
 }
 
-func (m *MockStorage) GetInventory(ir *storage.InfoResponse, id int) error {
+func (m *MockStorage) GetFullInfo(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+	args := m.Called(ir, username)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStorage) GetInventory(ctx context.Context, ir *storage.InfoResponse, id int) error {
 	args := m.Called(ir, id)
 	return args.Error(0)
 }
 
-func (m *MockStorage) GetReceivedHistory(ir *storage.InfoResponse, id int) error {
+func (m *MockStorage) GetReceivedHistory(ctx context.Context, ir *storage.InfoResponse, id int) error {
 	args := m.Called(ir, id)
 	return args.Error(0)
 }
 
-func (m *MockStorage) GetSendHistory(ir *storage.InfoResponse, id int) error {
+func (m *MockStorage) GetSendHistory(ctx context.Context, ir *storage.InfoResponse, id int) error {
 	args := m.Called(ir, id)
 	return args.Error(0)
 }
 
-func (m *MockStorage) BuyItem(name, item string, amount int) error {
+func (m *MockStorage) GetCoinHistory(ctx context.Context, ir *storage.InfoResponse, id int, filter storage.HistoryFilter) error {
+	args := m.Called(ir, id, filter)
+	return args.Error(0)
+}
+
+func (m *MockStorage) BuyItem(ctx context.Context, name, item string, amount int) error {
 	args := m.Called(name, item, amount)
 	return args.Error(0)
 }
 
-func (m *MockStorage) SendCoins(username string, fromUserID int, toUserID int, scr *storage.SendCoinRequest) error {
-	args := m.Called(username, fromUserID, toUserID, scr)
+func (m *MockStorage) SendCoins(ctx context.Context, username string, fromUserID int, toUserID int, scr *storage.SendCoinRequest, limits storage.TransferLimits) error {
+	args := m.Called(username, fromUserID, toUserID, scr, limits)
 	return args.Error(0)
 }
 
@@ -105,7 +340,7 @@ func TestInfoHandler_E2E(t *testing.T) {
 	logger := slog.New(slog.NewJSONHandler(nil, nil))
 
 	// Создаем обработчик
-	handlers := urls.NewHandlers(nil, mockService, logger)
+	handlers := urls.NewHandlers(nil, mockService, logger, "")
 
 	// Создаем HTTP-запрос
 	req := httptest.NewRequest(http.MethodGet, "/info", nil)
@@ -117,8 +352,7 @@ func TestInfoHandler_E2E(t *testing.T) {
 	rr := httptest.NewRecorder()
 
 	// Вызываем обработчик
-	handler := handlers.Info()
-	handler.ServeHTTP(rr, req)
+	handlers.Info(rr, req, api.InfoParams{})
 
 	// Проверяем статус-код
 	require.Equal(t, http.StatusOK, rr.Code)
@@ -142,3 +376,729 @@ func TestInfoHandler_E2E(t *testing.T) {
 	// Проверяем, что метод CollectAllInfo был вызван
 	mockService.AssertCalled(t, "CollectAllInfo", "testuser")
 }
+
+func TestInfoHandler_IfNoneMatch(t *testing.T) {
+	mockService := new(MockService)
+	mockService.On("CollectAllInfo", "testuser").Return(&storage.InfoResponse{Coins: 1000}, nil)
+	handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+	request := func(params api.InfoParams) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+		req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+		rr := httptest.NewRecorder()
+		handlers.Info(rr, req, params)
+		return rr
+	}
+
+	first := request(api.InfoParams{})
+	require.Equal(t, http.StatusOK, first.Code)
+	tag := first.Header().Get("ETag")
+	require.NotEmpty(t, tag)
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{"same etag", tag, http.StatusNotModified},
+		{"weak etag in list", `"other", W/` + tag, http.StatusNotModified},
+		{"wildcard", "*", http.StatusNotModified},
+		{"stale etag", `"other"`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := request(api.InfoParams{IfNoneMatch: &tt.ifNoneMatch})
+			require.Equal(t, tt.wantStatus, rr.Code)
+			require.Equal(t, tag, rr.Header().Get("ETag"))
+			if tt.wantStatus == http.StatusNotModified {
+				require.Empty(t, rr.Body.Bytes())
+			} else {
+				require.Equal(t, first.Body.String(), rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestSendCoinHandler_ValidationError(t *testing.T) {
+	mockService := new(MockService)
+	scr := &storage.SendCoinRequest{ToUser: "testuser", Amount: -5}
+	mockService.On("Send", "testuser", scr).Return(&shop.ValidationError{Fields: []shop.FieldError{
+		{Field: "toUser", Message: "нельзя отправить монеты самому себе"},
+		{Field: "amount", Message: "сумма перевода должна быть положительной"},
+	}})
+
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	handlers := urls.NewHandlers(nil, mockService, logger, "")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sendCoin", strings.NewReader(`{"toUser": "testuser", "amount": -5}`))
+	req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+	rr := httptest.NewRecorder()
+
+	handlers.SendCoin(rr, req)
+
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.JSONEq(t, `{
+		"errors": "Неверный запрос.",
+		"fields": [
+			{"field": "toUser", "message": "нельзя отправить монеты самому себе"},
+			{"field": "amount", "message": "сумма перевода должна быть положительной"}
+		]
+	}`, rr.Body.String())
+}
+
+func TestSendCoinHandler_LimitExceeded(t *testing.T) {
+	mockService := new(MockService)
+	scr := &storage.SendCoinRequest{ToUser: "bob", Amount: 50}
+	mockService.On("Send", "alice", scr).Return(&shop.LimitExceededError{Limit: storage.LimitDailySent, Max: 300, Used: 280})
+
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	handlers := urls.NewHandlers(nil, mockService, logger, "")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sendCoin", strings.NewReader(`{"toUser": "bob", "amount": 50}`))
+	req = req.WithContext(context.WithValue(req.Context(), "username", "alice"))
+	rr := httptest.NewRecorder()
+
+	handlers.SendCoin(rr, req)
+
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+	require.JSONEq(t, `{"errors": "Перевод отклонён: превышен суточный лимит отправки: 300 монет, сегодня уже отправлено 280."}`, rr.Body.String())
+}
+
+func TestSendCoinHandler_ApprovalRequired(t *testing.T) {
+	created := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	mockService := new(MockService)
+	scr := &storage.SendCoinRequest{ToUser: "bob", Amount: 600}
+	mockService.On("Send", "alice", scr).Return(&shop.ApprovalRequiredError{Transfer: &storage.PendingTransfer{
+		ID: 4, Sender: "alice", Recipient: "bob", Amount: 600, Fee: 12, Status: storage.PendingTransferPending,
+		CreatedAt: created, ExpiresAt: created.Add(72 * time.Hour),
+	}})
+
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	handlers := urls.NewHandlers(nil, mockService, logger, "")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/sendCoin", strings.NewReader(`{"toUser": "bob", "amount": 600}`))
+	req = req.WithContext(context.WithValue(req.Context(), "username", "alice"))
+	rr := httptest.NewRecorder()
+
+	handlers.SendCoin(rr, req)
+
+	require.Equal(t, http.StatusAccepted, rr.Code)
+	require.JSONEq(t, `{
+		"id": 4, "sender": "alice", "recipient": "bob", "amount": 600, "fee": 12, "status": "pending",
+		"createdAt": "2025-02-01T12:00:00Z", "expiresAt": "2025-02-04T12:00:00Z"
+	}`, rr.Body.String())
+}
+
+func TestHistoryHandler(t *testing.T) {
+	lunch, casino := "lunch", "casino"
+	tests := []struct {
+		name       string
+		params     api.HistoryParams
+		filter     storage.HistoryFilter
+		result     *storage.CoinHistory
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:   "Filtered by category",
+			params: api.HistoryParams{Category: &lunch},
+			filter: storage.HistoryFilter{Category: "lunch"},
+			result: &storage.CoinHistory{
+				Received: []storage.TransactionIn{{FromUser: "2", Amount: 10, Memo: "за обед", Category: "lunch"}},
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"received": [{"fromUser": "2", "amount": 10, "memo": "за обед", "category": "lunch"}]}`,
+		},
+		{
+			name:   "Unknown category",
+			params: api.HistoryParams{Category: &casino},
+			filter: storage.HistoryFilter{Category: "casino"},
+			err: &shop.ValidationError{Fields: []shop.FieldError{
+				{Field: "category", Message: "неизвестная категория"},
+			}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"errors": "Неверный запрос.", "fields": [{"field": "category", "message": "неизвестная категория"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("History", "testuser", tt.filter).Return(tt.result, tt.err)
+			handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+			req := httptest.NewRequest(http.MethodGet, "/api/history", nil)
+			req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+			rr := httptest.NewRecorder()
+
+			handlers.History(rr, req, tt.params)
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+			require.JSONEq(t, tt.wantBody, rr.Body.String())
+		})
+	}
+}
+
+func TestAcceptPaymentRequestHandler(t *testing.T) {
+	created := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	accepted := &storage.PaymentRequest{
+		ID: 7, Requester: "2", Payer: "testuser", Amount: 40, Memo: "за пиццу",
+		Status: storage.PaymentRequestAccepted, CreatedAt: created, ExpiresAt: created.Add(72 * time.Hour),
+	}
+	tests := []struct {
+		name       string
+		result     *storage.PaymentRequest
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "Accepted",
+			result:     accepted,
+			wantStatus: http.StatusOK,
+			wantBody: `{"id": 7, "requester": "2", "payer": "testuser", "amount": 40, "memo": "за пиццу",
+				"status": "accepted", "createdAt": "2025-02-01T12:00:00Z", "expiresAt": "2025-02-04T12:00:00Z"}`,
+		},
+		{
+			name:       "Not found",
+			err:        shop.ErrPaymentRequestNotFound,
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"errors": "Запрос на перевод не найден."}`,
+		},
+		{
+			name:       "Already resolved",
+			err:        shop.ErrPaymentRequestNotPending,
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"errors": "Запрос на перевод уже закрыт."}`,
+		},
+		{
+			name:       "Expired",
+			err:        shop.ErrPaymentRequestExpired,
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"errors": "Срок действия запроса на перевод истёк."}`,
+		},
+		{
+			name:       "Insufficient funds",
+			err:        shop.ErrInsufficientFunds,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"errors": "Недостаточно средств."}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("AcceptPaymentRequest", "testuser", 7).Return(tt.result, tt.err)
+			handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+			req := httptest.NewRequest(http.MethodPost, "/api/paymentRequests/7/accept", nil)
+			req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+			rr := httptest.NewRecorder()
+
+			handlers.AcceptPaymentRequest(rr, req, 7)
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+			require.JSONEq(t, tt.wantBody, rr.Body.String())
+		})
+	}
+}
+
+func TestCreatePaymentRequestHandler(t *testing.T) {
+	mockService := new(MockService)
+	input := &shop.PaymentRequestInput{FromUser: "2", Amount: 40, Memo: "за пиццу"}
+	created := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	mockService.On("RequestPayment", "testuser", input).Return(&storage.PaymentRequest{
+		ID: 1, Requester: "testuser", Payer: "2", Amount: 40, Memo: "за пиццу",
+		Status: storage.PaymentRequestPending, CreatedAt: created, ExpiresAt: created.Add(72 * time.Hour),
+	}, nil)
+	handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/paymentRequests",
+		strings.NewReader(`{"fromUser": "2", "amount": 40, "memo": "за пиццу"}`))
+	req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+	rr := httptest.NewRecorder()
+
+	handlers.CreatePaymentRequest(rr, req)
+
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.JSONEq(t, `{"id": 1, "requester": "testuser", "payer": "2", "amount": 40, "memo": "за пиццу",
+		"status": "pending", "createdAt": "2025-02-01T12:00:00Z", "expiresAt": "2025-02-04T12:00:00Z"}`, rr.Body.String())
+}
+
+func TestCreateScheduledTransferHandler(t *testing.T) {
+	created := time.Date(2025, 2, 5, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		body       string
+		in         *shop.ScheduledTransferInput
+		result     *storage.ScheduledTransfer
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "Weekly bonus",
+			body: `{"toUser": "bob", "amount": 50, "category": "thanks", "schedule": "0 9 * * 1"}`,
+			in: &shop.ScheduledTransferInput{
+				SendCoinRequest: storage.SendCoinRequest{ToUser: "bob", Amount: 50, Category: "thanks"},
+				Schedule:        "0 9 * * 1",
+			},
+			result: &storage.ScheduledTransfer{
+				ID: 3, Sender: "testuser", Recipient: "bob", Amount: 50, Category: "thanks", Schedule: "0 9 * * 1",
+				Status: storage.ScheduledTransferActive, NextRunAt: time.Date(2025, 2, 10, 9, 0, 0, 0, time.UTC), CreatedAt: created,
+			},
+			wantStatus: http.StatusCreated,
+			wantBody: `{"id": 3, "sender": "testuser", "toUser": "bob", "amount": 50, "category": "thanks",
+				"schedule": "0 9 * * 1", "status": "active", "nextRunAt": "2025-02-10T09:00:00Z", "runs": 0,
+				"createdAt": "2025-02-05T10:30:00Z"}`,
+		},
+		{
+			name: "Invalid schedule",
+			body: `{"toUser": "bob", "amount": 50, "schedule": "sometimes"}`,
+			in: &shop.ScheduledTransferInput{
+				SendCoinRequest: storage.SendCoinRequest{ToUser: "bob", Amount: 50},
+				Schedule:        "sometimes",
+			},
+			err: &shop.ValidationError{Fields: []shop.FieldError{
+				{Field: "schedule", Message: "некорректное расписание"},
+			}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"errors": "Неверный запрос.", "fields": [{"field": "schedule", "message": "некорректное расписание"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("ScheduleTransfer", "testuser", tt.in).Return(tt.result, tt.err)
+			handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+			req := httptest.NewRequest(http.MethodPost, "/api/scheduledTransfers", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+			rr := httptest.NewRecorder()
+
+			handlers.CreateScheduledTransfer(rr, req)
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+			require.JSONEq(t, tt.wantBody, rr.Body.String())
+		})
+	}
+}
+
+func TestCancelScheduledTransferHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "Not found", err: shop.ErrScheduledTransferNotFound, wantStatus: http.StatusNotFound},
+		{name: "Already cancelled", err: shop.ErrScheduledTransferNotActive, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("CancelScheduledTransfer", "testuser", 3).Return((*storage.ScheduledTransfer)(nil), tt.err)
+			handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+			req := httptest.NewRequest(http.MethodPost, "/api/scheduledTransfers/3/cancel", nil)
+			req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+			rr := httptest.NewRecorder()
+
+			handlers.CancelScheduledTransfer(rr, req, 3)
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestBuyItemHandler_PromoCode(t *testing.T) {
+	promoCode := "HOODY20"
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "Applied", wantStatus: http.StatusOK},
+		{name: "Used up", err: shop.ErrPromoCodeUsedUp, wantStatus: http.StatusBadRequest},
+		{name: "Not applicable", err: shop.ErrPromoCodeNotApplicable, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("Purchase", "testuser", "hoody", promoCode).Return(tt.err)
+			handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+			req := httptest.NewRequest(http.MethodGet, "/api/buy/hoody?promoCode="+promoCode, nil)
+			req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+			rr := httptest.NewRecorder()
+
+			handlers.BuyItem(rr, req, "hoody", api.BuyItemParams{PromoCode: &promoCode})
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestCreatePromoCodeHandler(t *testing.T) {
+	in := &shop.PromoCodeInput{Code: "SALE10", Kind: "percent", Value: 10, MaxUses: 100}
+	tests := []struct {
+		name       string
+		result     *storage.PromoCode
+		err        error
+		wantStatus int
+	}{
+		{name: "Created", result: &storage.PromoCode{Code: "SALE10", Kind: "percent", Value: 10, MaxUses: 100}, wantStatus: http.StatusCreated},
+		{name: "Not an admin", err: shop.ErrForbidden, wantStatus: http.StatusForbidden},
+		{name: "Duplicate code", err: shop.ErrPromoCodeExists, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("CreatePromoCode", "testuser", in).Return(tt.result, tt.err)
+			handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+			body := `{"code":"SALE10","kind":"percent","value":10,"maxUses":100}`
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/promoCodes", strings.NewReader(body))
+			req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+			rr := httptest.NewRecorder()
+
+			handlers.CreatePromoCode(rr, req)
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestListItemsHandler(t *testing.T) {
+	saleEndsAt := time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC)
+	mockService := new(MockService)
+	mockService.On("Catalog").Return([]storage.CatalogItem{
+		{Name: "cup", Price: 20, RegularPrice: 20},
+		{Name: "hoody", Price: 150, RegularPrice: 300, SaleEndsAt: &saleEndsAt},
+	}, nil)
+	handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/items", nil)
+	req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+	rr := httptest.NewRecorder()
+
+	handlers.ListItems(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `[{"name":"cup","price":20,"regularPrice":20},
+		{"name":"hoody","price":150,"regularPrice":300,"saleEndsAt":"2026-10-02T00:00:00Z"}]`, rr.Body.String())
+}
+
+func TestCancelItemPriceHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "Not an admin", err: shop.ErrForbidden, wantStatus: http.StatusForbidden},
+		{name: "Not found", err: shop.ErrItemPriceNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("CancelItemPrice", "testuser", 4).Return((*storage.ItemPrice)(nil), tt.err)
+			handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/prices/4/cancel", nil)
+			req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+			rr := httptest.NewRecorder()
+
+			handlers.CancelItemPrice(rr, req, 4)
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestLeaderboardHandler(t *testing.T) {
+	since := time.Date(2026, 9, 24, 12, 0, 0, 0, time.UTC)
+	limit, period := 2, "week"
+	tests := []struct {
+		name       string
+		board      string
+		result     *storage.Leaderboard
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:  "Ranked entries",
+			board: storage.BoardSenders,
+			result: &storage.Leaderboard{Board: storage.BoardSenders, Period: "week", Since: &since, Entries: []storage.LeaderboardEntry{
+				{Rank: 1, Username: "alice", Value: 50}, {Rank: 1, Username: "bob", Value: 50},
+			}},
+			wantStatus: http.StatusOK,
+			wantBody: `{"board":"senders","period":"week","since":"2026-09-24T12:00:00Z",
+				"entries":[{"rank":1,"username":"alice","value":50},{"rank":1,"username":"bob","value":50}]}`,
+		},
+		{
+			name:       "Empty board",
+			board:      storage.BoardCollectors,
+			result:     &storage.Leaderboard{Board: storage.BoardCollectors, Period: "week"},
+			wantStatus: http.StatusOK,
+			wantBody:   `{"board":"collectors","period":"week","entries":[]}`,
+		},
+		{name: "Unknown board", board: "richest", result: nil, err: shop.ErrLeaderboardNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("Leaderboard", tt.board, period, limit).Return(tt.result, tt.err)
+			handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+			req := httptest.NewRequest(http.MethodGet, "/api/leaderboards/"+tt.board+"?period=week&limit=2", nil)
+			req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+			rr := httptest.NewRecorder()
+
+			handlers.Leaderboard(rr, req, tt.board, api.LeaderboardParams{Period: &period, Limit: &limit})
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantBody != "" {
+				require.JSONEq(t, tt.wantBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestLeaderboardOptOutHandler(t *testing.T) {
+	mockService := new(MockService)
+	mockService.On("SetLeaderboardOptOut", "testuser", true).Return(nil)
+	mockService.On("SetLeaderboardOptOut", "testuser", false).Return(nil)
+	handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+	for _, call := range []struct {
+		path    string
+		handler http.HandlerFunc
+	}{
+		{"/api/leaderboards/optOut", handlers.LeaderboardOptOut},
+		{"/api/leaderboards/optIn", handlers.LeaderboardOptIn},
+	} {
+		req := httptest.NewRequest(http.MethodPost, call.path, nil)
+		req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+		rr := httptest.NewRecorder()
+
+		call.handler(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code, call.path)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateGroupHandler(t *testing.T) {
+	in := &shop.GroupInput{Name: "team", ApprovalsRequired: 2}
+	tests := []struct {
+		name       string
+		result     *storage.Group
+		err        error
+		wantStatus int
+	}{
+		{name: "Created", result: &storage.Group{Name: "team", ApprovalsRequired: 2}, wantStatus: http.StatusCreated},
+		{name: "Name taken", err: shop.ErrGroupExists, wantStatus: http.StatusConflict},
+		{name: "Invalid", err: &shop.ValidationError{}, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("CreateGroup", "testuser", in).Return(tt.result, tt.err)
+			handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+			req := httptest.NewRequest(http.MethodPost, "/api/groups", strings.NewReader(`{"name":"team","approvalsRequired":2}`))
+			req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+			rr := httptest.NewRecorder()
+
+			handlers.CreateGroup(rr, req)
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestApproveGroupPurchaseHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     *storage.GroupPurchase
+		err        error
+		wantStatus int
+	}{
+		{name: "Completed", result: &storage.GroupPurchase{ID: 3, Group: "team", Item: "cup", Status: storage.GroupPurchaseCompleted, Price: 20}, wantStatus: http.StatusOK},
+		{name: "Not a member", err: shop.ErrGroupNotFound, wantStatus: http.StatusNotFound},
+		{name: "Already approved", err: shop.ErrGroupPurchaseAlreadyApproved, wantStatus: http.StatusConflict},
+		{name: "Insufficient funds", err: shop.ErrInsufficientFunds, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("ApproveGroupPurchase", "testuser", "team", 3).Return(tt.result, tt.err)
+			handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+			req := httptest.NewRequest(http.MethodPost, "/api/groups/team/purchases/3/approve", nil)
+			req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+			rr := httptest.NewRecorder()
+
+			handlers.ApproveGroupPurchase(rr, req, "team", 3)
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestApprovePendingTransferHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		result     *storage.PendingTransfer
+		err        error
+		wantStatus int
+	}{
+		{name: "Approved", result: &storage.PendingTransfer{ID: 4, Sender: "alice", Recipient: "bob", Amount: 600, Status: storage.PendingTransferApproved}, wantStatus: http.StatusOK},
+		{name: "Not an approver", err: shop.ErrForbidden, wantStatus: http.StatusForbidden},
+		{name: "Not found", err: shop.ErrPendingTransferNotFound, wantStatus: http.StatusNotFound},
+		{name: "Already resolved", err: shop.ErrPendingTransferNotPending, wantStatus: http.StatusConflict},
+		{name: "Expired", err: shop.ErrPendingTransferExpired, wantStatus: http.StatusConflict},
+		{name: "Limit exceeded", err: &shop.LimitExceededError{Limit: storage.LimitDailyReceived, Max: 500}, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("ApprovePendingTransfer", "testuser", 4).Return(tt.result, tt.err)
+			handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+			req := httptest.NewRequest(http.MethodPost, "/api/transfers/pending/4/approve", nil)
+			req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+			rr := httptest.NewRecorder()
+
+			handlers.ApprovePendingTransfer(rr, req, 4)
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+}
+
+func TestReverseTransactionHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantInput  *shop.ReversalInput
+		result     *storage.TransferReversal
+		err        error
+		wantStatus int
+	}{
+		{name: "Reversed", body: `{"reason":"wrong recipient","policy":"negative"}`,
+			wantInput:  &shop.ReversalInput{TransactionID: 7, Policy: storage.ReversalNegative, Reason: "wrong recipient"},
+			result:     &storage.TransferReversal{TransactionID: 7, Amount: 300, Reversed: 300, Policy: storage.ReversalNegative},
+			wantStatus: http.StatusOK},
+		{name: "Invalid body", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "Not an admin", body: `{"reason":"x"}`, wantInput: &shop.ReversalInput{TransactionID: 7, Reason: "x"},
+			err: shop.ErrForbidden, wantStatus: http.StatusForbidden},
+		{name: "Not found", body: `{"reason":"x"}`, wantInput: &shop.ReversalInput{TransactionID: 7, Reason: "x"},
+			err: shop.ErrTransactionNotFound, wantStatus: http.StatusNotFound},
+		{name: "Already reversed", body: `{"reason":"x"}`, wantInput: &shop.ReversalInput{TransactionID: 7, Reason: "x"},
+			err: shop.ErrTransactionAlreadyReversed, wantStatus: http.StatusConflict},
+		{name: "Nothing to reverse", body: `{"reason":"x"}`, wantInput: &shop.ReversalInput{TransactionID: 7, Reason: "x"},
+			err: shop.ErrNothingToReverse, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			if tt.wantInput != nil {
+				mockService.On("ReverseTransfer", "testuser", tt.wantInput).Return(tt.result, tt.err)
+			}
+			handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/transactions/7/reverse", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+			rr := httptest.NewRecorder()
+
+			handlers.ReverseTransaction(rr, req, 7)
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestListAuditLogHandler(t *testing.T) {
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	actor, limit := "alice", 50
+	params := api.ListAuditLogParams{Actor: &actor, From: &from, Limit: &limit}
+	wantFilter := storage.AuditFilter{Actor: "alice", From: &from, Limit: 50}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "Listed", wantStatus: http.StatusOK},
+		{name: "Not an admin", err: shop.ErrForbidden, wantStatus: http.StatusForbidden},
+		{name: "Invalid filter", err: &shop.ValidationError{Fields: []shop.FieldError{{Field: "limit", Message: "x"}}},
+			wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("AuditLog", "testuser", wantFilter).Return([]storage.AuditEntry{{ID: 1, Actor: "alice"}}, tt.err)
+			handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/audit", nil)
+			req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+			rr := httptest.NewRecorder()
+
+			handlers.ListAuditLog(rr, req, params)
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestExportAuditLogHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		entries    []storage.AuditEntry
+		err        error
+		wantStatus int
+		wantLines  int
+	}{
+		{name: "Exported", entries: []storage.AuditEntry{{ID: 1}, {ID: 2}}, wantStatus: http.StatusOK, wantLines: 2},
+		{name: "Empty log", wantStatus: http.StatusOK},
+		{name: "Not an admin", err: shop.ErrForbidden, wantStatus: http.StatusForbidden},
+		{name: "Failed after the first batch", entries: []storage.AuditEntry{{ID: 1}}, err: shop.ErrInternalServer,
+			wantStatus: http.StatusOK, wantLines: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("ExportAuditLog", "testuser", storage.AuditFilter{}).Return(tt.entries, tt.err)
+			handlers := urls.NewHandlers(nil, mockService, slog.New(slog.NewJSONHandler(io.Discard, nil)), "")
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/audit/export", nil)
+			req = req.WithContext(context.WithValue(req.Context(), "username", "testuser"))
+			rr := httptest.NewRecorder()
+
+			handlers.ExportAuditLog(rr, req, api.ExportAuditLogParams{})
+
+			require.Equal(t, tt.wantStatus, rr.Code)
+			if tt.wantStatus == http.StatusOK {
+				require.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+				require.Equal(t, tt.wantLines, strings.Count(rr.Body.String(), "\n"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
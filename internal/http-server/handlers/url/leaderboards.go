@@ -0,0 +1,65 @@
+package urls
+
+import (
+	"avito-shop/internal/http-server/api"
+	"avito-shop/internal/service/shop"
+	"avito-shop/internal/service/shop/storage"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+func (h *Handlers) Leaderboard(w http.ResponseWriter, r *http.Request, board string, params api.LeaderboardParams) {
+	var (
+		period string
+		limit  int
+	)
+	if params.Period != nil {
+		period = *params.Period
+	}
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+
+	lb, err := h.service.Leaderboard(r.Context(), board, period, limit)
+	if err != nil {
+		var ve *shop.ValidationError
+		switch {
+		case errors.As(err, &ve):
+			h.log.WarnContext(r.Context(), "Invalid leaderboard request", slog.String("error", ve.Error()))
+			h.writeValidationError(w, ve)
+		case errors.Is(err, shop.ErrLeaderboardNotFound):
+			h.writeErrorResponse(w, "Таблица лидеров не найдена.", http.StatusNotFound)
+		default:
+			h.log.ErrorContext(r.Context(), "Failed to load leaderboard", slog.String("error", err.Error()))
+			h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
+		}
+		return
+	}
+	if lb.Entries == nil {
+		lb.Entries = []storage.LeaderboardEntry{}
+	}
+
+	h.writeJSON(r, w, http.StatusOK, lb)
+}
+
+func (h *Handlers) LeaderboardOptIn(w http.ResponseWriter, r *http.Request) {
+	h.setLeaderboardOptOut(w, r, false)
+}
+
+func (h *Handlers) LeaderboardOptOut(w http.ResponseWriter, r *http.Request) {
+	h.setLeaderboardOptOut(w, r, true)
+}
+
+func (h *Handlers) setLeaderboardOptOut(w http.ResponseWriter, r *http.Request, optOut bool) {
+	username := r.Context().Value("username").(string)
+
+	if err := h.service.SetLeaderboardOptOut(r.Context(), username, optOut); err != nil {
+		h.log.ErrorContext(r.Context(), "Failed to update leaderboard participation", slog.String("error", err.Error()))
+		h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
+		return
+	}
+
+	h.log.InfoContext(r.Context(), "Leaderboard participation updated", slog.String("username", username), slog.Bool("opt_out", optOut))
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,71 @@
+package urls
+
+import (
+	"avito-shop/internal/service/shop"
+	"avito-shop/internal/service/shop/storage"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+func (h *Handlers) ListPendingTransfers(w http.ResponseWriter, r *http.Request) {
+	username := r.Context().Value("username").(string)
+
+	transfers, err := h.service.ListPendingTransfers(r.Context(), username)
+	if err != nil {
+		h.writePendingTransferError(r, w, err)
+		return
+	}
+	if transfers == nil {
+		transfers = []storage.PendingTransfer{}
+	}
+
+	h.writeJSON(r, w, http.StatusOK, transfers)
+}
+
+func (h *Handlers) ApprovePendingTransfer(w http.ResponseWriter, r *http.Request, id int) {
+	h.resolvePendingTransfer(w, r, id, h.service.ApprovePendingTransfer, "Pending transfer approved")
+}
+
+func (h *Handlers) RejectPendingTransfer(w http.ResponseWriter, r *http.Request, id int) {
+	h.resolvePendingTransfer(w, r, id, h.service.RejectPendingTransfer, "Pending transfer rejected")
+}
+
+// resolvePendingTransfer одобряет или отклоняет удержанный перевод и отвечает его итоговым состоянием.
+func (h *Handlers) resolvePendingTransfer(w http.ResponseWriter, r *http.Request, id int,
+	resolve func(ctx context.Context, approver string, id int) (*storage.PendingTransfer, error), msg string) {
+	username := r.Context().Value("username").(string)
+
+	pt, err := resolve(r.Context(), username, id)
+	if err != nil {
+		h.writePendingTransferError(r, w, err)
+		return
+	}
+
+	h.log.InfoContext(r.Context(), msg, slog.Int("id", pt.ID), slog.String("by", username))
+	h.writeJSON(r, w, http.StatusOK, pt)
+}
+
+// writePendingTransferError переводит ошибки переводов на одобрении в статусы ответа.
+func (h *Handlers) writePendingTransferError(r *http.Request, w http.ResponseWriter, err error) {
+	var le *shop.LimitExceededError
+	switch {
+	case errors.As(err, &le):
+		h.writeLimitError(r, w, le)
+	case errors.Is(err, shop.ErrForbidden):
+		h.log.WarnContext(r.Context(), "Pending transfer operation forbidden")
+		h.writeErrorResponse(w, "Недостаточно прав.", http.StatusForbidden)
+	case errors.Is(err, shop.ErrUserNotFound):
+		h.writeErrorResponse(w, "Пользователь не найден.", http.StatusBadRequest)
+	case errors.Is(err, shop.ErrPendingTransferNotFound):
+		h.writeErrorResponse(w, "Перевод не найден.", http.StatusNotFound)
+	case errors.Is(err, shop.ErrPendingTransferNotPending):
+		h.writeErrorResponse(w, "Перевод уже одобрен, отклонён или истёк.", http.StatusConflict)
+	case errors.Is(err, shop.ErrPendingTransferExpired):
+		h.writeErrorResponse(w, "Срок одобрения перевода истёк.", http.StatusConflict)
+	default:
+		h.log.ErrorContext(r.Context(), "Failed to process pending transfer", slog.String("error", err.Error()))
+		h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,140 @@
+package urls
+
+import (
+	"avito-shop/internal/http-server/api"
+	"avito-shop/internal/service/shop"
+	"avito-shop/internal/service/shop/storage"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+func (h *Handlers) QuoteItem(w http.ResponseWriter, r *http.Request, item string, params api.QuoteItemParams) {
+	username := r.Context().Value("username").(string)
+
+	var promoCode string
+	if params.PromoCode != nil {
+		promoCode = *params.PromoCode
+	}
+
+	quote, err := h.service.QuotePurchase(r.Context(), username, item, promoCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, shop.ErrItemNotFound):
+			h.log.WarnContext(r.Context(), "Item not found", slog.String("item", item))
+			h.writeErrorResponse(w, fmt.Sprintf("Предмет '%s' не найден.", item), http.StatusBadRequest)
+		case isPromoCodeError(err):
+			h.writePromoCodeError(r, w, promoCode, err)
+		default:
+			h.log.ErrorContext(r.Context(), "Failed to quote purchase", slog.String("error", err.Error()))
+			h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.writeJSON(r, w, http.StatusOK, quote)
+}
+
+// isPromoCodeError сообщает, что промокод нельзя применить к покупке.
+func isPromoCodeError(err error) bool {
+	for _, target := range []error{shop.ErrPromoCodeNotFound, shop.ErrPromoCodeInactive, shop.ErrPromoCodeNotApplicable,
+		shop.ErrPromoCodeUsedUp, shop.ErrPromoCodeAlreadyUsed} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// writePromoCodeError отвечает 400 с причиной, по которой промокод не применяется.
+func (h *Handlers) writePromoCodeError(r *http.Request, w http.ResponseWriter, code string, err error) {
+	h.log.WarnContext(r.Context(), "Promo code rejected", slog.String("promo_code", code), slog.String("error", err.Error()))
+	h.writeErrorResponse(w, fmt.Sprintf("Промокод '%s' не применён: %s.", code, err.Error()), http.StatusBadRequest)
+}
+
+func (h *Handlers) ListPromoCodes(w http.ResponseWriter, r *http.Request) {
+	username := r.Context().Value("username").(string)
+
+	codes, err := h.service.ListPromoCodes(r.Context(), username)
+	if err != nil {
+		h.writePromoAdminError(r, w, err)
+		return
+	}
+	if codes == nil {
+		codes = []storage.PromoCode{}
+	}
+
+	h.writeJSON(r, w, http.StatusOK, codes)
+}
+
+func (h *Handlers) CreatePromoCode(w http.ResponseWriter, r *http.Request) {
+	username := r.Context().Value("username").(string)
+
+	var input api.CreatePromoCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.log.WarnContext(r.Context(), "Invalid request body")
+		h.writeErrorResponse(w, "Неверный запрос.", http.StatusBadRequest)
+		return
+	}
+
+	in := &shop.PromoCodeInput{
+		Code:     input.Code,
+		Kind:     input.Kind,
+		Value:    input.Value,
+		StartsAt: input.StartsAt,
+		EndsAt:   input.EndsAt,
+	}
+	if input.Items != nil {
+		in.Items = *input.Items
+	}
+	if input.MaxUses != nil {
+		in.MaxUses = *input.MaxUses
+	}
+	if input.MaxUsesPerUser != nil {
+		in.MaxUsesPerUser = *input.MaxUsesPerUser
+	}
+
+	pc, err := h.service.CreatePromoCode(r.Context(), username, in)
+	if err != nil {
+		h.writePromoAdminError(r, w, err)
+		return
+	}
+
+	h.log.InfoContext(r.Context(), "Promo code created", slog.String("promo_code", pc.Code), slog.String("admin", username))
+	h.writeJSON(r, w, http.StatusCreated, pc)
+}
+
+func (h *Handlers) DisablePromoCode(w http.ResponseWriter, r *http.Request, code string) {
+	username := r.Context().Value("username").(string)
+
+	pc, err := h.service.DisablePromoCode(r.Context(), username, code)
+	if err != nil {
+		h.writePromoAdminError(r, w, err)
+		return
+	}
+
+	h.log.InfoContext(r.Context(), "Promo code disabled", slog.String("promo_code", pc.Code), slog.String("admin", username))
+	h.writeJSON(r, w, http.StatusOK, pc)
+}
+
+// writePromoAdminError переводит ошибки управления промокодами в статусы ответа.
+func (h *Handlers) writePromoAdminError(r *http.Request, w http.ResponseWriter, err error) {
+	var ve *shop.ValidationError
+	switch {
+	case errors.As(err, &ve):
+		h.log.WarnContext(r.Context(), "Invalid promo code", slog.String("error", ve.Error()))
+		h.writeValidationError(w, ve)
+	case errors.Is(err, shop.ErrForbidden):
+		h.log.WarnContext(r.Context(), "Promo code management forbidden")
+		h.writeErrorResponse(w, "Недостаточно прав.", http.StatusForbidden)
+	case errors.Is(err, shop.ErrPromoCodeExists):
+		h.writeErrorResponse(w, "Промокод уже существует.", http.StatusConflict)
+	case errors.Is(err, shop.ErrPromoCodeNotFound):
+		h.writeErrorResponse(w, "Промокод не найден.", http.StatusNotFound)
+	default:
+		h.log.ErrorContext(r.Context(), "Failed to manage promo codes", slog.String("error", err.Error()))
+		h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
+	}
+}
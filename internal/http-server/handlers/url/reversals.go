@@ -0,0 +1,73 @@
+package urls
+
+import (
+	"avito-shop/internal/http-server/api"
+	"avito-shop/internal/service/shop"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+func (h *Handlers) ListUserTransactions(w http.ResponseWriter, r *http.Request, params api.ListUserTransactionsParams) {
+	username := r.Context().Value("username").(string)
+
+	txs, err := h.service.ListUserTransactions(r.Context(), username, params.Username)
+	if err != nil {
+		h.writeReversalError(r, w, err)
+		return
+	}
+
+	h.writeJSON(r, w, http.StatusOK, txs)
+}
+
+func (h *Handlers) ReverseTransaction(w http.ResponseWriter, r *http.Request, id int) {
+	username := r.Context().Value("username").(string)
+
+	var input api.ReverseTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.log.WarnContext(r.Context(), "Invalid request body")
+		h.writeErrorResponse(w, "Неверный запрос.", http.StatusBadRequest)
+		return
+	}
+
+	in := &shop.ReversalInput{TransactionID: id, Reason: input.Reason}
+	if input.Policy != nil {
+		in.Policy = *input.Policy
+	}
+	rv, err := h.service.ReverseTransfer(r.Context(), username, in)
+	if err != nil {
+		h.writeReversalError(r, w, err)
+		return
+	}
+
+	h.log.InfoContext(r.Context(), "Transfer reversed", slog.Int("transaction_id", id), slog.Int("reversed", rv.Reversed),
+		slog.String("policy", rv.Policy), slog.String("admin", username))
+	h.writeJSON(r, w, http.StatusOK, rv)
+}
+
+// writeReversalError переводит ошибки отмены переводов в статусы ответа.
+func (h *Handlers) writeReversalError(r *http.Request, w http.ResponseWriter, err error) {
+	var ve *shop.ValidationError
+	switch {
+	case errors.As(err, &ve):
+		h.log.WarnContext(r.Context(), "Invalid reversal", slog.String("error", ve.Error()))
+		h.writeValidationError(w, ve)
+	case errors.Is(err, shop.ErrForbidden):
+		h.log.WarnContext(r.Context(), "Transfer reversal forbidden")
+		h.writeErrorResponse(w, "Недостаточно прав.", http.StatusForbidden)
+	case errors.Is(err, shop.ErrUserNotFound):
+		h.writeErrorResponse(w, "Пользователь не найден.", http.StatusNotFound)
+	case errors.Is(err, shop.ErrTransactionNotFound):
+		h.writeErrorResponse(w, "Перевод не найден.", http.StatusNotFound)
+	case errors.Is(err, shop.ErrTransactionAlreadyReversed):
+		h.writeErrorResponse(w, "Перевод уже отменён.", http.StatusConflict)
+	case errors.Is(err, shop.ErrTransactionNotReversible):
+		h.writeErrorResponse(w, "Запись нельзя отменить: это не перевод между пользователями.", http.StatusConflict)
+	case errors.Is(err, shop.ErrNothingToReverse):
+		h.writeErrorResponse(w, "У получателя не осталось монет; чтобы вернуть всю сумму, укажите политику negative.", http.StatusConflict)
+	default:
+		h.log.ErrorContext(r.Context(), "Failed to reverse transfer", slog.String("error", err.Error()))
+		h.writeErrorResponse(w, "Внутренняя ошибка сервера.", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,47 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mwMetrics "avito-shop/internal/http-server/middleware/metrics"
+	"avito-shop/internal/metrics"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsMiddleware_RoutePattern(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(mwMetrics.New())
+	r.Get("/api/buy/{item}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	counter := metrics.HTTPRequestsTotal.WithLabelValues(http.MethodGet, "/api/buy/{item}", "400")
+	before := testutil.ToFloat64(counter)
+
+	for _, item := range []string{"cup", "pen"} {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/buy/"+item, nil))
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	}
+
+	require.Equal(t, before+2, testutil.ToFloat64(counter))
+}
+
+func TestMetricsMiddleware_Unmatched(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(mwMetrics.New())
+	r.Get("/api/info", func(w http.ResponseWriter, r *http.Request) {})
+
+	counter := metrics.HTTPRequestsTotal.WithLabelValues(http.MethodGet, "unmatched", "404")
+	before := testutil.ToFloat64(counter)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/unknown", nil))
+
+	require.Equal(t, before+1, testutil.ToFloat64(counter))
+}
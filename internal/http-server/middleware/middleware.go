@@ -2,10 +2,14 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 
+	"avito-shop/internal/http-server/api"
+	"avito-shop/internal/metrics"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -14,13 +18,13 @@ func JWTMiddleware(secretKey string) func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				http.Error(w, "Неавторизован.", http.StatusUnauthorized)
+				unauthorized(w)
 				return
 			}
 
 			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 			if tokenString == authHeader {
-				http.Error(w, "Неавторизован.", http.StatusUnauthorized)
+				unauthorized(w)
 				return
 			}
 
@@ -32,20 +36,20 @@ func JWTMiddleware(secretKey string) func(next http.Handler) http.Handler {
 			})
 
 			if err != nil || !token.Valid {
-				http.Error(w, "Неавторизован.", http.StatusUnauthorized)
+				unauthorized(w)
 				return
 			}
 
 			claims, ok := token.Claims.(jwt.MapClaims)
 			if !ok {
-				http.Error(w, "Неавторизован.", http.StatusUnauthorized)
+				unauthorized(w)
 				return
 			}
 
 			// Добавляем username в контекст запроса
 			username, ok := claims["username"].(string)
 			if !ok {
-				http.Error(w, "Неавторизован.", http.StatusUnauthorized)
+				unauthorized(w)
 				return
 			}
 
@@ -54,3 +58,25 @@ func JWTMiddleware(secretKey string) func(next http.Handler) http.Handler {
 		})
 	}
 }
+
+func unauthorized(w http.ResponseWriter) {
+	metrics.AuthFailuresTotal.WithLabelValues(metrics.AuthReasonInvalidToken).Inc()
+	message := "Неавторизован."
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnauthorized)
+	json.NewEncoder(w).Encode(api.ErrorResponse{Errors: &message})
+}
+
+// SecuredOnly применяет mw только к операциям, для которых спецификация требует BearerAuth.
+func SecuredOnly(mw func(next http.Handler) http.Handler) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		secured := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := r.Context().Value(api.BearerAuthScopes).([]string); ok {
+				secured.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+func init() {
+	// Выгрузка журнала аудита отдаётся построчным JSON; в спецификации это строка, и проверяется только статус.
+	openapi3filter.RegisterBodyDecoder("application/x-ndjson", openapi3filter.FileBodyDecoder)
+}
+
+// Validator проверяет запросы и ответы на соответствие спецификации api/schema.yaml.
+// Маршруты, которых нет в спецификации, пропускаются без проверки.
+type Validator struct {
+	router routers.Router
+	log    *slog.Logger
+}
+
+func NewValidator(swagger *openapi3.T, log *slog.Logger) (*Validator, error) {
+	const op = "middleware.openapi.NewValidator"
+
+	// Проверяем только пути и тела, а не хост из servers, иначе запросы через прокси/контейнер не найдут маршрут.
+	swagger.Servers = nil
+	router, err := gorillamux.NewRouter(swagger)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", op, err)
+	}
+
+	return &Validator{
+		router: router,
+		log:    log.With(slog.String("component", "middleware/openapi")),
+	}, nil
+}
+
+// Requests отклоняет запросы, не соответствующие спецификации, с кодом 400.
+// Аутентификация здесь не проверяется — этим занимается JWT middleware.
+func (v *Validator) Requests() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := v.router.FindRoute(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+					MultiError:         true,
+				},
+			}
+			if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+				v.log.WarnContext(r.Context(), "Request does not match API schema", slog.String("error", err.Error()))
+				writeError(w, "Неверный запрос.", http.StatusBadRequest)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// Responses проверяет ответы на соответствие спецификации и логирует расхождения.
+// Ответ клиенту при этом не меняется.
+func (v *Validator) Responses() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := v.router.FindRoute(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rec := &recorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+
+			input := &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: &openapi3filter.RequestValidationInput{
+					Request:    r,
+					PathParams: pathParams,
+					Route:      route,
+				},
+				Status: rec.status,
+				Header: w.Header(),
+				Body:   io.NopCloser(bytes.NewReader(rec.body.Bytes())),
+				Options: &openapi3filter.Options{
+					IncludeResponseStatus: true,
+					MultiError:            true,
+				},
+			}
+			if err := openapi3filter.ValidateResponse(r.Context(), input); err != nil {
+				v.log.WarnContext(r.Context(), "Response does not match API schema",
+					slog.Int("status", rec.status),
+					slog.String("error", err.Error()),
+				)
+			}
+
+			w.WriteHeader(rec.status)
+			w.Write(rec.body.Bytes())
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// recorder буферизует ответ, чтобы проверить его до отправки клиенту.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+func writeError(w http.ResponseWriter, errorMessage string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"errors": errorMessage})
+}
@@ -0,0 +1,104 @@
+package openapi_test
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"avito-shop/internal/http-server/api"
+	mwOpenAPI "avito-shop/internal/http-server/middleware/openapi"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouter(t *testing.T, logs io.Writer, handler http.HandlerFunc) http.Handler {
+	swagger, err := api.GetSwagger()
+	require.NoError(t, err)
+
+	validator, err := mwOpenAPI.NewValidator(swagger, slog.New(slog.NewTextHandler(logs, nil)))
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(validator.Responses())
+	r.Use(validator.Requests())
+	r.Post("/api/sendCoin", handler)
+	r.Get("/api/info", handler)
+	r.Get("/unknown", handler)
+	return r
+}
+
+func TestRequests_TableDriven(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		expectedCode int
+	}{
+		{
+			name:         "Valid request",
+			method:       http.MethodPost,
+			path:         "/api/sendCoin",
+			body:         `{"toUser": "user2", "amount": 10}`,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "Missing required field",
+			method:       http.MethodPost,
+			path:         "/api/sendCoin",
+			body:         `{"toUser": "user2"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Wrong field type",
+			method:       http.MethodPost,
+			path:         "/api/sendCoin",
+			body:         `{"toUser": "user2", "amount": "ten"}`,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Route outside of spec is not validated",
+			method:       http.MethodGet,
+			path:         "/unknown",
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRouter(t, io.Discard, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rr := httptest.NewRecorder()
+			r.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.expectedCode, rr.Code)
+			if tt.expectedCode == http.StatusBadRequest {
+				require.JSONEq(t, `{"errors": "Неверный запрос."}`, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestResponses_LogsMismatchWithoutChangingResponse(t *testing.T) {
+	var logs bytes.Buffer
+	r := newRouter(t, &logs, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"coins": "many"}`))
+	})
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/info", nil))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"coins": "many"}`, rr.Body.String())
+	require.Contains(t, logs.String(), "Response does not match API schema")
+}
@@ -0,0 +1,28 @@
+package requestinfo
+
+import (
+	"net"
+	"net/http"
+
+	"avito-shop/internal/service/shop"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// New передаёт сервису идентификатор запроса и адрес клиента для журнала аудита.
+// Идентификатор ставит middleware.RequestID, поэтому New подключается после него.
+// Адрес берётся из соединения, а не из X-Forwarded-For, который клиент может подделать.
+func New() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ip, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				ip = r.RemoteAddr
+			}
+			ctx := shop.WithRequestInfo(r.Context(), shop.RequestInfo{ID: middleware.GetReqID(r.Context()), IP: ip})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
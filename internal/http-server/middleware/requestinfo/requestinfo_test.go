@@ -0,0 +1,34 @@
+package requestinfo_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mwRequestInfo "avito-shop/internal/http-server/middleware/requestinfo"
+	"avito-shop/internal/service/shop"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestInfoMiddleware(t *testing.T) {
+	var info shop.RequestInfo
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(mwRequestInfo.New())
+	r.Get("/api/info", func(w http.ResponseWriter, r *http.Request) {
+		info = shop.RequestInfoFrom(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+	req.RemoteAddr = "10.0.0.7:52100"
+	req.Header.Set(middleware.RequestIDHeader, "req-42")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, shop.RequestInfo{ID: "req-42", IP: "10.0.0.7"}, info)
+}
@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "avito_shop"
+
+// Операции, для которых считаются отказы по недостатку средств.
+const (
+	OperationSend     = "send"
+	OperationPurchase = "purchase"
+)
+
+// Причины неудачной аутентификации.
+const (
+	AuthReasonBadRequest      = "bad_request"
+	AuthReasonInvalidPassword = "invalid_credentials"
+	AuthReasonInvalidToken    = "invalid_token"
+)
+
+// Результаты запуска отложенного перевода.
+const (
+	ScheduledExecuted          = "executed"
+	ScheduledInsufficientFunds = "insufficient_funds"
+	ScheduledLimitExceeded     = "limit_exceeded"
+	ScheduledClaimed           = "claimed"
+	ScheduledError             = "error"
+)
+
+// Исходы переводов на одобрении.
+const (
+	PendingTransferHeld     = "held"
+	PendingTransferApproved = "approved"
+	PendingTransferRejected = "rejected"
+	PendingTransferExpired  = "expired"
+)
+
+// Результаты обращения к кэшу /api/info.
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+)
+
+var (
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total number of HTTP requests by method, chi route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method and chi route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	PurchasesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "purchases_total",
+		Help:      "Total number of successful purchases by item.",
+	}, []string{"item"})
+
+	CoinsTransferredTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "coins_transferred_total",
+		Help:      "Total amount of coins transferred between users.",
+	})
+
+	FeesCollectedTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfer_fees_collected_total",
+		Help:      "Total amount of coins collected as transfer fees.",
+	})
+
+	PromoDiscountsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "promo_discounts_total",
+		Help:      "Total amount of coins discounted by promo codes, by code.",
+	}, []string{"code"})
+
+	CoinsExpiredTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "coins_expired_total",
+		Help:      "Total amount of coins burned because they expired.",
+	})
+
+	AchievementsAwardedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "achievements_awarded_total",
+		Help:      "Total number of achievements awarded, by achievement code.",
+	}, []string{"code"})
+
+	InsufficientFundsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "insufficient_funds_total",
+		Help:      "Total number of operations rejected because of insufficient funds.",
+	}, []string{"operation"})
+
+	AuthFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_failures_total",
+		Help:      "Total number of failed authentication attempts by reason.",
+	}, []string{"reason"})
+
+	InfoCacheRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "info_cache_requests_total",
+		Help:      "Total number of /api/info cache lookups by result (hit or miss).",
+	}, []string{"result"})
+
+	ScheduledTransferRunsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scheduled_transfer_runs_total",
+		Help:      "Total number of scheduled transfer runs by result (executed, insufficient_funds, claimed by another replica, error).",
+	}, []string{"result"})
+
+	PendingTransfersTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pending_transfers_total",
+		Help:      "Total number of transfers held for approval and of their outcomes (held, approved, rejected, expired).",
+	}, []string{"status"})
+
+	CoinsReversedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "coins_reversed_total",
+		Help:      "Total amount of coins returned to senders by admin transfer reversals.",
+	}, []string{"policy"})
+
+	AuditFailuresTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "audit_failures_total",
+		Help:      "Total number of audit log entries that could not be written, by action.",
+	}, []string{"action"})
+)
+
+// Handler отдаёт метрики в формате Prometheus.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterCollector регистрирует дополнительный коллектор (например, статистику пула БД).
+func RegisterCollector(c prometheus.Collector) error {
+	return prometheus.Register(c)
+}
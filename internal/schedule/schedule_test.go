@@ -0,0 +1,58 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	// 2025-02-05 — среда.
+	from := time.Date(2025, 2, 5, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, 2, 5, 10, 31, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 2, 5, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 2, 6, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2025, 2, 6, 9, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 2, 5, 10, 45, 0, 0, time.UTC)},
+		{"0 10 * * 7", time.Date(2025, 2, 9, 10, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// День месяца и день недели ограничены оба — достаточно любого совпадения.
+		{"0 12 15 * 5", time.Date(2025, 2, 7, 12, 0, 0, 0, time.UTC)},
+		{"30 10,18 * * *", time.Date(2025, 2, 5, 18, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			require.NoError(t, err)
+			next, err := s.Next(from)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, next)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "@yearly", "a * * * *"} {
+		t.Run(spec, func(t *testing.T) {
+			_, err := Parse(spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNext_NeverFires(t *testing.T) {
+	s, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	_, err = s.Next(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrNoNextRun)
+}
@@ -0,0 +1,127 @@
+package shop
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"avito-shop/internal/config"
+	"avito-shop/internal/metrics"
+	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Показатели, по которым срабатывают правила достижений (config.AchievementRule.Metric).
+const (
+	AchievementItems    = "items"
+	AchievementSent     = "sent"
+	AchievementReceived = "received"
+)
+
+// MaxAchievementCodeLength совпадает с размером колонки achievements.code.
+const MaxAchievementCodeLength = 64
+
+// EnableAchievements проверяет правила и включает выдачу достижений: сервис подписывается
+// на собственные события EventTransferCompleted и EventPurchaseCompleted.
+func (s *Service) EnableAchievements(rules []config.AchievementRule) error {
+	const op = "shop.Service.EnableAchievements"
+
+	codes := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		var problem string
+		switch {
+		case r.Code == "" || len(r.Code) > MaxAchievementCodeLength:
+			problem = fmt.Sprintf("code must be 1 to %d bytes", MaxAchievementCodeLength)
+		case codes[r.Code]:
+			problem = "duplicate code"
+		case r.Title == "":
+			problem = "title is required"
+		case !slices.Contains([]string{AchievementItems, AchievementSent, AchievementReceived}, r.Metric):
+			problem = fmt.Sprintf("unknown metric %q", r.Metric)
+		case r.Item != "" && r.Metric != AchievementItems:
+			problem = "item is only allowed for the items metric"
+		case r.Item != "" && storage.MerchItems[r.Item] == 0:
+			problem = fmt.Sprintf("unknown item %q", r.Item)
+		case r.Threshold < 1:
+			problem = "threshold must be positive"
+		}
+		if problem != "" {
+			return fmt.Errorf("%v: rule %q: %s", op, r.Code, problem)
+		}
+		codes[r.Code] = true
+	}
+
+	if !slices.Contains(s.subscribers, EventSink(achievementSink{s})) {
+		s.subscribe(achievementSink{s})
+	}
+	s.achievementRules = rules
+	return nil
+}
+
+// achievementSink выдаёт достижения по событиям сервиса, поэтому их получают все пути, которые
+// переводят монеты или покупают предметы, а не только Send и Purchase.
+type achievementSink struct {
+	s *Service
+}
+
+func (a achievementSink) Publish(ctx context.Context, e Event) {
+	switch data := e.Data.(type) {
+	case *TransferEvent:
+		a.s.awardAchievements(ctx, data.FromID, AchievementSent)
+		a.s.awardAchievements(ctx, data.ToID, AchievementReceived)
+	case *PurchaseEvent:
+		a.s.awardAchievements(ctx, data.BuyerID, AchievementItems)
+	}
+}
+
+// awardAchievements выдаёт пользователю ещё не выданные достижения с показателями kinds,
+// условия которых выполнены. Ошибка не отменяет покупку или перевод, после которых идёт проверка:
+// она только записывается в трассировку, а пропущенное достижение выдаст следующее событие.
+func (s *Service) awardAchievements(ctx context.Context, userID int, kinds ...string) {
+	if len(s.achievementRules) == 0 {
+		return
+	}
+	var err error
+	ctx, span := tracer.Start(ctx, "shop.Service.awardAchievements", trace.WithAttributes(attribute.Int("user.id", userID)))
+	defer func() { tracing.End(span, err) }()
+
+	progress, err := s.Storage.GetAchievementProgress(ctx, userID)
+	if err != nil {
+		return
+	}
+	for _, r := range s.achievementRules {
+		if !slices.Contains(kinds, r.Metric) || progress.Awarded[r.Code] || achievementValue(progress, r) < r.Threshold {
+			continue
+		}
+		var awarded bool
+		awarded, err = s.Storage.AwardAchievement(ctx, userID, &storage.Achievement{
+			Code:        r.Code,
+			Title:       r.Title,
+			Description: r.Description,
+			AwardedAt:   s.now(),
+		})
+		if err != nil {
+			return
+		}
+		if awarded {
+			metrics.AchievementsAwardedTotal.WithLabelValues(r.Code).Inc()
+		}
+	}
+}
+
+// achievementValue возвращает показатель пользователя, который проверяет правило r.
+func achievementValue(p *storage.AchievementProgress, r config.AchievementRule) int {
+	switch {
+	case r.Metric == AchievementSent:
+		return p.Sent
+	case r.Metric == AchievementReceived:
+		return p.Received
+	case r.Item != "":
+		return p.Items[r.Item]
+	default:
+		return p.TotalItems()
+	}
+}
@@ -0,0 +1,278 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"avito-shop/internal/config"
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testAchievementRules = []config.AchievementRule{
+	{Code: "first_purchase", Title: "Первая покупка", Metric: AchievementItems, Threshold: 1},
+	{Code: "pink_hoody", Title: "Розовая толстовка", Metric: AchievementItems, Item: "pink-hoody", Threshold: 1},
+	{Code: "generous", Title: "Щедрая душа", Description: "1000 монет коллегам", Metric: AchievementSent, Threshold: 1000},
+	{Code: "popular", Title: "Любимец коллег", Metric: AchievementReceived, Threshold: 500},
+}
+
+func TestEnableAchievements_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		rule config.AchievementRule
+	}{
+		{"Empty code", config.AchievementRule{Title: "t", Metric: AchievementSent, Threshold: 1}},
+		{"Duplicate code", config.AchievementRule{Code: "generous", Title: "t", Metric: AchievementSent, Threshold: 1}},
+		{"Empty title", config.AchievementRule{Code: "c", Metric: AchievementSent, Threshold: 1}},
+		{"Unknown metric", config.AchievementRule{Code: "c", Title: "t", Metric: "likes", Threshold: 1}},
+		{"Item for transfer metric", config.AchievementRule{Code: "c", Title: "t", Metric: AchievementSent, Item: "cup", Threshold: 1}},
+		{"Unknown item", config.AchievementRule{Code: "c", Title: "t", Metric: AchievementItems, Item: "dragon", Threshold: 1}},
+		{"Zero threshold", config.AchievementRule{Code: "c", Title: "t", Metric: AchievementItems}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewService(&storage.IStorageMock{})
+			err := service.EnableAchievements(append(append([]config.AchievementRule(nil), testAchievementRules...), tt.rule))
+			assert.Error(t, err)
+			assert.Empty(t, service.achievementRules)
+		})
+	}
+
+	service := NewService(&storage.IStorageMock{})
+	require.NoError(t, service.EnableAchievements(testAchievementRules))
+}
+
+func TestPurchase_AwardsAchievements(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	mockStorage := &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		ListItemPricesFunc: func(ctx context.Context, at time.Time) ([]storage.ItemPrice, error) {
+			return nil, nil
+		},
+		GetInfoFunc: func(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+			ir.Coins = 1000
+			return 7, nil
+		},
+		BuyItemFunc: func(ctx context.Context, name, item string, amount, priceID int, audit *storage.TxAudit) error {
+			return nil
+		},
+		GetAchievementProgressFunc: func(ctx context.Context, userID int) (*storage.AchievementProgress, error) {
+			return &storage.AchievementProgress{
+				Sent:    5000,
+				Items:   map[string]int{"pink-hoody": 1, "cup": 2},
+				Awarded: map[string]bool{"first_purchase": true},
+			}, nil
+		},
+		AwardAchievementFunc: func(ctx context.Context, userID int, a *storage.Achievement) (bool, error) {
+			return true, nil
+		},
+	}
+	service := NewService(mockStorage)
+	service.now = func() time.Time { return now }
+	require.NoError(t, service.EnableAchievements(testAchievementRules))
+
+	require.NoError(t, service.Purchase(context.Background(), "alice", "pink-hoody", ""))
+
+	calls := mockStorage.AwardAchievementCalls()
+	require.Len(t, calls, 1, "already awarded and transfer achievements are not checked on purchase")
+	assert.Equal(t, 7, calls[0].UserID)
+	assert.Equal(t, &storage.Achievement{Code: "pink_hoody", Title: "Розовая толстовка", AwardedAt: now}, calls[0].A)
+}
+
+func TestSend_AwardsAchievementsToBothSides(t *testing.T) {
+	progress := map[int]*storage.AchievementProgress{
+		1: {Sent: 1000, Received: 900},
+		2: {Sent: 1500, Received: 400},
+	}
+	mockStorage := &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		GetInfoFunc: func(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+			ir.Coins = 1000
+			if username == "alice" {
+				return 1, nil
+			}
+			return 2, nil
+		},
+		SendCoinsFunc: func(ctx context.Context, username string, fromUserID, toUserID int, scr *storage.SendCoinRequest, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+			return nil
+		},
+		GetAchievementProgressFunc: func(ctx context.Context, userID int) (*storage.AchievementProgress, error) {
+			return progress[userID], nil
+		},
+		AwardAchievementFunc: func(ctx context.Context, userID int, a *storage.Achievement) (bool, error) {
+			return false, errors.New("db is down")
+		},
+	}
+	service := NewService(mockStorage)
+	require.NoError(t, service.EnableAchievements(testAchievementRules))
+
+	require.NoError(t, service.Send(context.Background(), "alice", &storage.SendCoinRequest{ToUser: "bob", Amount: 100}),
+		"a failed award does not fail the transfer")
+
+	calls := mockStorage.AwardAchievementCalls()
+	require.Len(t, calls, 1, "the recipient has not reached the received threshold")
+	assert.Equal(t, 1, calls[0].UserID)
+	assert.Equal(t, "generous", calls[0].A.Code)
+}
+
+// Достижения выдаются по событиям сервиса, поэтому их получают все пути, которые переводят монеты или покупают.
+func TestAchievements_AllCoinPaths(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		setup func(m *storage.IStorageMock)
+		call  func(s *Service) error
+		want  []string
+	}{
+		{
+			name: "Accepted payment request",
+			setup: func(m *storage.IStorageMock) {
+				m.GetPaymentRequestFunc = func(ctx context.Context, id int) (*storage.PaymentRequest, error) {
+					return &storage.PaymentRequest{ID: id, RequesterID: 1, Requester: "alice", PayerID: 2, Payer: "bob", Amount: 40,
+						Status: storage.PaymentRequestPending, ExpiresAt: now.Add(time.Hour)}, nil
+				}
+				m.AcceptPaymentRequestFunc = func(ctx context.Context, id int, at time.Time, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+					return nil
+				}
+			},
+			call: func(s *Service) error {
+				_, err := s.AcceptPaymentRequest(ctx, "bob", 7)
+				return err
+			},
+			want: []string{"2:generous", "1:popular"},
+		},
+		{
+			name: "Scheduled transfer run",
+			setup: func(m *storage.IStorageMock) {
+				m.ListDueScheduledTransfersFunc = func(ctx context.Context, at time.Time, limit int) ([]storage.ScheduledTransfer, error) {
+					return []storage.ScheduledTransfer{{ID: 1, SenderID: 1, Sender: "alice", RecipientID: 2, Recipient: "bob", Amount: 10, NextRunAt: now}}, nil
+				}
+				m.RunScheduledTransferFunc = func(ctx context.Context, st *storage.ScheduledTransfer, next *time.Time, at time.Time, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+					return nil
+				}
+			},
+			call: func(s *Service) error {
+				_, err := s.RunDueTransfers(ctx)
+				return err
+			},
+			want: []string{"1:generous", "2:popular"},
+		},
+		{
+			name: "Approved pending transfer",
+			setup: func(m *storage.IStorageMock) {
+				m.GetUserRoleFunc = func(ctx context.Context, id int) (string, error) {
+					if id == 3 {
+						return storage.AdminRole, nil
+					}
+					return storage.DefaultRole, nil
+				}
+				pt := storage.PendingTransfer{ID: 4, SenderID: 1, Sender: "alice", RecipientID: 2, Recipient: "bob", Amount: 600,
+					Status: storage.PendingTransferPending, ExpiresAt: now.Add(time.Hour)}
+				m.GetPendingTransferFunc = func(ctx context.Context, id int) (*storage.PendingTransfer, error) {
+					c := pt
+					return &c, nil
+				}
+				m.ResolvePendingTransferFunc = func(ctx context.Context, id int, status string, resolverID int, at time.Time, limits storage.TransferLimits, audit *storage.TxAudit) (*storage.PendingTransfer, error) {
+					c := pt
+					c.Status = status
+					return &c, nil
+				}
+			},
+			call: func(s *Service) error {
+				_, err := s.ApprovePendingTransfer(ctx, "carol", 4)
+				return err
+			},
+			want: []string{"1:generous", "2:popular"},
+		},
+		{
+			name: "Transfer from a group wallet",
+			setup: func(m *storage.IStorageMock) {
+				m.SendCoinsFunc = func(ctx context.Context, username string, fromUserID, toUserID int, scr *storage.SendCoinRequest, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+					return nil
+				}
+			},
+			call: func(s *Service) error {
+				return s.SendFromGroup(ctx, "alice", "team", &storage.SendCoinRequest{ToUser: "bob", Amount: 10})
+			},
+			want: []string{"100:generous", "2:popular"},
+		},
+		{
+			name: "Group purchase",
+			setup: func(m *storage.IStorageMock) {
+				m.GetGroupPurchaseFunc = func(ctx context.Context, id int) (*storage.GroupPurchase, error) {
+					return &storage.GroupPurchase{ID: id, GroupID: 100, Group: "team", Item: "cup", Status: storage.GroupPurchasePending}, nil
+				}
+				m.ApproveGroupPurchaseFunc = func(ctx context.Context, id, userID, price, priceID int, at time.Time, audit *storage.TxAudit) (*storage.GroupPurchase, error) {
+					return &storage.GroupPurchase{ID: id, GroupID: 100, Group: "team", Item: "cup", Status: storage.GroupPurchaseCompleted, Price: price}, nil
+				}
+			},
+			call: func(s *Service) error {
+				_, err := s.ApproveGroupPurchase(ctx, "alice", "team", 1)
+				return err
+			},
+			want: []string{"100:first_purchase"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := groupStorage(1000)
+			mockStorage.GetAchievementProgressFunc = func(ctx context.Context, userID int) (*storage.AchievementProgress, error) {
+				return &storage.AchievementProgress{Sent: 1000, Received: 500, Items: map[string]int{"cup": 1}}, nil
+			}
+			mockStorage.AwardAchievementFunc = func(ctx context.Context, userID int, a *storage.Achievement) (bool, error) {
+				return true, nil
+			}
+			tt.setup(mockStorage)
+			service := NewService(mockStorage)
+			service.now = func() time.Time { return now }
+			service.Approvals = testApprovals
+			require.NoError(t, service.EnableAchievements(testAchievementRules))
+
+			require.NoError(t, tt.call(service))
+
+			var got []string
+			for _, c := range mockStorage.AwardAchievementCalls() {
+				got = append(got, fmt.Sprintf("%d:%s", c.UserID, c.A.Code))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnableAchievements_SubscribesOnce(t *testing.T) {
+	service := NewService(&storage.IStorageMock{})
+	require.NoError(t, service.EnableAchievements(testAchievementRules))
+	require.NoError(t, service.EnableAchievements(testAchievementRules))
+	assert.Len(t, service.subscribers, 1)
+}
+
+func TestCollectAllInfo_Achievements(t *testing.T) {
+	awardedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	mockStorage := &storage.IStorageMock{
+		GetFullInfoFunc: func(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+			return 1, nil
+		},
+		ListAchievementsFunc: func(ctx context.Context, userID int) ([]storage.Achievement, error) {
+			return []storage.Achievement{{Code: "first_purchase", Title: "Первая покупка", AwardedAt: awardedAt}}, nil
+		},
+	}
+	service := NewService(mockStorage)
+
+	info, err := service.CollectAllInfo(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Nil(t, info.Achievements, "achievements are not loaded while disabled")
+
+	require.NoError(t, service.EnableAchievements(testAchievementRules))
+	info, err = service.CollectAllInfo(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []storage.Achievement{{Code: "first_purchase", Title: "Первая покупка", AwardedAt: awardedAt}}, info.Achievements)
+}
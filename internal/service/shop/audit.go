@@ -0,0 +1,278 @@
+package shop
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"avito-shop/internal/metrics"
+	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Действия журнала аудита.
+const (
+	AuditAuthRegister = "auth.register"
+	AuditAuthLogin    = "auth.login"
+	AuditAuthFailed   = "auth.failed"
+
+	AuditTransferSend      = "transfer.send"
+	AuditTransferHold      = "transfer.hold"
+	AuditTransferApprove   = "transfer.approve"
+	AuditTransferReject    = "transfer.reject"
+	AuditTransferExpire    = "transfer.expire"
+	AuditTransferReverse   = "transfer.reverse"
+	AuditTransferScheduled = "transfer.scheduled"
+	AuditCoinsExpire       = "coins.expire"
+
+	AuditPaymentRequest = "payment_request.create"
+	AuditPaymentAccept  = "payment_request.accept"
+	AuditPaymentDecline = "payment_request.decline"
+	AuditPaymentCancel  = "payment_request.cancel"
+	AuditScheduleCreate = "scheduled_transfer.create"
+	AuditScheduleCancel = "scheduled_transfer.cancel"
+
+	AuditPurchase      = "purchase"
+	AuditGroupPurchase = "group.purchase"
+
+	AuditGroupCreate       = "group.create"
+	AuditGroupMemberSet    = "group.member_set"
+	AuditGroupMemberRemove = "group.member_remove"
+	AuditLeaderboardOptOut = "leaderboard.opt_out"
+	AuditRoleSet           = "user.role"
+
+	AuditPromoCreate   = "promo.create"
+	AuditPromoDisable  = "promo.disable"
+	AuditPriceSchedule = "price.schedule"
+	AuditPriceCancel   = "price.cancel"
+)
+
+// AuditSystem — исполнитель действий фоновых задач.
+const AuditSystem = "system"
+
+// AuditCLI — исполнитель действий консольных команд.
+const AuditCLI = "cli"
+
+const (
+	// DefaultAuditLimit — сколько записей журнала отдаётся, если лимит не указан.
+	DefaultAuditLimit = 100
+	// MaxAuditLimit — верхняя граница числа записей журнала в одном ответе.
+	MaxAuditLimit = 1000
+	// maxRequestIDLength совпадает с размером колонки audit_log.request_id: идентификатор
+	// может прийти от клиента в X-Request-Id.
+	maxRequestIDLength = 64
+)
+
+// RequestInfo — откуда пришёл запрос; попадает в журнал аудита вместе с действием.
+type RequestInfo struct {
+	ID string
+	IP string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo добавляет в контекст сведения о запросе для журнала аудита.
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFrom возвращает сведения о запросе из контекста; идентификатор обрезается
+// до размера колонки журнала.
+func RequestInfoFrom(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	if len(info.ID) > maxRequestIDLength {
+		info.ID = info.ID[:maxRequestIDLength]
+	}
+	return info
+}
+
+// AuditVerification — результат проверки цепочки хэшей журнала. BrokenAt — id первой записи,
+// на которой цепочка не сходится: запись изменена, удалена предыдущая или, если это последняя
+// запись Head, удалены записи после неё.
+type AuditVerification struct {
+	Valid    bool              `json:"valid"`
+	Checked  int               `json:"checked"`
+	Head     storage.AuditHead `json:"head"`
+	BrokenAt int               `json:"brokenAt,omitempty"`
+}
+
+// auditTarget возвращает цель записи журнала вида "kind:id".
+func auditTarget(kind string, id any) string {
+	return fmt.Sprintf("%s:%v", kind, id)
+}
+
+// audit дописывает запись в журнал аудита. Значения before и after сериализуются в JSON;
+// nil означает, что значения нет. Запись добавляется после изменения отдельной транзакцией,
+// поэтому ошибка журнала не отменяет операцию, а считается метрикой и попадает в трейс.
+// Операции с монетами так не журналируются, см. txAudit.
+func (s *Service) audit(ctx context.Context, actor, action, target string, before, after any) {
+	var err error
+	ctx, span := tracer.Start(ctx, "shop.Service.audit", trace.WithAttributes(attribute.String("shop.audit.action", action)))
+	defer func() { tracing.End(span, err) }()
+
+	info := RequestInfoFrom(ctx)
+	e := &storage.AuditEntry{
+		Actor:     actor,
+		Action:    action,
+		Target:    target,
+		RequestID: info.ID,
+		IP:        info.IP,
+		CreatedAt: s.now(),
+	}
+	if e.Before, err = auditValue(before); err == nil {
+		e.After, err = auditValue(after)
+	}
+	if err == nil {
+		err = s.Storage.AppendAudit(ctx, e)
+	}
+	if err != nil {
+		metrics.AuditFailuresTotal.WithLabelValues(action).Inc()
+	}
+}
+
+// txAudit готовит запись журнала об операции с монетами. Хранилище добавляет её в транзакции
+// самой операции вместе с балансами счетов accounts до и после изменения (см. storage.TxAudit),
+// поэтому операция без записи не выполняется.
+func (s *Service) txAudit(ctx context.Context, actor, action, target string, accounts ...int) *storage.TxAudit {
+	info := RequestInfoFrom(ctx)
+	return &storage.TxAudit{
+		Entry: &storage.AuditEntry{
+			Actor:     actor,
+			Action:    action,
+			Target:    target,
+			RequestID: info.ID,
+			IP:        info.IP,
+			CreatedAt: s.now(),
+		},
+		Accounts: accounts,
+	}
+}
+
+func auditValue(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// RecordAuth записывает в журнал аудита вход, регистрацию или неудачную попытку входа username.
+// Вход проверяется вне сервиса, поэтому обработчик сообщает о нём сам. Каждая запись, в том числе
+// об успешном входе, ждёт блокировку audit_head (см. storage.AuditHeadQuery): входы журналируются
+// по одному вместе с операциями с монетами, и пропускная способность входов ограничена этим шагом.
+func (s *Service) RecordAuth(ctx context.Context, username, action string) {
+	s.audit(ctx, username, action, auditTarget("user", username), nil, nil)
+}
+
+// AuditLog возвращает записи журнала аудита по фильтру в порядке добавления.
+func (s *Service) AuditLog(ctx context.Context, admin string, filter storage.AuditFilter) (_ []storage.AuditEntry, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.AuditLog")
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.requireAdmin(ctx, admin); err != nil {
+		return nil, err
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultAuditLimit
+	}
+	if err = ValidateAuditFilter(filter); err != nil {
+		return nil, err
+	}
+	entries, err := s.Storage.ListAudit(ctx, filter)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+	if entries == nil {
+		entries = []storage.AuditEntry{}
+	}
+	return entries, nil
+}
+
+// ExportAuditLog отдаёт в write все записи журнала по фильтру партиями по MaxAuditLimit;
+// filter.Limit не учитывается. Выгрузка содержит хэши, поэтому цепочку можно проверить вне сервиса.
+func (s *Service) ExportAuditLog(ctx context.Context, admin string, filter storage.AuditFilter,
+	write func([]storage.AuditEntry) error) (err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ExportAuditLog")
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.requireAdmin(ctx, admin); err != nil {
+		return err
+	}
+	filter.Limit = MaxAuditLimit
+	if err = ValidateAuditFilter(filter); err != nil {
+		return err
+	}
+	for {
+		entries, listErr := s.Storage.ListAudit(ctx, filter)
+		if listErr != nil {
+			return ErrInternalServer
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		if err = write(entries); err != nil {
+			return err
+		}
+		filter.AfterID = entries[len(entries)-1].ID
+	}
+}
+
+// VerifyAuditLog проходит журнал от начала до последней записи и проверяет цепочку хэшей.
+// Записи, добавленные во время проверки, не проверяются.
+func (s *Service) VerifyAuditLog(ctx context.Context, admin string) (_ *AuditVerification, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.VerifyAuditLog")
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.requireAdmin(ctx, admin); err != nil {
+		return nil, err
+	}
+	head, err := s.Storage.GetAuditHead(ctx)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	res := &AuditVerification{Head: *head}
+	filter := storage.AuditFilter{Limit: MaxAuditLimit}
+	prev := ""
+	for filter.AfterID < head.LastID {
+		entries, listErr := s.Storage.ListAudit(ctx, filter)
+		if listErr != nil {
+			return nil, ErrInternalServer
+		}
+		if len(entries) == 0 {
+			break
+		}
+		for i := range entries {
+			e := &entries[i]
+			if e.ID > head.LastID {
+				break
+			}
+			if e.PrevHash != prev || storage.AuditHash(e) != e.Hash {
+				res.BrokenAt = e.ID
+				return res, nil
+			}
+			prev = e.Hash
+			res.Checked++
+		}
+		filter.AfterID = entries[len(entries)-1].ID
+	}
+	if prev != head.Hash {
+		res.BrokenAt = head.LastID
+		return res, nil
+	}
+
+	res.Valid = true
+	return res, nil
+}
+
+// auditStatus описывает смену статуса объекта для журнала аудита.
+type auditStatus struct {
+	Status string `json:"status"`
+}
+
+// auditOptOut описывает участие пользователя в таблицах лидеров для журнала аудита.
+type auditOptOut struct {
+	OptOut bool `json:"optOut"`
+}
@@ -0,0 +1,282 @@
+package shop
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nopAppendAudit(ctx context.Context, e *storage.AuditEntry) error { return nil }
+
+// auditStorage — журнал аудита в памяти поверх IStorageMock: записи сцепляются так же, как в хранилище.
+type auditStorage struct {
+	entries []storage.AuditEntry
+	head    storage.AuditHead
+}
+
+func (a *auditStorage) attach(m *storage.IStorageMock) {
+	m.AppendAuditFunc = func(ctx context.Context, e *storage.AuditEntry) error {
+		e.ID = len(a.entries) + 1
+		e.CreatedAt = e.CreatedAt.UTC().Truncate(time.Second)
+		e.PrevHash = a.head.Hash
+		e.Hash = storage.AuditHash(e)
+		a.entries = append(a.entries, *e)
+		a.head = storage.AuditHead{LastID: e.ID, Hash: e.Hash}
+		return nil
+	}
+	m.ListAuditFunc = func(ctx context.Context, filter storage.AuditFilter) ([]storage.AuditEntry, error) {
+		var res []storage.AuditEntry
+		for _, e := range a.entries {
+			if e.ID > filter.AfterID && len(res) < filter.Limit {
+				res = append(res, e)
+			}
+		}
+		return res, nil
+	}
+	m.GetAuditHeadFunc = func(ctx context.Context) (*storage.AuditHead, error) {
+		head := a.head
+		return &head, nil
+	}
+}
+
+func adminStorage() *storage.IStorageMock {
+	return &storage.IStorageMock{
+		GetInfoFunc: func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+			return map[string]int{"admin": 10, "alice": 1, "bob": 2}[username], nil
+		},
+		GetUserRoleFunc: func(ctx context.Context, id int) (string, error) {
+			if id == 10 {
+				return storage.AdminRole, nil
+			}
+			return storage.DefaultRole, nil
+		},
+	}
+}
+
+func TestSend_Audited(t *testing.T) {
+	now := time.Date(2026, 10, 4, 12, 0, 0, 0, time.UTC)
+	mockStorage := &storage.IStorageMock{
+		GetInfoFunc: func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+			res.Coins = 1000
+			return map[string]int{"alice": 1, "bob": 2}[username], nil
+		},
+		SendCoinsFunc: func(ctx context.Context, fromUsername string, fromID, toID int, scr *storage.SendCoinRequest,
+			limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+			return nil
+		},
+	}
+	s := NewService(mockStorage)
+	s.now = func() time.Time { return now }
+
+	ctx := WithRequestInfo(context.Background(), RequestInfo{ID: "req-42", IP: "10.0.0.7"})
+	require.NoError(t, s.Send(ctx, "alice", &storage.SendCoinRequest{ToUser: "bob", Amount: 300}))
+
+	// Запись добавляет хранилище в транзакции перевода, отдельной записи после него нет.
+	assert.Empty(t, mockStorage.AppendAuditCalls())
+	require.Len(t, mockStorage.SendCoinsCalls(), 1)
+	audit := mockStorage.SendCoinsCalls()[0].Audit
+	require.NotNil(t, audit)
+	assert.Equal(t, []int{1, 2}, audit.Accounts)
+	e := audit.Entry
+	assert.Equal(t, "alice", e.Actor)
+	assert.Equal(t, AuditTransferSend, e.Action)
+	assert.Equal(t, "user:bob", e.Target)
+	assert.Equal(t, "req-42", e.RequestID)
+	assert.Equal(t, "10.0.0.7", e.IP)
+	assert.Equal(t, now, e.CreatedAt)
+}
+
+func TestSend_AuditFailureFailsTransfer(t *testing.T) {
+	mockStorage := &storage.IStorageMock{
+		GetInfoFunc: func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+			res.Coins = 1000
+			return map[string]int{"alice": 1, "bob": 2}[username], nil
+		},
+		SendCoinsFunc: func(ctx context.Context, fromUsername string, fromID, toID int, scr *storage.SendCoinRequest,
+			limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+			return assert.AnError
+		},
+	}
+	s := NewService(mockStorage)
+
+	assert.ErrorIs(t, s.Send(context.Background(), "alice", &storage.SendCoinRequest{ToUser: "bob", Amount: 300}), ErrInternalServer)
+	assert.Empty(t, mockStorage.AppendAuditCalls())
+}
+
+func TestAudit_FailureDoesNotFailOperation(t *testing.T) {
+	mockStorage := adminStorage()
+	mockStorage.CreatePromoCodeFunc = func(ctx context.Context, pc *storage.PromoCode) error { return nil }
+	mockStorage.AppendAuditFunc = func(ctx context.Context, e *storage.AuditEntry) error { return assert.AnError }
+	s := NewService(mockStorage)
+
+	_, err := s.CreatePromoCode(context.Background(), "admin", &PromoCodeInput{Code: "sale10", Kind: storage.PromoPercent, Value: 10})
+	require.NoError(t, err)
+	require.Len(t, mockStorage.AppendAuditCalls(), 1)
+	assert.Equal(t, AuditPromoCreate, mockStorage.AppendAuditCalls()[0].E.Action)
+}
+
+func TestRequestInfo_TruncatesID(t *testing.T) {
+	long := string(make([]byte, maxRequestIDLength+10))
+	info := RequestInfoFrom(WithRequestInfo(context.Background(), RequestInfo{ID: long, IP: "::1"}))
+	assert.Len(t, info.ID, maxRequestIDLength)
+	assert.Equal(t, "::1", info.IP)
+	assert.Equal(t, RequestInfo{}, RequestInfoFrom(context.Background()))
+}
+
+func TestAuditLog(t *testing.T) {
+	mockStorage := adminStorage()
+	log := &auditStorage{}
+	log.attach(mockStorage)
+	s := NewService(mockStorage)
+	s.RecordAuth(context.Background(), "alice", AuditAuthLogin)
+
+	entries, err := s.AuditLog(context.Background(), "admin", storage.AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "user:alice", entries[0].Target)
+	assert.Equal(t, DefaultAuditLimit, mockStorage.ListAuditCalls()[0].Filter.Limit)
+
+	_, err = s.AuditLog(context.Background(), "alice", storage.AuditFilter{})
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = s.AuditLog(context.Background(), "admin", storage.AuditFilter{Limit: MaxAuditLimit + 1})
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestExportAuditLog_Batches(t *testing.T) {
+	mockStorage := adminStorage()
+	log := &auditStorage{}
+	log.attach(mockStorage)
+	s := NewService(mockStorage)
+	for i := 0; i < MaxAuditLimit+5; i++ {
+		s.RecordAuth(context.Background(), "alice", AuditAuthLogin)
+	}
+
+	var batches []int
+	err := s.ExportAuditLog(context.Background(), "admin", storage.AuditFilter{Limit: 1}, func(entries []storage.AuditEntry) error {
+		batches = append(batches, len(entries))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{MaxAuditLimit, 5}, batches)
+
+	err = s.ExportAuditLog(context.Background(), "bob", storage.AuditFilter{}, nil)
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestVerifyAuditLog(t *testing.T) {
+	tests := []struct {
+		name         string
+		tamper       func(a *auditStorage)
+		wantValid    bool
+		wantBrokenAt int
+	}{
+		{name: "Intact", tamper: func(a *auditStorage) {}, wantValid: true},
+		{name: "Changed value", tamper: func(a *auditStorage) {
+			a.entries[1].After = json.RawMessage(`{"alice":100000}`)
+		}, wantBrokenAt: 2},
+		{name: "Deleted entry", tamper: func(a *auditStorage) {
+			a.entries = append(a.entries[:1], a.entries[2:]...)
+		}, wantBrokenAt: 3},
+		{name: "Truncated tail", tamper: func(a *auditStorage) {
+			a.entries = a.entries[:2]
+		}, wantBrokenAt: 3},
+		{name: "Rehashed entry", tamper: func(a *auditStorage) {
+			a.entries[0].Actor = "mallory"
+			a.entries[0].Hash = storage.AuditHash(&a.entries[0])
+		}, wantBrokenAt: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := adminStorage()
+			log := &auditStorage{}
+			log.attach(mockStorage)
+			s := NewService(mockStorage)
+			for _, user := range []string{"alice", "bob", "alice"} {
+				s.RecordAuth(context.Background(), user, AuditAuthLogin)
+			}
+			tt.tamper(log)
+
+			res, err := s.VerifyAuditLog(context.Background(), "admin")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantValid, res.Valid)
+			assert.Equal(t, tt.wantBrokenAt, res.BrokenAt)
+			assert.Equal(t, 3, res.Head.LastID)
+		})
+	}
+
+	_, err := NewService(adminStorage()).VerifyAuditLog(context.Background(), "alice")
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestAudit_RequestsSchedulesAndOptOut(t *testing.T) {
+	now := time.Date(2026, 10, 4, 12, 0, 0, 0, time.UTC)
+	pr := &storage.PaymentRequest{ID: 7, RequesterID: 1, Requester: "alice", PayerID: 2, Payer: "bob", Amount: 100,
+		Status: storage.PaymentRequestPending, ExpiresAt: now.Add(time.Hour)}
+	st := &storage.ScheduledTransfer{ID: 9, SenderID: 1, Sender: "alice", RecipientID: 2, Recipient: "bob", Amount: 100,
+		Status: storage.ScheduledTransferActive}
+	mockStorage := &storage.IStorageMock{
+		GetInfoFunc: func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+			return map[string]int{"alice": 1, "bob": 2}[username], nil
+		},
+		CreatePaymentRequestFunc: func(ctx context.Context, pr *storage.PaymentRequest) error {
+			pr.ID = 7
+			return nil
+		},
+		GetPaymentRequestFunc: func(ctx context.Context, id int) (*storage.PaymentRequest, error) {
+			cp := *pr
+			return &cp, nil
+		},
+		ResolvePaymentRequestFunc: func(ctx context.Context, id int, status string, at time.Time) error { return nil },
+		CreateScheduledTransferFunc: func(ctx context.Context, st *storage.ScheduledTransfer) error {
+			st.ID = 9
+			return nil
+		},
+		GetScheduledTransferFunc: func(ctx context.Context, id int) (*storage.ScheduledTransfer, error) {
+			cp := *st
+			return &cp, nil
+		},
+		CancelScheduledTransferFunc: func(ctx context.Context, id int) error { return nil },
+		SetLeaderboardOptOutFunc:    func(ctx context.Context, userID int, optOut bool) error { return nil },
+	}
+	log := &auditStorage{}
+	log.attach(mockStorage)
+	s := NewService(mockStorage)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err := s.RequestPayment(ctx, "alice", &PaymentRequestInput{FromUser: "bob", Amount: 100})
+	require.NoError(t, err)
+	_, err = s.DeclinePaymentRequest(ctx, "bob", 7)
+	require.NoError(t, err)
+	_, err = s.CancelPaymentRequest(ctx, "alice", 7)
+	require.NoError(t, err)
+	runAt := now.Add(time.Hour)
+	_, err = s.ScheduleTransfer(ctx, "alice", &ScheduledTransferInput{SendCoinRequest: storage.SendCoinRequest{ToUser: "bob", Amount: 100}, RunAt: &runAt})
+	require.NoError(t, err)
+	_, err = s.CancelScheduledTransfer(ctx, "alice", 9)
+	require.NoError(t, err)
+	require.NoError(t, s.SetLeaderboardOptOut(ctx, "bob", true))
+
+	type row struct{ Actor, Action, Target string }
+	var got []row
+	for _, e := range log.entries {
+		got = append(got, row{e.Actor, e.Action, e.Target})
+	}
+	assert.Equal(t, []row{
+		{"alice", AuditPaymentRequest, "payment_request:7"},
+		{"bob", AuditPaymentDecline, "payment_request:7"},
+		{"alice", AuditPaymentCancel, "payment_request:7"},
+		{"alice", AuditScheduleCreate, "scheduled_transfer:9"},
+		{"alice", AuditScheduleCancel, "scheduled_transfer:9"},
+		{"bob", AuditLeaderboardOptOut, "user:bob"},
+	}, got)
+	assert.JSONEq(t, `{"status":"active"}`, string(log.entries[4].Before))
+	assert.JSONEq(t, `{"optOut":true}`, string(log.entries[5].After))
+}
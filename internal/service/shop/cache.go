@@ -0,0 +1,312 @@
+package shop
+
+import (
+	"context"
+	"sync"
+
+	"avito-shop/internal/cache"
+	"avito-shop/internal/metrics"
+	"avito-shop/internal/service/shop/storage"
+)
+
+// CachedService — декоратор IService, кэширующий ответ CollectAllInfo по имени пользователя.
+// Любая операция, меняющая баланс, инвентарь или историю, сбрасывает записи всех затронутых пользователей.
+// Кэш локален для процесса: при нескольких репликах устаревание ограничено только TTL кэша,
+// если не подключить общую реализацию cache.Cache.
+type CachedService struct {
+	next  IService
+	cache cache.Cache[*storage.InfoResponse]
+
+	// epoch увеличивается при каждой инвалидации. Ответ, прочитанный из БД, попадает в кэш,
+	// только если за время чтения не было ни одной инвалидации, иначе запись могла устареть.
+	mu    sync.Mutex
+	epoch uint64
+}
+
+func NewCachedService(next IService, c cache.Cache[*storage.InfoResponse]) *CachedService {
+	return &CachedService{next: next, cache: c}
+}
+
+func (s *CachedService) CollectAllInfo(ctx context.Context, username string) (*storage.InfoResponse, error) {
+	if res, ok := s.cache.Get(username); ok {
+		metrics.InfoCacheRequestsTotal.WithLabelValues(metrics.CacheHit).Inc()
+		return cloneInfo(res), nil
+	}
+	metrics.InfoCacheRequestsTotal.WithLabelValues(metrics.CacheMiss).Inc()
+
+	s.mu.Lock()
+	epoch := s.epoch
+	s.mu.Unlock()
+
+	res, err := s.next.CollectAllInfo(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.epoch == epoch {
+		s.cache.Set(username, cloneInfo(res))
+	}
+	s.mu.Unlock()
+
+	return res, nil
+}
+
+// Send сбрасывает кэш отправителя и получателя даже при ошибке: внутренняя ошибка
+// не гарантирует, что перевод не был записан.
+func (s *CachedService) Send(ctx context.Context, fromUsername string, scr *storage.SendCoinRequest) error {
+	defer s.Invalidate(fromUsername, scr.ToUser)
+	return s.next.Send(ctx, fromUsername, scr)
+}
+
+func (s *CachedService) Purchase(ctx context.Context, username, item, promoCode string) error {
+	defer s.Invalidate(username)
+	return s.next.Purchase(ctx, username, item, promoCode)
+}
+
+func (s *CachedService) QuotePurchase(ctx context.Context, username, item, promoCode string) (*storage.PriceQuote, error) {
+	return s.next.QuotePurchase(ctx, username, item, promoCode)
+}
+
+// History не кэшируется: выборки с фильтрами редки, а ключ кэша пришлось бы строить из фильтра.
+func (s *CachedService) History(ctx context.Context, username string, filter storage.HistoryFilter) (*storage.CoinHistory, error) {
+	return s.next.History(ctx, username, filter)
+}
+
+func (s *CachedService) RequestPayment(ctx context.Context, requester string, req *PaymentRequestInput) (*storage.PaymentRequest, error) {
+	return s.next.RequestPayment(ctx, requester, req)
+}
+
+func (s *CachedService) ListPaymentRequests(ctx context.Context, username string, direction string) ([]storage.PaymentRequest, error) {
+	return s.next.ListPaymentRequests(ctx, username, direction)
+}
+
+// AcceptPaymentRequest переводит монеты, поэтому сбрасывает кэш плательщика и, если запрос
+// удалось загрузить, автора запроса.
+func (s *CachedService) AcceptPaymentRequest(ctx context.Context, username string, id int) (*storage.PaymentRequest, error) {
+	pr, err := s.next.AcceptPaymentRequest(ctx, username, id)
+	if pr != nil {
+		s.Invalidate(username, pr.Requester)
+	} else {
+		s.Invalidate(username)
+	}
+	return pr, err
+}
+
+func (s *CachedService) DeclinePaymentRequest(ctx context.Context, username string, id int) (*storage.PaymentRequest, error) {
+	return s.next.DeclinePaymentRequest(ctx, username, id)
+}
+
+func (s *CachedService) CancelPaymentRequest(ctx context.Context, username string, id int) (*storage.PaymentRequest, error) {
+	return s.next.CancelPaymentRequest(ctx, username, id)
+}
+
+func (s *CachedService) ScheduleTransfer(ctx context.Context, fromUsername string, in *ScheduledTransferInput) (*storage.ScheduledTransfer, error) {
+	return s.next.ScheduleTransfer(ctx, fromUsername, in)
+}
+
+func (s *CachedService) ListScheduledTransfers(ctx context.Context, username string) ([]storage.ScheduledTransfer, error) {
+	return s.next.ListScheduledTransfers(ctx, username)
+}
+
+func (s *CachedService) CancelScheduledTransfer(ctx context.Context, username string, id int) (*storage.ScheduledTransfer, error) {
+	return s.next.CancelScheduledTransfer(ctx, username, id)
+}
+
+// RunDueTransfers сбрасывает кэш обеих сторон каждого выполненного перевода.
+func (s *CachedService) RunDueTransfers(ctx context.Context) ([]storage.ScheduledTransfer, error) {
+	executed, err := s.next.RunDueTransfers(ctx)
+	for _, st := range executed {
+		s.Invalidate(st.Sender, st.Recipient)
+	}
+	return executed, err
+}
+
+// ExpireCoins сбрасывает кэш пользователей, у которых сгорели монеты.
+func (s *CachedService) ExpireCoins(ctx context.Context) ([]storage.ExpiredCoins, error) {
+	burned, err := s.next.ExpireCoins(ctx)
+	for _, e := range burned {
+		s.Invalidate(e.Username)
+	}
+	return burned, err
+}
+
+func (s *CachedService) CreatePromoCode(ctx context.Context, admin string, in *PromoCodeInput) (*storage.PromoCode, error) {
+	return s.next.CreatePromoCode(ctx, admin, in)
+}
+
+func (s *CachedService) ListPromoCodes(ctx context.Context, admin string) ([]storage.PromoCode, error) {
+	return s.next.ListPromoCodes(ctx, admin)
+}
+
+func (s *CachedService) DisablePromoCode(ctx context.Context, admin, code string) (*storage.PromoCode, error) {
+	return s.next.DisablePromoCode(ctx, admin, code)
+}
+
+func (s *CachedService) Catalog(ctx context.Context) ([]storage.CatalogItem, error) {
+	return s.next.Catalog(ctx)
+}
+
+func (s *CachedService) ListOrders(ctx context.Context, username string) ([]storage.Order, error) {
+	return s.next.ListOrders(ctx, username)
+}
+
+func (s *CachedService) ScheduleItemPrice(ctx context.Context, admin string, in *ItemPriceInput) (*storage.ItemPrice, error) {
+	return s.next.ScheduleItemPrice(ctx, admin, in)
+}
+
+func (s *CachedService) ListItemPrices(ctx context.Context, admin string) ([]storage.ItemPrice, error) {
+	return s.next.ListItemPrices(ctx, admin)
+}
+
+func (s *CachedService) CancelItemPrice(ctx context.Context, admin string, id int) (*storage.ItemPrice, error) {
+	return s.next.CancelItemPrice(ctx, admin, id)
+}
+
+func (s *CachedService) Leaderboard(ctx context.Context, board, period string, limit int) (*storage.Leaderboard, error) {
+	return s.next.Leaderboard(ctx, board, period, limit)
+}
+
+func (s *CachedService) SetLeaderboardOptOut(ctx context.Context, username string, optOut bool) error {
+	return s.next.SetLeaderboardOptOut(ctx, username, optOut)
+}
+
+func (s *CachedService) CreateGroup(ctx context.Context, username string, in *GroupInput) (*storage.Group, error) {
+	return s.next.CreateGroup(ctx, username, in)
+}
+
+func (s *CachedService) ListGroups(ctx context.Context, username string) ([]storage.Group, error) {
+	return s.next.ListGroups(ctx, username)
+}
+
+func (s *CachedService) GetGroup(ctx context.Context, username, name string) (*storage.GroupInfo, error) {
+	return s.next.GetGroup(ctx, username, name)
+}
+
+func (s *CachedService) GroupHistory(ctx context.Context, username, name string, filter storage.HistoryFilter) (*storage.CoinHistory, error) {
+	return s.next.GroupHistory(ctx, username, name, filter)
+}
+
+func (s *CachedService) SetGroupMember(ctx context.Context, username, name string, in *GroupMemberInput) ([]storage.GroupMember, error) {
+	return s.next.SetGroupMember(ctx, username, name, in)
+}
+
+func (s *CachedService) RemoveGroupMember(ctx context.Context, username, name, member string) ([]storage.GroupMember, error) {
+	return s.next.RemoveGroupMember(ctx, username, name, member)
+}
+
+// SendFromGroup сбрасывает кэш получателя; под кошельком группы никто не входит, поэтому он не кэшируется.
+func (s *CachedService) SendFromGroup(ctx context.Context, username, name string, scr *storage.SendCoinRequest) error {
+	defer s.Invalidate(scr.ToUser)
+	return s.next.SendFromGroup(ctx, username, name, scr)
+}
+
+func (s *CachedService) ProposeGroupPurchase(ctx context.Context, username, name, item string) (*storage.GroupPurchase, error) {
+	return s.next.ProposeGroupPurchase(ctx, username, name, item)
+}
+
+func (s *CachedService) ApproveGroupPurchase(ctx context.Context, username, name string, id int) (*storage.GroupPurchase, error) {
+	return s.next.ApproveGroupPurchase(ctx, username, name, id)
+}
+
+func (s *CachedService) CancelGroupPurchase(ctx context.Context, username, name string, id int) (*storage.GroupPurchase, error) {
+	return s.next.CancelGroupPurchase(ctx, username, name, id)
+}
+
+func (s *CachedService) ListGroupPurchases(ctx context.Context, username, name string) ([]storage.GroupPurchase, error) {
+	return s.next.ListGroupPurchases(ctx, username, name)
+}
+
+func (s *CachedService) ListPendingTransfers(ctx context.Context, username string) ([]storage.PendingTransfer, error) {
+	return s.next.ListPendingTransfers(ctx, username)
+}
+
+// ApprovePendingTransfer зачисляет удержанные монеты получателю, поэтому сбрасывает кэш обеих сторон.
+// Перевод, истёкший к моменту одобрения, сервис возвращает вместе с ErrPendingTransferExpired:
+// удержание тогда вернулось отправителю, и его кэш тоже устарел.
+func (s *CachedService) ApprovePendingTransfer(ctx context.Context, approver string, id int) (*storage.PendingTransfer, error) {
+	pt, err := s.next.ApprovePendingTransfer(ctx, approver, id)
+	if pt != nil {
+		s.Invalidate(pt.Sender, pt.Recipient)
+	}
+	return pt, err
+}
+
+// RejectPendingTransfer возвращает удержание отправителю и сбрасывает его кэш,
+// в том числе когда перевод истёк и вернулся вместе с ErrPendingTransferExpired.
+func (s *CachedService) RejectPendingTransfer(ctx context.Context, approver string, id int) (*storage.PendingTransfer, error) {
+	pt, err := s.next.RejectPendingTransfer(ctx, approver, id)
+	if pt != nil {
+		s.Invalidate(pt.Sender)
+	}
+	return pt, err
+}
+
+// ExpirePendingTransfers сбрасывает кэш отправителей, которым вернулось удержание.
+func (s *CachedService) ExpirePendingTransfers(ctx context.Context) ([]storage.PendingTransfer, error) {
+	expired, err := s.next.ExpirePendingTransfers(ctx)
+	for _, pt := range expired {
+		s.Invalidate(pt.Sender)
+	}
+	return expired, err
+}
+
+func (s *CachedService) ListUserTransactions(ctx context.Context, admin, username string) ([]storage.Transaction, error) {
+	return s.next.ListUserTransactions(ctx, admin, username)
+}
+
+// ReverseTransfer возвращает монеты от получателя отправителю и сбрасывает кэш обоих.
+func (s *CachedService) ReverseTransfer(ctx context.Context, admin string, in *ReversalInput) (*storage.TransferReversal, error) {
+	rv, err := s.next.ReverseTransfer(ctx, admin, in)
+	if rv != nil {
+		s.Invalidate(rv.FromUser, rv.ToUser)
+	}
+	return rv, err
+}
+
+func (s *CachedService) RecordAuth(ctx context.Context, username, action string) {
+	s.next.RecordAuth(ctx, username, action)
+}
+
+func (s *CachedService) AuditLog(ctx context.Context, admin string, filter storage.AuditFilter) ([]storage.AuditEntry, error) {
+	return s.next.AuditLog(ctx, admin, filter)
+}
+
+func (s *CachedService) ExportAuditLog(ctx context.Context, admin string, filter storage.AuditFilter,
+	write func([]storage.AuditEntry) error) error {
+	return s.next.ExportAuditLog(ctx, admin, filter, write)
+}
+
+func (s *CachedService) VerifyAuditLog(ctx context.Context, admin string) (*AuditVerification, error) {
+	return s.next.VerifyAuditLog(ctx, admin)
+}
+
+// Invalidate сбрасывает закэшированную информацию пользователей. Её должна вызывать
+// каждая операция, начисляющая или списывающая монеты в обход Send и Purchase.
+func (s *CachedService) Invalidate(usernames ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.epoch++
+	s.cache.Delete(usernames...)
+}
+
+// cloneInfo копирует ответ, чтобы вызывающий код не мог изменить запись в кэше.
+func cloneInfo(ir *storage.InfoResponse) *storage.InfoResponse {
+	c := *ir
+	if ir.Inventory != nil {
+		c.Inventory = append([]storage.Inventory(nil), ir.Inventory...)
+	}
+	if ir.CoinHistory.Received != nil {
+		c.CoinHistory.Received = append([]storage.TransactionIn(nil), ir.CoinHistory.Received...)
+	}
+	if ir.CoinHistory.Sent != nil {
+		c.CoinHistory.Sent = append([]storage.TransactionOut(nil), ir.CoinHistory.Sent...)
+	}
+	if ir.Expiring != nil {
+		c.Expiring = append([]storage.CoinExpiration(nil), ir.Expiring...)
+	}
+	if ir.Achievements != nil {
+		c.Achievements = append([]storage.Achievement(nil), ir.Achievements...)
+	}
+	return &c
+}
@@ -0,0 +1,200 @@
+package shop
+
+import (
+	"context"
+	"testing"
+
+	"avito-shop/internal/cache"
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCachedTestService() (*CachedService, *IServiceMock) {
+	next := &IServiceMock{
+		CollectAllInfoFunc: func(ctx context.Context, username string) (*storage.InfoResponse, error) {
+			return &storage.InfoResponse{Coins: 1000, Inventory: []storage.Inventory{{Type: "cup", Quantity: 1}}}, nil
+		},
+		SendFunc: func(ctx context.Context, fromUsername string, scr *storage.SendCoinRequest) error {
+			return nil
+		},
+		PurchaseFunc: func(ctx context.Context, username, item, promoCode string) error {
+			return nil
+		},
+		RunDueTransfersFunc: func(ctx context.Context) ([]storage.ScheduledTransfer, error) {
+			return []storage.ScheduledTransfer{{ID: 1, Sender: "alice", Recipient: "bob", Amount: 10}}, nil
+		},
+		ExpireCoinsFunc: func(ctx context.Context) ([]storage.ExpiredCoins, error) {
+			return []storage.ExpiredCoins{{UserID: 3, Username: "carol", Amount: 100}}, nil
+		},
+		ApprovePendingTransferFunc: func(ctx context.Context, approver string, id int) (*storage.PendingTransfer, error) {
+			return &storage.PendingTransfer{ID: id, Sender: "alice", Recipient: "bob", Status: storage.PendingTransferApproved}, nil
+		},
+		RejectPendingTransferFunc: func(ctx context.Context, approver string, id int) (*storage.PendingTransfer, error) {
+			return &storage.PendingTransfer{ID: id, Sender: "alice", Recipient: "bob", Status: storage.PendingTransferExpired},
+				ErrPendingTransferExpired
+		},
+		ReverseTransferFunc: func(ctx context.Context, admin string, in *ReversalInput) (*storage.TransferReversal, error) {
+			return &storage.TransferReversal{TransactionID: in.TransactionID, FromUser: "alice", ToUser: "bob"}, nil
+		},
+	}
+	return NewCachedService(next, cache.NewLRU[*storage.InfoResponse](16, 0)), next
+}
+
+func TestCachedService_CollectAllInfo(t *testing.T) {
+	ctx := context.Background()
+	s, next := newCachedTestService()
+
+	first, err := s.CollectAllInfo(ctx, "alice")
+	require.NoError(t, err)
+	first.Inventory[0].Quantity = 100
+
+	second, err := s.CollectAllInfo(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1, second.Inventory[0].Quantity, "cached entry must not be shared with callers")
+	assert.Len(t, next.CollectAllInfoCalls(), 1)
+
+	_, err = s.CollectAllInfo(ctx, "bob")
+	require.NoError(t, err)
+	assert.Len(t, next.CollectAllInfoCalls(), 2)
+}
+
+func TestCachedService_Invalidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		write       func(s *CachedService) error
+		invalidated []string
+		kept        []string
+	}{
+		{
+			name: "Send invalidates both sides",
+			write: func(s *CachedService) error {
+				return s.Send(context.Background(), "alice", &storage.SendCoinRequest{ToUser: "bob", Amount: 10})
+			},
+			invalidated: []string{"alice", "bob"},
+			kept:        []string{"carol"},
+		},
+		{
+			name: "Failed Send still invalidates",
+			write: func(s *CachedService) error {
+				return s.Send(context.Background(), "alice", &storage.SendCoinRequest{ToUser: "bob", Amount: 10000})
+			},
+			invalidated: []string{"alice", "bob"},
+			kept:        []string{"carol"},
+		},
+		{
+			name: "Purchase invalidates buyer",
+			write: func(s *CachedService) error {
+				return s.Purchase(context.Background(), "alice", "cup", "")
+			},
+			invalidated: []string{"alice"},
+			kept:        []string{"bob", "carol"},
+		},
+		{
+			name: "Scheduled run invalidates both sides",
+			write: func(s *CachedService) error {
+				_, err := s.RunDueTransfers(context.Background())
+				return err
+			},
+			invalidated: []string{"alice", "bob"},
+			kept:        []string{"carol"},
+		},
+		{
+			name: "Expiry invalidates users with burned coins",
+			write: func(s *CachedService) error {
+				_, err := s.ExpireCoins(context.Background())
+				return err
+			},
+			invalidated: []string{"carol"},
+			kept:        []string{"alice", "bob"},
+		},
+		{
+			name: "Approved transfer invalidates both sides",
+			write: func(s *CachedService) error {
+				_, err := s.ApprovePendingTransfer(context.Background(), "carol", 4)
+				return err
+			},
+			invalidated: []string{"alice", "bob"},
+			kept:        []string{"carol"},
+		},
+		{
+			name: "Transfer expired on access invalidates sender",
+			write: func(s *CachedService) error {
+				_, err := s.RejectPendingTransfer(context.Background(), "carol", 4)
+				return err
+			},
+			invalidated: []string{"alice"},
+			kept:        []string{"bob", "carol"},
+		},
+		{
+			name: "Reversal invalidates both sides",
+			write: func(s *CachedService) error {
+				_, err := s.ReverseTransfer(context.Background(), "carol", &ReversalInput{TransactionID: 7})
+				return err
+			},
+			invalidated: []string{"alice", "bob"},
+			kept:        []string{"carol"},
+		},
+		{
+			name: "Invalidate for grants",
+			write: func(s *CachedService) error {
+				s.Invalidate("bob", "carol")
+				return nil
+			},
+			invalidated: []string{"bob", "carol"},
+			kept:        []string{"alice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, next := newCachedTestService()
+			next.SendFunc = func(ctx context.Context, fromUsername string, scr *storage.SendCoinRequest) error {
+				if scr.Amount > 1000 {
+					return ErrInsufficientFunds
+				}
+				return nil
+			}
+			for _, u := range []string{"alice", "bob", "carol"} {
+				_, err := s.CollectAllInfo(ctx, u)
+				require.NoError(t, err)
+			}
+
+			_ = tt.write(s)
+
+			calls := len(next.CollectAllInfoCalls())
+			for _, u := range tt.kept {
+				_, err := s.CollectAllInfo(ctx, u)
+				require.NoError(t, err)
+			}
+			assert.Len(t, next.CollectAllInfoCalls(), calls, "kept users must be served from cache")
+
+			for _, u := range tt.invalidated {
+				_, err := s.CollectAllInfo(ctx, u)
+				require.NoError(t, err)
+			}
+			assert.Len(t, next.CollectAllInfoCalls(), calls+len(tt.invalidated))
+		})
+	}
+}
+
+func TestCachedService_SkipsStaleFill(t *testing.T) {
+	ctx := context.Background()
+	s, next := newCachedTestService()
+
+	// Перевод завершается, пока CollectAllInfo читает БД: прочитанный ответ мог устареть и не должен попасть в кэш.
+	next.CollectAllInfoFunc = func(ctx context.Context, username string) (*storage.InfoResponse, error) {
+		if len(next.SendCalls()) == 0 {
+			require.NoError(t, s.Send(ctx, "bob", &storage.SendCoinRequest{ToUser: username, Amount: 10}))
+		}
+		return &storage.InfoResponse{Coins: 1000}, nil
+	}
+
+	_, err := s.CollectAllInfo(ctx, "alice")
+	require.NoError(t, err)
+	_, err = s.CollectAllInfo(ctx, "alice")
+	require.NoError(t, err)
+	assert.Len(t, next.CollectAllInfoCalls(), 2)
+}
@@ -0,0 +1,173 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ItemPriceInput — параметры запланированной цены, см. storage.ItemPrice. Пустой StartsAt — с текущего момента.
+type ItemPriceInput struct {
+	Item     string
+	Price    int
+	StartsAt *time.Time
+	EndsAt   *time.Time
+}
+
+// Catalog возвращает предметы магазина, упорядоченные по названию, с ценами на текущий момент.
+func (s *Service) Catalog(ctx context.Context) (_ []storage.CatalogItem, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.Catalog")
+	defer func() { tracing.End(span, err) }()
+
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]storage.CatalogItem, 0, len(catalog))
+	for _, item := range catalog {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items, nil
+}
+
+// ListOrders возвращает покупки пользователя в порядке совершения.
+func (s *Service) ListOrders(ctx context.Context, username string) (_ []storage.Order, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ListOrders")
+	defer func() { tracing.End(span, err) }()
+
+	id, err := s.userID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := s.Storage.ListOrders(ctx, id)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+	return orders, nil
+}
+
+func (s *Service) ScheduleItemPrice(ctx context.Context, admin string, in *ItemPriceInput) (_ *storage.ItemPrice, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ScheduleItemPrice", trace.WithAttributes(attribute.String("shop.item", in.Item)))
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.requireAdmin(ctx, admin); err != nil {
+		return nil, err
+	}
+	now := s.now()
+	if in.StartsAt == nil {
+		in.StartsAt = &now
+	}
+	if err = ValidateItemPriceInput(in); err != nil {
+		return nil, err
+	}
+
+	p := &storage.ItemPrice{
+		Item:      in.Item,
+		Price:     in.Price,
+		StartsAt:  *utcTime(in.StartsAt),
+		EndsAt:    utcTime(in.EndsAt),
+		CreatedAt: now,
+	}
+	if err = s.Storage.CreateItemPrice(ctx, p); err != nil {
+		return nil, ErrInternalServer
+	}
+	s.audit(ctx, admin, AuditPriceSchedule, auditTarget("item_price", p.ID), nil, p)
+	return p, nil
+}
+
+// ListItemPrices возвращает действующие и будущие цены в порядке начала действия.
+func (s *Service) ListItemPrices(ctx context.Context, admin string) (_ []storage.ItemPrice, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ListItemPrices")
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.requireAdmin(ctx, admin); err != nil {
+		return nil, err
+	}
+	prices, err := s.Storage.ListItemPrices(ctx, s.now())
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+	return prices, nil
+}
+
+// CancelItemPrice удаляет запланированную цену; если она уже действует, предмет возвращается
+// к предыдущей цене. Заказы, сделанные по ней, не меняются.
+func (s *Service) CancelItemPrice(ctx context.Context, admin string, id int) (_ *storage.ItemPrice, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.CancelItemPrice")
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.requireAdmin(ctx, admin); err != nil {
+		return nil, err
+	}
+	p, err := s.Storage.GetItemPrice(ctx, id)
+	if err == nil {
+		err = s.Storage.DeleteItemPrice(ctx, id)
+	}
+	if err != nil {
+		if errors.Is(err, storage.ErrItemPriceNotFound) {
+			return nil, ErrItemPriceNotFound
+		}
+		return nil, ErrInternalServer
+	}
+	s.audit(ctx, admin, AuditPriceCancel, auditTarget("item_price", p.ID), p, nil)
+	return p, nil
+}
+
+// catalogItem возвращает предмет каталога с ценой на текущий момент или ErrItemNotFound.
+func (s *Service) catalogItem(ctx context.Context, item string) (storage.CatalogItem, error) {
+	if _, exists := storage.MerchItems[item]; !exists {
+		return storage.CatalogItem{}, ErrItemNotFound
+	}
+	catalog, err := s.catalog(ctx)
+	if err != nil {
+		return storage.CatalogItem{}, err
+	}
+	return catalog[item], nil
+}
+
+func (s *Service) catalog(ctx context.Context) (map[string]storage.CatalogItem, error) {
+	now := s.now()
+	prices, err := s.Storage.ListItemPrices(ctx, now)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+	return resolveCatalog(prices, now), nil
+}
+
+// resolveCatalog считает цены предметов на момент now. Обычная цена — базовая из storage.MerchItems,
+// заменённая последним начавшимся постоянным изменением; действующая распродажа, начавшаяся последней,
+// заменяет её до своего окончания. prices должны быть упорядочены по началу действия.
+func resolveCatalog(prices []storage.ItemPrice, now time.Time) map[string]storage.CatalogItem {
+	catalog := make(map[string]storage.CatalogItem, len(storage.MerchItems))
+	for name, price := range storage.MerchItems {
+		catalog[name] = storage.CatalogItem{Name: name, Price: price, RegularPrice: price}
+	}
+
+	sales := make(map[string]storage.ItemPrice)
+	for _, p := range prices {
+		item, ok := catalog[p.Item]
+		if !ok || p.StartsAt.After(now) || p.EndsAt != nil && !now.Before(*p.EndsAt) {
+			continue
+		}
+		if p.IsSale() {
+			sales[p.Item] = p
+			continue
+		}
+		item.Price, item.RegularPrice, item.PriceID = p.Price, p.Price, p.ID
+		catalog[p.Item] = item
+	}
+	for name, sale := range sales {
+		item := catalog[name]
+		item.Price, item.SaleEndsAt, item.PriceID = sale.Price, sale.EndsAt, sale.ID
+		catalog[name] = item
+	}
+	return catalog
+}
@@ -0,0 +1,143 @@
+package shop
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveCatalog(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		v := now.Add(d)
+		return &v
+	}
+
+	tests := []struct {
+		name   string
+		prices []storage.ItemPrice
+		want   storage.CatalogItem
+	}{
+		{
+			name: "Base price without schedules",
+			want: storage.CatalogItem{Name: "hoody", Price: 300, RegularPrice: 300},
+		},
+		{
+			name: "Latest started price change wins",
+			prices: []storage.ItemPrice{
+				{ID: 1, Item: "hoody", Price: 350, StartsAt: now.Add(-48 * time.Hour)},
+				{ID: 2, Item: "hoody", Price: 280, StartsAt: now.Add(-time.Hour)},
+				{ID: 3, Item: "hoody", Price: 999, StartsAt: now.Add(time.Hour)},
+			},
+			want: storage.CatalogItem{Name: "hoody", Price: 280, RegularPrice: 280, PriceID: 2},
+		},
+		{
+			name: "Sale overrides a later price change until it ends",
+			prices: []storage.ItemPrice{
+				{ID: 1, Item: "hoody", Price: 150, StartsAt: now.Add(-2 * time.Hour), EndsAt: at(time.Hour)},
+				{ID: 2, Item: "hoody", Price: 320, StartsAt: now.Add(-time.Hour)},
+			},
+			want: storage.CatalogItem{Name: "hoody", Price: 150, RegularPrice: 320, SaleEndsAt: at(time.Hour), PriceID: 1},
+		},
+		{
+			name: "Ended and upcoming sales are ignored",
+			prices: []storage.ItemPrice{
+				{Item: "hoody", Price: 100, StartsAt: now.Add(-2 * time.Hour), EndsAt: at(0)},
+				{Item: "hoody", Price: 200, StartsAt: now.Add(time.Hour), EndsAt: at(2 * time.Hour)},
+			},
+			want: storage.CatalogItem{Name: "hoody", Price: 300, RegularPrice: 300},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog := resolveCatalog(tt.prices, now)
+			assert.Len(t, catalog, len(storage.MerchItems))
+			assert.Equal(t, tt.want, catalog["hoody"])
+			assert.Equal(t, storage.CatalogItem{Name: "cup", Price: 20, RegularPrice: 20}, catalog["cup"])
+		})
+	}
+}
+
+func TestPurchase_UsesSalePrice(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	saleEnd := now.Add(time.Hour)
+	mockStorage := &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		ListItemPricesFunc: func(ctx context.Context, at time.Time) ([]storage.ItemPrice, error) {
+			return []storage.ItemPrice{{ID: 7, Item: "hoody", Price: 120, StartsAt: now.Add(-time.Hour), EndsAt: &saleEnd}}, nil
+		},
+		GetInfoFunc: func(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+			ir.Coins = 120
+			return 1, nil
+		},
+		BuyItemFunc: func(ctx context.Context, name, item string, amount, priceID int, audit *storage.TxAudit) error {
+			return nil
+		},
+	}
+	service := NewService(mockStorage)
+	service.now = func() time.Time { return now }
+
+	require.NoError(t, service.Purchase(context.Background(), "alice", "hoody", ""))
+	require.Len(t, mockStorage.BuyItemCalls(), 1)
+	assert.Equal(t, 120, mockStorage.BuyItemCalls()[0].Amount)
+	assert.Equal(t, 7, mockStorage.BuyItemCalls()[0].PriceID, "the order records which price was charged")
+
+	items, err := service.Catalog(context.Background())
+	require.NoError(t, err)
+	require.Len(t, items, len(storage.MerchItems))
+	assert.Equal(t, "book", items[0].Name)
+	for _, item := range items {
+		if item.Name == "hoody" {
+			assert.Equal(t, storage.CatalogItem{Name: "hoody", Price: 120, RegularPrice: 300, SaleEndsAt: &saleEnd, PriceID: 7}, item)
+		}
+	}
+}
+
+func TestScheduleItemPrice(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	mockStorage := &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		GetInfoFunc: func(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+			return 1, nil
+		},
+		GetUserRoleFunc: func(ctx context.Context, userID int) (string, error) {
+			return storage.AdminRole, nil
+		},
+		CreateItemPriceFunc: func(ctx context.Context, p *storage.ItemPrice) error {
+			p.ID = 3
+			return nil
+		},
+	}
+	service := NewService(mockStorage)
+	service.now = func() time.Time { return now }
+
+	p, err := service.ScheduleItemPrice(context.Background(), "admin", &ItemPriceInput{Item: "cup", Price: 15})
+	require.NoError(t, err)
+	assert.Equal(t, &storage.ItemPrice{ID: 3, Item: "cup", Price: 15, StartsAt: now, CreatedAt: now}, p)
+
+	tests := []struct {
+		name  string
+		in    *ItemPriceInput
+		field string
+	}{
+		{"Unknown item", &ItemPriceInput{Item: "dragon", Price: 15}, "item"},
+		{"Non-positive price", &ItemPriceInput{Item: "cup", Price: 0}, "price"},
+		{"Sale ends before it starts", &ItemPriceInput{Item: "cup", Price: 15, EndsAt: &now}, "endsAt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ScheduleItemPrice(context.Background(), "admin", tt.in)
+			var ve *ValidationError
+			require.ErrorAs(t, err, &ve)
+			require.Len(t, ve.Fields, 1)
+			assert.Equal(t, tt.field, ve.Fields[0].Field)
+		})
+	}
+	assert.Len(t, mockStorage.CreateItemPriceCalls(), 1)
+}
@@ -7,4 +7,44 @@ var (
 	ErrInsufficientFunds = errors.New("недостаточно средств")
 	ErrInternalServer    = errors.New("внутренняя ошибка сервера")
 	ErrUserNotFound      = errors.New("пользователь не найден")
+	ErrValidation        = errors.New("некорректные данные запроса")
+	ErrLimitExceeded     = errors.New("превышен лимит переводов")
+
+	ErrPaymentRequestNotFound   = errors.New("запрос на перевод не найден")
+	ErrPaymentRequestNotPending = errors.New("запрос на перевод уже закрыт")
+	ErrPaymentRequestExpired    = errors.New("срок действия запроса на перевод истёк")
+
+	ErrScheduledTransferNotFound  = errors.New("отложенный перевод не найден")
+	ErrScheduledTransferNotActive = errors.New("отложенный перевод уже выполнен или отменён")
+
+	ErrForbidden = errors.New("недостаточно прав")
+
+	ErrPromoCodeNotFound      = errors.New("промокод не найден")
+	ErrPromoCodeExists        = errors.New("промокод уже существует")
+	ErrPromoCodeInactive      = errors.New("промокод не действует")
+	ErrPromoCodeNotApplicable = errors.New("промокод не действует для этого предмета")
+	ErrPromoCodeUsedUp        = errors.New("промокод исчерпан")
+	ErrPromoCodeAlreadyUsed   = errors.New("промокод уже использован")
+
+	ErrItemPriceNotFound = errors.New("запланированная цена не найдена")
+
+	ErrLeaderboardNotFound = errors.New("таблица лидеров не найдена")
+
+	ErrGroupNotFound                = errors.New("группа не найдена")
+	ErrGroupExists                  = errors.New("имя группы уже занято")
+	ErrNotGroupMember               = errors.New("пользователь не состоит в группе")
+	ErrLastGroupAdmin               = errors.New("в группе должен остаться хотя бы один администратор")
+	ErrGroupPurchaseNotFound        = errors.New("покупка группы не найдена")
+	ErrGroupPurchaseNotPending      = errors.New("покупка группы уже выполнена или отменена")
+	ErrGroupPurchaseAlreadyApproved = errors.New("покупка уже одобрена этим участником")
+
+	ErrApprovalRequired          = errors.New("перевод ждёт одобрения")
+	ErrPendingTransferNotFound   = errors.New("перевод на одобрении не найден")
+	ErrPendingTransferNotPending = errors.New("перевод уже одобрен, отклонён или истёк")
+	ErrPendingTransferExpired    = errors.New("срок одобрения перевода истёк")
+
+	ErrTransactionNotFound        = errors.New("перевод не найден")
+	ErrTransactionNotReversible   = errors.New("запись нельзя отменить: это не перевод между пользователями")
+	ErrTransactionAlreadyReversed = errors.New("перевод уже отменён")
+	ErrNothingToReverse           = errors.New("у получателя не осталось монет, чтобы вернуть")
 )
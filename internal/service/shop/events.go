@@ -0,0 +1,104 @@
+package shop
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Типы событий, которые сервис публикует в Service.Events.
+const (
+	// EventTransferPending — перевод удержан до одобрения.
+	EventTransferPending = "transfer.pending"
+	// EventTransferApproved — удержанный перевод одобрен и выполнен.
+	EventTransferApproved = "transfer.approved"
+	// EventTransferRejected — удержанный перевод отклонён, монеты вернулись отправителю.
+	EventTransferRejected = "transfer.rejected"
+	// EventTransferExpired — удержанный перевод не одобрили вовремя, монеты вернулись отправителю.
+	EventTransferExpired = "transfer.expired"
+	// EventTransferReversed — администратор отменил перевод, монеты вернулись отправителю.
+	EventTransferReversed = "transfer.reversed"
+	// EventTransferCompleted — монеты зачислены получателю: перевод, оплата запроса, запуск
+	// отложенного перевода или одобрение удержанного. Data — *TransferEvent.
+	EventTransferCompleted = "transfer.completed"
+	// EventPurchaseCompleted — предмет куплен пользователем или кошельком группы. Data — *PurchaseEvent.
+	EventPurchaseCompleted = "purchase.completed"
+)
+
+// Event — уведомление о произошедшем в магазине. Users — кого событие касается,
+// Data — его содержимое (например, *storage.PendingTransfer).
+type Event struct {
+	Type  string
+	At    time.Time
+	Users []string
+	Data  any
+}
+
+// TransferEvent — данные EventTransferCompleted: Amount монет перешли от From к To, Fee списана сверху.
+type TransferEvent struct {
+	FromID int    `json:"-"`
+	From   string `json:"from"`
+	ToID   int    `json:"-"`
+	To     string `json:"to"`
+	Amount int    `json:"amount"`
+	Fee    int    `json:"fee,omitempty"`
+}
+
+// PurchaseEvent — данные EventPurchaseCompleted: Buyer купил Item за Price монет.
+type PurchaseEvent struct {
+	BuyerID int    `json:"-"`
+	Buyer   string `json:"buyer"`
+	Item    string `json:"item"`
+	Price   int    `json:"price"`
+}
+
+// EventSink получает события сервиса. Publish вызывается синхронно после записи
+// изменений, поэтому не должен блокироваться надолго; ошибки доставки остаются на стороне получателя.
+type EventSink interface {
+	Publish(ctx context.Context, e Event)
+}
+
+// LogEventSink пишет события в лог.
+type LogEventSink struct {
+	log *slog.Logger
+}
+
+func NewLogEventSink(log *slog.Logger) *LogEventSink {
+	return &LogEventSink{log: log}
+}
+
+func (l *LogEventSink) Publish(ctx context.Context, e Event) {
+	l.log.InfoContext(ctx, "Event", slog.String("type", e.Type), slog.Time("at", e.At),
+		slog.Any("users", e.Users), slog.Any("data", e.Data))
+}
+
+// subscribe добавляет внутреннего получателя событий, например выдачу достижений.
+// Внутренние получатели получают каждое событие раньше Events.
+func (s *Service) subscribe(sink EventSink) {
+	s.subscribers = append(s.subscribers, sink)
+}
+
+// publish отправляет событие внутренним получателям и в Events, если он задан.
+func (s *Service) publish(ctx context.Context, typ string, data any, users ...string) {
+	if s.Events == nil && len(s.subscribers) == 0 {
+		return
+	}
+	e := Event{Type: typ, At: s.now(), Users: users, Data: data}
+	for _, sink := range s.subscribers {
+		sink.Publish(ctx, e)
+	}
+	if s.Events != nil {
+		s.Events.Publish(ctx, e)
+	}
+}
+
+// publishTransfer публикует EventTransferCompleted; вызывается из каждого пути, который
+// зачисляет монеты получателю перевода.
+func (s *Service) publishTransfer(ctx context.Context, fromID int, from string, toID int, to string, amount, fee int) {
+	s.publish(ctx, EventTransferCompleted, &TransferEvent{FromID: fromID, From: from, ToID: toID, To: to, Amount: amount, Fee: fee}, from, to)
+}
+
+// publishPurchase публикует EventPurchaseCompleted; вызывается из каждого пути, который покупает предмет.
+func (s *Service) publishPurchase(ctx context.Context, buyerID int, buyer, item string, price int) {
+	s.publish(ctx, EventPurchaseCompleted, &PurchaseEvent{BuyerID: buyerID, Buyer: buyer, Item: item, Price: price}, buyer)
+}
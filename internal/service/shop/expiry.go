@@ -0,0 +1,132 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"avito-shop/internal/config"
+	"avito-shop/internal/metrics"
+	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/tracing"
+)
+
+// expiredCoinsBatch — сколько пользователей с просроченными монетами обрабатывается за один проход.
+const expiredCoinsBatch = 100
+
+// EnableCoinExpiry включает сгорание монет и создаёт служебный счёт для сгоревших монет, если его ещё нет.
+func (s *Service) EnableCoinExpiry(ctx context.Context, expiry config.CoinExpiry) error {
+	const op = "shop.Service.EnableCoinExpiry"
+
+	id, err := s.Storage.EnsureSystemAccount(ctx, expiry.Account)
+	if err != nil {
+		return fmt.Errorf("%v: account %q: %w", op, expiry.Account, err)
+	}
+
+	s.Expiry = expiry
+	s.expiryAccountID = id
+	return nil
+}
+
+// ExpireCoins сжигает партии монет старше Expiry.TTL и возвращает, у кого и сколько сгорело.
+// Партии, которые успел сжечь другой исполнитель, пропускаются; ошибки отдельных
+// пользователей не прерывают проход.
+func (s *Service) ExpireCoins(ctx context.Context) (_ []storage.ExpiredCoins, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ExpireCoins")
+	defer func() { tracing.End(span, err) }()
+
+	if s.expiryAccountID == 0 {
+		return nil, nil
+	}
+
+	now := s.now()
+	cutoff := now.Add(-s.Expiry.TTL)
+	due, err := s.Storage.ListExpiredCoins(ctx, cutoff, expiredCoinsBatch)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	var (
+		burned []storage.ExpiredCoins
+		errs   []error
+	)
+	for _, e := range due {
+		audit := s.txAudit(ctx, AuditSystem, AuditCoinsExpire, auditTarget("user", e.Username), e.UserID)
+		amount, expireErr := s.Storage.ExpireCoins(ctx, e.UserID, s.expiryAccountID, cutoff, now, audit)
+		if expireErr != nil {
+			errs = append(errs, expireErr)
+			continue
+		}
+		if amount == 0 {
+			continue
+		}
+		e.Amount = amount
+		metrics.CoinsExpiredTotal.Add(float64(amount))
+		burned = append(burned, e)
+	}
+
+	return burned, errors.Join(errs...)
+}
+
+// coinExpirations возвращает предстоящие сгорания монет пользователя в пределах Expiry.Notice.
+// Ещё не сожжённые просроченные партии попадают в ответ со сроком в прошлом.
+func (s *Service) coinExpirations(ctx context.Context, userID int) ([]storage.CoinExpiration, error) {
+	lots, err := s.Storage.GetCoinLots(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var horizon time.Time
+	if s.Expiry.Notice > 0 {
+		horizon = s.now().Add(s.Expiry.Notice)
+	}
+	var res []storage.CoinExpiration
+	for _, l := range lots {
+		expiresAt := l.GrantedAt.UTC().Add(s.Expiry.TTL)
+		// Партии отсортированы по дате выдачи, поэтому дальше сгорания только позже.
+		if !horizon.IsZero() && expiresAt.After(horizon) {
+			break
+		}
+		res = append(res, storage.CoinExpiration{Amount: l.Amount, ExpiresAt: expiresAt})
+	}
+	return res, nil
+}
+
+// CoinExpirer периодически сжигает просроченные монеты. Как и Scheduler, его безопасно запускать
+// на всех репликах: партии пользователя сжигаются в транзакции под блокировкой его строки.
+type CoinExpirer struct {
+	service  IService
+	interval time.Duration
+	log      *slog.Logger
+}
+
+func NewCoinExpirer(service IService, interval time.Duration, log *slog.Logger) *CoinExpirer {
+	return &CoinExpirer{service: service, interval: interval, log: log}
+}
+
+// Run выполняет проход сразу и затем каждые interval, пока не отменён ctx.
+func (e *CoinExpirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.tick(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *CoinExpirer) tick(ctx context.Context) {
+	burned, err := e.service.ExpireCoins(ctx)
+	if err != nil && ctx.Err() == nil {
+		e.log.ErrorContext(ctx, "Failed to expire coins", slog.String("error", err.Error()))
+	}
+	if len(burned) > 0 {
+		e.log.InfoContext(ctx, "Expired coins burned", slog.Int("users", len(burned)))
+	}
+}
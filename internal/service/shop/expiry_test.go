@@ -0,0 +1,101 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"avito-shop/internal/config"
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testExpiry = config.CoinExpiry{Enabled: true, TTL: 365 * 24 * time.Hour, Notice: 30 * 24 * time.Hour, Account: "system"}
+
+func newExpiryTestService(t *testing.T, mockStorage *storage.IStorageMock, now time.Time) *Service {
+	mockStorage.EnsureSystemAccountFunc = func(ctx context.Context, username string) (int, error) {
+		assert.Equal(t, "system", username)
+		return 99, nil
+	}
+	service := NewService(mockStorage)
+	service.now = func() time.Time { return now }
+	require.NoError(t, service.EnableCoinExpiry(context.Background(), testExpiry))
+	return service
+}
+
+func TestExpireCoins(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	cutoff := now.Add(-testExpiry.TTL)
+
+	mockStorage := &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		ListExpiredCoinsFunc: func(ctx context.Context, gotCutoff time.Time, limit int) ([]storage.ExpiredCoins, error) {
+			assert.Equal(t, cutoff, gotCutoff)
+			return []storage.ExpiredCoins{
+				{UserID: 1, Username: "alice", Amount: 300},
+				{UserID: 2, Username: "bob", Amount: 50},
+				{UserID: 3, Username: "carol", Amount: 10},
+			}, nil
+		},
+		ExpireCoinsFunc: func(ctx context.Context, userID, sinkID int, gotCutoff, gotNow time.Time, audit *storage.TxAudit) (int, error) {
+			assert.Equal(t, 99, sinkID)
+			assert.Equal(t, cutoff, gotCutoff)
+			assert.Equal(t, now, gotNow)
+			switch userID {
+			case 1:
+				return 250, nil // часть партий потрачена после выборки
+			case 2:
+				return 0, nil // партии уже сжёг другой исполнитель
+			}
+			return 0, errors.New("db is down")
+		},
+	}
+	service := newExpiryTestService(t, mockStorage, now)
+
+	burned, err := service.ExpireCoins(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, []storage.ExpiredCoins{{UserID: 1, Username: "alice", Amount: 250}}, burned)
+	assert.Len(t, mockStorage.ExpireCoinsCalls(), 3, "one failure does not stop the pass")
+}
+
+func TestExpireCoins_Disabled(t *testing.T) {
+	service := NewService(&storage.IStorageMock{})
+
+	burned, err := service.ExpireCoins(context.Background())
+
+	require.NoError(t, err)
+	assert.Empty(t, burned)
+}
+
+func TestCollectAllInfo_Expirations(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	grantedAt := func(daysAgo int) time.Time { return now.Add(-testExpiry.TTL).AddDate(0, 0, daysAgo) }
+
+	mockStorage := &storage.IStorageMock{
+		GetFullInfoFunc: func(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+			ir.Coins = 1000
+			return 1, nil
+		},
+		GetCoinLotsFunc: func(ctx context.Context, userID int) ([]storage.CoinLot, error) {
+			assert.Equal(t, 1, userID)
+			return []storage.CoinLot{
+				{Amount: 100, GrantedAt: grantedAt(0)},
+				{Amount: 200, GrantedAt: grantedAt(30)},
+				{Amount: 700, GrantedAt: grantedAt(31)},
+			}, nil
+		},
+	}
+	service := newExpiryTestService(t, mockStorage, now)
+
+	res, err := service.CollectAllInfo(context.Background(), "alice")
+
+	require.NoError(t, err)
+	assert.Equal(t, []storage.CoinExpiration{
+		{Amount: 100, ExpiresAt: now},
+		{Amount: 200, ExpiresAt: now.AddDate(0, 0, 30)},
+	}, res.Expiring, "only expirations within the notice window are shown")
+}
@@ -0,0 +1,55 @@
+package shop
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"avito-shop/internal/config"
+	"avito-shop/internal/service/shop/storage"
+)
+
+// EnableFees включает комиссии за переводы и создаёт служебный счёт для них, если его ещё нет.
+func (s *Service) EnableFees(ctx context.Context, fees config.TransferFees) error {
+	const op = "shop.Service.EnableFees"
+
+	id, err := s.Storage.EnsureSystemAccount(ctx, fees.Account)
+	if err != nil {
+		return fmt.Errorf("%v: account %q: %w", op, fees.Account, err)
+	}
+
+	s.Fees = fees
+	s.feeAccountID = id
+	return nil
+}
+
+// transferFee возвращает комиссию за перевод amount от пользователя fromID.
+func (s *Service) transferFee(ctx context.Context, fromID, amount int) (storage.TransferFee, error) {
+	if !s.Fees.Enabled() || s.feeAccountID == 0 {
+		return storage.TransferFee{}, nil
+	}
+
+	if len(s.Fees.ExemptRoles) > 0 {
+		role, err := s.Storage.GetUserRole(ctx, fromID)
+		if err != nil {
+			return storage.TransferFee{}, ErrInternalServer
+		}
+		if slices.Contains(s.Fees.ExemptRoles, role) {
+			return storage.TransferFee{}, nil
+		}
+	}
+
+	return storage.TransferFee{AccountID: s.feeAccountID, Amount: feeAmount(s.Fees.For(amount), amount)}, nil
+}
+
+// feeAmount считает комиссию по policy. Процент переводится в сотые доли процента, чтобы
+// округление вверх не добавляло монету из-за ошибки float: 0.07% от 10000 — ровно 7, а не 8.
+func feeAmount(policy config.FeePolicy, amount int) int {
+	bp := int(math.Round(policy.Percent * 100))
+	fee := policy.Flat
+	if bp > 0 {
+		fee += (amount*bp + 9999) / 10000
+	}
+	return max(fee, 0)
+}
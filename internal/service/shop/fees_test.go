@@ -0,0 +1,195 @@
+package shop
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"avito-shop/internal/config"
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeAmount(t *testing.T) {
+	fees := config.TransferFees{
+		FeePolicy: config.FeePolicy{Flat: 1, Percent: 1},
+		Tiers: []config.FeeTier{
+			{From: 1000, FeePolicy: config.FeePolicy{Percent: 0.5}},
+			{From: 500, FeePolicy: config.FeePolicy{Flat: 2, Percent: 0.07}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		amount int
+		want   int
+	}{
+		{"Flat plus percent rounded up", 10, 2},
+		{"Percent of a round amount", 300, 4},
+		{"Tier from its lower bound", 500, 3},
+		{"Tier applies up to the next bound", 999, 3},
+		{"Highest matching tier wins", 10000, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, feeAmount(fees.For(tt.amount), tt.amount))
+		})
+	}
+
+	assert.Equal(t, 7, feeAmount(config.FeePolicy{Percent: 0.07}, 10000))
+}
+
+func TestSend_Fees(t *testing.T) {
+	fees := config.TransferFees{Account: "system", FeePolicy: config.FeePolicy{Percent: 10}, ExemptRoles: []string{"merchant"}}
+
+	tests := []struct {
+		name       string
+		fees       config.TransferFees
+		role       string
+		balance    int
+		amount     int
+		wantFee    storage.TransferFee
+		wantErr    error
+		wantCalled bool
+	}{
+		{
+			name:       "No fees configured",
+			balance:    100,
+			amount:     100,
+			wantCalled: true,
+		},
+		{
+			name:       "Fee charged on top of the amount",
+			fees:       fees,
+			role:       storage.DefaultRole,
+			balance:    110,
+			amount:     100,
+			wantFee:    storage.TransferFee{AccountID: 99, Amount: 10},
+			wantCalled: true,
+		},
+		{
+			name:    "Balance covers the amount but not the fee",
+			fees:    fees,
+			role:    storage.DefaultRole,
+			balance: 100,
+			amount:  100,
+			wantErr: ErrInsufficientFunds,
+		},
+		{
+			name:       "Exempt role pays no fee",
+			fees:       fees,
+			role:       "merchant",
+			balance:    100,
+			amount:     100,
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotFee storage.TransferFee
+			mockStorage := &storage.IStorageMock{
+				AppendAuditFunc: nopAppendAudit,
+				EnsureSystemAccountFunc: func(ctx context.Context, username string) (int, error) {
+					assert.Equal(t, "system", username)
+					return 99, nil
+				},
+				GetInfoFunc: func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+					if username == "alice" {
+						res.Coins = tt.balance
+						return 1, nil
+					}
+					return 2, nil
+				},
+				GetUserRoleFunc: func(ctx context.Context, id int) (string, error) {
+					return tt.role, nil
+				},
+				SendCoinsFunc: func(ctx context.Context, username string, fromUserID int, toUserID int, scr *storage.SendCoinRequest, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+					gotFee = fee
+					return nil
+				},
+			}
+			service := NewService(mockStorage)
+			if tt.fees.Enabled() {
+				require.NoError(t, service.EnableFees(context.Background(), tt.fees))
+			}
+
+			err := service.Send(context.Background(), "alice", &storage.SendCoinRequest{ToUser: "bob", Amount: tt.amount})
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantCalled, len(mockStorage.SendCoinsCalls()) == 1)
+			assert.Equal(t, tt.wantFee, gotFee)
+		})
+	}
+}
+
+func TestAcceptPaymentRequest_Fee(t *testing.T) {
+	now := time.Date(2025, 2, 5, 10, 30, 0, 0, time.UTC)
+	mockStorage := &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		EnsureSystemAccountFunc: func(ctx context.Context, username string) (int, error) {
+			return 99, nil
+		},
+		GetPaymentRequestFunc: func(ctx context.Context, id int) (*storage.PaymentRequest, error) {
+			return &storage.PaymentRequest{
+				ID: id, RequesterID: 1, Requester: "alice", PayerID: 2, Payer: "bob", Amount: 200,
+				Status: storage.PaymentRequestPending, ExpiresAt: now.Add(time.Hour),
+			}, nil
+		},
+		AcceptPaymentRequestFunc: func(ctx context.Context, id int, at time.Time, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+			return nil
+		},
+	}
+	service := NewService(mockStorage)
+	service.now = func() time.Time { return now }
+	require.NoError(t, service.EnableFees(context.Background(), config.TransferFees{Account: "system", FeePolicy: config.FeePolicy{Percent: 10}}))
+
+	_, err := service.AcceptPaymentRequest(context.Background(), "bob", 7)
+
+	require.NoError(t, err)
+	require.Len(t, mockStorage.AcceptPaymentRequestCalls(), 1)
+	assert.Equal(t, storage.TransferFee{AccountID: 99, Amount: 20}, mockStorage.AcceptPaymentRequestCalls()[0].Fee)
+}
+
+func TestRunDueTransfers_Fee(t *testing.T) {
+	now := time.Date(2025, 2, 5, 10, 30, 0, 0, time.UTC)
+	mockStorage := &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		EnsureSystemAccountFunc: func(ctx context.Context, username string) (int, error) {
+			return 99, nil
+		},
+		ListDueScheduledTransfersFunc: func(ctx context.Context, at time.Time, limit int) ([]storage.ScheduledTransfer, error) {
+			return []storage.ScheduledTransfer{{ID: 1, SenderID: 1, RecipientID: 2, Amount: 50, NextRunAt: now}}, nil
+		},
+		RunScheduledTransferFunc: func(ctx context.Context, st *storage.ScheduledTransfer, next *time.Time, at time.Time, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+			return nil
+		},
+	}
+	service := NewService(mockStorage)
+	service.now = func() time.Time { return now }
+	require.NoError(t, service.EnableFees(context.Background(), config.TransferFees{Account: "system", FeePolicy: config.FeePolicy{Flat: 2}}))
+
+	executed, err := service.RunDueTransfers(context.Background())
+
+	require.NoError(t, err)
+	assert.Len(t, executed, 1)
+	require.Len(t, mockStorage.RunScheduledTransferCalls(), 1)
+	assert.Equal(t, storage.TransferFee{AccountID: 99, Amount: 2}, mockStorage.RunScheduledTransferCalls()[0].Fee)
+}
+
+func TestEnableFees_NameTaken(t *testing.T) {
+	mockStorage := &storage.IStorageMock{
+		EnsureSystemAccountFunc: func(ctx context.Context, username string) (int, error) {
+			return 0, storage.ErrNotSystemAccount
+		},
+	}
+	service := NewService(mockStorage)
+
+	err := service.EnableFees(context.Background(), config.TransferFees{Account: "system", FeePolicy: config.FeePolicy{Flat: 1}})
+
+	assert.ErrorIs(t, err, storage.ErrNotSystemAccount)
+	assert.False(t, service.Fees.Enabled())
+}
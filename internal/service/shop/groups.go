@@ -0,0 +1,436 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"avito-shop/internal/metrics"
+	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GroupInput — параметры новой группы. ApprovalsRequired — сколько участников должны одобрить
+// покупку из кошелька; 0 означает одного.
+type GroupInput struct {
+	Name              string `json:"name"`
+	ApprovalsRequired int    `json:"approvalsRequired,omitempty"`
+}
+
+// GroupMemberInput — участник, которого добавляют в группу или которому меняют роль;
+// пустая роль означает storage.GroupRoleMember.
+type GroupMemberInput struct {
+	Username string `json:"username"`
+	Role     string `json:"role,omitempty"`
+}
+
+// groupAccess — группа, к которой обращается её участник.
+type groupAccess struct {
+	group  *storage.Group
+	userID int
+	role   string
+}
+
+// CreateGroup создаёт кошелёк группы; создатель становится её администратором.
+func (s *Service) CreateGroup(ctx context.Context, username string, in *GroupInput) (_ *storage.Group, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.CreateGroup", trace.WithAttributes(attribute.String("shop.group", in.Name)))
+	defer func() { tracing.End(span, err) }()
+
+	if in.ApprovalsRequired == 0 {
+		in.ApprovalsRequired = 1
+	}
+	if err = ValidateGroupInput(in); err != nil {
+		return nil, err
+	}
+	ownerID, err := s.userID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &storage.Group{Name: in.Name, ApprovalsRequired: in.ApprovalsRequired, CreatedAt: s.now()}
+	if err = s.Storage.CreateGroup(ctx, g, ownerID); err != nil {
+		if errors.Is(err, storage.ErrGroupExists) {
+			return nil, ErrGroupExists
+		}
+		return nil, ErrInternalServer
+	}
+	g.Role = storage.GroupRoleAdmin
+	s.audit(ctx, username, AuditGroupCreate, auditTarget("group", g.Name), nil, g)
+	return g, nil
+}
+
+// ListGroups возвращает группы пользователя с его ролью в каждой.
+func (s *Service) ListGroups(ctx context.Context, username string) (_ []storage.Group, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ListGroups")
+	defer func() { tracing.End(span, err) }()
+
+	id, err := s.userID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := s.Storage.ListUserGroups(ctx, id)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+	if groups == nil {
+		groups = []storage.Group{}
+	}
+	return groups, nil
+}
+
+// GetGroup возвращает баланс, купленные предметы и участников группы; доступно только участникам.
+func (s *Service) GetGroup(ctx context.Context, username, name string) (_ *storage.GroupInfo, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.GetGroup", trace.WithAttributes(attribute.String("shop.group", name)))
+	defer func() { tracing.End(span, err) }()
+
+	ga, err := s.groupAccess(ctx, username, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var ir storage.InfoResponse
+	if _, err = s.Storage.GetInfo(ctx, &ir, ga.group.Name); err != nil {
+		return nil, ErrInternalServer
+	}
+	if err = s.Storage.GetInventory(ctx, &ir, ga.group.ID); err != nil {
+		return nil, ErrInternalServer
+	}
+	members, err := s.Storage.ListGroupMembers(ctx, ga.group.ID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	info := &storage.GroupInfo{Group: *ga.group, Coins: ir.Coins, Inventory: ir.Inventory, Members: members}
+	info.Role = ga.role
+	if info.Inventory == nil {
+		info.Inventory = []storage.Inventory{}
+	}
+	return info, nil
+}
+
+// GroupHistory возвращает переводы кошелька группы; доступно только участникам.
+func (s *Service) GroupHistory(ctx context.Context, username, name string, filter storage.HistoryFilter) (_ *storage.CoinHistory, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.GroupHistory", trace.WithAttributes(attribute.String("shop.group", name)))
+	defer func() { tracing.End(span, err) }()
+
+	if err = ValidateHistoryFilter(filter); err != nil {
+		return nil, err
+	}
+	ga, err := s.groupAccess(ctx, username, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var ir storage.InfoResponse
+	if err = s.Storage.GetCoinHistory(ctx, &ir, ga.group.ID, filter); err != nil {
+		return nil, ErrInternalServer
+	}
+	return &ir.CoinHistory, nil
+}
+
+// SetGroupMember добавляет пользователя в группу или меняет его роль; доступно администраторам группы.
+// Возвращает участников после изменения.
+func (s *Service) SetGroupMember(ctx context.Context, username, name string, in *GroupMemberInput) (_ []storage.GroupMember, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.SetGroupMember", trace.WithAttributes(attribute.String("shop.group", name)))
+	defer func() { tracing.End(span, err) }()
+
+	if in.Role == "" {
+		in.Role = storage.GroupRoleMember
+	}
+	if err = ValidateGroupMemberInput(in); err != nil {
+		return nil, err
+	}
+	ga, err := s.groupAccess(ctx, username, name)
+	if err != nil {
+		return nil, err
+	}
+	if ga.role != storage.GroupRoleAdmin {
+		return nil, ErrForbidden
+	}
+
+	memberID, err := s.userID(ctx, in.Username)
+	if err != nil {
+		return nil, err
+	}
+	role, err := s.Storage.GetUserRole(ctx, memberID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+	if role == storage.GroupRole || role == storage.SystemRole {
+		ve := &ValidationError{}
+		ve.add("username", "участником группы может быть только пользователь")
+		return nil, ve
+	}
+
+	members, err := s.Storage.ListGroupMembers(ctx, ga.group.ID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+	if in.Role != storage.GroupRoleAdmin && isLastGroupAdmin(members, in.Username) {
+		return nil, ErrLastGroupAdmin
+	}
+
+	if err = s.Storage.SetGroupMember(ctx, ga.group.ID, memberID, in.Role, s.now()); err != nil {
+		return nil, ErrInternalServer
+	}
+	s.audit(ctx, username, AuditGroupMemberSet, auditTarget("group", ga.group.Name), auditGroupMember(members, in.Username), in)
+	return s.groupMembers(ctx, ga.group.ID)
+}
+
+// RemoveGroupMember исключает участника из группы. Исключать других может администратор группы,
+// выйти из группы — любой участник; последнего администратора исключить нельзя.
+func (s *Service) RemoveGroupMember(ctx context.Context, username, name, member string) (_ []storage.GroupMember, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.RemoveGroupMember", trace.WithAttributes(attribute.String("shop.group", name)))
+	defer func() { tracing.End(span, err) }()
+
+	ga, err := s.groupAccess(ctx, username, name)
+	if err != nil {
+		return nil, err
+	}
+	if ga.role != storage.GroupRoleAdmin && !strings.EqualFold(username, member) {
+		return nil, ErrForbidden
+	}
+
+	memberID, err := s.userID(ctx, member)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.Storage.ListGroupMembers(ctx, ga.group.ID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+	if isLastGroupAdmin(members, member) {
+		return nil, ErrLastGroupAdmin
+	}
+
+	if err = s.Storage.RemoveGroupMember(ctx, ga.group.ID, memberID); err != nil {
+		if errors.Is(err, storage.ErrNotGroupMember) {
+			return nil, ErrNotGroupMember
+		}
+		return nil, ErrInternalServer
+	}
+	s.audit(ctx, username, AuditGroupMemberRemove, auditTarget("group", ga.group.Name), auditGroupMember(members, member), nil)
+	return s.groupMembers(ctx, ga.group.ID)
+}
+
+// SendFromGroup переводит монеты из кошелька группы; доступно администраторам группы.
+// Проверки, лимиты и комиссия те же, что у Send от имени кошелька.
+func (s *Service) SendFromGroup(ctx context.Context, username, name string, scr *storage.SendCoinRequest) (err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.SendFromGroup", trace.WithAttributes(attribute.String("shop.group", name)))
+	defer func() { tracing.End(span, err) }()
+
+	ga, err := s.groupAccess(ctx, username, name)
+	if err != nil {
+		return err
+	}
+	if ga.role != storage.GroupRoleAdmin {
+		return ErrForbidden
+	}
+	return s.send(ctx, username, ga.group.Name, scr)
+}
+
+// ProposeGroupPurchase предлагает купить item из кошелька группы и сразу засчитывает одобрение автора.
+// Если для покупки достаточно одного одобрения, она выполняется сразу; если на неё не хватает монет,
+// предложение отменяется и возвращается ErrInsufficientFunds.
+func (s *Service) ProposeGroupPurchase(ctx context.Context, username, name, item string) (_ *storage.GroupPurchase, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ProposeGroupPurchase", trace.WithAttributes(
+		attribute.String("shop.group", name), attribute.String("shop.item", item)))
+	defer func() { tracing.End(span, err) }()
+
+	if _, exists := storage.MerchItems[item]; !exists {
+		return nil, ErrItemNotFound
+	}
+	ga, err := s.groupAccess(ctx, username, name)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &storage.GroupPurchase{GroupID: ga.group.ID, Item: item, CreatedAt: s.now()}
+	if err = s.Storage.CreateGroupPurchase(ctx, p, ga.userID); err != nil {
+		return nil, ErrInternalServer
+	}
+
+	approved, err := s.approveGroupPurchase(ctx, username, ga, p.ID, item)
+	if errors.Is(err, ErrInsufficientFunds) {
+		if cancelErr := s.Storage.CancelGroupPurchase(ctx, p.ID, s.now()); cancelErr != nil {
+			return nil, ErrInternalServer
+		}
+	}
+	return approved, err
+}
+
+// ApproveGroupPurchase засчитывает одобрение участника; одобрение, на котором набирается нужное число,
+// покупает предмет по текущей цене каталога.
+func (s *Service) ApproveGroupPurchase(ctx context.Context, username, name string, id int) (_ *storage.GroupPurchase, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ApproveGroupPurchase", trace.WithAttributes(
+		attribute.String("shop.group", name), attribute.Int("shop.group_purchase.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	ga, err := s.groupAccess(ctx, username, name)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.pendingGroupPurchase(ctx, ga, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.approveGroupPurchase(ctx, username, ga, p.ID, p.Item)
+}
+
+// CancelGroupPurchase отменяет ожидающую покупку; отменить может её автор или администратор группы.
+func (s *Service) CancelGroupPurchase(ctx context.Context, username, name string, id int) (_ *storage.GroupPurchase, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.CancelGroupPurchase", trace.WithAttributes(
+		attribute.String("shop.group", name), attribute.Int("shop.group_purchase.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	ga, err := s.groupAccess(ctx, username, name)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.pendingGroupPurchase(ctx, ga, id)
+	if err != nil {
+		return nil, err
+	}
+	if ga.role != storage.GroupRoleAdmin && !strings.EqualFold(p.ProposedBy, username) {
+		return nil, ErrForbidden
+	}
+
+	now := s.now()
+	err = s.Storage.CancelGroupPurchase(ctx, id, now)
+	switch {
+	case errors.Is(err, storage.ErrGroupPurchaseNotPending):
+		return nil, ErrGroupPurchaseNotPending
+	case err != nil:
+		return nil, ErrInternalServer
+	}
+
+	p.Status, p.ResolvedAt = storage.GroupPurchaseCancelled, &now
+	return p, nil
+}
+
+// ListGroupPurchases возвращает покупки группы в порядке создания; доступно только участникам.
+func (s *Service) ListGroupPurchases(ctx context.Context, username, name string) (_ []storage.GroupPurchase, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ListGroupPurchases", trace.WithAttributes(attribute.String("shop.group", name)))
+	defer func() { tracing.End(span, err) }()
+
+	ga, err := s.groupAccess(ctx, username, name)
+	if err != nil {
+		return nil, err
+	}
+	purchases, err := s.Storage.ListGroupPurchases(ctx, ga.group.ID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+	if purchases == nil {
+		purchases = []storage.GroupPurchase{}
+	}
+	return purchases, nil
+}
+
+// groupAccess загружает группу name и роль в ней пользователя. Группа, в которой пользователь
+// не состоит, неотличима от несуществующей.
+func (s *Service) groupAccess(ctx context.Context, username, name string) (*groupAccess, error) {
+	g, err := s.Storage.GetGroup(ctx, name)
+	if err != nil {
+		if errors.Is(err, storage.ErrGroupNotFound) {
+			return nil, ErrGroupNotFound
+		}
+		return nil, ErrInternalServer
+	}
+	id, err := s.userID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	role, err := s.Storage.GetGroupMemberRole(ctx, g.ID, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotGroupMember) {
+			return nil, ErrGroupNotFound
+		}
+		return nil, ErrInternalServer
+	}
+	return &groupAccess{group: g, userID: id, role: role}, nil
+}
+
+// pendingGroupPurchase загружает ожидающую покупку группы ga; покупка другой группы неотличима от несуществующей.
+func (s *Service) pendingGroupPurchase(ctx context.Context, ga *groupAccess, id int) (*storage.GroupPurchase, error) {
+	p, err := s.Storage.GetGroupPurchase(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrGroupPurchaseNotFound) {
+			return nil, ErrGroupPurchaseNotFound
+		}
+		return nil, ErrInternalServer
+	}
+	if p.GroupID != ga.group.ID {
+		return nil, ErrGroupPurchaseNotFound
+	}
+	if p.Status != storage.GroupPurchasePending {
+		return nil, ErrGroupPurchaseNotPending
+	}
+	return p, nil
+}
+
+func (s *Service) approveGroupPurchase(ctx context.Context, username string, ga *groupAccess, id int, item string) (*storage.GroupPurchase, error) {
+	catalogItem, err := s.catalogItem(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := s.txAudit(ctx, username, AuditGroupPurchase, auditTarget("group_purchase", id), ga.group.ID)
+	p, err := s.Storage.ApproveGroupPurchase(ctx, id, ga.userID, catalogItem.Price, catalogItem.PriceID, s.now(), audit)
+	switch {
+	case errors.Is(err, storage.ErrInsufficientFunds):
+		metrics.InsufficientFundsTotal.WithLabelValues(metrics.OperationPurchase).Inc()
+		return nil, ErrInsufficientFunds
+	case errors.Is(err, storage.ErrGroupPurchaseNotPending):
+		return nil, ErrGroupPurchaseNotPending
+	case errors.Is(err, storage.ErrGroupPurchaseAlreadyApproved):
+		return nil, ErrGroupPurchaseAlreadyApproved
+	case err != nil:
+		return nil, ErrInternalServer
+	}
+	if p.Status == storage.GroupPurchaseCompleted {
+		metrics.PurchasesTotal.WithLabelValues(item).Inc()
+		s.publishPurchase(ctx, p.GroupID, p.Group, p.Item, p.Price)
+	}
+	return p, nil
+}
+
+func (s *Service) groupMembers(ctx context.Context, groupID int) ([]storage.GroupMember, error) {
+	members, err := s.Storage.ListGroupMembers(ctx, groupID)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+	if members == nil {
+		members = []storage.GroupMember{}
+	}
+	return members, nil
+}
+
+// isLastGroupAdmin сообщает, что username — единственный администратор среди members.
+// auditGroupMember возвращает участника username из members для журнала аудита
+// или nil, если его там нет.
+func auditGroupMember(members []storage.GroupMember, username string) any {
+	for _, m := range members {
+		if strings.EqualFold(m.Username, username) {
+			return m
+		}
+	}
+	return nil
+}
+
+func isLastGroupAdmin(members []storage.GroupMember, username string) bool {
+	admins, target := 0, false
+	for _, m := range members {
+		if m.Role != storage.GroupRoleAdmin {
+			continue
+		}
+		admins++
+		if strings.EqualFold(m.Username, username) {
+			target = true
+		}
+	}
+	return target && admins == 1
+}
@@ -0,0 +1,282 @@
+package shop
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// groupStorage возвращает хранилище с группой team (id 100, два одобрения на покупку) и пользователями
+// alice (администратор), bob (участник) и carol (не состоит в группе).
+func groupStorage(coins int) *storage.IStorageMock {
+	ids := map[string]int{"team": 100, "alice": 1, "bob": 2, "carol": 3}
+	roles := map[int]string{1: storage.GroupRoleAdmin, 2: storage.GroupRoleMember}
+	return &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		GetGroupFunc: func(ctx context.Context, name string) (*storage.Group, error) {
+			if name != "team" {
+				return nil, storage.ErrGroupNotFound
+			}
+			return &storage.Group{ID: 100, Name: "team", ApprovalsRequired: 2}, nil
+		},
+		GetInfoFunc: func(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+			id, ok := ids[username]
+			if !ok {
+				return 0, storage.ErrUserNotFound
+			}
+			if id == 100 {
+				ir.Coins = coins
+			}
+			return id, nil
+		},
+		GetUserRoleFunc: func(ctx context.Context, id int) (string, error) {
+			if id == 100 {
+				return storage.GroupRole, nil
+			}
+			return storage.DefaultRole, nil
+		},
+		GetGroupMemberRoleFunc: func(ctx context.Context, groupID, userID int) (string, error) {
+			if role, ok := roles[userID]; ok {
+				return role, nil
+			}
+			return "", storage.ErrNotGroupMember
+		},
+		ListGroupMembersFunc: func(ctx context.Context, groupID int) ([]storage.GroupMember, error) {
+			return []storage.GroupMember{{Username: "alice", Role: storage.GroupRoleAdmin}, {Username: "bob", Role: storage.GroupRoleMember}}, nil
+		},
+		ListItemPricesFunc: func(ctx context.Context, now time.Time) ([]storage.ItemPrice, error) {
+			return nil, nil
+		},
+	}
+}
+
+func TestCreateGroup(t *testing.T) {
+	mockStorage := groupStorage(0)
+	mockStorage.CreateGroupFunc = func(ctx context.Context, g *storage.Group, ownerID int) error {
+		if g.Name == "taken" {
+			return storage.ErrGroupExists
+		}
+		g.ID = 100
+		return nil
+	}
+	service := NewService(mockStorage)
+	ctx := context.Background()
+
+	g, err := service.CreateGroup(ctx, "alice", &GroupInput{Name: "team"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, g.ApprovalsRequired, "one approval by default")
+	assert.Equal(t, storage.GroupRoleAdmin, g.Role)
+	require.Len(t, mockStorage.CreateGroupCalls(), 1)
+	assert.Equal(t, 1, mockStorage.CreateGroupCalls()[0].OwnerID)
+	require.Len(t, mockStorage.AppendAuditCalls(), 1)
+	assert.Equal(t, AuditGroupCreate, mockStorage.AppendAuditCalls()[0].E.Action)
+	assert.Equal(t, "group:team", mockStorage.AppendAuditCalls()[0].E.Target)
+
+	_, err = service.CreateGroup(ctx, "alice", &GroupInput{Name: "taken"})
+	assert.Equal(t, ErrGroupExists, err)
+
+	_, err = service.CreateGroup(ctx, "alice", &GroupInput{Name: "bad name", ApprovalsRequired: MaxGroupApprovals + 1})
+	var ve *ValidationError
+	require.ErrorAs(t, err, &ve)
+	assert.Len(t, ve.Fields, 2)
+}
+
+func TestGroupAccess(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Non-members do not see the group", func(t *testing.T) {
+		service := NewService(groupStorage(0))
+		_, err := service.GetGroup(ctx, "carol", "team")
+		assert.Equal(t, ErrGroupNotFound, err)
+		_, err = service.GroupHistory(ctx, "carol", "team", storage.HistoryFilter{})
+		assert.Equal(t, ErrGroupNotFound, err)
+		_, err = service.GetGroup(ctx, "alice", "other")
+		assert.Equal(t, ErrGroupNotFound, err)
+	})
+
+	t.Run("Members see balance, inventory and members", func(t *testing.T) {
+		mockStorage := groupStorage(250)
+		mockStorage.GetInventoryFunc = func(ctx context.Context, ir *storage.InfoResponse, id int) error {
+			ir.Inventory = []storage.Inventory{{Type: "cup", Quantity: 1}}
+			return nil
+		}
+		info, err := NewService(mockStorage).GetGroup(ctx, "bob", "team")
+		require.NoError(t, err)
+		assert.Equal(t, 250, info.Coins)
+		assert.Equal(t, storage.GroupRoleMember, info.Role)
+		assert.Equal(t, []storage.Inventory{{Type: "cup", Quantity: 1}}, info.Inventory)
+		assert.Len(t, info.Members, 2)
+		assert.Equal(t, 100, mockStorage.GetInventoryCalls()[0].ID)
+	})
+
+	t.Run("Only group admins send from the wallet", func(t *testing.T) {
+		mockStorage := groupStorage(250)
+		mockStorage.SendCoinsFunc = func(ctx context.Context, username string, fromUserID int, toUserID int, scr *storage.SendCoinRequest, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+			return nil
+		}
+		service := NewService(mockStorage)
+
+		err := service.SendFromGroup(ctx, "bob", "team", &storage.SendCoinRequest{ToUser: "carol", Amount: 50})
+		assert.Equal(t, ErrForbidden, err)
+		assert.Empty(t, mockStorage.SendCoinsCalls())
+
+		require.NoError(t, service.SendFromGroup(ctx, "alice", "team", &storage.SendCoinRequest{ToUser: "carol", Amount: 50}))
+		require.Len(t, mockStorage.SendCoinsCalls(), 1)
+		assert.Equal(t, 100, mockStorage.SendCoinsCalls()[0].FromUserID)
+		assert.Equal(t, 3, mockStorage.SendCoinsCalls()[0].ToUserID)
+
+		err = service.SendFromGroup(ctx, "alice", "team", &storage.SendCoinRequest{ToUser: "carol", Amount: 300})
+		assert.Equal(t, ErrInsufficientFunds, err)
+	})
+}
+
+func TestGroupMembers_Roles(t *testing.T) {
+	ctx := context.Background()
+	mockStorage := groupStorage(0)
+	mockStorage.SetGroupMemberFunc = func(ctx context.Context, groupID, userID int, role string, at time.Time) error {
+		return nil
+	}
+	mockStorage.RemoveGroupMemberFunc = func(ctx context.Context, groupID, userID int) error {
+		return nil
+	}
+	service := NewService(mockStorage)
+
+	_, err := service.SetGroupMember(ctx, "bob", "team", &GroupMemberInput{Username: "carol"})
+	assert.Equal(t, ErrForbidden, err, "members cannot add members")
+	_, err = service.SetGroupMember(ctx, "alice", "team", &GroupMemberInput{Username: "team"})
+	assert.ErrorIs(t, err, ErrValidation, "a group cannot join a group")
+	_, err = service.SetGroupMember(ctx, "alice", "team", &GroupMemberInput{Username: "alice", Role: storage.GroupRoleMember})
+	assert.Equal(t, ErrLastGroupAdmin, err)
+
+	_, err = service.SetGroupMember(ctx, "alice", "team", &GroupMemberInput{Username: "carol"})
+	require.NoError(t, err)
+	require.Len(t, mockStorage.SetGroupMemberCalls(), 1)
+	assert.Equal(t, 3, mockStorage.SetGroupMemberCalls()[0].UserID)
+	assert.Equal(t, storage.GroupRoleMember, mockStorage.SetGroupMemberCalls()[0].Role)
+
+	_, err = service.RemoveGroupMember(ctx, "bob", "team", "alice")
+	assert.Equal(t, ErrForbidden, err, "members cannot remove others")
+	_, err = service.RemoveGroupMember(ctx, "alice", "team", "alice")
+	assert.Equal(t, ErrLastGroupAdmin, err)
+	_, err = service.RemoveGroupMember(ctx, "bob", "team", "bob")
+	require.NoError(t, err, "members can leave")
+	assert.Equal(t, 2, mockStorage.RemoveGroupMemberCalls()[0].UserID)
+
+	calls := mockStorage.AppendAuditCalls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, AuditGroupMemberSet, calls[0].E.Action)
+	assert.Empty(t, calls[0].E.Before, "carol was not a member")
+	assert.JSONEq(t, `{"username":"carol","role":"member"}`, string(calls[0].E.After))
+	assert.Equal(t, AuditGroupMemberRemove, calls[1].E.Action)
+	assert.Equal(t, "bob", calls[1].E.Actor)
+	assert.Contains(t, string(calls[1].E.Before), `"username":"bob"`)
+}
+
+func TestProposeGroupPurchase(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		approveErr error
+		wantErr    error
+		wantCancel bool
+	}{
+		{name: "Proposer approval is counted"},
+		{name: "Wallet cannot pay for an immediate purchase", approveErr: storage.ErrInsufficientFunds, wantErr: ErrInsufficientFunds, wantCancel: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := groupStorage(0)
+			mockStorage.CreateGroupPurchaseFunc = func(ctx context.Context, p *storage.GroupPurchase, proposerID int) error {
+				p.ID = 7
+				return nil
+			}
+			mockStorage.ApproveGroupPurchaseFunc = func(ctx context.Context, id, userID, price, priceID int, at time.Time, audit *storage.TxAudit) (*storage.GroupPurchase, error) {
+				if tt.approveErr != nil {
+					return nil, tt.approveErr
+				}
+				return &storage.GroupPurchase{ID: id, Status: storage.GroupPurchasePending, Approvals: []string{"bob"}}, nil
+			}
+			mockStorage.CancelGroupPurchaseFunc = func(ctx context.Context, id int, at time.Time) error {
+				return nil
+			}
+			service := NewService(mockStorage)
+			service.now = func() time.Time { return now }
+
+			p, err := service.ProposeGroupPurchase(ctx, "bob", "team", "cup")
+
+			assert.Equal(t, tt.wantErr, err)
+			require.Len(t, mockStorage.CreateGroupPurchaseCalls(), 1)
+			assert.Equal(t, 2, mockStorage.CreateGroupPurchaseCalls()[0].ProposerID)
+			require.Len(t, mockStorage.ApproveGroupPurchaseCalls(), 1)
+			call := mockStorage.ApproveGroupPurchaseCalls()[0]
+			assert.Equal(t, 7, call.ID)
+			assert.Equal(t, 2, call.UserID)
+			assert.Equal(t, storage.MerchItems["cup"], call.Price)
+			assert.Equal(t, tt.wantCancel, len(mockStorage.CancelGroupPurchaseCalls()) == 1)
+			if tt.wantErr == nil {
+				assert.Equal(t, storage.GroupPurchasePending, p.Status)
+			}
+		})
+	}
+
+	_, err := NewService(groupStorage(0)).ProposeGroupPurchase(ctx, "bob", "team", "dragon")
+	assert.Equal(t, ErrItemNotFound, err)
+}
+
+func TestApproveGroupPurchase(t *testing.T) {
+	ctx := context.Background()
+	purchases := map[int]*storage.GroupPurchase{
+		1: {ID: 1, GroupID: 100, Item: "hoody", ProposedBy: "bob", Status: storage.GroupPurchasePending},
+		2: {ID: 2, GroupID: 200, Item: "hoody", ProposedBy: "bob", Status: storage.GroupPurchasePending},
+		3: {ID: 3, GroupID: 100, Item: "hoody", ProposedBy: "bob", Status: storage.GroupPurchaseCompleted},
+	}
+
+	tests := []struct {
+		name       string
+		username   string
+		id         int
+		approveErr error
+		wantErr    error
+	}{
+		{name: "Last approval completes the purchase", username: "alice", id: 1},
+		{name: "Purchase of another group", username: "alice", id: 2, wantErr: ErrGroupPurchaseNotFound},
+		{name: "Unknown purchase", username: "alice", id: 4, wantErr: ErrGroupPurchaseNotFound},
+		{name: "Completed purchase", username: "alice", id: 3, wantErr: ErrGroupPurchaseNotPending},
+		{name: "Second approval by the same member", username: "alice", id: 1, approveErr: storage.ErrGroupPurchaseAlreadyApproved, wantErr: ErrGroupPurchaseAlreadyApproved},
+		{name: "Not a member", username: "carol", id: 1, wantErr: ErrGroupNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := groupStorage(500)
+			mockStorage.GetGroupPurchaseFunc = func(ctx context.Context, id int) (*storage.GroupPurchase, error) {
+				if p, ok := purchases[id]; ok {
+					c := *p
+					return &c, nil
+				}
+				return nil, storage.ErrGroupPurchaseNotFound
+			}
+			mockStorage.ApproveGroupPurchaseFunc = func(ctx context.Context, id, userID, price, priceID int, at time.Time, audit *storage.TxAudit) (*storage.GroupPurchase, error) {
+				if tt.approveErr != nil {
+					return nil, tt.approveErr
+				}
+				return &storage.GroupPurchase{ID: id, Status: storage.GroupPurchaseCompleted, Price: price}, nil
+			}
+
+			p, err := NewService(mockStorage).ApproveGroupPurchase(ctx, tt.username, "team", tt.id)
+
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, storage.MerchItems["hoody"], p.Price)
+			}
+		})
+	}
+}
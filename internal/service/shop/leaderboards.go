@@ -0,0 +1,158 @@
+package shop
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// PeriodAll — период таблицы лидеров за всё время, доступен всегда.
+	PeriodAll = "all"
+	// DefaultLeaderboardSize — число мест, если не задано ни в запросе, ни в конфигурации.
+	DefaultLeaderboardSize = 10
+	// MaxLeaderboardSize — сколько мест можно запросить; столько же хранит кэш таблиц.
+	MaxLeaderboardSize = 100
+)
+
+// DefaultLeaderboardWindows — периоды таблиц лидеров, если в конфигурации не задано других.
+var DefaultLeaderboardWindows = map[string]time.Duration{
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+}
+
+// Leaderboard возвращает первые limit мест таблицы board за период period (PeriodAll или имя
+// из Leaderboards.Windows); limit = 0 — размер по умолчанию. Пользователи с равным значением
+// делят место, следующее место пропускается («1, 2, 2, 4»).
+func (s *Service) Leaderboard(ctx context.Context, board, period string, limit int) (_ *storage.Leaderboard, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.Leaderboard", trace.WithAttributes(
+		attribute.String("shop.board", board),
+		attribute.String("shop.period", period),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	if !slices.Contains(storage.Boards, board) {
+		return nil, ErrLeaderboardNotFound
+	}
+	if period == "" {
+		period = PeriodAll
+	}
+	if limit == 0 {
+		limit = s.Leaderboards.Size
+		if limit <= 0 {
+			limit = DefaultLeaderboardSize
+		}
+	}
+	windows := s.leaderboardWindows()
+	ve := &ValidationError{}
+	if _, ok := windows[period]; !ok && period != PeriodAll {
+		ve.add("period", fmt.Sprintf("допустимые значения: %s", leaderboardPeriods(windows)))
+	}
+	if limit < 1 || limit > MaxLeaderboardSize {
+		ve.add("limit", fmt.Sprintf("должен быть от 1 до %d", MaxLeaderboardSize))
+	}
+	if err = ve.orNil(); err != nil {
+		return nil, err
+	}
+
+	key := board + ":" + period
+	lb, ok := s.cachedLeaderboard(key)
+	if !ok {
+		lb = &storage.Leaderboard{Board: board, Period: period}
+		if window, ok := windows[period]; ok && period != PeriodAll {
+			since := s.now().Add(-window)
+			lb.Since = &since
+		}
+		// В кэш попадает таблица наибольшего размера, чтобы отвечать на любой limit.
+		size := limit
+		if s.LeaderboardCache != nil {
+			size = MaxLeaderboardSize
+		}
+		if lb.Entries, err = s.Storage.GetLeaderboard(ctx, board, lb.Since, size); err != nil {
+			return nil, ErrInternalServer
+		}
+		rankLeaderboard(lb.Entries)
+		if s.LeaderboardCache != nil {
+			s.LeaderboardCache.Set(key, lb)
+		}
+	}
+
+	res := *lb
+	if len(res.Entries) > limit {
+		res.Entries = res.Entries[:limit]
+	}
+	res.Entries = slices.Clone(res.Entries)
+	return &res, nil
+}
+
+// SetLeaderboardOptOut скрывает пользователя из таблиц лидеров (optOut = true) или возвращает его.
+// Закэшированные таблицы сбрасываются, поэтому изменение видно сразу.
+func (s *Service) SetLeaderboardOptOut(ctx context.Context, username string, optOut bool) (err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.SetLeaderboardOptOut", trace.WithAttributes(attribute.Bool("shop.opt_out", optOut)))
+	defer func() { tracing.End(span, err) }()
+
+	id, err := s.userID(ctx, username)
+	if err != nil {
+		return err
+	}
+	if err = s.Storage.SetLeaderboardOptOut(ctx, id, optOut); err != nil {
+		return ErrInternalServer
+	}
+	s.audit(ctx, username, AuditLeaderboardOptOut, auditTarget("user", username), nil, auditOptOut{optOut})
+	if s.LeaderboardCache != nil {
+		var keys []string
+		for _, board := range storage.Boards {
+			keys = append(keys, board+":"+PeriodAll)
+			for period := range s.leaderboardWindows() {
+				keys = append(keys, board+":"+period)
+			}
+		}
+		s.LeaderboardCache.Delete(keys...)
+	}
+	return nil
+}
+
+func (s *Service) cachedLeaderboard(key string) (*storage.Leaderboard, bool) {
+	if s.LeaderboardCache == nil {
+		return nil, false
+	}
+	return s.LeaderboardCache.Get(key)
+}
+
+func (s *Service) leaderboardWindows() map[string]time.Duration {
+	if len(s.Leaderboards.Windows) == 0 {
+		return DefaultLeaderboardWindows
+	}
+	return s.Leaderboards.Windows
+}
+
+// leaderboardPeriods перечисляет допустимые периоды для сообщения об ошибке.
+func leaderboardPeriods(windows map[string]time.Duration) string {
+	periods := []string{PeriodAll}
+	for period := range windows {
+		if period != PeriodAll {
+			periods = append(periods, period)
+		}
+	}
+	sort.Strings(periods[1:])
+	return fmt.Sprint(periods)
+}
+
+// rankLeaderboard проставляет места записям, упорядоченным по убыванию значения.
+func rankLeaderboard(entries []storage.LeaderboardEntry) {
+	for i := range entries {
+		if i > 0 && entries[i].Value == entries[i-1].Value {
+			entries[i].Rank = entries[i-1].Rank
+		} else {
+			entries[i].Rank = i + 1
+		}
+	}
+}
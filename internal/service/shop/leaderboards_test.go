@@ -0,0 +1,95 @@
+package shop
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"avito-shop/internal/cache"
+	"avito-shop/internal/config"
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderboard_RanksAndWindows(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	mockStorage := &storage.IStorageMock{
+		GetLeaderboardFunc: func(ctx context.Context, board string, since *time.Time, limit int) ([]storage.LeaderboardEntry, error) {
+			return []storage.LeaderboardEntry{
+				{Username: "alice", Value: 50},
+				{Username: "bob", Value: 30},
+				{Username: "carol", Value: 30},
+				{Username: "dave", Value: 10},
+			}, nil
+		},
+	}
+	service := NewService(mockStorage)
+	service.now = func() time.Time { return now }
+	service.Leaderboards = config.Leaderboards{Windows: map[string]time.Duration{"day": 24 * time.Hour}, Size: 4}
+
+	lb, err := service.Leaderboard(context.Background(), storage.BoardSenders, "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, &storage.Leaderboard{Board: storage.BoardSenders, Period: PeriodAll, Entries: []storage.LeaderboardEntry{
+		{Rank: 1, Username: "alice", Value: 50},
+		{Rank: 2, Username: "bob", Value: 30},
+		{Rank: 2, Username: "carol", Value: 30},
+		{Rank: 4, Username: "dave", Value: 10},
+	}}, lb)
+
+	_, err = service.Leaderboard(context.Background(), storage.BoardCollectors, "day", 5)
+	require.NoError(t, err)
+	calls := mockStorage.GetLeaderboardCalls()
+	require.Len(t, calls, 2)
+	assert.Nil(t, calls[0].Since)
+	assert.Equal(t, 4, calls[0].Limit, "size from config")
+	require.NotNil(t, calls[1].Since)
+	assert.Equal(t, now.Add(-24*time.Hour), *calls[1].Since)
+	assert.Equal(t, 5, calls[1].Limit)
+
+	_, err = service.Leaderboard(context.Background(), "richest", PeriodAll, 0)
+	assert.Equal(t, ErrLeaderboardNotFound, err)
+
+	_, err = service.Leaderboard(context.Background(), storage.BoardSenders, "week", MaxLeaderboardSize+1)
+	var ve *ValidationError
+	require.ErrorAs(t, err, &ve)
+	require.Len(t, ve.Fields, 2)
+	assert.Equal(t, "period", ve.Fields[0].Field, "week is not configured")
+	assert.Equal(t, "limit", ve.Fields[1].Field)
+	assert.Len(t, mockStorage.GetLeaderboardCalls(), 2)
+}
+
+func TestLeaderboard_CacheInvalidatedByOptOut(t *testing.T) {
+	entries := []storage.LeaderboardEntry{{Username: "alice", Value: 50}, {Username: "bob", Value: 30}}
+	mockStorage := &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		GetLeaderboardFunc: func(ctx context.Context, board string, since *time.Time, limit int) ([]storage.LeaderboardEntry, error) {
+			return append([]storage.LeaderboardEntry(nil), entries...), nil
+		},
+		GetInfoFunc: func(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+			return 1, nil
+		},
+		SetLeaderboardOptOutFunc: func(ctx context.Context, userID int, optOut bool) error {
+			entries = entries[1:]
+			return nil
+		},
+	}
+	service := NewService(mockStorage)
+	service.LeaderboardCache = cache.NewLRU[*storage.Leaderboard](10, time.Minute)
+
+	top, err := service.Leaderboard(context.Background(), storage.BoardReceivers, "week", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.LeaderboardEntry{{Rank: 1, Username: "alice", Value: 50}}, top.Entries)
+	all, err := service.Leaderboard(context.Background(), storage.BoardReceivers, "week", 10)
+	require.NoError(t, err)
+	assert.Len(t, all.Entries, 2)
+	require.Len(t, mockStorage.GetLeaderboardCalls(), 1, "second request is served from cache")
+	assert.Equal(t, MaxLeaderboardSize, mockStorage.GetLeaderboardCalls()[0].Limit)
+
+	require.NoError(t, service.SetLeaderboardOptOut(context.Background(), "alice", true))
+	all, err = service.Leaderboard(context.Background(), storage.BoardReceivers, "week", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.LeaderboardEntry{{Rank: 1, Username: "bob", Value: 30}}, all.Entries)
+	assert.Len(t, mockStorage.GetLeaderboardCalls(), 2)
+}
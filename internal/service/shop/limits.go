@@ -0,0 +1,118 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"avito-shop/internal/config"
+	"avito-shop/internal/service/shop/storage"
+)
+
+// LimitExceededError — перевод нарушает лимит; текст ошибки можно показывать пользователю.
+type LimitExceededError struct {
+	Limit string
+	Max   int
+	Used  int
+}
+
+func (e *LimitExceededError) Error() string {
+	switch e.Limit {
+	case storage.LimitPerTransfer:
+		return fmt.Sprintf("превышен лимит одного перевода: не больше %d монет", e.Max)
+	case storage.LimitDailySent:
+		return fmt.Sprintf("превышен суточный лимит отправки: %d монет, сегодня уже отправлено %d", e.Max, e.Used)
+	case storage.LimitDailyReceived:
+		return fmt.Sprintf("превышен суточный лимит получения у получателя: %d монет, сегодня уже получено %d", e.Max, e.Used)
+	case storage.LimitApproval:
+		return fmt.Sprintf("перевод больше %d монет требует одобрения и выполняется только через /api/sendCoin", e.Max)
+	}
+	return ErrLimitExceeded.Error()
+}
+
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// limitError переводит ошибку лимита хранилища в ошибку сервиса.
+func limitError(err error) (*LimitExceededError, bool) {
+	var le *storage.LimitExceededError
+	if !errors.As(err, &le) {
+		return nil, false
+	}
+	return &LimitExceededError{Limit: le.Limit, Max: le.Max, Used: le.Used}, true
+}
+
+// dayStart — начало суток (UTC), с которого считаются суточные лимиты.
+func dayStart(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// limitPolicy возвращает лимиты пользователя. Роль читается из хранилища,
+// только если для ролей есть переопределения.
+func (s *Service) limitPolicy(ctx context.Context, userID int) (config.LimitPolicy, error) {
+	if len(s.Limits.Roles) == 0 {
+		return s.Limits.Default, nil
+	}
+	role, err := s.Storage.GetUserRole(ctx, userID)
+	if err != nil {
+		return config.LimitPolicy{}, err
+	}
+	return s.Limits.For(role), nil
+}
+
+// transferLimits проверяет лимит одного перевода отправителя и возвращает лимиты,
+// которые хранилище проверит внутри транзакции перевода.
+func (s *Service) transferLimits(ctx context.Context, fromID, toID, amount int) (storage.TransferLimits, error) {
+	limits, err := s.partyLimits(ctx, fromID, toID)
+	if err != nil {
+		return storage.TransferLimits{}, err
+	}
+	if limits.MaxPerTransfer > 0 && amount > limits.MaxPerTransfer {
+		return storage.TransferLimits{}, &LimitExceededError{Limit: storage.LimitPerTransfer, Max: limits.MaxPerTransfer}
+	}
+	return limits, nil
+}
+
+// partyLimits возвращает лимиты перевода от fromID к toID по ролям участников.
+func (s *Service) partyLimits(ctx context.Context, fromID, toID int) (storage.TransferLimits, error) {
+	if !s.Limits.Enabled() {
+		return storage.TransferLimits{}, nil
+	}
+
+	sender, err := s.limitPolicy(ctx, fromID)
+	if err != nil {
+		return storage.TransferLimits{}, ErrInternalServer
+	}
+	recipient, err := s.limitPolicy(ctx, toID)
+	if err != nil {
+		return storage.TransferLimits{}, ErrInternalServer
+	}
+
+	return storage.TransferLimits{
+		Since:          dayStart(s.now()),
+		MaxPerTransfer: sender.PerTransfer,
+		MaxSent:        sender.DailySent,
+		MaxReceived:    recipient.DailyReceived,
+	}, nil
+}
+
+// limitUsage собирает лимиты пользователя и их использование за текущие сутки для /api/info.
+func (s *Service) limitUsage(ctx context.Context, userID int) (*storage.LimitUsage, error) {
+	policy, err := s.limitPolicy(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sent, received, err := s.Storage.GetTransferTotals(ctx, userID, dayStart(s.now()))
+	if err != nil {
+		return nil, err
+	}
+	return &storage.LimitUsage{
+		PerTransfer:   policy.PerTransfer,
+		DailySent:     policy.DailySent,
+		SentToday:     sent,
+		DailyReceived: policy.DailyReceived,
+		ReceivedToday: received,
+	}, nil
+}
@@ -0,0 +1,148 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"avito-shop/internal/config"
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestSend_Limits(t *testing.T) {
+	now := time.Date(2025, 2, 5, 10, 30, 0, 0, time.UTC)
+	dayStart := time.Date(2025, 2, 5, 0, 0, 0, 0, time.UTC)
+	policy := config.LimitPolicy{PerTransfer: 100, DailySent: 300, DailyReceived: 500}
+
+	tests := []struct {
+		name       string
+		limits     config.TransferLimits
+		roles      map[int]string
+		amount     int
+		storageErr error
+		wantLimits *storage.TransferLimits
+		wantErr    error
+		wantMsg    string
+	}{
+		{
+			name:       "No limits configured",
+			amount:     900,
+			wantLimits: &storage.TransferLimits{},
+		},
+		{
+			name:       "Default policy",
+			limits:     config.TransferLimits{Default: policy},
+			amount:     100,
+			wantLimits: &storage.TransferLimits{Since: dayStart, MaxPerTransfer: 100, MaxSent: 300, MaxReceived: 500},
+		},
+		{
+			name:    "Per-transfer limit exceeded",
+			limits:  config.TransferLimits{Default: policy},
+			amount:  101,
+			wantErr: &LimitExceededError{Limit: storage.LimitPerTransfer, Max: 100},
+			wantMsg: "превышен лимит одного перевода: не больше 100 монет",
+		},
+		{
+			name: "Role overrides",
+			limits: config.TransferLimits{Default: policy, Roles: map[string]config.LimitOverride{
+				"vip":      {PerTransfer: intPtr(1000), DailySent: intPtr(2000)},
+				"merchant": {DailyReceived: intPtr(0)},
+			}},
+			roles:      map[int]string{1: "vip", 2: "merchant"},
+			amount:     900,
+			wantLimits: &storage.TransferLimits{Since: dayStart, MaxPerTransfer: 1000, MaxSent: 2000, MaxReceived: 0},
+		},
+		{
+			name:       "Daily limit exceeded in storage",
+			limits:     config.TransferLimits{Default: policy},
+			amount:     50,
+			storageErr: &storage.LimitExceededError{Limit: storage.LimitDailySent, Max: 300, Used: 280},
+			wantErr:    &LimitExceededError{Limit: storage.LimitDailySent, Max: 300, Used: 280},
+			wantMsg:    "превышен суточный лимит отправки: 300 монет, сегодня уже отправлено 280",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotLimits *storage.TransferLimits
+			mockStorage := &storage.IStorageMock{
+				AppendAuditFunc: nopAppendAudit,
+				GetInfoFunc: func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+					res.Coins = 1000
+					if username == "alice" {
+						return 1, nil
+					}
+					return 2, nil
+				},
+				GetUserRoleFunc: func(ctx context.Context, id int) (string, error) {
+					if role, ok := tt.roles[id]; ok {
+						return role, nil
+					}
+					return storage.DefaultRole, nil
+				},
+				SendCoinsFunc: func(ctx context.Context, username string, fromUserID int, toUserID int, scr *storage.SendCoinRequest, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+					gotLimits = &limits
+					return tt.storageErr
+				},
+			}
+			service := NewService(mockStorage)
+			service.Limits = tt.limits
+			service.now = func() time.Time { return now }
+
+			err := service.Send(context.Background(), "alice", &storage.SendCoinRequest{ToUser: "bob", Amount: tt.amount})
+
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				assert.ErrorIs(t, err, ErrLimitExceeded)
+				assert.Equal(t, tt.wantMsg, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			if tt.wantLimits != nil {
+				assert.Equal(t, tt.wantLimits, gotLimits)
+			}
+			if len(tt.limits.Roles) == 0 {
+				assert.Empty(t, mockStorage.GetUserRoleCalls(), "roles are not looked up without overrides")
+			}
+		})
+	}
+}
+
+func TestCollectAllInfo_Limits(t *testing.T) {
+	now := time.Date(2025, 2, 5, 10, 30, 0, 0, time.UTC)
+	mockStorage := &storage.IStorageMock{
+		GetFullInfoFunc: func(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+			ir.Coins = 900
+			return 7, nil
+		},
+		GetTransferTotalsFunc: func(ctx context.Context, userID int, since time.Time) (int, int, error) {
+			assert.Equal(t, 7, userID)
+			assert.Equal(t, time.Date(2025, 2, 5, 0, 0, 0, 0, time.UTC), since)
+			return 120, 40, nil
+		},
+	}
+	service := NewService(mockStorage)
+	service.now = func() time.Time { return now }
+
+	ir, err := service.CollectAllInfo(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Nil(t, ir.Limits, "no limits section without configured limits")
+	assert.Empty(t, mockStorage.GetTransferTotalsCalls())
+
+	service.Limits = config.TransferLimits{Default: config.LimitPolicy{PerTransfer: 100, DailySent: 300, DailyReceived: 500}}
+	ir, err = service.CollectAllInfo(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, &storage.LimitUsage{PerTransfer: 100, DailySent: 300, SentToday: 120, DailyReceived: 500, ReceivedToday: 40}, ir.Limits)
+
+	mockStorage.GetTransferTotalsFunc = func(ctx context.Context, userID int, since time.Time) (int, int, error) {
+		return 0, 0, errors.New("db down")
+	}
+	_, err = service.CollectAllInfo(context.Background(), "alice")
+	assert.ErrorIs(t, err, ErrInternalServer)
+}
@@ -5,6 +5,7 @@ package shop
 
 import (
 	"avito-shop/internal/service/shop/storage"
+	"context"
 	"sync"
 )
 
@@ -18,15 +19,144 @@ var _ IService = &IServiceMock{}
 //
 //		// make and configure a mocked IService
 //		mockedIService := &IServiceMock{
-//			CollectAllInfoFunc: func(username string) (*storage.InfoResponse, error) {
+//			AcceptPaymentRequestFunc: func(ctx context.Context, username string, id int) (*storage.PaymentRequest, error) {
+//				panic("mock out the AcceptPaymentRequest method")
+//			},
+//			ApproveGroupPurchaseFunc: func(ctx context.Context, username string, name string, id int) (*storage.GroupPurchase, error) {
+//				panic("mock out the ApproveGroupPurchase method")
+//			},
+//			ApprovePendingTransferFunc: func(ctx context.Context, approver string, id int) (*storage.PendingTransfer, error) {
+//				panic("mock out the ApprovePendingTransfer method")
+//			},
+//			AuditLogFunc: func(ctx context.Context, admin string, filter storage.AuditFilter) ([]storage.AuditEntry, error) {
+//				panic("mock out the AuditLog method")
+//			},
+//			CancelGroupPurchaseFunc: func(ctx context.Context, username string, name string, id int) (*storage.GroupPurchase, error) {
+//				panic("mock out the CancelGroupPurchase method")
+//			},
+//			CancelItemPriceFunc: func(ctx context.Context, admin string, id int) (*storage.ItemPrice, error) {
+//				panic("mock out the CancelItemPrice method")
+//			},
+//			CancelPaymentRequestFunc: func(ctx context.Context, username string, id int) (*storage.PaymentRequest, error) {
+//				panic("mock out the CancelPaymentRequest method")
+//			},
+//			CancelScheduledTransferFunc: func(ctx context.Context, username string, id int) (*storage.ScheduledTransfer, error) {
+//				panic("mock out the CancelScheduledTransfer method")
+//			},
+//			CatalogFunc: func(ctx context.Context) ([]storage.CatalogItem, error) {
+//				panic("mock out the Catalog method")
+//			},
+//			CollectAllInfoFunc: func(ctx context.Context, username string) (*storage.InfoResponse, error) {
 //				panic("mock out the CollectAllInfo method")
 //			},
-//			PurchaseFunc: func(username string, item string) error {
+//			CreateGroupFunc: func(ctx context.Context, username string, in *GroupInput) (*storage.Group, error) {
+//				panic("mock out the CreateGroup method")
+//			},
+//			CreatePromoCodeFunc: func(ctx context.Context, admin string, in *PromoCodeInput) (*storage.PromoCode, error) {
+//				panic("mock out the CreatePromoCode method")
+//			},
+//			DeclinePaymentRequestFunc: func(ctx context.Context, username string, id int) (*storage.PaymentRequest, error) {
+//				panic("mock out the DeclinePaymentRequest method")
+//			},
+//			DisablePromoCodeFunc: func(ctx context.Context, admin string, code string) (*storage.PromoCode, error) {
+//				panic("mock out the DisablePromoCode method")
+//			},
+//			ExpireCoinsFunc: func(ctx context.Context) ([]storage.ExpiredCoins, error) {
+//				panic("mock out the ExpireCoins method")
+//			},
+//			ExpirePendingTransfersFunc: func(ctx context.Context) ([]storage.PendingTransfer, error) {
+//				panic("mock out the ExpirePendingTransfers method")
+//			},
+//			ExportAuditLogFunc: func(ctx context.Context, admin string, filter storage.AuditFilter, write func([]storage.AuditEntry) error) error {
+//				panic("mock out the ExportAuditLog method")
+//			},
+//			GetGroupFunc: func(ctx context.Context, username string, name string) (*storage.GroupInfo, error) {
+//				panic("mock out the GetGroup method")
+//			},
+//			GroupHistoryFunc: func(ctx context.Context, username string, name string, filter storage.HistoryFilter) (*storage.CoinHistory, error) {
+//				panic("mock out the GroupHistory method")
+//			},
+//			HistoryFunc: func(ctx context.Context, username string, filter storage.HistoryFilter) (*storage.CoinHistory, error) {
+//				panic("mock out the History method")
+//			},
+//			LeaderboardFunc: func(ctx context.Context, board string, period string, limit int) (*storage.Leaderboard, error) {
+//				panic("mock out the Leaderboard method")
+//			},
+//			ListGroupPurchasesFunc: func(ctx context.Context, username string, name string) ([]storage.GroupPurchase, error) {
+//				panic("mock out the ListGroupPurchases method")
+//			},
+//			ListGroupsFunc: func(ctx context.Context, username string) ([]storage.Group, error) {
+//				panic("mock out the ListGroups method")
+//			},
+//			ListItemPricesFunc: func(ctx context.Context, admin string) ([]storage.ItemPrice, error) {
+//				panic("mock out the ListItemPrices method")
+//			},
+//			ListOrdersFunc: func(ctx context.Context, username string) ([]storage.Order, error) {
+//				panic("mock out the ListOrders method")
+//			},
+//			ListPaymentRequestsFunc: func(ctx context.Context, username string, direction string) ([]storage.PaymentRequest, error) {
+//				panic("mock out the ListPaymentRequests method")
+//			},
+//			ListPendingTransfersFunc: func(ctx context.Context, username string) ([]storage.PendingTransfer, error) {
+//				panic("mock out the ListPendingTransfers method")
+//			},
+//			ListPromoCodesFunc: func(ctx context.Context, admin string) ([]storage.PromoCode, error) {
+//				panic("mock out the ListPromoCodes method")
+//			},
+//			ListScheduledTransfersFunc: func(ctx context.Context, username string) ([]storage.ScheduledTransfer, error) {
+//				panic("mock out the ListScheduledTransfers method")
+//			},
+//			ListUserTransactionsFunc: func(ctx context.Context, admin string, username string) ([]storage.Transaction, error) {
+//				panic("mock out the ListUserTransactions method")
+//			},
+//			ProposeGroupPurchaseFunc: func(ctx context.Context, username string, name string, item string) (*storage.GroupPurchase, error) {
+//				panic("mock out the ProposeGroupPurchase method")
+//			},
+//			PurchaseFunc: func(ctx context.Context, username string, item string, promoCode string) error {
 //				panic("mock out the Purchase method")
 //			},
-//			SendFunc: func(fromUsername string, scr *storage.SendCoinRequest) error {
+//			QuotePurchaseFunc: func(ctx context.Context, username string, item string, promoCode string) (*storage.PriceQuote, error) {
+//				panic("mock out the QuotePurchase method")
+//			},
+//			RecordAuthFunc: func(ctx context.Context, username string, action string) {
+//				panic("mock out the RecordAuth method")
+//			},
+//			RejectPendingTransferFunc: func(ctx context.Context, approver string, id int) (*storage.PendingTransfer, error) {
+//				panic("mock out the RejectPendingTransfer method")
+//			},
+//			RemoveGroupMemberFunc: func(ctx context.Context, username string, name string, member string) ([]storage.GroupMember, error) {
+//				panic("mock out the RemoveGroupMember method")
+//			},
+//			RequestPaymentFunc: func(ctx context.Context, requester string, req *PaymentRequestInput) (*storage.PaymentRequest, error) {
+//				panic("mock out the RequestPayment method")
+//			},
+//			ReverseTransferFunc: func(ctx context.Context, admin string, in *ReversalInput) (*storage.TransferReversal, error) {
+//				panic("mock out the ReverseTransfer method")
+//			},
+//			RunDueTransfersFunc: func(ctx context.Context) ([]storage.ScheduledTransfer, error) {
+//				panic("mock out the RunDueTransfers method")
+//			},
+//			ScheduleItemPriceFunc: func(ctx context.Context, admin string, in *ItemPriceInput) (*storage.ItemPrice, error) {
+//				panic("mock out the ScheduleItemPrice method")
+//			},
+//			ScheduleTransferFunc: func(ctx context.Context, fromUsername string, in *ScheduledTransferInput) (*storage.ScheduledTransfer, error) {
+//				panic("mock out the ScheduleTransfer method")
+//			},
+//			SendFunc: func(ctx context.Context, fromUsername string, scr *storage.SendCoinRequest) error {
 //				panic("mock out the Send method")
 //			},
+//			SendFromGroupFunc: func(ctx context.Context, username string, name string, scr *storage.SendCoinRequest) error {
+//				panic("mock out the SendFromGroup method")
+//			},
+//			SetGroupMemberFunc: func(ctx context.Context, username string, name string, in *GroupMemberInput) ([]storage.GroupMember, error) {
+//				panic("mock out the SetGroupMember method")
+//			},
+//			SetLeaderboardOptOutFunc: func(ctx context.Context, username string, optOut bool) error {
+//				panic("mock out the SetLeaderboardOptOut method")
+//			},
+//			VerifyAuditLogFunc: func(ctx context.Context, admin string) (*AuditVerification, error) {
+//				panic("mock out the VerifyAuditLog method")
+//			},
 //		}
 //
 //		// use mockedIService in code that requires IService
@@ -34,142 +164,2415 @@ var _ IService = &IServiceMock{}
 //
 //	}
 type IServiceMock struct {
+	// AcceptPaymentRequestFunc mocks the AcceptPaymentRequest method.
+	AcceptPaymentRequestFunc func(ctx context.Context, username string, id int) (*storage.PaymentRequest, error)
+
+	// ApproveGroupPurchaseFunc mocks the ApproveGroupPurchase method.
+	ApproveGroupPurchaseFunc func(ctx context.Context, username string, name string, id int) (*storage.GroupPurchase, error)
+
+	// ApprovePendingTransferFunc mocks the ApprovePendingTransfer method.
+	ApprovePendingTransferFunc func(ctx context.Context, approver string, id int) (*storage.PendingTransfer, error)
+
+	// AuditLogFunc mocks the AuditLog method.
+	AuditLogFunc func(ctx context.Context, admin string, filter storage.AuditFilter) ([]storage.AuditEntry, error)
+
+	// CancelGroupPurchaseFunc mocks the CancelGroupPurchase method.
+	CancelGroupPurchaseFunc func(ctx context.Context, username string, name string, id int) (*storage.GroupPurchase, error)
+
+	// CancelItemPriceFunc mocks the CancelItemPrice method.
+	CancelItemPriceFunc func(ctx context.Context, admin string, id int) (*storage.ItemPrice, error)
+
+	// CancelPaymentRequestFunc mocks the CancelPaymentRequest method.
+	CancelPaymentRequestFunc func(ctx context.Context, username string, id int) (*storage.PaymentRequest, error)
+
+	// CancelScheduledTransferFunc mocks the CancelScheduledTransfer method.
+	CancelScheduledTransferFunc func(ctx context.Context, username string, id int) (*storage.ScheduledTransfer, error)
+
+	// CatalogFunc mocks the Catalog method.
+	CatalogFunc func(ctx context.Context) ([]storage.CatalogItem, error)
+
 	// CollectAllInfoFunc mocks the CollectAllInfo method.
-	CollectAllInfoFunc func(username string) (*storage.InfoResponse, error)
+	CollectAllInfoFunc func(ctx context.Context, username string) (*storage.InfoResponse, error)
+
+	// CreateGroupFunc mocks the CreateGroup method.
+	CreateGroupFunc func(ctx context.Context, username string, in *GroupInput) (*storage.Group, error)
+
+	// CreatePromoCodeFunc mocks the CreatePromoCode method.
+	CreatePromoCodeFunc func(ctx context.Context, admin string, in *PromoCodeInput) (*storage.PromoCode, error)
+
+	// DeclinePaymentRequestFunc mocks the DeclinePaymentRequest method.
+	DeclinePaymentRequestFunc func(ctx context.Context, username string, id int) (*storage.PaymentRequest, error)
+
+	// DisablePromoCodeFunc mocks the DisablePromoCode method.
+	DisablePromoCodeFunc func(ctx context.Context, admin string, code string) (*storage.PromoCode, error)
+
+	// ExpireCoinsFunc mocks the ExpireCoins method.
+	ExpireCoinsFunc func(ctx context.Context) ([]storage.ExpiredCoins, error)
+
+	// ExpirePendingTransfersFunc mocks the ExpirePendingTransfers method.
+	ExpirePendingTransfersFunc func(ctx context.Context) ([]storage.PendingTransfer, error)
+
+	// ExportAuditLogFunc mocks the ExportAuditLog method.
+	ExportAuditLogFunc func(ctx context.Context, admin string, filter storage.AuditFilter, write func([]storage.AuditEntry) error) error
+
+	// GetGroupFunc mocks the GetGroup method.
+	GetGroupFunc func(ctx context.Context, username string, name string) (*storage.GroupInfo, error)
+
+	// GroupHistoryFunc mocks the GroupHistory method.
+	GroupHistoryFunc func(ctx context.Context, username string, name string, filter storage.HistoryFilter) (*storage.CoinHistory, error)
+
+	// HistoryFunc mocks the History method.
+	HistoryFunc func(ctx context.Context, username string, filter storage.HistoryFilter) (*storage.CoinHistory, error)
+
+	// LeaderboardFunc mocks the Leaderboard method.
+	LeaderboardFunc func(ctx context.Context, board string, period string, limit int) (*storage.Leaderboard, error)
+
+	// ListGroupPurchasesFunc mocks the ListGroupPurchases method.
+	ListGroupPurchasesFunc func(ctx context.Context, username string, name string) ([]storage.GroupPurchase, error)
+
+	// ListGroupsFunc mocks the ListGroups method.
+	ListGroupsFunc func(ctx context.Context, username string) ([]storage.Group, error)
+
+	// ListItemPricesFunc mocks the ListItemPrices method.
+	ListItemPricesFunc func(ctx context.Context, admin string) ([]storage.ItemPrice, error)
+
+	// ListOrdersFunc mocks the ListOrders method.
+	ListOrdersFunc func(ctx context.Context, username string) ([]storage.Order, error)
+
+	// ListPaymentRequestsFunc mocks the ListPaymentRequests method.
+	ListPaymentRequestsFunc func(ctx context.Context, username string, direction string) ([]storage.PaymentRequest, error)
+
+	// ListPendingTransfersFunc mocks the ListPendingTransfers method.
+	ListPendingTransfersFunc func(ctx context.Context, username string) ([]storage.PendingTransfer, error)
+
+	// ListPromoCodesFunc mocks the ListPromoCodes method.
+	ListPromoCodesFunc func(ctx context.Context, admin string) ([]storage.PromoCode, error)
+
+	// ListScheduledTransfersFunc mocks the ListScheduledTransfers method.
+	ListScheduledTransfersFunc func(ctx context.Context, username string) ([]storage.ScheduledTransfer, error)
+
+	// ListUserTransactionsFunc mocks the ListUserTransactions method.
+	ListUserTransactionsFunc func(ctx context.Context, admin string, username string) ([]storage.Transaction, error)
+
+	// ProposeGroupPurchaseFunc mocks the ProposeGroupPurchase method.
+	ProposeGroupPurchaseFunc func(ctx context.Context, username string, name string, item string) (*storage.GroupPurchase, error)
 
 	// PurchaseFunc mocks the Purchase method.
-	PurchaseFunc func(username string, item string) error
+	PurchaseFunc func(ctx context.Context, username string, item string, promoCode string) error
+
+	// QuotePurchaseFunc mocks the QuotePurchase method.
+	QuotePurchaseFunc func(ctx context.Context, username string, item string, promoCode string) (*storage.PriceQuote, error)
+
+	// RecordAuthFunc mocks the RecordAuth method.
+	RecordAuthFunc func(ctx context.Context, username string, action string)
+
+	// RejectPendingTransferFunc mocks the RejectPendingTransfer method.
+	RejectPendingTransferFunc func(ctx context.Context, approver string, id int) (*storage.PendingTransfer, error)
+
+	// RemoveGroupMemberFunc mocks the RemoveGroupMember method.
+	RemoveGroupMemberFunc func(ctx context.Context, username string, name string, member string) ([]storage.GroupMember, error)
+
+	// RequestPaymentFunc mocks the RequestPayment method.
+	RequestPaymentFunc func(ctx context.Context, requester string, req *PaymentRequestInput) (*storage.PaymentRequest, error)
+
+	// ReverseTransferFunc mocks the ReverseTransfer method.
+	ReverseTransferFunc func(ctx context.Context, admin string, in *ReversalInput) (*storage.TransferReversal, error)
+
+	// RunDueTransfersFunc mocks the RunDueTransfers method.
+	RunDueTransfersFunc func(ctx context.Context) ([]storage.ScheduledTransfer, error)
+
+	// ScheduleItemPriceFunc mocks the ScheduleItemPrice method.
+	ScheduleItemPriceFunc func(ctx context.Context, admin string, in *ItemPriceInput) (*storage.ItemPrice, error)
+
+	// ScheduleTransferFunc mocks the ScheduleTransfer method.
+	ScheduleTransferFunc func(ctx context.Context, fromUsername string, in *ScheduledTransferInput) (*storage.ScheduledTransfer, error)
 
 	// SendFunc mocks the Send method.
-	SendFunc func(fromUsername string, scr *storage.SendCoinRequest) error
+	SendFunc func(ctx context.Context, fromUsername string, scr *storage.SendCoinRequest) error
+
+	// SendFromGroupFunc mocks the SendFromGroup method.
+	SendFromGroupFunc func(ctx context.Context, username string, name string, scr *storage.SendCoinRequest) error
+
+	// SetGroupMemberFunc mocks the SetGroupMember method.
+	SetGroupMemberFunc func(ctx context.Context, username string, name string, in *GroupMemberInput) ([]storage.GroupMember, error)
+
+	// SetLeaderboardOptOutFunc mocks the SetLeaderboardOptOut method.
+	SetLeaderboardOptOutFunc func(ctx context.Context, username string, optOut bool) error
+
+	// VerifyAuditLogFunc mocks the VerifyAuditLog method.
+	VerifyAuditLogFunc func(ctx context.Context, admin string) (*AuditVerification, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// AcceptPaymentRequest holds details about calls to the AcceptPaymentRequest method.
+		AcceptPaymentRequest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// ID is the id argument value.
+			ID int
+		}
+		// ApproveGroupPurchase holds details about calls to the ApproveGroupPurchase method.
+		ApproveGroupPurchase []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Name is the name argument value.
+			Name string
+			// ID is the id argument value.
+			ID int
+		}
+		// ApprovePendingTransfer holds details about calls to the ApprovePendingTransfer method.
+		ApprovePendingTransfer []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Approver is the approver argument value.
+			Approver string
+			// ID is the id argument value.
+			ID int
+		}
+		// AuditLog holds details about calls to the AuditLog method.
+		AuditLog []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Admin is the admin argument value.
+			Admin string
+			// Filter is the filter argument value.
+			Filter storage.AuditFilter
+		}
+		// CancelGroupPurchase holds details about calls to the CancelGroupPurchase method.
+		CancelGroupPurchase []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Name is the name argument value.
+			Name string
+			// ID is the id argument value.
+			ID int
+		}
+		// CancelItemPrice holds details about calls to the CancelItemPrice method.
+		CancelItemPrice []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Admin is the admin argument value.
+			Admin string
+			// ID is the id argument value.
+			ID int
+		}
+		// CancelPaymentRequest holds details about calls to the CancelPaymentRequest method.
+		CancelPaymentRequest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// ID is the id argument value.
+			ID int
+		}
+		// CancelScheduledTransfer holds details about calls to the CancelScheduledTransfer method.
+		CancelScheduledTransfer []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// ID is the id argument value.
+			ID int
+		}
+		// Catalog holds details about calls to the Catalog method.
+		Catalog []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
 		// CollectAllInfo holds details about calls to the CollectAllInfo method.
 		CollectAllInfo []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+		}
+		// CreateGroup holds details about calls to the CreateGroup method.
+		CreateGroup []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// In is the in argument value.
+			In *GroupInput
+		}
+		// CreatePromoCode holds details about calls to the CreatePromoCode method.
+		CreatePromoCode []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Admin is the admin argument value.
+			Admin string
+			// In is the in argument value.
+			In *PromoCodeInput
+		}
+		// DeclinePaymentRequest holds details about calls to the DeclinePaymentRequest method.
+		DeclinePaymentRequest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// ID is the id argument value.
+			ID int
+		}
+		// DisablePromoCode holds details about calls to the DisablePromoCode method.
+		DisablePromoCode []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Admin is the admin argument value.
+			Admin string
+			// Code is the code argument value.
+			Code string
+		}
+		// ExpireCoins holds details about calls to the ExpireCoins method.
+		ExpireCoins []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// ExpirePendingTransfers holds details about calls to the ExpirePendingTransfers method.
+		ExpirePendingTransfers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// ExportAuditLog holds details about calls to the ExportAuditLog method.
+		ExportAuditLog []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Admin is the admin argument value.
+			Admin string
+			// Filter is the filter argument value.
+			Filter storage.AuditFilter
+			// Write is the write argument value.
+			Write func([]storage.AuditEntry) error
+		}
+		// GetGroup holds details about calls to the GetGroup method.
+		GetGroup []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Name is the name argument value.
+			Name string
+		}
+		// GroupHistory holds details about calls to the GroupHistory method.
+		GroupHistory []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Name is the name argument value.
+			Name string
+			// Filter is the filter argument value.
+			Filter storage.HistoryFilter
+		}
+		// History holds details about calls to the History method.
+		History []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Filter is the filter argument value.
+			Filter storage.HistoryFilter
+		}
+		// Leaderboard holds details about calls to the Leaderboard method.
+		Leaderboard []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Board is the board argument value.
+			Board string
+			// Period is the period argument value.
+			Period string
+			// Limit is the limit argument value.
+			Limit int
+		}
+		// ListGroupPurchases holds details about calls to the ListGroupPurchases method.
+		ListGroupPurchases []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Name is the name argument value.
+			Name string
+		}
+		// ListGroups holds details about calls to the ListGroups method.
+		ListGroups []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+		}
+		// ListItemPrices holds details about calls to the ListItemPrices method.
+		ListItemPrices []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Admin is the admin argument value.
+			Admin string
+		}
+		// ListOrders holds details about calls to the ListOrders method.
+		ListOrders []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+		}
+		// ListPaymentRequests holds details about calls to the ListPaymentRequests method.
+		ListPaymentRequests []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Direction is the direction argument value.
+			Direction string
+		}
+		// ListPendingTransfers holds details about calls to the ListPendingTransfers method.
+		ListPendingTransfers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+		}
+		// ListPromoCodes holds details about calls to the ListPromoCodes method.
+		ListPromoCodes []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Admin is the admin argument value.
+			Admin string
+		}
+		// ListScheduledTransfers holds details about calls to the ListScheduledTransfers method.
+		ListScheduledTransfers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+		}
+		// ListUserTransactions holds details about calls to the ListUserTransactions method.
+		ListUserTransactions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Admin is the admin argument value.
+			Admin string
+			// Username is the username argument value.
+			Username string
+		}
+		// ProposeGroupPurchase holds details about calls to the ProposeGroupPurchase method.
+		ProposeGroupPurchase []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Username is the username argument value.
 			Username string
+			// Name is the name argument value.
+			Name string
+			// Item is the item argument value.
+			Item string
 		}
 		// Purchase holds details about calls to the Purchase method.
 		Purchase []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Item is the item argument value.
+			Item string
+			// PromoCode is the promoCode argument value.
+			PromoCode string
+		}
+		// QuotePurchase holds details about calls to the QuotePurchase method.
+		QuotePurchase []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Username is the username argument value.
 			Username string
 			// Item is the item argument value.
 			Item string
+			// PromoCode is the promoCode argument value.
+			PromoCode string
+		}
+		// RecordAuth holds details about calls to the RecordAuth method.
+		RecordAuth []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Action is the action argument value.
+			Action string
+		}
+		// RejectPendingTransfer holds details about calls to the RejectPendingTransfer method.
+		RejectPendingTransfer []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Approver is the approver argument value.
+			Approver string
+			// ID is the id argument value.
+			ID int
+		}
+		// RemoveGroupMember holds details about calls to the RemoveGroupMember method.
+		RemoveGroupMember []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Name is the name argument value.
+			Name string
+			// Member is the member argument value.
+			Member string
+		}
+		// RequestPayment holds details about calls to the RequestPayment method.
+		RequestPayment []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Requester is the requester argument value.
+			Requester string
+			// Req is the req argument value.
+			Req *PaymentRequestInput
+		}
+		// ReverseTransfer holds details about calls to the ReverseTransfer method.
+		ReverseTransfer []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Admin is the admin argument value.
+			Admin string
+			// In is the in argument value.
+			In *ReversalInput
+		}
+		// RunDueTransfers holds details about calls to the RunDueTransfers method.
+		RunDueTransfers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// ScheduleItemPrice holds details about calls to the ScheduleItemPrice method.
+		ScheduleItemPrice []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Admin is the admin argument value.
+			Admin string
+			// In is the in argument value.
+			In *ItemPriceInput
+		}
+		// ScheduleTransfer holds details about calls to the ScheduleTransfer method.
+		ScheduleTransfer []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// FromUsername is the fromUsername argument value.
+			FromUsername string
+			// In is the in argument value.
+			In *ScheduledTransferInput
 		}
 		// Send holds details about calls to the Send method.
 		Send []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// FromUsername is the fromUsername argument value.
 			FromUsername string
 			// Scr is the scr argument value.
 			Scr *storage.SendCoinRequest
 		}
+		// SendFromGroup holds details about calls to the SendFromGroup method.
+		SendFromGroup []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Name is the name argument value.
+			Name string
+			// Scr is the scr argument value.
+			Scr *storage.SendCoinRequest
+		}
+		// SetGroupMember holds details about calls to the SetGroupMember method.
+		SetGroupMember []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Name is the name argument value.
+			Name string
+			// In is the in argument value.
+			In *GroupMemberInput
+		}
+		// SetLeaderboardOptOut holds details about calls to the SetLeaderboardOptOut method.
+		SetLeaderboardOptOut []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// OptOut is the optOut argument value.
+			OptOut bool
+		}
+		// VerifyAuditLog holds details about calls to the VerifyAuditLog method.
+		VerifyAuditLog []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Admin is the admin argument value.
+			Admin string
+		}
 	}
-	lockCollectAllInfo sync.RWMutex
-	lockPurchase       sync.RWMutex
-	lockSend           sync.RWMutex
+	lockAcceptPaymentRequest    sync.RWMutex
+	lockApproveGroupPurchase    sync.RWMutex
+	lockApprovePendingTransfer  sync.RWMutex
+	lockAuditLog                sync.RWMutex
+	lockCancelGroupPurchase     sync.RWMutex
+	lockCancelItemPrice         sync.RWMutex
+	lockCancelPaymentRequest    sync.RWMutex
+	lockCancelScheduledTransfer sync.RWMutex
+	lockCatalog                 sync.RWMutex
+	lockCollectAllInfo          sync.RWMutex
+	lockCreateGroup             sync.RWMutex
+	lockCreatePromoCode         sync.RWMutex
+	lockDeclinePaymentRequest   sync.RWMutex
+	lockDisablePromoCode        sync.RWMutex
+	lockExpireCoins             sync.RWMutex
+	lockExpirePendingTransfers  sync.RWMutex
+	lockExportAuditLog          sync.RWMutex
+	lockGetGroup                sync.RWMutex
+	lockGroupHistory            sync.RWMutex
+	lockHistory                 sync.RWMutex
+	lockLeaderboard             sync.RWMutex
+	lockListGroupPurchases      sync.RWMutex
+	lockListGroups              sync.RWMutex
+	lockListItemPrices          sync.RWMutex
+	lockListOrders              sync.RWMutex
+	lockListPaymentRequests     sync.RWMutex
+	lockListPendingTransfers    sync.RWMutex
+	lockListPromoCodes          sync.RWMutex
+	lockListScheduledTransfers  sync.RWMutex
+	lockListUserTransactions    sync.RWMutex
+	lockProposeGroupPurchase    sync.RWMutex
+	lockPurchase                sync.RWMutex
+	lockQuotePurchase           sync.RWMutex
+	lockRecordAuth              sync.RWMutex
+	lockRejectPendingTransfer   sync.RWMutex
+	lockRemoveGroupMember       sync.RWMutex
+	lockRequestPayment          sync.RWMutex
+	lockReverseTransfer         sync.RWMutex
+	lockRunDueTransfers         sync.RWMutex
+	lockScheduleItemPrice       sync.RWMutex
+	lockScheduleTransfer        sync.RWMutex
+	lockSend                    sync.RWMutex
+	lockSendFromGroup           sync.RWMutex
+	lockSetGroupMember          sync.RWMutex
+	lockSetLeaderboardOptOut    sync.RWMutex
+	lockVerifyAuditLog          sync.RWMutex
 }
 
-// CollectAllInfo calls CollectAllInfoFunc.
-func (mock *IServiceMock) CollectAllInfo(username string) (*storage.InfoResponse, error) {
-	if mock.CollectAllInfoFunc == nil {
-		panic("IServiceMock.CollectAllInfoFunc: method is nil but IService.CollectAllInfo was just called")
+// AcceptPaymentRequest calls AcceptPaymentRequestFunc.
+func (mock *IServiceMock) AcceptPaymentRequest(ctx context.Context, username string, id int) (*storage.PaymentRequest, error) {
+	if mock.AcceptPaymentRequestFunc == nil {
+		panic("IServiceMock.AcceptPaymentRequestFunc: method is nil but IService.AcceptPaymentRequest was just called")
 	}
 	callInfo := struct {
+		Ctx      context.Context
 		Username string
+		ID       int
 	}{
+		Ctx:      ctx,
 		Username: username,
+		ID:       id,
 	}
-	mock.lockCollectAllInfo.Lock()
-	mock.calls.CollectAllInfo = append(mock.calls.CollectAllInfo, callInfo)
-	mock.lockCollectAllInfo.Unlock()
-	return mock.CollectAllInfoFunc(username)
+	mock.lockAcceptPaymentRequest.Lock()
+	mock.calls.AcceptPaymentRequest = append(mock.calls.AcceptPaymentRequest, callInfo)
+	mock.lockAcceptPaymentRequest.Unlock()
+	return mock.AcceptPaymentRequestFunc(ctx, username, id)
 }
 
-// CollectAllInfoCalls gets all the calls that were made to CollectAllInfo.
+// AcceptPaymentRequestCalls gets all the calls that were made to AcceptPaymentRequest.
 // Check the length with:
 //
-//	len(mockedIService.CollectAllInfoCalls())
-func (mock *IServiceMock) CollectAllInfoCalls() []struct {
+//	len(mockedIService.AcceptPaymentRequestCalls())
+func (mock *IServiceMock) AcceptPaymentRequestCalls() []struct {
+	Ctx      context.Context
 	Username string
+	ID       int
 } {
 	var calls []struct {
+		Ctx      context.Context
 		Username string
+		ID       int
 	}
-	mock.lockCollectAllInfo.RLock()
-	calls = mock.calls.CollectAllInfo
-	mock.lockCollectAllInfo.RUnlock()
+	mock.lockAcceptPaymentRequest.RLock()
+	calls = mock.calls.AcceptPaymentRequest
+	mock.lockAcceptPaymentRequest.RUnlock()
 	return calls
 }
 
-// Purchase calls PurchaseFunc.
-func (mock *IServiceMock) Purchase(username string, item string) error {
-	if mock.PurchaseFunc == nil {
-		panic("IServiceMock.PurchaseFunc: method is nil but IService.Purchase was just called")
+// ApproveGroupPurchase calls ApproveGroupPurchaseFunc.
+func (mock *IServiceMock) ApproveGroupPurchase(ctx context.Context, username string, name string, id int) (*storage.GroupPurchase, error) {
+	if mock.ApproveGroupPurchaseFunc == nil {
+		panic("IServiceMock.ApproveGroupPurchaseFunc: method is nil but IService.ApproveGroupPurchase was just called")
 	}
 	callInfo := struct {
+		Ctx      context.Context
 		Username string
-		Item     string
+		Name     string
+		ID       int
 	}{
+		Ctx:      ctx,
 		Username: username,
-		Item:     item,
+		Name:     name,
+		ID:       id,
 	}
-	mock.lockPurchase.Lock()
-	mock.calls.Purchase = append(mock.calls.Purchase, callInfo)
-	mock.lockPurchase.Unlock()
-	return mock.PurchaseFunc(username, item)
+	mock.lockApproveGroupPurchase.Lock()
+	mock.calls.ApproveGroupPurchase = append(mock.calls.ApproveGroupPurchase, callInfo)
+	mock.lockApproveGroupPurchase.Unlock()
+	return mock.ApproveGroupPurchaseFunc(ctx, username, name, id)
 }
 
-// PurchaseCalls gets all the calls that were made to Purchase.
+// ApproveGroupPurchaseCalls gets all the calls that were made to ApproveGroupPurchase.
 // Check the length with:
 //
-//	len(mockedIService.PurchaseCalls())
-func (mock *IServiceMock) PurchaseCalls() []struct {
+//	len(mockedIService.ApproveGroupPurchaseCalls())
+func (mock *IServiceMock) ApproveGroupPurchaseCalls() []struct {
+	Ctx      context.Context
 	Username string
-	Item     string
+	Name     string
+	ID       int
 } {
 	var calls []struct {
+		Ctx      context.Context
 		Username string
-		Item     string
+		Name     string
+		ID       int
 	}
-	mock.lockPurchase.RLock()
-	calls = mock.calls.Purchase
-	mock.lockPurchase.RUnlock()
+	mock.lockApproveGroupPurchase.RLock()
+	calls = mock.calls.ApproveGroupPurchase
+	mock.lockApproveGroupPurchase.RUnlock()
 	return calls
 }
 
-// Send calls SendFunc.
-func (mock *IServiceMock) Send(fromUsername string, scr *storage.SendCoinRequest) error {
-	if mock.SendFunc == nil {
-		panic("IServiceMock.SendFunc: method is nil but IService.Send was just called")
+// ApprovePendingTransfer calls ApprovePendingTransferFunc.
+func (mock *IServiceMock) ApprovePendingTransfer(ctx context.Context, approver string, id int) (*storage.PendingTransfer, error) {
+	if mock.ApprovePendingTransferFunc == nil {
+		panic("IServiceMock.ApprovePendingTransferFunc: method is nil but IService.ApprovePendingTransfer was just called")
 	}
 	callInfo := struct {
-		FromUsername string
-		Scr          *storage.SendCoinRequest
+		Ctx      context.Context
+		Approver string
+		ID       int
 	}{
-		FromUsername: fromUsername,
-		Scr:          scr,
+		Ctx:      ctx,
+		Approver: approver,
+		ID:       id,
 	}
-	mock.lockSend.Lock()
-	mock.calls.Send = append(mock.calls.Send, callInfo)
-	mock.lockSend.Unlock()
-	return mock.SendFunc(fromUsername, scr)
+	mock.lockApprovePendingTransfer.Lock()
+	mock.calls.ApprovePendingTransfer = append(mock.calls.ApprovePendingTransfer, callInfo)
+	mock.lockApprovePendingTransfer.Unlock()
+	return mock.ApprovePendingTransferFunc(ctx, approver, id)
 }
 
-// SendCalls gets all the calls that were made to Send.
+// ApprovePendingTransferCalls gets all the calls that were made to ApprovePendingTransfer.
 // Check the length with:
 //
-//	len(mockedIService.SendCalls())
-func (mock *IServiceMock) SendCalls() []struct {
-	FromUsername string
-	Scr          *storage.SendCoinRequest
+//	len(mockedIService.ApprovePendingTransferCalls())
+func (mock *IServiceMock) ApprovePendingTransferCalls() []struct {
+	Ctx      context.Context
+	Approver string
+	ID       int
 } {
 	var calls []struct {
-		FromUsername string
-		Scr          *storage.SendCoinRequest
+		Ctx      context.Context
+		Approver string
+		ID       int
 	}
-	mock.lockSend.RLock()
-	calls = mock.calls.Send
-	mock.lockSend.RUnlock()
+	mock.lockApprovePendingTransfer.RLock()
+	calls = mock.calls.ApprovePendingTransfer
+	mock.lockApprovePendingTransfer.RUnlock()
+	return calls
+}
+
+// AuditLog calls AuditLogFunc.
+func (mock *IServiceMock) AuditLog(ctx context.Context, admin string, filter storage.AuditFilter) ([]storage.AuditEntry, error) {
+	if mock.AuditLogFunc == nil {
+		panic("IServiceMock.AuditLogFunc: method is nil but IService.AuditLog was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Admin  string
+		Filter storage.AuditFilter
+	}{
+		Ctx:    ctx,
+		Admin:  admin,
+		Filter: filter,
+	}
+	mock.lockAuditLog.Lock()
+	mock.calls.AuditLog = append(mock.calls.AuditLog, callInfo)
+	mock.lockAuditLog.Unlock()
+	return mock.AuditLogFunc(ctx, admin, filter)
+}
+
+// AuditLogCalls gets all the calls that were made to AuditLog.
+// Check the length with:
+//
+//	len(mockedIService.AuditLogCalls())
+func (mock *IServiceMock) AuditLogCalls() []struct {
+	Ctx    context.Context
+	Admin  string
+	Filter storage.AuditFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Admin  string
+		Filter storage.AuditFilter
+	}
+	mock.lockAuditLog.RLock()
+	calls = mock.calls.AuditLog
+	mock.lockAuditLog.RUnlock()
+	return calls
+}
+
+// CancelGroupPurchase calls CancelGroupPurchaseFunc.
+func (mock *IServiceMock) CancelGroupPurchase(ctx context.Context, username string, name string, id int) (*storage.GroupPurchase, error) {
+	if mock.CancelGroupPurchaseFunc == nil {
+		panic("IServiceMock.CancelGroupPurchaseFunc: method is nil but IService.CancelGroupPurchase was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+		ID       int
+	}{
+		Ctx:      ctx,
+		Username: username,
+		Name:     name,
+		ID:       id,
+	}
+	mock.lockCancelGroupPurchase.Lock()
+	mock.calls.CancelGroupPurchase = append(mock.calls.CancelGroupPurchase, callInfo)
+	mock.lockCancelGroupPurchase.Unlock()
+	return mock.CancelGroupPurchaseFunc(ctx, username, name, id)
+}
+
+// CancelGroupPurchaseCalls gets all the calls that were made to CancelGroupPurchase.
+// Check the length with:
+//
+//	len(mockedIService.CancelGroupPurchaseCalls())
+func (mock *IServiceMock) CancelGroupPurchaseCalls() []struct {
+	Ctx      context.Context
+	Username string
+	Name     string
+	ID       int
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+		ID       int
+	}
+	mock.lockCancelGroupPurchase.RLock()
+	calls = mock.calls.CancelGroupPurchase
+	mock.lockCancelGroupPurchase.RUnlock()
+	return calls
+}
+
+// CancelItemPrice calls CancelItemPriceFunc.
+func (mock *IServiceMock) CancelItemPrice(ctx context.Context, admin string, id int) (*storage.ItemPrice, error) {
+	if mock.CancelItemPriceFunc == nil {
+		panic("IServiceMock.CancelItemPriceFunc: method is nil but IService.CancelItemPrice was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Admin string
+		ID    int
+	}{
+		Ctx:   ctx,
+		Admin: admin,
+		ID:    id,
+	}
+	mock.lockCancelItemPrice.Lock()
+	mock.calls.CancelItemPrice = append(mock.calls.CancelItemPrice, callInfo)
+	mock.lockCancelItemPrice.Unlock()
+	return mock.CancelItemPriceFunc(ctx, admin, id)
+}
+
+// CancelItemPriceCalls gets all the calls that were made to CancelItemPrice.
+// Check the length with:
+//
+//	len(mockedIService.CancelItemPriceCalls())
+func (mock *IServiceMock) CancelItemPriceCalls() []struct {
+	Ctx   context.Context
+	Admin string
+	ID    int
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Admin string
+		ID    int
+	}
+	mock.lockCancelItemPrice.RLock()
+	calls = mock.calls.CancelItemPrice
+	mock.lockCancelItemPrice.RUnlock()
+	return calls
+}
+
+// CancelPaymentRequest calls CancelPaymentRequestFunc.
+func (mock *IServiceMock) CancelPaymentRequest(ctx context.Context, username string, id int) (*storage.PaymentRequest, error) {
+	if mock.CancelPaymentRequestFunc == nil {
+		panic("IServiceMock.CancelPaymentRequestFunc: method is nil but IService.CancelPaymentRequest was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		ID       int
+	}{
+		Ctx:      ctx,
+		Username: username,
+		ID:       id,
+	}
+	mock.lockCancelPaymentRequest.Lock()
+	mock.calls.CancelPaymentRequest = append(mock.calls.CancelPaymentRequest, callInfo)
+	mock.lockCancelPaymentRequest.Unlock()
+	return mock.CancelPaymentRequestFunc(ctx, username, id)
+}
+
+// CancelPaymentRequestCalls gets all the calls that were made to CancelPaymentRequest.
+// Check the length with:
+//
+//	len(mockedIService.CancelPaymentRequestCalls())
+func (mock *IServiceMock) CancelPaymentRequestCalls() []struct {
+	Ctx      context.Context
+	Username string
+	ID       int
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		ID       int
+	}
+	mock.lockCancelPaymentRequest.RLock()
+	calls = mock.calls.CancelPaymentRequest
+	mock.lockCancelPaymentRequest.RUnlock()
+	return calls
+}
+
+// CancelScheduledTransfer calls CancelScheduledTransferFunc.
+func (mock *IServiceMock) CancelScheduledTransfer(ctx context.Context, username string, id int) (*storage.ScheduledTransfer, error) {
+	if mock.CancelScheduledTransferFunc == nil {
+		panic("IServiceMock.CancelScheduledTransferFunc: method is nil but IService.CancelScheduledTransfer was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		ID       int
+	}{
+		Ctx:      ctx,
+		Username: username,
+		ID:       id,
+	}
+	mock.lockCancelScheduledTransfer.Lock()
+	mock.calls.CancelScheduledTransfer = append(mock.calls.CancelScheduledTransfer, callInfo)
+	mock.lockCancelScheduledTransfer.Unlock()
+	return mock.CancelScheduledTransferFunc(ctx, username, id)
+}
+
+// CancelScheduledTransferCalls gets all the calls that were made to CancelScheduledTransfer.
+// Check the length with:
+//
+//	len(mockedIService.CancelScheduledTransferCalls())
+func (mock *IServiceMock) CancelScheduledTransferCalls() []struct {
+	Ctx      context.Context
+	Username string
+	ID       int
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		ID       int
+	}
+	mock.lockCancelScheduledTransfer.RLock()
+	calls = mock.calls.CancelScheduledTransfer
+	mock.lockCancelScheduledTransfer.RUnlock()
+	return calls
+}
+
+// Catalog calls CatalogFunc.
+func (mock *IServiceMock) Catalog(ctx context.Context) ([]storage.CatalogItem, error) {
+	if mock.CatalogFunc == nil {
+		panic("IServiceMock.CatalogFunc: method is nil but IService.Catalog was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockCatalog.Lock()
+	mock.calls.Catalog = append(mock.calls.Catalog, callInfo)
+	mock.lockCatalog.Unlock()
+	return mock.CatalogFunc(ctx)
+}
+
+// CatalogCalls gets all the calls that were made to Catalog.
+// Check the length with:
+//
+//	len(mockedIService.CatalogCalls())
+func (mock *IServiceMock) CatalogCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockCatalog.RLock()
+	calls = mock.calls.Catalog
+	mock.lockCatalog.RUnlock()
+	return calls
+}
+
+// CollectAllInfo calls CollectAllInfoFunc.
+func (mock *IServiceMock) CollectAllInfo(ctx context.Context, username string) (*storage.InfoResponse, error) {
+	if mock.CollectAllInfoFunc == nil {
+		panic("IServiceMock.CollectAllInfoFunc: method is nil but IService.CollectAllInfo was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+	}{
+		Ctx:      ctx,
+		Username: username,
+	}
+	mock.lockCollectAllInfo.Lock()
+	mock.calls.CollectAllInfo = append(mock.calls.CollectAllInfo, callInfo)
+	mock.lockCollectAllInfo.Unlock()
+	return mock.CollectAllInfoFunc(ctx, username)
+}
+
+// CollectAllInfoCalls gets all the calls that were made to CollectAllInfo.
+// Check the length with:
+//
+//	len(mockedIService.CollectAllInfoCalls())
+func (mock *IServiceMock) CollectAllInfoCalls() []struct {
+	Ctx      context.Context
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+	}
+	mock.lockCollectAllInfo.RLock()
+	calls = mock.calls.CollectAllInfo
+	mock.lockCollectAllInfo.RUnlock()
+	return calls
+}
+
+// CreateGroup calls CreateGroupFunc.
+func (mock *IServiceMock) CreateGroup(ctx context.Context, username string, in *GroupInput) (*storage.Group, error) {
+	if mock.CreateGroupFunc == nil {
+		panic("IServiceMock.CreateGroupFunc: method is nil but IService.CreateGroup was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		In       *GroupInput
+	}{
+		Ctx:      ctx,
+		Username: username,
+		In:       in,
+	}
+	mock.lockCreateGroup.Lock()
+	mock.calls.CreateGroup = append(mock.calls.CreateGroup, callInfo)
+	mock.lockCreateGroup.Unlock()
+	return mock.CreateGroupFunc(ctx, username, in)
+}
+
+// CreateGroupCalls gets all the calls that were made to CreateGroup.
+// Check the length with:
+//
+//	len(mockedIService.CreateGroupCalls())
+func (mock *IServiceMock) CreateGroupCalls() []struct {
+	Ctx      context.Context
+	Username string
+	In       *GroupInput
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		In       *GroupInput
+	}
+	mock.lockCreateGroup.RLock()
+	calls = mock.calls.CreateGroup
+	mock.lockCreateGroup.RUnlock()
+	return calls
+}
+
+// CreatePromoCode calls CreatePromoCodeFunc.
+func (mock *IServiceMock) CreatePromoCode(ctx context.Context, admin string, in *PromoCodeInput) (*storage.PromoCode, error) {
+	if mock.CreatePromoCodeFunc == nil {
+		panic("IServiceMock.CreatePromoCodeFunc: method is nil but IService.CreatePromoCode was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Admin string
+		In    *PromoCodeInput
+	}{
+		Ctx:   ctx,
+		Admin: admin,
+		In:    in,
+	}
+	mock.lockCreatePromoCode.Lock()
+	mock.calls.CreatePromoCode = append(mock.calls.CreatePromoCode, callInfo)
+	mock.lockCreatePromoCode.Unlock()
+	return mock.CreatePromoCodeFunc(ctx, admin, in)
+}
+
+// CreatePromoCodeCalls gets all the calls that were made to CreatePromoCode.
+// Check the length with:
+//
+//	len(mockedIService.CreatePromoCodeCalls())
+func (mock *IServiceMock) CreatePromoCodeCalls() []struct {
+	Ctx   context.Context
+	Admin string
+	In    *PromoCodeInput
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Admin string
+		In    *PromoCodeInput
+	}
+	mock.lockCreatePromoCode.RLock()
+	calls = mock.calls.CreatePromoCode
+	mock.lockCreatePromoCode.RUnlock()
+	return calls
+}
+
+// DeclinePaymentRequest calls DeclinePaymentRequestFunc.
+func (mock *IServiceMock) DeclinePaymentRequest(ctx context.Context, username string, id int) (*storage.PaymentRequest, error) {
+	if mock.DeclinePaymentRequestFunc == nil {
+		panic("IServiceMock.DeclinePaymentRequestFunc: method is nil but IService.DeclinePaymentRequest was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		ID       int
+	}{
+		Ctx:      ctx,
+		Username: username,
+		ID:       id,
+	}
+	mock.lockDeclinePaymentRequest.Lock()
+	mock.calls.DeclinePaymentRequest = append(mock.calls.DeclinePaymentRequest, callInfo)
+	mock.lockDeclinePaymentRequest.Unlock()
+	return mock.DeclinePaymentRequestFunc(ctx, username, id)
+}
+
+// DeclinePaymentRequestCalls gets all the calls that were made to DeclinePaymentRequest.
+// Check the length with:
+//
+//	len(mockedIService.DeclinePaymentRequestCalls())
+func (mock *IServiceMock) DeclinePaymentRequestCalls() []struct {
+	Ctx      context.Context
+	Username string
+	ID       int
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		ID       int
+	}
+	mock.lockDeclinePaymentRequest.RLock()
+	calls = mock.calls.DeclinePaymentRequest
+	mock.lockDeclinePaymentRequest.RUnlock()
+	return calls
+}
+
+// DisablePromoCode calls DisablePromoCodeFunc.
+func (mock *IServiceMock) DisablePromoCode(ctx context.Context, admin string, code string) (*storage.PromoCode, error) {
+	if mock.DisablePromoCodeFunc == nil {
+		panic("IServiceMock.DisablePromoCodeFunc: method is nil but IService.DisablePromoCode was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Admin string
+		Code  string
+	}{
+		Ctx:   ctx,
+		Admin: admin,
+		Code:  code,
+	}
+	mock.lockDisablePromoCode.Lock()
+	mock.calls.DisablePromoCode = append(mock.calls.DisablePromoCode, callInfo)
+	mock.lockDisablePromoCode.Unlock()
+	return mock.DisablePromoCodeFunc(ctx, admin, code)
+}
+
+// DisablePromoCodeCalls gets all the calls that were made to DisablePromoCode.
+// Check the length with:
+//
+//	len(mockedIService.DisablePromoCodeCalls())
+func (mock *IServiceMock) DisablePromoCodeCalls() []struct {
+	Ctx   context.Context
+	Admin string
+	Code  string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Admin string
+		Code  string
+	}
+	mock.lockDisablePromoCode.RLock()
+	calls = mock.calls.DisablePromoCode
+	mock.lockDisablePromoCode.RUnlock()
+	return calls
+}
+
+// ExpireCoins calls ExpireCoinsFunc.
+func (mock *IServiceMock) ExpireCoins(ctx context.Context) ([]storage.ExpiredCoins, error) {
+	if mock.ExpireCoinsFunc == nil {
+		panic("IServiceMock.ExpireCoinsFunc: method is nil but IService.ExpireCoins was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockExpireCoins.Lock()
+	mock.calls.ExpireCoins = append(mock.calls.ExpireCoins, callInfo)
+	mock.lockExpireCoins.Unlock()
+	return mock.ExpireCoinsFunc(ctx)
+}
+
+// ExpireCoinsCalls gets all the calls that were made to ExpireCoins.
+// Check the length with:
+//
+//	len(mockedIService.ExpireCoinsCalls())
+func (mock *IServiceMock) ExpireCoinsCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockExpireCoins.RLock()
+	calls = mock.calls.ExpireCoins
+	mock.lockExpireCoins.RUnlock()
+	return calls
+}
+
+// ExpirePendingTransfers calls ExpirePendingTransfersFunc.
+func (mock *IServiceMock) ExpirePendingTransfers(ctx context.Context) ([]storage.PendingTransfer, error) {
+	if mock.ExpirePendingTransfersFunc == nil {
+		panic("IServiceMock.ExpirePendingTransfersFunc: method is nil but IService.ExpirePendingTransfers was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockExpirePendingTransfers.Lock()
+	mock.calls.ExpirePendingTransfers = append(mock.calls.ExpirePendingTransfers, callInfo)
+	mock.lockExpirePendingTransfers.Unlock()
+	return mock.ExpirePendingTransfersFunc(ctx)
+}
+
+// ExpirePendingTransfersCalls gets all the calls that were made to ExpirePendingTransfers.
+// Check the length with:
+//
+//	len(mockedIService.ExpirePendingTransfersCalls())
+func (mock *IServiceMock) ExpirePendingTransfersCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockExpirePendingTransfers.RLock()
+	calls = mock.calls.ExpirePendingTransfers
+	mock.lockExpirePendingTransfers.RUnlock()
+	return calls
+}
+
+// ExportAuditLog calls ExportAuditLogFunc.
+func (mock *IServiceMock) ExportAuditLog(ctx context.Context, admin string, filter storage.AuditFilter, write func([]storage.AuditEntry) error) error {
+	if mock.ExportAuditLogFunc == nil {
+		panic("IServiceMock.ExportAuditLogFunc: method is nil but IService.ExportAuditLog was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Admin  string
+		Filter storage.AuditFilter
+		Write  func([]storage.AuditEntry) error
+	}{
+		Ctx:    ctx,
+		Admin:  admin,
+		Filter: filter,
+		Write:  write,
+	}
+	mock.lockExportAuditLog.Lock()
+	mock.calls.ExportAuditLog = append(mock.calls.ExportAuditLog, callInfo)
+	mock.lockExportAuditLog.Unlock()
+	return mock.ExportAuditLogFunc(ctx, admin, filter, write)
+}
+
+// ExportAuditLogCalls gets all the calls that were made to ExportAuditLog.
+// Check the length with:
+//
+//	len(mockedIService.ExportAuditLogCalls())
+func (mock *IServiceMock) ExportAuditLogCalls() []struct {
+	Ctx    context.Context
+	Admin  string
+	Filter storage.AuditFilter
+	Write  func([]storage.AuditEntry) error
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Admin  string
+		Filter storage.AuditFilter
+		Write  func([]storage.AuditEntry) error
+	}
+	mock.lockExportAuditLog.RLock()
+	calls = mock.calls.ExportAuditLog
+	mock.lockExportAuditLog.RUnlock()
+	return calls
+}
+
+// GetGroup calls GetGroupFunc.
+func (mock *IServiceMock) GetGroup(ctx context.Context, username string, name string) (*storage.GroupInfo, error) {
+	if mock.GetGroupFunc == nil {
+		panic("IServiceMock.GetGroupFunc: method is nil but IService.GetGroup was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+	}{
+		Ctx:      ctx,
+		Username: username,
+		Name:     name,
+	}
+	mock.lockGetGroup.Lock()
+	mock.calls.GetGroup = append(mock.calls.GetGroup, callInfo)
+	mock.lockGetGroup.Unlock()
+	return mock.GetGroupFunc(ctx, username, name)
+}
+
+// GetGroupCalls gets all the calls that were made to GetGroup.
+// Check the length with:
+//
+//	len(mockedIService.GetGroupCalls())
+func (mock *IServiceMock) GetGroupCalls() []struct {
+	Ctx      context.Context
+	Username string
+	Name     string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+	}
+	mock.lockGetGroup.RLock()
+	calls = mock.calls.GetGroup
+	mock.lockGetGroup.RUnlock()
+	return calls
+}
+
+// GroupHistory calls GroupHistoryFunc.
+func (mock *IServiceMock) GroupHistory(ctx context.Context, username string, name string, filter storage.HistoryFilter) (*storage.CoinHistory, error) {
+	if mock.GroupHistoryFunc == nil {
+		panic("IServiceMock.GroupHistoryFunc: method is nil but IService.GroupHistory was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+		Filter   storage.HistoryFilter
+	}{
+		Ctx:      ctx,
+		Username: username,
+		Name:     name,
+		Filter:   filter,
+	}
+	mock.lockGroupHistory.Lock()
+	mock.calls.GroupHistory = append(mock.calls.GroupHistory, callInfo)
+	mock.lockGroupHistory.Unlock()
+	return mock.GroupHistoryFunc(ctx, username, name, filter)
+}
+
+// GroupHistoryCalls gets all the calls that were made to GroupHistory.
+// Check the length with:
+//
+//	len(mockedIService.GroupHistoryCalls())
+func (mock *IServiceMock) GroupHistoryCalls() []struct {
+	Ctx      context.Context
+	Username string
+	Name     string
+	Filter   storage.HistoryFilter
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+		Filter   storage.HistoryFilter
+	}
+	mock.lockGroupHistory.RLock()
+	calls = mock.calls.GroupHistory
+	mock.lockGroupHistory.RUnlock()
+	return calls
+}
+
+// History calls HistoryFunc.
+func (mock *IServiceMock) History(ctx context.Context, username string, filter storage.HistoryFilter) (*storage.CoinHistory, error) {
+	if mock.HistoryFunc == nil {
+		panic("IServiceMock.HistoryFunc: method is nil but IService.History was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		Filter   storage.HistoryFilter
+	}{
+		Ctx:      ctx,
+		Username: username,
+		Filter:   filter,
+	}
+	mock.lockHistory.Lock()
+	mock.calls.History = append(mock.calls.History, callInfo)
+	mock.lockHistory.Unlock()
+	return mock.HistoryFunc(ctx, username, filter)
+}
+
+// HistoryCalls gets all the calls that were made to History.
+// Check the length with:
+//
+//	len(mockedIService.HistoryCalls())
+func (mock *IServiceMock) HistoryCalls() []struct {
+	Ctx      context.Context
+	Username string
+	Filter   storage.HistoryFilter
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		Filter   storage.HistoryFilter
+	}
+	mock.lockHistory.RLock()
+	calls = mock.calls.History
+	mock.lockHistory.RUnlock()
+	return calls
+}
+
+// Leaderboard calls LeaderboardFunc.
+func (mock *IServiceMock) Leaderboard(ctx context.Context, board string, period string, limit int) (*storage.Leaderboard, error) {
+	if mock.LeaderboardFunc == nil {
+		panic("IServiceMock.LeaderboardFunc: method is nil but IService.Leaderboard was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Board  string
+		Period string
+		Limit  int
+	}{
+		Ctx:    ctx,
+		Board:  board,
+		Period: period,
+		Limit:  limit,
+	}
+	mock.lockLeaderboard.Lock()
+	mock.calls.Leaderboard = append(mock.calls.Leaderboard, callInfo)
+	mock.lockLeaderboard.Unlock()
+	return mock.LeaderboardFunc(ctx, board, period, limit)
+}
+
+// LeaderboardCalls gets all the calls that were made to Leaderboard.
+// Check the length with:
+//
+//	len(mockedIService.LeaderboardCalls())
+func (mock *IServiceMock) LeaderboardCalls() []struct {
+	Ctx    context.Context
+	Board  string
+	Period string
+	Limit  int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Board  string
+		Period string
+		Limit  int
+	}
+	mock.lockLeaderboard.RLock()
+	calls = mock.calls.Leaderboard
+	mock.lockLeaderboard.RUnlock()
+	return calls
+}
+
+// ListGroupPurchases calls ListGroupPurchasesFunc.
+func (mock *IServiceMock) ListGroupPurchases(ctx context.Context, username string, name string) ([]storage.GroupPurchase, error) {
+	if mock.ListGroupPurchasesFunc == nil {
+		panic("IServiceMock.ListGroupPurchasesFunc: method is nil but IService.ListGroupPurchases was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+	}{
+		Ctx:      ctx,
+		Username: username,
+		Name:     name,
+	}
+	mock.lockListGroupPurchases.Lock()
+	mock.calls.ListGroupPurchases = append(mock.calls.ListGroupPurchases, callInfo)
+	mock.lockListGroupPurchases.Unlock()
+	return mock.ListGroupPurchasesFunc(ctx, username, name)
+}
+
+// ListGroupPurchasesCalls gets all the calls that were made to ListGroupPurchases.
+// Check the length with:
+//
+//	len(mockedIService.ListGroupPurchasesCalls())
+func (mock *IServiceMock) ListGroupPurchasesCalls() []struct {
+	Ctx      context.Context
+	Username string
+	Name     string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+	}
+	mock.lockListGroupPurchases.RLock()
+	calls = mock.calls.ListGroupPurchases
+	mock.lockListGroupPurchases.RUnlock()
+	return calls
+}
+
+// ListGroups calls ListGroupsFunc.
+func (mock *IServiceMock) ListGroups(ctx context.Context, username string) ([]storage.Group, error) {
+	if mock.ListGroupsFunc == nil {
+		panic("IServiceMock.ListGroupsFunc: method is nil but IService.ListGroups was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+	}{
+		Ctx:      ctx,
+		Username: username,
+	}
+	mock.lockListGroups.Lock()
+	mock.calls.ListGroups = append(mock.calls.ListGroups, callInfo)
+	mock.lockListGroups.Unlock()
+	return mock.ListGroupsFunc(ctx, username)
+}
+
+// ListGroupsCalls gets all the calls that were made to ListGroups.
+// Check the length with:
+//
+//	len(mockedIService.ListGroupsCalls())
+func (mock *IServiceMock) ListGroupsCalls() []struct {
+	Ctx      context.Context
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+	}
+	mock.lockListGroups.RLock()
+	calls = mock.calls.ListGroups
+	mock.lockListGroups.RUnlock()
+	return calls
+}
+
+// ListItemPrices calls ListItemPricesFunc.
+func (mock *IServiceMock) ListItemPrices(ctx context.Context, admin string) ([]storage.ItemPrice, error) {
+	if mock.ListItemPricesFunc == nil {
+		panic("IServiceMock.ListItemPricesFunc: method is nil but IService.ListItemPrices was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Admin string
+	}{
+		Ctx:   ctx,
+		Admin: admin,
+	}
+	mock.lockListItemPrices.Lock()
+	mock.calls.ListItemPrices = append(mock.calls.ListItemPrices, callInfo)
+	mock.lockListItemPrices.Unlock()
+	return mock.ListItemPricesFunc(ctx, admin)
+}
+
+// ListItemPricesCalls gets all the calls that were made to ListItemPrices.
+// Check the length with:
+//
+//	len(mockedIService.ListItemPricesCalls())
+func (mock *IServiceMock) ListItemPricesCalls() []struct {
+	Ctx   context.Context
+	Admin string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Admin string
+	}
+	mock.lockListItemPrices.RLock()
+	calls = mock.calls.ListItemPrices
+	mock.lockListItemPrices.RUnlock()
+	return calls
+}
+
+// ListOrders calls ListOrdersFunc.
+func (mock *IServiceMock) ListOrders(ctx context.Context, username string) ([]storage.Order, error) {
+	if mock.ListOrdersFunc == nil {
+		panic("IServiceMock.ListOrdersFunc: method is nil but IService.ListOrders was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+	}{
+		Ctx:      ctx,
+		Username: username,
+	}
+	mock.lockListOrders.Lock()
+	mock.calls.ListOrders = append(mock.calls.ListOrders, callInfo)
+	mock.lockListOrders.Unlock()
+	return mock.ListOrdersFunc(ctx, username)
+}
+
+// ListOrdersCalls gets all the calls that were made to ListOrders.
+// Check the length with:
+//
+//	len(mockedIService.ListOrdersCalls())
+func (mock *IServiceMock) ListOrdersCalls() []struct {
+	Ctx      context.Context
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+	}
+	mock.lockListOrders.RLock()
+	calls = mock.calls.ListOrders
+	mock.lockListOrders.RUnlock()
+	return calls
+}
+
+// ListPaymentRequests calls ListPaymentRequestsFunc.
+func (mock *IServiceMock) ListPaymentRequests(ctx context.Context, username string, direction string) ([]storage.PaymentRequest, error) {
+	if mock.ListPaymentRequestsFunc == nil {
+		panic("IServiceMock.ListPaymentRequestsFunc: method is nil but IService.ListPaymentRequests was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Username  string
+		Direction string
+	}{
+		Ctx:       ctx,
+		Username:  username,
+		Direction: direction,
+	}
+	mock.lockListPaymentRequests.Lock()
+	mock.calls.ListPaymentRequests = append(mock.calls.ListPaymentRequests, callInfo)
+	mock.lockListPaymentRequests.Unlock()
+	return mock.ListPaymentRequestsFunc(ctx, username, direction)
+}
+
+// ListPaymentRequestsCalls gets all the calls that were made to ListPaymentRequests.
+// Check the length with:
+//
+//	len(mockedIService.ListPaymentRequestsCalls())
+func (mock *IServiceMock) ListPaymentRequestsCalls() []struct {
+	Ctx       context.Context
+	Username  string
+	Direction string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Username  string
+		Direction string
+	}
+	mock.lockListPaymentRequests.RLock()
+	calls = mock.calls.ListPaymentRequests
+	mock.lockListPaymentRequests.RUnlock()
+	return calls
+}
+
+// ListPendingTransfers calls ListPendingTransfersFunc.
+func (mock *IServiceMock) ListPendingTransfers(ctx context.Context, username string) ([]storage.PendingTransfer, error) {
+	if mock.ListPendingTransfersFunc == nil {
+		panic("IServiceMock.ListPendingTransfersFunc: method is nil but IService.ListPendingTransfers was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+	}{
+		Ctx:      ctx,
+		Username: username,
+	}
+	mock.lockListPendingTransfers.Lock()
+	mock.calls.ListPendingTransfers = append(mock.calls.ListPendingTransfers, callInfo)
+	mock.lockListPendingTransfers.Unlock()
+	return mock.ListPendingTransfersFunc(ctx, username)
+}
+
+// ListPendingTransfersCalls gets all the calls that were made to ListPendingTransfers.
+// Check the length with:
+//
+//	len(mockedIService.ListPendingTransfersCalls())
+func (mock *IServiceMock) ListPendingTransfersCalls() []struct {
+	Ctx      context.Context
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+	}
+	mock.lockListPendingTransfers.RLock()
+	calls = mock.calls.ListPendingTransfers
+	mock.lockListPendingTransfers.RUnlock()
+	return calls
+}
+
+// ListPromoCodes calls ListPromoCodesFunc.
+func (mock *IServiceMock) ListPromoCodes(ctx context.Context, admin string) ([]storage.PromoCode, error) {
+	if mock.ListPromoCodesFunc == nil {
+		panic("IServiceMock.ListPromoCodesFunc: method is nil but IService.ListPromoCodes was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Admin string
+	}{
+		Ctx:   ctx,
+		Admin: admin,
+	}
+	mock.lockListPromoCodes.Lock()
+	mock.calls.ListPromoCodes = append(mock.calls.ListPromoCodes, callInfo)
+	mock.lockListPromoCodes.Unlock()
+	return mock.ListPromoCodesFunc(ctx, admin)
+}
+
+// ListPromoCodesCalls gets all the calls that were made to ListPromoCodes.
+// Check the length with:
+//
+//	len(mockedIService.ListPromoCodesCalls())
+func (mock *IServiceMock) ListPromoCodesCalls() []struct {
+	Ctx   context.Context
+	Admin string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Admin string
+	}
+	mock.lockListPromoCodes.RLock()
+	calls = mock.calls.ListPromoCodes
+	mock.lockListPromoCodes.RUnlock()
+	return calls
+}
+
+// ListScheduledTransfers calls ListScheduledTransfersFunc.
+func (mock *IServiceMock) ListScheduledTransfers(ctx context.Context, username string) ([]storage.ScheduledTransfer, error) {
+	if mock.ListScheduledTransfersFunc == nil {
+		panic("IServiceMock.ListScheduledTransfersFunc: method is nil but IService.ListScheduledTransfers was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+	}{
+		Ctx:      ctx,
+		Username: username,
+	}
+	mock.lockListScheduledTransfers.Lock()
+	mock.calls.ListScheduledTransfers = append(mock.calls.ListScheduledTransfers, callInfo)
+	mock.lockListScheduledTransfers.Unlock()
+	return mock.ListScheduledTransfersFunc(ctx, username)
+}
+
+// ListScheduledTransfersCalls gets all the calls that were made to ListScheduledTransfers.
+// Check the length with:
+//
+//	len(mockedIService.ListScheduledTransfersCalls())
+func (mock *IServiceMock) ListScheduledTransfersCalls() []struct {
+	Ctx      context.Context
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+	}
+	mock.lockListScheduledTransfers.RLock()
+	calls = mock.calls.ListScheduledTransfers
+	mock.lockListScheduledTransfers.RUnlock()
+	return calls
+}
+
+// ListUserTransactions calls ListUserTransactionsFunc.
+func (mock *IServiceMock) ListUserTransactions(ctx context.Context, admin string, username string) ([]storage.Transaction, error) {
+	if mock.ListUserTransactionsFunc == nil {
+		panic("IServiceMock.ListUserTransactionsFunc: method is nil but IService.ListUserTransactions was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Admin    string
+		Username string
+	}{
+		Ctx:      ctx,
+		Admin:    admin,
+		Username: username,
+	}
+	mock.lockListUserTransactions.Lock()
+	mock.calls.ListUserTransactions = append(mock.calls.ListUserTransactions, callInfo)
+	mock.lockListUserTransactions.Unlock()
+	return mock.ListUserTransactionsFunc(ctx, admin, username)
+}
+
+// ListUserTransactionsCalls gets all the calls that were made to ListUserTransactions.
+// Check the length with:
+//
+//	len(mockedIService.ListUserTransactionsCalls())
+func (mock *IServiceMock) ListUserTransactionsCalls() []struct {
+	Ctx      context.Context
+	Admin    string
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Admin    string
+		Username string
+	}
+	mock.lockListUserTransactions.RLock()
+	calls = mock.calls.ListUserTransactions
+	mock.lockListUserTransactions.RUnlock()
+	return calls
+}
+
+// ProposeGroupPurchase calls ProposeGroupPurchaseFunc.
+func (mock *IServiceMock) ProposeGroupPurchase(ctx context.Context, username string, name string, item string) (*storage.GroupPurchase, error) {
+	if mock.ProposeGroupPurchaseFunc == nil {
+		panic("IServiceMock.ProposeGroupPurchaseFunc: method is nil but IService.ProposeGroupPurchase was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+		Item     string
+	}{
+		Ctx:      ctx,
+		Username: username,
+		Name:     name,
+		Item:     item,
+	}
+	mock.lockProposeGroupPurchase.Lock()
+	mock.calls.ProposeGroupPurchase = append(mock.calls.ProposeGroupPurchase, callInfo)
+	mock.lockProposeGroupPurchase.Unlock()
+	return mock.ProposeGroupPurchaseFunc(ctx, username, name, item)
+}
+
+// ProposeGroupPurchaseCalls gets all the calls that were made to ProposeGroupPurchase.
+// Check the length with:
+//
+//	len(mockedIService.ProposeGroupPurchaseCalls())
+func (mock *IServiceMock) ProposeGroupPurchaseCalls() []struct {
+	Ctx      context.Context
+	Username string
+	Name     string
+	Item     string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+		Item     string
+	}
+	mock.lockProposeGroupPurchase.RLock()
+	calls = mock.calls.ProposeGroupPurchase
+	mock.lockProposeGroupPurchase.RUnlock()
+	return calls
+}
+
+// Purchase calls PurchaseFunc.
+func (mock *IServiceMock) Purchase(ctx context.Context, username string, item string, promoCode string) error {
+	if mock.PurchaseFunc == nil {
+		panic("IServiceMock.PurchaseFunc: method is nil but IService.Purchase was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Username  string
+		Item      string
+		PromoCode string
+	}{
+		Ctx:       ctx,
+		Username:  username,
+		Item:      item,
+		PromoCode: promoCode,
+	}
+	mock.lockPurchase.Lock()
+	mock.calls.Purchase = append(mock.calls.Purchase, callInfo)
+	mock.lockPurchase.Unlock()
+	return mock.PurchaseFunc(ctx, username, item, promoCode)
+}
+
+// PurchaseCalls gets all the calls that were made to Purchase.
+// Check the length with:
+//
+//	len(mockedIService.PurchaseCalls())
+func (mock *IServiceMock) PurchaseCalls() []struct {
+	Ctx       context.Context
+	Username  string
+	Item      string
+	PromoCode string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Username  string
+		Item      string
+		PromoCode string
+	}
+	mock.lockPurchase.RLock()
+	calls = mock.calls.Purchase
+	mock.lockPurchase.RUnlock()
+	return calls
+}
+
+// QuotePurchase calls QuotePurchaseFunc.
+func (mock *IServiceMock) QuotePurchase(ctx context.Context, username string, item string, promoCode string) (*storage.PriceQuote, error) {
+	if mock.QuotePurchaseFunc == nil {
+		panic("IServiceMock.QuotePurchaseFunc: method is nil but IService.QuotePurchase was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Username  string
+		Item      string
+		PromoCode string
+	}{
+		Ctx:       ctx,
+		Username:  username,
+		Item:      item,
+		PromoCode: promoCode,
+	}
+	mock.lockQuotePurchase.Lock()
+	mock.calls.QuotePurchase = append(mock.calls.QuotePurchase, callInfo)
+	mock.lockQuotePurchase.Unlock()
+	return mock.QuotePurchaseFunc(ctx, username, item, promoCode)
+}
+
+// QuotePurchaseCalls gets all the calls that were made to QuotePurchase.
+// Check the length with:
+//
+//	len(mockedIService.QuotePurchaseCalls())
+func (mock *IServiceMock) QuotePurchaseCalls() []struct {
+	Ctx       context.Context
+	Username  string
+	Item      string
+	PromoCode string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Username  string
+		Item      string
+		PromoCode string
+	}
+	mock.lockQuotePurchase.RLock()
+	calls = mock.calls.QuotePurchase
+	mock.lockQuotePurchase.RUnlock()
+	return calls
+}
+
+// RecordAuth calls RecordAuthFunc.
+func (mock *IServiceMock) RecordAuth(ctx context.Context, username string, action string) {
+	if mock.RecordAuthFunc == nil {
+		panic("IServiceMock.RecordAuthFunc: method is nil but IService.RecordAuth was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		Action   string
+	}{
+		Ctx:      ctx,
+		Username: username,
+		Action:   action,
+	}
+	mock.lockRecordAuth.Lock()
+	mock.calls.RecordAuth = append(mock.calls.RecordAuth, callInfo)
+	mock.lockRecordAuth.Unlock()
+	mock.RecordAuthFunc(ctx, username, action)
+}
+
+// RecordAuthCalls gets all the calls that were made to RecordAuth.
+// Check the length with:
+//
+//	len(mockedIService.RecordAuthCalls())
+func (mock *IServiceMock) RecordAuthCalls() []struct {
+	Ctx      context.Context
+	Username string
+	Action   string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		Action   string
+	}
+	mock.lockRecordAuth.RLock()
+	calls = mock.calls.RecordAuth
+	mock.lockRecordAuth.RUnlock()
+	return calls
+}
+
+// RejectPendingTransfer calls RejectPendingTransferFunc.
+func (mock *IServiceMock) RejectPendingTransfer(ctx context.Context, approver string, id int) (*storage.PendingTransfer, error) {
+	if mock.RejectPendingTransferFunc == nil {
+		panic("IServiceMock.RejectPendingTransferFunc: method is nil but IService.RejectPendingTransfer was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Approver string
+		ID       int
+	}{
+		Ctx:      ctx,
+		Approver: approver,
+		ID:       id,
+	}
+	mock.lockRejectPendingTransfer.Lock()
+	mock.calls.RejectPendingTransfer = append(mock.calls.RejectPendingTransfer, callInfo)
+	mock.lockRejectPendingTransfer.Unlock()
+	return mock.RejectPendingTransferFunc(ctx, approver, id)
+}
+
+// RejectPendingTransferCalls gets all the calls that were made to RejectPendingTransfer.
+// Check the length with:
+//
+//	len(mockedIService.RejectPendingTransferCalls())
+func (mock *IServiceMock) RejectPendingTransferCalls() []struct {
+	Ctx      context.Context
+	Approver string
+	ID       int
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Approver string
+		ID       int
+	}
+	mock.lockRejectPendingTransfer.RLock()
+	calls = mock.calls.RejectPendingTransfer
+	mock.lockRejectPendingTransfer.RUnlock()
+	return calls
+}
+
+// RemoveGroupMember calls RemoveGroupMemberFunc.
+func (mock *IServiceMock) RemoveGroupMember(ctx context.Context, username string, name string, member string) ([]storage.GroupMember, error) {
+	if mock.RemoveGroupMemberFunc == nil {
+		panic("IServiceMock.RemoveGroupMemberFunc: method is nil but IService.RemoveGroupMember was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+		Member   string
+	}{
+		Ctx:      ctx,
+		Username: username,
+		Name:     name,
+		Member:   member,
+	}
+	mock.lockRemoveGroupMember.Lock()
+	mock.calls.RemoveGroupMember = append(mock.calls.RemoveGroupMember, callInfo)
+	mock.lockRemoveGroupMember.Unlock()
+	return mock.RemoveGroupMemberFunc(ctx, username, name, member)
+}
+
+// RemoveGroupMemberCalls gets all the calls that were made to RemoveGroupMember.
+// Check the length with:
+//
+//	len(mockedIService.RemoveGroupMemberCalls())
+func (mock *IServiceMock) RemoveGroupMemberCalls() []struct {
+	Ctx      context.Context
+	Username string
+	Name     string
+	Member   string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+		Member   string
+	}
+	mock.lockRemoveGroupMember.RLock()
+	calls = mock.calls.RemoveGroupMember
+	mock.lockRemoveGroupMember.RUnlock()
+	return calls
+}
+
+// RequestPayment calls RequestPaymentFunc.
+func (mock *IServiceMock) RequestPayment(ctx context.Context, requester string, req *PaymentRequestInput) (*storage.PaymentRequest, error) {
+	if mock.RequestPaymentFunc == nil {
+		panic("IServiceMock.RequestPaymentFunc: method is nil but IService.RequestPayment was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Requester string
+		Req       *PaymentRequestInput
+	}{
+		Ctx:       ctx,
+		Requester: requester,
+		Req:       req,
+	}
+	mock.lockRequestPayment.Lock()
+	mock.calls.RequestPayment = append(mock.calls.RequestPayment, callInfo)
+	mock.lockRequestPayment.Unlock()
+	return mock.RequestPaymentFunc(ctx, requester, req)
+}
+
+// RequestPaymentCalls gets all the calls that were made to RequestPayment.
+// Check the length with:
+//
+//	len(mockedIService.RequestPaymentCalls())
+func (mock *IServiceMock) RequestPaymentCalls() []struct {
+	Ctx       context.Context
+	Requester string
+	Req       *PaymentRequestInput
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Requester string
+		Req       *PaymentRequestInput
+	}
+	mock.lockRequestPayment.RLock()
+	calls = mock.calls.RequestPayment
+	mock.lockRequestPayment.RUnlock()
+	return calls
+}
+
+// ReverseTransfer calls ReverseTransferFunc.
+func (mock *IServiceMock) ReverseTransfer(ctx context.Context, admin string, in *ReversalInput) (*storage.TransferReversal, error) {
+	if mock.ReverseTransferFunc == nil {
+		panic("IServiceMock.ReverseTransferFunc: method is nil but IService.ReverseTransfer was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Admin string
+		In    *ReversalInput
+	}{
+		Ctx:   ctx,
+		Admin: admin,
+		In:    in,
+	}
+	mock.lockReverseTransfer.Lock()
+	mock.calls.ReverseTransfer = append(mock.calls.ReverseTransfer, callInfo)
+	mock.lockReverseTransfer.Unlock()
+	return mock.ReverseTransferFunc(ctx, admin, in)
+}
+
+// ReverseTransferCalls gets all the calls that were made to ReverseTransfer.
+// Check the length with:
+//
+//	len(mockedIService.ReverseTransferCalls())
+func (mock *IServiceMock) ReverseTransferCalls() []struct {
+	Ctx   context.Context
+	Admin string
+	In    *ReversalInput
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Admin string
+		In    *ReversalInput
+	}
+	mock.lockReverseTransfer.RLock()
+	calls = mock.calls.ReverseTransfer
+	mock.lockReverseTransfer.RUnlock()
+	return calls
+}
+
+// RunDueTransfers calls RunDueTransfersFunc.
+func (mock *IServiceMock) RunDueTransfers(ctx context.Context) ([]storage.ScheduledTransfer, error) {
+	if mock.RunDueTransfersFunc == nil {
+		panic("IServiceMock.RunDueTransfersFunc: method is nil but IService.RunDueTransfers was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockRunDueTransfers.Lock()
+	mock.calls.RunDueTransfers = append(mock.calls.RunDueTransfers, callInfo)
+	mock.lockRunDueTransfers.Unlock()
+	return mock.RunDueTransfersFunc(ctx)
+}
+
+// RunDueTransfersCalls gets all the calls that were made to RunDueTransfers.
+// Check the length with:
+//
+//	len(mockedIService.RunDueTransfersCalls())
+func (mock *IServiceMock) RunDueTransfersCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockRunDueTransfers.RLock()
+	calls = mock.calls.RunDueTransfers
+	mock.lockRunDueTransfers.RUnlock()
+	return calls
+}
+
+// ScheduleItemPrice calls ScheduleItemPriceFunc.
+func (mock *IServiceMock) ScheduleItemPrice(ctx context.Context, admin string, in *ItemPriceInput) (*storage.ItemPrice, error) {
+	if mock.ScheduleItemPriceFunc == nil {
+		panic("IServiceMock.ScheduleItemPriceFunc: method is nil but IService.ScheduleItemPrice was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Admin string
+		In    *ItemPriceInput
+	}{
+		Ctx:   ctx,
+		Admin: admin,
+		In:    in,
+	}
+	mock.lockScheduleItemPrice.Lock()
+	mock.calls.ScheduleItemPrice = append(mock.calls.ScheduleItemPrice, callInfo)
+	mock.lockScheduleItemPrice.Unlock()
+	return mock.ScheduleItemPriceFunc(ctx, admin, in)
+}
+
+// ScheduleItemPriceCalls gets all the calls that were made to ScheduleItemPrice.
+// Check the length with:
+//
+//	len(mockedIService.ScheduleItemPriceCalls())
+func (mock *IServiceMock) ScheduleItemPriceCalls() []struct {
+	Ctx   context.Context
+	Admin string
+	In    *ItemPriceInput
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Admin string
+		In    *ItemPriceInput
+	}
+	mock.lockScheduleItemPrice.RLock()
+	calls = mock.calls.ScheduleItemPrice
+	mock.lockScheduleItemPrice.RUnlock()
+	return calls
+}
+
+// ScheduleTransfer calls ScheduleTransferFunc.
+func (mock *IServiceMock) ScheduleTransfer(ctx context.Context, fromUsername string, in *ScheduledTransferInput) (*storage.ScheduledTransfer, error) {
+	if mock.ScheduleTransferFunc == nil {
+		panic("IServiceMock.ScheduleTransferFunc: method is nil but IService.ScheduleTransfer was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		FromUsername string
+		In           *ScheduledTransferInput
+	}{
+		Ctx:          ctx,
+		FromUsername: fromUsername,
+		In:           in,
+	}
+	mock.lockScheduleTransfer.Lock()
+	mock.calls.ScheduleTransfer = append(mock.calls.ScheduleTransfer, callInfo)
+	mock.lockScheduleTransfer.Unlock()
+	return mock.ScheduleTransferFunc(ctx, fromUsername, in)
+}
+
+// ScheduleTransferCalls gets all the calls that were made to ScheduleTransfer.
+// Check the length with:
+//
+//	len(mockedIService.ScheduleTransferCalls())
+func (mock *IServiceMock) ScheduleTransferCalls() []struct {
+	Ctx          context.Context
+	FromUsername string
+	In           *ScheduledTransferInput
+} {
+	var calls []struct {
+		Ctx          context.Context
+		FromUsername string
+		In           *ScheduledTransferInput
+	}
+	mock.lockScheduleTransfer.RLock()
+	calls = mock.calls.ScheduleTransfer
+	mock.lockScheduleTransfer.RUnlock()
+	return calls
+}
+
+// Send calls SendFunc.
+func (mock *IServiceMock) Send(ctx context.Context, fromUsername string, scr *storage.SendCoinRequest) error {
+	if mock.SendFunc == nil {
+		panic("IServiceMock.SendFunc: method is nil but IService.Send was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		FromUsername string
+		Scr          *storage.SendCoinRequest
+	}{
+		Ctx:          ctx,
+		FromUsername: fromUsername,
+		Scr:          scr,
+	}
+	mock.lockSend.Lock()
+	mock.calls.Send = append(mock.calls.Send, callInfo)
+	mock.lockSend.Unlock()
+	return mock.SendFunc(ctx, fromUsername, scr)
+}
+
+// SendCalls gets all the calls that were made to Send.
+// Check the length with:
+//
+//	len(mockedIService.SendCalls())
+func (mock *IServiceMock) SendCalls() []struct {
+	Ctx          context.Context
+	FromUsername string
+	Scr          *storage.SendCoinRequest
+} {
+	var calls []struct {
+		Ctx          context.Context
+		FromUsername string
+		Scr          *storage.SendCoinRequest
+	}
+	mock.lockSend.RLock()
+	calls = mock.calls.Send
+	mock.lockSend.RUnlock()
+	return calls
+}
+
+// SendFromGroup calls SendFromGroupFunc.
+func (mock *IServiceMock) SendFromGroup(ctx context.Context, username string, name string, scr *storage.SendCoinRequest) error {
+	if mock.SendFromGroupFunc == nil {
+		panic("IServiceMock.SendFromGroupFunc: method is nil but IService.SendFromGroup was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+		Scr      *storage.SendCoinRequest
+	}{
+		Ctx:      ctx,
+		Username: username,
+		Name:     name,
+		Scr:      scr,
+	}
+	mock.lockSendFromGroup.Lock()
+	mock.calls.SendFromGroup = append(mock.calls.SendFromGroup, callInfo)
+	mock.lockSendFromGroup.Unlock()
+	return mock.SendFromGroupFunc(ctx, username, name, scr)
+}
+
+// SendFromGroupCalls gets all the calls that were made to SendFromGroup.
+// Check the length with:
+//
+//	len(mockedIService.SendFromGroupCalls())
+func (mock *IServiceMock) SendFromGroupCalls() []struct {
+	Ctx      context.Context
+	Username string
+	Name     string
+	Scr      *storage.SendCoinRequest
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+		Scr      *storage.SendCoinRequest
+	}
+	mock.lockSendFromGroup.RLock()
+	calls = mock.calls.SendFromGroup
+	mock.lockSendFromGroup.RUnlock()
+	return calls
+}
+
+// SetGroupMember calls SetGroupMemberFunc.
+func (mock *IServiceMock) SetGroupMember(ctx context.Context, username string, name string, in *GroupMemberInput) ([]storage.GroupMember, error) {
+	if mock.SetGroupMemberFunc == nil {
+		panic("IServiceMock.SetGroupMemberFunc: method is nil but IService.SetGroupMember was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+		In       *GroupMemberInput
+	}{
+		Ctx:      ctx,
+		Username: username,
+		Name:     name,
+		In:       in,
+	}
+	mock.lockSetGroupMember.Lock()
+	mock.calls.SetGroupMember = append(mock.calls.SetGroupMember, callInfo)
+	mock.lockSetGroupMember.Unlock()
+	return mock.SetGroupMemberFunc(ctx, username, name, in)
+}
+
+// SetGroupMemberCalls gets all the calls that were made to SetGroupMember.
+// Check the length with:
+//
+//	len(mockedIService.SetGroupMemberCalls())
+func (mock *IServiceMock) SetGroupMemberCalls() []struct {
+	Ctx      context.Context
+	Username string
+	Name     string
+	In       *GroupMemberInput
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		Name     string
+		In       *GroupMemberInput
+	}
+	mock.lockSetGroupMember.RLock()
+	calls = mock.calls.SetGroupMember
+	mock.lockSetGroupMember.RUnlock()
+	return calls
+}
+
+// SetLeaderboardOptOut calls SetLeaderboardOptOutFunc.
+func (mock *IServiceMock) SetLeaderboardOptOut(ctx context.Context, username string, optOut bool) error {
+	if mock.SetLeaderboardOptOutFunc == nil {
+		panic("IServiceMock.SetLeaderboardOptOutFunc: method is nil but IService.SetLeaderboardOptOut was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		OptOut   bool
+	}{
+		Ctx:      ctx,
+		Username: username,
+		OptOut:   optOut,
+	}
+	mock.lockSetLeaderboardOptOut.Lock()
+	mock.calls.SetLeaderboardOptOut = append(mock.calls.SetLeaderboardOptOut, callInfo)
+	mock.lockSetLeaderboardOptOut.Unlock()
+	return mock.SetLeaderboardOptOutFunc(ctx, username, optOut)
+}
+
+// SetLeaderboardOptOutCalls gets all the calls that were made to SetLeaderboardOptOut.
+// Check the length with:
+//
+//	len(mockedIService.SetLeaderboardOptOutCalls())
+func (mock *IServiceMock) SetLeaderboardOptOutCalls() []struct {
+	Ctx      context.Context
+	Username string
+	OptOut   bool
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		OptOut   bool
+	}
+	mock.lockSetLeaderboardOptOut.RLock()
+	calls = mock.calls.SetLeaderboardOptOut
+	mock.lockSetLeaderboardOptOut.RUnlock()
+	return calls
+}
+
+// VerifyAuditLog calls VerifyAuditLogFunc.
+func (mock *IServiceMock) VerifyAuditLog(ctx context.Context, admin string) (*AuditVerification, error) {
+	if mock.VerifyAuditLogFunc == nil {
+		panic("IServiceMock.VerifyAuditLogFunc: method is nil but IService.VerifyAuditLog was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Admin string
+	}{
+		Ctx:   ctx,
+		Admin: admin,
+	}
+	mock.lockVerifyAuditLog.Lock()
+	mock.calls.VerifyAuditLog = append(mock.calls.VerifyAuditLog, callInfo)
+	mock.lockVerifyAuditLog.Unlock()
+	return mock.VerifyAuditLogFunc(ctx, admin)
+}
+
+// VerifyAuditLogCalls gets all the calls that were made to VerifyAuditLog.
+// Check the length with:
+//
+//	len(mockedIService.VerifyAuditLogCalls())
+func (mock *IServiceMock) VerifyAuditLogCalls() []struct {
+	Ctx   context.Context
+	Admin string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Admin string
+	}
+	mock.lockVerifyAuditLog.RLock()
+	calls = mock.calls.VerifyAuditLog
+	mock.lockVerifyAuditLog.RUnlock()
 	return calls
 }
@@ -0,0 +1,233 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"avito-shop/internal/metrics"
+	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Направления выборки запросов на перевод.
+const (
+	// PaymentRequestsIncoming — запросы, которые должен оплатить пользователь.
+	PaymentRequestsIncoming = "incoming"
+	// PaymentRequestsOutgoing — запросы, созданные пользователем.
+	PaymentRequestsOutgoing = "outgoing"
+)
+
+// PaymentRequestInput — просьба перевести Amount монет от FromUser автору запроса.
+type PaymentRequestInput struct {
+	FromUser string `json:"fromUser"`
+	Amount   int    `json:"amount"`
+	Memo     string `json:"memo,omitempty"`
+}
+
+func (s *Service) RequestPayment(ctx context.Context, requester string, req *PaymentRequestInput) (_ *storage.PaymentRequest, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.RequestPayment", trace.WithAttributes(attribute.Int("shop.amount", req.Amount)))
+	defer func() { tracing.End(span, err) }()
+
+	if err = ValidatePaymentRequestInput(requester, req); err != nil {
+		return nil, err
+	}
+	if err = s.checkUnheldApproval(req.Amount); err != nil {
+		return nil, err
+	}
+
+	requesterID, err := s.userID(ctx, requester)
+	if err != nil {
+		return nil, err
+	}
+	payerID, err := s.userID(ctx, req.FromUser)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	pr := &storage.PaymentRequest{
+		RequesterID: requesterID,
+		Requester:   requester,
+		PayerID:     payerID,
+		Payer:       req.FromUser,
+		Amount:      req.Amount,
+		Memo:        req.Memo,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.PaymentRequestTTL),
+	}
+	if err = s.Storage.CreatePaymentRequest(ctx, pr); err != nil {
+		return nil, ErrInternalServer
+	}
+	s.audit(ctx, requester, AuditPaymentRequest, auditTarget("payment_request", pr.ID), nil, pr)
+
+	return pr, nil
+}
+
+func (s *Service) ListPaymentRequests(ctx context.Context, username string, direction string) (_ []storage.PaymentRequest, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ListPaymentRequests", trace.WithAttributes(attribute.String("shop.direction", direction)))
+	defer func() { tracing.End(span, err) }()
+
+	if direction == "" {
+		direction = PaymentRequestsIncoming
+	}
+	if direction != PaymentRequestsIncoming && direction != PaymentRequestsOutgoing {
+		ve := &ValidationError{}
+		ve.add("direction", "допустимы значения incoming и outgoing")
+		return nil, ve
+	}
+
+	id, err := s.userID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	prs, err := s.Storage.ListPendingPaymentRequests(ctx, id, direction == PaymentRequestsIncoming, s.now())
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+	if prs == nil {
+		prs = []storage.PaymentRequest{}
+	}
+
+	return prs, nil
+}
+
+// AcceptPaymentRequest оплачивает запрос: проверки те же, что у Send, а закрытие запроса
+// и перевод монет выполняются в хранилище одной транзакцией.
+func (s *Service) AcceptPaymentRequest(ctx context.Context, username string, id int) (_ *storage.PaymentRequest, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.AcceptPaymentRequest", trace.WithAttributes(attribute.Int("shop.payment_request.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	pr, err := s.pendingPaymentRequest(ctx, id, func(pr *storage.PaymentRequest) bool {
+		return strings.EqualFold(pr.Payer, username)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scr := &storage.SendCoinRequest{ToUser: pr.Requester, Amount: pr.Amount, Memo: pr.Memo}
+	if err = ValidateSendCoinRequest(pr.Payer, scr); err != nil {
+		return nil, err
+	}
+	// Порог мог уменьшиться после создания запроса.
+	if err = s.checkUnheldApproval(pr.Amount); err != nil {
+		return nil, err
+	}
+
+	limits, err := s.transferLimits(ctx, pr.PayerID, pr.RequesterID, pr.Amount)
+	if err != nil {
+		return nil, err
+	}
+	fee, err := s.transferFee(ctx, pr.PayerID, pr.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := s.txAudit(ctx, username, AuditPaymentAccept, auditTarget("payment_request", pr.ID), pr.PayerID, pr.RequesterID)
+	err = s.Storage.AcceptPaymentRequest(ctx, id, s.now(), limits, fee, audit)
+	if le, ok := limitError(err); ok {
+		return nil, le
+	}
+	switch {
+	case errors.Is(err, storage.ErrInsufficientFunds):
+		metrics.InsufficientFundsTotal.WithLabelValues(metrics.OperationSend).Inc()
+		return nil, ErrInsufficientFunds
+	case errors.Is(err, storage.ErrPaymentRequestNotPending):
+		return nil, ErrPaymentRequestNotPending
+	case err != nil:
+		return nil, ErrInternalServer
+	}
+	metrics.CoinsTransferredTotal.Add(float64(pr.Amount))
+	metrics.FeesCollectedTotal.Add(float64(fee.Amount))
+	s.publishTransfer(ctx, pr.PayerID, pr.Payer, pr.RequesterID, pr.Requester, pr.Amount, fee.Amount)
+
+	pr.Status = storage.PaymentRequestAccepted
+	return pr, nil
+}
+
+// DeclinePaymentRequest отклоняет запрос; отклонить может только плательщик.
+func (s *Service) DeclinePaymentRequest(ctx context.Context, username string, id int) (_ *storage.PaymentRequest, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.DeclinePaymentRequest", trace.WithAttributes(attribute.Int("shop.payment_request.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	return s.resolvePaymentRequest(ctx, username, AuditPaymentDecline, id, storage.PaymentRequestDeclined, func(pr *storage.PaymentRequest) bool {
+		return strings.EqualFold(pr.Payer, username)
+	})
+}
+
+// CancelPaymentRequest отзывает запрос; отозвать может только его автор.
+func (s *Service) CancelPaymentRequest(ctx context.Context, username string, id int) (_ *storage.PaymentRequest, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.CancelPaymentRequest", trace.WithAttributes(attribute.Int("shop.payment_request.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	return s.resolvePaymentRequest(ctx, username, AuditPaymentCancel, id, storage.PaymentRequestCancelled, func(pr *storage.PaymentRequest) bool {
+		return strings.EqualFold(pr.Requester, username)
+	})
+}
+
+// resolvePaymentRequest закрывает запрос без перевода монет и записывает в журнал аудита действие action от имени username.
+func (s *Service) resolvePaymentRequest(ctx context.Context, username, action string, id int, status string,
+	allowed func(*storage.PaymentRequest) bool) (*storage.PaymentRequest, error) {
+	pr, err := s.pendingPaymentRequest(ctx, id, allowed)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Storage.ResolvePaymentRequest(ctx, id, status, s.now())
+	switch {
+	case errors.Is(err, storage.ErrPaymentRequestNotPending):
+		return nil, ErrPaymentRequestNotPending
+	case err != nil:
+		return nil, ErrInternalServer
+	}
+
+	pr.Status = status
+	s.audit(ctx, username, action, auditTarget("payment_request", pr.ID), auditStatus{storage.PaymentRequestPending}, pr)
+	return pr, nil
+}
+
+// pendingPaymentRequest загружает запрос, доступный пользователю по allowed, и проверяет, что он ещё открыт.
+// Чужой запрос неотличим от несуществующего. Истёкший запрос помечается expired.
+func (s *Service) pendingPaymentRequest(ctx context.Context, id int, allowed func(*storage.PaymentRequest) bool) (*storage.PaymentRequest, error) {
+	pr, err := s.Storage.GetPaymentRequest(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrPaymentRequestNotFound) {
+			return nil, ErrPaymentRequestNotFound
+		}
+		return nil, ErrInternalServer
+	}
+	if !allowed(pr) {
+		return nil, ErrPaymentRequestNotFound
+	}
+	if pr.Status != storage.PaymentRequestPending {
+		return nil, ErrPaymentRequestNotPending
+	}
+
+	now := s.now()
+	if !pr.ExpiresAt.After(now) {
+		err = s.Storage.ResolvePaymentRequest(ctx, id, storage.PaymentRequestExpired, now)
+		if err != nil && !errors.Is(err, storage.ErrPaymentRequestNotPending) {
+			return nil, ErrInternalServer
+		}
+		return nil, ErrPaymentRequestExpired
+	}
+
+	return pr, nil
+}
+
+// userID возвращает id пользователя или ErrUserNotFound.
+func (s *Service) userID(ctx context.Context, username string) (int, error) {
+	var ir storage.InfoResponse
+	id, err := s.Storage.GetInfo(ctx, &ir, username)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return 0, ErrUserNotFound
+		}
+		return 0, ErrInternalServer
+	}
+	return id, nil
+}
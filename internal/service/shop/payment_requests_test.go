@@ -0,0 +1,222 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestPayment_TableDriven(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	users := func(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+		switch username {
+		case "requester":
+			return 1, nil
+		case "payer":
+			return 2, nil
+		}
+		return 0, storage.ErrUserNotFound
+	}
+
+	tests := []struct {
+		name          string
+		req           *PaymentRequestInput
+		expectedError error
+		expectedField string
+	}{
+		{
+			name: "Successful request",
+			req:  &PaymentRequestInput{FromUser: "payer", Amount: 40, Memo: "за пиццу"},
+		},
+		{
+			name:          "Request from self",
+			req:           &PaymentRequestInput{FromUser: "requester", Amount: 40},
+			expectedField: "fromUser",
+		},
+		{
+			name:          "Non-positive amount",
+			req:           &PaymentRequestInput{FromUser: "payer", Amount: 0},
+			expectedField: "amount",
+		},
+		{
+			name:          "Unknown payer",
+			req:           &PaymentRequestInput{FromUser: "ghost", Amount: 40},
+			expectedError: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *storage.PaymentRequest
+			mockStorage := &storage.IStorageMock{
+				AppendAuditFunc: nopAppendAudit,
+				GetInfoFunc:     users,
+				CreatePaymentRequestFunc: func(ctx context.Context, pr *storage.PaymentRequest) error {
+					pr.ID, pr.Status = 5, storage.PaymentRequestPending
+					created = pr
+					return nil
+				},
+			}
+			s := NewService(mockStorage)
+			s.now = func() time.Time { return now }
+
+			pr, err := s.RequestPayment(context.Background(), "requester", tt.req)
+
+			if tt.expectedField != "" {
+				var ve *ValidationError
+				assert.ErrorAs(t, err, &ve)
+				assert.Equal(t, tt.expectedField, ve.Fields[0].Field)
+				assert.Nil(t, created)
+				return
+			}
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, created)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &storage.PaymentRequest{
+				ID: 5, RequesterID: 1, Requester: "requester", PayerID: 2, Payer: "payer",
+				Amount: 40, Memo: "за пиццу", Status: storage.PaymentRequestPending,
+				CreatedAt: now, ExpiresAt: now.Add(DefaultPaymentRequestTTL),
+			}, pr)
+		})
+	}
+}
+
+func TestAcceptPaymentRequest_TableDriven(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	pending := storage.PaymentRequest{
+		ID: 7, RequesterID: 1, Requester: "requester", PayerID: 2, Payer: "payer", Amount: 40,
+		Status: storage.PaymentRequestPending, CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour),
+	}
+
+	tests := []struct {
+		name           string
+		username       string
+		stored         storage.PaymentRequest
+		acceptErr      error
+		expectedError  error
+		expectAccept   bool
+		expectResolved string
+	}{
+		{
+			name:         "Payer accepts",
+			username:     "payer",
+			stored:       pending,
+			expectAccept: true,
+		},
+		{
+			name:          "Requester cannot accept own request",
+			username:      "requester",
+			stored:        pending,
+			expectedError: ErrPaymentRequestNotFound,
+		},
+		{
+			name:     "Already declined",
+			username: "payer",
+			stored: func() storage.PaymentRequest {
+				pr := pending
+				pr.Status = storage.PaymentRequestDeclined
+				return pr
+			}(),
+			expectedError: ErrPaymentRequestNotPending,
+		},
+		{
+			name:     "Expired request is closed",
+			username: "payer",
+			stored: func() storage.PaymentRequest {
+				pr := pending
+				pr.ExpiresAt = now
+				return pr
+			}(),
+			expectedError:  ErrPaymentRequestExpired,
+			expectResolved: storage.PaymentRequestExpired,
+		},
+		{
+			name:          "Insufficient funds",
+			username:      "payer",
+			stored:        pending,
+			acceptErr:     storage.ErrInsufficientFunds,
+			expectedError: ErrInsufficientFunds,
+			expectAccept:  true,
+		},
+		{
+			name:          "Accepted concurrently",
+			username:      "payer",
+			stored:        pending,
+			acceptErr:     storage.ErrPaymentRequestNotPending,
+			expectedError: ErrPaymentRequestNotPending,
+			expectAccept:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resolved string
+			mockStorage := &storage.IStorageMock{
+				AppendAuditFunc: nopAppendAudit,
+				GetPaymentRequestFunc: func(ctx context.Context, id int) (*storage.PaymentRequest, error) {
+					if id != tt.stored.ID {
+						return nil, storage.ErrPaymentRequestNotFound
+					}
+					pr := tt.stored
+					return &pr, nil
+				},
+				AcceptPaymentRequestFunc: func(ctx context.Context, id int, at time.Time, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+					return tt.acceptErr
+				},
+				ResolvePaymentRequestFunc: func(ctx context.Context, id int, status string, at time.Time) error {
+					resolved = status
+					return nil
+				},
+			}
+			s := NewService(mockStorage)
+			s.now = func() time.Time { return now }
+
+			pr, err := s.AcceptPaymentRequest(context.Background(), tt.username, 7)
+
+			assert.Equal(t, tt.expectAccept, len(mockStorage.AcceptPaymentRequestCalls()) == 1)
+			assert.Equal(t, tt.expectResolved, resolved)
+			if tt.expectedError != nil {
+				assert.True(t, errors.Is(err, tt.expectedError), "got %v", err)
+				assert.Nil(t, pr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, storage.PaymentRequestAccepted, pr.Status)
+		})
+	}
+}
+
+func TestCancelPaymentRequest_OnlyRequester(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	mockStorage := &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		GetPaymentRequestFunc: func(ctx context.Context, id int) (*storage.PaymentRequest, error) {
+			return &storage.PaymentRequest{
+				ID: id, Requester: "requester", Payer: "payer", Amount: 40,
+				Status: storage.PaymentRequestPending, ExpiresAt: now.Add(time.Hour),
+			}, nil
+		},
+		ResolvePaymentRequestFunc: func(ctx context.Context, id int, status string, at time.Time) error {
+			return nil
+		},
+	}
+	s := NewService(mockStorage)
+	s.now = func() time.Time { return now }
+
+	_, err := s.CancelPaymentRequest(context.Background(), "payer", 7)
+	assert.ErrorIs(t, err, ErrPaymentRequestNotFound)
+	assert.Empty(t, mockStorage.ResolvePaymentRequestCalls())
+
+	pr, err := s.CancelPaymentRequest(context.Background(), "requester", 7)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.PaymentRequestCancelled, pr.Status)
+	assert.Equal(t, storage.PaymentRequestCancelled, mockStorage.ResolvePaymentRequestCalls()[0].Status)
+}
@@ -0,0 +1,312 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"avito-shop/internal/metrics"
+	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// expiredPendingTransfersBatch — сколько истёкших переводов закрывается за один проход.
+const expiredPendingTransfersBatch = 100
+
+// ApprovalRequiredError — перевод не выполнен, а удержан до одобрения. Send возвращает её
+// вместо nil: монеты уже списаны с отправителя, но получатель их ещё не получил.
+type ApprovalRequiredError struct {
+	Transfer *storage.PendingTransfer
+}
+
+func (e *ApprovalRequiredError) Error() string {
+	return fmt.Sprintf("перевод %d монет ждёт одобрения до %s", e.Transfer.Amount, e.Transfer.ExpiresAt.Format(time.RFC3339))
+}
+
+func (e *ApprovalRequiredError) Is(target error) bool {
+	return target == ErrApprovalRequired
+}
+
+// requiresApproval сообщает, нужно ли одобрение для перевода amount.
+func (s *Service) requiresApproval(amount int) bool {
+	return s.Approvals.Enabled() && amount > s.Approvals.Threshold
+}
+
+// checkUnheldApproval отклоняет перевод amount сверх порога одобрения там, где его нельзя удержать,
+// как Send: оплата запроса закрыла бы запрос до решения, а отложенный перевод выполняется без отправителя.
+func (s *Service) checkUnheldApproval(amount int) error {
+	if s.requiresApproval(amount) {
+		return &LimitExceededError{Limit: storage.LimitApproval, Max: s.Approvals.Threshold}
+	}
+	return nil
+}
+
+// holdTransfer удерживает перевод до одобрения вместо его выполнения; actor — кто его отправил.
+func (s *Service) holdTransfer(ctx context.Context, actor, fromUsername string, fromID, toID int, scr *storage.SendCoinRequest,
+	limits storage.TransferLimits, fee storage.TransferFee) error {
+	now := s.now()
+	pt := &storage.PendingTransfer{
+		SenderID:     fromID,
+		Sender:       fromUsername,
+		RecipientID:  toID,
+		Recipient:    scr.ToUser,
+		Amount:       scr.Amount,
+		Fee:          fee.Amount,
+		FeeAccountID: fee.AccountID,
+		Memo:         scr.Memo,
+		Category:     scr.Category,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(s.Approvals.TTL),
+	}
+
+	audit := s.txAudit(ctx, actor, AuditTransferHold, "", fromID, toID)
+	audit.TargetKind = "pending_transfer"
+	err := s.Storage.CreatePendingTransfer(ctx, pt, limits, audit)
+	if le, ok := limitError(err); ok {
+		return le
+	}
+	switch {
+	case errors.Is(err, storage.ErrInsufficientFunds):
+		metrics.InsufficientFundsTotal.WithLabelValues(metrics.OperationSend).Inc()
+		return ErrInsufficientFunds
+	case err != nil:
+		return ErrInternalServer
+	}
+	metrics.PendingTransfersTotal.WithLabelValues(metrics.PendingTransferHeld).Inc()
+	s.publish(ctx, EventTransferPending, pt, pt.Sender, pt.Recipient)
+
+	return &ApprovalRequiredError{Transfer: pt}
+}
+
+// ListPendingTransfers возвращает ожидающие одобрения переводы: одобряющим — все,
+// остальным — только их собственные.
+func (s *Service) ListPendingTransfers(ctx context.Context, username string) (_ []storage.PendingTransfer, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ListPendingTransfers")
+	defer func() { tracing.End(span, err) }()
+
+	id, err := s.userID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	approver, err := s.isApprover(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	senderID := id
+	if approver {
+		senderID = 0
+	}
+	pts, err := s.Storage.ListPendingTransfers(ctx, senderID, s.now())
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+	if pts == nil {
+		pts = []storage.PendingTransfer{}
+	}
+
+	return pts, nil
+}
+
+// ApprovePendingTransfer одобряет удержанный перевод и зачисляет монеты получателю.
+// Одобрять свои переводы нельзя; суточные лимиты проверяются заново на момент одобрения.
+// Просроченный перевод закрывается как истёкший и возвращается вместе с ErrPendingTransferExpired.
+func (s *Service) ApprovePendingTransfer(ctx context.Context, approver string, id int) (_ *storage.PendingTransfer, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ApprovePendingTransfer", trace.WithAttributes(attribute.Int("shop.pending_transfer.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	approverID, pt, err := s.pendingTransfer(ctx, approver, id)
+	if err != nil {
+		return pt, err
+	}
+	if pt.SenderID == approverID {
+		return nil, ErrForbidden
+	}
+
+	limits, err := s.transferLimits(ctx, pt.SenderID, pt.RecipientID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	pt, err = s.resolvePendingTransfer(ctx, approver, id, storage.PendingTransferApproved, approverID, limits)
+	if err != nil {
+		return nil, err
+	}
+	metrics.CoinsTransferredTotal.Add(float64(pt.Amount))
+	metrics.FeesCollectedTotal.Add(float64(pt.Fee))
+	s.publishTransfer(ctx, pt.SenderID, pt.Sender, pt.RecipientID, pt.Recipient, pt.Amount, pt.Fee)
+
+	return pt, nil
+}
+
+// RejectPendingTransfer отклоняет удержанный перевод и возвращает удержание отправителю.
+// Просроченный перевод, как и в ApprovePendingTransfer, возвращается вместе с ErrPendingTransferExpired.
+func (s *Service) RejectPendingTransfer(ctx context.Context, approver string, id int) (_ *storage.PendingTransfer, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.RejectPendingTransfer", trace.WithAttributes(attribute.Int("shop.pending_transfer.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	approverID, expired, err := s.pendingTransfer(ctx, approver, id)
+	if err != nil {
+		return expired, err
+	}
+
+	return s.resolvePendingTransfer(ctx, approver, id, storage.PendingTransferRejected, approverID, storage.TransferLimits{})
+}
+
+// ExpirePendingTransfers закрывает переводы, которые не одобрили за Approvals.TTL, и возвращает
+// удержание отправителям. Переводы, закрытые параллельно, пропускаются; ошибки отдельных
+// переводов не прерывают проход.
+func (s *Service) ExpirePendingTransfers(ctx context.Context) (_ []storage.PendingTransfer, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ExpirePendingTransfers")
+	defer func() { tracing.End(span, err) }()
+
+	due, err := s.Storage.ListExpiredPendingTransfers(ctx, s.now(), expiredPendingTransfersBatch)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	var (
+		expired []storage.PendingTransfer
+		errs    []error
+	)
+	for _, pt := range due {
+		res, expireErr := s.resolvePendingTransfer(ctx, AuditSystem, pt.ID, storage.PendingTransferExpired, 0, storage.TransferLimits{})
+		switch {
+		case expireErr == nil:
+			expired = append(expired, *res)
+		case errors.Is(expireErr, ErrPendingTransferNotPending):
+		default:
+			errs = append(errs, expireErr)
+		}
+	}
+
+	return expired, errors.Join(errs...)
+}
+
+// pendingTransfer проверяет, что approver вправе одобрять переводы, и загружает
+// ожидающий перевод id. Истёкший перевод сразу закрывается, а вызывающий получает
+// закрытый перевод вместе с ErrPendingTransferExpired, чтобы сбросить кэш отправителя.
+func (s *Service) pendingTransfer(ctx context.Context, approver string, id int) (int, *storage.PendingTransfer, error) {
+	approverID, err := s.userID(ctx, approver)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return 0, nil, ErrForbidden
+		}
+		return 0, nil, err
+	}
+	ok, err := s.isApprover(ctx, approverID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !ok {
+		return 0, nil, ErrForbidden
+	}
+
+	pt, err := s.Storage.GetPendingTransfer(ctx, id)
+	switch {
+	case errors.Is(err, storage.ErrPendingTransferNotFound):
+		return 0, nil, ErrPendingTransferNotFound
+	case err != nil:
+		return 0, nil, ErrInternalServer
+	}
+	if pt.Status != storage.PendingTransferPending {
+		return 0, nil, ErrPendingTransferNotPending
+	}
+	if !pt.ExpiresAt.After(s.now()) {
+		if pt, err = s.resolvePendingTransfer(ctx, AuditSystem, id, storage.PendingTransferExpired, 0, storage.TransferLimits{}); err != nil {
+			return 0, nil, err
+		}
+		return 0, pt, ErrPendingTransferExpired
+	}
+
+	return approverID, pt, nil
+}
+
+// resolvePendingTransfer закрывает перевод в хранилище, считает исход, публикует событие
+// и записывает в журнал аудита действие actor.
+func (s *Service) resolvePendingTransfer(ctx context.Context, actor string, id int, status string, resolverID int,
+	limits storage.TransferLimits) (*storage.PendingTransfer, error) {
+	action := map[string]string{
+		storage.PendingTransferApproved: AuditTransferApprove,
+		storage.PendingTransferRejected: AuditTransferReject,
+		storage.PendingTransferExpired:  AuditTransferExpire,
+	}[status]
+	audit := s.txAudit(ctx, actor, action, auditTarget("pending_transfer", id))
+	pt, err := s.Storage.ResolvePendingTransfer(ctx, id, status, resolverID, s.now(), limits, audit)
+	if le, ok := limitError(err); ok {
+		return nil, le
+	}
+	switch {
+	case errors.Is(err, storage.ErrPendingTransferNotFound):
+		return nil, ErrPendingTransferNotFound
+	case errors.Is(err, storage.ErrPendingTransferNotPending):
+		return nil, ErrPendingTransferNotPending
+	case err != nil:
+		return nil, ErrInternalServer
+	}
+
+	event := map[string]string{
+		storage.PendingTransferApproved: EventTransferApproved,
+		storage.PendingTransferRejected: EventTransferRejected,
+		storage.PendingTransferExpired:  EventTransferExpired,
+	}[status]
+	metrics.PendingTransfersTotal.WithLabelValues(status).Inc()
+	s.publish(ctx, event, pt, pt.Sender, pt.Recipient)
+
+	return pt, nil
+}
+
+// isApprover сообщает, может ли пользователь одобрять переводы.
+func (s *Service) isApprover(ctx context.Context, userID int) (bool, error) {
+	if !s.Approvals.Enabled() {
+		return false, nil
+	}
+	role, err := s.Storage.GetUserRole(ctx, userID)
+	if err != nil {
+		return false, ErrInternalServer
+	}
+	return slices.Contains(s.Approvals.ApproverRoles, role), nil
+}
+
+// PendingTransferExpirer периодически закрывает переводы, которые не одобрили вовремя.
+// Его безопасно запускать на всех репликах: каждый перевод закрывается ровно один раз.
+type PendingTransferExpirer struct {
+	service  IService
+	interval time.Duration
+	log      *slog.Logger
+}
+
+func NewPendingTransferExpirer(service IService, interval time.Duration, log *slog.Logger) *PendingTransferExpirer {
+	return &PendingTransferExpirer{service: service, interval: interval, log: log}
+}
+
+// Run выполняет проход сразу и затем каждые interval, пока не отменён ctx.
+func (e *PendingTransferExpirer) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		e.tick(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *PendingTransferExpirer) tick(ctx context.Context) {
+	expired, err := e.service.ExpirePendingTransfers(ctx)
+	if err != nil && ctx.Err() == nil {
+		e.log.ErrorContext(ctx, "Failed to expire pending transfers", slog.String("error", err.Error()))
+	}
+	if len(expired) > 0 {
+		e.log.InfoContext(ctx, "Pending transfers expired", slog.Int("count", len(expired)))
+	}
+}
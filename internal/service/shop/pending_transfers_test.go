@@ -0,0 +1,300 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"avito-shop/internal/config"
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink запоминает опубликованные события.
+type recordingSink struct {
+	events []Event
+}
+
+func (r *recordingSink) Publish(ctx context.Context, e Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recordingSink) types() []string {
+	var res []string
+	for _, e := range r.events {
+		res = append(res, e.Type)
+	}
+	return res
+}
+
+var testApprovals = config.TransferApprovals{Threshold: 500, TTL: 72 * time.Hour, ApproverRoles: []string{storage.AdminRole}}
+
+func TestSend_HoldsLargeTransfers(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		approvals config.TransferApprovals
+		amount    int
+		wantHeld  bool
+	}{
+		{name: "Approvals disabled", amount: 900},
+		{name: "At the threshold", approvals: testApprovals, amount: 500},
+		{name: "Above the threshold", approvals: testApprovals, amount: 501, wantHeld: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := &storage.IStorageMock{
+				AppendAuditFunc: nopAppendAudit,
+				GetInfoFunc: func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+					if username == "alice" {
+						res.Coins = 1000
+						return 1, nil
+					}
+					return 2, nil
+				},
+				SendCoinsFunc: func(ctx context.Context, username string, fromUserID int, toUserID int, scr *storage.SendCoinRequest, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+					return nil
+				},
+				CreatePendingTransferFunc: func(ctx context.Context, pt *storage.PendingTransfer, limits storage.TransferLimits, audit *storage.TxAudit) error {
+					pt.ID, pt.Status = 4, storage.PendingTransferPending
+					return nil
+				},
+			}
+			sink := &recordingSink{}
+			s := NewService(mockStorage)
+			s.now = func() time.Time { return now }
+			s.Approvals, s.Events = tt.approvals, sink
+
+			err := s.Send(context.Background(), "alice", &storage.SendCoinRequest{ToUser: "bob", Amount: tt.amount, Memo: "rent"})
+
+			if !tt.wantHeld {
+				require.NoError(t, err)
+				assert.Len(t, mockStorage.SendCoinsCalls(), 1)
+				assert.Empty(t, mockStorage.CreatePendingTransferCalls())
+				assert.Equal(t, []string{EventTransferCompleted}, sink.types())
+				assert.Equal(t, &TransferEvent{FromID: 1, From: "alice", ToID: 2, To: "bob", Amount: tt.amount}, sink.events[0].Data)
+				return
+			}
+			var ae *ApprovalRequiredError
+			require.ErrorAs(t, err, &ae)
+			assert.ErrorIs(t, err, ErrApprovalRequired)
+			assert.Empty(t, mockStorage.SendCoinsCalls())
+			assert.Equal(t, &storage.PendingTransfer{
+				ID: 4, SenderID: 1, Sender: "alice", RecipientID: 2, Recipient: "bob", Amount: tt.amount, Memo: "rent",
+				Status: storage.PendingTransferPending, CreatedAt: now, ExpiresAt: now.Add(72 * time.Hour),
+			}, ae.Transfer)
+			assert.Equal(t, []string{EventTransferPending}, sink.types())
+			assert.Equal(t, []string{"alice", "bob"}, sink.events[0].Users)
+		})
+	}
+}
+
+// Оплату запроса и отложенный перевод нельзя удержать, как Send, поэтому сверх порога они отклоняются.
+func TestApprovalThreshold_UnheldTransfers(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	runAt := now.Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		amount int
+		call   func(s *Service) error
+	}{
+		{name: "Request payment", amount: 501, call: func(s *Service) error {
+			_, err := s.RequestPayment(context.Background(), "alice", &PaymentRequestInput{FromUser: "bob", Amount: 501})
+			return err
+		}},
+		{name: "Accept payment request", amount: 501, call: func(s *Service) error {
+			_, err := s.AcceptPaymentRequest(context.Background(), "bob", 7)
+			return err
+		}},
+		{name: "Schedule transfer", amount: 501, call: func(s *Service) error {
+			_, err := s.ScheduleTransfer(context.Background(), "alice", &ScheduledTransferInput{
+				SendCoinRequest: storage.SendCoinRequest{ToUser: "bob", Amount: 501}, RunAt: &runAt})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := &storage.IStorageMock{
+				AppendAuditFunc: nopAppendAudit,
+				GetInfoFunc: func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+					return map[string]int{"alice": 1, "bob": 2}[username], nil
+				},
+				GetPaymentRequestFunc: func(ctx context.Context, id int) (*storage.PaymentRequest, error) {
+					// Запрос создан до того, как порог уменьшили.
+					return &storage.PaymentRequest{ID: id, RequesterID: 1, Requester: "alice", PayerID: 2, Payer: "bob",
+						Amount: tt.amount, Status: storage.PaymentRequestPending, ExpiresAt: now.Add(time.Hour)}, nil
+				},
+			}
+			s := NewService(mockStorage)
+			s.now = func() time.Time { return now }
+			s.Approvals = testApprovals
+
+			err := tt.call(s)
+
+			var le *LimitExceededError
+			require.ErrorAs(t, err, &le)
+			assert.Equal(t, storage.LimitApproval, le.Limit)
+			assert.Equal(t, 500, le.Max)
+			assert.Empty(t, mockStorage.CreatePaymentRequestCalls())
+			assert.Empty(t, mockStorage.AcceptPaymentRequestCalls())
+			assert.Empty(t, mockStorage.CreateScheduledTransferCalls())
+		})
+	}
+}
+
+func TestResolvePendingTransfer(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	pending := storage.PendingTransfer{
+		ID: 4, SenderID: 1, Sender: "alice", RecipientID: 2, Recipient: "bob", Amount: 600,
+		Status: storage.PendingTransferPending, CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour),
+	}
+	expired := pending
+	expired.ExpiresAt = now
+	approved := pending
+	approved.Status = storage.PendingTransferApproved
+
+	tests := []struct {
+		name       string
+		approver   string
+		reject     bool
+		stored     storage.PendingTransfer
+		wantStatus string
+		wantErr    error
+		wantEvents []string
+	}{
+		{name: "Admin approves", approver: "admin", stored: pending,
+			wantStatus: storage.PendingTransferApproved, wantEvents: []string{EventTransferApproved, EventTransferCompleted}},
+		{name: "Admin rejects", approver: "admin", reject: true, stored: pending,
+			wantStatus: storage.PendingTransferRejected, wantEvents: []string{EventTransferRejected}},
+		{name: "Regular user cannot approve", approver: "carol", stored: pending, wantErr: ErrForbidden},
+		{name: "Sender cannot approve own transfer", approver: "alice", stored: pending, wantErr: ErrForbidden},
+		{name: "Already resolved", approver: "admin", stored: approved, wantErr: ErrPendingTransferNotPending},
+		{name: "Expired transfer is closed on access", approver: "admin", stored: expired,
+			wantStatus: storage.PendingTransferExpired, wantErr: ErrPendingTransferExpired, wantEvents: []string{EventTransferExpired}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := map[string]int{"alice": 1, "bob": 2, "carol": 3, "admin": 10}
+			var resolved string
+			mockStorage := &storage.IStorageMock{
+				AppendAuditFunc: nopAppendAudit,
+				GetInfoFunc: func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+					return ids[username], nil
+				},
+				GetUserRoleFunc: func(ctx context.Context, id int) (string, error) {
+					if id == 10 || id == 1 {
+						return storage.AdminRole, nil
+					}
+					return storage.DefaultRole, nil
+				},
+				GetPendingTransferFunc: func(ctx context.Context, id int) (*storage.PendingTransfer, error) {
+					pt := tt.stored
+					return &pt, nil
+				},
+				ResolvePendingTransferFunc: func(ctx context.Context, id int, status string, resolverID int, at time.Time, limits storage.TransferLimits, audit *storage.TxAudit) (*storage.PendingTransfer, error) {
+					resolved = status
+					pt := tt.stored
+					pt.Status = status
+					return &pt, nil
+				},
+			}
+			sink := &recordingSink{}
+			s := NewService(mockStorage)
+			s.now = func() time.Time { return now }
+			s.Approvals, s.Events = testApprovals, sink
+
+			var (
+				pt  *storage.PendingTransfer
+				err error
+			)
+			if tt.reject {
+				pt, err = s.RejectPendingTransfer(context.Background(), tt.approver, 4)
+			} else {
+				pt, err = s.ApprovePendingTransfer(context.Background(), tt.approver, 4)
+			}
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantStatus, resolved)
+			assert.Equal(t, tt.wantEvents, sink.types())
+			if tt.wantErr == nil || tt.wantErr == ErrPendingTransferExpired {
+				require.NotNil(t, pt, "the closed transfer is returned even when it expired")
+				assert.Equal(t, tt.wantStatus, pt.Status)
+			}
+		})
+	}
+}
+
+func TestListPendingTransfers_ApproversSeeAll(t *testing.T) {
+	mockStorage := &storage.IStorageMock{
+		GetInfoFunc: func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+			if username == "admin" {
+				return 10, nil
+			}
+			return 1, nil
+		},
+		GetUserRoleFunc: func(ctx context.Context, id int) (string, error) {
+			if id == 10 {
+				return storage.AdminRole, nil
+			}
+			return storage.DefaultRole, nil
+		},
+		ListPendingTransfersFunc: func(ctx context.Context, senderID int, now time.Time) ([]storage.PendingTransfer, error) {
+			return nil, nil
+		},
+	}
+	s := NewService(mockStorage)
+	s.Approvals = testApprovals
+
+	own, err := s.ListPendingTransfers(context.Background(), "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []storage.PendingTransfer{}, own)
+	_, err = s.ListPendingTransfers(context.Background(), "admin")
+	require.NoError(t, err)
+
+	calls := mockStorage.ListPendingTransfersCalls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, 1, calls[0].SenderID, "a regular user sees only own transfers")
+	assert.Equal(t, 0, calls[1].SenderID, "an approver sees all transfers")
+}
+
+func TestExpirePendingTransfers(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	boom := errors.New("boom")
+	mockStorage := &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		ListExpiredPendingTransfersFunc: func(ctx context.Context, at time.Time, limit int) ([]storage.PendingTransfer, error) {
+			assert.Equal(t, now, at)
+			return []storage.PendingTransfer{{ID: 1}, {ID: 2}, {ID: 3}}, nil
+		},
+		ResolvePendingTransferFunc: func(ctx context.Context, id int, status string, resolverID int, at time.Time, limits storage.TransferLimits, audit *storage.TxAudit) (*storage.PendingTransfer, error) {
+			assert.Equal(t, storage.PendingTransferExpired, status)
+			assert.Zero(t, resolverID)
+			switch id {
+			case 2:
+				return nil, storage.ErrPendingTransferNotPending
+			case 3:
+				return nil, boom
+			}
+			return &storage.PendingTransfer{ID: id, Sender: "alice", Status: status}, nil
+		},
+	}
+	sink := &recordingSink{}
+	s := NewService(mockStorage)
+	s.now = func() time.Time { return now }
+	s.Events = sink
+
+	expired, err := s.ExpirePendingTransfers(context.Background())
+
+	assert.ErrorIs(t, err, ErrInternalServer, "the failure of one transfer is reported")
+	require.Len(t, expired, 1, "a transfer closed concurrently is skipped")
+	assert.Equal(t, 1, expired[0].ID)
+	assert.Equal(t, []string{EventTransferExpired}, sink.types())
+}
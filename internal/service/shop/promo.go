@@ -0,0 +1,221 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PromoCodeInput — параметры нового промокода, см. storage.PromoCode.
+type PromoCodeInput struct {
+	Code           string
+	Kind           string
+	Value          int
+	Items          []string
+	MaxUses        int
+	MaxUsesPerUser int
+	StartsAt       *time.Time
+	EndsAt         *time.Time
+}
+
+// NormalizePromoCode приводит промокод к виду, в котором он хранится: промокоды не зависят от регистра.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (s *Service) CreatePromoCode(ctx context.Context, admin string, in *PromoCodeInput) (_ *storage.PromoCode, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.CreatePromoCode")
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.requireAdmin(ctx, admin); err != nil {
+		return nil, err
+	}
+	in.Code = NormalizePromoCode(in.Code)
+	if err = ValidatePromoCodeInput(in); err != nil {
+		return nil, err
+	}
+
+	pc := &storage.PromoCode{
+		Code:           in.Code,
+		Kind:           in.Kind,
+		Value:          in.Value,
+		Items:          in.Items,
+		MaxUses:        in.MaxUses,
+		MaxUsesPerUser: in.MaxUsesPerUser,
+		StartsAt:       utcTime(in.StartsAt),
+		EndsAt:         utcTime(in.EndsAt),
+		CreatedAt:      s.now(),
+	}
+	if err = s.Storage.CreatePromoCode(ctx, pc); err != nil {
+		if errors.Is(err, storage.ErrPromoCodeExists) {
+			return nil, ErrPromoCodeExists
+		}
+		return nil, ErrInternalServer
+	}
+	s.audit(ctx, admin, AuditPromoCreate, auditTarget("promo", pc.Code), nil, pc)
+	return pc, nil
+}
+
+func (s *Service) ListPromoCodes(ctx context.Context, admin string) (_ []storage.PromoCode, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ListPromoCodes")
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.requireAdmin(ctx, admin); err != nil {
+		return nil, err
+	}
+	codes, err := s.Storage.ListPromoCodes(ctx)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+	return codes, nil
+}
+
+// DisablePromoCode отключает промокод; покупки, уже сделанные по нему, не меняются.
+func (s *Service) DisablePromoCode(ctx context.Context, admin, code string) (_ *storage.PromoCode, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.DisablePromoCode")
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.requireAdmin(ctx, admin); err != nil {
+		return nil, err
+	}
+	code = NormalizePromoCode(code)
+	before, err := s.promoCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.Storage.DisablePromoCode(ctx, code, s.now()); err != nil {
+		return nil, ErrInternalServer
+	}
+	pc, err := s.promoCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, admin, AuditPromoDisable, auditTarget("promo", code), before, pc)
+	return pc, nil
+}
+
+// QuotePurchase считает цену покупки item по каталогу с промокодом promoCode (пустой — без скидки), ничего не покупая.
+// Ошибки промокода те же, что вернула бы Purchase.
+func (s *Service) QuotePurchase(ctx context.Context, username, item, promoCode string) (_ *storage.PriceQuote, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.QuotePurchase", trace.WithAttributes(attribute.String("shop.item", item)))
+	defer func() { tracing.End(span, err) }()
+
+	catalogItem, err := s.catalogItem(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.userID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, _, err := s.quote(ctx, id, item, catalogItem.Price, promoCode)
+	return quote, err
+}
+
+// quote считает цену покупки item пользователем userID и возвращает применённый промокод
+// (nil, если он не указан).
+func (s *Service) quote(ctx context.Context, userID int, item string, price int, promoCode string) (*storage.PriceQuote, *storage.PromoCode, error) {
+	quote := &storage.PriceQuote{Item: item, Price: price, Total: price}
+	if strings.TrimSpace(promoCode) == "" {
+		return quote, nil, nil
+	}
+
+	pc, err := s.applicablePromoCode(ctx, userID, item, NormalizePromoCode(promoCode))
+	if err != nil {
+		return nil, nil, err
+	}
+	quote.PromoCode = pc.Code
+	quote.Discount = pc.Discount(price)
+	quote.Total = price - quote.Discount
+	return quote, pc, nil
+}
+
+// applicablePromoCode возвращает промокод, если userID может применить его к покупке item сейчас.
+func (s *Service) applicablePromoCode(ctx context.Context, userID int, item, code string) (*storage.PromoCode, error) {
+	pc, err := s.promoCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	switch {
+	case pc.DisabledAt != nil,
+		pc.StartsAt != nil && now.Before(*pc.StartsAt),
+		pc.EndsAt != nil && !now.Before(*pc.EndsAt):
+		return nil, ErrPromoCodeInactive
+	case len(pc.Items) > 0 && !slices.Contains(pc.Items, item):
+		return nil, ErrPromoCodeNotApplicable
+	case pc.MaxUses > 0 && pc.Uses >= pc.MaxUses:
+		return nil, ErrPromoCodeUsedUp
+	}
+
+	if pc.MaxUsesPerUser > 0 {
+		used, err := s.Storage.CountPromoRedemptions(ctx, pc.ID, userID)
+		if err != nil {
+			return nil, ErrInternalServer
+		}
+		if used >= pc.MaxUsesPerUser {
+			return nil, ErrPromoCodeAlreadyUsed
+		}
+	}
+	return pc, nil
+}
+
+func (s *Service) promoCode(ctx context.Context, code string) (*storage.PromoCode, error) {
+	pc, err := s.Storage.GetPromoCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, storage.ErrPromoCodeNotFound) {
+			return nil, ErrPromoCodeNotFound
+		}
+		return nil, ErrInternalServer
+	}
+	return pc, nil
+}
+
+// promoRedemptionError переводит отказ хранилища, случившийся между проверкой промокода
+// и покупкой (код исчерпали или отключили параллельно), в ошибку сервиса.
+func promoRedemptionError(err error) error {
+	switch {
+	case errors.Is(err, storage.ErrPromoCodeUserLimit):
+		return ErrPromoCodeAlreadyUsed
+	case errors.Is(err, storage.ErrPromoCodeUnavailable):
+		return ErrPromoCodeUsedUp
+	}
+	return ErrInternalServer
+}
+
+// requireAdmin возвращает ErrForbidden, если у пользователя нет роли storage.AdminRole.
+func (s *Service) requireAdmin(ctx context.Context, username string) error {
+	id, err := s.userID(ctx, username)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return ErrForbidden
+		}
+		return err
+	}
+	role, err := s.Storage.GetUserRole(ctx, id)
+	if err != nil {
+		return ErrInternalServer
+	}
+	if role != storage.AdminRole {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := t.UTC().Truncate(time.Second)
+	return &v
+}
@@ -0,0 +1,192 @@
+package shop
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotePurchase_TableDriven(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	codes := map[string]*storage.PromoCode{
+		"HOODY20":  {ID: 1, Code: "HOODY20", Kind: storage.PromoPercent, Value: 20, Items: []string{"hoody"}},
+		"MINUS500": {ID: 2, Code: "MINUS500", Kind: storage.PromoFixed, Value: 500},
+		"OFF":      {ID: 3, Code: "OFF", Kind: storage.PromoFixed, Value: 5, DisabledAt: &past},
+		"LATER":    {ID: 4, Code: "LATER", Kind: storage.PromoFixed, Value: 5, StartsAt: &future},
+		"OVER":     {ID: 5, Code: "OVER", Kind: storage.PromoFixed, Value: 5, EndsAt: &now},
+		"FIRST3":   {ID: 6, Code: "FIRST3", Kind: storage.PromoFixed, Value: 5, MaxUses: 3, Uses: 3},
+		"ONCE":     {ID: 7, Code: "ONCE", Kind: storage.PromoFixed, Value: 5, MaxUsesPerUser: 1},
+	}
+
+	tests := []struct {
+		name          string
+		item          string
+		promoCode     string
+		expected      *storage.PriceQuote
+		expectedError error
+	}{
+		{
+			name:     "Without promo code",
+			item:     "hoody",
+			expected: &storage.PriceQuote{Item: "hoody", Price: 300, Total: 300},
+		},
+		{
+			name:      "Percent discount, code is case-insensitive",
+			item:      "hoody",
+			promoCode: " hoody20 ",
+			expected:  &storage.PriceQuote{Item: "hoody", Price: 300, Discount: 60, Total: 240, PromoCode: "HOODY20"},
+		},
+		{
+			name:      "Fixed discount capped by price",
+			item:      "cup",
+			promoCode: "MINUS500",
+			expected:  &storage.PriceQuote{Item: "cup", Price: 20, Discount: 20, Total: 0, PromoCode: "MINUS500"},
+		},
+		{"Unknown item", "dragon", "", nil, ErrItemNotFound},
+		{"Unknown code", "cup", "NOPE", nil, ErrPromoCodeNotFound},
+		{"Disabled code", "cup", "OFF", nil, ErrPromoCodeInactive},
+		{"Code not started", "cup", "LATER", nil, ErrPromoCodeInactive},
+		{"Code expired", "cup", "OVER", nil, ErrPromoCodeInactive},
+		{"Item not covered", "cup", "HOODY20", nil, ErrPromoCodeNotApplicable},
+		{"Total uses exhausted", "cup", "FIRST3", nil, ErrPromoCodeUsedUp},
+		{"Already used by user", "cup", "ONCE", nil, ErrPromoCodeAlreadyUsed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := &storage.IStorageMock{
+				GetInfoFunc: func(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+					return 1, nil
+				},
+				ListItemPricesFunc: func(ctx context.Context, now time.Time) ([]storage.ItemPrice, error) {
+					return nil, nil
+				},
+				GetPromoCodeFunc: func(ctx context.Context, code string) (*storage.PromoCode, error) {
+					if pc, ok := codes[code]; ok {
+						return pc, nil
+					}
+					return nil, storage.ErrPromoCodeNotFound
+				},
+				CountPromoRedemptionsFunc: func(ctx context.Context, promoCodeID, userID int) (int, error) {
+					return 1, nil
+				},
+			}
+			service := NewService(mockStorage)
+			service.now = func() time.Time { return now }
+
+			quote, err := service.QuotePurchase(context.Background(), "alice", tt.item, tt.promoCode)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expected, quote)
+		})
+	}
+}
+
+func TestPurchase_WithPromoCode(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	pc := &storage.PromoCode{ID: 4, Code: "HOODY20", Kind: storage.PromoPercent, Value: 20, MaxUses: 10}
+
+	tests := []struct {
+		name          string
+		coins         int
+		buyErr        error
+		expectedError error
+		wantBuy       bool
+	}{
+		{name: "Balance covers the discounted price", coins: 240, wantBuy: true},
+		{name: "Balance below the discounted price", coins: 239, expectedError: ErrInsufficientFunds},
+		{name: "Code used up concurrently", coins: 240, buyErr: storage.ErrPromoCodeUnavailable, expectedError: ErrPromoCodeUsedUp, wantBuy: true},
+		{name: "User limit hit concurrently", coins: 240, buyErr: storage.ErrPromoCodeUserLimit, expectedError: ErrPromoCodeAlreadyUsed, wantBuy: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *storage.PromoRedemption
+			mockStorage := &storage.IStorageMock{
+				AppendAuditFunc: nopAppendAudit,
+				GetInfoFunc: func(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+					ir.Coins = tt.coins
+					return 1, nil
+				},
+				ListItemPricesFunc: func(ctx context.Context, now time.Time) ([]storage.ItemPrice, error) {
+					return nil, nil
+				},
+				GetPromoCodeFunc: func(ctx context.Context, code string) (*storage.PromoCode, error) {
+					return pc, nil
+				},
+				BuyItemWithPromoFunc: func(ctx context.Context, name, item string, r *storage.PromoRedemption, audit *storage.TxAudit) error {
+					got = r
+					return tt.buyErr
+				},
+			}
+			service := NewService(mockStorage)
+			service.now = func() time.Time { return now }
+
+			err := service.Purchase(context.Background(), "alice", "hoody", "hoody20")
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Empty(t, mockStorage.BuyItemCalls())
+			if !tt.wantBuy {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Equal(t, &storage.PromoRedemption{PromoCodeID: 4, Price: 300, Discount: 60, RedeemedAt: now}, got)
+		})
+	}
+}
+
+func TestPromoCodeAdmin_RequiresAdminRole(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	mockStorage := &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		GetInfoFunc: func(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+			switch username {
+			case "admin":
+				return 1, nil
+			case "alice":
+				return 2, nil
+			}
+			return 0, storage.ErrUserNotFound
+		},
+		GetUserRoleFunc: func(ctx context.Context, userID int) (string, error) {
+			if userID == 1 {
+				return storage.AdminRole, nil
+			}
+			return storage.DefaultRole, nil
+		},
+		CreatePromoCodeFunc: func(ctx context.Context, pc *storage.PromoCode) error {
+			pc.ID = 9
+			return nil
+		},
+	}
+	service := NewService(mockStorage)
+	service.now = func() time.Time { return now }
+
+	in := func() *PromoCodeInput {
+		return &PromoCodeInput{Code: "sale10", Kind: storage.PromoPercent, Value: 10}
+	}
+
+	for _, user := range []string{"alice", "ghost"} {
+		_, err := service.CreatePromoCode(context.Background(), user, in())
+		assert.Equal(t, ErrForbidden, err, user)
+		_, err = service.ListPromoCodes(context.Background(), user)
+		assert.Equal(t, ErrForbidden, err, user)
+		_, err = service.DisablePromoCode(context.Background(), user, "SALE10")
+		assert.Equal(t, ErrForbidden, err, user)
+	}
+	assert.Empty(t, mockStorage.CreatePromoCodeCalls())
+
+	pc, err := service.CreatePromoCode(context.Background(), "admin", in())
+	require.NoError(t, err)
+	assert.Equal(t, &storage.PromoCode{ID: 9, Code: "SALE10", Kind: storage.PromoPercent, Value: 10, CreatedAt: now}, pc)
+
+	_, err = service.CreatePromoCode(context.Background(), "admin", &PromoCodeInput{Code: "x", Kind: "bogus"})
+	var ve *ValidationError
+	require.ErrorAs(t, err, &ve)
+}
@@ -0,0 +1,94 @@
+package shop
+
+import (
+	"context"
+	"errors"
+
+	"avito-shop/internal/metrics"
+	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// userTransactionsLimit — сколько последних записей пользователя видит администратор.
+const userTransactionsLimit = 100
+
+// ReversalInput — отмена перевода TransactionID администратором, см. storage.TransferReversal.
+type ReversalInput struct {
+	TransactionID int
+	Policy        string
+	Reason        string
+}
+
+// ListUserTransactions возвращает последние записи пользователя с идентификаторами,
+// чтобы администратор мог найти перевод для отмены.
+func (s *Service) ListUserTransactions(ctx context.Context, admin, username string) (_ []storage.Transaction, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ListUserTransactions")
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.requireAdmin(ctx, admin); err != nil {
+		return nil, err
+	}
+	id, err := s.userID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := s.Storage.ListUserTransactions(ctx, id, userTransactionsLimit)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+	if txs == nil {
+		txs = []storage.Transaction{}
+	}
+	return txs, nil
+}
+
+// ReverseTransfer отменяет ошибочный перевод компенсирующей записью; исходная запись остаётся
+// в истории. Если получатель уже потратил монеты, политика storage.ReversalPartial возвращает
+// остаток, а storage.ReversalNegative — всю сумму, уводя баланс получателя в минус.
+// Комиссия перевода не возвращается.
+func (s *Service) ReverseTransfer(ctx context.Context, admin string, in *ReversalInput) (_ *storage.TransferReversal, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ReverseTransfer", trace.WithAttributes(attribute.Int("shop.transaction.id", in.TransactionID)))
+	defer func() { tracing.End(span, err) }()
+
+	if err = s.requireAdmin(ctx, admin); err != nil {
+		return nil, err
+	}
+	if in.Policy == "" {
+		in.Policy = storage.ReversalPartial
+	}
+	if err = ValidateReversalInput(in); err != nil {
+		return nil, err
+	}
+	adminID, err := s.userID(ctx, admin)
+	if err != nil {
+		return nil, err
+	}
+
+	rv := &storage.TransferReversal{
+		TransactionID: in.TransactionID,
+		Policy:        in.Policy,
+		Reason:        in.Reason,
+		ReversedByID:  adminID,
+		CreatedAt:     s.now(),
+	}
+	err = s.Storage.ReverseTransaction(ctx, rv, s.txAudit(ctx, admin, AuditTransferReverse, auditTarget("transaction", rv.TransactionID)))
+	switch {
+	case errors.Is(err, storage.ErrTransactionNotFound):
+		return nil, ErrTransactionNotFound
+	case errors.Is(err, storage.ErrTransactionNotReversible):
+		return nil, ErrTransactionNotReversible
+	case errors.Is(err, storage.ErrTransactionAlreadyReversed):
+		return nil, ErrTransactionAlreadyReversed
+	case errors.Is(err, storage.ErrInsufficientFunds):
+		return nil, ErrNothingToReverse
+	case err != nil:
+		return nil, ErrInternalServer
+	}
+	metrics.CoinsReversedTotal.WithLabelValues(rv.Policy).Add(float64(rv.Reversed))
+	s.publish(ctx, EventTransferReversed, rv, rv.FromUser, rv.ToUser)
+
+	return rv, nil
+}
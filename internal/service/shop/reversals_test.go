@@ -0,0 +1,119 @@
+package shop
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseTransfer(t *testing.T) {
+	now := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		admin      string
+		in         ReversalInput
+		storageErr error
+		wantPolicy string
+		wantErr    error
+	}{
+		{name: "Partial by default", admin: "admin", in: ReversalInput{TransactionID: 7, Reason: "wrong recipient"},
+			wantPolicy: storage.ReversalPartial},
+		{name: "Negative policy", admin: "admin", in: ReversalInput{TransactionID: 7, Policy: storage.ReversalNegative, Reason: "fraud"},
+			wantPolicy: storage.ReversalNegative},
+		{name: "Regular user", admin: "carol", in: ReversalInput{TransactionID: 7, Reason: "mine"}, wantErr: ErrForbidden},
+		{name: "Reason is required", admin: "admin", in: ReversalInput{TransactionID: 7, Reason: " "}, wantErr: ErrValidation},
+		{name: "Unknown policy", admin: "admin", in: ReversalInput{TransactionID: 7, Policy: "full", Reason: "x"}, wantErr: ErrValidation},
+		{name: "Not found", admin: "admin", in: ReversalInput{TransactionID: 7, Reason: "x"},
+			storageErr: storage.ErrTransactionNotFound, wantErr: ErrTransactionNotFound},
+		{name: "Already reversed", admin: "admin", in: ReversalInput{TransactionID: 7, Reason: "x"},
+			storageErr: storage.ErrTransactionAlreadyReversed, wantErr: ErrTransactionAlreadyReversed},
+		{name: "Grant is not reversible", admin: "admin", in: ReversalInput{TransactionID: 7, Reason: "x"},
+			storageErr: storage.ErrTransactionNotReversible, wantErr: ErrTransactionNotReversible},
+		{name: "Recipient spent everything", admin: "admin", in: ReversalInput{TransactionID: 7, Reason: "x"},
+			storageErr: storage.ErrInsufficientFunds, wantErr: ErrNothingToReverse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := &storage.IStorageMock{
+				AppendAuditFunc: nopAppendAudit,
+				GetInfoFunc: func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+					return map[string]int{"admin": 10, "carol": 3}[username], nil
+				},
+				GetUserRoleFunc: func(ctx context.Context, id int) (string, error) {
+					if id == 10 {
+						return storage.AdminRole, nil
+					}
+					return storage.DefaultRole, nil
+				},
+				ReverseTransactionFunc: func(ctx context.Context, rv *storage.TransferReversal, audit *storage.TxAudit) error {
+					if tt.storageErr != nil {
+						return tt.storageErr
+					}
+					rv.ID, rv.FromUser, rv.ToUser, rv.Amount, rv.Reversed = 1, "alice", "bob", 300, 300
+					return nil
+				},
+			}
+			sink := &recordingSink{}
+			s := NewService(mockStorage)
+			s.now = func() time.Time { return now }
+			s.Events = sink
+
+			in := tt.in
+			rv, err := s.ReverseTransfer(context.Background(), tt.admin, &in)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Empty(t, sink.events)
+				return
+			}
+			require.NoError(t, err)
+			calls := mockStorage.ReverseTransactionCalls()
+			require.Len(t, calls, 1)
+			assert.Equal(t, &storage.TransferReversal{
+				ID: 1, TransactionID: 7, FromUser: "alice", ToUser: "bob", Amount: 300, Reversed: 300,
+				Policy: tt.wantPolicy, Reason: tt.in.Reason, ReversedByID: 10, CreatedAt: now,
+			}, rv)
+			assert.Equal(t, []string{EventTransferReversed}, sink.types())
+			assert.Equal(t, []string{"alice", "bob"}, sink.events[0].Users)
+		})
+	}
+}
+
+func TestListUserTransactions(t *testing.T) {
+	mockStorage := &storage.IStorageMock{
+		GetInfoFunc: func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+			if username == "ghost" {
+				return 0, storage.ErrUserNotFound
+			}
+			return map[string]int{"admin": 10, "alice": 1}[username], nil
+		},
+		GetUserRoleFunc: func(ctx context.Context, id int) (string, error) {
+			if id == 10 {
+				return storage.AdminRole, nil
+			}
+			return storage.DefaultRole, nil
+		},
+		ListUserTransactionsFunc: func(ctx context.Context, userID, limit int) ([]storage.Transaction, error) {
+			return nil, nil
+		},
+	}
+	s := NewService(mockStorage)
+
+	txs, err := s.ListUserTransactions(context.Background(), "admin", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []storage.Transaction{}, txs)
+	require.Len(t, mockStorage.ListUserTransactionsCalls(), 1)
+	assert.Equal(t, 1, mockStorage.ListUserTransactionsCalls()[0].UserID)
+
+	_, err = s.ListUserTransactions(context.Background(), "alice", "alice")
+	assert.ErrorIs(t, err, ErrForbidden)
+	_, err = s.ListUserTransactions(context.Background(), "admin", "ghost")
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
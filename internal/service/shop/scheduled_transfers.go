@@ -0,0 +1,220 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"avito-shop/internal/metrics"
+	"avito-shop/internal/schedule"
+	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// dueTransfersBatch — сколько наступивших переводов обрабатывается за один проход планировщика.
+const dueTransfersBatch = 100
+
+// ScheduledTransferInput — перевод, который нужно выполнить в RunAt или по расписанию Schedule.
+// Если заданы оба поля, первый запуск происходит в RunAt, а следующие — по расписанию.
+type ScheduledTransferInput struct {
+	storage.SendCoinRequest
+	RunAt    *time.Time `json:"runAt,omitempty"`
+	Schedule string     `json:"schedule,omitempty"`
+}
+
+func (s *Service) ScheduleTransfer(ctx context.Context, fromUsername string, in *ScheduledTransferInput) (_ *storage.ScheduledTransfer, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ScheduleTransfer", trace.WithAttributes(
+		attribute.Int("shop.amount", in.Amount), attribute.String("shop.schedule", in.Schedule)))
+	defer func() { tracing.End(span, err) }()
+
+	now := s.now()
+	if err = ValidateScheduledTransferInput(fromUsername, in, now); err != nil {
+		return nil, err
+	}
+
+	senderID, err := s.userID(ctx, fromUsername)
+	if err != nil {
+		return nil, err
+	}
+	recipientID, err := s.userID(ctx, in.ToUser)
+	if err != nil {
+		return nil, err
+	}
+
+	// Лимит одного перевода проверяется при создании и при каждом запуске, суточные — только при запуске.
+	if _, err = s.transferLimits(ctx, senderID, recipientID, in.Amount); err != nil {
+		return nil, err
+	}
+	if err = s.checkUnheldApproval(in.Amount); err != nil {
+		return nil, err
+	}
+
+	var nextRunAt time.Time
+	if in.RunAt != nil {
+		nextRunAt = in.RunAt.UTC().Truncate(time.Second)
+	} else if nextRunAt, err = nextRun(in.Schedule, now); err != nil {
+		return nil, ErrInternalServer
+	}
+
+	st := &storage.ScheduledTransfer{
+		SenderID:    senderID,
+		Sender:      fromUsername,
+		RecipientID: recipientID,
+		Recipient:   in.ToUser,
+		Amount:      in.Amount,
+		Memo:        in.Memo,
+		Category:    in.Category,
+		Schedule:    in.Schedule,
+		NextRunAt:   nextRunAt,
+		CreatedAt:   now,
+	}
+	if err = s.Storage.CreateScheduledTransfer(ctx, st); err != nil {
+		return nil, ErrInternalServer
+	}
+	s.audit(ctx, fromUsername, AuditScheduleCreate, auditTarget("scheduled_transfer", st.ID), nil, st)
+
+	return st, nil
+}
+
+func (s *Service) ListScheduledTransfers(ctx context.Context, username string) (_ []storage.ScheduledTransfer, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.ListScheduledTransfers")
+	defer func() { tracing.End(span, err) }()
+
+	id, err := s.userID(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	sts, err := s.Storage.ListScheduledTransfers(ctx, id)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+	if sts == nil {
+		sts = []storage.ScheduledTransfer{}
+	}
+
+	return sts, nil
+}
+
+// CancelScheduledTransfer отменяет будущие запуски; отменить может только отправитель.
+func (s *Service) CancelScheduledTransfer(ctx context.Context, username string, id int) (_ *storage.ScheduledTransfer, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.CancelScheduledTransfer", trace.WithAttributes(attribute.Int("shop.scheduled_transfer.id", id)))
+	defer func() { tracing.End(span, err) }()
+
+	st, err := s.Storage.GetScheduledTransfer(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrScheduledTransferNotFound) {
+			return nil, ErrScheduledTransferNotFound
+		}
+		return nil, ErrInternalServer
+	}
+	if !strings.EqualFold(st.Sender, username) {
+		return nil, ErrScheduledTransferNotFound
+	}
+
+	err = s.Storage.CancelScheduledTransfer(ctx, id)
+	switch {
+	case errors.Is(err, storage.ErrScheduledTransferNotActive):
+		return nil, ErrScheduledTransferNotActive
+	case err != nil:
+		return nil, ErrInternalServer
+	}
+
+	before := auditStatus{st.Status}
+	st.Status = storage.ScheduledTransferCancelled
+	s.audit(ctx, username, AuditScheduleCancel, auditTarget("scheduled_transfer", st.ID), before, st)
+	return st, nil
+}
+
+// RunDueTransfers выполняет наступившие отложенные переводы и возвращает выполненные.
+// Пропущенные во время простоя запуски не наверстываются: следующий считается от текущего времени.
+// Запуск, захваченный другой репликой, пропускается; ошибки отдельных переводов не прерывают проход.
+func (s *Service) RunDueTransfers(ctx context.Context) (_ []storage.ScheduledTransfer, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.RunDueTransfers")
+	defer func() { tracing.End(span, err) }()
+
+	now := s.now()
+	due, err := s.Storage.ListDueScheduledTransfers(ctx, now, dueTransfersBatch)
+	if err != nil {
+		return nil, ErrInternalServer
+	}
+
+	var (
+		executed []storage.ScheduledTransfer
+		errs     []error
+	)
+	for i := range due {
+		st := &due[i]
+
+		var next *time.Time
+		if st.Schedule != "" {
+			// Расписание, которое больше не срабатывает, завершается как разовый перевод.
+			if t, nextErr := nextRun(st.Schedule, now); nextErr == nil {
+				next = &t
+			}
+		}
+
+		limits, limitsErr := s.scheduledTransferLimits(ctx, st)
+		if limitsErr != nil {
+			metrics.ScheduledTransferRunsTotal.WithLabelValues(metrics.ScheduledError).Inc()
+			errs = append(errs, limitsErr)
+			continue
+		}
+		// Комиссия считается по правилам на момент запуска, а не создания перевода.
+		fee, feeErr := s.transferFee(ctx, st.SenderID, st.Amount)
+		if feeErr != nil {
+			metrics.ScheduledTransferRunsTotal.WithLabelValues(metrics.ScheduledError).Inc()
+			errs = append(errs, feeErr)
+			continue
+		}
+
+		audit := s.txAudit(ctx, AuditSystem, AuditTransferScheduled, auditTarget("scheduled_transfer", st.ID), st.SenderID, st.RecipientID)
+		runErr := s.Storage.RunScheduledTransfer(ctx, st, next, now, limits, fee, audit)
+		switch {
+		case runErr == nil:
+			metrics.ScheduledTransferRunsTotal.WithLabelValues(metrics.ScheduledExecuted).Inc()
+			metrics.CoinsTransferredTotal.Add(float64(st.Amount))
+			metrics.FeesCollectedTotal.Add(float64(fee.Amount))
+			s.publishTransfer(ctx, st.SenderID, st.Sender, st.RecipientID, st.Recipient, st.Amount, fee.Amount)
+			executed = append(executed, *st)
+		case errors.Is(runErr, storage.ErrInsufficientFunds):
+			metrics.ScheduledTransferRunsTotal.WithLabelValues(metrics.ScheduledInsufficientFunds).Inc()
+		case errors.Is(runErr, storage.ErrLimitExceeded):
+			metrics.ScheduledTransferRunsTotal.WithLabelValues(metrics.ScheduledLimitExceeded).Inc()
+		case errors.Is(runErr, storage.ErrScheduledTransferClaimed):
+			metrics.ScheduledTransferRunsTotal.WithLabelValues(metrics.ScheduledClaimed).Inc()
+		default:
+			metrics.ScheduledTransferRunsTotal.WithLabelValues(metrics.ScheduledError).Inc()
+			errs = append(errs, runErr)
+		}
+	}
+
+	return executed, errors.Join(errs...)
+}
+
+// scheduledTransferLimits возвращает лимиты для запуска отложенного перевода. Лимит одного перевода
+// и порог одобрения могли уменьшиться после создания, поэтому хранилище сверяет с ними st.Amount
+// вместе с суточными: запуск сверх лимита пропускается и записывается в last_error, а не повторяется
+// на каждом проходе.
+func (s *Service) scheduledTransferLimits(ctx context.Context, st *storage.ScheduledTransfer) (storage.TransferLimits, error) {
+	limits, err := s.partyLimits(ctx, st.SenderID, st.RecipientID)
+	if err != nil {
+		return storage.TransferLimits{}, err
+	}
+	if s.Approvals.Enabled() {
+		limits.MaxUnapproved = s.Approvals.Threshold
+	}
+	return limits, nil
+}
+
+func nextRun(spec string, after time.Time) (time.Time, error) {
+	sched, err := schedule.Parse(spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return sched.Next(after)
+}
@@ -0,0 +1,225 @@
+package shop
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"avito-shop/internal/config"
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleTransfer_TableDriven(t *testing.T) {
+	// 2025-02-05 — среда.
+	now := time.Date(2025, 2, 5, 10, 30, 0, 0, time.UTC)
+	runAt := now.Add(2 * time.Hour)
+	past := now.Add(-time.Minute)
+
+	tests := []struct {
+		name          string
+		in            *ScheduledTransferInput
+		wantNextRunAt time.Time
+		expectedField string
+	}{
+		{
+			name:          "One-off at time",
+			in:            &ScheduledTransferInput{SendCoinRequest: storage.SendCoinRequest{ToUser: "bob", Amount: 10}, RunAt: &runAt},
+			wantNextRunAt: runAt,
+		},
+		{
+			name:          "Weekly bonus",
+			in:            &ScheduledTransferInput{SendCoinRequest: storage.SendCoinRequest{ToUser: "bob", Amount: 10}, Schedule: "0 9 * * 1"},
+			wantNextRunAt: time.Date(2025, 2, 10, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "Recurring with explicit first run",
+			in:            &ScheduledTransferInput{SendCoinRequest: storage.SendCoinRequest{ToUser: "bob", Amount: 10}, Schedule: "@weekly", RunAt: &runAt},
+			wantNextRunAt: runAt,
+		},
+		{
+			name:          "Neither time nor schedule",
+			in:            &ScheduledTransferInput{SendCoinRequest: storage.SendCoinRequest{ToUser: "bob", Amount: 10}},
+			expectedField: "runAt",
+		},
+		{
+			name:          "Time in the past",
+			in:            &ScheduledTransferInput{SendCoinRequest: storage.SendCoinRequest{ToUser: "bob", Amount: 10}, RunAt: &past},
+			expectedField: "runAt",
+		},
+		{
+			name:          "Invalid schedule",
+			in:            &ScheduledTransferInput{SendCoinRequest: storage.SendCoinRequest{ToUser: "bob", Amount: 10}, Schedule: "every monday"},
+			expectedField: "schedule",
+		},
+		{
+			name:          "Transfer to self",
+			in:            &ScheduledTransferInput{SendCoinRequest: storage.SendCoinRequest{ToUser: "alice", Amount: 10}, RunAt: &runAt},
+			expectedField: "toUser",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := &storage.IStorageMock{
+				AppendAuditFunc: nopAppendAudit,
+				GetInfoFunc: func(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+					return map[string]int{"alice": 1, "bob": 2}[username], nil
+				},
+				CreateScheduledTransferFunc: func(ctx context.Context, st *storage.ScheduledTransfer) error {
+					st.ID, st.Status = 3, storage.ScheduledTransferActive
+					return nil
+				},
+			}
+			s := NewService(mockStorage)
+			s.now = func() time.Time { return now }
+
+			st, err := s.ScheduleTransfer(context.Background(), "alice", tt.in)
+
+			if tt.expectedField != "" {
+				var ve *ValidationError
+				require.ErrorAs(t, err, &ve)
+				assert.Equal(t, tt.expectedField, ve.Fields[0].Field)
+				assert.Empty(t, mockStorage.CreateScheduledTransferCalls())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNextRunAt, st.NextRunAt)
+			assert.Equal(t, 1, st.SenderID)
+			assert.Equal(t, 2, st.RecipientID)
+			assert.Equal(t, tt.in.Schedule, st.Schedule)
+		})
+	}
+}
+
+func TestRunDueTransfers(t *testing.T) {
+	now := time.Date(2025, 2, 5, 10, 30, 0, 0, time.UTC)
+	due := []storage.ScheduledTransfer{
+		{ID: 1, Sender: "alice", Recipient: "bob", Amount: 10, Schedule: "@daily", NextRunAt: now.Add(-72 * time.Hour)},
+		{ID: 2, Sender: "alice", Recipient: "bob", Amount: 10, NextRunAt: now},
+		{ID: 3, Sender: "carol", Recipient: "bob", Amount: 5000, NextRunAt: now},
+		{ID: 4, Sender: "dave", Recipient: "bob", Amount: 10, NextRunAt: now},
+		{ID: 5, Sender: "erin", Recipient: "bob", Amount: 10, NextRunAt: now},
+		{ID: 6, Sender: "frank", Recipient: "bob", Amount: 10, NextRunAt: now},
+	}
+	dbErr := errors.New("connection reset")
+
+	nextRuns := map[int]*time.Time{}
+	mockStorage := &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		ListDueScheduledTransfersFunc: func(ctx context.Context, at time.Time, limit int) ([]storage.ScheduledTransfer, error) {
+			assert.Equal(t, now, at)
+			return append([]storage.ScheduledTransfer(nil), due...), nil
+		},
+		RunScheduledTransferFunc: func(ctx context.Context, st *storage.ScheduledTransfer, next *time.Time, at time.Time, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+			nextRuns[st.ID] = next
+			switch st.ID {
+			case 3:
+				return storage.ErrInsufficientFunds
+			case 4:
+				return storage.ErrScheduledTransferClaimed
+			case 5:
+				return dbErr
+			case 6:
+				return &storage.LimitExceededError{Limit: storage.LimitDailySent, Max: 5}
+			}
+			return nil
+		},
+	}
+	s := NewService(mockStorage)
+	s.now = func() time.Time { return now }
+
+	executed, err := s.RunDueTransfers(context.Background())
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.NotErrorIs(t, err, storage.ErrLimitExceeded, "a limit is a recorded failure, not an error of the pass")
+	require.Len(t, executed, 2)
+	assert.Equal(t, []int{1, 2}, []int{executed[0].ID, executed[1].ID})
+	// Пропущенные запуски не наверстываются: следующий считается от текущего времени.
+	require.NotNil(t, nextRuns[1])
+	assert.Equal(t, time.Date(2025, 2, 6, 0, 0, 0, 0, time.UTC), *nextRuns[1])
+	assert.Nil(t, nextRuns[2])
+	assert.Len(t, mockStorage.RunScheduledTransferCalls(), 6)
+}
+
+func TestRunDueTransfers_PerTransferLimit(t *testing.T) {
+	now := time.Date(2025, 2, 5, 10, 30, 0, 0, time.UTC)
+	mockStorage := &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		ListDueScheduledTransfersFunc: func(ctx context.Context, at time.Time, limit int) ([]storage.ScheduledTransfer, error) {
+			return []storage.ScheduledTransfer{{ID: 1, SenderID: 1, RecipientID: 2, Amount: 500, NextRunAt: now}}, nil
+		},
+		RunScheduledTransferFunc: func(ctx context.Context, st *storage.ScheduledTransfer, next *time.Time, at time.Time, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+			return storage.CheckTransferLimits(ctx, nil, "", st.SenderID, st.RecipientID, st.Amount, limits)
+		},
+	}
+	s := NewService(mockStorage)
+	s.Limits = config.TransferLimits{Default: config.LimitPolicy{PerTransfer: 100}}
+	s.now = func() time.Time { return now }
+
+	// Лимит уменьшился после создания перевода: запуск пропускается хранилищем, а не прерывает проход.
+	executed, err := s.RunDueTransfers(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, executed)
+	require.Len(t, mockStorage.RunScheduledTransferCalls(), 1)
+	assert.Equal(t, 100, mockStorage.RunScheduledTransferCalls()[0].Limits.MaxPerTransfer)
+}
+
+func TestRunDueTransfers_ApprovalThreshold(t *testing.T) {
+	now := time.Date(2025, 2, 5, 10, 30, 0, 0, time.UTC)
+	mockStorage := &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		ListDueScheduledTransfersFunc: func(ctx context.Context, at time.Time, limit int) ([]storage.ScheduledTransfer, error) {
+			return []storage.ScheduledTransfer{
+				{ID: 1, SenderID: 1, RecipientID: 2, Amount: 500, NextRunAt: now},
+				{ID: 2, SenderID: 1, RecipientID: 2, Amount: 501, NextRunAt: now},
+			}, nil
+		},
+		RunScheduledTransferFunc: func(ctx context.Context, st *storage.ScheduledTransfer, next *time.Time, at time.Time, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+			return storage.CheckTransferLimits(ctx, nil, "", st.SenderID, st.RecipientID, st.Amount, limits)
+		},
+	}
+	s := NewService(mockStorage)
+	s.Approvals = testApprovals
+	s.now = func() time.Time { return now }
+
+	// Порог одобрения уменьшился после создания перевода: запуск сверх него пропускается хранилищем.
+	executed, err := s.RunDueTransfers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, executed, 1)
+	assert.Equal(t, 1, executed[0].ID)
+	require.Len(t, mockStorage.RunScheduledTransferCalls(), 2)
+	assert.Equal(t, 500, mockStorage.RunScheduledTransferCalls()[1].Limits.MaxUnapproved)
+}
+
+func TestScheduler_RunsUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	service := &IServiceMock{
+		RunDueTransfersFunc: func(ctx context.Context) ([]storage.ScheduledTransfer, error) {
+			if calls.Add(1) == 3 {
+				cancel()
+			}
+			return nil, nil
+		},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		NewScheduler(service, time.Millisecond, slog.New(slog.NewTextHandler(io.Discard, nil))).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler did not stop after cancellation")
+	}
+	assert.Equal(t, int32(3), calls.Load())
+}
@@ -0,0 +1,45 @@
+package shop
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Scheduler периодически выполняет наступившие отложенные переводы.
+// Его безопасно запускать на всех репликах: каждый запуск перевода захватывается в хранилище
+// атомарно (см. storage.IStorage.RunScheduledTransfer), поэтому выделять лидера не нужно.
+type Scheduler struct {
+	service  IService
+	interval time.Duration
+	log      *slog.Logger
+}
+
+func NewScheduler(service IService, interval time.Duration, log *slog.Logger) *Scheduler {
+	return &Scheduler{service: service, interval: interval, log: log}
+}
+
+// Run выполняет проход сразу и затем каждые interval, пока не отменён ctx.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	executed, err := s.service.RunDueTransfers(ctx)
+	if err != nil && ctx.Err() == nil {
+		s.log.ErrorContext(ctx, "Failed to run scheduled transfers", slog.String("error", err.Error()))
+	}
+	if len(executed) > 0 {
+		s.log.InfoContext(ctx, "Scheduled transfers executed", slog.Int("count", len(executed)))
+	}
+}
@@ -1,29 +1,122 @@
 package shop
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"time"
 
+	"avito-shop/internal/cache"
+	"avito-shop/internal/config"
+	"avito-shop/internal/metrics"
 	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/tracing"
 
 	"github.com/golang-jwt/jwt/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("avito-shop/internal/service/shop")
+
+// DefaultPaymentRequestTTL — срок действия запроса на перевод, если в конфигурации не задан другой.
+const DefaultPaymentRequestTTL = 72 * time.Hour
+
 type Service struct {
 	Storage storage.IStorage
+	// PaymentRequestTTL — через сколько запрос на перевод истекает.
+	PaymentRequestTTL time.Duration
+	// Limits — лимиты переводов; нулевое значение ничего не ограничивает.
+	Limits config.TransferLimits
+	// Fees — комиссия за переводы; включается через EnableFees.
+	Fees config.TransferFees
+	// Approvals — одобрение крупных переводов; нулевое значение выключает его.
+	Approvals config.TransferApprovals
+	// Events получает уведомления сервиса; nil — уведомления не отправляются.
+	Events EventSink
+
+	// Expiry — сгорание монет; включается через EnableCoinExpiry.
+	Expiry config.CoinExpiry
+	// Leaderboards — периоды и размер таблиц лидеров.
+	Leaderboards config.Leaderboards
+	// LeaderboardCache хранит посчитанные таблицы лидеров; nil — считать на каждый запрос.
+	LeaderboardCache cache.Cache[*storage.Leaderboard]
+
+	feeAccountID     int
+	expiryAccountID  int
+	achievementRules []config.AchievementRule
+	// subscribers — внутренние получатели событий, см. subscribe.
+	subscribers []EventSink
+
+	now func() time.Time
 }
 
 func NewService(storage storage.IStorage) *Service {
 	return &Service{
-		Storage: storage,
+		Storage:           storage,
+		PaymentRequestTTL: DefaultPaymentRequestTTL,
+		now:               func() time.Time { return time.Now().UTC().Truncate(time.Second) },
 	}
 }
 
 type IService interface {
-	CollectAllInfo(username string) (*storage.InfoResponse, error)
-	Send(fromUsername string, scr *storage.SendCoinRequest) error
-	Purchase(username, item string) error
+	CollectAllInfo(ctx context.Context, username string) (*storage.InfoResponse, error)
+	Send(ctx context.Context, fromUsername string, scr *storage.SendCoinRequest) error
+	Purchase(ctx context.Context, username, item, promoCode string) error
+	QuotePurchase(ctx context.Context, username, item, promoCode string) (*storage.PriceQuote, error)
+	History(ctx context.Context, username string, filter storage.HistoryFilter) (*storage.CoinHistory, error)
+
+	RequestPayment(ctx context.Context, requester string, req *PaymentRequestInput) (*storage.PaymentRequest, error)
+	ListPaymentRequests(ctx context.Context, username string, direction string) ([]storage.PaymentRequest, error)
+	AcceptPaymentRequest(ctx context.Context, username string, id int) (*storage.PaymentRequest, error)
+	DeclinePaymentRequest(ctx context.Context, username string, id int) (*storage.PaymentRequest, error)
+	CancelPaymentRequest(ctx context.Context, username string, id int) (*storage.PaymentRequest, error)
+
+	ScheduleTransfer(ctx context.Context, fromUsername string, in *ScheduledTransferInput) (*storage.ScheduledTransfer, error)
+	ListScheduledTransfers(ctx context.Context, username string) ([]storage.ScheduledTransfer, error)
+	CancelScheduledTransfer(ctx context.Context, username string, id int) (*storage.ScheduledTransfer, error)
+	RunDueTransfers(ctx context.Context) ([]storage.ScheduledTransfer, error)
+
+	ExpireCoins(ctx context.Context) ([]storage.ExpiredCoins, error)
+
+	CreatePromoCode(ctx context.Context, admin string, in *PromoCodeInput) (*storage.PromoCode, error)
+	ListPromoCodes(ctx context.Context, admin string) ([]storage.PromoCode, error)
+	DisablePromoCode(ctx context.Context, admin, code string) (*storage.PromoCode, error)
+
+	Catalog(ctx context.Context) ([]storage.CatalogItem, error)
+	ListOrders(ctx context.Context, username string) ([]storage.Order, error)
+	ScheduleItemPrice(ctx context.Context, admin string, in *ItemPriceInput) (*storage.ItemPrice, error)
+	ListItemPrices(ctx context.Context, admin string) ([]storage.ItemPrice, error)
+	CancelItemPrice(ctx context.Context, admin string, id int) (*storage.ItemPrice, error)
+
+	Leaderboard(ctx context.Context, board, period string, limit int) (*storage.Leaderboard, error)
+	SetLeaderboardOptOut(ctx context.Context, username string, optOut bool) error
+
+	CreateGroup(ctx context.Context, username string, in *GroupInput) (*storage.Group, error)
+	ListGroups(ctx context.Context, username string) ([]storage.Group, error)
+	GetGroup(ctx context.Context, username, name string) (*storage.GroupInfo, error)
+	GroupHistory(ctx context.Context, username, name string, filter storage.HistoryFilter) (*storage.CoinHistory, error)
+	SetGroupMember(ctx context.Context, username, name string, in *GroupMemberInput) ([]storage.GroupMember, error)
+	RemoveGroupMember(ctx context.Context, username, name, member string) ([]storage.GroupMember, error)
+	SendFromGroup(ctx context.Context, username, name string, scr *storage.SendCoinRequest) error
+	ProposeGroupPurchase(ctx context.Context, username, name, item string) (*storage.GroupPurchase, error)
+	ApproveGroupPurchase(ctx context.Context, username, name string, id int) (*storage.GroupPurchase, error)
+	CancelGroupPurchase(ctx context.Context, username, name string, id int) (*storage.GroupPurchase, error)
+	ListGroupPurchases(ctx context.Context, username, name string) ([]storage.GroupPurchase, error)
+
+	ListPendingTransfers(ctx context.Context, username string) ([]storage.PendingTransfer, error)
+	ApprovePendingTransfer(ctx context.Context, approver string, id int) (*storage.PendingTransfer, error)
+	RejectPendingTransfer(ctx context.Context, approver string, id int) (*storage.PendingTransfer, error)
+	ExpirePendingTransfers(ctx context.Context) ([]storage.PendingTransfer, error)
+
+	ListUserTransactions(ctx context.Context, admin, username string) ([]storage.Transaction, error)
+	ReverseTransfer(ctx context.Context, admin string, in *ReversalInput) (*storage.TransferReversal, error)
+
+	RecordAuth(ctx context.Context, username, action string)
+	AuditLog(ctx context.Context, admin string, filter storage.AuditFilter) ([]storage.AuditEntry, error)
+	ExportAuditLog(ctx context.Context, admin string, filter storage.AuditFilter, write func([]storage.AuditEntry) error) error
+	VerifyAuditLog(ctx context.Context, admin string) (*AuditVerification, error)
 }
 
 func GenerateJWT(secretKey string, username string) (string, error) {
@@ -46,41 +139,47 @@ func GenerateJWT(secretKey string, username string) (string, error) {
 	return tokenSign, nil
 }
 
-func (s *Service) CollectAllInfo(username string) (*storage.InfoResponse, error) {
-	var res storage.InfoResponse
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var errs []error
+func (s *Service) CollectAllInfo(ctx context.Context, username string) (_ *storage.InfoResponse, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.CollectAllInfo")
+	defer func() { tracing.End(span, err) }()
 
-	id, err := s.Storage.GetInfo(&res, username)
+	var res storage.InfoResponse
+	id, err := s.Storage.GetFullInfo(ctx, &res, username)
 	if err != nil {
 		return nil, ErrInternalServer
 	}
-
-	runCollect := func(fn func(*storage.InfoResponse, int) error) {
-		defer wg.Done()
-		err := fn(&res, id)
-		if err != nil {
-			mu.Lock()
-			errs = append(errs, ErrInternalServer)
-			mu.Unlock()
+	if s.Limits.Enabled() {
+		if res.Limits, err = s.limitUsage(ctx, id); err != nil {
+			return nil, ErrInternalServer
 		}
 	}
-	wg.Add(3)
-	go runCollect(s.Storage.GetInventory)
-	go runCollect(s.Storage.GetSendHistory)
-	go runCollect(s.Storage.GetReceivedHistory)
-
-	wg.Wait()
-
-	if len(errs) > 0 {
-		return nil, errs[0]
+	if s.expiryAccountID != 0 {
+		if res.Expiring, err = s.coinExpirations(ctx, id); err != nil {
+			return nil, ErrInternalServer
+		}
+	}
+	if len(s.achievementRules) > 0 {
+		if res.Achievements, err = s.Storage.ListAchievements(ctx, id); err != nil {
+			return nil, ErrInternalServer
+		}
 	}
 
 	return &res, nil
 }
 
-func (s *Service) Send(fromUsername string, scr *storage.SendCoinRequest) error {
+func (s *Service) Send(ctx context.Context, fromUsername string, scr *storage.SendCoinRequest) (err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.Send", trace.WithAttributes(attribute.Int("shop.amount", scr.Amount)))
+	defer func() { tracing.End(span, err) }()
+
+	return s.send(ctx, fromUsername, fromUsername, scr)
+}
+
+// send переводит монеты от fromUsername; actor — кто выполнил перевод, для журнала аудита.
+func (s *Service) send(ctx context.Context, actor, fromUsername string, scr *storage.SendCoinRequest) (err error) {
+	if err = ValidateSendCoinRequest(fromUsername, scr); err != nil {
+		return err
+	}
+
 	var (
 		infoResponseFrom, infoResponseTo storage.InfoResponse
 		fromUserID, toUserID             int
@@ -91,7 +190,7 @@ func (s *Service) Send(fromUsername string, scr *storage.SendCoinRequest) error
 
 	fetchUserInfo := func(username string, infoResponse *storage.InfoResponse, userID *int) {
 		defer wg.Done()
-		id, err := s.Storage.GetInfo(infoResponse, username)
+		id, err := s.Storage.GetInfo(ctx, infoResponse, username)
 		if err != nil {
 			if errors.Is(err, storage.ErrUserNotFound) {
 				mu.Lock()
@@ -116,26 +215,59 @@ func (s *Service) Send(fromUsername string, scr *storage.SendCoinRequest) error
 		return errs[0]
 	}
 
-	if infoResponseFrom.Coins < scr.Amount {
+	fee, err := s.transferFee(ctx, fromUserID, scr.Amount)
+	if err != nil {
+		return err
+	}
+
+	if infoResponseFrom.Coins < scr.Amount+fee.Amount {
+		metrics.InsufficientFundsTotal.WithLabelValues(metrics.OperationSend).Inc()
 		return ErrInsufficientFunds
 	}
 
-	err := s.Storage.SendCoins(fromUsername, fromUserID, toUserID, scr)
+	limits, err := s.transferLimits(ctx, fromUserID, toUserID, scr.Amount)
 	if err != nil {
+		return err
+	}
+
+	if s.requiresApproval(scr.Amount) {
+		return s.holdTransfer(ctx, actor, fromUsername, fromUserID, toUserID, scr, limits, fee)
+	}
+
+	audit := s.txAudit(ctx, actor, AuditTransferSend, auditTarget("user", scr.ToUser), fromUserID, toUserID)
+	err = s.Storage.SendCoins(ctx, fromUsername, fromUserID, toUserID, scr, limits, fee, audit)
+	if err != nil {
+		if le, ok := limitError(err); ok {
+			return le
+		}
+		if errors.Is(err, storage.ErrInsufficientFunds) {
+			metrics.InsufficientFundsTotal.WithLabelValues(metrics.OperationSend).Inc()
+			return ErrInsufficientFunds
+		}
 		return ErrInternalServer
 	}
+	metrics.CoinsTransferredTotal.Add(float64(scr.Amount))
+	metrics.FeesCollectedTotal.Add(float64(fee.Amount))
+	s.publishTransfer(ctx, fromUserID, fromUsername, toUserID, scr.ToUser, scr.Amount, fee.Amount)
 
 	return nil
 }
 
-func (s *Service) Purchase(username, item string) error {
-	price, exists := storage.MerchItems[item]
-	if !exists {
-		return ErrItemNotFound
+// Purchase покупает item; непустой promoCode применяется к цене, а покупка записывается с ценой и скидкой.
+// Цена берётся из каталога до транзакции покупки: если она сменилась в этот промежуток, покупка проходит
+// по уже показанной цене, а в заказе сохраняется id записи цены, по которой она посчитана.
+func (s *Service) Purchase(ctx context.Context, username, item, promoCode string) (err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.Purchase", trace.WithAttributes(attribute.String("shop.item", item)))
+	defer func() { tracing.End(span, err) }()
+
+	catalogItem, err := s.catalogItem(ctx, item)
+	if err != nil {
+		return err
 	}
+	price := catalogItem.Price
 
 	var infoResponse storage.InfoResponse
-	_, err := s.Storage.GetInfo(&infoResponse, username)
+	id, err := s.Storage.GetInfo(ctx, &infoResponse, username)
 	if err != nil {
 		if errors.Is(err, storage.ErrUserNotFound) {
 			return storage.ErrUserNotFound
@@ -143,14 +275,68 @@ func (s *Service) Purchase(username, item string) error {
 		return ErrInternalServer
 	}
 
-	if infoResponse.Coins < price {
+	quote, promo, err := s.quote(ctx, id, item, price, promoCode)
+	if err != nil {
+		return err
+	}
+
+	if infoResponse.Coins < quote.Total {
+		metrics.InsufficientFundsTotal.WithLabelValues(metrics.OperationPurchase).Inc()
 		return ErrInsufficientFunds
 	}
 
-	err = s.Storage.BuyItem(username, item, price)
+	audit := s.txAudit(ctx, username, AuditPurchase, auditTarget("item", item), id)
+	if promo == nil {
+		err = s.Storage.BuyItem(ctx, username, item, price, catalogItem.PriceID, audit)
+	} else {
+		err = s.Storage.BuyItemWithPromo(ctx, username, item, &storage.PromoRedemption{
+			PromoCodeID: promo.ID,
+			Price:       price,
+			PriceID:     catalogItem.PriceID,
+			Discount:    quote.Discount,
+			RedeemedAt:  s.now(),
+		}, audit)
+	}
 	if err != nil {
+		// Баланс мог уменьшиться параллельной операцией после проверки выше.
+		if errors.Is(err, storage.ErrInsufficientFunds) {
+			metrics.InsufficientFundsTotal.WithLabelValues(metrics.OperationPurchase).Inc()
+			return ErrInsufficientFunds
+		}
+		if promo != nil {
+			return promoRedemptionError(err)
+		}
 		return ErrInternalServer
 	}
+	metrics.PurchasesTotal.WithLabelValues(item).Inc()
+	if promo != nil {
+		metrics.PromoDiscountsTotal.WithLabelValues(promo.Code).Add(float64(quote.Discount))
+	}
+	s.publishPurchase(ctx, id, username, item, quote.Total)
 
 	return nil
 }
+
+func (s *Service) History(ctx context.Context, username string, filter storage.HistoryFilter) (_ *storage.CoinHistory, err error) {
+	ctx, span := tracer.Start(ctx, "shop.Service.History", trace.WithAttributes(attribute.String("shop.category", filter.Category)))
+	defer func() { tracing.End(span, err) }()
+
+	if err = ValidateHistoryFilter(filter); err != nil {
+		return nil, err
+	}
+
+	var res storage.InfoResponse
+	id, err := s.Storage.GetInfo(ctx, &res, username)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrInternalServer
+	}
+
+	if err = s.Storage.GetCoinHistory(ctx, &res, id, filter); err != nil {
+		return nil, ErrInternalServer
+	}
+
+	return &res.CoinHistory, nil
+}
@@ -2,10 +2,12 @@ package shop
 
 import (
 	"avito-shop/internal/service/shop/storage"
+	"context"
 
 	"errors"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 	"time"
 )
@@ -79,7 +81,12 @@ func TestGenerateJWT(t *testing.T) {
 }
 
 func TestPurchase_TableDriven(t *testing.T) {
-	mockStorage := &storage.IStorageMock{}
+	mockStorage := &storage.IStorageMock{
+		AppendAuditFunc: nopAppendAudit,
+		ListItemPricesFunc: func(ctx context.Context, now time.Time) ([]storage.ItemPrice, error) {
+			return nil, nil
+		},
+	}
 	service := NewService(mockStorage)
 
 	tests := []struct {
@@ -95,11 +102,11 @@ func TestPurchase_TableDriven(t *testing.T) {
 			username: "test_user",
 			setupMocks: func() {
 				storage.MerchItems["t-shirt"] = 50 // Добавляем предмет в список товаров
-				mockStorage.GetInfoFunc = func(res *storage.InfoResponse, username string) (int, error) {
+				mockStorage.GetInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
 					res.Coins = 100 // У пользователя достаточно средств
 					return 1, nil
 				}
-				mockStorage.BuyItemFunc = func(name, item string, amount int) error {
+				mockStorage.BuyItemFunc = func(ctx context.Context, name, item string, amount, priceID int, audit *storage.TxAudit) error {
 					return nil
 				}
 			},
@@ -118,7 +125,7 @@ func TestPurchase_TableDriven(t *testing.T) {
 			username: "test_user",
 			setupMocks: func() {
 				storage.MerchItems["t-shirt"] = 50 // Добавляем предмет в список товаров
-				mockStorage.GetInfoFunc = func(res *storage.InfoResponse, username string) (int, error) {
+				mockStorage.GetInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
 					res.Coins = 30 // У пользователя недостаточно средств
 					return 1, nil
 				}
@@ -131,7 +138,7 @@ func TestPurchase_TableDriven(t *testing.T) {
 			username: "test_user",
 			setupMocks: func() {
 				storage.MerchItems["t-shirt"] = 50 // Добавляем предмет в список товаров
-				mockStorage.GetInfoFunc = func(res *storage.InfoResponse, username string) (int, error) {
+				mockStorage.GetInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
 					return 0, errors.New("database error")
 				}
 			},
@@ -143,16 +150,32 @@ func TestPurchase_TableDriven(t *testing.T) {
 			username: "test_user",
 			setupMocks: func() {
 				storage.MerchItems["t-shirt"] = 50 // Добавляем предмет в список товаров
-				mockStorage.GetInfoFunc = func(res *storage.InfoResponse, username string) (int, error) {
+				mockStorage.GetInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
 					res.Coins = 100 // У пользователя достаточно средств
 					return 1, nil
 				}
-				mockStorage.BuyItemFunc = func(name, item string, amount int) error {
+				mockStorage.BuyItemFunc = func(ctx context.Context, name, item string, amount, priceID int, audit *storage.TxAudit) error {
 					return errors.New("buy item error")
 				}
 			},
 			expectedError: ErrInternalServer,
 		},
+		{
+			name:     "Balance spent concurrently",
+			item:     "t-shirt",
+			username: "test_user",
+			setupMocks: func() {
+				storage.MerchItems["t-shirt"] = 50 // Добавляем предмет в список товаров
+				mockStorage.GetInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+					res.Coins = 100 // Проверка в сервисе проходит, но баланс уже потрачен другой операцией
+					return 1, nil
+				}
+				mockStorage.BuyItemFunc = func(ctx context.Context, name, item string, amount, priceID int, audit *storage.TxAudit) error {
+					return storage.ErrInsufficientFunds
+				}
+			},
+			expectedError: ErrInsufficientFunds,
+		},
 	}
 
 	for _, tt := range tests {
@@ -161,7 +184,7 @@ func TestPurchase_TableDriven(t *testing.T) {
 				tt.setupMocks()
 			}
 
-			err := service.Purchase(tt.username, tt.item)
+			err := service.Purchase(context.Background(), tt.username, tt.item, "")
 
 			assert.Equal(t, tt.expectedError, err)
 		})
@@ -180,27 +203,18 @@ func TestCollectAllInfo_TableDriven(t *testing.T) {
 			name:     "Successful collection",
 			username: "test_user",
 			setupMocks: func(mockStorage *storage.IStorageMock) {
-				mockStorage.GetInfoFunc = func(res *storage.InfoResponse, username string) (int, error) {
+				mockStorage.GetFullInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
 					res.Coins = 100
-					return 1, nil
-				}
-				mockStorage.GetInventoryFunc = func(res *storage.InfoResponse, id int) error {
 					res.Inventory = []storage.Inventory{
 						{Type: "t-shirt", Quantity: 2},
 					}
-					return nil
-				}
-				mockStorage.GetSendHistoryFunc = func(res *storage.InfoResponse, id int) error {
 					res.CoinHistory.Sent = []storage.TransactionOut{
 						{ToUser: "jane_doe", Amount: 50},
 					}
-					return nil
-				}
-				mockStorage.GetReceivedHistoryFunc = func(res *storage.InfoResponse, id int) error {
 					res.CoinHistory.Received = []storage.TransactionIn{
 						{FromUser: "john_doe", Amount: 30},
 					}
-					return nil
+					return 1, nil
 				}
 			},
 			expectedResult: &storage.InfoResponse{
@@ -219,6 +233,17 @@ func TestCollectAllInfo_TableDriven(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			name:     "Storage error",
+			username: "test_user",
+			setupMocks: func(mockStorage *storage.IStorageMock) {
+				mockStorage.GetFullInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+					return 0, errors.New("db error")
+				}
+			},
+			expectedResult: nil,
+			expectedError:  ErrInternalServer,
+		},
 	}
 
 	for _, tt := range tests {
@@ -231,7 +256,7 @@ func TestCollectAllInfo_TableDriven(t *testing.T) {
 				tt.setupMocks(mockStorage)
 			}
 
-			result, err := service.CollectAllInfo(tt.username)
+			result, err := service.CollectAllInfo(context.Background(), tt.username)
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -260,7 +285,7 @@ func TestSend_TableDriven(t *testing.T) {
 				Amount: 50,
 			},
 			setupMocks: func(mockStorage *storage.IStorageMock) {
-				mockStorage.GetInfoFunc = func(res *storage.InfoResponse, username string) (int, error) {
+				mockStorage.GetInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
 					if username == "from_user" {
 						res.Coins = 100
 						return 1, nil
@@ -271,7 +296,7 @@ func TestSend_TableDriven(t *testing.T) {
 					}
 					return 0, errors.New("unknown user")
 				}
-				mockStorage.SendCoinsFunc = func(username string, fromUserID int, toUserID int, scr *storage.SendCoinRequest) error {
+				mockStorage.SendCoinsFunc = func(ctx context.Context, username string, fromUserID int, toUserID int, scr *storage.SendCoinRequest, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
 					return nil
 				}
 			},
@@ -285,7 +310,7 @@ func TestSend_TableDriven(t *testing.T) {
 				Amount: 50,
 			},
 			setupMocks: func(mockStorage *storage.IStorageMock) {
-				mockStorage.GetInfoFunc = func(res *storage.InfoResponse, username string) (int, error) {
+				mockStorage.GetInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
 					if username == "from_user" {
 						res.Coins = 30
 						return 1, nil
@@ -307,7 +332,7 @@ func TestSend_TableDriven(t *testing.T) {
 				Amount: 50,
 			},
 			setupMocks: func(mockStorage *storage.IStorageMock) {
-				mockStorage.GetInfoFunc = func(res *storage.InfoResponse, username string) (int, error) {
+				mockStorage.GetInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
 					if username == "from_user" {
 						return 0, errors.New("database error")
 					}
@@ -328,7 +353,7 @@ func TestSend_TableDriven(t *testing.T) {
 				Amount: 50,
 			},
 			setupMocks: func(mockStorage *storage.IStorageMock) {
-				mockStorage.GetInfoFunc = func(res *storage.InfoResponse, username string) (int, error) {
+				mockStorage.GetInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
 					if username == "from_user" {
 						res.Coins = 100
 						return 1, nil
@@ -349,7 +374,7 @@ func TestSend_TableDriven(t *testing.T) {
 				Amount: 50,
 			},
 			setupMocks: func(mockStorage *storage.IStorageMock) {
-				mockStorage.GetInfoFunc = func(res *storage.InfoResponse, username string) (int, error) {
+				mockStorage.GetInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
 					if username == "from_user" {
 						res.Coins = 100
 						return 1, nil
@@ -360,24 +385,64 @@ func TestSend_TableDriven(t *testing.T) {
 					}
 					return 0, errors.New("unknown user")
 				}
-				mockStorage.SendCoinsFunc = func(username string, fromUserID int, toUserID int, scr *storage.SendCoinRequest) error {
+				mockStorage.SendCoinsFunc = func(ctx context.Context, username string, fromUserID int, toUserID int, scr *storage.SendCoinRequest, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
 					return errors.New("send coins error")
 				}
 			},
 			expectedError: ErrInternalServer,
 		},
+		{
+			name:         "Balance spent concurrently",
+			fromUsername: "from_user",
+			scr: &storage.SendCoinRequest{
+				ToUser: "to_user",
+				Amount: 50,
+			},
+			setupMocks: func(mockStorage *storage.IStorageMock) {
+				mockStorage.GetInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+					if username == "from_user" {
+						res.Coins = 100
+						return 1, nil
+					}
+					res.Coins = 50
+					return 2, nil
+				}
+				mockStorage.SendCoinsFunc = func(ctx context.Context, username string, fromUserID int, toUserID int, scr *storage.SendCoinRequest, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+					return storage.ErrInsufficientFunds
+				}
+			},
+			expectedError: ErrInsufficientFunds,
+		},
+		{
+			name:          "Zero amount",
+			fromUsername:  "from_user",
+			scr:           &storage.SendCoinRequest{ToUser: "to_user", Amount: 0},
+			expectedError: ErrValidation,
+		},
+		{
+			name:          "Negative amount",
+			fromUsername:  "from_user",
+			scr:           &storage.SendCoinRequest{ToUser: "to_user", Amount: -50},
+			expectedError: ErrValidation,
+		},
+		{
+			name:          "Send to yourself",
+			fromUsername:  "from_user",
+			scr:           &storage.SendCoinRequest{ToUser: "From_User", Amount: 50},
+			expectedError: ErrValidation,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mockStorage := &storage.IStorageMock{}
+			mockStorage := &storage.IStorageMock{AppendAuditFunc: nopAppendAudit}
 			service := NewService(mockStorage)
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(mockStorage)
 			}
 
-			err := service.Send(tt.fromUsername, tt.scr)
+			err := service.Send(context.Background(), tt.fromUsername, tt.scr)
 
 			if tt.expectedError != nil {
 				assert.Error(t, err)
@@ -388,3 +453,168 @@ func TestSend_TableDriven(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSendCoinRequest_TableDriven(t *testing.T) {
+	tests := []struct {
+		name           string
+		fromUsername   string
+		scr            *storage.SendCoinRequest
+		expectedFields []FieldError
+	}{
+		{
+			name:         "Valid request",
+			fromUsername: "from_user",
+			scr:          &storage.SendCoinRequest{ToUser: "to_user", Amount: 10},
+		},
+		{
+			name:         "All violations are reported",
+			fromUsername: "from_user",
+			scr:          &storage.SendCoinRequest{ToUser: "", Amount: -1},
+			expectedFields: []FieldError{
+				{Field: "toUser", Message: "имя пользователя не указано"},
+				{Field: "amount", Message: "сумма перевода должна быть положительной"},
+			},
+		},
+		{
+			name:         "Oversized amount and malformed username",
+			fromUsername: "from_user",
+			scr:          &storage.SendCoinRequest{ToUser: "to user; DROP", Amount: MaxTransferAmount + 1},
+			expectedFields: []FieldError{
+				{Field: "toUser", Message: "имя пользователя может содержать только буквы, цифры и символы _ . @ -"},
+				{Field: "amount", Message: "сумма перевода не может превышать 1000000"},
+			},
+		},
+		{
+			name:         "Self transfer",
+			fromUsername: "from_user",
+			scr:          &storage.SendCoinRequest{ToUser: "from_user", Amount: 10},
+			expectedFields: []FieldError{
+				{Field: "toUser", Message: "нельзя отправить монеты самому себе"},
+			},
+		},
+		{
+			name:         "Memo and category",
+			fromUsername: "from_user",
+			scr: &storage.SendCoinRequest{
+				ToUser: "to_user", Amount: 10, Memo: strings.Repeat("ж", MaxMemoLength), Category: "lunch",
+			},
+		},
+		{
+			name:         "Oversized memo and unknown category",
+			fromUsername: "from_user",
+			scr: &storage.SendCoinRequest{
+				ToUser: "to_user", Amount: 10, Memo: strings.Repeat("ж", MaxMemoLength+1), Category: "casino",
+			},
+			expectedFields: []FieldError{
+				{Field: "memo", Message: "комментарий длиннее 255 символов"},
+				{Field: "category", Message: "неизвестная категория, допустимы: thanks, bet, lunch, gift, other"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendCoinRequest(tt.fromUsername, tt.scr)
+			if tt.expectedFields == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var ve *ValidationError
+			assert.ErrorAs(t, err, &ve)
+			assert.ErrorIs(t, err, ErrValidation)
+			assert.Equal(t, tt.expectedFields, ve.Fields)
+		})
+	}
+}
+
+func TestHistory_TableDriven(t *testing.T) {
+	tests := []struct {
+		name           string
+		filter         storage.HistoryFilter
+		setupMocks     func(mockStorage *storage.IStorageMock)
+		expectedResult *storage.CoinHistory
+		expectedError  error
+	}{
+		{
+			name:   "Filtered history",
+			filter: storage.HistoryFilter{Category: "lunch"},
+			setupMocks: func(mockStorage *storage.IStorageMock) {
+				mockStorage.GetInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+					return 7, nil
+				}
+				mockStorage.GetCoinHistoryFunc = func(ctx context.Context, res *storage.InfoResponse, id int, filter storage.HistoryFilter) error {
+					if id != 7 || filter.Category != "lunch" {
+						return errors.New("unexpected arguments")
+					}
+					res.CoinHistory.Sent = []storage.TransactionOut{{ToUser: "2", Amount: 10, Category: "lunch"}}
+					return nil
+				}
+			},
+			expectedResult: &storage.CoinHistory{
+				Sent: []storage.TransactionOut{{ToUser: "2", Amount: 10, Category: "lunch"}},
+			},
+		},
+		{
+			name:   "Fees only",
+			filter: storage.HistoryFilter{Category: storage.FeeCategory},
+			setupMocks: func(mockStorage *storage.IStorageMock) {
+				mockStorage.GetInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+					return 7, nil
+				}
+				mockStorage.GetCoinHistoryFunc = func(ctx context.Context, res *storage.InfoResponse, id int, filter storage.HistoryFilter) error {
+					res.CoinHistory.Sent = []storage.TransactionOut{{ToUser: "1", Amount: 1, Category: storage.FeeCategory}}
+					return nil
+				}
+			},
+			expectedResult: &storage.CoinHistory{
+				Sent: []storage.TransactionOut{{ToUser: "1", Amount: 1, Category: storage.FeeCategory}},
+			},
+		},
+		{
+			name:          "Unknown category",
+			filter:        storage.HistoryFilter{Category: "casino"},
+			expectedError: ErrValidation,
+		},
+		{
+			name: "User not found",
+			setupMocks: func(mockStorage *storage.IStorageMock) {
+				mockStorage.GetInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+					return 0, storage.ErrUserNotFound
+				}
+			},
+			expectedError: ErrUserNotFound,
+		},
+		{
+			name: "Storage error",
+			setupMocks: func(mockStorage *storage.IStorageMock) {
+				mockStorage.GetInfoFunc = func(ctx context.Context, res *storage.InfoResponse, username string) (int, error) {
+					return 7, nil
+				}
+				mockStorage.GetCoinHistoryFunc = func(ctx context.Context, res *storage.InfoResponse, id int, filter storage.HistoryFilter) error {
+					return errors.New("db error")
+				}
+			},
+			expectedError: ErrInternalServer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockStorage := &storage.IStorageMock{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(mockStorage)
+			}
+			service := NewService(mockStorage)
+
+			result, err := service.History(context.Background(), "test_user", tt.filter)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedResult, result)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Achievement — достижение Code, выданное пользователю в AwardedAt. Название и описание
+// сохраняются при выдаче, поэтому достижение остаётся понятным, даже если правило убрали.
+type Achievement struct {
+	Code        string    `json:"code"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	AwardedAt   time.Time `json:"awardedAt"`
+}
+
+// AchievementProgress — показатели пользователя, по которым выдаются достижения: Sent и Received —
+// монеты, отправленные и полученные переводами (без комиссий и сгораний), Items — купленные
+// предметы по названию, Awarded — коды уже выданных достижений.
+type AchievementProgress struct {
+	Sent     int
+	Received int
+	Items    map[string]int
+	Awarded  map[string]bool
+}
+
+// TotalItems возвращает число купленных предметов всех видов.
+func (p *AchievementProgress) TotalItems() int {
+	total := 0
+	for _, n := range p.Items {
+		total += n
+	}
+	return total
+}
+
+// achievementTransfers отбирает переводы между пользователями: комиссии, сгорания и отмены не считаются.
+const achievementTransfers = `(category IS NULL OR category NOT IN ('` + FeeCategory + `', '` + ExpiredCategory + `', '` + ReversalCategory + `'))`
+
+// LoadAchievementProgress читает показатели пользователя userID; rebind переводит запросы в диалект хранилища.
+func LoadAchievementProgress(ctx context.Context, q LotTx, rebind func(string) string, userID int) (*AchievementProgress, error) {
+	p := &AchievementProgress{Items: map[string]int{}, Awarded: map[string]bool{}}
+	err := q.QueryRowContext(ctx, rebind(`
+	SELECT (SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE from_user_id = ? AND `+achievementTransfers+`),
+	       (SELECT COALESCE(SUM(amount), 0) FROM transactions WHERE to_user_id = ? AND `+achievementTransfers+`);`),
+		userID, userID).Scan(&p.Sent, &p.Received)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.QueryContext(ctx, rebind("SELECT item_name, quantity FROM inventory WHERE user_id = ?;"), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			item     string
+			quantity int
+		)
+		if err = rows.Scan(&item, &quantity); err != nil {
+			return nil, err
+		}
+		p.Items[item] = quantity
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	codes, err := q.QueryContext(ctx, rebind("SELECT code FROM achievements WHERE user_id = ?;"), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer codes.Close()
+	for codes.Next() {
+		var code string
+		if err = codes.Scan(&code); err != nil {
+			return nil, err
+		}
+		p.Awarded[code] = true
+	}
+	return p, codes.Err()
+}
+
+// AchievementsQuery выбирает достижения пользователя в порядке выдачи. Параметр — id пользователя.
+const AchievementsQuery = `SELECT code, title, description, awarded_at FROM achievements WHERE user_id = ? ORDER BY awarded_at, id;`
+
+// ScanAchievements читает все строки AchievementsQuery.
+func ScanAchievements(rows *sql.Rows) ([]Achievement, error) {
+	defer rows.Close()
+
+	var res []Achievement
+	for rows.Next() {
+		var a Achievement
+		if err := rows.Scan(&a.Code, &a.Title, &a.Description, &a.AwardedAt); err != nil {
+			return nil, err
+		}
+		a.AwardedAt = a.AwardedAt.UTC()
+		res = append(res, a)
+	}
+	return res, rows.Err()
+}
+
+// InsertAchievementQuery выдаёт достижение; параметры — AchievementArgs. Хранилища дописывают
+// к нему пропуск уже выданного достижения в синтаксисе своей БД.
+const InsertAchievementQuery = `INSERT INTO achievements (user_id, code, title, description, awarded_at) VALUES (?, ?, ?, ?, ?)`
+
+// AchievementArgs возвращает значения колонок user_id, code, title, description, awarded_at для вставки достижения.
+func AchievementArgs(userID int, a *Achievement) []any {
+	return []any{userID, a.Code, a.Title, a.Description, a.AwardedAt}
+}
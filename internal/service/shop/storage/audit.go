@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AuditEntry — запись журнала аудита: кто (Actor) что сделал (Action) с чем (Target), какие
+// значения были до и после, из какого запроса. Журнал только дополняется: каждая запись хранит
+// хэш предыдущей (PrevHash) и свой (Hash, см. AuditHash), поэтому изменение или удаление записи
+// обнаруживается проверкой цепочки.
+type AuditEntry struct {
+	ID        int             `json:"id"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Target    string          `json:"target"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	RequestID string          `json:"requestId,omitempty"`
+	IP        string          `json:"ip,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+	PrevHash  string          `json:"prevHash"`
+	Hash      string          `json:"hash"`
+}
+
+// AuditFilter отбирает записи журнала; пустые поля не ограничивают выборку. Записи идут
+// в порядке добавления начиная с id больше AfterID, не больше Limit штук.
+type AuditFilter struct {
+	Actor   string
+	Action  string
+	Target  string
+	From    *time.Time
+	To      *time.Time
+	AfterID int
+	Limit   int
+}
+
+// AuditHead — последняя запись журнала. Хранится отдельно от записей, поэтому удаление
+// записей с конца журнала тоже обнаруживается.
+type AuditHead struct {
+	LastID int    `json:"lastId"`
+	Hash   string `json:"hash"`
+}
+
+// AuditHash возвращает хэш записи: SHA-256 от хэша предыдущей записи и полей самой записи
+// в фиксированном порядке. ID в хэш не входит: порядок записей задаёт цепочка PrevHash.
+func AuditHash(e *AuditEntry) string {
+	data, _ := json.Marshal(struct {
+		PrevHash  string          `json:"prevHash"`
+		Actor     string          `json:"actor"`
+		Action    string          `json:"action"`
+		Target    string          `json:"target"`
+		Before    json.RawMessage `json:"before"`
+		After     json.RawMessage `json:"after"`
+		RequestID string          `json:"requestId"`
+		IP        string          `json:"ip"`
+		CreatedAt string          `json:"createdAt"`
+	}{e.PrevHash, e.Actor, e.Action, e.Target, e.Before, e.After, e.RequestID, e.IP, e.CreatedAt.UTC().Format(time.RFC3339)})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// TxAudit — запись журнала об операции с монетами. Хранилище добавляет Entry в транзакции самой
+// операции, поэтому запись есть тогда и только тогда, когда операция выполнена. Before и After
+// записи — балансы счетов Accounts, прочитанные в этой транзакции до и после изменения; если
+// Accounts пуст, счета выбирает операция (например, участников отменяемого перевода). Если задан
+// TargetKind, цель записи — созданный операцией объект этого вида, и его id дописывает Created.
+// nil — операция не журналируется.
+type TxAudit struct {
+	Entry      *AuditEntry
+	Accounts   []int
+	TargetKind string
+}
+
+// Created делает целью записи созданный операцией объект id, если задан TargetKind.
+func (a *TxAudit) Created(id int) {
+	if a != nil && a.TargetKind != "" {
+		a.Entry.Target = fmt.Sprintf("%s:%d", a.TargetKind, id)
+	}
+}
+
+// AuditBalanceQuery читает баланс счёта для TxAudit. mysql и postgres добавляют FOR UPDATE: балансы
+// до операции читаются первыми в её транзакции, поэтому счета блокируются до конца транзакции
+// и баланс «до» не устаревает к моменту изменения.
+const AuditBalanceQuery = `SELECT username, coins FROM users WHERE id = ?`
+
+// ReadAuditBalances читает балансы счетов a.Accounts запросом query (см. AuditBalanceQuery)
+// и возвращает их в виде {"username": coins}. Счета читаются по возрастанию id, чтобы транзакции
+// блокировали их в одном порядке.
+func ReadAuditBalances(ctx context.Context, tx LotTx, query string, a *TxAudit) (json.RawMessage, error) {
+	if len(a.Accounts) == 0 {
+		return nil, nil
+	}
+	ids := slices.Clone(a.Accounts)
+	slices.Sort(ids)
+	balances := make(map[string]int, len(ids))
+	for _, id := range slices.Compact(ids) {
+		var (
+			username string
+			coins    int
+		)
+		if err := tx.QueryRowContext(ctx, query, id).Scan(&username, &coins); err != nil {
+			return nil, err
+		}
+		balances[username] = coins
+	}
+	return json.Marshal(balances)
+}
+
+// AuditBefore записывает в запись a балансы её счетов до операции. Вызывается до первого
+// изменения балансов в транзакции операции; при nil a ничего не делает.
+func AuditBefore(ctx context.Context, tx LotTx, query string, a *TxAudit) (err error) {
+	if a == nil {
+		return nil
+	}
+	a.Entry.Before, err = ReadAuditBalances(ctx, tx, query, a)
+	return err
+}
+
+// AuditPartiesBefore — AuditBefore для операции над готовым переводом: если счета записи a
+// не заданы, в неё попадают отправитель fromID и получатель toID.
+func AuditPartiesBefore(ctx context.Context, tx LotTx, query string, a *TxAudit, fromID, toID int) error {
+	if a != nil && len(a.Accounts) == 0 {
+		a.Accounts = []int{fromID, toID}
+	}
+	return AuditBefore(ctx, tx, query, a)
+}
+
+// AuditAfter записывает в запись a балансы её счетов после операции; при nil a ничего не делает.
+func AuditAfter(ctx context.Context, tx LotTx, query string, a *TxAudit) (err error) {
+	if a == nil {
+		return nil
+	}
+	a.Entry.After, err = ReadAuditBalances(ctx, tx, query, a)
+	return err
+}
+
+// AuditHeadQuery читает последнюю запись журнала. mysql и postgres добавляют FOR UPDATE,
+// чтобы записи добавлялись по одной и цепочка не ветвилась.
+const AuditHeadQuery = `SELECT last_id, hash FROM audit_head WHERE id = 1`
+
+// ChainAuditEntry читает последнюю запись журнала запросом headQuery (см. AuditHeadQuery)
+// и заполняет PrevHash и Hash записи e; CreatedAt округляется до секунды, как его хранит БД.
+// Запись добавляет вызывающий (InsertAuditEntryQuery), после чего в той же транзакции
+// сдвигает последнюю запись через AdvanceAuditHead.
+func ChainAuditEntry(ctx context.Context, tx LotTx, headQuery string, e *AuditEntry) error {
+	var head AuditHead
+	if err := tx.QueryRowContext(ctx, headQuery).Scan(&head.LastID, &head.Hash); err != nil {
+		return err
+	}
+	e.CreatedAt = e.CreatedAt.UTC().Truncate(time.Second)
+	e.PrevHash = head.Hash
+	e.Hash = AuditHash(e)
+	return nil
+}
+
+// InsertAuditEntryQuery добавляет запись журнала; параметры — AuditEntryArgs.
+const InsertAuditEntryQuery = `INSERT INTO audit_log
+	(actor, action, target, before_value, after_value, request_id, ip, created_at, prev_hash, hash)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+// AuditEntryArgs возвращает аргументы InsertAuditEntryQuery.
+func AuditEntryArgs(e *AuditEntry) []any {
+	return []any{e.Actor, e.Action, e.Target, nullJSON(e.Before), nullJSON(e.After), NullString(e.RequestID), NullString(e.IP),
+		e.CreatedAt, e.PrevHash, e.Hash}
+}
+
+// AdvanceAuditHead делает e последней записью журнала.
+func AdvanceAuditHead(ctx context.Context, tx LotTx, rebind func(string) string, e *AuditEntry) error {
+	_, err := tx.ExecContext(ctx, rebind("UPDATE audit_head SET last_id = ?, hash = ? WHERE id = 1;"), e.ID, e.Hash)
+	return err
+}
+
+// LoadAuditHead возвращает последнюю запись журнала; у пустого журнала LastID равен 0, а Hash пуст.
+func LoadAuditHead(ctx context.Context, q LotTx) (*AuditHead, error) {
+	var head AuditHead
+	if err := q.QueryRowContext(ctx, AuditHeadQuery+";").Scan(&head.LastID, &head.Hash); err != nil {
+		return nil, err
+	}
+	return &head, nil
+}
+
+// LoadAuditEntries возвращает записи журнала, подходящие под filter, в порядке добавления.
+func LoadAuditEntries(ctx context.Context, q LotTx, rebind func(string) string, filter AuditFilter) ([]AuditEntry, error) {
+	conds := []string{"id > ?"}
+	args := []any{filter.AfterID}
+	for _, c := range []struct {
+		column, value string
+	}{{"actor", filter.Actor}, {"action", filter.Action}, {"target", filter.Target}} {
+		if c.value != "" {
+			conds = append(conds, c.column+" = ?")
+			args = append(args, c.value)
+		}
+	}
+	if filter.From != nil {
+		conds = append(conds, "created_at >= ?")
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		conds = append(conds, "created_at < ?")
+		args = append(args, *filter.To)
+	}
+	args = append(args, filter.Limit)
+
+	rows, err := q.QueryContext(ctx, rebind(`
+		SELECT id, actor, action, target, before_value, after_value, request_id, ip, created_at, prev_hash, hash
+		FROM audit_log
+		WHERE `+strings.Join(conds, " AND ")+`
+		ORDER BY id
+		LIMIT ?;`), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []AuditEntry
+	for rows.Next() {
+		var (
+			e                        AuditEntry
+			before, after, reqID, ip sql.NullString
+		)
+		if err = rows.Scan(&e.ID, &e.Actor, &e.Action, &e.Target, &before, &after, &reqID, &ip, &e.CreatedAt, &e.PrevHash, &e.Hash); err != nil {
+			return nil, err
+		}
+		if before.Valid {
+			e.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			e.After = json.RawMessage(after.String)
+		}
+		e.RequestID, e.IP, e.CreatedAt = reqID.String, ip.String, e.CreatedAt.UTC()
+		res = append(res, e)
+	}
+	return res, rows.Err()
+}
+
+func nullJSON(v json.RawMessage) sql.NullString {
+	return sql.NullString{String: string(v), Valid: v != nil}
+}
@@ -3,5 +3,44 @@ package storage
 import "errors"
 
 var (
-	ErrUserNotFound = errors.New("User not found")
+	ErrUserNotFound      = errors.New("User not found")
+	ErrInsufficientFunds = errors.New("Insufficient funds")
+	ErrLimitExceeded     = errors.New("Transfer limit exceeded")
+	// ErrNotSystemAccount — имя служебного счёта уже занято обычным пользователем.
+	ErrNotSystemAccount         = errors.New("Account exists and is not a system account")
+	ErrPaymentRequestNotFound   = errors.New("Payment request not found")
+	ErrPaymentRequestNotPending = errors.New("Payment request is not pending")
+
+	ErrScheduledTransferNotFound  = errors.New("Scheduled transfer not found")
+	ErrScheduledTransferNotActive = errors.New("Scheduled transfer is not active")
+	// ErrScheduledTransferClaimed — запуск уже выполнила другая реплика или расписание отменено.
+	ErrScheduledTransferClaimed = errors.New("Scheduled transfer run already claimed")
+
+	ErrPromoCodeNotFound = errors.New("Promo code not found")
+	ErrPromoCodeExists   = errors.New("Promo code already exists")
+	// ErrPromoCodeUnavailable — промокод отключён, вне срока действия или исчерпан.
+	ErrPromoCodeUnavailable = errors.New("Promo code is not available")
+	ErrPromoCodeUserLimit   = errors.New("Promo code usage limit per user reached")
+
+	ErrItemPriceNotFound = errors.New("Item price not found")
+
+	ErrUnknownLeaderboard = errors.New("Unknown leaderboard")
+
+	// ErrGroupExists — имя группы уже занято группой или пользователем.
+	ErrGroupExists                  = errors.New("Group already exists")
+	ErrGroupNotFound                = errors.New("Group not found")
+	ErrNotGroupMember               = errors.New("User is not a group member")
+	ErrGroupPurchaseNotFound        = errors.New("Group purchase not found")
+	ErrGroupPurchaseNotPending      = errors.New("Group purchase is not pending")
+	ErrGroupPurchaseAlreadyApproved = errors.New("Group purchase already approved by user")
+
+	ErrPendingTransferNotFound = errors.New("Pending transfer not found")
+	// ErrPendingTransferNotPending — перевод уже одобрен, отклонён или истёк.
+	ErrPendingTransferNotPending = errors.New("Pending transfer is not pending")
+
+	ErrTransactionNotFound = errors.New("Transaction not found")
+	// ErrTransactionNotReversible — запись не перевод между пользователями: начисление, отмена, комиссия,
+	// сгорание или движение служебного счёта либо кошелька группы.
+	ErrTransactionNotReversible   = errors.New("Transaction is not reversible")
+	ErrTransactionAlreadyReversed = errors.New("Transaction already reversed")
 )
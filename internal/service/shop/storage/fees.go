@@ -0,0 +1,16 @@
+package storage
+
+// SystemRole — роль служебного счёта, на который зачисляются комиссии. Войти под ним нельзя:
+// у счёта нет пароля.
+const SystemRole = "system"
+
+// FeeCategory — категория записи о комиссии в истории переводов. Пользователь не может
+// выбрать её для своего перевода, а в суточные лимиты комиссии не входят.
+const FeeCategory = "fee"
+
+// TransferFee — комиссия за перевод: Amount списывается с отправителя сверх суммы перевода
+// и зачисляется на счёт AccountID отдельной записью с категорией FeeCategory.
+type TransferFee struct {
+	AccountID int
+	Amount    int
+}
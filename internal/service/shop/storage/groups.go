@@ -0,0 +1,362 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// GroupRole — роль счёта общего кошелька группы. Кошелёк — обычная строка users без пароля,
+// поэтому переводы, партии монет, инвентарь и история работают для него так же, как для
+// пользователя, а войти под ним нельзя.
+const GroupRole = "group"
+
+// Роли участников группы: администратор управляет участниками и переводит монеты из кошелька,
+// участник видит кошелёк и историю, предлагает и одобряет покупки.
+const (
+	GroupRoleAdmin  = "admin"
+	GroupRoleMember = "member"
+)
+
+// Статусы покупки группы.
+const (
+	GroupPurchasePending   = "pending"
+	GroupPurchaseCompleted = "completed"
+	GroupPurchaseCancelled = "cancelled"
+)
+
+// Group — общий кошелёк Name; покупка из него выполняется после ApprovalsRequired одобрений участников.
+// Role — роль пользователя, для которого выбран список его групп.
+type Group struct {
+	ID                int       `json:"-"`
+	Name              string    `json:"name"`
+	ApprovalsRequired int       `json:"approvalsRequired"`
+	Role              string    `json:"role,omitempty"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+// GroupMember — участник группы.
+type GroupMember struct {
+	Username string    `json:"username"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+// GroupInfo — кошелёк группы с балансом, купленными предметами и участниками.
+type GroupInfo struct {
+	Group
+	Coins     int           `json:"coins"`
+	Inventory []Inventory   `json:"inventory"`
+	Members   []GroupMember `json:"members"`
+}
+
+// GroupPurchase — предложение ProposedBy купить Item из кошелька группы. Покупка выполняется,
+// когда её одобрят ApprovalsRequired участников; Price — списанная сумма выполненной покупки.
+type GroupPurchase struct {
+	ID                int        `json:"id"`
+	GroupID           int        `json:"-"`
+	Group             string     `json:"group"`
+	Item              string     `json:"item"`
+	ProposedBy        string     `json:"proposedBy"`
+	Status            string     `json:"status"`
+	ApprovalsRequired int        `json:"approvalsRequired"`
+	Approvals         []string   `json:"approvals"`
+	Price             int        `json:"price,omitempty"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	ResolvedAt        *time.Time `json:"resolvedAt,omitempty"`
+}
+
+// GroupSelect — общая часть выборки групп; строки разбирает ScanGroups.
+const GroupSelect = `
+	SELECT u.id, u.username, g.approvals_required, g.created_at
+	FROM group_wallets g JOIN users u ON u.id = g.user_id`
+
+// UserGroupsQuery выбирает группы пользователя с его ролью в порядке названия. Параметр — id пользователя.
+const UserGroupsQuery = `
+	SELECT u.id, u.username, g.approvals_required, g.created_at, m.role
+	FROM group_members m
+	JOIN group_wallets g ON g.user_id = m.group_id
+	JOIN users u ON u.id = g.user_id
+	WHERE m.user_id = ?
+	ORDER BY u.username;`
+
+// ScanGroup читает одну строку GroupSelect.
+func ScanGroup(row rowScanner) (Group, error) {
+	var g Group
+	if err := row.Scan(&g.ID, &g.Name, &g.ApprovalsRequired, &g.CreatedAt); err != nil {
+		return Group{}, err
+	}
+	g.CreatedAt = g.CreatedAt.UTC()
+	return g, nil
+}
+
+// ScanUserGroups читает все строки UserGroupsQuery.
+func ScanUserGroups(rows *sql.Rows) ([]Group, error) {
+	defer rows.Close()
+
+	var res []Group
+	for rows.Next() {
+		var g Group
+		if err := rows.Scan(&g.ID, &g.Name, &g.ApprovalsRequired, &g.CreatedAt, &g.Role); err != nil {
+			return nil, err
+		}
+		g.CreatedAt = g.CreatedAt.UTC()
+		res = append(res, g)
+	}
+	return res, rows.Err()
+}
+
+// GroupMembersQuery выбирает участников группы в порядке вступления. Параметр — id группы.
+const GroupMembersQuery = `
+	SELECT u.username, m.role, m.created_at
+	FROM group_members m JOIN users u ON u.id = m.user_id
+	WHERE m.group_id = ?
+	ORDER BY m.created_at, u.username;`
+
+// ScanGroupMembers читает все строки GroupMembersQuery.
+func ScanGroupMembers(rows *sql.Rows) ([]GroupMember, error) {
+	defer rows.Close()
+
+	var res []GroupMember
+	for rows.Next() {
+		var m GroupMember
+		if err := rows.Scan(&m.Username, &m.Role, &m.JoinedAt); err != nil {
+			return nil, err
+		}
+		m.JoinedAt = m.JoinedAt.UTC()
+		res = append(res, m)
+	}
+	return res, rows.Err()
+}
+
+// CreateGroupTx создаёт кошелёк g.Name с нулевым балансом и делает ownerID его администратором;
+// заполняет g.ID. Если имя занято пользователем или группой, возвращает ErrGroupExists.
+func CreateGroupTx(ctx context.Context, tx LotTx, rebind func(string) string, g *Group, ownerID int) error {
+	var n int
+	if err := tx.QueryRowContext(ctx, rebind("SELECT COUNT(*) FROM users WHERE username = ?;"), g.Name).Scan(&n); err != nil {
+		return err
+	}
+	if n > 0 {
+		return ErrGroupExists
+	}
+	// Пустой хэш не совпадает ни с одним паролем, поэтому войти под кошельком нельзя.
+	if _, err := tx.ExecContext(ctx, rebind("INSERT INTO users (username, password_hash, coins, role) VALUES (?, '', 0, ?);"),
+		g.Name, GroupRole); err != nil {
+		return err
+	}
+	if err := tx.QueryRowContext(ctx, rebind("SELECT id FROM users WHERE username = ?;"), g.Name).Scan(&g.ID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, rebind("INSERT INTO group_wallets (user_id, approvals_required, created_at) VALUES (?, ?, ?);"),
+		g.ID, g.ApprovalsRequired, g.CreatedAt); err != nil {
+		return err
+	}
+	return SetGroupMemberTx(ctx, tx, rebind, g.ID, ownerID, GroupRoleAdmin, g.CreatedAt)
+}
+
+// GroupMemberRole возвращает роль userID в группе groupID или ErrNotGroupMember.
+func GroupMemberRole(ctx context.Context, q RowQuerier, rebind func(string) string, groupID, userID int) (string, error) {
+	var role string
+	err := q.QueryRowContext(ctx, rebind("SELECT role FROM group_members WHERE group_id = ? AND user_id = ?;"), groupID, userID).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNotGroupMember
+	}
+	return role, err
+}
+
+// SetGroupMemberTx добавляет userID в группу с ролью role или меняет роль участника.
+func SetGroupMemberTx(ctx context.Context, tx LotTx, rebind func(string) string, groupID, userID int, role string, at time.Time) error {
+	_, err := GroupMemberRole(ctx, tx, rebind, groupID, userID)
+	switch {
+	case errors.Is(err, ErrNotGroupMember):
+		_, err = tx.ExecContext(ctx, rebind("INSERT INTO group_members (group_id, user_id, role, created_at) VALUES (?, ?, ?, ?);"),
+			groupID, userID, role, at)
+	case err == nil:
+		_, err = tx.ExecContext(ctx, rebind("UPDATE group_members SET role = ? WHERE group_id = ? AND user_id = ?;"),
+			role, groupID, userID)
+	}
+	return err
+}
+
+// InsertGroupPurchaseQuery добавляет предложение покупки; параметры — GroupPurchaseArgs.
+const InsertGroupPurchaseQuery = `INSERT INTO group_purchases (group_id, item_name, proposed_by, status, created_at) VALUES (?, ?, ?, ?, ?)`
+
+// GroupPurchaseArgs возвращает аргументы InsertGroupPurchaseQuery для предложения proposerID.
+func GroupPurchaseArgs(p *GroupPurchase, proposerID int) []any {
+	return []any{p.GroupID, p.Item, proposerID, GroupPurchasePending, p.CreatedAt}
+}
+
+// groupPurchaseSelect — общая часть выборки покупок группы (см. LoadGroupPurchases).
+const groupPurchaseSelect = `
+	SELECT p.id, p.group_id, g.username, p.item_name, u.username, p.status, w.approvals_required,
+	       p.price, p.created_at, p.resolved_at
+	FROM group_purchases p
+	JOIN users g ON g.id = p.group_id
+	JOIN users u ON u.id = p.proposed_by
+	JOIN group_wallets w ON w.user_id = p.group_id`
+
+// LoadGroupPurchases выбирает покупки по условию where (без WHERE) в порядке создания вместе с одобрениями.
+func LoadGroupPurchases(ctx context.Context, q LotTx, rebind func(string) string, where string, args ...any) ([]GroupPurchase, error) {
+	rows, err := q.QueryContext(ctx, rebind(groupPurchaseSelect+" WHERE "+where+" ORDER BY p.id;"), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		res   []GroupPurchase
+		index = map[int]int{}
+	)
+	for rows.Next() {
+		var (
+			p          GroupPurchase
+			price      sql.NullInt64
+			resolvedAt sql.NullTime
+		)
+		if err = rows.Scan(&p.ID, &p.GroupID, &p.Group, &p.Item, &p.ProposedBy, &p.Status, &p.ApprovalsRequired,
+			&price, &p.CreatedAt, &resolvedAt); err != nil {
+			return nil, err
+		}
+		p.Price, p.CreatedAt, p.ResolvedAt = int(price.Int64), p.CreatedAt.UTC(), nullTimePtr(resolvedAt)
+		p.Approvals = []string{}
+		index[p.ID] = len(res)
+		res = append(res, p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	if len(res) == 0 {
+		return res, nil
+	}
+
+	approvals, err := q.QueryContext(ctx, rebind(`
+	SELECT a.purchase_id, u.username
+	FROM group_purchase_approvals a
+	JOIN users u ON u.id = a.user_id
+	JOIN group_purchases p ON p.id = a.purchase_id
+	WHERE `+where+`
+	ORDER BY a.created_at, u.username;`), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer approvals.Close()
+	for approvals.Next() {
+		var (
+			id       int
+			username string
+		)
+		if err = approvals.Scan(&id, &username); err != nil {
+			return nil, err
+		}
+		if i, ok := index[id]; ok {
+			res[i].Approvals = append(res[i].Approvals, username)
+		}
+	}
+	return res, approvals.Err()
+}
+
+// LoadGroupPurchase возвращает покупку по id или ErrGroupPurchaseNotFound.
+func LoadGroupPurchase(ctx context.Context, q LotTx, rebind func(string) string, id int) (*GroupPurchase, error) {
+	res, err := LoadGroupPurchases(ctx, q, rebind, "p.id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, ErrGroupPurchaseNotFound
+	}
+	return &res[0], nil
+}
+
+// ApproveGroupPurchaseTx записывает одобрение покупки id участником userID. Одобрение, на котором
+// набирается нужное число, в той же транзакции списывает price монет с кошелька, добавляет предмет
+// в инвентарь группы, записывает заказ и закрывает покупку; если монет не хватает, возвращается
+// ErrInsufficientFunds и одобрение не записывается.
+func ApproveGroupPurchaseTx(ctx context.Context, tx LotTx, rebind func(string) string, id, userID, price, priceID int, at time.Time) (*GroupPurchase, error) {
+	// Счётчик одобрений обновляется первым: блокировка строки покупки упорядочивает одновременные
+	// одобрения, поэтому последнее из нужных всегда видит все предыдущие.
+	res, err := tx.ExecContext(ctx, rebind("UPDATE group_purchases SET approvals = approvals + 1 WHERE id = ? AND status = ?;"),
+		id, GroupPurchasePending)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		if _, err = LoadGroupPurchase(ctx, tx, rebind, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrGroupPurchaseNotPending
+	}
+
+	var n int
+	if err = tx.QueryRowContext(ctx, rebind("SELECT COUNT(*) FROM group_purchase_approvals WHERE purchase_id = ? AND user_id = ?;"),
+		id, userID).Scan(&n); err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		return nil, ErrGroupPurchaseAlreadyApproved
+	}
+	if _, err = tx.ExecContext(ctx, rebind("INSERT INTO group_purchase_approvals (purchase_id, user_id, created_at) VALUES (?, ?, ?);"),
+		id, userID, at); err != nil {
+		return nil, err
+	}
+
+	var groupID, approvals, required int
+	var item string
+	if err = tx.QueryRowContext(ctx, rebind(`
+	SELECT p.group_id, p.item_name, p.approvals, w.approvals_required
+	FROM group_purchases p JOIN group_wallets w ON w.user_id = p.group_id
+	WHERE p.id = ?;`), id).Scan(&groupID, &item, &approvals, &required); err != nil {
+		return nil, err
+	}
+	if approvals >= required {
+		if err = buyForGroup(ctx, tx, rebind, groupID, item, price, priceID, at); err != nil {
+			return nil, err
+		}
+		if _, err = tx.ExecContext(ctx, rebind("UPDATE group_purchases SET status = ?, price = ?, resolved_at = ? WHERE id = ?;"),
+			GroupPurchaseCompleted, price, at, id); err != nil {
+			return nil, err
+		}
+	}
+	return LoadGroupPurchase(ctx, tx, rebind, id)
+}
+
+// buyForGroup списывает price монет с кошелька группы, добавляет item в её инвентарь и записывает заказ
+// с ценой из записи priceID.
+func buyForGroup(ctx context.Context, tx LotTx, rebind func(string) string, groupID int, item string, price, priceID int, at time.Time) error {
+	res, err := tx.ExecContext(ctx, rebind("UPDATE users SET coins = coins - ? WHERE id = ? AND coins >= ?;"), price, groupID, price)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrInsufficientFunds
+	}
+	// Строка кошелька уже заблокирована списанием, поэтому обновление и вставка не гонятся между собой.
+	res, err = tx.ExecContext(ctx, rebind("UPDATE inventory SET quantity = quantity + 1 WHERE user_id = ? AND item_name = ?;"), groupID, item)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		if _, err = tx.ExecContext(ctx, rebind("INSERT INTO inventory (user_id, item_name, quantity) VALUES (?, ?, 1);"), groupID, item); err != nil {
+			return err
+		}
+	}
+	if err = MoveLots(ctx, tx, rebind, groupID, 0, price); err != nil {
+		return err
+	}
+	return InsertOrder(ctx, tx, rebind, groupID, item, price, priceID, price, at)
+}
+
+// CancelGroupPurchase отменяет покупку id, если она ещё ожидает одобрений, иначе возвращает
+// ErrGroupPurchaseNotPending.
+func CancelGroupPurchase(ctx context.Context, q LotTx, rebind func(string) string, id int, at time.Time) error {
+	res, err := q.ExecContext(ctx, rebind("UPDATE group_purchases SET status = ?, resolved_at = ? WHERE id = ? AND status = ?;"),
+		GroupPurchaseCancelled, at, id, GroupPurchasePending)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrGroupPurchaseNotPending
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"database/sql"
+	"strconv"
+)
+
+// Виды строк в результатах FullInfoQuery и CoinHistoryQuery.
+const (
+	infoRowBalance = iota
+	infoRowInventory
+	infoRowSent
+	infoRowReceived
+)
+
+// FullInfoQuery читает баланс, инвентарь и обе истории пользователя одним запросом.
+// Один оператор во всех поддерживаемых БД видит единый снимок данных, поэтому баланс
+// и история согласованы без явной транзакции и лишних обращений к серверу.
+// Единственный параметр — имя пользователя. Результат разбирает ScanFullInfo.
+//
+// PostgreSQL выводит типы UNION попарно слева направо, поэтому ветки с историей идут первыми:
+// иначе other_user_id из двух NULL стал бы text и не сошёлся с integer.
+const FullInfoQuery = `
+	WITH u AS (SELECT id, coins FROM users WHERE username = ?)
+	SELECT 2 AS kind, u.id, NULL AS item_name, t.to_user_id AS other_user_id, t.amount, t.memo, t.category
+	FROM u JOIN transactions t ON t.from_user_id = u.id
+	UNION ALL
+	SELECT 3, u.id, NULL, t.from_user_id, t.amount, t.memo, t.category
+	FROM u JOIN transactions t ON t.to_user_id = u.id
+	UNION ALL
+	SELECT 1, u.id, i.item_name, NULL, i.quantity, NULL, NULL FROM u JOIN inventory i ON i.user_id = u.id
+	UNION ALL
+	SELECT 0, u.id, NULL, NULL, u.coins, NULL, NULL FROM u;`
+
+// ScanFullInfo заполняет ir строками FullInfoQuery и возвращает id пользователя.
+// Если строки с балансом нет, пользователь не существует — возвращается ErrUserNotFound.
+func ScanFullInfo(rows *sql.Rows, ir *InfoResponse) (int, error) {
+	defer rows.Close()
+
+	id, found := 0, false
+	for rows.Next() {
+		var (
+			kind, amount         int
+			item, memo, category sql.NullString
+			otherUserID          sql.NullInt64
+		)
+		if err := rows.Scan(&kind, &id, &item, &otherUserID, &amount, &memo, &category); err != nil {
+			return 0, err
+		}
+
+		switch kind {
+		case infoRowBalance:
+			found = true
+			ir.Coins = amount
+		case infoRowInventory:
+			ir.Inventory = append(ir.Inventory, Inventory{Type: item.String, Quantity: amount})
+		default:
+			appendTransaction(ir, kind, otherUserID, amount, memo, category)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, ErrUserNotFound
+	}
+	return id, nil
+}
+
+// CoinHistoryQuery строит запрос истории переводов пользователя id с учётом filter
+// в синтаксисе с параметрами "?". Результат разбирает ScanCoinHistory.
+func CoinHistoryQuery(id int, filter HistoryFilter) (string, []any) {
+	var cond string
+	sentArgs, receivedArgs := []any{id}, []any{id}
+	if filter.Category != "" {
+		cond += " AND category = ?"
+		sentArgs = append(sentArgs, filter.Category)
+		receivedArgs = append(receivedArgs, filter.Category)
+	}
+
+	query := `
+	SELECT 2 AS kind, id, to_user_id AS other_user_id, amount, memo, category
+	FROM transactions WHERE from_user_id = ?` + cond + `
+	UNION ALL
+	SELECT 3, id, from_user_id, amount, memo, category
+	FROM transactions WHERE to_user_id = ?` + cond + `
+	ORDER BY id;`
+	return query, append(sentArgs, receivedArgs...)
+}
+
+// ScanCoinHistory добавляет в ir переводы из строк CoinHistoryQuery.
+func ScanCoinHistory(rows *sql.Rows, ir *InfoResponse) error {
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			kind, id, amount int
+			memo, category   sql.NullString
+			otherUserID      sql.NullInt64
+		)
+		if err := rows.Scan(&kind, &id, &otherUserID, &amount, &memo, &category); err != nil {
+			return err
+		}
+		appendTransaction(ir, kind, otherUserID, amount, memo, category)
+	}
+	return rows.Err()
+}
+
+func appendTransaction(ir *InfoResponse, kind int, otherUserID sql.NullInt64, amount int, memo, category sql.NullString) {
+	var otherUser string
+	if otherUserID.Valid {
+		otherUser = strconv.FormatInt(otherUserID.Int64, 10)
+	}
+	switch kind {
+	case infoRowSent:
+		ir.CoinHistory.Sent = append(ir.CoinHistory.Sent, TransactionOut{
+			ToUser: otherUser, Amount: amount, Memo: memo.String, Category: category.String,
+		})
+	case infoRowReceived:
+		ir.CoinHistory.Received = append(ir.CoinHistory.Received, TransactionIn{
+			FromUser: otherUser, Amount: amount, Memo: memo.String, Category: category.String,
+		})
+	}
+}
+
+// NullString сохраняет пустую строку как NULL, чтобы необязательные поля не хранились пустыми строками.
+func NullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Таблицы лидеров.
+const (
+	// BoardSenders — кто больше всех отправил монет другим пользователям.
+	BoardSenders = "senders"
+	// BoardReceivers — кто больше всех получил монет от других пользователей.
+	BoardReceivers = "receivers"
+	// BoardCollectors — кто купил больше всех предметов.
+	BoardCollectors = "collectors"
+)
+
+// Boards — все таблицы лидеров.
+var Boards = []string{BoardSenders, BoardReceivers, BoardCollectors}
+
+// LeaderboardEntry — место пользователя в таблице лидеров; Value — монеты или число предметов.
+type LeaderboardEntry struct {
+	Rank     int    `json:"rank"`
+	Username string `json:"username"`
+	Value    int    `json:"value"`
+}
+
+// Leaderboard — таблица лидеров Board за период Period, начавшийся в Since (nil — за всё время).
+type Leaderboard struct {
+	Board   string             `json:"board"`
+	Period  string             `json:"period"`
+	Since   *time.Time         `json:"since,omitempty"`
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+// leaderboardUsers отбирает участников таблиц: служебные счета, кошельки групп и отказавшиеся от участия не попадают.
+const leaderboardUsers = `u.role NOT IN ('` + SystemRole + `', '` + GroupRole + `') AND NOT u.leaderboard_opt_out`
+
+// LeaderboardQuery строит запрос первых limit строк таблицы board начиная с since (nil — за всё время)
+// в синтаксисе с параметрами "?". Строки — имя пользователя и значение, по убыванию значения,
+// при равенстве — по имени. Результат разбирает ScanLeaderboard; для неизвестной таблицы возвращает "".
+//
+// Переводы считаются без комиссий и сгораний; переводы, сделанные до появления у них времени,
+// попадают только в таблицу за всё время. Предметы за всё время считаются по инвентарю,
+// за период — по заказам, поэтому покупки до появления заказов учитываются только за всё время.
+func LeaderboardQuery(board string, since *time.Time, limit int) (string, []any) {
+	var (
+		query string
+		args  []any
+	)
+	transfers := func(userColumn string) string {
+		q := `
+	SELECT u.username, SUM(t.amount) AS total
+	FROM transactions t JOIN users u ON u.id = t.` + userColumn + `
+	WHERE ` + leaderboardUsers + `
+	  AND (t.category IS NULL OR t.category NOT IN ('` + FeeCategory + `', '` + ExpiredCategory + `', '` + ReversalCategory + `'))`
+		if since != nil {
+			q += ` AND t.created_at >= ?`
+			args = append(args, *since)
+		}
+		return q
+	}
+
+	switch {
+	case board == BoardSenders:
+		query = transfers("from_user_id")
+	case board == BoardReceivers:
+		query = transfers("to_user_id")
+	case board == BoardCollectors && since == nil:
+		query = `
+	SELECT u.username, SUM(i.quantity) AS total
+	FROM inventory i JOIN users u ON u.id = i.user_id
+	WHERE ` + leaderboardUsers
+	case board == BoardCollectors:
+		query = `
+	SELECT u.username, COUNT(*) AS total
+	FROM orders o JOIN users u ON u.id = o.user_id
+	WHERE ` + leaderboardUsers + ` AND o.created_at >= ?`
+		args = append(args, *since)
+	default:
+		return "", nil
+	}
+
+	query += `
+	GROUP BY u.id, u.username
+	ORDER BY total DESC, u.username
+	LIMIT ?;`
+	return query, append(args, limit)
+}
+
+// ScanLeaderboard читает строки LeaderboardQuery; места проставляет вызывающий.
+func ScanLeaderboard(rows *sql.Rows) ([]LeaderboardEntry, error) {
+	defer rows.Close()
+
+	var res []LeaderboardEntry
+	for rows.Next() {
+		var e LeaderboardEntry
+		if err := rows.Scan(&e.Username, &e.Value); err != nil {
+			return nil, err
+		}
+		res = append(res, e)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DefaultRole — роль пользователя, если другая не назначена.
+const DefaultRole = "user"
+
+// Виды лимитов переводов.
+const (
+	LimitPerTransfer   = "per_transfer"
+	LimitDailySent     = "daily_sent"
+	LimitDailyReceived = "daily_received"
+	LimitApproval      = "approval"
+)
+
+// TransferLimits — лимиты, которые хранилище проверяет внутри транзакции перевода,
+// когда строки обоих участников уже заблокированы. В суточных учитываются переводы с created_at >= Since;
+// нулевой лимит не ограничивает. MaxUnapproved — порог одобрения для переводов, которые нельзя
+// удержать до одобрения (запуски отложенных переводов): такой перевод сверх порога не выполняется.
+type TransferLimits struct {
+	Since          time.Time
+	MaxPerTransfer int
+	MaxUnapproved  int
+	MaxSent        int
+	MaxReceived    int
+}
+
+// Enabled сообщает, задан ли хотя бы один лимит.
+func (l TransferLimits) Enabled() bool {
+	return l.MaxPerTransfer > 0 || l.MaxUnapproved > 0 || l.MaxSent > 0 || l.MaxReceived > 0
+}
+
+// LimitExceededError — перевод превысил лимит Limit: разрешено Max, до перевода использовано Used.
+type LimitExceededError struct {
+	Limit string
+	Max   int
+	Used  int
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("Transfer limit %s exceeded: max %d, used %d", e.Limit, e.Max, e.Used)
+}
+
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// TransferTotalsQuery считает, сколько пользователь отправил и получил начиная с момента,
+// не считая комиссий, сгораний и отмен. Параметры: id, id, since, id, id.
+const TransferTotalsQuery = `
+	SELECT COALESCE(SUM(CASE WHEN from_user_id = ? THEN amount ELSE 0 END), 0),
+	       COALESCE(SUM(CASE WHEN to_user_id = ? THEN amount ELSE 0 END), 0)
+	FROM transactions
+	WHERE created_at >= ? AND (from_user_id = ? OR to_user_id = ?)
+	  AND (category IS NULL OR category NOT IN ('` + FeeCategory + `', '` + ExpiredCategory + `', '` + ReversalCategory + `'));`
+
+// RowQuerier — общее у *sql.DB и *sql.Tx.
+type RowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// TransferTotals выполняет query (TransferTotalsQuery в диалекте хранилища).
+func TransferTotals(ctx context.Context, q RowQuerier, query string, userID int, since time.Time) (sent, received int, err error) {
+	err = q.QueryRowContext(ctx, query, userID, userID, since, userID, userID).Scan(&sent, &received)
+	return sent, received, err
+}
+
+// CheckTransferLimits возвращает *LimitExceededError, если перевод amount от fromID к toID
+// превысит лимит одного перевода, порог одобрения или суточные лимиты.
+func CheckTransferLimits(ctx context.Context, q RowQuerier, query string, fromID, toID, amount int, limits TransferLimits) error {
+	if limits.MaxPerTransfer > 0 && amount > limits.MaxPerTransfer {
+		return &LimitExceededError{Limit: LimitPerTransfer, Max: limits.MaxPerTransfer}
+	}
+	if limits.MaxUnapproved > 0 && amount > limits.MaxUnapproved {
+		return &LimitExceededError{Limit: LimitApproval, Max: limits.MaxUnapproved}
+	}
+	if limits.MaxSent > 0 {
+		sent, _, err := TransferTotals(ctx, q, query, fromID, limits.Since)
+		if err != nil {
+			return err
+		}
+		if sent+amount > limits.MaxSent {
+			return &LimitExceededError{Limit: LimitDailySent, Max: limits.MaxSent, Used: sent}
+		}
+	}
+	if limits.MaxReceived > 0 {
+		_, received, err := TransferTotals(ctx, q, query, toID, limits.Since)
+		if err != nil {
+			return err
+		}
+		if received+amount > limits.MaxReceived {
+			return &LimitExceededError{Limit: LimitDailyReceived, Max: limits.MaxReceived, Used: received}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// ExpiredCategory — категория записи о сгоревших монетах в истории переводов.
+const ExpiredCategory = "expired"
+
+// CoinLot — партия монет, выданных пользователю в GrantedAt. Баланс пользователя равен сумме
+// его партий. Переводы и покупки расходуют партии от старых к новым, а получатель перевода
+// получает партии с теми же датами выдачи, поэтому перевод не продлевает срок жизни монет.
+type CoinLot struct {
+	Amount    int
+	GrantedAt time.Time
+}
+
+// ExpiredCoins — монеты пользователя, выданные раньше срока сгорания.
+type ExpiredCoins struct {
+	UserID   int
+	Username string
+	Amount   int
+}
+
+// CoinExpiration — монеты, которые сгорят в ExpiresAt, если их не потратить.
+type CoinExpiration struct {
+	Amount    int       `json:"amount"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// LotTx — часть *sql.Tx, которая нужна операциям с партиями.
+type LotTx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// GrantInitialLot записывает стартовый баланс только что созданного пользователя партией,
+// выданной в grantedAt. Размер баланса задаёт значение по умолчанию колонки users.coins.
+func GrantInitialLot(ctx context.Context, tx LotTx, rebind func(string) string, username string, grantedAt time.Time) error {
+	_, err := tx.ExecContext(ctx, rebind("INSERT INTO coin_lots (user_id, amount, granted_at) SELECT id, coins, ? FROM users WHERE username = ? AND coins > 0;"),
+		grantedAt, username)
+	return err
+}
+
+// GrantLot зачисляет пользователю amount монет партией, выданной в grantedAt; партии
+// с одинаковой датой выдачи объединяются. rebind переводит запрос в диалект хранилища.
+// Баланс users.coins к этому моменту уже должен включать amount: если пользователь ушёл
+// в минус после отмены перевода с ReversalNegative, зачисление сначала гасит долг, и партии
+// в сумме остаются равны неотрицательной части баланса.
+func GrantLot(ctx context.Context, tx LotTx, rebind func(string) string, userID, amount int, grantedAt time.Time) error {
+	if amount <= 0 {
+		return nil
+	}
+	var uncovered int
+	err := tx.QueryRowContext(ctx, rebind("SELECT coins - (SELECT COALESCE(SUM(amount), 0) FROM coin_lots WHERE user_id = ?) FROM users WHERE id = ?;"),
+		userID, userID).Scan(&uncovered)
+	if err != nil {
+		return err
+	}
+	if amount = min(amount, uncovered); amount <= 0 {
+		return nil
+	}
+	res, err := tx.ExecContext(ctx, rebind("UPDATE coin_lots SET amount = amount + ? WHERE user_id = ? AND granted_at = ?;"),
+		amount, userID, grantedAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return nil
+	}
+	_, err = tx.ExecContext(ctx, rebind("INSERT INTO coin_lots (user_id, amount, granted_at) VALUES (?, ?, ?);"),
+		userID, amount, grantedAt)
+	return err
+}
+
+// MoveLots списывает amount монет с партий fromID от старых к новым и, если toID не 0,
+// зачисляет их toID с теми же датами выдачи. Строка fromID в users к этому моменту должна быть
+// заблокирована транзакцией. Если партий не хватает, возвращает ErrInsufficientFunds.
+func MoveLots(ctx context.Context, tx LotTx, rebind func(string) string, fromID, toID, amount int) error {
+	taken, err := TakeLots(ctx, tx, rebind, fromID, amount)
+	if err != nil || toID == 0 {
+		return err
+	}
+	for _, l := range taken {
+		if err = GrantLot(ctx, tx, rebind, toID, l.Amount, l.GrantedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TakeLots списывает amount монет с партий fromID от старых к новым и возвращает списанные
+// части с датами выдачи. Требования те же, что у MoveLots.
+func TakeLots(ctx context.Context, tx LotTx, rebind func(string) string, fromID, amount int) ([]CoinLot, error) {
+	if amount <= 0 {
+		return nil, nil
+	}
+
+	type part struct {
+		id, take, left int
+		grantedAt      time.Time
+	}
+	rows, err := tx.QueryContext(ctx, rebind("SELECT id, amount, granted_at FROM coin_lots WHERE user_id = ? ORDER BY granted_at, id;"), fromID)
+	if err != nil {
+		return nil, err
+	}
+	var parts []part
+	need := amount
+	for need > 0 && rows.Next() {
+		var p part
+		var lot int
+		if err = rows.Scan(&p.id, &lot, &p.grantedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		p.take = min(need, lot)
+		p.left = lot - p.take
+		need -= p.take
+		parts = append(parts, p)
+	}
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if need > 0 {
+		return nil, ErrInsufficientFunds
+	}
+
+	taken := make([]CoinLot, 0, len(parts))
+	for _, p := range parts {
+		if p.left == 0 {
+			_, err = tx.ExecContext(ctx, rebind("DELETE FROM coin_lots WHERE id = ?;"), p.id)
+		} else {
+			_, err = tx.ExecContext(ctx, rebind("UPDATE coin_lots SET amount = ? WHERE id = ?;"), p.left, p.id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		taken = append(taken, CoinLot{Amount: p.take, GrantedAt: p.grantedAt})
+	}
+	return taken, nil
+}
+
+// BurnExpiredLots сжигает партии userID, выданные не позже cutoff: списывает их с баланса,
+// зачисляет вместе с партиями на служебный счёт sinkID и записывает в историю переводом
+// с категорией ExpiredCategory. Строки обоих счетов к этому моменту должны быть заблокированы
+// транзакцией. Возвращает число сгоревших монет; если сгорать нечему, ничего не меняет.
+func BurnExpiredLots(ctx context.Context, tx LotTx, rebind func(string) string, userID, sinkID int, cutoff, now time.Time) (int, error) {
+	var amount int
+	err := tx.QueryRowContext(ctx, rebind("SELECT COALESCE(SUM(amount), 0) FROM coin_lots WHERE user_id = ? AND granted_at <= ?;"), userID, cutoff).
+		Scan(&amount)
+	if err != nil || amount == 0 {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, rebind("UPDATE users SET coins = CASE WHEN id = ? THEN coins - ? ELSE coins + ? END WHERE id IN (?, ?);"),
+		userID, amount, amount, userID, sinkID)
+	if err != nil {
+		return 0, err
+	}
+	// Сгоревшие партии старше остальных, поэтому MoveLots заберёт ровно их.
+	if err = MoveLots(ctx, tx, rebind, userID, sinkID, amount); err != nil {
+		return 0, err
+	}
+	_, err = tx.ExecContext(ctx, rebind("INSERT INTO transactions(from_user_id, to_user_id, amount, category, created_at) VALUES (?, ?, ?, ?, ?);"),
+		userID, sinkID, amount, ExpiredCategory, now)
+	if err != nil {
+		return 0, err
+	}
+	return amount, nil
+}
+
+// CoinLotsQuery возвращает партии пользователя от старых к новым. Параметр: id.
+const CoinLotsQuery = `SELECT amount, granted_at FROM coin_lots WHERE user_id = ? ORDER BY granted_at;`
+
+// ExpiredCoinsQuery возвращает пользователей с партиями, выданными не позже момента,
+// кроме служебных счетов. Параметры: cutoff, limit.
+const ExpiredCoinsQuery = `
+	SELECT u.id, u.username, SUM(l.amount)
+	FROM coin_lots l
+	JOIN users u ON u.id = l.user_id
+	WHERE l.granted_at <= ? AND u.role <> '` + SystemRole + `'
+	GROUP BY u.id, u.username
+	ORDER BY u.id
+	LIMIT ?;`
+
+// ScanCoinLots читает результат CoinLotsQuery.
+func ScanCoinLots(rows *sql.Rows) ([]CoinLot, error) {
+	defer rows.Close()
+	var lots []CoinLot
+	for rows.Next() {
+		var l CoinLot
+		if err := rows.Scan(&l.Amount, &l.GrantedAt); err != nil {
+			return nil, err
+		}
+		lots = append(lots, l)
+	}
+	return lots, rows.Err()
+}
+
+// ScanExpiredCoins читает результат ExpiredCoinsQuery.
+func ScanExpiredCoins(rows *sql.Rows) ([]ExpiredCoins, error) {
+	defer rows.Close()
+	var res []ExpiredCoins
+	for rows.Next() {
+		var e ExpiredCoins
+		if err := rows.Scan(&e.UserID, &e.Username, &e.Amount); err != nil {
+			return nil, err
+		}
+		res = append(res, e)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,497 @@
+// Package migrations содержит общий для всех SQL-хранилищ набор миграций схемы.
+// Операторы пишутся один раз, а различия диалектов подставляются через шаблон (см. Dialect).
+package migrations
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// LatestVersion — версия схемы после применения всех миграций из All.
+const LatestVersion = 15
+
+// Migration — одна версия схемы. Statements — шаблоны text/template над Dialect.
+type Migration struct {
+	Version    int
+	Statements []string
+}
+
+// All — упорядоченный по версиям список миграций.
+var All = []Migration{
+	{
+		Version: 1,
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS users (
+            id {{.AutoIncrementPK}},
+            username VARCHAR(255) UNIQUE NOT NULL,
+            password_hash VARCHAR(255) NOT NULL,
+            coins INT DEFAULT 1000
+        );`,
+			`CREATE TABLE IF NOT EXISTS transactions (
+            id {{.AutoIncrementPK}},
+            from_user_id INT,
+            to_user_id INT NOT NULL,
+            amount INT NOT NULL,
+            FOREIGN KEY (from_user_id) REFERENCES users(id),
+            FOREIGN KEY (to_user_id) REFERENCES users(id)
+        );`,
+			`CREATE TABLE IF NOT EXISTS inventory (
+            id {{.AutoIncrementPK}},
+            user_id INT NOT NULL,
+            item_name VARCHAR(255) NOT NULL,
+            quantity INT DEFAULT 0,
+            FOREIGN KEY (user_id) REFERENCES users(id),
+            CONSTRAINT unique_user_item UNIQUE (user_id, item_name)
+        );`,
+		},
+	},
+	{
+		Version: 2,
+		Statements: []string{
+			`ALTER TABLE transactions ADD COLUMN memo VARCHAR(255);`,
+			`ALTER TABLE transactions ADD COLUMN category VARCHAR(32);`,
+		},
+	},
+	{
+		Version: 3,
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS payment_requests (
+            id {{.AutoIncrementPK}},
+            requester_id INT NOT NULL,
+            payer_id INT NOT NULL,
+            amount INT NOT NULL,
+            memo VARCHAR(255),
+            status VARCHAR(16) NOT NULL,
+            created_at {{.Timestamp}} NOT NULL,
+            expires_at {{.Timestamp}} NOT NULL,
+            resolved_at {{.Timestamp}},
+            FOREIGN KEY (requester_id) REFERENCES users(id),
+            FOREIGN KEY (payer_id) REFERENCES users(id)
+        );`,
+			`CREATE INDEX idx_payment_requests_payer ON payment_requests (payer_id, status);`,
+			`CREATE INDEX idx_payment_requests_requester ON payment_requests (requester_id, status);`,
+		},
+	},
+	{
+		Version: 4,
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS scheduled_transfers (
+            id {{.AutoIncrementPK}},
+            sender_id INT NOT NULL,
+            recipient_id INT NOT NULL,
+            amount INT NOT NULL,
+            memo VARCHAR(255),
+            category VARCHAR(32),
+            schedule VARCHAR(64),
+            status VARCHAR(16) NOT NULL,
+            next_run_at {{.Timestamp}} NOT NULL,
+            runs INT NOT NULL DEFAULT 0,
+            last_run_at {{.Timestamp}},
+            last_error VARCHAR(255),
+            created_at {{.Timestamp}} NOT NULL,
+            FOREIGN KEY (sender_id) REFERENCES users(id),
+            FOREIGN KEY (recipient_id) REFERENCES users(id)
+        );`,
+			`CREATE INDEX idx_scheduled_transfers_due ON scheduled_transfers (status, next_run_at);`,
+			`CREATE INDEX idx_scheduled_transfers_sender ON scheduled_transfers (sender_id);`,
+		},
+	},
+	{
+		Version: 5,
+		Statements: []string{
+			`ALTER TABLE users ADD COLUMN role VARCHAR(32) NOT NULL DEFAULT 'user';`,
+			// У переводов, сделанных до этой версии, времени нет — в суточные лимиты они не попадают.
+			`ALTER TABLE transactions ADD COLUMN created_at {{.Timestamp}};`,
+			`CREATE INDEX idx_transactions_from_created ON transactions (from_user_id, created_at);`,
+			`CREATE INDEX idx_transactions_to_created ON transactions (to_user_id, created_at);`,
+		},
+	},
+	{
+		Version: 6,
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS coin_lots (
+            id {{.AutoIncrementPK}},
+            user_id INT NOT NULL,
+            amount INT NOT NULL,
+            granted_at {{.Timestamp}} NOT NULL,
+            FOREIGN KEY (user_id) REFERENCES users(id),
+            CONSTRAINT unique_user_granted UNIQUE (user_id, granted_at)
+        );`,
+			// Уже начисленные монеты считаются выданными в момент миграции.
+			`INSERT INTO coin_lots (user_id, amount, granted_at) SELECT id, coins, {{.Now}} FROM users WHERE coins > 0;`,
+		},
+	},
+	{
+		Version: 7,
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS promo_codes (
+            id {{.AutoIncrementPK}},
+            code VARCHAR(32) UNIQUE NOT NULL,
+            kind VARCHAR(16) NOT NULL,
+            discount INT NOT NULL,
+            items VARCHAR(512),
+            max_uses INT NOT NULL DEFAULT 0,
+            max_uses_per_user INT NOT NULL DEFAULT 0,
+            uses INT NOT NULL DEFAULT 0,
+            starts_at {{.Timestamp}},
+            ends_at {{.Timestamp}},
+            disabled_at {{.Timestamp}},
+            created_at {{.Timestamp}} NOT NULL
+        );`,
+			`CREATE TABLE IF NOT EXISTS promo_redemptions (
+            id {{.AutoIncrementPK}},
+            promo_code_id INT NOT NULL,
+            user_id INT NOT NULL,
+            item_name VARCHAR(255) NOT NULL,
+            price INT NOT NULL,
+            discount INT NOT NULL,
+            created_at {{.Timestamp}} NOT NULL,
+            FOREIGN KEY (promo_code_id) REFERENCES promo_codes(id),
+            FOREIGN KEY (user_id) REFERENCES users(id)
+        );`,
+			`CREATE INDEX idx_promo_redemptions_code_user ON promo_redemptions (promo_code_id, user_id);`,
+		},
+	},
+	{
+		Version: 8,
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS item_prices (
+            id {{.AutoIncrementPK}},
+            item_name VARCHAR(255) NOT NULL,
+            price INT NOT NULL,
+            starts_at {{.Timestamp}} NOT NULL,
+            ends_at {{.Timestamp}},
+            created_at {{.Timestamp}} NOT NULL
+        );`,
+			`CREATE INDEX idx_item_prices_ends ON item_prices (ends_at);`,
+			// Покупки, сделанные до этой версии, в заказы не попадают.
+			`CREATE TABLE IF NOT EXISTS orders (
+            id {{.AutoIncrementPK}},
+            user_id INT NOT NULL,
+            item_name VARCHAR(255) NOT NULL,
+            price INT NOT NULL,
+            paid INT NOT NULL,
+            created_at {{.Timestamp}} NOT NULL,
+            FOREIGN KEY (user_id) REFERENCES users(id)
+        );`,
+			`CREATE INDEX idx_orders_user ON orders (user_id);`,
+		},
+	},
+	{
+		Version: 9,
+		Statements: []string{
+			`ALTER TABLE users ADD COLUMN leaderboard_opt_out BOOLEAN NOT NULL DEFAULT FALSE;`,
+			// Таблицы лидеров за период выбирают переводы и заказы по времени у всех пользователей сразу.
+			`CREATE INDEX idx_transactions_created ON transactions (created_at);`,
+			`CREATE INDEX idx_orders_created ON orders (created_at);`,
+		},
+	},
+	{
+		Version: 10,
+		Statements: []string{
+			// Уникальность (user_id, code) делает выдачу достижения идемпотентной.
+			`CREATE TABLE IF NOT EXISTS achievements (
+            id {{.AutoIncrementPK}},
+            user_id INT NOT NULL,
+            code VARCHAR(64) NOT NULL,
+            title VARCHAR(255) NOT NULL,
+            description VARCHAR(255) NOT NULL DEFAULT '',
+            awarded_at {{.Timestamp}} NOT NULL,
+            FOREIGN KEY (user_id) REFERENCES users(id),
+            CONSTRAINT unique_user_achievement UNIQUE (user_id, code)
+        );`,
+		},
+	},
+	{
+		Version: 11,
+		Statements: []string{
+			// Кошелёк группы — строка users с ролью group; имя groups зарезервировано в MySQL.
+			`CREATE TABLE IF NOT EXISTS group_wallets (
+            user_id INT PRIMARY KEY,
+            approvals_required INT NOT NULL,
+            created_at {{.Timestamp}} NOT NULL,
+            FOREIGN KEY (user_id) REFERENCES users(id)
+        );`,
+			`CREATE TABLE IF NOT EXISTS group_members (
+            group_id INT NOT NULL,
+            user_id INT NOT NULL,
+            role VARCHAR(16) NOT NULL,
+            created_at {{.Timestamp}} NOT NULL,
+            PRIMARY KEY (group_id, user_id),
+            FOREIGN KEY (group_id) REFERENCES group_wallets(user_id),
+            FOREIGN KEY (user_id) REFERENCES users(id)
+        );`,
+			`CREATE INDEX idx_group_members_user ON group_members (user_id);`,
+			// approvals дублирует число строк group_purchase_approvals: его обновление блокирует
+			// строку покупки и упорядочивает одновременные одобрения.
+			`CREATE TABLE IF NOT EXISTS group_purchases (
+            id {{.AutoIncrementPK}},
+            group_id INT NOT NULL,
+            item_name VARCHAR(255) NOT NULL,
+            proposed_by INT NOT NULL,
+            status VARCHAR(16) NOT NULL,
+            approvals INT NOT NULL DEFAULT 0,
+            price INT,
+            created_at {{.Timestamp}} NOT NULL,
+            resolved_at {{.Timestamp}},
+            FOREIGN KEY (group_id) REFERENCES group_wallets(user_id),
+            FOREIGN KEY (proposed_by) REFERENCES users(id)
+        );`,
+			`CREATE INDEX idx_group_purchases_group ON group_purchases (group_id, status);`,
+			`CREATE TABLE IF NOT EXISTS group_purchase_approvals (
+            purchase_id INT NOT NULL,
+            user_id INT NOT NULL,
+            created_at {{.Timestamp}} NOT NULL,
+            PRIMARY KEY (purchase_id, user_id),
+            FOREIGN KEY (purchase_id) REFERENCES group_purchases(id),
+            FOREIGN KEY (user_id) REFERENCES users(id)
+        );`,
+		},
+	},
+	{
+		Version: 12,
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS pending_transfers (
+            id {{.AutoIncrementPK}},
+            sender_id INT NOT NULL,
+            recipient_id INT NOT NULL,
+            amount INT NOT NULL,
+            fee INT NOT NULL DEFAULT 0,
+            fee_account_id INT,
+            memo VARCHAR(255),
+            category VARCHAR(32),
+            status VARCHAR(16) NOT NULL,
+            created_at {{.Timestamp}} NOT NULL,
+            expires_at {{.Timestamp}} NOT NULL,
+            resolved_by INT,
+            resolved_at {{.Timestamp}},
+            FOREIGN KEY (sender_id) REFERENCES users(id),
+            FOREIGN KEY (recipient_id) REFERENCES users(id),
+            FOREIGN KEY (fee_account_id) REFERENCES users(id),
+            FOREIGN KEY (resolved_by) REFERENCES users(id)
+        );`,
+			`CREATE INDEX idx_pending_transfers_status ON pending_transfers (status, expires_at);`,
+			`CREATE INDEX idx_pending_transfers_sender ON pending_transfers (sender_id, status);`,
+			// Удержанные партии хранятся отдельно от coin_lots: пока перевод ждёт одобрения,
+			// монеты не принадлежат ни отправителю, ни получателю и не сгорают.
+			`CREATE TABLE IF NOT EXISTS held_coin_lots (
+            id {{.AutoIncrementPK}},
+            transfer_id INT NOT NULL,
+            amount INT NOT NULL,
+            granted_at {{.Timestamp}} NOT NULL,
+            FOREIGN KEY (transfer_id) REFERENCES pending_transfers(id)
+        );`,
+			`CREATE INDEX idx_held_coin_lots_transfer ON held_coin_lots (transfer_id);`,
+		},
+	},
+	{
+		Version: 13,
+		Statements: []string{
+			// Отмена не меняет исходную запись: компенсирующая запись ссылается на неё через reversal_of.
+			`ALTER TABLE transactions ADD COLUMN reversal_of INT;`,
+			`CREATE TABLE IF NOT EXISTS transfer_reversals (
+            id {{.AutoIncrementPK}},
+            transaction_id INT NOT NULL UNIQUE,
+            reversed_by INT NOT NULL,
+            policy VARCHAR(16) NOT NULL,
+            amount INT NOT NULL,
+            reversed INT NOT NULL,
+            recipient_before INT NOT NULL,
+            recipient_after INT NOT NULL,
+            reason VARCHAR(255) NOT NULL,
+            created_at {{.Timestamp}} NOT NULL,
+            FOREIGN KEY (transaction_id) REFERENCES transactions(id),
+            FOREIGN KEY (reversed_by) REFERENCES users(id)
+        );`,
+		},
+	},
+	{
+		Version: 14,
+		Statements: []string{
+			// Журнал только дополняется; hash — SHA-256 записи вместе с prev_hash, см. storage.AuditHash.
+			`CREATE TABLE IF NOT EXISTS audit_log (
+            id {{.AutoIncrementPK}},
+            actor VARCHAR(255) NOT NULL,
+            action VARCHAR(64) NOT NULL,
+            target VARCHAR(255) NOT NULL,
+            before_value TEXT,
+            after_value TEXT,
+            request_id VARCHAR(64),
+            ip VARCHAR(64),
+            created_at {{.Timestamp}} NOT NULL,
+            prev_hash VARCHAR(64) NOT NULL,
+            hash VARCHAR(64) NOT NULL UNIQUE
+        );`,
+			`CREATE INDEX idx_audit_log_actor ON audit_log (actor, id);`,
+			`CREATE INDEX idx_audit_log_target ON audit_log (target, id);`,
+			// Единственная строка с последней записью журнала: её блокировка упорядочивает добавление записей.
+			`CREATE TABLE IF NOT EXISTS audit_head (
+            id INT PRIMARY KEY,
+            last_id INT NOT NULL,
+            hash VARCHAR(64) NOT NULL
+        );`,
+			`INSERT INTO audit_head (id, last_id, hash) VALUES (1, 0, '');`,
+		},
+	},
+	{
+		Version: 15,
+		Statements: []string{
+			// Цена, по которой сделан заказ; без внешнего ключа, потому что отменённая цена удаляется,
+			// а заказ по ней остаётся. NULL — базовая цена или заказ до этой версии.
+			`ALTER TABLE orders ADD COLUMN price_id INT;`,
+		},
+	},
+}
+
+// Dialect описывает различия SQL между поддерживаемыми БД.
+type Dialect struct {
+	Name            string
+	AutoIncrementPK string
+	Timestamp       string
+	// Now — текущее время UTC в том виде, в каком драйвер записывает time.Time.
+	Now string
+	// Lock/Unlock выполняются на одном соединении вокруг миграций, чтобы реплики не применяли их одновременно.
+	Lock   string
+	Unlock string
+	// Placeholder возвращает параметр запроса с номером n (с единицы).
+	Placeholder func(n int) string
+}
+
+var MySQL = Dialect{
+	Name:            "mysql",
+	AutoIncrementPK: "INT AUTO_INCREMENT PRIMARY KEY",
+	Timestamp:       "DATETIME",
+	Now:             "UTC_TIMESTAMP()",
+	Lock:            "SELECT GET_LOCK('avito_shop_migrations', 60);",
+	Unlock:          "SELECT RELEASE_LOCK('avito_shop_migrations');",
+	Placeholder:     func(int) string { return "?" },
+}
+
+var Postgres = Dialect{
+	Name:            "postgres",
+	AutoIncrementPK: "SERIAL PRIMARY KEY",
+	Timestamp:       "TIMESTAMP",
+	Now:             "(NOW() AT TIME ZONE 'UTC')",
+	Lock:            "SELECT pg_advisory_lock(727001);",
+	Unlock:          "SELECT pg_advisory_unlock(727001);",
+	Placeholder:     func(n int) string { return "$" + strconv.Itoa(n) },
+}
+
+var SQLite = Dialect{
+	Name:            "sqlite",
+	AutoIncrementPK: "INTEGER PRIMARY KEY AUTOINCREMENT",
+	Timestamp:       "DATETIME",
+	// modernc.org/sqlite хранит time.Time текстом в формате time.Time.String.
+	Now:         "(strftime('%Y-%m-%d %H:%M:%S', 'now') || ' +0000 UTC')",
+	Placeholder: func(int) string { return "?" },
+}
+
+// Render подставляет особенности диалекта в оператор миграции.
+func (d Dialect) Render(statement string) (string, error) {
+	tmpl, err := template.New("migration").Parse(statement)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, d); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Rebind заменяет параметры "?" на принятые в диалекте.
+func (d Dialect) Rebind(query string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString(d.Placeholder(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Apply применяет к БД все миграции, версия которых больше текущей.
+func Apply(ctx context.Context, db *sql.DB, d Dialect) (err error) {
+	const op = "migrations.Apply"
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("%v: %w", op, err)
+	}
+	defer conn.Close()
+
+	if d.Lock != "" {
+		if _, err = conn.ExecContext(ctx, d.Lock); err != nil {
+			return fmt.Errorf("%v: lock: %w", op, err)
+		}
+		defer func() {
+			if _, unlockErr := conn.ExecContext(context.Background(), d.Unlock); unlockErr != nil && err == nil {
+				err = fmt.Errorf("%v: unlock: %w", op, unlockErr)
+			}
+		}()
+	}
+
+	createVersions, err := d.Render(`CREATE TABLE IF NOT EXISTS schema_migrations (
+            version INT PRIMARY KEY,
+            applied_at {{.Timestamp}} DEFAULT CURRENT_TIMESTAMP
+        );`)
+	if err != nil {
+		return fmt.Errorf("%v: %w", op, err)
+	}
+	if _, err = conn.ExecContext(ctx, createVersions); err != nil {
+		return fmt.Errorf("%v: %w", op, err)
+	}
+
+	current, err := currentVersion(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("%v: %w", op, err)
+	}
+
+	for _, m := range All {
+		if m.Version <= current {
+			continue
+		}
+		for _, statement := range m.Statements {
+			query, err := d.Render(statement)
+			if err != nil {
+				return fmt.Errorf("%v: version %d: %w", op, m.Version, err)
+			}
+			if _, err = conn.ExecContext(ctx, query); err != nil {
+				return fmt.Errorf("%v: version %d: %w", op, m.Version, err)
+			}
+		}
+		_, err = conn.ExecContext(ctx, d.Rebind("INSERT INTO schema_migrations (version) VALUES (?);"), m.Version)
+		if err != nil {
+			return fmt.Errorf("%v: version %d: %w", op, m.Version, err)
+		}
+	}
+
+	return nil
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// CurrentVersion возвращает последнюю применённую версию схемы (0, если миграций не было).
+func CurrentVersion(ctx context.Context, db *sql.DB) (int, error) {
+	return currentVersion(ctx, db)
+}
+
+func currentVersion(ctx context.Context, q queryRower) (int, error) {
+	var version sql.NullInt64
+	err := q.QueryRowContext(ctx, "SELECT MAX(version) FROM schema_migrations;").Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+	return int(version.Int64), nil
+}
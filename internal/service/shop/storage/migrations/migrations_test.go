@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func TestLatestVersion(t *testing.T) {
+	require.Equal(t, LatestVersion, All[len(All)-1].Version)
+	for i, m := range All {
+		require.Equal(t, i+1, m.Version, "migrations must be numbered sequentially")
+	}
+}
+
+func TestRebind(t *testing.T) {
+	query := "INSERT INTO t (a, b) VALUES (?, ?);"
+	require.Equal(t, query, MySQL.Rebind(query))
+	require.Equal(t, "INSERT INTO t (a, b) VALUES ($1, $2);", Postgres.Rebind(query))
+}
+
+func TestRender(t *testing.T) {
+	for _, d := range []Dialect{MySQL, Postgres, SQLite} {
+		for _, m := range All {
+			for _, statement := range m.Statements {
+				query, err := d.Render(statement)
+				require.NoError(t, err)
+				require.NotContains(t, query, "{{")
+			}
+		}
+	}
+}
+
+func TestApply_Idempotent(t *testing.T) {
+	db, err := sql.Open("sqlite", "file:"+filepath.Join(t.TempDir(), "shop.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	require.NoError(t, Apply(ctx, db, SQLite))
+	require.NoError(t, Apply(ctx, db, SQLite))
+
+	version, err := CurrentVersion(ctx, db)
+	require.NoError(t, err)
+	require.Equal(t, LatestVersion, version)
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM schema_migrations;").Scan(&count))
+	require.Equal(t, len(All), count)
+}
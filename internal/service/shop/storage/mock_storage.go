@@ -4,7 +4,9 @@
 package storage
 
 import (
+	"context"
 	"sync"
+	"time"
 )
 
 // Ensure, that IStorageMock does implement IStorage.
@@ -17,30 +19,204 @@ var _ IStorage = &IStorageMock{}
 //
 //		// make and configure a mocked IStorage
 //		mockedIStorage := &IStorageMock{
-//			AddNewUserFunc: func(username string, password string) error {
+//			AcceptPaymentRequestFunc: func(ctx context.Context, id int, now time.Time, limits TransferLimits, fee TransferFee, audit *TxAudit) error {
+//				panic("mock out the AcceptPaymentRequest method")
+//			},
+//			AddNewUserFunc: func(ctx context.Context, username string, password string) error {
 //				panic("mock out the AddNewUser method")
 //			},
-//			BuyItemFunc: func(name string, item string, amount int) error {
+//			AppendAuditFunc: func(ctx context.Context, e *AuditEntry) error {
+//				panic("mock out the AppendAudit method")
+//			},
+//			ApproveGroupPurchaseFunc: func(ctx context.Context, id int, userID int, price int, priceID int, at time.Time, audit *TxAudit) (*GroupPurchase, error) {
+//				panic("mock out the ApproveGroupPurchase method")
+//			},
+//			AwardAchievementFunc: func(ctx context.Context, userID int, a *Achievement) (bool, error) {
+//				panic("mock out the AwardAchievement method")
+//			},
+//			BuyItemFunc: func(ctx context.Context, name string, item string, amount int, priceID int, audit *TxAudit) error {
 //				panic("mock out the BuyItem method")
 //			},
-//			CheckAuthFunc: func(username string) (string, error) {
+//			BuyItemWithPromoFunc: func(ctx context.Context, name string, item string, r *PromoRedemption, audit *TxAudit) error {
+//				panic("mock out the BuyItemWithPromo method")
+//			},
+//			CancelGroupPurchaseFunc: func(ctx context.Context, id int, at time.Time) error {
+//				panic("mock out the CancelGroupPurchase method")
+//			},
+//			CancelScheduledTransferFunc: func(ctx context.Context, id int) error {
+//				panic("mock out the CancelScheduledTransfer method")
+//			},
+//			CheckAuthFunc: func(ctx context.Context, username string) (string, error) {
 //				panic("mock out the CheckAuth method")
 //			},
-//			GetInfoFunc: func(ir *InfoResponse, username string) (int, error) {
+//			CountPromoRedemptionsFunc: func(ctx context.Context, promoCodeID int, userID int) (int, error) {
+//				panic("mock out the CountPromoRedemptions method")
+//			},
+//			CreateGroupFunc: func(ctx context.Context, g *Group, ownerID int) error {
+//				panic("mock out the CreateGroup method")
+//			},
+//			CreateGroupPurchaseFunc: func(ctx context.Context, p *GroupPurchase, proposerID int) error {
+//				panic("mock out the CreateGroupPurchase method")
+//			},
+//			CreateItemPriceFunc: func(ctx context.Context, p *ItemPrice) error {
+//				panic("mock out the CreateItemPrice method")
+//			},
+//			CreatePaymentRequestFunc: func(ctx context.Context, pr *PaymentRequest) error {
+//				panic("mock out the CreatePaymentRequest method")
+//			},
+//			CreatePendingTransferFunc: func(ctx context.Context, pt *PendingTransfer, limits TransferLimits, audit *TxAudit) error {
+//				panic("mock out the CreatePendingTransfer method")
+//			},
+//			CreatePromoCodeFunc: func(ctx context.Context, pc *PromoCode) error {
+//				panic("mock out the CreatePromoCode method")
+//			},
+//			CreateScheduledTransferFunc: func(ctx context.Context, st *ScheduledTransfer) error {
+//				panic("mock out the CreateScheduledTransfer method")
+//			},
+//			DeleteItemPriceFunc: func(ctx context.Context, id int) error {
+//				panic("mock out the DeleteItemPrice method")
+//			},
+//			DisablePromoCodeFunc: func(ctx context.Context, code string, now time.Time) error {
+//				panic("mock out the DisablePromoCode method")
+//			},
+//			EnsureSystemAccountFunc: func(ctx context.Context, username string) (int, error) {
+//				panic("mock out the EnsureSystemAccount method")
+//			},
+//			ExpireCoinsFunc: func(ctx context.Context, userID int, sinkID int, cutoff time.Time, now time.Time, audit *TxAudit) (int, error) {
+//				panic("mock out the ExpireCoins method")
+//			},
+//			GetAchievementProgressFunc: func(ctx context.Context, userID int) (*AchievementProgress, error) {
+//				panic("mock out the GetAchievementProgress method")
+//			},
+//			GetAuditHeadFunc: func(ctx context.Context) (*AuditHead, error) {
+//				panic("mock out the GetAuditHead method")
+//			},
+//			GetCoinHistoryFunc: func(ctx context.Context, ir *InfoResponse, id int, filter HistoryFilter) error {
+//				panic("mock out the GetCoinHistory method")
+//			},
+//			GetCoinLotsFunc: func(ctx context.Context, userID int) ([]CoinLot, error) {
+//				panic("mock out the GetCoinLots method")
+//			},
+//			GetFullInfoFunc: func(ctx context.Context, ir *InfoResponse, username string) (int, error) {
+//				panic("mock out the GetFullInfo method")
+//			},
+//			GetGroupFunc: func(ctx context.Context, name string) (*Group, error) {
+//				panic("mock out the GetGroup method")
+//			},
+//			GetGroupMemberRoleFunc: func(ctx context.Context, groupID int, userID int) (string, error) {
+//				panic("mock out the GetGroupMemberRole method")
+//			},
+//			GetGroupPurchaseFunc: func(ctx context.Context, id int) (*GroupPurchase, error) {
+//				panic("mock out the GetGroupPurchase method")
+//			},
+//			GetInfoFunc: func(ctx context.Context, ir *InfoResponse, username string) (int, error) {
 //				panic("mock out the GetInfo method")
 //			},
-//			GetInventoryFunc: func(ir *InfoResponse, id int) error {
+//			GetInventoryFunc: func(ctx context.Context, ir *InfoResponse, id int) error {
 //				panic("mock out the GetInventory method")
 //			},
-//			GetReceivedHistoryFunc: func(ir *InfoResponse, id int) error {
+//			GetItemPriceFunc: func(ctx context.Context, id int) (*ItemPrice, error) {
+//				panic("mock out the GetItemPrice method")
+//			},
+//			GetLeaderboardFunc: func(ctx context.Context, board string, since *time.Time, limit int) ([]LeaderboardEntry, error) {
+//				panic("mock out the GetLeaderboard method")
+//			},
+//			GetPaymentRequestFunc: func(ctx context.Context, id int) (*PaymentRequest, error) {
+//				panic("mock out the GetPaymentRequest method")
+//			},
+//			GetPendingTransferFunc: func(ctx context.Context, id int) (*PendingTransfer, error) {
+//				panic("mock out the GetPendingTransfer method")
+//			},
+//			GetPromoCodeFunc: func(ctx context.Context, code string) (*PromoCode, error) {
+//				panic("mock out the GetPromoCode method")
+//			},
+//			GetReceivedHistoryFunc: func(ctx context.Context, ir *InfoResponse, id int) error {
 //				panic("mock out the GetReceivedHistory method")
 //			},
-//			GetSendHistoryFunc: func(ir *InfoResponse, id int) error {
+//			GetScheduledTransferFunc: func(ctx context.Context, id int) (*ScheduledTransfer, error) {
+//				panic("mock out the GetScheduledTransfer method")
+//			},
+//			GetSendHistoryFunc: func(ctx context.Context, ir *InfoResponse, id int) error {
 //				panic("mock out the GetSendHistory method")
 //			},
-//			SendCoinsFunc: func(username string, fromUserID int, toUserID int, scr *SendCoinRequest) error {
+//			GetTransferTotalsFunc: func(ctx context.Context, userID int, since time.Time) (int, int, error) {
+//				panic("mock out the GetTransferTotals method")
+//			},
+//			GetUserRoleFunc: func(ctx context.Context, id int) (string, error) {
+//				panic("mock out the GetUserRole method")
+//			},
+//			ListAchievementsFunc: func(ctx context.Context, userID int) ([]Achievement, error) {
+//				panic("mock out the ListAchievements method")
+//			},
+//			ListAuditFunc: func(ctx context.Context, filter AuditFilter) ([]AuditEntry, error) {
+//				panic("mock out the ListAudit method")
+//			},
+//			ListDueScheduledTransfersFunc: func(ctx context.Context, now time.Time, limit int) ([]ScheduledTransfer, error) {
+//				panic("mock out the ListDueScheduledTransfers method")
+//			},
+//			ListExpiredCoinsFunc: func(ctx context.Context, cutoff time.Time, limit int) ([]ExpiredCoins, error) {
+//				panic("mock out the ListExpiredCoins method")
+//			},
+//			ListExpiredPendingTransfersFunc: func(ctx context.Context, now time.Time, limit int) ([]PendingTransfer, error) {
+//				panic("mock out the ListExpiredPendingTransfers method")
+//			},
+//			ListGroupMembersFunc: func(ctx context.Context, groupID int) ([]GroupMember, error) {
+//				panic("mock out the ListGroupMembers method")
+//			},
+//			ListGroupPurchasesFunc: func(ctx context.Context, groupID int) ([]GroupPurchase, error) {
+//				panic("mock out the ListGroupPurchases method")
+//			},
+//			ListItemPricesFunc: func(ctx context.Context, now time.Time) ([]ItemPrice, error) {
+//				panic("mock out the ListItemPrices method")
+//			},
+//			ListOrdersFunc: func(ctx context.Context, userID int) ([]Order, error) {
+//				panic("mock out the ListOrders method")
+//			},
+//			ListPendingPaymentRequestsFunc: func(ctx context.Context, userID int, incoming bool, now time.Time) ([]PaymentRequest, error) {
+//				panic("mock out the ListPendingPaymentRequests method")
+//			},
+//			ListPendingTransfersFunc: func(ctx context.Context, senderID int, now time.Time) ([]PendingTransfer, error) {
+//				panic("mock out the ListPendingTransfers method")
+//			},
+//			ListPromoCodesFunc: func(ctx context.Context) ([]PromoCode, error) {
+//				panic("mock out the ListPromoCodes method")
+//			},
+//			ListScheduledTransfersFunc: func(ctx context.Context, senderID int) ([]ScheduledTransfer, error) {
+//				panic("mock out the ListScheduledTransfers method")
+//			},
+//			ListUserGroupsFunc: func(ctx context.Context, userID int) ([]Group, error) {
+//				panic("mock out the ListUserGroups method")
+//			},
+//			ListUserTransactionsFunc: func(ctx context.Context, userID int, limit int) ([]Transaction, error) {
+//				panic("mock out the ListUserTransactions method")
+//			},
+//			RemoveGroupMemberFunc: func(ctx context.Context, groupID int, userID int) error {
+//				panic("mock out the RemoveGroupMember method")
+//			},
+//			ResolvePaymentRequestFunc: func(ctx context.Context, id int, status string, now time.Time) error {
+//				panic("mock out the ResolvePaymentRequest method")
+//			},
+//			ResolvePendingTransferFunc: func(ctx context.Context, id int, status string, resolverID int, at time.Time, limits TransferLimits, audit *TxAudit) (*PendingTransfer, error) {
+//				panic("mock out the ResolvePendingTransfer method")
+//			},
+//			ReverseTransactionFunc: func(ctx context.Context, rv *TransferReversal, audit *TxAudit) error {
+//				panic("mock out the ReverseTransaction method")
+//			},
+//			RunScheduledTransferFunc: func(ctx context.Context, st *ScheduledTransfer, next *time.Time, now time.Time, limits TransferLimits, fee TransferFee, audit *TxAudit) error {
+//				panic("mock out the RunScheduledTransfer method")
+//			},
+//			SendCoinsFunc: func(ctx context.Context, username string, fromUserID int, toUserID int, scr *SendCoinRequest, limits TransferLimits, fee TransferFee, audit *TxAudit) error {
 //				panic("mock out the SendCoins method")
 //			},
+//			SetGroupMemberFunc: func(ctx context.Context, groupID int, userID int, role string, at time.Time) error {
+//				panic("mock out the SetGroupMember method")
+//			},
+//			SetLeaderboardOptOutFunc: func(ctx context.Context, userID int, optOut bool) error {
+//				panic("mock out the SetLeaderboardOptOut method")
+//			},
+//			SetUserRoleFunc: func(ctx context.Context, username string, role string) error {
+//				panic("mock out the SetUserRole method")
+//			},
 //		}
 //
 //		// use mockedIStorage in code that requires IStorage
@@ -48,55 +224,484 @@ var _ IStorage = &IStorageMock{}
 //
 //	}
 type IStorageMock struct {
+	// AcceptPaymentRequestFunc mocks the AcceptPaymentRequest method.
+	AcceptPaymentRequestFunc func(ctx context.Context, id int, now time.Time, limits TransferLimits, fee TransferFee, audit *TxAudit) error
+
 	// AddNewUserFunc mocks the AddNewUser method.
-	AddNewUserFunc func(username string, password string) error
+	AddNewUserFunc func(ctx context.Context, username string, password string) error
+
+	// AppendAuditFunc mocks the AppendAudit method.
+	AppendAuditFunc func(ctx context.Context, e *AuditEntry) error
+
+	// ApproveGroupPurchaseFunc mocks the ApproveGroupPurchase method.
+	ApproveGroupPurchaseFunc func(ctx context.Context, id int, userID int, price int, priceID int, at time.Time, audit *TxAudit) (*GroupPurchase, error)
+
+	// AwardAchievementFunc mocks the AwardAchievement method.
+	AwardAchievementFunc func(ctx context.Context, userID int, a *Achievement) (bool, error)
 
 	// BuyItemFunc mocks the BuyItem method.
-	BuyItemFunc func(name string, item string, amount int) error
+	BuyItemFunc func(ctx context.Context, name string, item string, amount int, priceID int, audit *TxAudit) error
+
+	// BuyItemWithPromoFunc mocks the BuyItemWithPromo method.
+	BuyItemWithPromoFunc func(ctx context.Context, name string, item string, r *PromoRedemption, audit *TxAudit) error
+
+	// CancelGroupPurchaseFunc mocks the CancelGroupPurchase method.
+	CancelGroupPurchaseFunc func(ctx context.Context, id int, at time.Time) error
+
+	// CancelScheduledTransferFunc mocks the CancelScheduledTransfer method.
+	CancelScheduledTransferFunc func(ctx context.Context, id int) error
 
 	// CheckAuthFunc mocks the CheckAuth method.
-	CheckAuthFunc func(username string) (string, error)
+	CheckAuthFunc func(ctx context.Context, username string) (string, error)
+
+	// CountPromoRedemptionsFunc mocks the CountPromoRedemptions method.
+	CountPromoRedemptionsFunc func(ctx context.Context, promoCodeID int, userID int) (int, error)
+
+	// CreateGroupFunc mocks the CreateGroup method.
+	CreateGroupFunc func(ctx context.Context, g *Group, ownerID int) error
+
+	// CreateGroupPurchaseFunc mocks the CreateGroupPurchase method.
+	CreateGroupPurchaseFunc func(ctx context.Context, p *GroupPurchase, proposerID int) error
+
+	// CreateItemPriceFunc mocks the CreateItemPrice method.
+	CreateItemPriceFunc func(ctx context.Context, p *ItemPrice) error
+
+	// CreatePaymentRequestFunc mocks the CreatePaymentRequest method.
+	CreatePaymentRequestFunc func(ctx context.Context, pr *PaymentRequest) error
+
+	// CreatePendingTransferFunc mocks the CreatePendingTransfer method.
+	CreatePendingTransferFunc func(ctx context.Context, pt *PendingTransfer, limits TransferLimits, audit *TxAudit) error
+
+	// CreatePromoCodeFunc mocks the CreatePromoCode method.
+	CreatePromoCodeFunc func(ctx context.Context, pc *PromoCode) error
+
+	// CreateScheduledTransferFunc mocks the CreateScheduledTransfer method.
+	CreateScheduledTransferFunc func(ctx context.Context, st *ScheduledTransfer) error
+
+	// DeleteItemPriceFunc mocks the DeleteItemPrice method.
+	DeleteItemPriceFunc func(ctx context.Context, id int) error
+
+	// DisablePromoCodeFunc mocks the DisablePromoCode method.
+	DisablePromoCodeFunc func(ctx context.Context, code string, now time.Time) error
+
+	// EnsureSystemAccountFunc mocks the EnsureSystemAccount method.
+	EnsureSystemAccountFunc func(ctx context.Context, username string) (int, error)
+
+	// ExpireCoinsFunc mocks the ExpireCoins method.
+	ExpireCoinsFunc func(ctx context.Context, userID int, sinkID int, cutoff time.Time, now time.Time, audit *TxAudit) (int, error)
+
+	// GetAchievementProgressFunc mocks the GetAchievementProgress method.
+	GetAchievementProgressFunc func(ctx context.Context, userID int) (*AchievementProgress, error)
+
+	// GetAuditHeadFunc mocks the GetAuditHead method.
+	GetAuditHeadFunc func(ctx context.Context) (*AuditHead, error)
+
+	// GetCoinHistoryFunc mocks the GetCoinHistory method.
+	GetCoinHistoryFunc func(ctx context.Context, ir *InfoResponse, id int, filter HistoryFilter) error
+
+	// GetCoinLotsFunc mocks the GetCoinLots method.
+	GetCoinLotsFunc func(ctx context.Context, userID int) ([]CoinLot, error)
+
+	// GetFullInfoFunc mocks the GetFullInfo method.
+	GetFullInfoFunc func(ctx context.Context, ir *InfoResponse, username string) (int, error)
+
+	// GetGroupFunc mocks the GetGroup method.
+	GetGroupFunc func(ctx context.Context, name string) (*Group, error)
+
+	// GetGroupMemberRoleFunc mocks the GetGroupMemberRole method.
+	GetGroupMemberRoleFunc func(ctx context.Context, groupID int, userID int) (string, error)
+
+	// GetGroupPurchaseFunc mocks the GetGroupPurchase method.
+	GetGroupPurchaseFunc func(ctx context.Context, id int) (*GroupPurchase, error)
 
 	// GetInfoFunc mocks the GetInfo method.
-	GetInfoFunc func(ir *InfoResponse, username string) (int, error)
+	GetInfoFunc func(ctx context.Context, ir *InfoResponse, username string) (int, error)
 
 	// GetInventoryFunc mocks the GetInventory method.
-	GetInventoryFunc func(ir *InfoResponse, id int) error
+	GetInventoryFunc func(ctx context.Context, ir *InfoResponse, id int) error
+
+	// GetItemPriceFunc mocks the GetItemPrice method.
+	GetItemPriceFunc func(ctx context.Context, id int) (*ItemPrice, error)
+
+	// GetLeaderboardFunc mocks the GetLeaderboard method.
+	GetLeaderboardFunc func(ctx context.Context, board string, since *time.Time, limit int) ([]LeaderboardEntry, error)
+
+	// GetPaymentRequestFunc mocks the GetPaymentRequest method.
+	GetPaymentRequestFunc func(ctx context.Context, id int) (*PaymentRequest, error)
+
+	// GetPendingTransferFunc mocks the GetPendingTransfer method.
+	GetPendingTransferFunc func(ctx context.Context, id int) (*PendingTransfer, error)
+
+	// GetPromoCodeFunc mocks the GetPromoCode method.
+	GetPromoCodeFunc func(ctx context.Context, code string) (*PromoCode, error)
 
 	// GetReceivedHistoryFunc mocks the GetReceivedHistory method.
-	GetReceivedHistoryFunc func(ir *InfoResponse, id int) error
+	GetReceivedHistoryFunc func(ctx context.Context, ir *InfoResponse, id int) error
+
+	// GetScheduledTransferFunc mocks the GetScheduledTransfer method.
+	GetScheduledTransferFunc func(ctx context.Context, id int) (*ScheduledTransfer, error)
 
 	// GetSendHistoryFunc mocks the GetSendHistory method.
-	GetSendHistoryFunc func(ir *InfoResponse, id int) error
+	GetSendHistoryFunc func(ctx context.Context, ir *InfoResponse, id int) error
+
+	// GetTransferTotalsFunc mocks the GetTransferTotals method.
+	GetTransferTotalsFunc func(ctx context.Context, userID int, since time.Time) (int, int, error)
+
+	// GetUserRoleFunc mocks the GetUserRole method.
+	GetUserRoleFunc func(ctx context.Context, id int) (string, error)
+
+	// ListAchievementsFunc mocks the ListAchievements method.
+	ListAchievementsFunc func(ctx context.Context, userID int) ([]Achievement, error)
+
+	// ListAuditFunc mocks the ListAudit method.
+	ListAuditFunc func(ctx context.Context, filter AuditFilter) ([]AuditEntry, error)
+
+	// ListDueScheduledTransfersFunc mocks the ListDueScheduledTransfers method.
+	ListDueScheduledTransfersFunc func(ctx context.Context, now time.Time, limit int) ([]ScheduledTransfer, error)
+
+	// ListExpiredCoinsFunc mocks the ListExpiredCoins method.
+	ListExpiredCoinsFunc func(ctx context.Context, cutoff time.Time, limit int) ([]ExpiredCoins, error)
+
+	// ListExpiredPendingTransfersFunc mocks the ListExpiredPendingTransfers method.
+	ListExpiredPendingTransfersFunc func(ctx context.Context, now time.Time, limit int) ([]PendingTransfer, error)
+
+	// ListGroupMembersFunc mocks the ListGroupMembers method.
+	ListGroupMembersFunc func(ctx context.Context, groupID int) ([]GroupMember, error)
+
+	// ListGroupPurchasesFunc mocks the ListGroupPurchases method.
+	ListGroupPurchasesFunc func(ctx context.Context, groupID int) ([]GroupPurchase, error)
+
+	// ListItemPricesFunc mocks the ListItemPrices method.
+	ListItemPricesFunc func(ctx context.Context, now time.Time) ([]ItemPrice, error)
+
+	// ListOrdersFunc mocks the ListOrders method.
+	ListOrdersFunc func(ctx context.Context, userID int) ([]Order, error)
+
+	// ListPendingPaymentRequestsFunc mocks the ListPendingPaymentRequests method.
+	ListPendingPaymentRequestsFunc func(ctx context.Context, userID int, incoming bool, now time.Time) ([]PaymentRequest, error)
+
+	// ListPendingTransfersFunc mocks the ListPendingTransfers method.
+	ListPendingTransfersFunc func(ctx context.Context, senderID int, now time.Time) ([]PendingTransfer, error)
+
+	// ListPromoCodesFunc mocks the ListPromoCodes method.
+	ListPromoCodesFunc func(ctx context.Context) ([]PromoCode, error)
+
+	// ListScheduledTransfersFunc mocks the ListScheduledTransfers method.
+	ListScheduledTransfersFunc func(ctx context.Context, senderID int) ([]ScheduledTransfer, error)
+
+	// ListUserGroupsFunc mocks the ListUserGroups method.
+	ListUserGroupsFunc func(ctx context.Context, userID int) ([]Group, error)
+
+	// ListUserTransactionsFunc mocks the ListUserTransactions method.
+	ListUserTransactionsFunc func(ctx context.Context, userID int, limit int) ([]Transaction, error)
+
+	// RemoveGroupMemberFunc mocks the RemoveGroupMember method.
+	RemoveGroupMemberFunc func(ctx context.Context, groupID int, userID int) error
+
+	// ResolvePaymentRequestFunc mocks the ResolvePaymentRequest method.
+	ResolvePaymentRequestFunc func(ctx context.Context, id int, status string, now time.Time) error
+
+	// ResolvePendingTransferFunc mocks the ResolvePendingTransfer method.
+	ResolvePendingTransferFunc func(ctx context.Context, id int, status string, resolverID int, at time.Time, limits TransferLimits, audit *TxAudit) (*PendingTransfer, error)
+
+	// ReverseTransactionFunc mocks the ReverseTransaction method.
+	ReverseTransactionFunc func(ctx context.Context, rv *TransferReversal, audit *TxAudit) error
+
+	// RunScheduledTransferFunc mocks the RunScheduledTransfer method.
+	RunScheduledTransferFunc func(ctx context.Context, st *ScheduledTransfer, next *time.Time, now time.Time, limits TransferLimits, fee TransferFee, audit *TxAudit) error
 
 	// SendCoinsFunc mocks the SendCoins method.
-	SendCoinsFunc func(username string, fromUserID int, toUserID int, scr *SendCoinRequest) error
+	SendCoinsFunc func(ctx context.Context, username string, fromUserID int, toUserID int, scr *SendCoinRequest, limits TransferLimits, fee TransferFee, audit *TxAudit) error
+
+	// SetGroupMemberFunc mocks the SetGroupMember method.
+	SetGroupMemberFunc func(ctx context.Context, groupID int, userID int, role string, at time.Time) error
+
+	// SetLeaderboardOptOutFunc mocks the SetLeaderboardOptOut method.
+	SetLeaderboardOptOutFunc func(ctx context.Context, userID int, optOut bool) error
+
+	// SetUserRoleFunc mocks the SetUserRole method.
+	SetUserRoleFunc func(ctx context.Context, username string, role string) error
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// AcceptPaymentRequest holds details about calls to the AcceptPaymentRequest method.
+		AcceptPaymentRequest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+			// Now is the now argument value.
+			Now time.Time
+			// Limits is the limits argument value.
+			Limits TransferLimits
+			// Fee is the fee argument value.
+			Fee TransferFee
+			// Audit is the audit argument value.
+			Audit *TxAudit
+		}
 		// AddNewUser holds details about calls to the AddNewUser method.
 		AddNewUser []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Username is the username argument value.
 			Username string
 			// Password is the password argument value.
 			Password string
 		}
+		// AppendAudit holds details about calls to the AppendAudit method.
+		AppendAudit []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// E is the e argument value.
+			E *AuditEntry
+		}
+		// ApproveGroupPurchase holds details about calls to the ApproveGroupPurchase method.
+		ApproveGroupPurchase []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+			// UserID is the userID argument value.
+			UserID int
+			// Price is the price argument value.
+			Price int
+			// PriceID is the priceID argument value.
+			PriceID int
+			// At is the at argument value.
+			At time.Time
+			// Audit is the audit argument value.
+			Audit *TxAudit
+		}
+		// AwardAchievement holds details about calls to the AwardAchievement method.
+		AwardAchievement []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int
+			// A is the a argument value.
+			A *Achievement
+		}
 		// BuyItem holds details about calls to the BuyItem method.
 		BuyItem []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Name is the name argument value.
 			Name string
 			// Item is the item argument value.
 			Item string
 			// Amount is the amount argument value.
 			Amount int
+			// PriceID is the priceID argument value.
+			PriceID int
+			// Audit is the audit argument value.
+			Audit *TxAudit
+		}
+		// BuyItemWithPromo holds details about calls to the BuyItemWithPromo method.
+		BuyItemWithPromo []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Name is the name argument value.
+			Name string
+			// Item is the item argument value.
+			Item string
+			// R is the r argument value.
+			R *PromoRedemption
+			// Audit is the audit argument value.
+			Audit *TxAudit
+		}
+		// CancelGroupPurchase holds details about calls to the CancelGroupPurchase method.
+		CancelGroupPurchase []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+			// At is the at argument value.
+			At time.Time
+		}
+		// CancelScheduledTransfer holds details about calls to the CancelScheduledTransfer method.
+		CancelScheduledTransfer []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
 		}
 		// CheckAuth holds details about calls to the CheckAuth method.
 		CheckAuth []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+		}
+		// CountPromoRedemptions holds details about calls to the CountPromoRedemptions method.
+		CountPromoRedemptions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// PromoCodeID is the promoCodeID argument value.
+			PromoCodeID int
+			// UserID is the userID argument value.
+			UserID int
+		}
+		// CreateGroup holds details about calls to the CreateGroup method.
+		CreateGroup []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// G is the g argument value.
+			G *Group
+			// OwnerID is the ownerID argument value.
+			OwnerID int
+		}
+		// CreateGroupPurchase holds details about calls to the CreateGroupPurchase method.
+		CreateGroupPurchase []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// P is the p argument value.
+			P *GroupPurchase
+			// ProposerID is the proposerID argument value.
+			ProposerID int
+		}
+		// CreateItemPrice holds details about calls to the CreateItemPrice method.
+		CreateItemPrice []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// P is the p argument value.
+			P *ItemPrice
+		}
+		// CreatePaymentRequest holds details about calls to the CreatePaymentRequest method.
+		CreatePaymentRequest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Pr is the pr argument value.
+			Pr *PaymentRequest
+		}
+		// CreatePendingTransfer holds details about calls to the CreatePendingTransfer method.
+		CreatePendingTransfer []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Pt is the pt argument value.
+			Pt *PendingTransfer
+			// Limits is the limits argument value.
+			Limits TransferLimits
+			// Audit is the audit argument value.
+			Audit *TxAudit
+		}
+		// CreatePromoCode holds details about calls to the CreatePromoCode method.
+		CreatePromoCode []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Pc is the pc argument value.
+			Pc *PromoCode
+		}
+		// CreateScheduledTransfer holds details about calls to the CreateScheduledTransfer method.
+		CreateScheduledTransfer []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// St is the st argument value.
+			St *ScheduledTransfer
+		}
+		// DeleteItemPrice holds details about calls to the DeleteItemPrice method.
+		DeleteItemPrice []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+		}
+		// DisablePromoCode holds details about calls to the DisablePromoCode method.
+		DisablePromoCode []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Code is the code argument value.
+			Code string
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// EnsureSystemAccount holds details about calls to the EnsureSystemAccount method.
+		EnsureSystemAccount []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+		}
+		// ExpireCoins holds details about calls to the ExpireCoins method.
+		ExpireCoins []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int
+			// SinkID is the sinkID argument value.
+			SinkID int
+			// Cutoff is the cutoff argument value.
+			Cutoff time.Time
+			// Now is the now argument value.
+			Now time.Time
+			// Audit is the audit argument value.
+			Audit *TxAudit
+		}
+		// GetAchievementProgress holds details about calls to the GetAchievementProgress method.
+		GetAchievementProgress []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int
+		}
+		// GetAuditHead holds details about calls to the GetAuditHead method.
+		GetAuditHead []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// GetCoinHistory holds details about calls to the GetCoinHistory method.
+		GetCoinHistory []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Ir is the ir argument value.
+			Ir *InfoResponse
+			// ID is the id argument value.
+			ID int
+			// Filter is the filter argument value.
+			Filter HistoryFilter
+		}
+		// GetCoinLots holds details about calls to the GetCoinLots method.
+		GetCoinLots []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int
+		}
+		// GetFullInfo holds details about calls to the GetFullInfo method.
+		GetFullInfo []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Ir is the ir argument value.
+			Ir *InfoResponse
 			// Username is the username argument value.
 			Username string
 		}
+		// GetGroup holds details about calls to the GetGroup method.
+		GetGroup []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Name is the name argument value.
+			Name string
+		}
+		// GetGroupMemberRole holds details about calls to the GetGroupMemberRole method.
+		GetGroupMemberRole []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// GroupID is the groupID argument value.
+			GroupID int
+			// UserID is the userID argument value.
+			UserID int
+		}
+		// GetGroupPurchase holds details about calls to the GetGroupPurchase method.
+		GetGroupPurchase []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+		}
 		// GetInfo holds details about calls to the GetInfo method.
 		GetInfo []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Ir is the ir argument value.
 			Ir *InfoResponse
 			// Username is the username argument value.
@@ -104,27 +709,277 @@ type IStorageMock struct {
 		}
 		// GetInventory holds details about calls to the GetInventory method.
 		GetInventory []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Ir is the ir argument value.
 			Ir *InfoResponse
 			// ID is the id argument value.
 			ID int
 		}
+		// GetItemPrice holds details about calls to the GetItemPrice method.
+		GetItemPrice []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+		}
+		// GetLeaderboard holds details about calls to the GetLeaderboard method.
+		GetLeaderboard []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Board is the board argument value.
+			Board string
+			// Since is the since argument value.
+			Since *time.Time
+			// Limit is the limit argument value.
+			Limit int
+		}
+		// GetPaymentRequest holds details about calls to the GetPaymentRequest method.
+		GetPaymentRequest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+		}
+		// GetPendingTransfer holds details about calls to the GetPendingTransfer method.
+		GetPendingTransfer []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+		}
+		// GetPromoCode holds details about calls to the GetPromoCode method.
+		GetPromoCode []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Code is the code argument value.
+			Code string
+		}
 		// GetReceivedHistory holds details about calls to the GetReceivedHistory method.
 		GetReceivedHistory []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Ir is the ir argument value.
 			Ir *InfoResponse
 			// ID is the id argument value.
 			ID int
 		}
+		// GetScheduledTransfer holds details about calls to the GetScheduledTransfer method.
+		GetScheduledTransfer []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+		}
 		// GetSendHistory holds details about calls to the GetSendHistory method.
 		GetSendHistory []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Ir is the ir argument value.
 			Ir *InfoResponse
 			// ID is the id argument value.
 			ID int
 		}
+		// GetTransferTotals holds details about calls to the GetTransferTotals method.
+		GetTransferTotals []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int
+			// Since is the since argument value.
+			Since time.Time
+		}
+		// GetUserRole holds details about calls to the GetUserRole method.
+		GetUserRole []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+		}
+		// ListAchievements holds details about calls to the ListAchievements method.
+		ListAchievements []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int
+		}
+		// ListAudit holds details about calls to the ListAudit method.
+		ListAudit []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Filter is the filter argument value.
+			Filter AuditFilter
+		}
+		// ListDueScheduledTransfers holds details about calls to the ListDueScheduledTransfers method.
+		ListDueScheduledTransfers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Now is the now argument value.
+			Now time.Time
+			// Limit is the limit argument value.
+			Limit int
+		}
+		// ListExpiredCoins holds details about calls to the ListExpiredCoins method.
+		ListExpiredCoins []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Cutoff is the cutoff argument value.
+			Cutoff time.Time
+			// Limit is the limit argument value.
+			Limit int
+		}
+		// ListExpiredPendingTransfers holds details about calls to the ListExpiredPendingTransfers method.
+		ListExpiredPendingTransfers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Now is the now argument value.
+			Now time.Time
+			// Limit is the limit argument value.
+			Limit int
+		}
+		// ListGroupMembers holds details about calls to the ListGroupMembers method.
+		ListGroupMembers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// GroupID is the groupID argument value.
+			GroupID int
+		}
+		// ListGroupPurchases holds details about calls to the ListGroupPurchases method.
+		ListGroupPurchases []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// GroupID is the groupID argument value.
+			GroupID int
+		}
+		// ListItemPrices holds details about calls to the ListItemPrices method.
+		ListItemPrices []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// ListOrders holds details about calls to the ListOrders method.
+		ListOrders []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int
+		}
+		// ListPendingPaymentRequests holds details about calls to the ListPendingPaymentRequests method.
+		ListPendingPaymentRequests []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int
+			// Incoming is the incoming argument value.
+			Incoming bool
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// ListPendingTransfers holds details about calls to the ListPendingTransfers method.
+		ListPendingTransfers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SenderID is the senderID argument value.
+			SenderID int
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// ListPromoCodes holds details about calls to the ListPromoCodes method.
+		ListPromoCodes []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// ListScheduledTransfers holds details about calls to the ListScheduledTransfers method.
+		ListScheduledTransfers []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// SenderID is the senderID argument value.
+			SenderID int
+		}
+		// ListUserGroups holds details about calls to the ListUserGroups method.
+		ListUserGroups []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int
+		}
+		// ListUserTransactions holds details about calls to the ListUserTransactions method.
+		ListUserTransactions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int
+			// Limit is the limit argument value.
+			Limit int
+		}
+		// RemoveGroupMember holds details about calls to the RemoveGroupMember method.
+		RemoveGroupMember []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// GroupID is the groupID argument value.
+			GroupID int
+			// UserID is the userID argument value.
+			UserID int
+		}
+		// ResolvePaymentRequest holds details about calls to the ResolvePaymentRequest method.
+		ResolvePaymentRequest []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+			// Status is the status argument value.
+			Status string
+			// Now is the now argument value.
+			Now time.Time
+		}
+		// ResolvePendingTransfer holds details about calls to the ResolvePendingTransfer method.
+		ResolvePendingTransfer []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID int
+			// Status is the status argument value.
+			Status string
+			// ResolverID is the resolverID argument value.
+			ResolverID int
+			// At is the at argument value.
+			At time.Time
+			// Limits is the limits argument value.
+			Limits TransferLimits
+			// Audit is the audit argument value.
+			Audit *TxAudit
+		}
+		// ReverseTransaction holds details about calls to the ReverseTransaction method.
+		ReverseTransaction []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Rv is the rv argument value.
+			Rv *TransferReversal
+			// Audit is the audit argument value.
+			Audit *TxAudit
+		}
+		// RunScheduledTransfer holds details about calls to the RunScheduledTransfer method.
+		RunScheduledTransfer []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// St is the st argument value.
+			St *ScheduledTransfer
+			// Next is the next argument value.
+			Next *time.Time
+			// Now is the now argument value.
+			Now time.Time
+			// Limits is the limits argument value.
+			Limits TransferLimits
+			// Fee is the fee argument value.
+			Fee TransferFee
+			// Audit is the audit argument value.
+			Audit *TxAudit
+		}
 		// SendCoins holds details about calls to the SendCoins method.
 		SendCoins []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
 			// Username is the username argument value.
 			Username string
 			// FromUserID is the fromUserID argument value.
@@ -133,34 +988,183 @@ type IStorageMock struct {
 			ToUserID int
 			// Scr is the scr argument value.
 			Scr *SendCoinRequest
+			// Limits is the limits argument value.
+			Limits TransferLimits
+			// Fee is the fee argument value.
+			Fee TransferFee
+			// Audit is the audit argument value.
+			Audit *TxAudit
+		}
+		// SetGroupMember holds details about calls to the SetGroupMember method.
+		SetGroupMember []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// GroupID is the groupID argument value.
+			GroupID int
+			// UserID is the userID argument value.
+			UserID int
+			// Role is the role argument value.
+			Role string
+			// At is the at argument value.
+			At time.Time
+		}
+		// SetLeaderboardOptOut holds details about calls to the SetLeaderboardOptOut method.
+		SetLeaderboardOptOut []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// UserID is the userID argument value.
+			UserID int
+			// OptOut is the optOut argument value.
+			OptOut bool
+		}
+		// SetUserRole holds details about calls to the SetUserRole method.
+		SetUserRole []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Username is the username argument value.
+			Username string
+			// Role is the role argument value.
+			Role string
 		}
 	}
-	lockAddNewUser         sync.RWMutex
-	lockBuyItem            sync.RWMutex
-	lockCheckAuth          sync.RWMutex
-	lockGetInfo            sync.RWMutex
-	lockGetInventory       sync.RWMutex
-	lockGetReceivedHistory sync.RWMutex
-	lockGetSendHistory     sync.RWMutex
-	lockSendCoins          sync.RWMutex
+	lockAcceptPaymentRequest        sync.RWMutex
+	lockAddNewUser                  sync.RWMutex
+	lockAppendAudit                 sync.RWMutex
+	lockApproveGroupPurchase        sync.RWMutex
+	lockAwardAchievement            sync.RWMutex
+	lockBuyItem                     sync.RWMutex
+	lockBuyItemWithPromo            sync.RWMutex
+	lockCancelGroupPurchase         sync.RWMutex
+	lockCancelScheduledTransfer     sync.RWMutex
+	lockCheckAuth                   sync.RWMutex
+	lockCountPromoRedemptions       sync.RWMutex
+	lockCreateGroup                 sync.RWMutex
+	lockCreateGroupPurchase         sync.RWMutex
+	lockCreateItemPrice             sync.RWMutex
+	lockCreatePaymentRequest        sync.RWMutex
+	lockCreatePendingTransfer       sync.RWMutex
+	lockCreatePromoCode             sync.RWMutex
+	lockCreateScheduledTransfer     sync.RWMutex
+	lockDeleteItemPrice             sync.RWMutex
+	lockDisablePromoCode            sync.RWMutex
+	lockEnsureSystemAccount         sync.RWMutex
+	lockExpireCoins                 sync.RWMutex
+	lockGetAchievementProgress      sync.RWMutex
+	lockGetAuditHead                sync.RWMutex
+	lockGetCoinHistory              sync.RWMutex
+	lockGetCoinLots                 sync.RWMutex
+	lockGetFullInfo                 sync.RWMutex
+	lockGetGroup                    sync.RWMutex
+	lockGetGroupMemberRole          sync.RWMutex
+	lockGetGroupPurchase            sync.RWMutex
+	lockGetInfo                     sync.RWMutex
+	lockGetInventory                sync.RWMutex
+	lockGetItemPrice                sync.RWMutex
+	lockGetLeaderboard              sync.RWMutex
+	lockGetPaymentRequest           sync.RWMutex
+	lockGetPendingTransfer          sync.RWMutex
+	lockGetPromoCode                sync.RWMutex
+	lockGetReceivedHistory          sync.RWMutex
+	lockGetScheduledTransfer        sync.RWMutex
+	lockGetSendHistory              sync.RWMutex
+	lockGetTransferTotals           sync.RWMutex
+	lockGetUserRole                 sync.RWMutex
+	lockListAchievements            sync.RWMutex
+	lockListAudit                   sync.RWMutex
+	lockListDueScheduledTransfers   sync.RWMutex
+	lockListExpiredCoins            sync.RWMutex
+	lockListExpiredPendingTransfers sync.RWMutex
+	lockListGroupMembers            sync.RWMutex
+	lockListGroupPurchases          sync.RWMutex
+	lockListItemPrices              sync.RWMutex
+	lockListOrders                  sync.RWMutex
+	lockListPendingPaymentRequests  sync.RWMutex
+	lockListPendingTransfers        sync.RWMutex
+	lockListPromoCodes              sync.RWMutex
+	lockListScheduledTransfers      sync.RWMutex
+	lockListUserGroups              sync.RWMutex
+	lockListUserTransactions        sync.RWMutex
+	lockRemoveGroupMember           sync.RWMutex
+	lockResolvePaymentRequest       sync.RWMutex
+	lockResolvePendingTransfer      sync.RWMutex
+	lockReverseTransaction          sync.RWMutex
+	lockRunScheduledTransfer        sync.RWMutex
+	lockSendCoins                   sync.RWMutex
+	lockSetGroupMember              sync.RWMutex
+	lockSetLeaderboardOptOut        sync.RWMutex
+	lockSetUserRole                 sync.RWMutex
+}
+
+// AcceptPaymentRequest calls AcceptPaymentRequestFunc.
+func (mock *IStorageMock) AcceptPaymentRequest(ctx context.Context, id int, now time.Time, limits TransferLimits, fee TransferFee, audit *TxAudit) error {
+	if mock.AcceptPaymentRequestFunc == nil {
+		panic("IStorageMock.AcceptPaymentRequestFunc: method is nil but IStorage.AcceptPaymentRequest was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		ID     int
+		Now    time.Time
+		Limits TransferLimits
+		Fee    TransferFee
+		Audit  *TxAudit
+	}{
+		Ctx:    ctx,
+		ID:     id,
+		Now:    now,
+		Limits: limits,
+		Fee:    fee,
+		Audit:  audit,
+	}
+	mock.lockAcceptPaymentRequest.Lock()
+	mock.calls.AcceptPaymentRequest = append(mock.calls.AcceptPaymentRequest, callInfo)
+	mock.lockAcceptPaymentRequest.Unlock()
+	return mock.AcceptPaymentRequestFunc(ctx, id, now, limits, fee, audit)
+}
+
+// AcceptPaymentRequestCalls gets all the calls that were made to AcceptPaymentRequest.
+// Check the length with:
+//
+//	len(mockedIStorage.AcceptPaymentRequestCalls())
+func (mock *IStorageMock) AcceptPaymentRequestCalls() []struct {
+	Ctx    context.Context
+	ID     int
+	Now    time.Time
+	Limits TransferLimits
+	Fee    TransferFee
+	Audit  *TxAudit
+} {
+	var calls []struct {
+		Ctx    context.Context
+		ID     int
+		Now    time.Time
+		Limits TransferLimits
+		Fee    TransferFee
+		Audit  *TxAudit
+	}
+	mock.lockAcceptPaymentRequest.RLock()
+	calls = mock.calls.AcceptPaymentRequest
+	mock.lockAcceptPaymentRequest.RUnlock()
+	return calls
 }
 
 // AddNewUser calls AddNewUserFunc.
-func (mock *IStorageMock) AddNewUser(username string, password string) error {
+func (mock *IStorageMock) AddNewUser(ctx context.Context, username string, password string) error {
 	if mock.AddNewUserFunc == nil {
 		panic("IStorageMock.AddNewUserFunc: method is nil but IStorage.AddNewUser was just called")
 	}
 	callInfo := struct {
+		Ctx      context.Context
 		Username string
 		Password string
 	}{
+		Ctx:      ctx,
 		Username: username,
 		Password: password,
 	}
 	mock.lockAddNewUser.Lock()
 	mock.calls.AddNewUser = append(mock.calls.AddNewUser, callInfo)
 	mock.lockAddNewUser.Unlock()
-	return mock.AddNewUserFunc(username, password)
+	return mock.AddNewUserFunc(ctx, username, password)
 }
 
 // AddNewUserCalls gets all the calls that were made to AddNewUser.
@@ -168,10 +1172,12 @@ func (mock *IStorageMock) AddNewUser(username string, password string) error {
 //
 //	len(mockedIStorage.AddNewUserCalls())
 func (mock *IStorageMock) AddNewUserCalls() []struct {
+	Ctx      context.Context
 	Username string
 	Password string
 } {
 	var calls []struct {
+		Ctx      context.Context
 		Username string
 		Password string
 	}
@@ -181,242 +1187,2410 @@ func (mock *IStorageMock) AddNewUserCalls() []struct {
 	return calls
 }
 
-// BuyItem calls BuyItemFunc.
-func (mock *IStorageMock) BuyItem(name string, item string, amount int) error {
-	if mock.BuyItemFunc == nil {
-		panic("IStorageMock.BuyItemFunc: method is nil but IStorage.BuyItem was just called")
+// AppendAudit calls AppendAuditFunc.
+func (mock *IStorageMock) AppendAudit(ctx context.Context, e *AuditEntry) error {
+	if mock.AppendAuditFunc == nil {
+		panic("IStorageMock.AppendAuditFunc: method is nil but IStorage.AppendAudit was just called")
 	}
 	callInfo := struct {
-		Name   string
-		Item   string
-		Amount int
+		Ctx context.Context
+		E   *AuditEntry
 	}{
-		Name:   name,
-		Item:   item,
-		Amount: amount,
+		Ctx: ctx,
+		E:   e,
 	}
-	mock.lockBuyItem.Lock()
-	mock.calls.BuyItem = append(mock.calls.BuyItem, callInfo)
-	mock.lockBuyItem.Unlock()
-	return mock.BuyItemFunc(name, item, amount)
+	mock.lockAppendAudit.Lock()
+	mock.calls.AppendAudit = append(mock.calls.AppendAudit, callInfo)
+	mock.lockAppendAudit.Unlock()
+	return mock.AppendAuditFunc(ctx, e)
 }
 
-// BuyItemCalls gets all the calls that were made to BuyItem.
+// AppendAuditCalls gets all the calls that were made to AppendAudit.
 // Check the length with:
 //
-//	len(mockedIStorage.BuyItemCalls())
-func (mock *IStorageMock) BuyItemCalls() []struct {
-	Name   string
-	Item   string
-	Amount int
+//	len(mockedIStorage.AppendAuditCalls())
+func (mock *IStorageMock) AppendAuditCalls() []struct {
+	Ctx context.Context
+	E   *AuditEntry
 } {
 	var calls []struct {
-		Name   string
-		Item   string
-		Amount int
+		Ctx context.Context
+		E   *AuditEntry
 	}
-	mock.lockBuyItem.RLock()
-	calls = mock.calls.BuyItem
-	mock.lockBuyItem.RUnlock()
+	mock.lockAppendAudit.RLock()
+	calls = mock.calls.AppendAudit
+	mock.lockAppendAudit.RUnlock()
 	return calls
 }
 
-// CheckAuth calls CheckAuthFunc.
-func (mock *IStorageMock) CheckAuth(username string) (string, error) {
-	if mock.CheckAuthFunc == nil {
-		panic("IStorageMock.CheckAuthFunc: method is nil but IStorage.CheckAuth was just called")
+// ApproveGroupPurchase calls ApproveGroupPurchaseFunc.
+func (mock *IStorageMock) ApproveGroupPurchase(ctx context.Context, id int, userID int, price int, priceID int, at time.Time, audit *TxAudit) (*GroupPurchase, error) {
+	if mock.ApproveGroupPurchaseFunc == nil {
+		panic("IStorageMock.ApproveGroupPurchaseFunc: method is nil but IStorage.ApproveGroupPurchase was just called")
 	}
 	callInfo := struct {
-		Username string
+		Ctx     context.Context
+		ID      int
+		UserID  int
+		Price   int
+		PriceID int
+		At      time.Time
+		Audit   *TxAudit
 	}{
-		Username: username,
+		Ctx:     ctx,
+		ID:      id,
+		UserID:  userID,
+		Price:   price,
+		PriceID: priceID,
+		At:      at,
+		Audit:   audit,
 	}
-	mock.lockCheckAuth.Lock()
-	mock.calls.CheckAuth = append(mock.calls.CheckAuth, callInfo)
-	mock.lockCheckAuth.Unlock()
-	return mock.CheckAuthFunc(username)
+	mock.lockApproveGroupPurchase.Lock()
+	mock.calls.ApproveGroupPurchase = append(mock.calls.ApproveGroupPurchase, callInfo)
+	mock.lockApproveGroupPurchase.Unlock()
+	return mock.ApproveGroupPurchaseFunc(ctx, id, userID, price, priceID, at, audit)
 }
 
-// CheckAuthCalls gets all the calls that were made to CheckAuth.
+// ApproveGroupPurchaseCalls gets all the calls that were made to ApproveGroupPurchase.
 // Check the length with:
 //
-//	len(mockedIStorage.CheckAuthCalls())
-func (mock *IStorageMock) CheckAuthCalls() []struct {
-	Username string
+//	len(mockedIStorage.ApproveGroupPurchaseCalls())
+func (mock *IStorageMock) ApproveGroupPurchaseCalls() []struct {
+	Ctx     context.Context
+	ID      int
+	UserID  int
+	Price   int
+	PriceID int
+	At      time.Time
+	Audit   *TxAudit
 } {
 	var calls []struct {
+		Ctx     context.Context
+		ID      int
+		UserID  int
+		Price   int
+		PriceID int
+		At      time.Time
+		Audit   *TxAudit
+	}
+	mock.lockApproveGroupPurchase.RLock()
+	calls = mock.calls.ApproveGroupPurchase
+	mock.lockApproveGroupPurchase.RUnlock()
+	return calls
+}
+
+// AwardAchievement calls AwardAchievementFunc.
+func (mock *IStorageMock) AwardAchievement(ctx context.Context, userID int, a *Achievement) (bool, error) {
+	if mock.AwardAchievementFunc == nil {
+		panic("IStorageMock.AwardAchievementFunc: method is nil but IStorage.AwardAchievement was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int
+		A      *Achievement
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		A:      a,
+	}
+	mock.lockAwardAchievement.Lock()
+	mock.calls.AwardAchievement = append(mock.calls.AwardAchievement, callInfo)
+	mock.lockAwardAchievement.Unlock()
+	return mock.AwardAchievementFunc(ctx, userID, a)
+}
+
+// AwardAchievementCalls gets all the calls that were made to AwardAchievement.
+// Check the length with:
+//
+//	len(mockedIStorage.AwardAchievementCalls())
+func (mock *IStorageMock) AwardAchievementCalls() []struct {
+	Ctx    context.Context
+	UserID int
+	A      *Achievement
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int
+		A      *Achievement
+	}
+	mock.lockAwardAchievement.RLock()
+	calls = mock.calls.AwardAchievement
+	mock.lockAwardAchievement.RUnlock()
+	return calls
+}
+
+// BuyItem calls BuyItemFunc.
+func (mock *IStorageMock) BuyItem(ctx context.Context, name string, item string, amount int, priceID int, audit *TxAudit) error {
+	if mock.BuyItemFunc == nil {
+		panic("IStorageMock.BuyItemFunc: method is nil but IStorage.BuyItem was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Name    string
+		Item    string
+		Amount  int
+		PriceID int
+		Audit   *TxAudit
+	}{
+		Ctx:     ctx,
+		Name:    name,
+		Item:    item,
+		Amount:  amount,
+		PriceID: priceID,
+		Audit:   audit,
+	}
+	mock.lockBuyItem.Lock()
+	mock.calls.BuyItem = append(mock.calls.BuyItem, callInfo)
+	mock.lockBuyItem.Unlock()
+	return mock.BuyItemFunc(ctx, name, item, amount, priceID, audit)
+}
+
+// BuyItemCalls gets all the calls that were made to BuyItem.
+// Check the length with:
+//
+//	len(mockedIStorage.BuyItemCalls())
+func (mock *IStorageMock) BuyItemCalls() []struct {
+	Ctx     context.Context
+	Name    string
+	Item    string
+	Amount  int
+	PriceID int
+	Audit   *TxAudit
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Name    string
+		Item    string
+		Amount  int
+		PriceID int
+		Audit   *TxAudit
+	}
+	mock.lockBuyItem.RLock()
+	calls = mock.calls.BuyItem
+	mock.lockBuyItem.RUnlock()
+	return calls
+}
+
+// BuyItemWithPromo calls BuyItemWithPromoFunc.
+func (mock *IStorageMock) BuyItemWithPromo(ctx context.Context, name string, item string, r *PromoRedemption, audit *TxAudit) error {
+	if mock.BuyItemWithPromoFunc == nil {
+		panic("IStorageMock.BuyItemWithPromoFunc: method is nil but IStorage.BuyItemWithPromo was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Name  string
+		Item  string
+		R     *PromoRedemption
+		Audit *TxAudit
+	}{
+		Ctx:   ctx,
+		Name:  name,
+		Item:  item,
+		R:     r,
+		Audit: audit,
+	}
+	mock.lockBuyItemWithPromo.Lock()
+	mock.calls.BuyItemWithPromo = append(mock.calls.BuyItemWithPromo, callInfo)
+	mock.lockBuyItemWithPromo.Unlock()
+	return mock.BuyItemWithPromoFunc(ctx, name, item, r, audit)
+}
+
+// BuyItemWithPromoCalls gets all the calls that were made to BuyItemWithPromo.
+// Check the length with:
+//
+//	len(mockedIStorage.BuyItemWithPromoCalls())
+func (mock *IStorageMock) BuyItemWithPromoCalls() []struct {
+	Ctx   context.Context
+	Name  string
+	Item  string
+	R     *PromoRedemption
+	Audit *TxAudit
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Name  string
+		Item  string
+		R     *PromoRedemption
+		Audit *TxAudit
+	}
+	mock.lockBuyItemWithPromo.RLock()
+	calls = mock.calls.BuyItemWithPromo
+	mock.lockBuyItemWithPromo.RUnlock()
+	return calls
+}
+
+// CancelGroupPurchase calls CancelGroupPurchaseFunc.
+func (mock *IStorageMock) CancelGroupPurchase(ctx context.Context, id int, at time.Time) error {
+	if mock.CancelGroupPurchaseFunc == nil {
+		panic("IStorageMock.CancelGroupPurchaseFunc: method is nil but IStorage.CancelGroupPurchase was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int
+		At  time.Time
+	}{
+		Ctx: ctx,
+		ID:  id,
+		At:  at,
+	}
+	mock.lockCancelGroupPurchase.Lock()
+	mock.calls.CancelGroupPurchase = append(mock.calls.CancelGroupPurchase, callInfo)
+	mock.lockCancelGroupPurchase.Unlock()
+	return mock.CancelGroupPurchaseFunc(ctx, id, at)
+}
+
+// CancelGroupPurchaseCalls gets all the calls that were made to CancelGroupPurchase.
+// Check the length with:
+//
+//	len(mockedIStorage.CancelGroupPurchaseCalls())
+func (mock *IStorageMock) CancelGroupPurchaseCalls() []struct {
+	Ctx context.Context
+	ID  int
+	At  time.Time
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int
+		At  time.Time
+	}
+	mock.lockCancelGroupPurchase.RLock()
+	calls = mock.calls.CancelGroupPurchase
+	mock.lockCancelGroupPurchase.RUnlock()
+	return calls
+}
+
+// CancelScheduledTransfer calls CancelScheduledTransferFunc.
+func (mock *IStorageMock) CancelScheduledTransfer(ctx context.Context, id int) error {
+	if mock.CancelScheduledTransferFunc == nil {
+		panic("IStorageMock.CancelScheduledTransferFunc: method is nil but IStorage.CancelScheduledTransfer was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockCancelScheduledTransfer.Lock()
+	mock.calls.CancelScheduledTransfer = append(mock.calls.CancelScheduledTransfer, callInfo)
+	mock.lockCancelScheduledTransfer.Unlock()
+	return mock.CancelScheduledTransferFunc(ctx, id)
+}
+
+// CancelScheduledTransferCalls gets all the calls that were made to CancelScheduledTransfer.
+// Check the length with:
+//
+//	len(mockedIStorage.CancelScheduledTransferCalls())
+func (mock *IStorageMock) CancelScheduledTransferCalls() []struct {
+	Ctx context.Context
+	ID  int
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int
+	}
+	mock.lockCancelScheduledTransfer.RLock()
+	calls = mock.calls.CancelScheduledTransfer
+	mock.lockCancelScheduledTransfer.RUnlock()
+	return calls
+}
+
+// CheckAuth calls CheckAuthFunc.
+func (mock *IStorageMock) CheckAuth(ctx context.Context, username string) (string, error) {
+	if mock.CheckAuthFunc == nil {
+		panic("IStorageMock.CheckAuthFunc: method is nil but IStorage.CheckAuth was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+	}{
+		Ctx:      ctx,
+		Username: username,
+	}
+	mock.lockCheckAuth.Lock()
+	mock.calls.CheckAuth = append(mock.calls.CheckAuth, callInfo)
+	mock.lockCheckAuth.Unlock()
+	return mock.CheckAuthFunc(ctx, username)
+}
+
+// CheckAuthCalls gets all the calls that were made to CheckAuth.
+// Check the length with:
+//
+//	len(mockedIStorage.CheckAuthCalls())
+func (mock *IStorageMock) CheckAuthCalls() []struct {
+	Ctx      context.Context
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
 		Username string
 	}
-	mock.lockCheckAuth.RLock()
-	calls = mock.calls.CheckAuth
-	mock.lockCheckAuth.RUnlock()
+	mock.lockCheckAuth.RLock()
+	calls = mock.calls.CheckAuth
+	mock.lockCheckAuth.RUnlock()
+	return calls
+}
+
+// CountPromoRedemptions calls CountPromoRedemptionsFunc.
+func (mock *IStorageMock) CountPromoRedemptions(ctx context.Context, promoCodeID int, userID int) (int, error) {
+	if mock.CountPromoRedemptionsFunc == nil {
+		panic("IStorageMock.CountPromoRedemptionsFunc: method is nil but IStorage.CountPromoRedemptions was just called")
+	}
+	callInfo := struct {
+		Ctx         context.Context
+		PromoCodeID int
+		UserID      int
+	}{
+		Ctx:         ctx,
+		PromoCodeID: promoCodeID,
+		UserID:      userID,
+	}
+	mock.lockCountPromoRedemptions.Lock()
+	mock.calls.CountPromoRedemptions = append(mock.calls.CountPromoRedemptions, callInfo)
+	mock.lockCountPromoRedemptions.Unlock()
+	return mock.CountPromoRedemptionsFunc(ctx, promoCodeID, userID)
+}
+
+// CountPromoRedemptionsCalls gets all the calls that were made to CountPromoRedemptions.
+// Check the length with:
+//
+//	len(mockedIStorage.CountPromoRedemptionsCalls())
+func (mock *IStorageMock) CountPromoRedemptionsCalls() []struct {
+	Ctx         context.Context
+	PromoCodeID int
+	UserID      int
+} {
+	var calls []struct {
+		Ctx         context.Context
+		PromoCodeID int
+		UserID      int
+	}
+	mock.lockCountPromoRedemptions.RLock()
+	calls = mock.calls.CountPromoRedemptions
+	mock.lockCountPromoRedemptions.RUnlock()
+	return calls
+}
+
+// CreateGroup calls CreateGroupFunc.
+func (mock *IStorageMock) CreateGroup(ctx context.Context, g *Group, ownerID int) error {
+	if mock.CreateGroupFunc == nil {
+		panic("IStorageMock.CreateGroupFunc: method is nil but IStorage.CreateGroup was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		G       *Group
+		OwnerID int
+	}{
+		Ctx:     ctx,
+		G:       g,
+		OwnerID: ownerID,
+	}
+	mock.lockCreateGroup.Lock()
+	mock.calls.CreateGroup = append(mock.calls.CreateGroup, callInfo)
+	mock.lockCreateGroup.Unlock()
+	return mock.CreateGroupFunc(ctx, g, ownerID)
+}
+
+// CreateGroupCalls gets all the calls that were made to CreateGroup.
+// Check the length with:
+//
+//	len(mockedIStorage.CreateGroupCalls())
+func (mock *IStorageMock) CreateGroupCalls() []struct {
+	Ctx     context.Context
+	G       *Group
+	OwnerID int
+} {
+	var calls []struct {
+		Ctx     context.Context
+		G       *Group
+		OwnerID int
+	}
+	mock.lockCreateGroup.RLock()
+	calls = mock.calls.CreateGroup
+	mock.lockCreateGroup.RUnlock()
+	return calls
+}
+
+// CreateGroupPurchase calls CreateGroupPurchaseFunc.
+func (mock *IStorageMock) CreateGroupPurchase(ctx context.Context, p *GroupPurchase, proposerID int) error {
+	if mock.CreateGroupPurchaseFunc == nil {
+		panic("IStorageMock.CreateGroupPurchaseFunc: method is nil but IStorage.CreateGroupPurchase was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		P          *GroupPurchase
+		ProposerID int
+	}{
+		Ctx:        ctx,
+		P:          p,
+		ProposerID: proposerID,
+	}
+	mock.lockCreateGroupPurchase.Lock()
+	mock.calls.CreateGroupPurchase = append(mock.calls.CreateGroupPurchase, callInfo)
+	mock.lockCreateGroupPurchase.Unlock()
+	return mock.CreateGroupPurchaseFunc(ctx, p, proposerID)
+}
+
+// CreateGroupPurchaseCalls gets all the calls that were made to CreateGroupPurchase.
+// Check the length with:
+//
+//	len(mockedIStorage.CreateGroupPurchaseCalls())
+func (mock *IStorageMock) CreateGroupPurchaseCalls() []struct {
+	Ctx        context.Context
+	P          *GroupPurchase
+	ProposerID int
+} {
+	var calls []struct {
+		Ctx        context.Context
+		P          *GroupPurchase
+		ProposerID int
+	}
+	mock.lockCreateGroupPurchase.RLock()
+	calls = mock.calls.CreateGroupPurchase
+	mock.lockCreateGroupPurchase.RUnlock()
+	return calls
+}
+
+// CreateItemPrice calls CreateItemPriceFunc.
+func (mock *IStorageMock) CreateItemPrice(ctx context.Context, p *ItemPrice) error {
+	if mock.CreateItemPriceFunc == nil {
+		panic("IStorageMock.CreateItemPriceFunc: method is nil but IStorage.CreateItemPrice was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		P   *ItemPrice
+	}{
+		Ctx: ctx,
+		P:   p,
+	}
+	mock.lockCreateItemPrice.Lock()
+	mock.calls.CreateItemPrice = append(mock.calls.CreateItemPrice, callInfo)
+	mock.lockCreateItemPrice.Unlock()
+	return mock.CreateItemPriceFunc(ctx, p)
+}
+
+// CreateItemPriceCalls gets all the calls that were made to CreateItemPrice.
+// Check the length with:
+//
+//	len(mockedIStorage.CreateItemPriceCalls())
+func (mock *IStorageMock) CreateItemPriceCalls() []struct {
+	Ctx context.Context
+	P   *ItemPrice
+} {
+	var calls []struct {
+		Ctx context.Context
+		P   *ItemPrice
+	}
+	mock.lockCreateItemPrice.RLock()
+	calls = mock.calls.CreateItemPrice
+	mock.lockCreateItemPrice.RUnlock()
+	return calls
+}
+
+// CreatePaymentRequest calls CreatePaymentRequestFunc.
+func (mock *IStorageMock) CreatePaymentRequest(ctx context.Context, pr *PaymentRequest) error {
+	if mock.CreatePaymentRequestFunc == nil {
+		panic("IStorageMock.CreatePaymentRequestFunc: method is nil but IStorage.CreatePaymentRequest was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Pr  *PaymentRequest
+	}{
+		Ctx: ctx,
+		Pr:  pr,
+	}
+	mock.lockCreatePaymentRequest.Lock()
+	mock.calls.CreatePaymentRequest = append(mock.calls.CreatePaymentRequest, callInfo)
+	mock.lockCreatePaymentRequest.Unlock()
+	return mock.CreatePaymentRequestFunc(ctx, pr)
+}
+
+// CreatePaymentRequestCalls gets all the calls that were made to CreatePaymentRequest.
+// Check the length with:
+//
+//	len(mockedIStorage.CreatePaymentRequestCalls())
+func (mock *IStorageMock) CreatePaymentRequestCalls() []struct {
+	Ctx context.Context
+	Pr  *PaymentRequest
+} {
+	var calls []struct {
+		Ctx context.Context
+		Pr  *PaymentRequest
+	}
+	mock.lockCreatePaymentRequest.RLock()
+	calls = mock.calls.CreatePaymentRequest
+	mock.lockCreatePaymentRequest.RUnlock()
+	return calls
+}
+
+// CreatePendingTransfer calls CreatePendingTransferFunc.
+func (mock *IStorageMock) CreatePendingTransfer(ctx context.Context, pt *PendingTransfer, limits TransferLimits, audit *TxAudit) error {
+	if mock.CreatePendingTransferFunc == nil {
+		panic("IStorageMock.CreatePendingTransferFunc: method is nil but IStorage.CreatePendingTransfer was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Pt     *PendingTransfer
+		Limits TransferLimits
+		Audit  *TxAudit
+	}{
+		Ctx:    ctx,
+		Pt:     pt,
+		Limits: limits,
+		Audit:  audit,
+	}
+	mock.lockCreatePendingTransfer.Lock()
+	mock.calls.CreatePendingTransfer = append(mock.calls.CreatePendingTransfer, callInfo)
+	mock.lockCreatePendingTransfer.Unlock()
+	return mock.CreatePendingTransferFunc(ctx, pt, limits, audit)
+}
+
+// CreatePendingTransferCalls gets all the calls that were made to CreatePendingTransfer.
+// Check the length with:
+//
+//	len(mockedIStorage.CreatePendingTransferCalls())
+func (mock *IStorageMock) CreatePendingTransferCalls() []struct {
+	Ctx    context.Context
+	Pt     *PendingTransfer
+	Limits TransferLimits
+	Audit  *TxAudit
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Pt     *PendingTransfer
+		Limits TransferLimits
+		Audit  *TxAudit
+	}
+	mock.lockCreatePendingTransfer.RLock()
+	calls = mock.calls.CreatePendingTransfer
+	mock.lockCreatePendingTransfer.RUnlock()
+	return calls
+}
+
+// CreatePromoCode calls CreatePromoCodeFunc.
+func (mock *IStorageMock) CreatePromoCode(ctx context.Context, pc *PromoCode) error {
+	if mock.CreatePromoCodeFunc == nil {
+		panic("IStorageMock.CreatePromoCodeFunc: method is nil but IStorage.CreatePromoCode was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Pc  *PromoCode
+	}{
+		Ctx: ctx,
+		Pc:  pc,
+	}
+	mock.lockCreatePromoCode.Lock()
+	mock.calls.CreatePromoCode = append(mock.calls.CreatePromoCode, callInfo)
+	mock.lockCreatePromoCode.Unlock()
+	return mock.CreatePromoCodeFunc(ctx, pc)
+}
+
+// CreatePromoCodeCalls gets all the calls that were made to CreatePromoCode.
+// Check the length with:
+//
+//	len(mockedIStorage.CreatePromoCodeCalls())
+func (mock *IStorageMock) CreatePromoCodeCalls() []struct {
+	Ctx context.Context
+	Pc  *PromoCode
+} {
+	var calls []struct {
+		Ctx context.Context
+		Pc  *PromoCode
+	}
+	mock.lockCreatePromoCode.RLock()
+	calls = mock.calls.CreatePromoCode
+	mock.lockCreatePromoCode.RUnlock()
+	return calls
+}
+
+// CreateScheduledTransfer calls CreateScheduledTransferFunc.
+func (mock *IStorageMock) CreateScheduledTransfer(ctx context.Context, st *ScheduledTransfer) error {
+	if mock.CreateScheduledTransferFunc == nil {
+		panic("IStorageMock.CreateScheduledTransferFunc: method is nil but IStorage.CreateScheduledTransfer was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		St  *ScheduledTransfer
+	}{
+		Ctx: ctx,
+		St:  st,
+	}
+	mock.lockCreateScheduledTransfer.Lock()
+	mock.calls.CreateScheduledTransfer = append(mock.calls.CreateScheduledTransfer, callInfo)
+	mock.lockCreateScheduledTransfer.Unlock()
+	return mock.CreateScheduledTransferFunc(ctx, st)
+}
+
+// CreateScheduledTransferCalls gets all the calls that were made to CreateScheduledTransfer.
+// Check the length with:
+//
+//	len(mockedIStorage.CreateScheduledTransferCalls())
+func (mock *IStorageMock) CreateScheduledTransferCalls() []struct {
+	Ctx context.Context
+	St  *ScheduledTransfer
+} {
+	var calls []struct {
+		Ctx context.Context
+		St  *ScheduledTransfer
+	}
+	mock.lockCreateScheduledTransfer.RLock()
+	calls = mock.calls.CreateScheduledTransfer
+	mock.lockCreateScheduledTransfer.RUnlock()
+	return calls
+}
+
+// DeleteItemPrice calls DeleteItemPriceFunc.
+func (mock *IStorageMock) DeleteItemPrice(ctx context.Context, id int) error {
+	if mock.DeleteItemPriceFunc == nil {
+		panic("IStorageMock.DeleteItemPriceFunc: method is nil but IStorage.DeleteItemPrice was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockDeleteItemPrice.Lock()
+	mock.calls.DeleteItemPrice = append(mock.calls.DeleteItemPrice, callInfo)
+	mock.lockDeleteItemPrice.Unlock()
+	return mock.DeleteItemPriceFunc(ctx, id)
+}
+
+// DeleteItemPriceCalls gets all the calls that were made to DeleteItemPrice.
+// Check the length with:
+//
+//	len(mockedIStorage.DeleteItemPriceCalls())
+func (mock *IStorageMock) DeleteItemPriceCalls() []struct {
+	Ctx context.Context
+	ID  int
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int
+	}
+	mock.lockDeleteItemPrice.RLock()
+	calls = mock.calls.DeleteItemPrice
+	mock.lockDeleteItemPrice.RUnlock()
+	return calls
+}
+
+// DisablePromoCode calls DisablePromoCodeFunc.
+func (mock *IStorageMock) DisablePromoCode(ctx context.Context, code string, now time.Time) error {
+	if mock.DisablePromoCodeFunc == nil {
+		panic("IStorageMock.DisablePromoCodeFunc: method is nil but IStorage.DisablePromoCode was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Code string
+		Now  time.Time
+	}{
+		Ctx:  ctx,
+		Code: code,
+		Now:  now,
+	}
+	mock.lockDisablePromoCode.Lock()
+	mock.calls.DisablePromoCode = append(mock.calls.DisablePromoCode, callInfo)
+	mock.lockDisablePromoCode.Unlock()
+	return mock.DisablePromoCodeFunc(ctx, code, now)
+}
+
+// DisablePromoCodeCalls gets all the calls that were made to DisablePromoCode.
+// Check the length with:
+//
+//	len(mockedIStorage.DisablePromoCodeCalls())
+func (mock *IStorageMock) DisablePromoCodeCalls() []struct {
+	Ctx  context.Context
+	Code string
+	Now  time.Time
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Code string
+		Now  time.Time
+	}
+	mock.lockDisablePromoCode.RLock()
+	calls = mock.calls.DisablePromoCode
+	mock.lockDisablePromoCode.RUnlock()
+	return calls
+}
+
+// EnsureSystemAccount calls EnsureSystemAccountFunc.
+func (mock *IStorageMock) EnsureSystemAccount(ctx context.Context, username string) (int, error) {
+	if mock.EnsureSystemAccountFunc == nil {
+		panic("IStorageMock.EnsureSystemAccountFunc: method is nil but IStorage.EnsureSystemAccount was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+	}{
+		Ctx:      ctx,
+		Username: username,
+	}
+	mock.lockEnsureSystemAccount.Lock()
+	mock.calls.EnsureSystemAccount = append(mock.calls.EnsureSystemAccount, callInfo)
+	mock.lockEnsureSystemAccount.Unlock()
+	return mock.EnsureSystemAccountFunc(ctx, username)
+}
+
+// EnsureSystemAccountCalls gets all the calls that were made to EnsureSystemAccount.
+// Check the length with:
+//
+//	len(mockedIStorage.EnsureSystemAccountCalls())
+func (mock *IStorageMock) EnsureSystemAccountCalls() []struct {
+	Ctx      context.Context
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+	}
+	mock.lockEnsureSystemAccount.RLock()
+	calls = mock.calls.EnsureSystemAccount
+	mock.lockEnsureSystemAccount.RUnlock()
+	return calls
+}
+
+// ExpireCoins calls ExpireCoinsFunc.
+func (mock *IStorageMock) ExpireCoins(ctx context.Context, userID int, sinkID int, cutoff time.Time, now time.Time, audit *TxAudit) (int, error) {
+	if mock.ExpireCoinsFunc == nil {
+		panic("IStorageMock.ExpireCoinsFunc: method is nil but IStorage.ExpireCoins was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int
+		SinkID int
+		Cutoff time.Time
+		Now    time.Time
+		Audit  *TxAudit
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		SinkID: sinkID,
+		Cutoff: cutoff,
+		Now:    now,
+		Audit:  audit,
+	}
+	mock.lockExpireCoins.Lock()
+	mock.calls.ExpireCoins = append(mock.calls.ExpireCoins, callInfo)
+	mock.lockExpireCoins.Unlock()
+	return mock.ExpireCoinsFunc(ctx, userID, sinkID, cutoff, now, audit)
+}
+
+// ExpireCoinsCalls gets all the calls that were made to ExpireCoins.
+// Check the length with:
+//
+//	len(mockedIStorage.ExpireCoinsCalls())
+func (mock *IStorageMock) ExpireCoinsCalls() []struct {
+	Ctx    context.Context
+	UserID int
+	SinkID int
+	Cutoff time.Time
+	Now    time.Time
+	Audit  *TxAudit
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int
+		SinkID int
+		Cutoff time.Time
+		Now    time.Time
+		Audit  *TxAudit
+	}
+	mock.lockExpireCoins.RLock()
+	calls = mock.calls.ExpireCoins
+	mock.lockExpireCoins.RUnlock()
+	return calls
+}
+
+// GetAchievementProgress calls GetAchievementProgressFunc.
+func (mock *IStorageMock) GetAchievementProgress(ctx context.Context, userID int) (*AchievementProgress, error) {
+	if mock.GetAchievementProgressFunc == nil {
+		panic("IStorageMock.GetAchievementProgressFunc: method is nil but IStorage.GetAchievementProgress was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockGetAchievementProgress.Lock()
+	mock.calls.GetAchievementProgress = append(mock.calls.GetAchievementProgress, callInfo)
+	mock.lockGetAchievementProgress.Unlock()
+	return mock.GetAchievementProgressFunc(ctx, userID)
+}
+
+// GetAchievementProgressCalls gets all the calls that were made to GetAchievementProgress.
+// Check the length with:
+//
+//	len(mockedIStorage.GetAchievementProgressCalls())
+func (mock *IStorageMock) GetAchievementProgressCalls() []struct {
+	Ctx    context.Context
+	UserID int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int
+	}
+	mock.lockGetAchievementProgress.RLock()
+	calls = mock.calls.GetAchievementProgress
+	mock.lockGetAchievementProgress.RUnlock()
+	return calls
+}
+
+// GetAuditHead calls GetAuditHeadFunc.
+func (mock *IStorageMock) GetAuditHead(ctx context.Context) (*AuditHead, error) {
+	if mock.GetAuditHeadFunc == nil {
+		panic("IStorageMock.GetAuditHeadFunc: method is nil but IStorage.GetAuditHead was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetAuditHead.Lock()
+	mock.calls.GetAuditHead = append(mock.calls.GetAuditHead, callInfo)
+	mock.lockGetAuditHead.Unlock()
+	return mock.GetAuditHeadFunc(ctx)
+}
+
+// GetAuditHeadCalls gets all the calls that were made to GetAuditHead.
+// Check the length with:
+//
+//	len(mockedIStorage.GetAuditHeadCalls())
+func (mock *IStorageMock) GetAuditHeadCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetAuditHead.RLock()
+	calls = mock.calls.GetAuditHead
+	mock.lockGetAuditHead.RUnlock()
+	return calls
+}
+
+// GetCoinHistory calls GetCoinHistoryFunc.
+func (mock *IStorageMock) GetCoinHistory(ctx context.Context, ir *InfoResponse, id int, filter HistoryFilter) error {
+	if mock.GetCoinHistoryFunc == nil {
+		panic("IStorageMock.GetCoinHistoryFunc: method is nil but IStorage.GetCoinHistory was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Ir     *InfoResponse
+		ID     int
+		Filter HistoryFilter
+	}{
+		Ctx:    ctx,
+		Ir:     ir,
+		ID:     id,
+		Filter: filter,
+	}
+	mock.lockGetCoinHistory.Lock()
+	mock.calls.GetCoinHistory = append(mock.calls.GetCoinHistory, callInfo)
+	mock.lockGetCoinHistory.Unlock()
+	return mock.GetCoinHistoryFunc(ctx, ir, id, filter)
+}
+
+// GetCoinHistoryCalls gets all the calls that were made to GetCoinHistory.
+// Check the length with:
+//
+//	len(mockedIStorage.GetCoinHistoryCalls())
+func (mock *IStorageMock) GetCoinHistoryCalls() []struct {
+	Ctx    context.Context
+	Ir     *InfoResponse
+	ID     int
+	Filter HistoryFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Ir     *InfoResponse
+		ID     int
+		Filter HistoryFilter
+	}
+	mock.lockGetCoinHistory.RLock()
+	calls = mock.calls.GetCoinHistory
+	mock.lockGetCoinHistory.RUnlock()
+	return calls
+}
+
+// GetCoinLots calls GetCoinLotsFunc.
+func (mock *IStorageMock) GetCoinLots(ctx context.Context, userID int) ([]CoinLot, error) {
+	if mock.GetCoinLotsFunc == nil {
+		panic("IStorageMock.GetCoinLotsFunc: method is nil but IStorage.GetCoinLots was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockGetCoinLots.Lock()
+	mock.calls.GetCoinLots = append(mock.calls.GetCoinLots, callInfo)
+	mock.lockGetCoinLots.Unlock()
+	return mock.GetCoinLotsFunc(ctx, userID)
+}
+
+// GetCoinLotsCalls gets all the calls that were made to GetCoinLots.
+// Check the length with:
+//
+//	len(mockedIStorage.GetCoinLotsCalls())
+func (mock *IStorageMock) GetCoinLotsCalls() []struct {
+	Ctx    context.Context
+	UserID int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int
+	}
+	mock.lockGetCoinLots.RLock()
+	calls = mock.calls.GetCoinLots
+	mock.lockGetCoinLots.RUnlock()
+	return calls
+}
+
+// GetFullInfo calls GetFullInfoFunc.
+func (mock *IStorageMock) GetFullInfo(ctx context.Context, ir *InfoResponse, username string) (int, error) {
+	if mock.GetFullInfoFunc == nil {
+		panic("IStorageMock.GetFullInfoFunc: method is nil but IStorage.GetFullInfo was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Ir       *InfoResponse
+		Username string
+	}{
+		Ctx:      ctx,
+		Ir:       ir,
+		Username: username,
+	}
+	mock.lockGetFullInfo.Lock()
+	mock.calls.GetFullInfo = append(mock.calls.GetFullInfo, callInfo)
+	mock.lockGetFullInfo.Unlock()
+	return mock.GetFullInfoFunc(ctx, ir, username)
+}
+
+// GetFullInfoCalls gets all the calls that were made to GetFullInfo.
+// Check the length with:
+//
+//	len(mockedIStorage.GetFullInfoCalls())
+func (mock *IStorageMock) GetFullInfoCalls() []struct {
+	Ctx      context.Context
+	Ir       *InfoResponse
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Ir       *InfoResponse
+		Username string
+	}
+	mock.lockGetFullInfo.RLock()
+	calls = mock.calls.GetFullInfo
+	mock.lockGetFullInfo.RUnlock()
+	return calls
+}
+
+// GetGroup calls GetGroupFunc.
+func (mock *IStorageMock) GetGroup(ctx context.Context, name string) (*Group, error) {
+	if mock.GetGroupFunc == nil {
+		panic("IStorageMock.GetGroupFunc: method is nil but IStorage.GetGroup was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Name string
+	}{
+		Ctx:  ctx,
+		Name: name,
+	}
+	mock.lockGetGroup.Lock()
+	mock.calls.GetGroup = append(mock.calls.GetGroup, callInfo)
+	mock.lockGetGroup.Unlock()
+	return mock.GetGroupFunc(ctx, name)
+}
+
+// GetGroupCalls gets all the calls that were made to GetGroup.
+// Check the length with:
+//
+//	len(mockedIStorage.GetGroupCalls())
+func (mock *IStorageMock) GetGroupCalls() []struct {
+	Ctx  context.Context
+	Name string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Name string
+	}
+	mock.lockGetGroup.RLock()
+	calls = mock.calls.GetGroup
+	mock.lockGetGroup.RUnlock()
+	return calls
+}
+
+// GetGroupMemberRole calls GetGroupMemberRoleFunc.
+func (mock *IStorageMock) GetGroupMemberRole(ctx context.Context, groupID int, userID int) (string, error) {
+	if mock.GetGroupMemberRoleFunc == nil {
+		panic("IStorageMock.GetGroupMemberRoleFunc: method is nil but IStorage.GetGroupMemberRole was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		GroupID int
+		UserID  int
+	}{
+		Ctx:     ctx,
+		GroupID: groupID,
+		UserID:  userID,
+	}
+	mock.lockGetGroupMemberRole.Lock()
+	mock.calls.GetGroupMemberRole = append(mock.calls.GetGroupMemberRole, callInfo)
+	mock.lockGetGroupMemberRole.Unlock()
+	return mock.GetGroupMemberRoleFunc(ctx, groupID, userID)
+}
+
+// GetGroupMemberRoleCalls gets all the calls that were made to GetGroupMemberRole.
+// Check the length with:
+//
+//	len(mockedIStorage.GetGroupMemberRoleCalls())
+func (mock *IStorageMock) GetGroupMemberRoleCalls() []struct {
+	Ctx     context.Context
+	GroupID int
+	UserID  int
+} {
+	var calls []struct {
+		Ctx     context.Context
+		GroupID int
+		UserID  int
+	}
+	mock.lockGetGroupMemberRole.RLock()
+	calls = mock.calls.GetGroupMemberRole
+	mock.lockGetGroupMemberRole.RUnlock()
+	return calls
+}
+
+// GetGroupPurchase calls GetGroupPurchaseFunc.
+func (mock *IStorageMock) GetGroupPurchase(ctx context.Context, id int) (*GroupPurchase, error) {
+	if mock.GetGroupPurchaseFunc == nil {
+		panic("IStorageMock.GetGroupPurchaseFunc: method is nil but IStorage.GetGroupPurchase was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetGroupPurchase.Lock()
+	mock.calls.GetGroupPurchase = append(mock.calls.GetGroupPurchase, callInfo)
+	mock.lockGetGroupPurchase.Unlock()
+	return mock.GetGroupPurchaseFunc(ctx, id)
+}
+
+// GetGroupPurchaseCalls gets all the calls that were made to GetGroupPurchase.
+// Check the length with:
+//
+//	len(mockedIStorage.GetGroupPurchaseCalls())
+func (mock *IStorageMock) GetGroupPurchaseCalls() []struct {
+	Ctx context.Context
+	ID  int
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int
+	}
+	mock.lockGetGroupPurchase.RLock()
+	calls = mock.calls.GetGroupPurchase
+	mock.lockGetGroupPurchase.RUnlock()
+	return calls
+}
+
+// GetInfo calls GetInfoFunc.
+func (mock *IStorageMock) GetInfo(ctx context.Context, ir *InfoResponse, username string) (int, error) {
+	if mock.GetInfoFunc == nil {
+		panic("IStorageMock.GetInfoFunc: method is nil but IStorage.GetInfo was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Ir       *InfoResponse
+		Username string
+	}{
+		Ctx:      ctx,
+		Ir:       ir,
+		Username: username,
+	}
+	mock.lockGetInfo.Lock()
+	mock.calls.GetInfo = append(mock.calls.GetInfo, callInfo)
+	mock.lockGetInfo.Unlock()
+	return mock.GetInfoFunc(ctx, ir, username)
+}
+
+// GetInfoCalls gets all the calls that were made to GetInfo.
+// Check the length with:
+//
+//	len(mockedIStorage.GetInfoCalls())
+func (mock *IStorageMock) GetInfoCalls() []struct {
+	Ctx      context.Context
+	Ir       *InfoResponse
+	Username string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Ir       *InfoResponse
+		Username string
+	}
+	mock.lockGetInfo.RLock()
+	calls = mock.calls.GetInfo
+	mock.lockGetInfo.RUnlock()
+	return calls
+}
+
+// GetInventory calls GetInventoryFunc.
+func (mock *IStorageMock) GetInventory(ctx context.Context, ir *InfoResponse, id int) error {
+	if mock.GetInventoryFunc == nil {
+		panic("IStorageMock.GetInventoryFunc: method is nil but IStorage.GetInventory was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Ir  *InfoResponse
+		ID  int
+	}{
+		Ctx: ctx,
+		Ir:  ir,
+		ID:  id,
+	}
+	mock.lockGetInventory.Lock()
+	mock.calls.GetInventory = append(mock.calls.GetInventory, callInfo)
+	mock.lockGetInventory.Unlock()
+	return mock.GetInventoryFunc(ctx, ir, id)
+}
+
+// GetInventoryCalls gets all the calls that were made to GetInventory.
+// Check the length with:
+//
+//	len(mockedIStorage.GetInventoryCalls())
+func (mock *IStorageMock) GetInventoryCalls() []struct {
+	Ctx context.Context
+	Ir  *InfoResponse
+	ID  int
+} {
+	var calls []struct {
+		Ctx context.Context
+		Ir  *InfoResponse
+		ID  int
+	}
+	mock.lockGetInventory.RLock()
+	calls = mock.calls.GetInventory
+	mock.lockGetInventory.RUnlock()
+	return calls
+}
+
+// GetItemPrice calls GetItemPriceFunc.
+func (mock *IStorageMock) GetItemPrice(ctx context.Context, id int) (*ItemPrice, error) {
+	if mock.GetItemPriceFunc == nil {
+		panic("IStorageMock.GetItemPriceFunc: method is nil but IStorage.GetItemPrice was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetItemPrice.Lock()
+	mock.calls.GetItemPrice = append(mock.calls.GetItemPrice, callInfo)
+	mock.lockGetItemPrice.Unlock()
+	return mock.GetItemPriceFunc(ctx, id)
+}
+
+// GetItemPriceCalls gets all the calls that were made to GetItemPrice.
+// Check the length with:
+//
+//	len(mockedIStorage.GetItemPriceCalls())
+func (mock *IStorageMock) GetItemPriceCalls() []struct {
+	Ctx context.Context
+	ID  int
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int
+	}
+	mock.lockGetItemPrice.RLock()
+	calls = mock.calls.GetItemPrice
+	mock.lockGetItemPrice.RUnlock()
+	return calls
+}
+
+// GetLeaderboard calls GetLeaderboardFunc.
+func (mock *IStorageMock) GetLeaderboard(ctx context.Context, board string, since *time.Time, limit int) ([]LeaderboardEntry, error) {
+	if mock.GetLeaderboardFunc == nil {
+		panic("IStorageMock.GetLeaderboardFunc: method is nil but IStorage.GetLeaderboard was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Board string
+		Since *time.Time
+		Limit int
+	}{
+		Ctx:   ctx,
+		Board: board,
+		Since: since,
+		Limit: limit,
+	}
+	mock.lockGetLeaderboard.Lock()
+	mock.calls.GetLeaderboard = append(mock.calls.GetLeaderboard, callInfo)
+	mock.lockGetLeaderboard.Unlock()
+	return mock.GetLeaderboardFunc(ctx, board, since, limit)
+}
+
+// GetLeaderboardCalls gets all the calls that were made to GetLeaderboard.
+// Check the length with:
+//
+//	len(mockedIStorage.GetLeaderboardCalls())
+func (mock *IStorageMock) GetLeaderboardCalls() []struct {
+	Ctx   context.Context
+	Board string
+	Since *time.Time
+	Limit int
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Board string
+		Since *time.Time
+		Limit int
+	}
+	mock.lockGetLeaderboard.RLock()
+	calls = mock.calls.GetLeaderboard
+	mock.lockGetLeaderboard.RUnlock()
+	return calls
+}
+
+// GetPaymentRequest calls GetPaymentRequestFunc.
+func (mock *IStorageMock) GetPaymentRequest(ctx context.Context, id int) (*PaymentRequest, error) {
+	if mock.GetPaymentRequestFunc == nil {
+		panic("IStorageMock.GetPaymentRequestFunc: method is nil but IStorage.GetPaymentRequest was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetPaymentRequest.Lock()
+	mock.calls.GetPaymentRequest = append(mock.calls.GetPaymentRequest, callInfo)
+	mock.lockGetPaymentRequest.Unlock()
+	return mock.GetPaymentRequestFunc(ctx, id)
+}
+
+// GetPaymentRequestCalls gets all the calls that were made to GetPaymentRequest.
+// Check the length with:
+//
+//	len(mockedIStorage.GetPaymentRequestCalls())
+func (mock *IStorageMock) GetPaymentRequestCalls() []struct {
+	Ctx context.Context
+	ID  int
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int
+	}
+	mock.lockGetPaymentRequest.RLock()
+	calls = mock.calls.GetPaymentRequest
+	mock.lockGetPaymentRequest.RUnlock()
+	return calls
+}
+
+// GetPendingTransfer calls GetPendingTransferFunc.
+func (mock *IStorageMock) GetPendingTransfer(ctx context.Context, id int) (*PendingTransfer, error) {
+	if mock.GetPendingTransferFunc == nil {
+		panic("IStorageMock.GetPendingTransferFunc: method is nil but IStorage.GetPendingTransfer was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetPendingTransfer.Lock()
+	mock.calls.GetPendingTransfer = append(mock.calls.GetPendingTransfer, callInfo)
+	mock.lockGetPendingTransfer.Unlock()
+	return mock.GetPendingTransferFunc(ctx, id)
+}
+
+// GetPendingTransferCalls gets all the calls that were made to GetPendingTransfer.
+// Check the length with:
+//
+//	len(mockedIStorage.GetPendingTransferCalls())
+func (mock *IStorageMock) GetPendingTransferCalls() []struct {
+	Ctx context.Context
+	ID  int
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int
+	}
+	mock.lockGetPendingTransfer.RLock()
+	calls = mock.calls.GetPendingTransfer
+	mock.lockGetPendingTransfer.RUnlock()
+	return calls
+}
+
+// GetPromoCode calls GetPromoCodeFunc.
+func (mock *IStorageMock) GetPromoCode(ctx context.Context, code string) (*PromoCode, error) {
+	if mock.GetPromoCodeFunc == nil {
+		panic("IStorageMock.GetPromoCodeFunc: method is nil but IStorage.GetPromoCode was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Code string
+	}{
+		Ctx:  ctx,
+		Code: code,
+	}
+	mock.lockGetPromoCode.Lock()
+	mock.calls.GetPromoCode = append(mock.calls.GetPromoCode, callInfo)
+	mock.lockGetPromoCode.Unlock()
+	return mock.GetPromoCodeFunc(ctx, code)
+}
+
+// GetPromoCodeCalls gets all the calls that were made to GetPromoCode.
+// Check the length with:
+//
+//	len(mockedIStorage.GetPromoCodeCalls())
+func (mock *IStorageMock) GetPromoCodeCalls() []struct {
+	Ctx  context.Context
+	Code string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Code string
+	}
+	mock.lockGetPromoCode.RLock()
+	calls = mock.calls.GetPromoCode
+	mock.lockGetPromoCode.RUnlock()
+	return calls
+}
+
+// GetReceivedHistory calls GetReceivedHistoryFunc.
+func (mock *IStorageMock) GetReceivedHistory(ctx context.Context, ir *InfoResponse, id int) error {
+	if mock.GetReceivedHistoryFunc == nil {
+		panic("IStorageMock.GetReceivedHistoryFunc: method is nil but IStorage.GetReceivedHistory was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Ir  *InfoResponse
+		ID  int
+	}{
+		Ctx: ctx,
+		Ir:  ir,
+		ID:  id,
+	}
+	mock.lockGetReceivedHistory.Lock()
+	mock.calls.GetReceivedHistory = append(mock.calls.GetReceivedHistory, callInfo)
+	mock.lockGetReceivedHistory.Unlock()
+	return mock.GetReceivedHistoryFunc(ctx, ir, id)
+}
+
+// GetReceivedHistoryCalls gets all the calls that were made to GetReceivedHistory.
+// Check the length with:
+//
+//	len(mockedIStorage.GetReceivedHistoryCalls())
+func (mock *IStorageMock) GetReceivedHistoryCalls() []struct {
+	Ctx context.Context
+	Ir  *InfoResponse
+	ID  int
+} {
+	var calls []struct {
+		Ctx context.Context
+		Ir  *InfoResponse
+		ID  int
+	}
+	mock.lockGetReceivedHistory.RLock()
+	calls = mock.calls.GetReceivedHistory
+	mock.lockGetReceivedHistory.RUnlock()
+	return calls
+}
+
+// GetScheduledTransfer calls GetScheduledTransferFunc.
+func (mock *IStorageMock) GetScheduledTransfer(ctx context.Context, id int) (*ScheduledTransfer, error) {
+	if mock.GetScheduledTransferFunc == nil {
+		panic("IStorageMock.GetScheduledTransferFunc: method is nil but IStorage.GetScheduledTransfer was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetScheduledTransfer.Lock()
+	mock.calls.GetScheduledTransfer = append(mock.calls.GetScheduledTransfer, callInfo)
+	mock.lockGetScheduledTransfer.Unlock()
+	return mock.GetScheduledTransferFunc(ctx, id)
+}
+
+// GetScheduledTransferCalls gets all the calls that were made to GetScheduledTransfer.
+// Check the length with:
+//
+//	len(mockedIStorage.GetScheduledTransferCalls())
+func (mock *IStorageMock) GetScheduledTransferCalls() []struct {
+	Ctx context.Context
+	ID  int
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int
+	}
+	mock.lockGetScheduledTransfer.RLock()
+	calls = mock.calls.GetScheduledTransfer
+	mock.lockGetScheduledTransfer.RUnlock()
+	return calls
+}
+
+// GetSendHistory calls GetSendHistoryFunc.
+func (mock *IStorageMock) GetSendHistory(ctx context.Context, ir *InfoResponse, id int) error {
+	if mock.GetSendHistoryFunc == nil {
+		panic("IStorageMock.GetSendHistoryFunc: method is nil but IStorage.GetSendHistory was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Ir  *InfoResponse
+		ID  int
+	}{
+		Ctx: ctx,
+		Ir:  ir,
+		ID:  id,
+	}
+	mock.lockGetSendHistory.Lock()
+	mock.calls.GetSendHistory = append(mock.calls.GetSendHistory, callInfo)
+	mock.lockGetSendHistory.Unlock()
+	return mock.GetSendHistoryFunc(ctx, ir, id)
+}
+
+// GetSendHistoryCalls gets all the calls that were made to GetSendHistory.
+// Check the length with:
+//
+//	len(mockedIStorage.GetSendHistoryCalls())
+func (mock *IStorageMock) GetSendHistoryCalls() []struct {
+	Ctx context.Context
+	Ir  *InfoResponse
+	ID  int
+} {
+	var calls []struct {
+		Ctx context.Context
+		Ir  *InfoResponse
+		ID  int
+	}
+	mock.lockGetSendHistory.RLock()
+	calls = mock.calls.GetSendHistory
+	mock.lockGetSendHistory.RUnlock()
+	return calls
+}
+
+// GetTransferTotals calls GetTransferTotalsFunc.
+func (mock *IStorageMock) GetTransferTotals(ctx context.Context, userID int, since time.Time) (int, int, error) {
+	if mock.GetTransferTotalsFunc == nil {
+		panic("IStorageMock.GetTransferTotalsFunc: method is nil but IStorage.GetTransferTotals was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int
+		Since  time.Time
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Since:  since,
+	}
+	mock.lockGetTransferTotals.Lock()
+	mock.calls.GetTransferTotals = append(mock.calls.GetTransferTotals, callInfo)
+	mock.lockGetTransferTotals.Unlock()
+	return mock.GetTransferTotalsFunc(ctx, userID, since)
+}
+
+// GetTransferTotalsCalls gets all the calls that were made to GetTransferTotals.
+// Check the length with:
+//
+//	len(mockedIStorage.GetTransferTotalsCalls())
+func (mock *IStorageMock) GetTransferTotalsCalls() []struct {
+	Ctx    context.Context
+	UserID int
+	Since  time.Time
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int
+		Since  time.Time
+	}
+	mock.lockGetTransferTotals.RLock()
+	calls = mock.calls.GetTransferTotals
+	mock.lockGetTransferTotals.RUnlock()
+	return calls
+}
+
+// GetUserRole calls GetUserRoleFunc.
+func (mock *IStorageMock) GetUserRole(ctx context.Context, id int) (string, error) {
+	if mock.GetUserRoleFunc == nil {
+		panic("IStorageMock.GetUserRoleFunc: method is nil but IStorage.GetUserRole was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  int
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockGetUserRole.Lock()
+	mock.calls.GetUserRole = append(mock.calls.GetUserRole, callInfo)
+	mock.lockGetUserRole.Unlock()
+	return mock.GetUserRoleFunc(ctx, id)
+}
+
+// GetUserRoleCalls gets all the calls that were made to GetUserRole.
+// Check the length with:
+//
+//	len(mockedIStorage.GetUserRoleCalls())
+func (mock *IStorageMock) GetUserRoleCalls() []struct {
+	Ctx context.Context
+	ID  int
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  int
+	}
+	mock.lockGetUserRole.RLock()
+	calls = mock.calls.GetUserRole
+	mock.lockGetUserRole.RUnlock()
+	return calls
+}
+
+// ListAchievements calls ListAchievementsFunc.
+func (mock *IStorageMock) ListAchievements(ctx context.Context, userID int) ([]Achievement, error) {
+	if mock.ListAchievementsFunc == nil {
+		panic("IStorageMock.ListAchievementsFunc: method is nil but IStorage.ListAchievements was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockListAchievements.Lock()
+	mock.calls.ListAchievements = append(mock.calls.ListAchievements, callInfo)
+	mock.lockListAchievements.Unlock()
+	return mock.ListAchievementsFunc(ctx, userID)
+}
+
+// ListAchievementsCalls gets all the calls that were made to ListAchievements.
+// Check the length with:
+//
+//	len(mockedIStorage.ListAchievementsCalls())
+func (mock *IStorageMock) ListAchievementsCalls() []struct {
+	Ctx    context.Context
+	UserID int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int
+	}
+	mock.lockListAchievements.RLock()
+	calls = mock.calls.ListAchievements
+	mock.lockListAchievements.RUnlock()
+	return calls
+}
+
+// ListAudit calls ListAuditFunc.
+func (mock *IStorageMock) ListAudit(ctx context.Context, filter AuditFilter) ([]AuditEntry, error) {
+	if mock.ListAuditFunc == nil {
+		panic("IStorageMock.ListAuditFunc: method is nil but IStorage.ListAudit was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Filter AuditFilter
+	}{
+		Ctx:    ctx,
+		Filter: filter,
+	}
+	mock.lockListAudit.Lock()
+	mock.calls.ListAudit = append(mock.calls.ListAudit, callInfo)
+	mock.lockListAudit.Unlock()
+	return mock.ListAuditFunc(ctx, filter)
+}
+
+// ListAuditCalls gets all the calls that were made to ListAudit.
+// Check the length with:
+//
+//	len(mockedIStorage.ListAuditCalls())
+func (mock *IStorageMock) ListAuditCalls() []struct {
+	Ctx    context.Context
+	Filter AuditFilter
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Filter AuditFilter
+	}
+	mock.lockListAudit.RLock()
+	calls = mock.calls.ListAudit
+	mock.lockListAudit.RUnlock()
+	return calls
+}
+
+// ListDueScheduledTransfers calls ListDueScheduledTransfersFunc.
+func (mock *IStorageMock) ListDueScheduledTransfers(ctx context.Context, now time.Time, limit int) ([]ScheduledTransfer, error) {
+	if mock.ListDueScheduledTransfersFunc == nil {
+		panic("IStorageMock.ListDueScheduledTransfersFunc: method is nil but IStorage.ListDueScheduledTransfers was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Now   time.Time
+		Limit int
+	}{
+		Ctx:   ctx,
+		Now:   now,
+		Limit: limit,
+	}
+	mock.lockListDueScheduledTransfers.Lock()
+	mock.calls.ListDueScheduledTransfers = append(mock.calls.ListDueScheduledTransfers, callInfo)
+	mock.lockListDueScheduledTransfers.Unlock()
+	return mock.ListDueScheduledTransfersFunc(ctx, now, limit)
+}
+
+// ListDueScheduledTransfersCalls gets all the calls that were made to ListDueScheduledTransfers.
+// Check the length with:
+//
+//	len(mockedIStorage.ListDueScheduledTransfersCalls())
+func (mock *IStorageMock) ListDueScheduledTransfersCalls() []struct {
+	Ctx   context.Context
+	Now   time.Time
+	Limit int
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Now   time.Time
+		Limit int
+	}
+	mock.lockListDueScheduledTransfers.RLock()
+	calls = mock.calls.ListDueScheduledTransfers
+	mock.lockListDueScheduledTransfers.RUnlock()
+	return calls
+}
+
+// ListExpiredCoins calls ListExpiredCoinsFunc.
+func (mock *IStorageMock) ListExpiredCoins(ctx context.Context, cutoff time.Time, limit int) ([]ExpiredCoins, error) {
+	if mock.ListExpiredCoinsFunc == nil {
+		panic("IStorageMock.ListExpiredCoinsFunc: method is nil but IStorage.ListExpiredCoins was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		Cutoff time.Time
+		Limit  int
+	}{
+		Ctx:    ctx,
+		Cutoff: cutoff,
+		Limit:  limit,
+	}
+	mock.lockListExpiredCoins.Lock()
+	mock.calls.ListExpiredCoins = append(mock.calls.ListExpiredCoins, callInfo)
+	mock.lockListExpiredCoins.Unlock()
+	return mock.ListExpiredCoinsFunc(ctx, cutoff, limit)
+}
+
+// ListExpiredCoinsCalls gets all the calls that were made to ListExpiredCoins.
+// Check the length with:
+//
+//	len(mockedIStorage.ListExpiredCoinsCalls())
+func (mock *IStorageMock) ListExpiredCoinsCalls() []struct {
+	Ctx    context.Context
+	Cutoff time.Time
+	Limit  int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		Cutoff time.Time
+		Limit  int
+	}
+	mock.lockListExpiredCoins.RLock()
+	calls = mock.calls.ListExpiredCoins
+	mock.lockListExpiredCoins.RUnlock()
+	return calls
+}
+
+// ListExpiredPendingTransfers calls ListExpiredPendingTransfersFunc.
+func (mock *IStorageMock) ListExpiredPendingTransfers(ctx context.Context, now time.Time, limit int) ([]PendingTransfer, error) {
+	if mock.ListExpiredPendingTransfersFunc == nil {
+		panic("IStorageMock.ListExpiredPendingTransfersFunc: method is nil but IStorage.ListExpiredPendingTransfers was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Now   time.Time
+		Limit int
+	}{
+		Ctx:   ctx,
+		Now:   now,
+		Limit: limit,
+	}
+	mock.lockListExpiredPendingTransfers.Lock()
+	mock.calls.ListExpiredPendingTransfers = append(mock.calls.ListExpiredPendingTransfers, callInfo)
+	mock.lockListExpiredPendingTransfers.Unlock()
+	return mock.ListExpiredPendingTransfersFunc(ctx, now, limit)
+}
+
+// ListExpiredPendingTransfersCalls gets all the calls that were made to ListExpiredPendingTransfers.
+// Check the length with:
+//
+//	len(mockedIStorage.ListExpiredPendingTransfersCalls())
+func (mock *IStorageMock) ListExpiredPendingTransfersCalls() []struct {
+	Ctx   context.Context
+	Now   time.Time
+	Limit int
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Now   time.Time
+		Limit int
+	}
+	mock.lockListExpiredPendingTransfers.RLock()
+	calls = mock.calls.ListExpiredPendingTransfers
+	mock.lockListExpiredPendingTransfers.RUnlock()
+	return calls
+}
+
+// ListGroupMembers calls ListGroupMembersFunc.
+func (mock *IStorageMock) ListGroupMembers(ctx context.Context, groupID int) ([]GroupMember, error) {
+	if mock.ListGroupMembersFunc == nil {
+		panic("IStorageMock.ListGroupMembersFunc: method is nil but IStorage.ListGroupMembers was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		GroupID int
+	}{
+		Ctx:     ctx,
+		GroupID: groupID,
+	}
+	mock.lockListGroupMembers.Lock()
+	mock.calls.ListGroupMembers = append(mock.calls.ListGroupMembers, callInfo)
+	mock.lockListGroupMembers.Unlock()
+	return mock.ListGroupMembersFunc(ctx, groupID)
+}
+
+// ListGroupMembersCalls gets all the calls that were made to ListGroupMembers.
+// Check the length with:
+//
+//	len(mockedIStorage.ListGroupMembersCalls())
+func (mock *IStorageMock) ListGroupMembersCalls() []struct {
+	Ctx     context.Context
+	GroupID int
+} {
+	var calls []struct {
+		Ctx     context.Context
+		GroupID int
+	}
+	mock.lockListGroupMembers.RLock()
+	calls = mock.calls.ListGroupMembers
+	mock.lockListGroupMembers.RUnlock()
 	return calls
 }
 
-// GetInfo calls GetInfoFunc.
-func (mock *IStorageMock) GetInfo(ir *InfoResponse, username string) (int, error) {
-	if mock.GetInfoFunc == nil {
-		panic("IStorageMock.GetInfoFunc: method is nil but IStorage.GetInfo was just called")
+// ListGroupPurchases calls ListGroupPurchasesFunc.
+func (mock *IStorageMock) ListGroupPurchases(ctx context.Context, groupID int) ([]GroupPurchase, error) {
+	if mock.ListGroupPurchasesFunc == nil {
+		panic("IStorageMock.ListGroupPurchasesFunc: method is nil but IStorage.ListGroupPurchases was just called")
 	}
 	callInfo := struct {
-		Ir       *InfoResponse
-		Username string
+		Ctx     context.Context
+		GroupID int
 	}{
-		Ir:       ir,
-		Username: username,
+		Ctx:     ctx,
+		GroupID: groupID,
 	}
-	mock.lockGetInfo.Lock()
-	mock.calls.GetInfo = append(mock.calls.GetInfo, callInfo)
-	mock.lockGetInfo.Unlock()
-	return mock.GetInfoFunc(ir, username)
+	mock.lockListGroupPurchases.Lock()
+	mock.calls.ListGroupPurchases = append(mock.calls.ListGroupPurchases, callInfo)
+	mock.lockListGroupPurchases.Unlock()
+	return mock.ListGroupPurchasesFunc(ctx, groupID)
 }
 
-// GetInfoCalls gets all the calls that were made to GetInfo.
+// ListGroupPurchasesCalls gets all the calls that were made to ListGroupPurchases.
 // Check the length with:
 //
-//	len(mockedIStorage.GetInfoCalls())
-func (mock *IStorageMock) GetInfoCalls() []struct {
-	Ir       *InfoResponse
-	Username string
+//	len(mockedIStorage.ListGroupPurchasesCalls())
+func (mock *IStorageMock) ListGroupPurchasesCalls() []struct {
+	Ctx     context.Context
+	GroupID int
 } {
 	var calls []struct {
-		Ir       *InfoResponse
-		Username string
+		Ctx     context.Context
+		GroupID int
 	}
-	mock.lockGetInfo.RLock()
-	calls = mock.calls.GetInfo
-	mock.lockGetInfo.RUnlock()
+	mock.lockListGroupPurchases.RLock()
+	calls = mock.calls.ListGroupPurchases
+	mock.lockListGroupPurchases.RUnlock()
 	return calls
 }
 
-// GetInventory calls GetInventoryFunc.
-func (mock *IStorageMock) GetInventory(ir *InfoResponse, id int) error {
-	if mock.GetInventoryFunc == nil {
-		panic("IStorageMock.GetInventoryFunc: method is nil but IStorage.GetInventory was just called")
+// ListItemPrices calls ListItemPricesFunc.
+func (mock *IStorageMock) ListItemPrices(ctx context.Context, now time.Time) ([]ItemPrice, error) {
+	if mock.ListItemPricesFunc == nil {
+		panic("IStorageMock.ListItemPricesFunc: method is nil but IStorage.ListItemPrices was just called")
 	}
 	callInfo := struct {
-		Ir *InfoResponse
-		ID int
+		Ctx context.Context
+		Now time.Time
 	}{
-		Ir: ir,
-		ID: id,
+		Ctx: ctx,
+		Now: now,
 	}
-	mock.lockGetInventory.Lock()
-	mock.calls.GetInventory = append(mock.calls.GetInventory, callInfo)
-	mock.lockGetInventory.Unlock()
-	return mock.GetInventoryFunc(ir, id)
+	mock.lockListItemPrices.Lock()
+	mock.calls.ListItemPrices = append(mock.calls.ListItemPrices, callInfo)
+	mock.lockListItemPrices.Unlock()
+	return mock.ListItemPricesFunc(ctx, now)
 }
 
-// GetInventoryCalls gets all the calls that were made to GetInventory.
+// ListItemPricesCalls gets all the calls that were made to ListItemPrices.
 // Check the length with:
 //
-//	len(mockedIStorage.GetInventoryCalls())
-func (mock *IStorageMock) GetInventoryCalls() []struct {
-	Ir *InfoResponse
-	ID int
+//	len(mockedIStorage.ListItemPricesCalls())
+func (mock *IStorageMock) ListItemPricesCalls() []struct {
+	Ctx context.Context
+	Now time.Time
 } {
 	var calls []struct {
-		Ir *InfoResponse
-		ID int
+		Ctx context.Context
+		Now time.Time
 	}
-	mock.lockGetInventory.RLock()
-	calls = mock.calls.GetInventory
-	mock.lockGetInventory.RUnlock()
+	mock.lockListItemPrices.RLock()
+	calls = mock.calls.ListItemPrices
+	mock.lockListItemPrices.RUnlock()
 	return calls
 }
 
-// GetReceivedHistory calls GetReceivedHistoryFunc.
-func (mock *IStorageMock) GetReceivedHistory(ir *InfoResponse, id int) error {
-	if mock.GetReceivedHistoryFunc == nil {
-		panic("IStorageMock.GetReceivedHistoryFunc: method is nil but IStorage.GetReceivedHistory was just called")
+// ListOrders calls ListOrdersFunc.
+func (mock *IStorageMock) ListOrders(ctx context.Context, userID int) ([]Order, error) {
+	if mock.ListOrdersFunc == nil {
+		panic("IStorageMock.ListOrdersFunc: method is nil but IStorage.ListOrders was just called")
 	}
 	callInfo := struct {
-		Ir *InfoResponse
-		ID int
+		Ctx    context.Context
+		UserID int
 	}{
-		Ir: ir,
-		ID: id,
+		Ctx:    ctx,
+		UserID: userID,
 	}
-	mock.lockGetReceivedHistory.Lock()
-	mock.calls.GetReceivedHistory = append(mock.calls.GetReceivedHistory, callInfo)
-	mock.lockGetReceivedHistory.Unlock()
-	return mock.GetReceivedHistoryFunc(ir, id)
+	mock.lockListOrders.Lock()
+	mock.calls.ListOrders = append(mock.calls.ListOrders, callInfo)
+	mock.lockListOrders.Unlock()
+	return mock.ListOrdersFunc(ctx, userID)
 }
 
-// GetReceivedHistoryCalls gets all the calls that were made to GetReceivedHistory.
+// ListOrdersCalls gets all the calls that were made to ListOrders.
 // Check the length with:
 //
-//	len(mockedIStorage.GetReceivedHistoryCalls())
-func (mock *IStorageMock) GetReceivedHistoryCalls() []struct {
-	Ir *InfoResponse
-	ID int
+//	len(mockedIStorage.ListOrdersCalls())
+func (mock *IStorageMock) ListOrdersCalls() []struct {
+	Ctx    context.Context
+	UserID int
 } {
 	var calls []struct {
-		Ir *InfoResponse
-		ID int
+		Ctx    context.Context
+		UserID int
 	}
-	mock.lockGetReceivedHistory.RLock()
-	calls = mock.calls.GetReceivedHistory
-	mock.lockGetReceivedHistory.RUnlock()
+	mock.lockListOrders.RLock()
+	calls = mock.calls.ListOrders
+	mock.lockListOrders.RUnlock()
 	return calls
 }
 
-// GetSendHistory calls GetSendHistoryFunc.
-func (mock *IStorageMock) GetSendHistory(ir *InfoResponse, id int) error {
-	if mock.GetSendHistoryFunc == nil {
-		panic("IStorageMock.GetSendHistoryFunc: method is nil but IStorage.GetSendHistory was just called")
+// ListPendingPaymentRequests calls ListPendingPaymentRequestsFunc.
+func (mock *IStorageMock) ListPendingPaymentRequests(ctx context.Context, userID int, incoming bool, now time.Time) ([]PaymentRequest, error) {
+	if mock.ListPendingPaymentRequestsFunc == nil {
+		panic("IStorageMock.ListPendingPaymentRequestsFunc: method is nil but IStorage.ListPendingPaymentRequests was just called")
 	}
 	callInfo := struct {
-		Ir *InfoResponse
-		ID int
+		Ctx      context.Context
+		UserID   int
+		Incoming bool
+		Now      time.Time
 	}{
-		Ir: ir,
-		ID: id,
+		Ctx:      ctx,
+		UserID:   userID,
+		Incoming: incoming,
+		Now:      now,
 	}
-	mock.lockGetSendHistory.Lock()
-	mock.calls.GetSendHistory = append(mock.calls.GetSendHistory, callInfo)
-	mock.lockGetSendHistory.Unlock()
-	return mock.GetSendHistoryFunc(ir, id)
+	mock.lockListPendingPaymentRequests.Lock()
+	mock.calls.ListPendingPaymentRequests = append(mock.calls.ListPendingPaymentRequests, callInfo)
+	mock.lockListPendingPaymentRequests.Unlock()
+	return mock.ListPendingPaymentRequestsFunc(ctx, userID, incoming, now)
 }
 
-// GetSendHistoryCalls gets all the calls that were made to GetSendHistory.
+// ListPendingPaymentRequestsCalls gets all the calls that were made to ListPendingPaymentRequests.
 // Check the length with:
 //
-//	len(mockedIStorage.GetSendHistoryCalls())
-func (mock *IStorageMock) GetSendHistoryCalls() []struct {
-	Ir *InfoResponse
-	ID int
+//	len(mockedIStorage.ListPendingPaymentRequestsCalls())
+func (mock *IStorageMock) ListPendingPaymentRequestsCalls() []struct {
+	Ctx      context.Context
+	UserID   int
+	Incoming bool
+	Now      time.Time
 } {
 	var calls []struct {
-		Ir *InfoResponse
-		ID int
+		Ctx      context.Context
+		UserID   int
+		Incoming bool
+		Now      time.Time
 	}
-	mock.lockGetSendHistory.RLock()
-	calls = mock.calls.GetSendHistory
-	mock.lockGetSendHistory.RUnlock()
+	mock.lockListPendingPaymentRequests.RLock()
+	calls = mock.calls.ListPendingPaymentRequests
+	mock.lockListPendingPaymentRequests.RUnlock()
+	return calls
+}
+
+// ListPendingTransfers calls ListPendingTransfersFunc.
+func (mock *IStorageMock) ListPendingTransfers(ctx context.Context, senderID int, now time.Time) ([]PendingTransfer, error) {
+	if mock.ListPendingTransfersFunc == nil {
+		panic("IStorageMock.ListPendingTransfersFunc: method is nil but IStorage.ListPendingTransfers was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		SenderID int
+		Now      time.Time
+	}{
+		Ctx:      ctx,
+		SenderID: senderID,
+		Now:      now,
+	}
+	mock.lockListPendingTransfers.Lock()
+	mock.calls.ListPendingTransfers = append(mock.calls.ListPendingTransfers, callInfo)
+	mock.lockListPendingTransfers.Unlock()
+	return mock.ListPendingTransfersFunc(ctx, senderID, now)
+}
+
+// ListPendingTransfersCalls gets all the calls that were made to ListPendingTransfers.
+// Check the length with:
+//
+//	len(mockedIStorage.ListPendingTransfersCalls())
+func (mock *IStorageMock) ListPendingTransfersCalls() []struct {
+	Ctx      context.Context
+	SenderID int
+	Now      time.Time
+} {
+	var calls []struct {
+		Ctx      context.Context
+		SenderID int
+		Now      time.Time
+	}
+	mock.lockListPendingTransfers.RLock()
+	calls = mock.calls.ListPendingTransfers
+	mock.lockListPendingTransfers.RUnlock()
+	return calls
+}
+
+// ListPromoCodes calls ListPromoCodesFunc.
+func (mock *IStorageMock) ListPromoCodes(ctx context.Context) ([]PromoCode, error) {
+	if mock.ListPromoCodesFunc == nil {
+		panic("IStorageMock.ListPromoCodesFunc: method is nil but IStorage.ListPromoCodes was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockListPromoCodes.Lock()
+	mock.calls.ListPromoCodes = append(mock.calls.ListPromoCodes, callInfo)
+	mock.lockListPromoCodes.Unlock()
+	return mock.ListPromoCodesFunc(ctx)
+}
+
+// ListPromoCodesCalls gets all the calls that were made to ListPromoCodes.
+// Check the length with:
+//
+//	len(mockedIStorage.ListPromoCodesCalls())
+func (mock *IStorageMock) ListPromoCodesCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockListPromoCodes.RLock()
+	calls = mock.calls.ListPromoCodes
+	mock.lockListPromoCodes.RUnlock()
+	return calls
+}
+
+// ListScheduledTransfers calls ListScheduledTransfersFunc.
+func (mock *IStorageMock) ListScheduledTransfers(ctx context.Context, senderID int) ([]ScheduledTransfer, error) {
+	if mock.ListScheduledTransfersFunc == nil {
+		panic("IStorageMock.ListScheduledTransfersFunc: method is nil but IStorage.ListScheduledTransfers was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		SenderID int
+	}{
+		Ctx:      ctx,
+		SenderID: senderID,
+	}
+	mock.lockListScheduledTransfers.Lock()
+	mock.calls.ListScheduledTransfers = append(mock.calls.ListScheduledTransfers, callInfo)
+	mock.lockListScheduledTransfers.Unlock()
+	return mock.ListScheduledTransfersFunc(ctx, senderID)
+}
+
+// ListScheduledTransfersCalls gets all the calls that were made to ListScheduledTransfers.
+// Check the length with:
+//
+//	len(mockedIStorage.ListScheduledTransfersCalls())
+func (mock *IStorageMock) ListScheduledTransfersCalls() []struct {
+	Ctx      context.Context
+	SenderID int
+} {
+	var calls []struct {
+		Ctx      context.Context
+		SenderID int
+	}
+	mock.lockListScheduledTransfers.RLock()
+	calls = mock.calls.ListScheduledTransfers
+	mock.lockListScheduledTransfers.RUnlock()
+	return calls
+}
+
+// ListUserGroups calls ListUserGroupsFunc.
+func (mock *IStorageMock) ListUserGroups(ctx context.Context, userID int) ([]Group, error) {
+	if mock.ListUserGroupsFunc == nil {
+		panic("IStorageMock.ListUserGroupsFunc: method is nil but IStorage.ListUserGroups was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+	}
+	mock.lockListUserGroups.Lock()
+	mock.calls.ListUserGroups = append(mock.calls.ListUserGroups, callInfo)
+	mock.lockListUserGroups.Unlock()
+	return mock.ListUserGroupsFunc(ctx, userID)
+}
+
+// ListUserGroupsCalls gets all the calls that were made to ListUserGroups.
+// Check the length with:
+//
+//	len(mockedIStorage.ListUserGroupsCalls())
+func (mock *IStorageMock) ListUserGroupsCalls() []struct {
+	Ctx    context.Context
+	UserID int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int
+	}
+	mock.lockListUserGroups.RLock()
+	calls = mock.calls.ListUserGroups
+	mock.lockListUserGroups.RUnlock()
+	return calls
+}
+
+// ListUserTransactions calls ListUserTransactionsFunc.
+func (mock *IStorageMock) ListUserTransactions(ctx context.Context, userID int, limit int) ([]Transaction, error) {
+	if mock.ListUserTransactionsFunc == nil {
+		panic("IStorageMock.ListUserTransactionsFunc: method is nil but IStorage.ListUserTransactions was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int
+		Limit  int
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		Limit:  limit,
+	}
+	mock.lockListUserTransactions.Lock()
+	mock.calls.ListUserTransactions = append(mock.calls.ListUserTransactions, callInfo)
+	mock.lockListUserTransactions.Unlock()
+	return mock.ListUserTransactionsFunc(ctx, userID, limit)
+}
+
+// ListUserTransactionsCalls gets all the calls that were made to ListUserTransactions.
+// Check the length with:
+//
+//	len(mockedIStorage.ListUserTransactionsCalls())
+func (mock *IStorageMock) ListUserTransactionsCalls() []struct {
+	Ctx    context.Context
+	UserID int
+	Limit  int
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int
+		Limit  int
+	}
+	mock.lockListUserTransactions.RLock()
+	calls = mock.calls.ListUserTransactions
+	mock.lockListUserTransactions.RUnlock()
+	return calls
+}
+
+// RemoveGroupMember calls RemoveGroupMemberFunc.
+func (mock *IStorageMock) RemoveGroupMember(ctx context.Context, groupID int, userID int) error {
+	if mock.RemoveGroupMemberFunc == nil {
+		panic("IStorageMock.RemoveGroupMemberFunc: method is nil but IStorage.RemoveGroupMember was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		GroupID int
+		UserID  int
+	}{
+		Ctx:     ctx,
+		GroupID: groupID,
+		UserID:  userID,
+	}
+	mock.lockRemoveGroupMember.Lock()
+	mock.calls.RemoveGroupMember = append(mock.calls.RemoveGroupMember, callInfo)
+	mock.lockRemoveGroupMember.Unlock()
+	return mock.RemoveGroupMemberFunc(ctx, groupID, userID)
+}
+
+// RemoveGroupMemberCalls gets all the calls that were made to RemoveGroupMember.
+// Check the length with:
+//
+//	len(mockedIStorage.RemoveGroupMemberCalls())
+func (mock *IStorageMock) RemoveGroupMemberCalls() []struct {
+	Ctx     context.Context
+	GroupID int
+	UserID  int
+} {
+	var calls []struct {
+		Ctx     context.Context
+		GroupID int
+		UserID  int
+	}
+	mock.lockRemoveGroupMember.RLock()
+	calls = mock.calls.RemoveGroupMember
+	mock.lockRemoveGroupMember.RUnlock()
+	return calls
+}
+
+// ResolvePaymentRequest calls ResolvePaymentRequestFunc.
+func (mock *IStorageMock) ResolvePaymentRequest(ctx context.Context, id int, status string, now time.Time) error {
+	if mock.ResolvePaymentRequestFunc == nil {
+		panic("IStorageMock.ResolvePaymentRequestFunc: method is nil but IStorage.ResolvePaymentRequest was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		ID     int
+		Status string
+		Now    time.Time
+	}{
+		Ctx:    ctx,
+		ID:     id,
+		Status: status,
+		Now:    now,
+	}
+	mock.lockResolvePaymentRequest.Lock()
+	mock.calls.ResolvePaymentRequest = append(mock.calls.ResolvePaymentRequest, callInfo)
+	mock.lockResolvePaymentRequest.Unlock()
+	return mock.ResolvePaymentRequestFunc(ctx, id, status, now)
+}
+
+// ResolvePaymentRequestCalls gets all the calls that were made to ResolvePaymentRequest.
+// Check the length with:
+//
+//	len(mockedIStorage.ResolvePaymentRequestCalls())
+func (mock *IStorageMock) ResolvePaymentRequestCalls() []struct {
+	Ctx    context.Context
+	ID     int
+	Status string
+	Now    time.Time
+} {
+	var calls []struct {
+		Ctx    context.Context
+		ID     int
+		Status string
+		Now    time.Time
+	}
+	mock.lockResolvePaymentRequest.RLock()
+	calls = mock.calls.ResolvePaymentRequest
+	mock.lockResolvePaymentRequest.RUnlock()
+	return calls
+}
+
+// ResolvePendingTransfer calls ResolvePendingTransferFunc.
+func (mock *IStorageMock) ResolvePendingTransfer(ctx context.Context, id int, status string, resolverID int, at time.Time, limits TransferLimits, audit *TxAudit) (*PendingTransfer, error) {
+	if mock.ResolvePendingTransferFunc == nil {
+		panic("IStorageMock.ResolvePendingTransferFunc: method is nil but IStorage.ResolvePendingTransfer was just called")
+	}
+	callInfo := struct {
+		Ctx        context.Context
+		ID         int
+		Status     string
+		ResolverID int
+		At         time.Time
+		Limits     TransferLimits
+		Audit      *TxAudit
+	}{
+		Ctx:        ctx,
+		ID:         id,
+		Status:     status,
+		ResolverID: resolverID,
+		At:         at,
+		Limits:     limits,
+		Audit:      audit,
+	}
+	mock.lockResolvePendingTransfer.Lock()
+	mock.calls.ResolvePendingTransfer = append(mock.calls.ResolvePendingTransfer, callInfo)
+	mock.lockResolvePendingTransfer.Unlock()
+	return mock.ResolvePendingTransferFunc(ctx, id, status, resolverID, at, limits, audit)
+}
+
+// ResolvePendingTransferCalls gets all the calls that were made to ResolvePendingTransfer.
+// Check the length with:
+//
+//	len(mockedIStorage.ResolvePendingTransferCalls())
+func (mock *IStorageMock) ResolvePendingTransferCalls() []struct {
+	Ctx        context.Context
+	ID         int
+	Status     string
+	ResolverID int
+	At         time.Time
+	Limits     TransferLimits
+	Audit      *TxAudit
+} {
+	var calls []struct {
+		Ctx        context.Context
+		ID         int
+		Status     string
+		ResolverID int
+		At         time.Time
+		Limits     TransferLimits
+		Audit      *TxAudit
+	}
+	mock.lockResolvePendingTransfer.RLock()
+	calls = mock.calls.ResolvePendingTransfer
+	mock.lockResolvePendingTransfer.RUnlock()
+	return calls
+}
+
+// ReverseTransaction calls ReverseTransactionFunc.
+func (mock *IStorageMock) ReverseTransaction(ctx context.Context, rv *TransferReversal, audit *TxAudit) error {
+	if mock.ReverseTransactionFunc == nil {
+		panic("IStorageMock.ReverseTransactionFunc: method is nil but IStorage.ReverseTransaction was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Rv    *TransferReversal
+		Audit *TxAudit
+	}{
+		Ctx:   ctx,
+		Rv:    rv,
+		Audit: audit,
+	}
+	mock.lockReverseTransaction.Lock()
+	mock.calls.ReverseTransaction = append(mock.calls.ReverseTransaction, callInfo)
+	mock.lockReverseTransaction.Unlock()
+	return mock.ReverseTransactionFunc(ctx, rv, audit)
+}
+
+// ReverseTransactionCalls gets all the calls that were made to ReverseTransaction.
+// Check the length with:
+//
+//	len(mockedIStorage.ReverseTransactionCalls())
+func (mock *IStorageMock) ReverseTransactionCalls() []struct {
+	Ctx   context.Context
+	Rv    *TransferReversal
+	Audit *TxAudit
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Rv    *TransferReversal
+		Audit *TxAudit
+	}
+	mock.lockReverseTransaction.RLock()
+	calls = mock.calls.ReverseTransaction
+	mock.lockReverseTransaction.RUnlock()
+	return calls
+}
+
+// RunScheduledTransfer calls RunScheduledTransferFunc.
+func (mock *IStorageMock) RunScheduledTransfer(ctx context.Context, st *ScheduledTransfer, next *time.Time, now time.Time, limits TransferLimits, fee TransferFee, audit *TxAudit) error {
+	if mock.RunScheduledTransferFunc == nil {
+		panic("IStorageMock.RunScheduledTransferFunc: method is nil but IStorage.RunScheduledTransfer was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		St     *ScheduledTransfer
+		Next   *time.Time
+		Now    time.Time
+		Limits TransferLimits
+		Fee    TransferFee
+		Audit  *TxAudit
+	}{
+		Ctx:    ctx,
+		St:     st,
+		Next:   next,
+		Now:    now,
+		Limits: limits,
+		Fee:    fee,
+		Audit:  audit,
+	}
+	mock.lockRunScheduledTransfer.Lock()
+	mock.calls.RunScheduledTransfer = append(mock.calls.RunScheduledTransfer, callInfo)
+	mock.lockRunScheduledTransfer.Unlock()
+	return mock.RunScheduledTransferFunc(ctx, st, next, now, limits, fee, audit)
+}
+
+// RunScheduledTransferCalls gets all the calls that were made to RunScheduledTransfer.
+// Check the length with:
+//
+//	len(mockedIStorage.RunScheduledTransferCalls())
+func (mock *IStorageMock) RunScheduledTransferCalls() []struct {
+	Ctx    context.Context
+	St     *ScheduledTransfer
+	Next   *time.Time
+	Now    time.Time
+	Limits TransferLimits
+	Fee    TransferFee
+	Audit  *TxAudit
+} {
+	var calls []struct {
+		Ctx    context.Context
+		St     *ScheduledTransfer
+		Next   *time.Time
+		Now    time.Time
+		Limits TransferLimits
+		Fee    TransferFee
+		Audit  *TxAudit
+	}
+	mock.lockRunScheduledTransfer.RLock()
+	calls = mock.calls.RunScheduledTransfer
+	mock.lockRunScheduledTransfer.RUnlock()
 	return calls
 }
 
 // SendCoins calls SendCoinsFunc.
-func (mock *IStorageMock) SendCoins(username string, fromUserID int, toUserID int, scr *SendCoinRequest) error {
+func (mock *IStorageMock) SendCoins(ctx context.Context, username string, fromUserID int, toUserID int, scr *SendCoinRequest, limits TransferLimits, fee TransferFee, audit *TxAudit) error {
 	if mock.SendCoinsFunc == nil {
 		panic("IStorageMock.SendCoinsFunc: method is nil but IStorage.SendCoins was just called")
 	}
 	callInfo := struct {
+		Ctx        context.Context
 		Username   string
 		FromUserID int
 		ToUserID   int
 		Scr        *SendCoinRequest
+		Limits     TransferLimits
+		Fee        TransferFee
+		Audit      *TxAudit
 	}{
+		Ctx:        ctx,
 		Username:   username,
 		FromUserID: fromUserID,
 		ToUserID:   toUserID,
 		Scr:        scr,
+		Limits:     limits,
+		Fee:        fee,
+		Audit:      audit,
 	}
 	mock.lockSendCoins.Lock()
 	mock.calls.SendCoins = append(mock.calls.SendCoins, callInfo)
 	mock.lockSendCoins.Unlock()
-	return mock.SendCoinsFunc(username, fromUserID, toUserID, scr)
+	return mock.SendCoinsFunc(ctx, username, fromUserID, toUserID, scr, limits, fee, audit)
 }
 
 // SendCoinsCalls gets all the calls that were made to SendCoins.
@@ -424,19 +3598,155 @@ func (mock *IStorageMock) SendCoins(username string, fromUserID int, toUserID in
 //
 //	len(mockedIStorage.SendCoinsCalls())
 func (mock *IStorageMock) SendCoinsCalls() []struct {
+	Ctx        context.Context
 	Username   string
 	FromUserID int
 	ToUserID   int
 	Scr        *SendCoinRequest
+	Limits     TransferLimits
+	Fee        TransferFee
+	Audit      *TxAudit
 } {
 	var calls []struct {
+		Ctx        context.Context
 		Username   string
 		FromUserID int
 		ToUserID   int
 		Scr        *SendCoinRequest
+		Limits     TransferLimits
+		Fee        TransferFee
+		Audit      *TxAudit
 	}
 	mock.lockSendCoins.RLock()
 	calls = mock.calls.SendCoins
 	mock.lockSendCoins.RUnlock()
 	return calls
 }
+
+// SetGroupMember calls SetGroupMemberFunc.
+func (mock *IStorageMock) SetGroupMember(ctx context.Context, groupID int, userID int, role string, at time.Time) error {
+	if mock.SetGroupMemberFunc == nil {
+		panic("IStorageMock.SetGroupMemberFunc: method is nil but IStorage.SetGroupMember was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		GroupID int
+		UserID  int
+		Role    string
+		At      time.Time
+	}{
+		Ctx:     ctx,
+		GroupID: groupID,
+		UserID:  userID,
+		Role:    role,
+		At:      at,
+	}
+	mock.lockSetGroupMember.Lock()
+	mock.calls.SetGroupMember = append(mock.calls.SetGroupMember, callInfo)
+	mock.lockSetGroupMember.Unlock()
+	return mock.SetGroupMemberFunc(ctx, groupID, userID, role, at)
+}
+
+// SetGroupMemberCalls gets all the calls that were made to SetGroupMember.
+// Check the length with:
+//
+//	len(mockedIStorage.SetGroupMemberCalls())
+func (mock *IStorageMock) SetGroupMemberCalls() []struct {
+	Ctx     context.Context
+	GroupID int
+	UserID  int
+	Role    string
+	At      time.Time
+} {
+	var calls []struct {
+		Ctx     context.Context
+		GroupID int
+		UserID  int
+		Role    string
+		At      time.Time
+	}
+	mock.lockSetGroupMember.RLock()
+	calls = mock.calls.SetGroupMember
+	mock.lockSetGroupMember.RUnlock()
+	return calls
+}
+
+// SetLeaderboardOptOut calls SetLeaderboardOptOutFunc.
+func (mock *IStorageMock) SetLeaderboardOptOut(ctx context.Context, userID int, optOut bool) error {
+	if mock.SetLeaderboardOptOutFunc == nil {
+		panic("IStorageMock.SetLeaderboardOptOutFunc: method is nil but IStorage.SetLeaderboardOptOut was just called")
+	}
+	callInfo := struct {
+		Ctx    context.Context
+		UserID int
+		OptOut bool
+	}{
+		Ctx:    ctx,
+		UserID: userID,
+		OptOut: optOut,
+	}
+	mock.lockSetLeaderboardOptOut.Lock()
+	mock.calls.SetLeaderboardOptOut = append(mock.calls.SetLeaderboardOptOut, callInfo)
+	mock.lockSetLeaderboardOptOut.Unlock()
+	return mock.SetLeaderboardOptOutFunc(ctx, userID, optOut)
+}
+
+// SetLeaderboardOptOutCalls gets all the calls that were made to SetLeaderboardOptOut.
+// Check the length with:
+//
+//	len(mockedIStorage.SetLeaderboardOptOutCalls())
+func (mock *IStorageMock) SetLeaderboardOptOutCalls() []struct {
+	Ctx    context.Context
+	UserID int
+	OptOut bool
+} {
+	var calls []struct {
+		Ctx    context.Context
+		UserID int
+		OptOut bool
+	}
+	mock.lockSetLeaderboardOptOut.RLock()
+	calls = mock.calls.SetLeaderboardOptOut
+	mock.lockSetLeaderboardOptOut.RUnlock()
+	return calls
+}
+
+// SetUserRole calls SetUserRoleFunc.
+func (mock *IStorageMock) SetUserRole(ctx context.Context, username string, role string) error {
+	if mock.SetUserRoleFunc == nil {
+		panic("IStorageMock.SetUserRoleFunc: method is nil but IStorage.SetUserRole was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Username string
+		Role     string
+	}{
+		Ctx:      ctx,
+		Username: username,
+		Role:     role,
+	}
+	mock.lockSetUserRole.Lock()
+	mock.calls.SetUserRole = append(mock.calls.SetUserRole, callInfo)
+	mock.lockSetUserRole.Unlock()
+	return mock.SetUserRoleFunc(ctx, username, role)
+}
+
+// SetUserRoleCalls gets all the calls that were made to SetUserRole.
+// Check the length with:
+//
+//	len(mockedIStorage.SetUserRoleCalls())
+func (mock *IStorageMock) SetUserRoleCalls() []struct {
+	Ctx      context.Context
+	Username string
+	Role     string
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Username string
+		Role     string
+	}
+	mock.lockSetUserRole.RLock()
+	calls = mock.calls.SetUserRole
+	mock.lockSetUserRole.RUnlock()
+	return calls
+}
@@ -2,48 +2,82 @@ package mysql
 
 import (
 	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/service/shop/storage/migrations"
+	"avito-shop/internal/service/shop/storage/storagetest"
+	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"github.com/stretchr/testify/require"
+	"os"
 	"strconv"
 	"testing"
 
+	driver "github.com/go-sql-driver/mysql"
 	"github.com/stretchr/testify/assert"
 )
 
-func NewTestDB(t *testing.T) (*Storage, func()) {
-	dsn := "user:password@tcp(127.0.0.1:3306)/test_db"
-	//connect := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", cfg.Username, cfg.Password, cfg.Host, cfg.Port, cfg.DatabaseTest)
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		t.Fatalf("failed to connect to database: %v", err)
+// Тесты запускаются только при заданном SERVICE_TEST_MYSQL_DSN, например
+// user:password@tcp(127.0.0.1:3306)/test_db. Каждый тест получает собственную
+// временную базу, поэтому таблицы test_db не очищаются и тесты не мешают друг другу.
+func newTestStorage(tb testing.TB) *Storage {
+	dsn := os.Getenv("SERVICE_TEST_MYSQL_DSN")
+	if dsn == "" {
+		tb.Skip("SERVICE_TEST_MYSQL_DSN is not set")
 	}
-	_, err = db.Exec("TRUNCATE TABLE users;")
+
+	cfg, err := driver.ParseDSN(dsn)
 	if err != nil {
-		t.Fatalf("failed to clean up users table: %v", err)
+		tb.Fatalf("failed to parse dsn: %v", err)
 	}
-	_, err = db.Exec("TRUNCATE TABLE inventory;")
+
+	admin, err := sql.Open("mysql", cfg.FormatDSN())
 	if err != nil {
-		t.Fatalf("failed to clean up users table: %v", err)
+		tb.Fatalf("failed to connect to database: %v", err)
+	}
+	tb.Cleanup(func() { admin.Close() })
+
+	suffix := make([]byte, 6)
+	if _, err = rand.Read(suffix); err != nil {
+		tb.Fatalf("failed to generate database name: %v", err)
+	}
+	cfg.DBName = "avito_shop_test_" + hex.EncodeToString(suffix)
+	cfg.ParseTime = true
+
+	if _, err = admin.Exec("CREATE DATABASE " + cfg.DBName); err != nil {
+		tb.Fatalf("failed to create database: %v", err)
 	}
-	_, err = db.Exec("TRUNCATE TABLE transactions;")
+	tb.Cleanup(func() { admin.Exec("DROP DATABASE " + cfg.DBName) })
+
+	db, err := sql.Open("mysql", cfg.FormatDSN())
 	if err != nil {
-		t.Fatalf("failed to clean up users table: %v", err)
+		tb.Fatalf("failed to connect to database: %v", err)
 	}
+	tb.Cleanup(func() { db.Close() })
 
-	return NewStorage(db), func() {
-		db.Close()
+	if err = migrations.Apply(context.Background(), db, migrations.MySQL); err != nil {
+		tb.Fatalf("failed to migrate database: %v", err)
 	}
+
+	return NewStorage(db)
+}
+
+func BenchmarkInfo(b *testing.B) {
+	storagetest.BenchmarkInfo(b, func(tb testing.TB) storage.IStorage { return newTestStorage(tb) })
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(tb testing.TB) storage.IStorage { return newTestStorage(tb) })
 }
 
 func TestAddNewUser_Success(t *testing.T) {
-	store, cleanup := NewTestDB(t)
-	defer cleanup()
+	store := newTestStorage(t)
 
 	username := "test_user"
 	passwordHash := "hashed_password"
 
-	err := store.AddNewUser(username, passwordHash)
+	err := store.AddNewUser(context.Background(), username, passwordHash)
 	assert.NoError(t, err)
 
 	var count int
@@ -53,22 +87,20 @@ func TestAddNewUser_Success(t *testing.T) {
 }
 
 func TestAddNewUser_DuplicateUser(t *testing.T) {
-	store, cleanup := NewTestDB(t)
-	defer cleanup()
+	store := newTestStorage(t)
 
 	username := "test_user"
 	passwordHash := "hashed_password"
 
-	err := store.AddNewUser(username, passwordHash)
+	err := store.AddNewUser(context.Background(), username, passwordHash)
 	assert.NoError(t, err)
 
-	err = store.AddNewUser(username, passwordHash)
+	err = store.AddNewUser(context.Background(), username, passwordHash)
 	assert.Error(t, err)
 
 }
 func TestCheckAuth_Success(t *testing.T) {
-	store, cleanup := NewTestDB(t)
-	defer cleanup()
+	store := newTestStorage(t)
 
 	username := "test_user"
 	passwordHash := "hashed_password"
@@ -76,25 +108,23 @@ func TestCheckAuth_Success(t *testing.T) {
 	_, err := store.GetDB().Exec("INSERT INTO users (username, password_hash) VALUES (?, ?)", username, passwordHash)
 	assert.NoError(t, err)
 
-	storedPasswordHash, err := store.CheckAuth(username)
+	storedPasswordHash, err := store.CheckAuth(context.Background(), username)
 	assert.NoError(t, err)
 	assert.Equal(t, passwordHash, storedPasswordHash)
 }
 
 func TestCheckAuth_UserNotFound(t *testing.T) {
-	store, cleanup := NewTestDB(t)
-	defer cleanup()
+	store := newTestStorage(t)
 
 	username := "non_existent_user"
 
-	_, err := store.CheckAuth(username)
+	_, err := store.CheckAuth(context.Background(), username)
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, storage.ErrUserNotFound))
 }
 
 func TestGetInfo_Success(t *testing.T) {
-	store, cleanup := NewTestDB(t)
-	defer cleanup()
+	store := newTestStorage(t)
 
 	username := "test_user"
 	passwordHash := "hashed_password"
@@ -104,26 +134,24 @@ func TestGetInfo_Success(t *testing.T) {
 	assert.NoError(t, err)
 
 	var infoResponse storage.InfoResponse
-	_, err = store.GetInfo(&infoResponse, username)
+	_, err = store.GetInfo(context.Background(), &infoResponse, username)
 	assert.NoError(t, err)
 	assert.Equal(t, coins, infoResponse.Coins)
 }
 
 func TestGetInfo_UserNotFound(t *testing.T) {
-	store, cleanup := NewTestDB(t)
-	defer cleanup()
+	store := newTestStorage(t)
 
 	username := "non_existent_user"
 
 	var infoResponse storage.InfoResponse
-	_, err := store.GetInfo(&infoResponse, username)
+	_, err := store.GetInfo(context.Background(), &infoResponse, username)
 	assert.Error(t, err)
 	assert.True(t, errors.Is(err, storage.ErrUserNotFound))
 }
 
 func TestGetInventory_Success(t *testing.T) {
-	store, cleanup := NewTestDB(t)
-	defer cleanup()
+	store := newTestStorage(t)
 
 	username := "test_user"
 	passwordHash := "hashed_password"
@@ -140,14 +168,13 @@ func TestGetInventory_Success(t *testing.T) {
 	}
 
 	var infoResponse storage.InfoResponse
-	err = store.GetInventory(&infoResponse, int(userID))
+	err = store.GetInventory(context.Background(), &infoResponse, int(userID))
 	assert.NoError(t, err)
 	assert.Equal(t, inventory, infoResponse.Inventory)
 }
 
 func TestGetReceivedHistory_Success(t *testing.T) {
-	store, cleanup := NewTestDB(t)
-	defer cleanup()
+	store := newTestStorage(t)
 
 	username := "test_user"
 	passwordHash := "hashed_password"
@@ -172,18 +199,17 @@ func TestGetReceivedHistory_Success(t *testing.T) {
 	}
 
 	var infoResponse storage.InfoResponse
-	err = store.GetReceivedHistory(&infoResponse, int(userID))
+	err = store.GetReceivedHistory(context.Background(), &infoResponse, int(userID))
 	receivedHistory[0].FromUser = strconv.Itoa(int(userIDTwo))
 	assert.NoError(t, err)
 	assert.Equal(t, receivedHistory, infoResponse.CoinHistory.Received)
 }
 
 func TestBuyItem_Success(t *testing.T) {
-	db, cleanup := NewTestDB(t)
-	defer cleanup()
+	db := newTestStorage(t)
 	storagex := NewStorage(db.db)
 
-	err := storagex.AddNewUser("testuser", "hashedpassword")
+	err := storagex.AddNewUser(context.Background(), "testuser", "hashedpassword")
 	require.NoError(t, err)
 
 	var initialCoins int
@@ -191,7 +217,7 @@ func TestBuyItem_Success(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 1000, initialCoins)
 
-	err = storagex.BuyItem("testuser", "t-shirt", 50)
+	err = storagex.BuyItem(context.Background(), "testuser", "t-shirt", 50, 0, nil)
 	require.NoError(t, err)
 
 	var updatedCoins int
@@ -210,8 +236,7 @@ func TestBuyItem_Success(t *testing.T) {
 }
 
 func TestSendCoins_Success(t *testing.T) {
-	store, cleanup := NewTestDB(t)
-	defer cleanup()
+	store := newTestStorage(t)
 
 	usernameSender := "sender"
 	usernameRecipient := "recipient"
@@ -227,7 +252,7 @@ func TestSendCoins_Success(t *testing.T) {
 		ToUser: usernameRecipient,
 		Amount: 50,
 	}
-	err = store.SendCoins(usernameSender, int(senderID), int(recipientID), scr)
+	err = store.SendCoins(context.Background(), usernameSender, int(senderID), int(recipientID), scr, storage.TransferLimits{}, storage.TransferFee{}, nil)
 	assert.NoError(t, err)
 
 	var senderUpdatedCoins int
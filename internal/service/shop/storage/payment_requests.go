@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Статусы запроса на перевод. Истёкший запрос остаётся pending в БД, пока его не закроют
+// явно: такие запросы не попадают в выборки ожидающих и не могут быть приняты.
+const (
+	PaymentRequestPending   = "pending"
+	PaymentRequestAccepted  = "accepted"
+	PaymentRequestDeclined  = "declined"
+	PaymentRequestCancelled = "cancelled"
+	PaymentRequestExpired   = "expired"
+)
+
+// PaymentRequest — просьба Requester перевести ему Amount монет от Payer.
+type PaymentRequest struct {
+	ID          int       `json:"id"`
+	RequesterID int       `json:"-"`
+	Requester   string    `json:"requester"`
+	PayerID     int       `json:"-"`
+	Payer       string    `json:"payer"`
+	Amount      int       `json:"amount"`
+	Memo        string    `json:"memo,omitempty"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"createdAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// PaymentRequestSelect — общая часть выборки запросов на перевод с именами участников;
+// строки разбирает ScanPaymentRequest.
+const PaymentRequestSelect = `
+	SELECT pr.id, pr.requester_id, r.username, pr.payer_id, p.username,
+	       pr.amount, pr.memo, pr.status, pr.created_at, pr.expires_at
+	FROM payment_requests pr
+	JOIN users r ON r.id = pr.requester_id
+	JOIN users p ON p.id = pr.payer_id`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// ScanPaymentRequest читает одну строку PaymentRequestSelect.
+func ScanPaymentRequest(row rowScanner) (PaymentRequest, error) {
+	var (
+		pr   PaymentRequest
+		memo sql.NullString
+	)
+	err := row.Scan(&pr.ID, &pr.RequesterID, &pr.Requester, &pr.PayerID, &pr.Payer,
+		&pr.Amount, &memo, &pr.Status, &pr.CreatedAt, &pr.ExpiresAt)
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+	pr.Memo = memo.String
+	pr.CreatedAt, pr.ExpiresAt = pr.CreatedAt.UTC(), pr.ExpiresAt.UTC()
+	return pr, nil
+}
+
+// ScanPaymentRequests читает все строки PaymentRequestSelect.
+func ScanPaymentRequests(rows *sql.Rows) ([]PaymentRequest, error) {
+	defer rows.Close()
+
+	var res []PaymentRequest
+	for rows.Next() {
+		pr, err := ScanPaymentRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, pr)
+	}
+	return res, rows.Err()
+}
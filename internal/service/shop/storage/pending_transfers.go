@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Статусы перевода, ожидающего одобрения.
+const (
+	PendingTransferPending  = "pending"
+	PendingTransferApproved = "approved"
+	PendingTransferRejected = "rejected"
+	PendingTransferExpired  = "expired"
+)
+
+// PendingTransfer — крупный перевод Sender → Recipient, ожидающий одобрения. Пока он ждёт,
+// Amount и Fee удержаны: списаны с баланса отправителя вместе с партиями монет и не принадлежат
+// никому. Одобрение зачисляет их получателю и на счёт комиссий FeeAccountID, отказ и истечение
+// возвращают отправителю те же партии.
+type PendingTransfer struct {
+	ID           int        `json:"id"`
+	SenderID     int        `json:"-"`
+	Sender       string     `json:"sender"`
+	RecipientID  int        `json:"-"`
+	Recipient    string     `json:"recipient"`
+	Amount       int        `json:"amount"`
+	Fee          int        `json:"fee,omitempty"`
+	FeeAccountID int        `json:"-"`
+	Memo         string     `json:"memo,omitempty"`
+	Category     string     `json:"category,omitempty"`
+	Status       string     `json:"status"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	ExpiresAt    time.Time  `json:"expiresAt"`
+	ResolvedBy   string     `json:"resolvedBy,omitempty"`
+	ResolvedAt   *time.Time `json:"resolvedAt,omitempty"`
+}
+
+// InsertPendingTransferQuery добавляет перевод, ожидающий одобрения; параметры — PendingTransferArgs.
+const InsertPendingTransferQuery = `INSERT INTO pending_transfers
+	(sender_id, recipient_id, amount, fee, fee_account_id, memo, category, status, created_at, expires_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+// PendingTransferArgs возвращает аргументы InsertPendingTransferQuery.
+func PendingTransferArgs(pt *PendingTransfer) []any {
+	feeAccount := sql.NullInt64{Int64: int64(pt.FeeAccountID), Valid: pt.FeeAccountID != 0}
+	return []any{pt.SenderID, pt.RecipientID, pt.Amount, pt.Fee, feeAccount, NullString(pt.Memo), NullString(pt.Category),
+		PendingTransferPending, pt.CreatedAt, pt.ExpiresAt}
+}
+
+// HoldTransferTx удерживает сумму и комиссию только что добавленного перевода pt: списывает их
+// с баланса отправителя и переносит его партии в held_coin_lots. Если монет не хватает,
+// возвращает ErrInsufficientFunds.
+func HoldTransferTx(ctx context.Context, tx LotTx, rebind func(string) string, pt *PendingTransfer) error {
+	total := pt.Amount + pt.Fee
+	res, err := tx.ExecContext(ctx, rebind("UPDATE users SET coins = coins - ? WHERE id = ? AND coins >= ?;"), total, pt.SenderID, total)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrInsufficientFunds
+	}
+	lots, err := TakeLots(ctx, tx, rebind, pt.SenderID, total)
+	if err != nil {
+		return err
+	}
+	for _, l := range lots {
+		if _, err = tx.ExecContext(ctx, rebind("INSERT INTO held_coin_lots (transfer_id, amount, granted_at) VALUES (?, ?, ?);"),
+			pt.ID, l.Amount, l.GrantedAt); err != nil {
+			return err
+		}
+	}
+	pt.Status = PendingTransferPending
+	return nil
+}
+
+// pendingTransferSelect — общая часть выборки переводов, ожидающих одобрения (см. LoadPendingTransfers).
+const pendingTransferSelect = `
+	SELECT t.id, t.sender_id, s.username, t.recipient_id, r.username, t.amount, t.fee, t.fee_account_id,
+	       t.memo, t.category, t.status, t.created_at, t.expires_at, v.username, t.resolved_at
+	FROM pending_transfers t
+	JOIN users s ON s.id = t.sender_id
+	JOIN users r ON r.id = t.recipient_id
+	LEFT JOIN users v ON v.id = t.resolved_by`
+
+// LoadPendingTransfers выбирает первые limit (0 — все) переводов по условию where (без WHERE)
+// в порядке создания.
+func LoadPendingTransfers(ctx context.Context, q LotTx, rebind func(string) string, limit int, where string, args ...any) ([]PendingTransfer, error) {
+	query := pendingTransferSelect + " WHERE " + where + " ORDER BY t.id"
+	if limit > 0 {
+		query, args = query+" LIMIT ?", append(args, limit)
+	}
+	rows, err := q.QueryContext(ctx, rebind(query+";"), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []PendingTransfer
+	for rows.Next() {
+		var (
+			pt                 PendingTransfer
+			feeAccount         sql.NullInt64
+			memo, category, by sql.NullString
+			resolvedAt         sql.NullTime
+		)
+		if err = rows.Scan(&pt.ID, &pt.SenderID, &pt.Sender, &pt.RecipientID, &pt.Recipient, &pt.Amount, &pt.Fee, &feeAccount,
+			&memo, &category, &pt.Status, &pt.CreatedAt, &pt.ExpiresAt, &by, &resolvedAt); err != nil {
+			return nil, err
+		}
+		pt.FeeAccountID, pt.Memo, pt.Category, pt.ResolvedBy = int(feeAccount.Int64), memo.String, category.String, by.String
+		pt.CreatedAt, pt.ExpiresAt, pt.ResolvedAt = pt.CreatedAt.UTC(), pt.ExpiresAt.UTC(), nullTimePtr(resolvedAt)
+		res = append(res, pt)
+	}
+	return res, rows.Err()
+}
+
+// LoadPendingTransfer возвращает перевод по id или ErrPendingTransferNotFound.
+func LoadPendingTransfer(ctx context.Context, q LotTx, rebind func(string) string, id int) (*PendingTransfer, error) {
+	res, err := LoadPendingTransfers(ctx, q, rebind, 0, "t.id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 {
+		return nil, ErrPendingTransferNotFound
+	}
+	return &res[0], nil
+}
+
+// ClaimPendingTransferTx переводит ожидающий перевод id в status от имени resolverID (0 — без
+// пользователя, при истечении) и возвращает его. Одобрить можно только не истёкший перевод.
+// Если перевод уже закрыт или истёк, возвращает ErrPendingTransferNotPending. Удержание
+// после этого распределяет SettlePendingTransferTx в той же транзакции.
+func ClaimPendingTransferTx(ctx context.Context, tx LotTx, rebind func(string) string, id int, status string, resolverID int, at time.Time) (*PendingTransfer, error) {
+	query, args := "UPDATE pending_transfers SET status = ?, resolved_by = ?, resolved_at = ? WHERE id = ? AND status = ?",
+		[]any{status, sql.NullInt64{Int64: int64(resolverID), Valid: resolverID != 0}, at, id, PendingTransferPending}
+	if status == PendingTransferApproved {
+		query, args = query+" AND expires_at > ?", append(args, at)
+	}
+	res, err := tx.ExecContext(ctx, rebind(query+";"), args...)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		if _, err = LoadPendingTransfer(ctx, tx, rebind, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrPendingTransferNotPending
+	}
+	return LoadPendingTransfer(ctx, tx, rebind, id)
+}
+
+// SettlePendingTransferTx распределяет удержание закрытого перевода pt. Одобренный перевод
+// зачисляет Amount получателю и Fee на счёт комиссий и записывает в историю перевод и комиссию
+// от имени отправителя; отклонённый или истёкший возвращает удержание отправителю без записей
+// в истории. Партии сохраняют даты выдачи: получатель получает самые старые из удержанных.
+func SettlePendingTransferTx(ctx context.Context, tx LotTx, rebind func(string) string, pt *PendingTransfer, at time.Time) error {
+	rows, err := tx.QueryContext(ctx, rebind("SELECT amount, granted_at FROM held_coin_lots WHERE transfer_id = ? ORDER BY granted_at, id;"), pt.ID)
+	if err != nil {
+		return err
+	}
+	lots, err := ScanCoinLots(rows)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, rebind("DELETE FROM held_coin_lots WHERE transfer_id = ?;"), pt.ID); err != nil {
+		return err
+	}
+
+	type share struct{ userID, amount int }
+	shares := []share{{pt.SenderID, pt.Amount + pt.Fee}}
+	if pt.Status == PendingTransferApproved {
+		shares = []share{{pt.RecipientID, pt.Amount}, {pt.FeeAccountID, pt.Fee}}
+	}
+	for _, sh := range shares {
+		if sh.amount <= 0 {
+			continue
+		}
+		if _, err = tx.ExecContext(ctx, rebind("UPDATE users SET coins = coins + ? WHERE id = ?;"), sh.amount, sh.userID); err != nil {
+			return err
+		}
+		need := sh.amount
+		for need > 0 && len(lots) > 0 {
+			take := min(need, lots[0].Amount)
+			if err = GrantLot(ctx, tx, rebind, sh.userID, take, lots[0].GrantedAt); err != nil {
+				return err
+			}
+			if lots[0].Amount -= take; lots[0].Amount == 0 {
+				lots = lots[1:]
+			}
+			need -= take
+		}
+	}
+	if pt.Status != PendingTransferApproved {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, rebind("INSERT INTO transactions(from_user_id, to_user_id, amount, memo, category, created_at) VALUES (?, ?, ?, ?, ?, ?);"),
+		pt.SenderID, pt.RecipientID, pt.Amount, NullString(pt.Memo), NullString(pt.Category), at)
+	if err != nil || pt.Fee <= 0 {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, rebind("INSERT INTO transactions(from_user_id, to_user_id, amount, category, created_at) VALUES (?, ?, ?, ?, ?);"),
+		pt.SenderID, pt.FeeAccountID, pt.Fee, FeeCategory, at)
+	return err
+}
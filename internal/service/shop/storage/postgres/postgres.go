@@ -0,0 +1,1180 @@
+package postgres
+
+import (
+	"avito-shop/internal/config"
+	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/service/shop/storage/migrations"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// SchemaVersion — версия схемы БД, которую ожидает текущая сборка сервиса.
+const SchemaVersion = migrations.LatestVersion
+
+// rebind переводит общие запросы пакета storage в диалект Postgres.
+var rebind = migrations.Postgres.Rebind
+
+type Storage struct {
+	db *sql.DB
+}
+
+func (s *Storage) GetDB() *sql.DB {
+	return s.db
+}
+
+// Close закрывает пул соединений с БД.
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+var (
+	fullInfoQuery          = migrations.Postgres.Rebind(storage.FullInfoQuery)
+	transferTotalsQuery    = migrations.Postgres.Rebind(storage.TransferTotalsQuery)
+	coinLotsQuery          = migrations.Postgres.Rebind(storage.CoinLotsQuery)
+	expiredCoinsQuery      = migrations.Postgres.Rebind(storage.ExpiredCoinsQuery)
+	insertPromoQuery       = migrations.Postgres.Rebind(storage.InsertPromoCodeQuery) + " RETURNING id;"
+	redemptionsQuery       = migrations.Postgres.Rebind(storage.PromoRedemptionsQuery)
+	insertItemPriceQuery   = migrations.Postgres.Rebind(storage.InsertItemPriceQuery) + " RETURNING id;"
+	itemPricesQuery        = migrations.Postgres.Rebind(storage.ItemPricesQuery)
+	ordersQuery            = migrations.Postgres.Rebind(storage.OrdersQuery)
+	insertAchievement      = migrations.Postgres.Rebind(storage.InsertAchievementQuery) + " ON CONFLICT (user_id, code) DO NOTHING;"
+	achievementsQuery      = migrations.Postgres.Rebind(storage.AchievementsQuery)
+	userGroupsQuery        = migrations.Postgres.Rebind(storage.UserGroupsQuery)
+	groupMembersQuery      = migrations.Postgres.Rebind(storage.GroupMembersQuery)
+	insertGroupPurchase    = migrations.Postgres.Rebind(storage.InsertGroupPurchaseQuery) + " RETURNING id;"
+	insertPendingTransfer  = migrations.Postgres.Rebind(storage.InsertPendingTransferQuery) + " RETURNING id;"
+	reversalSourceQuery    = migrations.Postgres.Rebind(storage.ReversalSourceQuery) + " FOR UPDATE;"
+	insertTransferReversal = migrations.Postgres.Rebind(storage.InsertTransferReversalQuery) + " RETURNING id;"
+	auditHeadQuery         = storage.AuditHeadQuery + " FOR UPDATE;"
+	insertAuditEntry       = migrations.Postgres.Rebind(storage.InsertAuditEntryQuery) + " RETURNING id;"
+	auditBalanceQuery      = migrations.Postgres.Rebind(storage.AuditBalanceQuery) + " FOR UPDATE;"
+)
+
+func NewStorage(db *sql.DB) *Storage {
+	return &Storage{db: db}
+}
+
+// DSN собирает строку подключения pgx из конфигурации.
+func DSN(cfg config.DB) string {
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.Username, cfg.Password),
+		Host:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
+		Path:     cfg.Database,
+		RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+	}
+	return u.String()
+}
+
+func New(cfg config.DB) (*Storage, error) {
+	const op = "storage.postgres.New"
+
+	db, err := sql.Open("pgx", DSN(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", op, err)
+	}
+
+	err = db.Ping()
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", op, err)
+	}
+
+	if err = migrations.Apply(context.Background(), db, migrations.Postgres); err != nil {
+		return nil, fmt.Errorf("%v: %w", op, err)
+	}
+
+	return &Storage{db: db}, nil
+}
+
+// Ping проверяет доступность БД.
+func (s *Storage) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// CurrentSchemaVersion возвращает последнюю применённую версию схемы.
+func (s *Storage) CurrentSchemaVersion(ctx context.Context) (int, error) {
+	return migrations.CurrentVersion(ctx, s.db)
+}
+
+func (s *Storage) AddNewUser(ctx context.Context, username, passwordHash string) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO users (username, password_hash) VALUES ($1, $2)", username, passwordHash)
+	if err != nil {
+		return err
+	}
+	if err = storage.GrantInitialLot(ctx, tx, rebind, username, time.Now().UTC().Truncate(time.Second)); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storage) CheckAuth(ctx context.Context, username string) (string, error) {
+	var storedPasswordHash string
+	err := s.db.QueryRowContext(ctx, "SELECT password_hash FROM users WHERE username = $1", username).Scan(&storedPasswordHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", storage.ErrUserNotFound
+		}
+		return "", err
+	}
+	return storedPasswordHash, nil
+}
+
+func (s *Storage) GetInfo(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+	var id int
+
+	err := s.db.QueryRowContext(ctx, "SELECT id, coins FROM users WHERE username = $1;", username).Scan(&id, &ir.Coins)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, storage.ErrUserNotFound
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetFullInfo(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+	rows, err := s.db.QueryContext(ctx, fullInfoQuery, username)
+	if err != nil {
+		return 0, err
+	}
+	return storage.ScanFullInfo(rows, ir)
+}
+
+func (s *Storage) GetInventory(ctx context.Context, ir *storage.InfoResponse, id int) error {
+	rows, err := s.db.QueryContext(ctx, "SELECT item_name, quantity FROM inventory WHERE user_id = $1;", id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i storage.Inventory
+		if err = rows.Scan(&i.Type, &i.Quantity); err != nil {
+			return err
+		}
+		ir.Inventory = append(ir.Inventory, i)
+	}
+	return rows.Err()
+}
+
+func (s *Storage) GetReceivedHistory(ctx context.Context, ir *storage.InfoResponse, id int) error {
+	rows, err := s.db.QueryContext(ctx, "SELECT from_user_id, amount, memo, category FROM transactions WHERE to_user_id = $1;", id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i storage.TransactionIn
+		var memo, category sql.NullString
+		if err = rows.Scan(&i.FromUser, &i.Amount, &memo, &category); err != nil {
+			return err
+		}
+		i.Memo, i.Category = memo.String, category.String
+		ir.CoinHistory.Received = append(ir.CoinHistory.Received, i)
+	}
+	return rows.Err()
+}
+
+func (s *Storage) GetSendHistory(ctx context.Context, ir *storage.InfoResponse, id int) error {
+	rows, err := s.db.QueryContext(ctx, "SELECT to_user_id, amount, memo, category FROM transactions WHERE from_user_id = $1;", id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i storage.TransactionOut
+		var memo, category sql.NullString
+		if err = rows.Scan(&i.ToUser, &i.Amount, &memo, &category); err != nil {
+			return err
+		}
+		i.Memo, i.Category = memo.String, category.String
+		ir.CoinHistory.Sent = append(ir.CoinHistory.Sent, i)
+	}
+	return rows.Err()
+}
+
+func (s *Storage) GetCoinHistory(ctx context.Context, ir *storage.InfoResponse, id int, filter storage.HistoryFilter) error {
+	query, args := storage.CoinHistoryQuery(id, filter)
+	rows, err := s.db.QueryContext(ctx, migrations.Postgres.Rebind(query), args...)
+	if err != nil {
+		return err
+	}
+	return storage.ScanCoinHistory(rows, ir)
+}
+
+func (s *Storage) BuyItem(ctx context.Context, name, item string, amount, priceID int, audit *storage.TxAudit) error {
+	return s.buyItem(ctx, name, item, amount, priceID, nil, audit)
+}
+
+func (s *Storage) BuyItemWithPromo(ctx context.Context, name, item string, r *storage.PromoRedemption, audit *storage.TxAudit) error {
+	return s.buyItem(ctx, name, item, r.Price-r.Discount, r.PriceID, r, audit)
+}
+
+func (s *Storage) buyItem(ctx context.Context, name, item string, amount, priceID int, promo *storage.PromoRedemption, audit *storage.TxAudit) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+	if err = storage.AuditBefore(ctx, tx, auditBalanceQuery, audit); err != nil {
+		return err
+	}
+	query := `INSERT INTO inventory (user_id, item_name, quantity)
+			VALUES ((SELECT id FROM users WHERE username = $1), $2, 1)
+			ON CONFLICT (user_id, item_name) DO UPDATE SET quantity = inventory.quantity + 1;`
+	_, err = tx.ExecContext(ctx, query, name, item)
+	if err != nil {
+		return err
+	}
+	// Баланс проверяется в том же операторе, что и списание: параллельная покупка или перевод
+	// не уведут его в минус между проверкой в сервисе и этой транзакцией.
+	res, err := tx.ExecContext(ctx, "UPDATE users SET coins = coins - $1 WHERE username = $2 AND coins >= $1", amount, name)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n != 1 {
+		err = storage.ErrInsufficientFunds
+		return err
+	}
+
+	var userID int
+	if err = tx.QueryRowContext(ctx, "SELECT id FROM users WHERE username = $1;", name).Scan(&userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = storage.ErrUserNotFound
+		}
+		return err
+	}
+	if err = storage.MoveLots(ctx, tx, rebind, userID, 0, amount); err != nil {
+		return err
+	}
+	price, at := amount, time.Now().UTC().Truncate(time.Second)
+	if promo != nil {
+		if err = storage.RedeemPromoCode(ctx, tx, rebind, userID, item, promo); err != nil {
+			return err
+		}
+		price, at = promo.Price, promo.RedeemedAt
+	}
+	if err = storage.InsertOrder(ctx, tx, rebind, userID, item, price, priceID, amount, at); err != nil {
+		return err
+	}
+
+	if err = commitAudit(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storage) SendCoins(ctx context.Context, username string, fromUserID int, toUserID int, scr *storage.SendCoinRequest, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+	if err = storage.AuditBefore(ctx, tx, auditBalanceQuery, audit); err != nil {
+		return err
+	}
+	if err = checkTransferLimits(ctx, tx, fromUserID, toUserID, scr.Amount, limits); err != nil {
+		return err
+	}
+
+	query := `
+	  UPDATE users
+	  SET coins = CASE
+	      WHEN username = $1 THEN coins - $2
+	      WHEN username = $3 THEN coins + $2
+	  END
+	  WHERE username IN ($1, $3) AND (username <> $1 OR coins >= $4);
+	`
+	// Баланс отправителя проверяется вместе с комиссией в том же операторе, что и списание.
+	res, err := tx.ExecContext(ctx, query, username, scr.Amount, scr.ToUser, scr.Amount+fee.Amount)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n != 2 {
+		err = storage.ErrInsufficientFunds
+		return err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	_, err = tx.ExecContext(ctx, "INSERT INTO transactions(from_user_id, to_user_id, amount, memo, category, created_at) VALUES ($1, $2, $3, $4, $5, $6);",
+		fromUserID, toUserID, scr.Amount, storage.NullString(scr.Memo), storage.NullString(scr.Category), now)
+	if err != nil {
+		return err
+	}
+	if err = storage.MoveLots(ctx, tx, rebind, fromUserID, toUserID, scr.Amount); err != nil {
+		return err
+	}
+	if err = chargeTransferFee(ctx, tx, fromUserID, fee, now); err != nil {
+		return err
+	}
+	if err = commitAudit(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storage) CreatePaymentRequest(ctx context.Context, pr *storage.PaymentRequest) error {
+	err := s.db.QueryRowContext(ctx, `INSERT INTO payment_requests
+		(requester_id, payer_id, amount, memo, status, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`,
+		pr.RequesterID, pr.PayerID, pr.Amount, storage.NullString(pr.Memo), storage.PaymentRequestPending,
+		pr.CreatedAt, pr.ExpiresAt).Scan(&pr.ID)
+	if err != nil {
+		return err
+	}
+	pr.Status = storage.PaymentRequestPending
+	return nil
+}
+
+func (s *Storage) GetPaymentRequest(ctx context.Context, id int) (*storage.PaymentRequest, error) {
+	row := s.db.QueryRowContext(ctx, storage.PaymentRequestSelect+" WHERE pr.id = $1;", id)
+	pr, err := storage.ScanPaymentRequest(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrPaymentRequestNotFound
+		}
+		return nil, err
+	}
+	return &pr, nil
+}
+
+func (s *Storage) ListPendingPaymentRequests(ctx context.Context, userID int, incoming bool, now time.Time) ([]storage.PaymentRequest, error) {
+	column := "pr.requester_id"
+	if incoming {
+		column = "pr.payer_id"
+	}
+	rows, err := s.db.QueryContext(ctx, storage.PaymentRequestSelect+" WHERE "+column+" = $1 AND pr.status = $2 AND pr.expires_at > $3 ORDER BY pr.id;",
+		userID, storage.PaymentRequestPending, now)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanPaymentRequests(rows)
+}
+
+func (s *Storage) AcceptPaymentRequest(ctx context.Context, id int, now time.Time, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+	if err = storage.AuditBefore(ctx, tx, auditBalanceQuery, audit); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE payment_requests SET status = $1, resolved_at = $2
+		WHERE id = $3 AND status = $4 AND expires_at > $2;`,
+		storage.PaymentRequestAccepted, now, id, storage.PaymentRequestPending)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		err = storage.ErrPaymentRequestNotPending
+		return err
+	}
+
+	var (
+		requesterID, payerID, amount int
+		memo                         sql.NullString
+	)
+	err = tx.QueryRowContext(ctx, "SELECT requester_id, payer_id, amount, memo FROM payment_requests WHERE id = $1;", id).
+		Scan(&requesterID, &payerID, &amount, &memo)
+	if err != nil {
+		return err
+	}
+
+	if err = checkTransferLimits(ctx, tx, payerID, requesterID, amount, limits); err != nil {
+		return err
+	}
+
+	// Списание и зачисление одним оператором: баланс плательщика вместе с комиссией проверяется под блокировкой строки.
+	res, err = tx.ExecContext(ctx, `
+	  UPDATE users
+	  SET coins = CASE WHEN id = $1 THEN coins - $3 ELSE coins + $3 END
+	  WHERE id IN ($1, $2) AND (id <> $1 OR coins >= $4);`,
+		payerID, requesterID, amount, amount+fee.Amount)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n != 2 {
+		err = storage.ErrInsufficientFunds
+		return err
+	}
+
+	if err = storage.MoveLots(ctx, tx, rebind, payerID, requesterID, amount); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO transactions(from_user_id, to_user_id, amount, memo, created_at) VALUES ($1, $2, $3, $4, $5);",
+		payerID, requesterID, amount, memo, now)
+	if err != nil {
+		return err
+	}
+	if err = chargeTransferFee(ctx, tx, payerID, fee, now); err != nil {
+		return err
+	}
+
+	if err = commitAudit(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storage) ResolvePaymentRequest(ctx context.Context, id int, status string, now time.Time) error {
+	res, err := s.db.ExecContext(ctx, "UPDATE payment_requests SET status = $1, resolved_at = $2 WHERE id = $3 AND status = $4;",
+		status, now, id, storage.PaymentRequestPending)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return storage.ErrPaymentRequestNotPending
+	}
+	return nil
+}
+
+func (s *Storage) CreateScheduledTransfer(ctx context.Context, st *storage.ScheduledTransfer) error {
+	err := s.db.QueryRowContext(ctx, `INSERT INTO scheduled_transfers
+		(sender_id, recipient_id, amount, memo, category, schedule, status, next_run_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id;`,
+		st.SenderID, st.RecipientID, st.Amount, storage.NullString(st.Memo), storage.NullString(st.Category),
+		storage.NullString(st.Schedule), storage.ScheduledTransferActive, st.NextRunAt, st.CreatedAt).Scan(&st.ID)
+	if err != nil {
+		return err
+	}
+	st.Status = storage.ScheduledTransferActive
+	return nil
+}
+
+func (s *Storage) GetScheduledTransfer(ctx context.Context, id int) (*storage.ScheduledTransfer, error) {
+	row := s.db.QueryRowContext(ctx, storage.ScheduledTransferSelect+" WHERE st.id = $1;", id)
+	st, err := storage.ScanScheduledTransfer(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrScheduledTransferNotFound
+		}
+		return nil, err
+	}
+	return &st, nil
+}
+
+func (s *Storage) ListScheduledTransfers(ctx context.Context, senderID int) ([]storage.ScheduledTransfer, error) {
+	rows, err := s.db.QueryContext(ctx, storage.ScheduledTransferSelect+" WHERE st.sender_id = $1 ORDER BY st.id;", senderID)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanScheduledTransfers(rows)
+}
+
+func (s *Storage) ListDueScheduledTransfers(ctx context.Context, now time.Time, limit int) ([]storage.ScheduledTransfer, error) {
+	rows, err := s.db.QueryContext(ctx, storage.ScheduledTransferSelect+" WHERE st.status = $1 AND st.next_run_at <= $2 ORDER BY st.next_run_at, st.id LIMIT $3;",
+		storage.ScheduledTransferActive, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanScheduledTransfers(rows)
+}
+
+func (s *Storage) CancelScheduledTransfer(ctx context.Context, id int) error {
+	res, err := s.db.ExecContext(ctx, "UPDATE scheduled_transfers SET status = $1 WHERE id = $2 AND status = $3;",
+		storage.ScheduledTransferCancelled, id, storage.ScheduledTransferActive)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return storage.ErrScheduledTransferNotActive
+	}
+	return nil
+}
+
+func (s *Storage) RunScheduledTransfer(ctx context.Context, st *storage.ScheduledTransfer, next *time.Time, now time.Time, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+	failure, err := s.runScheduledTransfer(ctx, st, next, now, limits, fee, audit)
+	if err != nil {
+		return err
+	}
+	return failure
+}
+
+// runScheduledTransfer фиксирует запуск и возвращает в failure причину, по которой монеты не переведены
+// (нехватка средств или лимит): в этом случае транзакция всё равно коммитится, чтобы запуск не повторялся.
+func (s *Storage) runScheduledTransfer(ctx context.Context, st *storage.ScheduledTransfer, next *time.Time, now time.Time, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) (failure error, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+	if err = storage.AuditBefore(ctx, tx, auditBalanceQuery, audit); err != nil {
+		return nil, err
+	}
+
+	status, nextRunAt := storage.ScheduledTransferActive, st.NextRunAt
+	if next == nil {
+		status = storage.ScheduledTransferCompleted
+	} else {
+		nextRunAt = *next
+	}
+
+	// Захват запуска: счётчик runs совпадёт только у первой реплики, остальные обновят 0 строк.
+	res, err := tx.ExecContext(ctx, `UPDATE scheduled_transfers
+		SET runs = runs + 1, status = $1, next_run_at = $2, last_run_at = $3, last_error = NULL
+		WHERE id = $4 AND status = $5 AND runs = $6;`,
+		status, nextRunAt, now, st.ID, storage.ScheduledTransferActive, st.Runs)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		err = storage.ErrScheduledTransferClaimed
+		return nil, err
+	}
+
+	failure = checkTransferLimits(ctx, tx, st.SenderID, st.RecipientID, st.Amount, limits)
+	if failure == nil {
+		// Списание отдельным условным оператором: при нехватке монет на сумму с комиссией не меняется ни один баланс,
+		// и транзакцию можно закоммитить вместе с отметкой о неудачном запуске.
+		res, err = tx.ExecContext(ctx, "UPDATE users SET coins = coins - $1 WHERE id = $2 AND coins >= $3;",
+			st.Amount, st.SenderID, st.Amount+fee.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			failure = storage.ErrInsufficientFunds
+		}
+	}
+	var limitErr *storage.LimitExceededError
+	if failure != nil && !errors.Is(failure, storage.ErrInsufficientFunds) && !errors.As(failure, &limitErr) {
+		err = failure
+		return nil, err
+	}
+	if failure != nil {
+		if next == nil {
+			status = storage.ScheduledTransferFailed
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE scheduled_transfers SET status = $1, last_error = $2 WHERE id = $3;",
+			status, failure.Error(), st.ID)
+		if err != nil {
+			return nil, err
+		}
+		err = tx.Commit()
+		return failure, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE users SET coins = coins + $1 WHERE id = $2;", st.Amount, st.RecipientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = storage.MoveLots(ctx, tx, rebind, st.SenderID, st.RecipientID, st.Amount); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO transactions(from_user_id, to_user_id, amount, memo, category, created_at) VALUES ($1, $2, $3, $4, $5, $6);",
+		st.SenderID, st.RecipientID, st.Amount, storage.NullString(st.Memo), storage.NullString(st.Category), now)
+	if err != nil {
+		return nil, err
+	}
+	if err = chargeTransferFee(ctx, tx, st.SenderID, fee, now); err != nil {
+		return nil, err
+	}
+
+	if err = commitAudit(ctx, tx, audit); err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	return nil, err
+}
+
+func (s *Storage) GetUserRole(ctx context.Context, id int) (string, error) {
+	var role string
+	err := s.db.QueryRowContext(ctx, "SELECT role FROM users WHERE id = $1;", id).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", storage.ErrUserNotFound
+	}
+	return role, err
+}
+
+func (s *Storage) SetUserRole(ctx context.Context, username, role string) error {
+	res, err := s.db.ExecContext(ctx, "UPDATE users SET role = $1 WHERE username = $2;", role, username)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return storage.ErrUserNotFound
+	}
+	return nil
+}
+
+func (s *Storage) EnsureSystemAccount(ctx context.Context, username string) (int, error) {
+	const query = "SELECT id, role FROM users WHERE username = $1;"
+	var (
+		id   int
+		role string
+	)
+	err := s.db.QueryRowContext(ctx, query, username).Scan(&id, &role)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Пустой хэш не совпадает ни с одним паролем, поэтому войти под служебным счётом нельзя.
+		// Если счёт одновременно создала другая реплика, вставка не пройдёт, а её счёт прочитается ниже.
+		s.db.ExecContext(ctx, "INSERT INTO users (username, password_hash, coins, role) VALUES ($1, '', 0, $2);", username, storage.SystemRole)
+		err = s.db.QueryRowContext(ctx, query, username).Scan(&id, &role)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, storage.ErrUserNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if role != storage.SystemRole {
+		return 0, storage.ErrNotSystemAccount
+	}
+	return id, nil
+}
+
+func (s *Storage) GetTransferTotals(ctx context.Context, userID int, since time.Time) (int, int, error) {
+	return storage.TransferTotals(ctx, s.db, transferTotalsQuery, userID, since)
+}
+
+func (s *Storage) GetCoinLots(ctx context.Context, userID int) ([]storage.CoinLot, error) {
+	rows, err := s.db.QueryContext(ctx, coinLotsQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanCoinLots(rows)
+}
+
+func (s *Storage) ListExpiredCoins(ctx context.Context, cutoff time.Time, limit int) ([]storage.ExpiredCoins, error) {
+	rows, err := s.db.QueryContext(ctx, expiredCoinsQuery, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanExpiredCoins(rows)
+}
+
+func (s *Storage) ExpireCoins(ctx context.Context, userID, sinkID int, cutoff, now time.Time, audit *storage.TxAudit) (amount int, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+
+	// Блокировка не даёт параллельному переводу потратить партии между подсчётом и списанием.
+	if err = lockTransferParties(ctx, tx, userID, sinkID); err != nil {
+		return 0, err
+	}
+	if err = storage.AuditBefore(ctx, tx, auditBalanceQuery, audit); err != nil {
+		return 0, err
+	}
+	if amount, err = storage.BurnExpiredLots(ctx, tx, rebind, userID, sinkID, cutoff, now); err != nil {
+		return 0, err
+	}
+	if amount > 0 {
+		if err = commitAudit(ctx, tx, audit); err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit()
+	return amount, err
+}
+
+func (s *Storage) CreatePromoCode(ctx context.Context, pc *storage.PromoCode) error {
+	var n int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM promo_codes WHERE code = $1;", pc.Code).Scan(&n); err != nil {
+		return err
+	}
+	if n > 0 {
+		return storage.ErrPromoCodeExists
+	}
+	return s.db.QueryRowContext(ctx, insertPromoQuery, storage.PromoCodeArgs(pc)...).Scan(&pc.ID)
+}
+
+func (s *Storage) GetPromoCode(ctx context.Context, code string) (*storage.PromoCode, error) {
+	row := s.db.QueryRowContext(ctx, storage.PromoCodeSelect+" WHERE code = $1;", code)
+	pc, err := storage.ScanPromoCode(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrPromoCodeNotFound
+		}
+		return nil, err
+	}
+	return &pc, nil
+}
+
+func (s *Storage) ListPromoCodes(ctx context.Context) ([]storage.PromoCode, error) {
+	rows, err := s.db.QueryContext(ctx, storage.PromoCodeSelect+" ORDER BY id;")
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanPromoCodes(rows)
+}
+
+func (s *Storage) DisablePromoCode(ctx context.Context, code string, now time.Time) error {
+	_, err := s.db.ExecContext(ctx, "UPDATE promo_codes SET disabled_at = $1 WHERE code = $2 AND disabled_at IS NULL;", now, code)
+	return err
+}
+
+func (s *Storage) CountPromoRedemptions(ctx context.Context, promoCodeID, userID int) (n int, err error) {
+	err = s.db.QueryRowContext(ctx, redemptionsQuery, promoCodeID, userID).Scan(&n)
+	return n, err
+}
+
+func (s *Storage) CreateItemPrice(ctx context.Context, p *storage.ItemPrice) error {
+	return s.db.QueryRowContext(ctx, insertItemPriceQuery, storage.ItemPriceArgs(p)...).Scan(&p.ID)
+}
+
+func (s *Storage) GetItemPrice(ctx context.Context, id int) (*storage.ItemPrice, error) {
+	p, err := storage.ScanItemPrice(s.db.QueryRowContext(ctx, storage.ItemPriceSelect+" WHERE id = $1;", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrItemPriceNotFound
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *Storage) ListItemPrices(ctx context.Context, now time.Time) ([]storage.ItemPrice, error) {
+	rows, err := s.db.QueryContext(ctx, itemPricesQuery, now)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanItemPrices(rows)
+}
+
+func (s *Storage) DeleteItemPrice(ctx context.Context, id int) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM item_prices WHERE id = $1;", id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return storage.ErrItemPriceNotFound
+	}
+	return nil
+}
+
+func (s *Storage) ListOrders(ctx context.Context, userID int) ([]storage.Order, error) {
+	rows, err := s.db.QueryContext(ctx, ordersQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanOrders(rows)
+}
+
+func (s *Storage) GetLeaderboard(ctx context.Context, board string, since *time.Time, limit int) ([]storage.LeaderboardEntry, error) {
+	query, args := storage.LeaderboardQuery(board, since, limit)
+	if query == "" {
+		return nil, storage.ErrUnknownLeaderboard
+	}
+	rows, err := s.db.QueryContext(ctx, migrations.Postgres.Rebind(query), args...)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanLeaderboard(rows)
+}
+
+func (s *Storage) SetLeaderboardOptOut(ctx context.Context, userID int, optOut bool) error {
+	_, err := s.db.ExecContext(ctx, "UPDATE users SET leaderboard_opt_out = $1 WHERE id = $2;", optOut, userID)
+	return err
+}
+
+func (s *Storage) GetAchievementProgress(ctx context.Context, userID int) (*storage.AchievementProgress, error) {
+	return storage.LoadAchievementProgress(ctx, s.db, rebind, userID)
+}
+
+func (s *Storage) AwardAchievement(ctx context.Context, userID int, a *storage.Achievement) (bool, error) {
+	res, err := s.db.ExecContext(ctx, insertAchievement, storage.AchievementArgs(userID, a)...)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (s *Storage) ListAchievements(ctx context.Context, userID int) ([]storage.Achievement, error) {
+	rows, err := s.db.QueryContext(ctx, achievementsQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanAchievements(rows)
+}
+
+func (s *Storage) CreateGroup(ctx context.Context, g *storage.Group, ownerID int) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+
+	if err = storage.CreateGroupTx(ctx, tx, rebind, g, ownerID); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storage) GetGroup(ctx context.Context, name string) (*storage.Group, error) {
+	g, err := storage.ScanGroup(s.db.QueryRowContext(ctx, storage.GroupSelect+" WHERE u.username = $1;", name))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrGroupNotFound
+		}
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (s *Storage) ListUserGroups(ctx context.Context, userID int) ([]storage.Group, error) {
+	rows, err := s.db.QueryContext(ctx, userGroupsQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanUserGroups(rows)
+}
+
+func (s *Storage) ListGroupMembers(ctx context.Context, groupID int) ([]storage.GroupMember, error) {
+	rows, err := s.db.QueryContext(ctx, groupMembersQuery, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanGroupMembers(rows)
+}
+
+func (s *Storage) GetGroupMemberRole(ctx context.Context, groupID, userID int) (string, error) {
+	return storage.GroupMemberRole(ctx, s.db, rebind, groupID, userID)
+}
+
+func (s *Storage) SetGroupMember(ctx context.Context, groupID, userID int, role string, at time.Time) error {
+	return storage.SetGroupMemberTx(ctx, s.db, rebind, groupID, userID, role, at)
+}
+
+func (s *Storage) RemoveGroupMember(ctx context.Context, groupID, userID int) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM group_members WHERE group_id = $1 AND user_id = $2;", groupID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return storage.ErrNotGroupMember
+	}
+	return nil
+}
+
+func (s *Storage) CreateGroupPurchase(ctx context.Context, p *storage.GroupPurchase, proposerID int) error {
+	return s.db.QueryRowContext(ctx, insertGroupPurchase, storage.GroupPurchaseArgs(p, proposerID)...).Scan(&p.ID)
+}
+
+func (s *Storage) GetGroupPurchase(ctx context.Context, id int) (*storage.GroupPurchase, error) {
+	return storage.LoadGroupPurchase(ctx, s.db, rebind, id)
+}
+
+func (s *Storage) ListGroupPurchases(ctx context.Context, groupID int) ([]storage.GroupPurchase, error) {
+	return storage.LoadGroupPurchases(ctx, s.db, rebind, "p.group_id = ?", groupID)
+}
+
+func (s *Storage) ApproveGroupPurchase(ctx context.Context, id, userID, price, priceID int, at time.Time, audit *storage.TxAudit) (p *storage.GroupPurchase, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+	if err = storage.AuditBefore(ctx, tx, auditBalanceQuery, audit); err != nil {
+		return nil, err
+	}
+
+	if p, err = storage.ApproveGroupPurchaseTx(ctx, tx, rebind, id, userID, price, priceID, at); err != nil {
+		return nil, err
+	}
+	if p.Status == storage.GroupPurchaseCompleted {
+		if err = commitAudit(ctx, tx, audit); err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	return p, err
+}
+
+func (s *Storage) CancelGroupPurchase(ctx context.Context, id int, at time.Time) error {
+	return storage.CancelGroupPurchase(ctx, s.db, rebind, id, at)
+}
+
+func (s *Storage) CreatePendingTransfer(ctx context.Context, pt *storage.PendingTransfer, limits storage.TransferLimits, audit *storage.TxAudit) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+	if err = storage.AuditBefore(ctx, tx, auditBalanceQuery, audit); err != nil {
+		return err
+	}
+
+	if err = checkTransferLimits(ctx, tx, pt.SenderID, pt.RecipientID, pt.Amount, limits); err != nil {
+		return err
+	}
+	if err = tx.QueryRowContext(ctx, insertPendingTransfer, storage.PendingTransferArgs(pt)...).Scan(&pt.ID); err != nil {
+		return err
+	}
+	audit.Created(pt.ID)
+	if err = storage.HoldTransferTx(ctx, tx, rebind, pt); err != nil {
+		return err
+	}
+
+	if err = commitAudit(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storage) GetPendingTransfer(ctx context.Context, id int) (*storage.PendingTransfer, error) {
+	return storage.LoadPendingTransfer(ctx, s.db, rebind, id)
+}
+
+func (s *Storage) ListPendingTransfers(ctx context.Context, senderID int, now time.Time) ([]storage.PendingTransfer, error) {
+	if senderID == 0 {
+		return storage.LoadPendingTransfers(ctx, s.db, rebind, 0, "t.status = ? AND t.expires_at > ?", storage.PendingTransferPending, now)
+	}
+	return storage.LoadPendingTransfers(ctx, s.db, rebind, 0, "t.sender_id = ? AND t.status = ? AND t.expires_at > ?",
+		senderID, storage.PendingTransferPending, now)
+}
+
+func (s *Storage) ListExpiredPendingTransfers(ctx context.Context, now time.Time, limit int) ([]storage.PendingTransfer, error) {
+	return storage.LoadPendingTransfers(ctx, s.db, rebind, limit, "t.status = ? AND t.expires_at <= ?", storage.PendingTransferPending, now)
+}
+
+func (s *Storage) ResolvePendingTransfer(ctx context.Context, id int, status string, resolverID int, at time.Time, limits storage.TransferLimits, audit *storage.TxAudit) (pt *storage.PendingTransfer, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+
+	if pt, err = storage.ClaimPendingTransferTx(ctx, tx, rebind, id, status, resolverID, at); err != nil {
+		return nil, err
+	}
+	if err = storage.AuditPartiesBefore(ctx, tx, auditBalanceQuery, audit, pt.SenderID, pt.RecipientID); err != nil {
+		return nil, err
+	}
+	if status == storage.PendingTransferApproved {
+		if err = checkTransferLimits(ctx, tx, pt.SenderID, pt.RecipientID, pt.Amount, limits); err != nil {
+			return nil, err
+		}
+	}
+	if err = storage.SettlePendingTransferTx(ctx, tx, rebind, pt, at); err != nil {
+		return nil, err
+	}
+	if err = commitAudit(ctx, tx, audit); err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	return pt, err
+}
+
+func (s *Storage) ReverseTransaction(ctx context.Context, rv *storage.TransferReversal, audit *storage.TxAudit) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+
+	// Отменяемая запись читается первой и с блокировкой: повторная отмена той же записи ждёт эту.
+	if err = storage.LoadReversalSource(ctx, tx, reversalSourceQuery, rv); err != nil {
+		return err
+	}
+	if err = lockTransferParties(ctx, tx, rv.FromUserID, rv.ToUserID); err != nil {
+		return err
+	}
+	if err = storage.AuditPartiesBefore(ctx, tx, auditBalanceQuery, audit, rv.FromUserID, rv.ToUserID); err != nil {
+		return err
+	}
+	if err = storage.ReverseTransactionTx(ctx, tx, rebind, rv); err != nil {
+		return err
+	}
+	if err = tx.QueryRowContext(ctx, insertTransferReversal, storage.TransferReversalArgs(rv)...).Scan(&rv.ID); err != nil {
+		return err
+	}
+
+	if err = commitAudit(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storage) ListUserTransactions(ctx context.Context, userID, limit int) ([]storage.Transaction, error) {
+	return storage.LoadUserTransactions(ctx, s.db, rebind, userID, limit)
+}
+
+func (s *Storage) AppendAudit(ctx context.Context, e *storage.AuditEntry) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+
+	if err = chainAudit(ctx, tx, e); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+// chainAudit дописывает e в конец журнала аудита в транзакции tx.
+func chainAudit(ctx context.Context, tx *sql.Tx, e *storage.AuditEntry) error {
+	if err := storage.ChainAuditEntry(ctx, tx, auditHeadQuery, e); err != nil {
+		return err
+	}
+	if err := tx.QueryRowContext(ctx, insertAuditEntry, storage.AuditEntryArgs(e)...).Scan(&e.ID); err != nil {
+		return err
+	}
+	return storage.AdvanceAuditHead(ctx, tx, rebind, e)
+}
+
+// commitAudit читает балансы после операции и дописывает запись a в журнал в транзакции операции;
+// при nil a ничего не делает.
+func commitAudit(ctx context.Context, tx *sql.Tx, a *storage.TxAudit) error {
+	if a == nil {
+		return nil
+	}
+	if err := storage.AuditAfter(ctx, tx, auditBalanceQuery, a); err != nil {
+		return err
+	}
+	return chainAudit(ctx, tx, a.Entry)
+}
+
+func (s *Storage) ListAudit(ctx context.Context, filter storage.AuditFilter) ([]storage.AuditEntry, error) {
+	return storage.LoadAuditEntries(ctx, s.db, rebind, filter)
+}
+
+func (s *Storage) GetAuditHead(ctx context.Context) (*storage.AuditHead, error) {
+	return storage.LoadAuditHead(ctx, s.db)
+}
+
+// chargeTransferFee списывает комиссию с отправителя и записывает её отдельным переводом на служебный счёт.
+func chargeTransferFee(ctx context.Context, tx *sql.Tx, fromUserID int, fee storage.TransferFee, at time.Time) error {
+	if fee.Amount <= 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET coins = coins - $1 WHERE id = $2;", fee.Amount, fromUserID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET coins = coins + $1 WHERE id = $2;", fee.Amount, fee.AccountID); err != nil {
+		return err
+	}
+	if err := storage.MoveLots(ctx, tx, rebind, fromUserID, fee.AccountID, fee.Amount); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, "INSERT INTO transactions(from_user_id, to_user_id, amount, category, created_at) VALUES ($1, $2, $3, $4, $5);",
+		fromUserID, fee.AccountID, fee.Amount, storage.FeeCategory, at)
+	return err
+}
+
+// lockTransferParties блокирует строки участников перевода до конца транзакции, чтобы параллельные
+// переводы тех же пользователей не обошли суточные лимиты. Строки блокируются в порядке id.
+func lockTransferParties(ctx context.Context, tx *sql.Tx, ids ...int) error {
+	rows, err := tx.QueryContext(ctx, "SELECT id FROM users WHERE id IN ($1, $2) ORDER BY id FOR UPDATE;", ids[0], ids[1])
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// checkTransferLimits блокирует участников и проверяет суточные лимиты; без лимитов ничего не делает.
+func checkTransferLimits(ctx context.Context, tx *sql.Tx, fromID, toID, amount int, limits storage.TransferLimits) error {
+	if !limits.Enabled() {
+		return nil
+	}
+	if err := lockTransferParties(ctx, tx, fromID, toID); err != nil {
+		return err
+	}
+	return storage.CheckTransferLimits(ctx, tx, transferTotalsQuery, fromID, toID, amount, limits)
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// ItemPrice — запланированная цена предмета Item с момента StartsAt. Без EndsAt это постоянное
+// изменение цены; с EndsAt — распродажа, которая в [StartsAt, EndsAt) действует поверх обычной цены.
+type ItemPrice struct {
+	ID        int        `json:"id"`
+	Item      string     `json:"item"`
+	Price     int        `json:"price"`
+	StartsAt  time.Time  `json:"startsAt"`
+	EndsAt    *time.Time `json:"endsAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// IsSale сообщает, что цена ограничена по времени.
+func (p *ItemPrice) IsSale() bool {
+	return p.EndsAt != nil
+}
+
+// CatalogItem — предмет каталога с ценой на момент запроса: Price — цена к оплате,
+// RegularPrice — цена без распродажи, SaleEndsAt — конец текущей распродажи,
+// PriceID — id записи ItemPrice, из которой взята Price (0 — базовая цена).
+type CatalogItem struct {
+	Name         string     `json:"name"`
+	Price        int        `json:"price"`
+	RegularPrice int        `json:"regularPrice"`
+	SaleEndsAt   *time.Time `json:"saleEndsAt,omitempty"`
+	PriceID      int        `json:"-"`
+}
+
+// Order — покупка предмета: Price — цена по каталогу в момент покупки, Paid — сколько монет
+// списано с учётом промокода, PriceID — id записи ItemPrice, по которой считана Price (0 — базовая цена).
+type Order struct {
+	ID        int       `json:"id"`
+	Item      string    `json:"item"`
+	Price     int       `json:"price"`
+	Paid      int       `json:"paid"`
+	PriceID   int       `json:"priceId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ItemPriceSelect — общая часть выборки цен; строки разбирает ScanItemPrices.
+const ItemPriceSelect = `SELECT id, item_name, price, starts_at, ends_at, created_at FROM item_prices`
+
+// ItemPricesQuery выбирает цены, не закончившиеся к моменту из параметра, в порядке начала действия.
+const ItemPricesQuery = ItemPriceSelect + ` WHERE ends_at IS NULL OR ends_at > ? ORDER BY starts_at, id;`
+
+// ScanItemPrice читает одну строку ItemPriceSelect.
+func ScanItemPrice(row rowScanner) (ItemPrice, error) {
+	var (
+		p      ItemPrice
+		endsAt sql.NullTime
+	)
+	if err := row.Scan(&p.ID, &p.Item, &p.Price, &p.StartsAt, &endsAt, &p.CreatedAt); err != nil {
+		return ItemPrice{}, err
+	}
+	p.StartsAt, p.EndsAt, p.CreatedAt = p.StartsAt.UTC(), nullTimePtr(endsAt), p.CreatedAt.UTC()
+	return p, nil
+}
+
+// ScanItemPrices читает все строки ItemPriceSelect.
+func ScanItemPrices(rows *sql.Rows) ([]ItemPrice, error) {
+	defer rows.Close()
+
+	var res []ItemPrice
+	for rows.Next() {
+		p, err := ScanItemPrice(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, p)
+	}
+	return res, rows.Err()
+}
+
+// InsertItemPriceQuery добавляет цену; параметры — ItemPriceArgs.
+const InsertItemPriceQuery = `INSERT INTO item_prices (item_name, price, starts_at, ends_at, created_at) VALUES (?, ?, ?, ?, ?)`
+
+// ItemPriceArgs возвращает значения колонок item_name, price, starts_at, ends_at, created_at для вставки цены.
+func ItemPriceArgs(p *ItemPrice) []any {
+	return []any{p.Item, p.Price, p.StartsAt, nullTime(p.EndsAt), p.CreatedAt}
+}
+
+// OrdersQuery выбирает покупки пользователя в порядке совершения. Параметр — id пользователя.
+const OrdersQuery = `SELECT id, item_name, price, paid, price_id, created_at FROM orders WHERE user_id = ? ORDER BY id;`
+
+// ScanOrders читает все строки OrdersQuery.
+func ScanOrders(rows *sql.Rows) ([]Order, error) {
+	defer rows.Close()
+
+	var res []Order
+	for rows.Next() {
+		var (
+			o       Order
+			priceID sql.NullInt64
+		)
+		if err := rows.Scan(&o.ID, &o.Item, &o.Price, &o.Paid, &priceID, &o.CreatedAt); err != nil {
+			return nil, err
+		}
+		o.PriceID, o.CreatedAt = int(priceID.Int64), o.CreatedAt.UTC()
+		res = append(res, o)
+	}
+	return res, rows.Err()
+}
+
+// InsertOrder записывает покупку item пользователем userID по цене price из записи priceID (0 — базовая цена),
+// за которую списано paid монет.
+func InsertOrder(ctx context.Context, tx LotTx, rebind func(string) string, userID int, item string, price, priceID, paid int, at time.Time) error {
+	_, err := tx.ExecContext(ctx, rebind("INSERT INTO orders (user_id, item_name, price, price_id, paid, created_at) VALUES (?, ?, ?, ?, ?, ?);"),
+		userID, item, price, sql.NullInt64{Int64: int64(priceID), Valid: priceID != 0}, paid, at)
+	return err
+}
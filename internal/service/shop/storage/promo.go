@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// AdminRole — роль пользователя, которому доступно управление промокодами.
+const AdminRole = "admin"
+
+// Виды скидки по промокоду.
+const (
+	PromoPercent = "percent"
+	PromoFixed   = "fixed"
+)
+
+// PromoCode — промокод на скидку Value процентов (PromoPercent) или монет (PromoFixed).
+// Пустой Items — код действует на любой предмет; нулевые MaxUses и MaxUsesPerUser
+// не ограничивают число покупок; StartsAt и EndsAt ограничивают срок действия [StartsAt, EndsAt).
+type PromoCode struct {
+	ID             int        `json:"-"`
+	Code           string     `json:"code"`
+	Kind           string     `json:"kind"`
+	Value          int        `json:"value"`
+	Items          []string   `json:"items,omitempty"`
+	MaxUses        int        `json:"maxUses"`
+	MaxUsesPerUser int        `json:"maxUsesPerUser"`
+	Uses           int        `json:"uses"`
+	StartsAt       *time.Time `json:"startsAt,omitempty"`
+	EndsAt         *time.Time `json:"endsAt,omitempty"`
+	DisabledAt     *time.Time `json:"disabledAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// Discount возвращает скидку на предмет стоимостью price; она не превышает саму цену.
+func (pc *PromoCode) Discount(price int) int {
+	if pc.Kind == PromoPercent {
+		return price * pc.Value / 100
+	}
+	return min(pc.Value, price)
+}
+
+// PriceQuote — цена покупки предмета с учётом промокода.
+type PriceQuote struct {
+	Item      string `json:"item"`
+	Price     int    `json:"price"`
+	Discount  int    `json:"discount"`
+	Total     int    `json:"total"`
+	PromoCode string `json:"promoCode,omitempty"`
+}
+
+// PromoRedemption — применение промокода PromoCodeID к покупке: Price — цена без скидки из записи
+// ItemPrice PriceID (0 — базовая цена), Discount — скидка, списывается Price - Discount.
+type PromoRedemption struct {
+	PromoCodeID int
+	Price       int
+	PriceID     int
+	Discount    int
+	RedeemedAt  time.Time
+}
+
+// PromoCodeSelect — общая часть выборки промокодов; строки разбирает ScanPromoCode.
+const PromoCodeSelect = `
+	SELECT id, code, kind, discount, items, max_uses, max_uses_per_user, uses,
+	       starts_at, ends_at, disabled_at, created_at
+	FROM promo_codes`
+
+// ScanPromoCode читает одну строку PromoCodeSelect.
+func ScanPromoCode(row rowScanner) (PromoCode, error) {
+	var (
+		pc                         PromoCode
+		items                      sql.NullString
+		startsAt, endsAt, disabled sql.NullTime
+	)
+	err := row.Scan(&pc.ID, &pc.Code, &pc.Kind, &pc.Value, &items, &pc.MaxUses, &pc.MaxUsesPerUser, &pc.Uses,
+		&startsAt, &endsAt, &disabled, &pc.CreatedAt)
+	if err != nil {
+		return PromoCode{}, err
+	}
+	if items.String != "" {
+		pc.Items = strings.Split(items.String, ",")
+	}
+	pc.StartsAt, pc.EndsAt, pc.DisabledAt = nullTimePtr(startsAt), nullTimePtr(endsAt), nullTimePtr(disabled)
+	pc.CreatedAt = pc.CreatedAt.UTC()
+	return pc, nil
+}
+
+// ScanPromoCodes читает все строки PromoCodeSelect.
+func ScanPromoCodes(rows *sql.Rows) ([]PromoCode, error) {
+	defer rows.Close()
+
+	var res []PromoCode
+	for rows.Next() {
+		pc, err := ScanPromoCode(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, pc)
+	}
+	return res, rows.Err()
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time.UTC()
+	return &v
+}
+
+// PromoCodeArgs возвращает значения колонок code, kind, discount, items, max_uses, max_uses_per_user,
+// starts_at, ends_at, created_at для вставки промокода.
+func PromoCodeArgs(pc *PromoCode) []any {
+	return []any{pc.Code, pc.Kind, pc.Value, NullString(strings.Join(pc.Items, ",")), pc.MaxUses, pc.MaxUsesPerUser,
+		nullTime(pc.StartsAt), nullTime(pc.EndsAt), pc.CreatedAt}
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+// InsertPromoCodeQuery добавляет промокод; параметры — PromoCodeArgs.
+const InsertPromoCodeQuery = `INSERT INTO promo_codes
+	(code, kind, discount, items, max_uses, max_uses_per_user, starts_at, ends_at, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+// PromoRedemptionsQuery считает покупки пользователя по промокоду. Параметры: id промокода, id пользователя.
+const PromoRedemptionsQuery = `SELECT COUNT(*) FROM promo_redemptions WHERE promo_code_id = ? AND user_id = ?;`
+
+// RedeemPromoCode засчитывает применение промокода к покупке item пользователем userID и записывает
+// покупку с ценой и скидкой. Счётчик использований увеличивается условным UPDATE, который блокирует
+// строку промокода до конца транзакции, поэтому лимиты не превышаются при одновременных покупках.
+// Возвращает ErrPromoCodeUnavailable, если код отключён, вне срока действия или исчерпан,
+// и ErrPromoCodeUserLimit, если пользователь уже применил его максимальное число раз.
+func RedeemPromoCode(ctx context.Context, tx LotTx, rebind func(string) string, userID int, item string, r *PromoRedemption) error {
+	res, err := tx.ExecContext(ctx, rebind(`UPDATE promo_codes SET uses = uses + 1
+		WHERE id = ? AND disabled_at IS NULL
+		  AND (starts_at IS NULL OR starts_at <= ?) AND (ends_at IS NULL OR ends_at > ?)
+		  AND (max_uses = 0 OR uses < max_uses);`),
+		r.PromoCodeID, r.RedeemedAt, r.RedeemedAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrPromoCodeUnavailable
+	}
+
+	var maxPerUser, used int
+	if err = tx.QueryRowContext(ctx, rebind("SELECT max_uses_per_user FROM promo_codes WHERE id = ?;"), r.PromoCodeID).Scan(&maxPerUser); err != nil {
+		return err
+	}
+	if maxPerUser > 0 {
+		if err = tx.QueryRowContext(ctx, rebind(PromoRedemptionsQuery), r.PromoCodeID, userID).Scan(&used); err != nil {
+			return err
+		}
+		if used >= maxPerUser {
+			return ErrPromoCodeUserLimit
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, rebind(`INSERT INTO promo_redemptions
+		(promo_code_id, user_id, item_name, price, discount, created_at) VALUES (?, ?, ?, ?, ?, ?);`),
+		r.PromoCodeID, userID, item, r.Price, r.Discount, r.RedeemedAt)
+	return err
+}
@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// ReversalCategory — категория компенсирующей записи, которой администратор отменил перевод.
+const ReversalCategory = "reversal"
+
+// Политики отмены перевода, монеты которого получатель уже потратил.
+const (
+	// ReversalPartial возвращает отправителю не больше, чем осталось у получателя.
+	ReversalPartial = "partial"
+	// ReversalNegative возвращает всю сумму; недостающее уводит баланс получателя в минус,
+	// и долг гасится его следующими поступлениями.
+	ReversalNegative = "negative"
+)
+
+// TransferReversal — отмена перевода TransactionID администратором ReversedBy. Исходная запись
+// не меняется: Reversed монет возвращаются от получателя ToUser отправителю FromUser новой записью
+// с категорией ReversalCategory. Сама TransferReversal — запись аудита с балансом получателя
+// до и после отмены.
+type TransferReversal struct {
+	ID              int       `json:"id"`
+	TransactionID   int       `json:"transactionId"`
+	FromUserID      int       `json:"-"`
+	FromUser        string    `json:"fromUser"`
+	ToUserID        int       `json:"-"`
+	ToUser          string    `json:"toUser"`
+	Amount          int       `json:"amount"`
+	Reversed        int       `json:"reversed"`
+	Policy          string    `json:"policy"`
+	RecipientBefore int       `json:"recipientBefore"`
+	RecipientAfter  int       `json:"recipientAfter"`
+	Reason          string    `json:"reason"`
+	ReversedByID    int       `json:"-"`
+	ReversedBy      string    `json:"reversedBy"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// Transaction — запись истории переводов с идентификатором, по которому её можно отменить.
+// FromUser пуст у начислений без отправителя. ReversalOf — какую запись отменяет эта,
+// Reversed — отменена ли она сама.
+type Transaction struct {
+	ID         int        `json:"id"`
+	FromUser   string     `json:"fromUser,omitempty"`
+	ToUser     string     `json:"toUser"`
+	Amount     int        `json:"amount"`
+	Memo       string     `json:"memo,omitempty"`
+	Category   string     `json:"category,omitempty"`
+	CreatedAt  *time.Time `json:"createdAt,omitempty"`
+	ReversalOf int        `json:"reversalOf,omitempty"`
+	Reversed   bool       `json:"reversed,omitempty"`
+}
+
+// ReversalSourceQuery выбирает отменяемую запись по id вместе с ролями участников. mysql и postgres
+// добавляют FOR UPDATE, чтобы одновременные отмены одной записи выполнялись по очереди; роли читаются
+// подзапросами, чтобы блокировалась только строка transactions.
+const ReversalSourceQuery = `SELECT t.from_user_id, t.to_user_id, t.amount, t.reversal_of, t.category,
+	(SELECT role FROM users WHERE id = t.from_user_id), (SELECT role FROM users WHERE id = t.to_user_id)
+	FROM transactions t WHERE t.id = ?`
+
+// LoadReversalSource читает запись rv.TransactionID запросом query (см. ReversalSourceQuery)
+// и заполняет участников и сумму rv. Возвращает ErrTransactionNotFound или ErrTransactionNotReversible
+// для начислений, компенсирующих записей, комиссий и сгораний, а также для записей, где одна
+// из сторон — служебный счёт или кошелёк группы: их движения отменяются не администратором.
+func LoadReversalSource(ctx context.Context, tx LotTx, query string, rv *TransferReversal) error {
+	var (
+		from, reversalOf           sql.NullInt64
+		category, fromRole, toRole sql.NullString
+	)
+	err := tx.QueryRowContext(ctx, query, rv.TransactionID).Scan(&from, &rv.ToUserID, &rv.Amount, &reversalOf,
+		&category, &fromRole, &toRole)
+	if err == sql.ErrNoRows {
+		return ErrTransactionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !from.Valid || reversalOf.Valid {
+		return ErrTransactionNotReversible
+	}
+	if category.String == FeeCategory || category.String == ExpiredCategory {
+		return ErrTransactionNotReversible
+	}
+	for _, role := range []string{fromRole.String, toRole.String} {
+		if role == SystemRole || role == GroupRole {
+			return ErrTransactionNotReversible
+		}
+	}
+	rv.FromUserID = int(from.Int64)
+	return nil
+}
+
+// ReverseTransactionTx возвращает отправителю монеты записи, загруженной LoadReversalSource.
+// Строки обоих участников к этому моменту должны быть заблокированы транзакцией. С политикой
+// ReversalPartial возвращается не больше баланса получателя, и если возвращать нечего — ErrInsufficientFunds;
+// с ReversalNegative — вся сумма. Отправитель получает партии получателя с их датами выдачи,
+// а недостающее — новой партией от rv.CreatedAt. Запись аудита добавляет вызывающий
+// (InsertTransferReversalQuery).
+func ReverseTransactionTx(ctx context.Context, tx LotTx, rebind func(string) string, rv *TransferReversal) error {
+	var n int
+	if err := tx.QueryRowContext(ctx, rebind("SELECT COUNT(*) FROM transfer_reversals WHERE transaction_id = ?;"), rv.TransactionID).Scan(&n); err != nil {
+		return err
+	}
+	if n > 0 {
+		return ErrTransactionAlreadyReversed
+	}
+
+	err := tx.QueryRowContext(ctx, rebind(`
+		SELECT t.coins, t.username, f.username, a.username
+		FROM users t, users f, users a
+		WHERE t.id = ? AND f.id = ? AND a.id = ?;`), rv.ToUserID, rv.FromUserID, rv.ReversedByID).
+		Scan(&rv.RecipientBefore, &rv.ToUser, &rv.FromUser, &rv.ReversedBy)
+	if err != nil {
+		return err
+	}
+	available := max(min(rv.Amount, rv.RecipientBefore), 0)
+	rv.Reversed = available
+	if rv.Policy == ReversalNegative {
+		rv.Reversed = rv.Amount
+	}
+	if rv.Reversed == 0 {
+		return ErrInsufficientFunds
+	}
+	rv.RecipientAfter = rv.RecipientBefore - rv.Reversed
+
+	if _, err = tx.ExecContext(ctx, rebind("UPDATE users SET coins = coins - ? WHERE id = ?;"), rv.Reversed, rv.ToUserID); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, rebind("UPDATE users SET coins = coins + ? WHERE id = ?;"), rv.Reversed, rv.FromUserID); err != nil {
+		return err
+	}
+	// Партии получателя в сумме равны неотрицательной части его баланса, поэтому available монет всегда найдётся;
+	// долг сверх баланса в партиях не записывается и гасится следующими зачислениями (GrantLot).
+	if err = MoveLots(ctx, tx, rebind, rv.ToUserID, rv.FromUserID, available); err != nil {
+		return err
+	}
+	if err = GrantLot(ctx, tx, rebind, rv.FromUserID, rv.Reversed-available, rv.CreatedAt); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, rebind("INSERT INTO transactions(from_user_id, to_user_id, amount, memo, category, created_at, reversal_of) VALUES (?, ?, ?, ?, ?, ?, ?);"),
+		rv.ToUserID, rv.FromUserID, rv.Reversed, NullString(rv.Reason), ReversalCategory, rv.CreatedAt, rv.TransactionID)
+	return err
+}
+
+// InsertTransferReversalQuery добавляет запись аудита отмены; параметры — TransferReversalArgs.
+const InsertTransferReversalQuery = `INSERT INTO transfer_reversals
+	(transaction_id, reversed_by, policy, amount, reversed, recipient_before, recipient_after, reason, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+// TransferReversalArgs возвращает аргументы InsertTransferReversalQuery.
+func TransferReversalArgs(rv *TransferReversal) []any {
+	return []any{rv.TransactionID, rv.ReversedByID, rv.Policy, rv.Amount, rv.Reversed,
+		rv.RecipientBefore, rv.RecipientAfter, rv.Reason, rv.CreatedAt}
+}
+
+// LoadUserTransactions возвращает до limit последних записей, где userID — отправитель
+// или получатель, от новых к старым.
+func LoadUserTransactions(ctx context.Context, q LotTx, rebind func(string) string, userID, limit int) ([]Transaction, error) {
+	rows, err := q.QueryContext(ctx, rebind(`
+		SELECT t.id, f.username, u.username, t.amount, t.memo, t.category, t.created_at, t.reversal_of, r.id
+		FROM transactions t
+		LEFT JOIN users f ON f.id = t.from_user_id
+		JOIN users u ON u.id = t.to_user_id
+		LEFT JOIN transfer_reversals r ON r.transaction_id = t.id
+		WHERE t.from_user_id = ? OR t.to_user_id = ?
+		ORDER BY t.id DESC
+		LIMIT ?;`), userID, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []Transaction
+	for rows.Next() {
+		var (
+			t                    Transaction
+			from, memo, category sql.NullString
+			createdAt            sql.NullTime
+			reversalOf, reversal sql.NullInt64
+		)
+		if err = rows.Scan(&t.ID, &from, &t.ToUser, &t.Amount, &memo, &category, &createdAt, &reversalOf, &reversal); err != nil {
+			return nil, err
+		}
+		t.FromUser, t.Memo, t.Category, t.CreatedAt = from.String, memo.String, category.String, nullTimePtr(createdAt)
+		t.ReversalOf, t.Reversed = int(reversalOf.Int64), reversal.Valid
+		res = append(res, t)
+	}
+	return res, rows.Err()
+}
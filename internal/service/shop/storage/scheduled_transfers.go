@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Статусы отложенного перевода.
+const (
+	ScheduledTransferActive    = "active"
+	ScheduledTransferCompleted = "completed"
+	ScheduledTransferCancelled = "cancelled"
+	ScheduledTransferFailed    = "failed"
+)
+
+// ScheduledTransfer — перевод Amount монет от Sender к Recipient в NextRunAt.
+// Пустой Schedule означает разовый перевод, иначе это cron-выражение повторения.
+type ScheduledTransfer struct {
+	ID          int        `json:"id"`
+	SenderID    int        `json:"-"`
+	Sender      string     `json:"sender"`
+	RecipientID int        `json:"-"`
+	Recipient   string     `json:"toUser"`
+	Amount      int        `json:"amount"`
+	Memo        string     `json:"memo,omitempty"`
+	Category    string     `json:"category,omitempty"`
+	Schedule    string     `json:"schedule,omitempty"`
+	Status      string     `json:"status"`
+	NextRunAt   time.Time  `json:"nextRunAt"`
+	Runs        int        `json:"runs"`
+	LastRunAt   *time.Time `json:"lastRunAt,omitempty"`
+	LastError   string     `json:"lastError,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// ScheduledTransferSelect — общая часть выборки отложенных переводов с именами участников;
+// строки разбирает ScanScheduledTransfer.
+const ScheduledTransferSelect = `
+	SELECT st.id, st.sender_id, s.username, st.recipient_id, r.username, st.amount, st.memo, st.category,
+	       st.schedule, st.status, st.next_run_at, st.runs, st.last_run_at, st.last_error, st.created_at
+	FROM scheduled_transfers st
+	JOIN users s ON s.id = st.sender_id
+	JOIN users r ON r.id = st.recipient_id`
+
+// ScanScheduledTransfer читает одну строку ScheduledTransferSelect.
+func ScanScheduledTransfer(row rowScanner) (ScheduledTransfer, error) {
+	var (
+		st                                  ScheduledTransfer
+		memo, category, schedule, lastError sql.NullString
+		lastRunAt                           sql.NullTime
+	)
+	err := row.Scan(&st.ID, &st.SenderID, &st.Sender, &st.RecipientID, &st.Recipient, &st.Amount, &memo, &category,
+		&schedule, &st.Status, &st.NextRunAt, &st.Runs, &lastRunAt, &lastError, &st.CreatedAt)
+	if err != nil {
+		return ScheduledTransfer{}, err
+	}
+	st.Memo, st.Category, st.Schedule, st.LastError = memo.String, category.String, schedule.String, lastError.String
+	st.NextRunAt, st.CreatedAt = st.NextRunAt.UTC(), st.CreatedAt.UTC()
+	if lastRunAt.Valid {
+		t := lastRunAt.Time.UTC()
+		st.LastRunAt = &t
+	}
+	return st, nil
+}
+
+// ScanScheduledTransfers читает все строки ScheduledTransferSelect.
+func ScanScheduledTransfers(rows *sql.Rows) ([]ScheduledTransfer, error) {
+	defer rows.Close()
+
+	var res []ScheduledTransfer
+	for rows.Next() {
+		st, err := ScanScheduledTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, st)
+	}
+	return res, rows.Err()
+}
@@ -0,0 +1,1364 @@
+package sqlite
+
+import (
+	"avito-shop/internal/config"
+	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/service/shop/storage/migrations"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// SchemaVersion — версия схемы БД, которую ожидает текущая сборка сервиса.
+const SchemaVersion = migrations.LatestVersion
+
+// rebind переводит общие запросы пакета storage в диалект SQLite.
+var rebind = migrations.SQLite.Rebind
+
+// reversalSourceQuery не блокирует строку: пишущие транзакции SQLite и так выполняются по одной.
+var reversalSourceQuery = storage.ReversalSourceQuery + ";"
+
+// auditHeadQuery не блокирует строку по той же причине.
+var auditHeadQuery = storage.AuditHeadQuery + ";"
+
+// auditBalanceQuery не блокирует строку по той же причине.
+var auditBalanceQuery = storage.AuditBalanceQuery + ";"
+
+const (
+	// busyTimeout — сколько SQLite ждёт освобождения блокировки, прежде чем вернуть SQLITE_BUSY.
+	busyTimeout = 5 * time.Second
+	// busyRetries — сколько раз повторяется пишущая транзакция, если блокировку так и не удалось получить.
+	busyRetries = 3
+)
+
+type Storage struct {
+	db *sql.DB
+}
+
+func (s *Storage) GetDB() *sql.DB {
+	return s.db
+}
+
+// Close закрывает пул соединений с БД.
+func (s *Storage) Close() error {
+	return s.db.Close()
+}
+
+func NewStorage(db *sql.DB) *Storage {
+	return &Storage{db: db}
+}
+
+// DSN собирает строку подключения: WAL, busy_timeout, внешние ключи и BEGIN IMMEDIATE для пишущих транзакций,
+// чтобы конкурирующие SendCoins/BuyItem ждали блокировку, а не падали при её повышении.
+func DSN(path string) string {
+	q := url.Values{}
+	q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout.Milliseconds()))
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Add("_pragma", "synchronous(NORMAL)")
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Set("_txlock", "immediate")
+	return "file:" + path + "?" + q.Encode()
+}
+
+func New(cfg config.DB) (*Storage, error) {
+	const op = "storage.sqlite.New"
+
+	db, err := Open(cfg.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", op, err)
+	}
+
+	return &Storage{db: db}, nil
+}
+
+// Open открывает файл БД и применяет миграции.
+func Open(path string) (*sql.DB, error) {
+	if path == "" {
+		return nil, errors.New("db.path is not set")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", DSN(path))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err = migrations.Apply(context.Background(), db, migrations.SQLite); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// isBusy сообщает, что операция не дождалась блокировки БД.
+func isBusy(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code() & 0xff
+	return code == sqlite3.SQLITE_BUSY || code == sqlite3.SQLITE_LOCKED
+}
+
+// retryBusy повторяет пишущую транзакцию, если SQLite вернул SQLITE_BUSY несмотря на busy_timeout.
+func retryBusy(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 0; attempt < busyRetries; attempt++ {
+		err = fn()
+		if !isBusy(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt+1) * 50 * time.Millisecond):
+		}
+	}
+	return err
+}
+
+// Ping проверяет доступность БД.
+func (s *Storage) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// CurrentSchemaVersion возвращает последнюю применённую версию схемы.
+func (s *Storage) CurrentSchemaVersion(ctx context.Context) (int, error) {
+	return migrations.CurrentVersion(ctx, s.db)
+}
+
+func (s *Storage) AddNewUser(ctx context.Context, username, passwordHash string) error {
+	return retryBusy(ctx, func() error { return s.addNewUser(ctx, username, passwordHash) })
+}
+
+func (s *Storage) addNewUser(ctx context.Context, username, passwordHash string) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO users (username, password_hash) VALUES (?, ?)", username, passwordHash)
+	if err != nil {
+		return err
+	}
+	if err = storage.GrantInitialLot(ctx, tx, rebind, username, time.Now().UTC().Truncate(time.Second)); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storage) CheckAuth(ctx context.Context, username string) (string, error) {
+	var storedPasswordHash string
+	err := s.db.QueryRowContext(ctx, "SELECT password_hash FROM users WHERE username = ?", username).Scan(&storedPasswordHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", storage.ErrUserNotFound
+		}
+		return "", err
+	}
+	return storedPasswordHash, nil
+}
+
+func (s *Storage) GetInfo(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+	var id int
+
+	err := s.db.QueryRowContext(ctx, "SELECT id, coins FROM users WHERE username = ?;", username).Scan(&id, &ir.Coins)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, storage.ErrUserNotFound
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetFullInfo(ctx context.Context, ir *storage.InfoResponse, username string) (int, error) {
+	rows, err := s.db.QueryContext(ctx, storage.FullInfoQuery, username)
+	if err != nil {
+		return 0, err
+	}
+	return storage.ScanFullInfo(rows, ir)
+}
+
+func (s *Storage) GetInventory(ctx context.Context, ir *storage.InfoResponse, id int) error {
+	rows, err := s.db.QueryContext(ctx, "SELECT item_name, quantity FROM inventory WHERE user_id = ?;", id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i storage.Inventory
+		if err = rows.Scan(&i.Type, &i.Quantity); err != nil {
+			return err
+		}
+		ir.Inventory = append(ir.Inventory, i)
+	}
+	return rows.Err()
+}
+
+func (s *Storage) GetReceivedHistory(ctx context.Context, ir *storage.InfoResponse, id int) error {
+	rows, err := s.db.QueryContext(ctx, "SELECT from_user_id, amount, memo, category FROM transactions WHERE to_user_id = ?;", id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i storage.TransactionIn
+		var memo, category sql.NullString
+		if err = rows.Scan(&i.FromUser, &i.Amount, &memo, &category); err != nil {
+			return err
+		}
+		i.Memo, i.Category = memo.String, category.String
+		ir.CoinHistory.Received = append(ir.CoinHistory.Received, i)
+	}
+	return rows.Err()
+}
+
+func (s *Storage) GetSendHistory(ctx context.Context, ir *storage.InfoResponse, id int) error {
+	rows, err := s.db.QueryContext(ctx, "SELECT to_user_id, amount, memo, category FROM transactions WHERE from_user_id = ?;", id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i storage.TransactionOut
+		var memo, category sql.NullString
+		if err = rows.Scan(&i.ToUser, &i.Amount, &memo, &category); err != nil {
+			return err
+		}
+		i.Memo, i.Category = memo.String, category.String
+		ir.CoinHistory.Sent = append(ir.CoinHistory.Sent, i)
+	}
+	return rows.Err()
+}
+
+func (s *Storage) GetCoinHistory(ctx context.Context, ir *storage.InfoResponse, id int, filter storage.HistoryFilter) error {
+	query, args := storage.CoinHistoryQuery(id, filter)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	return storage.ScanCoinHistory(rows, ir)
+}
+
+func (s *Storage) BuyItem(ctx context.Context, name, item string, amount, priceID int, audit *storage.TxAudit) error {
+	return retryBusy(ctx, func() error { return s.buyItem(ctx, name, item, amount, priceID, nil, audit) })
+}
+
+func (s *Storage) BuyItemWithPromo(ctx context.Context, name, item string, r *storage.PromoRedemption, audit *storage.TxAudit) error {
+	return retryBusy(ctx, func() error { return s.buyItem(ctx, name, item, r.Price-r.Discount, r.PriceID, r, audit) })
+}
+
+func (s *Storage) buyItem(ctx context.Context, name, item string, amount, priceID int, promo *storage.PromoRedemption, audit *storage.TxAudit) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+	if err = storage.AuditBefore(ctx, tx, auditBalanceQuery, audit); err != nil {
+		return err
+	}
+	query := `INSERT INTO inventory (user_id, item_name, quantity)
+			VALUES ((SELECT id FROM users WHERE username = ?), ?, 1)
+			ON CONFLICT (user_id, item_name) DO UPDATE SET quantity = quantity + 1;`
+	_, err = tx.ExecContext(ctx, query, name, item)
+	if err != nil {
+		return err
+	}
+	// Баланс проверяется в том же операторе, что и списание: параллельная покупка или перевод
+	// не уведут его в минус между проверкой в сервисе и этой транзакцией.
+	res, err := tx.ExecContext(ctx, "UPDATE users SET coins = coins - ? WHERE username = ? AND coins >= ?", amount, name, amount)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n != 1 {
+		err = storage.ErrInsufficientFunds
+		return err
+	}
+
+	var userID int
+	if err = tx.QueryRowContext(ctx, "SELECT id FROM users WHERE username = ?;", name).Scan(&userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = storage.ErrUserNotFound
+		}
+		return err
+	}
+	if err = storage.MoveLots(ctx, tx, rebind, userID, 0, amount); err != nil {
+		return err
+	}
+	price, at := amount, time.Now().UTC().Truncate(time.Second)
+	if promo != nil {
+		if err = storage.RedeemPromoCode(ctx, tx, rebind, userID, item, promo); err != nil {
+			return err
+		}
+		price, at = promo.Price, promo.RedeemedAt
+	}
+	if err = storage.InsertOrder(ctx, tx, rebind, userID, item, price, priceID, amount, at); err != nil {
+		return err
+	}
+
+	if err = commitAudit(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storage) SendCoins(ctx context.Context, username string, fromUserID int, toUserID int, scr *storage.SendCoinRequest, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+	return retryBusy(ctx, func() error { return s.sendCoins(ctx, username, fromUserID, toUserID, scr, limits, fee, audit) })
+}
+
+func (s *Storage) sendCoins(ctx context.Context, username string, fromUserID int, toUserID int, scr *storage.SendCoinRequest, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+	if err = storage.AuditBefore(ctx, tx, auditBalanceQuery, audit); err != nil {
+		return err
+	}
+	if err = checkTransferLimits(ctx, tx, fromUserID, toUserID, scr.Amount, limits); err != nil {
+		return err
+	}
+
+	query := `
+	  UPDATE users
+	  SET coins = CASE
+	      WHEN username = ? THEN coins - ?
+	      WHEN username = ? THEN coins + ?
+	  END
+	  WHERE username IN (?, ?) AND (username <> ? OR coins >= ?);
+	`
+	// Баланс отправителя проверяется вместе с комиссией в том же операторе, что и списание.
+	res, err := tx.ExecContext(ctx, query, username, scr.Amount, scr.ToUser, scr.Amount, username, scr.ToUser,
+		username, scr.Amount+fee.Amount)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n != 2 {
+		err = storage.ErrInsufficientFunds
+		return err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	_, err = tx.ExecContext(ctx, "INSERT INTO transactions(from_user_id, to_user_id, amount, memo, category, created_at) VALUES (?, ?, ?, ?, ?, ?);",
+		fromUserID, toUserID, scr.Amount, storage.NullString(scr.Memo), storage.NullString(scr.Category), now)
+	if err != nil {
+		return err
+	}
+	if err = storage.MoveLots(ctx, tx, rebind, fromUserID, toUserID, scr.Amount); err != nil {
+		return err
+	}
+	if err = chargeTransferFee(ctx, tx, fromUserID, fee, now); err != nil {
+		return err
+	}
+	if err = commitAudit(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storage) CreatePaymentRequest(ctx context.Context, pr *storage.PaymentRequest) error {
+	return retryBusy(ctx, func() error {
+		res, err := s.db.ExecContext(ctx, `INSERT INTO payment_requests
+			(requester_id, payer_id, amount, memo, status, created_at, expires_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?);`,
+			pr.RequesterID, pr.PayerID, pr.Amount, storage.NullString(pr.Memo), storage.PaymentRequestPending,
+			pr.CreatedAt, pr.ExpiresAt)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		pr.ID = int(id)
+		pr.Status = storage.PaymentRequestPending
+		return nil
+	})
+}
+
+func (s *Storage) GetPaymentRequest(ctx context.Context, id int) (*storage.PaymentRequest, error) {
+	row := s.db.QueryRowContext(ctx, storage.PaymentRequestSelect+" WHERE pr.id = ?;", id)
+	pr, err := storage.ScanPaymentRequest(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrPaymentRequestNotFound
+		}
+		return nil, err
+	}
+	return &pr, nil
+}
+
+func (s *Storage) ListPendingPaymentRequests(ctx context.Context, userID int, incoming bool, now time.Time) ([]storage.PaymentRequest, error) {
+	column := "pr.requester_id"
+	if incoming {
+		column = "pr.payer_id"
+	}
+	rows, err := s.db.QueryContext(ctx, storage.PaymentRequestSelect+" WHERE "+column+" = ? AND pr.status = ? AND pr.expires_at > ? ORDER BY pr.id;",
+		userID, storage.PaymentRequestPending, now)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanPaymentRequests(rows)
+}
+
+func (s *Storage) AcceptPaymentRequest(ctx context.Context, id int, now time.Time, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+	return retryBusy(ctx, func() error { return s.acceptPaymentRequest(ctx, id, now, limits, fee, audit) })
+}
+
+func (s *Storage) acceptPaymentRequest(ctx context.Context, id int, now time.Time, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+	if err = storage.AuditBefore(ctx, tx, auditBalanceQuery, audit); err != nil {
+		return err
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE payment_requests SET status = ?, resolved_at = ?
+		WHERE id = ? AND status = ? AND expires_at > ?;`,
+		storage.PaymentRequestAccepted, now, id, storage.PaymentRequestPending, now)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		err = storage.ErrPaymentRequestNotPending
+		return err
+	}
+
+	var (
+		requesterID, payerID, amount int
+		memo                         sql.NullString
+	)
+	err = tx.QueryRowContext(ctx, "SELECT requester_id, payer_id, amount, memo FROM payment_requests WHERE id = ?;", id).
+		Scan(&requesterID, &payerID, &amount, &memo)
+	if err != nil {
+		return err
+	}
+
+	if err = checkTransferLimits(ctx, tx, payerID, requesterID, amount, limits); err != nil {
+		return err
+	}
+
+	// Списание и зачисление одним оператором: баланс плательщика вместе с комиссией проверяется под блокировкой строки.
+	res, err = tx.ExecContext(ctx, `
+	  UPDATE users
+	  SET coins = CASE WHEN id = ? THEN coins - ? ELSE coins + ? END
+	  WHERE id IN (?, ?) AND (id <> ? OR coins >= ?);`,
+		payerID, amount, amount, payerID, requesterID, payerID, amount+fee.Amount)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n != 2 {
+		err = storage.ErrInsufficientFunds
+		return err
+	}
+
+	if err = storage.MoveLots(ctx, tx, rebind, payerID, requesterID, amount); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO transactions(from_user_id, to_user_id, amount, memo, created_at) VALUES (?, ?, ?, ?, ?);",
+		payerID, requesterID, amount, memo, now)
+	if err != nil {
+		return err
+	}
+	if err = chargeTransferFee(ctx, tx, payerID, fee, now); err != nil {
+		return err
+	}
+
+	if err = commitAudit(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storage) ResolvePaymentRequest(ctx context.Context, id int, status string, now time.Time) error {
+	return retryBusy(ctx, func() error {
+		res, err := s.db.ExecContext(ctx, "UPDATE payment_requests SET status = ?, resolved_at = ? WHERE id = ? AND status = ?;",
+			status, now, id, storage.PaymentRequestPending)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return storage.ErrPaymentRequestNotPending
+		}
+		return nil
+	})
+}
+
+func (s *Storage) CreateScheduledTransfer(ctx context.Context, st *storage.ScheduledTransfer) error {
+	return retryBusy(ctx, func() error {
+		res, err := s.db.ExecContext(ctx, `INSERT INTO scheduled_transfers
+			(sender_id, recipient_id, amount, memo, category, schedule, status, next_run_at, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);`,
+			st.SenderID, st.RecipientID, st.Amount, storage.NullString(st.Memo), storage.NullString(st.Category),
+			storage.NullString(st.Schedule), storage.ScheduledTransferActive, st.NextRunAt, st.CreatedAt)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		st.ID = int(id)
+		st.Status = storage.ScheduledTransferActive
+		return nil
+	})
+}
+
+func (s *Storage) GetScheduledTransfer(ctx context.Context, id int) (*storage.ScheduledTransfer, error) {
+	row := s.db.QueryRowContext(ctx, storage.ScheduledTransferSelect+" WHERE st.id = ?;", id)
+	st, err := storage.ScanScheduledTransfer(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrScheduledTransferNotFound
+		}
+		return nil, err
+	}
+	return &st, nil
+}
+
+func (s *Storage) ListScheduledTransfers(ctx context.Context, senderID int) ([]storage.ScheduledTransfer, error) {
+	rows, err := s.db.QueryContext(ctx, storage.ScheduledTransferSelect+" WHERE st.sender_id = ? ORDER BY st.id;", senderID)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanScheduledTransfers(rows)
+}
+
+func (s *Storage) ListDueScheduledTransfers(ctx context.Context, now time.Time, limit int) ([]storage.ScheduledTransfer, error) {
+	rows, err := s.db.QueryContext(ctx, storage.ScheduledTransferSelect+" WHERE st.status = ? AND st.next_run_at <= ? ORDER BY st.next_run_at, st.id LIMIT ?;",
+		storage.ScheduledTransferActive, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanScheduledTransfers(rows)
+}
+
+func (s *Storage) CancelScheduledTransfer(ctx context.Context, id int) error {
+	return retryBusy(ctx, func() error {
+		res, err := s.db.ExecContext(ctx, "UPDATE scheduled_transfers SET status = ? WHERE id = ? AND status = ?;",
+			storage.ScheduledTransferCancelled, id, storage.ScheduledTransferActive)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return storage.ErrScheduledTransferNotActive
+		}
+		return nil
+	})
+}
+
+func (s *Storage) RunScheduledTransfer(ctx context.Context, st *storage.ScheduledTransfer, next *time.Time, now time.Time, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) error {
+	var failure error
+	err := retryBusy(ctx, func() (err error) {
+		failure, err = s.runScheduledTransfer(ctx, st, next, now, limits, fee, audit)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return failure
+}
+
+// runScheduledTransfer фиксирует запуск и возвращает в failure причину, по которой монеты не переведены
+// (нехватка средств или лимит): в этом случае транзакция всё равно коммитится, чтобы запуск не повторялся.
+func (s *Storage) runScheduledTransfer(ctx context.Context, st *storage.ScheduledTransfer, next *time.Time, now time.Time, limits storage.TransferLimits, fee storage.TransferFee, audit *storage.TxAudit) (failure error, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+	if err = storage.AuditBefore(ctx, tx, auditBalanceQuery, audit); err != nil {
+		return nil, err
+	}
+
+	status, nextRunAt := storage.ScheduledTransferActive, st.NextRunAt
+	if next == nil {
+		status = storage.ScheduledTransferCompleted
+	} else {
+		nextRunAt = *next
+	}
+
+	// Захват запуска: счётчик runs совпадёт только у первой реплики, остальные обновят 0 строк.
+	res, err := tx.ExecContext(ctx, `UPDATE scheduled_transfers
+		SET runs = runs + 1, status = ?, next_run_at = ?, last_run_at = ?, last_error = NULL
+		WHERE id = ? AND status = ? AND runs = ?;`,
+		status, nextRunAt, now, st.ID, storage.ScheduledTransferActive, st.Runs)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		err = storage.ErrScheduledTransferClaimed
+		return nil, err
+	}
+
+	failure = checkTransferLimits(ctx, tx, st.SenderID, st.RecipientID, st.Amount, limits)
+	if failure == nil {
+		// Списание отдельным условным оператором: при нехватке монет на сумму с комиссией не меняется ни один баланс,
+		// и транзакцию можно закоммитить вместе с отметкой о неудачном запуске.
+		res, err = tx.ExecContext(ctx, "UPDATE users SET coins = coins - ? WHERE id = ? AND coins >= ?;",
+			st.Amount, st.SenderID, st.Amount+fee.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			failure = storage.ErrInsufficientFunds
+		}
+	}
+	var limitErr *storage.LimitExceededError
+	if failure != nil && !errors.Is(failure, storage.ErrInsufficientFunds) && !errors.As(failure, &limitErr) {
+		err = failure
+		return nil, err
+	}
+	if failure != nil {
+		if next == nil {
+			status = storage.ScheduledTransferFailed
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE scheduled_transfers SET status = ?, last_error = ? WHERE id = ?;",
+			status, failure.Error(), st.ID)
+		if err != nil {
+			return nil, err
+		}
+		err = tx.Commit()
+		return failure, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE users SET coins = coins + ? WHERE id = ?;", st.Amount, st.RecipientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = storage.MoveLots(ctx, tx, rebind, st.SenderID, st.RecipientID, st.Amount); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO transactions(from_user_id, to_user_id, amount, memo, category, created_at) VALUES (?, ?, ?, ?, ?, ?);",
+		st.SenderID, st.RecipientID, st.Amount, storage.NullString(st.Memo), storage.NullString(st.Category), now)
+	if err != nil {
+		return nil, err
+	}
+	if err = chargeTransferFee(ctx, tx, st.SenderID, fee, now); err != nil {
+		return nil, err
+	}
+
+	if err = commitAudit(ctx, tx, audit); err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	return nil, err
+}
+
+func (s *Storage) GetUserRole(ctx context.Context, id int) (string, error) {
+	var role string
+	err := s.db.QueryRowContext(ctx, "SELECT role FROM users WHERE id = ?;", id).Scan(&role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", storage.ErrUserNotFound
+	}
+	return role, err
+}
+
+func (s *Storage) SetUserRole(ctx context.Context, username, role string) error {
+	return retryBusy(ctx, func() error {
+		res, err := s.db.ExecContext(ctx, "UPDATE users SET role = ? WHERE username = ?;", role, username)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return storage.ErrUserNotFound
+		}
+		return nil
+	})
+}
+
+func (s *Storage) EnsureSystemAccount(ctx context.Context, username string) (id int, err error) {
+	err = retryBusy(ctx, func() error {
+		id, err = s.ensureSystemAccount(ctx, username)
+		return err
+	})
+	return id, err
+}
+
+func (s *Storage) ensureSystemAccount(ctx context.Context, username string) (int, error) {
+	const query = "SELECT id, role FROM users WHERE username = ?;"
+	var (
+		id   int
+		role string
+	)
+	err := s.db.QueryRowContext(ctx, query, username).Scan(&id, &role)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Пустой хэш не совпадает ни с одним паролем, поэтому войти под служебным счётом нельзя.
+		// Если счёт одновременно создала другая реплика, вставка не пройдёт, а её счёт прочитается ниже.
+		s.db.ExecContext(ctx, "INSERT INTO users (username, password_hash, coins, role) VALUES (?, '', 0, ?);", username, storage.SystemRole)
+		err = s.db.QueryRowContext(ctx, query, username).Scan(&id, &role)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, storage.ErrUserNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if role != storage.SystemRole {
+		return 0, storage.ErrNotSystemAccount
+	}
+	return id, nil
+}
+
+func (s *Storage) GetTransferTotals(ctx context.Context, userID int, since time.Time) (int, int, error) {
+	return storage.TransferTotals(ctx, s.db, storage.TransferTotalsQuery, userID, since)
+}
+
+func (s *Storage) GetCoinLots(ctx context.Context, userID int) ([]storage.CoinLot, error) {
+	rows, err := s.db.QueryContext(ctx, storage.CoinLotsQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanCoinLots(rows)
+}
+
+func (s *Storage) ListExpiredCoins(ctx context.Context, cutoff time.Time, limit int) ([]storage.ExpiredCoins, error) {
+	rows, err := s.db.QueryContext(ctx, storage.ExpiredCoinsQuery, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanExpiredCoins(rows)
+}
+
+func (s *Storage) ExpireCoins(ctx context.Context, userID, sinkID int, cutoff, now time.Time, audit *storage.TxAudit) (amount int, err error) {
+	err = retryBusy(ctx, func() error {
+		amount, err = s.expireCoins(ctx, userID, sinkID, cutoff, now, audit)
+		return err
+	})
+	return amount, err
+}
+
+func (s *Storage) expireCoins(ctx context.Context, userID, sinkID int, cutoff, now time.Time, audit *storage.TxAudit) (amount int, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+	if err = storage.AuditBefore(ctx, tx, auditBalanceQuery, audit); err != nil {
+		return 0, err
+	}
+
+	if amount, err = storage.BurnExpiredLots(ctx, tx, rebind, userID, sinkID, cutoff, now); err != nil {
+		return 0, err
+	}
+	if amount > 0 {
+		if err = commitAudit(ctx, tx, audit); err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit()
+	return amount, err
+}
+
+func (s *Storage) CreatePromoCode(ctx context.Context, pc *storage.PromoCode) error {
+	return retryBusy(ctx, func() error {
+		var n int
+		if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM promo_codes WHERE code = ?;", pc.Code).Scan(&n); err != nil {
+			return err
+		}
+		if n > 0 {
+			return storage.ErrPromoCodeExists
+		}
+		res, err := s.db.ExecContext(ctx, storage.InsertPromoCodeQuery+";", storage.PromoCodeArgs(pc)...)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		pc.ID = int(id)
+		return nil
+	})
+}
+
+func (s *Storage) GetPromoCode(ctx context.Context, code string) (*storage.PromoCode, error) {
+	row := s.db.QueryRowContext(ctx, storage.PromoCodeSelect+" WHERE code = ?;", code)
+	pc, err := storage.ScanPromoCode(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrPromoCodeNotFound
+		}
+		return nil, err
+	}
+	return &pc, nil
+}
+
+func (s *Storage) ListPromoCodes(ctx context.Context) ([]storage.PromoCode, error) {
+	rows, err := s.db.QueryContext(ctx, storage.PromoCodeSelect+" ORDER BY id;")
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanPromoCodes(rows)
+}
+
+func (s *Storage) DisablePromoCode(ctx context.Context, code string, now time.Time) error {
+	return retryBusy(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, "UPDATE promo_codes SET disabled_at = ? WHERE code = ? AND disabled_at IS NULL;", now, code)
+		return err
+	})
+}
+
+func (s *Storage) CountPromoRedemptions(ctx context.Context, promoCodeID, userID int) (n int, err error) {
+	err = s.db.QueryRowContext(ctx, storage.PromoRedemptionsQuery, promoCodeID, userID).Scan(&n)
+	return n, err
+}
+
+func (s *Storage) CreateItemPrice(ctx context.Context, p *storage.ItemPrice) error {
+	return retryBusy(ctx, func() error {
+		res, err := s.db.ExecContext(ctx, storage.InsertItemPriceQuery+";", storage.ItemPriceArgs(p)...)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		p.ID = int(id)
+		return nil
+	})
+}
+
+func (s *Storage) GetItemPrice(ctx context.Context, id int) (*storage.ItemPrice, error) {
+	p, err := storage.ScanItemPrice(s.db.QueryRowContext(ctx, storage.ItemPriceSelect+" WHERE id = ?;", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrItemPriceNotFound
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *Storage) ListItemPrices(ctx context.Context, now time.Time) ([]storage.ItemPrice, error) {
+	rows, err := s.db.QueryContext(ctx, storage.ItemPricesQuery, now)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanItemPrices(rows)
+}
+
+func (s *Storage) DeleteItemPrice(ctx context.Context, id int) error {
+	return retryBusy(ctx, func() error {
+		res, err := s.db.ExecContext(ctx, "DELETE FROM item_prices WHERE id = ?;", id)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return storage.ErrItemPriceNotFound
+		}
+		return nil
+	})
+}
+
+func (s *Storage) ListOrders(ctx context.Context, userID int) ([]storage.Order, error) {
+	rows, err := s.db.QueryContext(ctx, storage.OrdersQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanOrders(rows)
+}
+
+func (s *Storage) GetLeaderboard(ctx context.Context, board string, since *time.Time, limit int) ([]storage.LeaderboardEntry, error) {
+	query, args := storage.LeaderboardQuery(board, since, limit)
+	if query == "" {
+		return nil, storage.ErrUnknownLeaderboard
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanLeaderboard(rows)
+}
+
+func (s *Storage) SetLeaderboardOptOut(ctx context.Context, userID int, optOut bool) error {
+	return retryBusy(ctx, func() error {
+		_, err := s.db.ExecContext(ctx, "UPDATE users SET leaderboard_opt_out = ? WHERE id = ?;", optOut, userID)
+		return err
+	})
+}
+
+func (s *Storage) GetAchievementProgress(ctx context.Context, userID int) (*storage.AchievementProgress, error) {
+	return storage.LoadAchievementProgress(ctx, s.db, rebind, userID)
+}
+
+func (s *Storage) AwardAchievement(ctx context.Context, userID int, a *storage.Achievement) (bool, error) {
+	var awarded bool
+	err := retryBusy(ctx, func() error {
+		res, err := s.db.ExecContext(ctx, storage.InsertAchievementQuery+" ON CONFLICT (user_id, code) DO NOTHING;", storage.AchievementArgs(userID, a)...)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		awarded = n > 0
+		return err
+	})
+	return awarded, err
+}
+
+func (s *Storage) ListAchievements(ctx context.Context, userID int) ([]storage.Achievement, error) {
+	rows, err := s.db.QueryContext(ctx, storage.AchievementsQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanAchievements(rows)
+}
+
+func (s *Storage) CreateGroup(ctx context.Context, g *storage.Group, ownerID int) error {
+	return retryBusy(ctx, func() error { return s.createGroup(ctx, g, ownerID) })
+}
+
+func (s *Storage) createGroup(ctx context.Context, g *storage.Group, ownerID int) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+
+	if err = storage.CreateGroupTx(ctx, tx, rebind, g, ownerID); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storage) GetGroup(ctx context.Context, name string) (*storage.Group, error) {
+	g, err := storage.ScanGroup(s.db.QueryRowContext(ctx, storage.GroupSelect+" WHERE u.username = ?;", name))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrGroupNotFound
+		}
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (s *Storage) ListUserGroups(ctx context.Context, userID int) ([]storage.Group, error) {
+	rows, err := s.db.QueryContext(ctx, storage.UserGroupsQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanUserGroups(rows)
+}
+
+func (s *Storage) ListGroupMembers(ctx context.Context, groupID int) ([]storage.GroupMember, error) {
+	rows, err := s.db.QueryContext(ctx, storage.GroupMembersQuery, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ScanGroupMembers(rows)
+}
+
+func (s *Storage) GetGroupMemberRole(ctx context.Context, groupID, userID int) (string, error) {
+	return storage.GroupMemberRole(ctx, s.db, rebind, groupID, userID)
+}
+
+func (s *Storage) SetGroupMember(ctx context.Context, groupID, userID int, role string, at time.Time) error {
+	return retryBusy(ctx, func() error {
+		return storage.SetGroupMemberTx(ctx, s.db, rebind, groupID, userID, role, at)
+	})
+}
+
+func (s *Storage) RemoveGroupMember(ctx context.Context, groupID, userID int) error {
+	return retryBusy(ctx, func() error {
+		res, err := s.db.ExecContext(ctx, "DELETE FROM group_members WHERE group_id = ? AND user_id = ?;", groupID, userID)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return storage.ErrNotGroupMember
+		}
+		return nil
+	})
+}
+
+func (s *Storage) CreateGroupPurchase(ctx context.Context, p *storage.GroupPurchase, proposerID int) error {
+	return retryBusy(ctx, func() error {
+		res, err := s.db.ExecContext(ctx, storage.InsertGroupPurchaseQuery+";", storage.GroupPurchaseArgs(p, proposerID)...)
+		if err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		p.ID = int(id)
+		return nil
+	})
+}
+
+func (s *Storage) GetGroupPurchase(ctx context.Context, id int) (*storage.GroupPurchase, error) {
+	return storage.LoadGroupPurchase(ctx, s.db, rebind, id)
+}
+
+func (s *Storage) ListGroupPurchases(ctx context.Context, groupID int) ([]storage.GroupPurchase, error) {
+	return storage.LoadGroupPurchases(ctx, s.db, rebind, "p.group_id = ?", groupID)
+}
+
+func (s *Storage) ApproveGroupPurchase(ctx context.Context, id, userID, price, priceID int, at time.Time, audit *storage.TxAudit) (p *storage.GroupPurchase, err error) {
+	err = retryBusy(ctx, func() error {
+		p, err = s.approveGroupPurchase(ctx, id, userID, price, priceID, at, audit)
+		return err
+	})
+	return p, err
+}
+
+func (s *Storage) approveGroupPurchase(ctx context.Context, id, userID, price, priceID int, at time.Time, audit *storage.TxAudit) (p *storage.GroupPurchase, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+	if err = storage.AuditBefore(ctx, tx, auditBalanceQuery, audit); err != nil {
+		return nil, err
+	}
+
+	if p, err = storage.ApproveGroupPurchaseTx(ctx, tx, rebind, id, userID, price, priceID, at); err != nil {
+		return nil, err
+	}
+	if p.Status == storage.GroupPurchaseCompleted {
+		if err = commitAudit(ctx, tx, audit); err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	return p, err
+}
+
+func (s *Storage) CancelGroupPurchase(ctx context.Context, id int, at time.Time) error {
+	return retryBusy(ctx, func() error {
+		return storage.CancelGroupPurchase(ctx, s.db, rebind, id, at)
+	})
+}
+
+func (s *Storage) CreatePendingTransfer(ctx context.Context, pt *storage.PendingTransfer, limits storage.TransferLimits, audit *storage.TxAudit) error {
+	return retryBusy(ctx, func() error { return s.createPendingTransfer(ctx, pt, limits, audit) })
+}
+
+func (s *Storage) createPendingTransfer(ctx context.Context, pt *storage.PendingTransfer, limits storage.TransferLimits, audit *storage.TxAudit) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+	if err = storage.AuditBefore(ctx, tx, auditBalanceQuery, audit); err != nil {
+		return err
+	}
+
+	if err = checkTransferLimits(ctx, tx, pt.SenderID, pt.RecipientID, pt.Amount, limits); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, storage.InsertPendingTransferQuery+";", storage.PendingTransferArgs(pt)...)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	pt.ID = int(id)
+	audit.Created(pt.ID)
+	if err = storage.HoldTransferTx(ctx, tx, rebind, pt); err != nil {
+		return err
+	}
+
+	if err = commitAudit(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storage) GetPendingTransfer(ctx context.Context, id int) (*storage.PendingTransfer, error) {
+	return storage.LoadPendingTransfer(ctx, s.db, rebind, id)
+}
+
+func (s *Storage) ListPendingTransfers(ctx context.Context, senderID int, now time.Time) ([]storage.PendingTransfer, error) {
+	if senderID == 0 {
+		return storage.LoadPendingTransfers(ctx, s.db, rebind, 0, "t.status = ? AND t.expires_at > ?", storage.PendingTransferPending, now)
+	}
+	return storage.LoadPendingTransfers(ctx, s.db, rebind, 0, "t.sender_id = ? AND t.status = ? AND t.expires_at > ?",
+		senderID, storage.PendingTransferPending, now)
+}
+
+func (s *Storage) ListExpiredPendingTransfers(ctx context.Context, now time.Time, limit int) ([]storage.PendingTransfer, error) {
+	return storage.LoadPendingTransfers(ctx, s.db, rebind, limit, "t.status = ? AND t.expires_at <= ?", storage.PendingTransferPending, now)
+}
+
+func (s *Storage) ResolvePendingTransfer(ctx context.Context, id int, status string, resolverID int, at time.Time, limits storage.TransferLimits, audit *storage.TxAudit) (pt *storage.PendingTransfer, err error) {
+	err = retryBusy(ctx, func() error {
+		pt, err = s.resolvePendingTransfer(ctx, id, status, resolverID, at, limits, audit)
+		return err
+	})
+	return pt, err
+}
+
+func (s *Storage) resolvePendingTransfer(ctx context.Context, id int, status string, resolverID int, at time.Time, limits storage.TransferLimits, audit *storage.TxAudit) (pt *storage.PendingTransfer, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+
+	if pt, err = storage.ClaimPendingTransferTx(ctx, tx, rebind, id, status, resolverID, at); err != nil {
+		return nil, err
+	}
+	if err = storage.AuditPartiesBefore(ctx, tx, auditBalanceQuery, audit, pt.SenderID, pt.RecipientID); err != nil {
+		return nil, err
+	}
+	if status == storage.PendingTransferApproved {
+		if err = checkTransferLimits(ctx, tx, pt.SenderID, pt.RecipientID, pt.Amount, limits); err != nil {
+			return nil, err
+		}
+	}
+	if err = storage.SettlePendingTransferTx(ctx, tx, rebind, pt, at); err != nil {
+		return nil, err
+	}
+	if err = commitAudit(ctx, tx, audit); err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	return pt, err
+}
+
+func (s *Storage) ReverseTransaction(ctx context.Context, rv *storage.TransferReversal, audit *storage.TxAudit) error {
+	return retryBusy(ctx, func() error {
+		return s.reverseTransaction(ctx, rv, audit)
+	})
+}
+
+func (s *Storage) reverseTransaction(ctx context.Context, rv *storage.TransferReversal, audit *storage.TxAudit) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+
+	if err = storage.LoadReversalSource(ctx, tx, reversalSourceQuery, rv); err != nil {
+		return err
+	}
+	if err = lockTransferParties(ctx, tx, rv.FromUserID, rv.ToUserID); err != nil {
+		return err
+	}
+	if err = storage.AuditPartiesBefore(ctx, tx, auditBalanceQuery, audit, rv.FromUserID, rv.ToUserID); err != nil {
+		return err
+	}
+	if err = storage.ReverseTransactionTx(ctx, tx, rebind, rv); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, storage.InsertTransferReversalQuery+";", storage.TransferReversalArgs(rv)...)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	rv.ID = int(id)
+	if err = commitAudit(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storage) ListUserTransactions(ctx context.Context, userID, limit int) ([]storage.Transaction, error) {
+	return storage.LoadUserTransactions(ctx, s.db, rebind, userID, limit)
+}
+
+func (s *Storage) AppendAudit(ctx context.Context, e *storage.AuditEntry) error {
+	return retryBusy(ctx, func() error {
+		return s.appendAudit(ctx, e)
+	})
+}
+
+func (s *Storage) appendAudit(ctx context.Context, e *storage.AuditEntry) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			log.Println("Transaction rolled back")
+		}
+	}()
+
+	if err = chainAudit(ctx, tx, e); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	return err
+}
+
+// chainAudit дописывает e в конец журнала аудита в транзакции tx.
+func chainAudit(ctx context.Context, tx *sql.Tx, e *storage.AuditEntry) error {
+	if err := storage.ChainAuditEntry(ctx, tx, auditHeadQuery, e); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, storage.InsertAuditEntryQuery+";", storage.AuditEntryArgs(e)...)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	e.ID = int(id)
+	return storage.AdvanceAuditHead(ctx, tx, rebind, e)
+}
+
+// commitAudit читает балансы после операции и дописывает запись a в журнал в транзакции операции;
+// при nil a ничего не делает.
+func commitAudit(ctx context.Context, tx *sql.Tx, a *storage.TxAudit) error {
+	if a == nil {
+		return nil
+	}
+	if err := storage.AuditAfter(ctx, tx, auditBalanceQuery, a); err != nil {
+		return err
+	}
+	return chainAudit(ctx, tx, a.Entry)
+}
+
+func (s *Storage) ListAudit(ctx context.Context, filter storage.AuditFilter) ([]storage.AuditEntry, error) {
+	return storage.LoadAuditEntries(ctx, s.db, rebind, filter)
+}
+
+func (s *Storage) GetAuditHead(ctx context.Context) (*storage.AuditHead, error) {
+	return storage.LoadAuditHead(ctx, s.db)
+}
+
+// chargeTransferFee списывает комиссию с отправителя и записывает её отдельным переводом на служебный счёт.
+func chargeTransferFee(ctx context.Context, tx *sql.Tx, fromUserID int, fee storage.TransferFee, at time.Time) error {
+	if fee.Amount <= 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET coins = coins - ? WHERE id = ?;", fee.Amount, fromUserID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET coins = coins + ? WHERE id = ?;", fee.Amount, fee.AccountID); err != nil {
+		return err
+	}
+	if err := storage.MoveLots(ctx, tx, rebind, fromUserID, fee.AccountID, fee.Amount); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, "INSERT INTO transactions(from_user_id, to_user_id, amount, category, created_at) VALUES (?, ?, ?, ?, ?);",
+		fromUserID, fee.AccountID, fee.Amount, storage.FeeCategory, at)
+	return err
+}
+
+// lockTransferParties ничего не делает: транзакции SQLite открываются с _txlock=immediate
+// и выполняются по одной, поэтому суммы переводов не меняются до коммита.
+func lockTransferParties(ctx context.Context, tx *sql.Tx, ids ...int) error {
+	return nil
+}
+
+// checkTransferLimits блокирует участников и проверяет суточные лимиты; без лимитов ничего не делает.
+func checkTransferLimits(ctx context.Context, tx *sql.Tx, fromID, toID, amount int, limits storage.TransferLimits) error {
+	if !limits.Enabled() {
+		return nil
+	}
+	if err := lockTransferParties(ctx, tx, fromID, toID); err != nil {
+		return err
+	}
+	return storage.CheckTransferLimits(ctx, tx, storage.TransferTotalsQuery, fromID, toID, amount, limits)
+}
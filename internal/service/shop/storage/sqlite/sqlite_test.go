@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"avito-shop/internal/service/shop/storage"
+	"avito-shop/internal/service/shop/storage/storagetest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStorage(tb testing.TB) storage.IStorage {
+	db, err := Open(filepath.Join(tb.TempDir(), "shop.db"))
+	if err != nil {
+		tb.Fatalf("failed to open database: %v", err)
+	}
+	tb.Cleanup(func() { db.Close() })
+
+	return NewStorage(db)
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, newTestStorage)
+}
+
+func BenchmarkInfo(b *testing.B) {
+	storagetest.BenchmarkInfo(b, newTestStorage)
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	for _, username := range []string{"sender", "recipient"} {
+		require.NoError(t, s.AddNewUser(ctx, username, "hashed_password"))
+	}
+	var sender, recipient storage.InfoResponse
+	senderID, err := s.GetInfo(ctx, &sender, "sender")
+	require.NoError(t, err)
+	recipientID, err := s.GetInfo(ctx, &recipient, "recipient")
+	require.NoError(t, err)
+
+	const workers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*2)
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			errs <- s.SendCoins(ctx, "sender", senderID, recipientID, &storage.SendCoinRequest{ToUser: "recipient", Amount: 10}, storage.TransferLimits{}, storage.TransferFee{}, nil)
+		}()
+		go func() {
+			defer wg.Done()
+			errs <- s.BuyItem(ctx, "recipient", "pen", 10, 0, nil)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	sender, recipient = storage.InfoResponse{}, storage.InfoResponse{}
+	_, err = s.GetInfo(ctx, &sender, "sender")
+	require.NoError(t, err)
+	_, err = s.GetInfo(ctx, &recipient, "recipient")
+	require.NoError(t, err)
+	require.Equal(t, 1000-workers*10, sender.Coins)
+	require.Equal(t, 1000+workers*10-workers*10, recipient.Coins)
+
+	require.NoError(t, s.GetInventory(ctx, &recipient, recipientID))
+	require.Equal(t, []storage.Inventory{{Type: "pen", Quantity: workers}}, recipient.Inventory)
+}
@@ -1,20 +1,209 @@
 package storage
 
+import (
+	"context"
+	"time"
+)
+
 type IStorage interface {
-	CheckAuth(username string) (string, error)
-	AddNewUser(username, password string) error
-	GetInfo(ir *InfoResponse, username string) (int, error)
-	GetInventory(ir *InfoResponse, id int) error
-	GetReceivedHistory(ir *InfoResponse, id int) error
-	GetSendHistory(ir *InfoResponse, id int) error
-	BuyItem(name, item string, amount int) error
-	SendCoins(username string, fromUserID int, toUserID int, scr *SendCoinRequest) error
+	CheckAuth(ctx context.Context, username string) (string, error)
+	AddNewUser(ctx context.Context, username, password string) error
+	GetInfo(ctx context.Context, ir *InfoResponse, username string) (int, error)
+	// GetFullInfo заполняет баланс, инвентарь и историю пользователя за одно обращение к БД.
+	GetFullInfo(ctx context.Context, ir *InfoResponse, username string) (int, error)
+	GetInventory(ctx context.Context, ir *InfoResponse, id int) error
+	GetReceivedHistory(ctx context.Context, ir *InfoResponse, id int) error
+	GetSendHistory(ctx context.Context, ir *InfoResponse, id int) error
+	// GetCoinHistory заполняет обе истории переводов пользователя с учётом фильтра.
+	GetCoinHistory(ctx context.Context, ir *InfoResponse, id int, filter HistoryFilter) error
+	// BuyItem покупает предмет за amount монет и записывает заказ (см. Order) с этой ценой и id записи
+	// цены priceID, по которой она посчитана (0 — базовая цена). Если у пользователя меньше amount монет,
+	// возвращает ErrInsufficientFunds. Запись audit (см. TxAudit) добавляется в журнал в той же транзакции.
+	BuyItem(ctx context.Context, name, item string, amount, priceID int, audit *TxAudit) error
+	// BuyItemWithPromo покупает предмет, как BuyItem, со скидкой по промокоду: в той же транзакции
+	// засчитывает применение кода (см. RedeemPromoCode) и списывает r.Price - r.Discount монет.
+	BuyItemWithPromo(ctx context.Context, name, item string, r *PromoRedemption, audit *TxAudit) error
+	// SendCoins переводит монеты и записывает перевод в историю, а ненулевую комиссию fee
+	// списывает с отправителя и записывает отдельным переводом на служебный счёт.
+	// Если перевод превысит limits, ничего не меняется и возвращается *LimitExceededError.
+	// Запись audit добавляется в журнал в той же транзакции.
+	SendCoins(ctx context.Context, username string, fromUserID int, toUserID int, scr *SendCoinRequest, limits TransferLimits, fee TransferFee, audit *TxAudit) error
+	// GetUserRole возвращает роль пользователя или ErrUserNotFound.
+	GetUserRole(ctx context.Context, id int) (string, error)
+	// SetUserRole назначает роль пользователю или возвращает ErrUserNotFound.
+	SetUserRole(ctx context.Context, username, role string) error
+	// EnsureSystemAccount создаёт служебный счёт username с ролью SystemRole, если его ещё нет,
+	// и возвращает его id. Если имя занято обычным пользователем, возвращает ErrNotSystemAccount.
+	EnsureSystemAccount(ctx context.Context, username string) (int, error)
+	// GetTransferTotals возвращает, сколько пользователь отправил и получил начиная с since.
+	GetTransferTotals(ctx context.Context, userID int, since time.Time) (sent, received int, err error)
+
+	// CreatePaymentRequest сохраняет новый запрос на перевод в статусе pending и заполняет pr.ID.
+	CreatePaymentRequest(ctx context.Context, pr *PaymentRequest) error
+	// GetPaymentRequest возвращает запрос по id или ErrPaymentRequestNotFound.
+	GetPaymentRequest(ctx context.Context, id int) (*PaymentRequest, error)
+	// ListPendingPaymentRequests возвращает ожидающие и не истёкшие к now запросы, где пользователь
+	// плательщик (incoming) или автор запроса, в порядке создания.
+	ListPendingPaymentRequests(ctx context.Context, userID int, incoming bool, now time.Time) ([]PaymentRequest, error)
+	// AcceptPaymentRequest в одной транзакции закрывает запрос, переводит монеты от плательщика автору
+	// и списывает с плательщика ненулевую комиссию fee, как SendCoins.
+	// Возвращает ErrPaymentRequestNotPending, если запрос уже закрыт или истёк к now,
+	// ErrInsufficientFunds, если у плательщика не хватает монет на сумму с комиссией,
+	// и *LimitExceededError при превышении limits. Запись audit добавляется вместе с переводом.
+	AcceptPaymentRequest(ctx context.Context, id int, now time.Time, limits TransferLimits, fee TransferFee, audit *TxAudit) error
+	// ResolvePaymentRequest переводит ожидающий запрос в status без перевода монет
+	// или возвращает ErrPaymentRequestNotPending.
+	ResolvePaymentRequest(ctx context.Context, id int, status string, now time.Time) error
+
+	// CreateScheduledTransfer сохраняет активный отложенный перевод и заполняет st.ID.
+	CreateScheduledTransfer(ctx context.Context, st *ScheduledTransfer) error
+	// GetScheduledTransfer возвращает отложенный перевод по id или ErrScheduledTransferNotFound.
+	GetScheduledTransfer(ctx context.Context, id int) (*ScheduledTransfer, error)
+	// ListScheduledTransfers возвращает все отложенные переводы отправителя в порядке создания.
+	ListScheduledTransfers(ctx context.Context, senderID int) ([]ScheduledTransfer, error)
+	// ListDueScheduledTransfers возвращает до limit активных переводов, время запуска которых наступило к now.
+	ListDueScheduledTransfers(ctx context.Context, now time.Time, limit int) ([]ScheduledTransfer, error)
+	// CancelScheduledTransfer отменяет активный перевод или возвращает ErrScheduledTransferNotActive.
+	CancelScheduledTransfer(ctx context.Context, id int) error
+	// RunScheduledTransfer выполняет очередной запуск st в одной транзакции: переносит запуск на next
+	// (nil — разовый перевод, он завершается), переводит монеты и списывает комиссию fee. Запуск захватывается сравнением
+	// st.Runs, поэтому при нескольких репликах его выполнит только одна, остальные получат
+	// ErrScheduledTransferClaimed. При нехватке монет с учётом комиссии или превышении limits запуск всё равно считается
+	// состоявшимся: ошибка сохраняется в last_error, разовый перевод помечается failed
+	// и возвращается ErrInsufficientFunds или *LimitExceededError. Запись audit добавляется, только если монеты переведены.
+	RunScheduledTransfer(ctx context.Context, st *ScheduledTransfer, next *time.Time, now time.Time, limits TransferLimits, fee TransferFee, audit *TxAudit) error
+
+	// GetCoinLots возвращает партии монет пользователя от старых к новым.
+	GetCoinLots(ctx context.Context, userID int) ([]CoinLot, error)
+	// ListExpiredCoins возвращает до limit пользователей, кроме служебных счетов, у которых есть партии,
+	// выданные не позже cutoff, вместе с суммой таких партий.
+	ListExpiredCoins(ctx context.Context, cutoff time.Time, limit int) ([]ExpiredCoins, error)
+	// ExpireCoins в одной транзакции сжигает партии пользователя, выданные не позже cutoff (см. BurnExpiredLots),
+	// и возвращает число сгоревших монет. Если партии уже сжёг другой исполнитель, возвращает 0
+	// и не добавляет запись audit.
+	ExpireCoins(ctx context.Context, userID, sinkID int, cutoff, now time.Time, audit *TxAudit) (int, error)
+
+	// CreatePromoCode сохраняет промокод и заполняет pc.ID; если код занят, возвращает ErrPromoCodeExists.
+	CreatePromoCode(ctx context.Context, pc *PromoCode) error
+	// GetPromoCode возвращает промокод по коду или ErrPromoCodeNotFound.
+	GetPromoCode(ctx context.Context, code string) (*PromoCode, error)
+	// ListPromoCodes возвращает все промокоды в порядке создания.
+	ListPromoCodes(ctx context.Context) ([]PromoCode, error)
+	// DisablePromoCode отключает промокод с момента now; уже отключённый код не меняется.
+	DisablePromoCode(ctx context.Context, code string, now time.Time) error
+	// CountPromoRedemptions возвращает, сколько покупок пользователь сделал по промокоду.
+	CountPromoRedemptions(ctx context.Context, promoCodeID, userID int) (int, error)
+
+	// CreateItemPrice сохраняет запланированную цену и заполняет p.ID.
+	CreateItemPrice(ctx context.Context, p *ItemPrice) error
+	// GetItemPrice возвращает цену по id или ErrItemPriceNotFound.
+	GetItemPrice(ctx context.Context, id int) (*ItemPrice, error)
+	// ListItemPrices возвращает цены, не закончившиеся к now (действующие и будущие), в порядке начала действия.
+	ListItemPrices(ctx context.Context, now time.Time) ([]ItemPrice, error)
+	// DeleteItemPrice удаляет цену или возвращает ErrItemPriceNotFound.
+	DeleteItemPrice(ctx context.Context, id int) error
+	// ListOrders возвращает покупки пользователя в порядке совершения.
+	ListOrders(ctx context.Context, userID int) ([]Order, error)
+
+	// GetLeaderboard возвращает первые limit строк таблицы лидеров board (см. LeaderboardQuery) без мест;
+	// для неизвестной таблицы возвращает ErrUnknownLeaderboard.
+	GetLeaderboard(ctx context.Context, board string, since *time.Time, limit int) ([]LeaderboardEntry, error)
+	// SetLeaderboardOptOut исключает пользователя из таблиц лидеров (optOut) или возвращает в них.
+	SetLeaderboardOptOut(ctx context.Context, userID int, optOut bool) error
+
+	// GetAchievementProgress возвращает показатели пользователя для правил достижений.
+	GetAchievementProgress(ctx context.Context, userID int) (*AchievementProgress, error)
+	// AwardAchievement выдаёт достижение a.Code пользователю. Если оно уже выдано, ничего
+	// не меняется и возвращается false, в том числе при одновременной выдаче.
+	AwardAchievement(ctx context.Context, userID int, a *Achievement) (bool, error)
+	// ListAchievements возвращает достижения пользователя в порядке выдачи.
+	ListAchievements(ctx context.Context, userID int) ([]Achievement, error)
+
+	// CreateGroup создаёт кошелёк группы с администратором ownerID и заполняет g.ID (см. CreateGroupTx);
+	// если имя занято, возвращает ErrGroupExists.
+	CreateGroup(ctx context.Context, g *Group, ownerID int) error
+	// GetGroup возвращает группу по имени или ErrGroupNotFound.
+	GetGroup(ctx context.Context, name string) (*Group, error)
+	// ListUserGroups возвращает группы пользователя с его ролью в порядке названия.
+	ListUserGroups(ctx context.Context, userID int) ([]Group, error)
+	// ListGroupMembers возвращает участников группы в порядке вступления.
+	ListGroupMembers(ctx context.Context, groupID int) ([]GroupMember, error)
+	// GetGroupMemberRole возвращает роль пользователя в группе или ErrNotGroupMember.
+	GetGroupMemberRole(ctx context.Context, groupID, userID int) (string, error)
+	// SetGroupMember добавляет пользователя в группу с ролью role или меняет роль участника.
+	SetGroupMember(ctx context.Context, groupID, userID int, role string, at time.Time) error
+	// RemoveGroupMember исключает пользователя из группы или возвращает ErrNotGroupMember.
+	RemoveGroupMember(ctx context.Context, groupID, userID int) error
+	// CreateGroupPurchase сохраняет предложение покупки proposerID в статусе pending и заполняет p.ID.
+	CreateGroupPurchase(ctx context.Context, p *GroupPurchase, proposerID int) error
+	// GetGroupPurchase возвращает покупку группы по id или ErrGroupPurchaseNotFound.
+	GetGroupPurchase(ctx context.Context, id int) (*GroupPurchase, error)
+	// ListGroupPurchases возвращает покупки группы в порядке создания.
+	ListGroupPurchases(ctx context.Context, groupID int) ([]GroupPurchase, error)
+	// ApproveGroupPurchase в одной транзакции записывает одобрение и, если оно последнее из нужных,
+	// покупает предмет за price монет кошелька (см. ApproveGroupPurchaseTx). Возвращает покупку после
+	// одобрения, ErrGroupPurchaseNotPending, ErrGroupPurchaseAlreadyApproved или ErrInsufficientFunds.
+	// Запись audit добавляется, только если покупка совершена.
+	ApproveGroupPurchase(ctx context.Context, id, userID, price, priceID int, at time.Time, audit *TxAudit) (*GroupPurchase, error)
+	// CancelGroupPurchase отменяет ожидающую покупку или возвращает ErrGroupPurchaseNotPending.
+	CancelGroupPurchase(ctx context.Context, id int, at time.Time) error
+
+	// CreatePendingTransfer сохраняет перевод pt, ожидающий одобрения, и в той же транзакции удерживает
+	// его сумму и комиссию (см. HoldTransferTx); заполняет pt.ID и pt.Status. Суточные лимиты limits
+	// проверяются так же, как в SendCoins. Если монет не хватает, возвращает ErrInsufficientFunds.
+	// Целью записи audit становится сохранённый перевод (см. TxAudit.Created).
+	CreatePendingTransfer(ctx context.Context, pt *PendingTransfer, limits TransferLimits, audit *TxAudit) error
+	// GetPendingTransfer возвращает перевод по id или ErrPendingTransferNotFound.
+	GetPendingTransfer(ctx context.Context, id int) (*PendingTransfer, error)
+	// ListPendingTransfers возвращает не истёкшие к now ожидающие переводы отправителя senderID
+	// (0 — всех отправителей) в порядке создания.
+	ListPendingTransfers(ctx context.Context, senderID int, now time.Time) ([]PendingTransfer, error)
+	// ListExpiredPendingTransfers возвращает до limit ожидающих переводов, истёкших к now.
+	ListExpiredPendingTransfers(ctx context.Context, now time.Time, limit int) ([]PendingTransfer, error)
+	// ResolvePendingTransfer в одной транзакции закрывает перевод id со статусом status от имени
+	// resolverID (см. ClaimPendingTransferTx) и распределяет удержание (см. SettlePendingTransferTx);
+	// одобрение проверяет суточные лимиты limits. Возвращает закрытый перевод,
+	// ErrPendingTransferNotFound или ErrPendingTransferNotPending. Если у audit не заданы счета,
+	// в запись попадают балансы отправителя и получателя.
+	ResolvePendingTransfer(ctx context.Context, id int, status string, resolverID int, at time.Time, limits TransferLimits, audit *TxAudit) (*PendingTransfer, error)
+
+	// ReverseTransaction в одной транзакции отменяет перевод rv.TransactionID (см. ReverseTransactionTx)
+	// и сохраняет запись аудита; заполняет rv. Возвращает ErrTransactionNotFound, ErrTransactionNotReversible,
+	// ErrTransactionAlreadyReversed или, если по политике возвращать нечего, ErrInsufficientFunds.
+	// Если у audit не заданы счета, в запись попадают балансы участников отменяемого перевода.
+	ReverseTransaction(ctx context.Context, rv *TransferReversal, audit *TxAudit) error
+	// ListUserTransactions возвращает до limit последних записей пользователя userID (см. LoadUserTransactions).
+	ListUserTransactions(ctx context.Context, userID, limit int) ([]Transaction, error)
+
+	// AppendAudit добавляет запись в конец журнала аудита и заполняет её ID, PrevHash и Hash.
+	// Записи добавляются по одной, поэтому цепочка хэшей не ветвится. Операции с монетами
+	// добавляют свою запись сами, в своей транзакции (см. TxAudit).
+	AppendAudit(ctx context.Context, e *AuditEntry) error
+	// ListAudit возвращает записи журнала по фильтру в порядке добавления (см. LoadAuditEntries).
+	ListAudit(ctx context.Context, filter AuditFilter) ([]AuditEntry, error)
+	// GetAuditHead возвращает последнюю запись журнала.
+	GetAuditHead(ctx context.Context) (*AuditHead, error)
 }
 
 type InfoResponse struct {
 	Coins       int         `json:"coins"`
 	Inventory   []Inventory `json:"inventory"`
 	CoinHistory CoinHistory `json:"coinHistory"`
+	Limits      *LimitUsage `json:"limits,omitempty"`
+	// Expiring — ближайшие сгорания монет; заполняется, только если сгорание включено.
+	Expiring []CoinExpiration `json:"expiring,omitempty"`
+	// Achievements — выданные достижения; заполняется, только если достижения включены.
+	Achievements []Achievement `json:"achievements,omitempty"`
+}
+
+// LimitUsage — действующие для пользователя лимиты переводов (0 — без ограничения)
+// и их использование за текущие сутки (UTC).
+type LimitUsage struct {
+	PerTransfer   int `json:"perTransfer"`
+	DailySent     int `json:"dailySent"`
+	SentToday     int `json:"sentToday"`
+	DailyReceived int `json:"dailyReceived"`
+	ReceivedToday int `json:"receivedToday"`
 }
 
 type Inventory struct {
@@ -30,16 +219,27 @@ type CoinHistory struct {
 type TransactionIn struct {
 	FromUser string `json:"fromUser,omitempty"`
 	Amount   int    `json:"amount,omitempty"`
+	Memo     string `json:"memo,omitempty"`
+	Category string `json:"category,omitempty"`
 }
 
 type TransactionOut struct {
-	ToUser string `json:"toUser,omitempty"`
-	Amount int    `json:"amount,omitempty"`
+	ToUser   string `json:"toUser,omitempty"`
+	Amount   int    `json:"amount,omitempty"`
+	Memo     string `json:"memo,omitempty"`
+	Category string `json:"category,omitempty"`
 }
 
 type SendCoinRequest struct {
-	ToUser string `json:"toUser"`
-	Amount int    `json:"amount"`
+	ToUser   string `json:"toUser"`
+	Amount   int    `json:"amount"`
+	Memo     string `json:"memo,omitempty"`
+	Category string `json:"category,omitempty"`
+}
+
+// HistoryFilter ограничивает выборку истории переводов; пустые поля не фильтруют.
+type HistoryFilter struct {
+	Category string
 }
 
 type AuthRequest struct {
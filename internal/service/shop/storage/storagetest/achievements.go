@@ -0,0 +1,104 @@
+package storagetest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var achievementTests = []struct {
+	name string
+	fn   func(t *testing.T, s storage.IStorage)
+}{
+	{"GetAchievementProgress_Counts", testGetAchievementProgress},
+	{"AwardAchievement_Idempotent", testAwardAchievementIdempotent},
+}
+
+func testGetAchievementProgress(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	systemID, err := s.EnsureSystemAccount(ctx, "system")
+	require.NoError(t, err)
+
+	progress, err := s.GetAchievementProgress(ctx, aliceID)
+	require.NoError(t, err)
+	assert.Equal(t, &storage.AchievementProgress{Items: map[string]int{}, Awarded: map[string]bool{}}, progress)
+
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 100},
+		storage.TransferLimits{}, storage.TransferFee{AccountID: systemID, Amount: 5}, nil))
+	require.NoError(t, s.SendCoins(ctx, "bob", bobID, aliceID, &storage.SendCoinRequest{ToUser: "alice", Amount: 30},
+		storage.TransferLimits{}, storage.TransferFee{}, nil))
+	require.NoError(t, s.BuyItem(ctx, "alice", "cup", 20, 0, nil))
+	require.NoError(t, s.BuyItem(ctx, "alice", "cup", 20, 0, nil))
+	require.NoError(t, s.BuyItem(ctx, "alice", "pen", 10, 0, nil))
+	awarded, err := s.AwardAchievement(ctx, aliceID, &storage.Achievement{Code: "first_purchase", Title: "Первая покупка", AwardedAt: time.Now().UTC()})
+	require.NoError(t, err)
+	require.True(t, awarded)
+
+	progress, err = s.GetAchievementProgress(ctx, aliceID)
+	require.NoError(t, err)
+	assert.Equal(t, &storage.AchievementProgress{
+		Sent:     100,
+		Received: 30,
+		Items:    map[string]int{"cup": 2, "pen": 1},
+		Awarded:  map[string]bool{"first_purchase": true},
+	}, progress, "the fee is not counted as sent")
+	assert.Equal(t, 3, progress.TotalItems())
+}
+
+func testAwardAchievementIdempotent(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	badge := func(code string, at time.Time) *storage.Achievement {
+		return &storage.Achievement{Code: code, Title: "Title " + code, Description: "Description " + code, AwardedAt: at}
+	}
+
+	const workers = 5
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		won int
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			awarded, err := s.AwardAchievement(ctx, aliceID, badge("sender_100", at))
+			assert.NoError(t, err)
+			if awarded {
+				mu.Lock()
+				won++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, won, "only one concurrent award succeeds")
+
+	awarded, err := s.AwardAchievement(ctx, aliceID, badge("sender_100", at.Add(time.Hour)))
+	require.NoError(t, err)
+	assert.False(t, awarded)
+	awarded, err = s.AwardAchievement(ctx, aliceID, badge("first_purchase", at.Add(time.Minute)))
+	require.NoError(t, err)
+	assert.True(t, awarded)
+	awarded, err = s.AwardAchievement(ctx, bobID, badge("sender_100", at))
+	require.NoError(t, err)
+	assert.True(t, awarded, "the same achievement is awarded to another user")
+
+	list, err := s.ListAchievements(ctx, aliceID)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.Achievement{*badge("sender_100", at), *badge("first_purchase", at.Add(time.Minute))}, list)
+
+	list, err = s.ListAchievements(ctx, addUser(t, s, "carol"))
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
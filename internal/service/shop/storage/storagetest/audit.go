@@ -0,0 +1,177 @@
+package storagetest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var auditTests = []struct {
+	name string
+	fn   func(t *testing.T, s storage.IStorage)
+}{
+	{"AppendAudit_Chains", testAppendAuditChains},
+	{"ListAudit_Filter", testListAuditFilter},
+	{"AppendAudit_Concurrent", testAppendAuditConcurrent},
+	{"SendCoins_AuditInTransaction", testSendCoinsAuditInTransaction},
+	{"PendingTransfer_AuditInTransaction", testPendingTransferAuditInTransaction},
+}
+
+var auditNow = time.Date(2026, 10, 4, 12, 0, 0, 0, time.UTC)
+
+func appendAudit(tb testing.TB, s storage.IStorage, actor, action, target string, at time.Time) *storage.AuditEntry {
+	e := &storage.AuditEntry{Actor: actor, Action: action, Target: target, RequestID: "req-1", IP: "10.0.0.1", CreatedAt: at}
+	require.NoError(tb, s.AppendAudit(context.Background(), e))
+	require.NotZero(tb, e.ID)
+	return e
+}
+
+func txAudit(action, target string, accounts ...int) *storage.TxAudit {
+	return &storage.TxAudit{
+		Entry:    &storage.AuditEntry{Actor: "alice", Action: action, Target: target, RequestID: "req-1", CreatedAt: auditNow},
+		Accounts: accounts,
+	}
+}
+
+// auditLog возвращает весь журнал в порядке добавления.
+func auditLog(tb testing.TB, s storage.IStorage) []storage.AuditEntry {
+	entries, err := s.ListAudit(context.Background(), storage.AuditFilter{Limit: 1000})
+	require.NoError(tb, err)
+	return entries
+}
+
+func testAppendAuditChains(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	head, err := s.GetAuditHead(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &storage.AuditHead{}, head, "the log starts empty")
+
+	first := &storage.AuditEntry{Actor: "alice", Action: "transfer.send", Target: "user:bob",
+		Before: json.RawMessage(`{"alice":1000,"bob":1000}`), After: json.RawMessage(`{"alice":700,"bob":1300}`),
+		RequestID: "req-1", IP: "10.0.0.1", CreatedAt: auditNow.Add(500 * time.Millisecond)}
+	require.NoError(t, s.AppendAudit(ctx, first))
+	second := appendAudit(t, s, "admin", "promo.create", "promo:SALE", auditNow)
+
+	assert.Empty(t, first.PrevHash)
+	assert.Equal(t, auditNow, first.CreatedAt, "the time is stored to the second")
+	assert.Equal(t, first.Hash, second.PrevHash)
+	assert.Equal(t, storage.AuditHash(second), second.Hash)
+
+	assert.Equal(t, []storage.AuditEntry{*first, *second}, auditLog(t, s))
+	head, err = s.GetAuditHead(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &storage.AuditHead{LastID: second.ID, Hash: second.Hash}, head)
+}
+
+func testListAuditFilter(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	e1 := appendAudit(t, s, "alice", "transfer.send", "user:bob", auditNow)
+	e2 := appendAudit(t, s, "bob", "purchase", "item:cup", auditNow.Add(time.Hour))
+	e3 := appendAudit(t, s, "alice", "purchase", "item:pen", auditNow.Add(2*time.Hour))
+
+	ids := func(filter storage.AuditFilter) []int {
+		if filter.Limit == 0 {
+			filter.Limit = 10
+		}
+		entries, err := s.ListAudit(ctx, filter)
+		require.NoError(t, err)
+		var res []int
+		for _, e := range entries {
+			res = append(res, e.ID)
+		}
+		return res
+	}
+	from, to := auditNow.Add(time.Hour), auditNow.Add(2*time.Hour)
+
+	assert.Equal(t, []int{e1.ID, e3.ID}, ids(storage.AuditFilter{Actor: "alice"}))
+	assert.Equal(t, []int{e2.ID, e3.ID}, ids(storage.AuditFilter{Action: "purchase"}))
+	assert.Equal(t, []int{e2.ID}, ids(storage.AuditFilter{Target: "item:cup"}))
+	assert.Equal(t, []int{e2.ID}, ids(storage.AuditFilter{From: &from, To: &to}), "the range excludes its end")
+	assert.Equal(t, []int{e2.ID}, ids(storage.AuditFilter{AfterID: e1.ID, Limit: 1}))
+	assert.Empty(t, ids(storage.AuditFilter{Actor: "carol"}))
+}
+
+func testAppendAuditConcurrent(t *testing.T, s storage.IStorage) {
+	const workers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- s.AppendAudit(context.Background(), &storage.AuditEntry{Actor: "alice", Action: "auth.login", Target: "user:alice", CreatedAt: auditNow})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	entries := auditLog(t, s)
+	require.Len(t, entries, workers)
+	prev := ""
+	for _, e := range entries {
+		assert.Equal(t, prev, e.PrevHash, "the chain does not branch")
+		assert.Equal(t, storage.AuditHash(&e), e.Hash)
+		prev = e.Hash
+	}
+	head, err := s.GetAuditHead(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, prev, head.Hash)
+}
+
+func testSendCoinsAuditInTransaction(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	scr := &storage.SendCoinRequest{ToUser: "bob", Amount: 300}
+
+	audit := txAudit("transfer.send", "user:bob", bobID, aliceID)
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, bobID, scr, storage.TransferLimits{}, storage.TransferFee{}, audit))
+	assert.JSONEq(t, `{"alice":1000,"bob":1000}`, string(audit.Entry.Before))
+	assert.JSONEq(t, `{"alice":700,"bob":1300}`, string(audit.Entry.After))
+	assert.Equal(t, storage.AuditHash(audit.Entry), audit.Entry.Hash)
+	assert.Equal(t, []storage.AuditEntry{*audit.Entry}, auditLog(t, s))
+
+	scr.Amount = 5000
+	err := s.SendCoins(ctx, "alice", aliceID, bobID, scr, storage.TransferLimits{}, storage.TransferFee{},
+		txAudit("transfer.send", "user:bob", aliceID, bobID))
+	assert.ErrorIs(t, err, storage.ErrInsufficientFunds)
+	assert.Len(t, auditLog(t, s), 1, "a rolled back transfer leaves no entry")
+
+	head, err := s.GetAuditHead(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &storage.AuditHead{LastID: audit.Entry.ID, Hash: audit.Entry.Hash}, head)
+}
+
+func testPendingTransferAuditInTransaction(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	pt := &storage.PendingTransfer{SenderID: aliceID, RecipientID: bobID, Amount: 300,
+		CreatedAt: pendingTransferNow, ExpiresAt: pendingTransferNow.Add(time.Hour)}
+
+	hold := txAudit("transfer.hold", "", aliceID)
+	hold.TargetKind = "pending_transfer"
+	require.NoError(t, s.CreatePendingTransfer(ctx, pt, storage.TransferLimits{}, hold))
+	assert.Equal(t, fmt.Sprintf("pending_transfer:%d", pt.ID), hold.Entry.Target)
+	assert.JSONEq(t, `{"alice":700}`, string(hold.Entry.After))
+
+	// Без заданных счетов в запись попадают участники перевода.
+	reject := txAudit("transfer.reject", fmt.Sprintf("pending_transfer:%d", pt.ID))
+	_, err := s.ResolvePendingTransfer(ctx, pt.ID, storage.PendingTransferRejected, 0, pendingTransferNow,
+		storage.TransferLimits{}, reject)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"alice":700,"bob":1000}`, string(reject.Entry.Before))
+	assert.JSONEq(t, `{"alice":1000,"bob":1000}`, string(reject.Entry.After))
+	assert.Equal(t, []storage.AuditEntry{*hold.Entry, *reject.Entry}, auditLog(t, s))
+}
@@ -0,0 +1,162 @@
+package storagetest
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var feeTests = []struct {
+	name string
+	fn   func(t *testing.T, s storage.IStorage)
+}{
+	{"EnsureSystemAccount_CreatesOnce", testEnsureSystemAccount},
+	{"EnsureSystemAccount_NameTaken", testEnsureSystemAccountNameTaken},
+	{"SendCoins_ChargesFee", testSendCoinsChargesFee},
+	{"PaymentRequest_AcceptChargesFee", testPaymentRequestAcceptChargesFee},
+	{"PaymentRequest_AcceptFeeInsufficientFunds", testPaymentRequestAcceptFeeInsufficientFunds},
+	{"ScheduledTransfer_RunChargesFee", testScheduledTransferRunChargesFee},
+	{"ScheduledTransfer_RunFeeInsufficientFunds", testScheduledTransferRunFeeInsufficientFunds},
+}
+
+func testEnsureSystemAccount(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+
+	id, err := s.EnsureSystemAccount(ctx, "system")
+	require.NoError(t, err)
+	again, err := s.EnsureSystemAccount(ctx, "system")
+	require.NoError(t, err)
+	assert.Equal(t, id, again)
+
+	role, err := s.GetUserRole(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, storage.SystemRole, role)
+	assert.Equal(t, 0, balance(t, s, "system"))
+
+	hash, err := s.CheckAuth(ctx, "system")
+	require.NoError(t, err)
+	assert.Empty(t, hash, "the system account has no password")
+}
+
+func testEnsureSystemAccountNameTaken(t *testing.T, s storage.IStorage) {
+	addUser(t, s, "system")
+
+	_, err := s.EnsureSystemAccount(context.Background(), "system")
+	assert.ErrorIs(t, err, storage.ErrNotSystemAccount)
+}
+
+func testSendCoinsChargesFee(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	systemID, err := s.EnsureSystemAccount(ctx, "system")
+	require.NoError(t, err)
+
+	fee := storage.TransferFee{AccountID: systemID, Amount: 3}
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, bobID,
+		&storage.SendCoinRequest{ToUser: "bob", Amount: 100, Memo: "за обед", Category: "lunch"}, storage.TransferLimits{}, fee, nil))
+
+	assert.Equal(t, 897, balance(t, s, "alice"))
+	assert.Equal(t, 1100, balance(t, s, "bob"))
+	assert.Equal(t, 3, balance(t, s, "system"))
+
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetSendHistory(ctx, &ir, aliceID))
+	assert.Equal(t, []storage.TransactionOut{
+		{ToUser: strconv.Itoa(bobID), Amount: 100, Memo: "за обед", Category: "lunch"},
+		{ToUser: strconv.Itoa(systemID), Amount: 3, Category: storage.FeeCategory},
+	}, ir.CoinHistory.Sent)
+
+	ir = storage.InfoResponse{}
+	require.NoError(t, s.GetCoinHistory(ctx, &ir, aliceID, storage.HistoryFilter{Category: storage.FeeCategory}))
+	assert.Equal(t, []storage.TransactionOut{{ToUser: strconv.Itoa(systemID), Amount: 3, Category: storage.FeeCategory}}, ir.CoinHistory.Sent)
+
+	sent, _, err := s.GetTransferTotals(ctx, aliceID, limitsSince())
+	require.NoError(t, err)
+	assert.Equal(t, 100, sent, "fees do not count towards daily limits")
+}
+
+func testPaymentRequestAcceptChargesFee(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	systemID, err := s.EnsureSystemAccount(ctx, "system")
+	require.NoError(t, err)
+	pr := createPaymentRequest(t, s, aliceID, bobID, 100, time.Hour)
+
+	fee := storage.TransferFee{AccountID: systemID, Amount: 3}
+	require.NoError(t, s.AcceptPaymentRequest(ctx, pr.ID, paymentRequestNow, storage.TransferLimits{}, fee, nil))
+
+	assert.Equal(t, 1100, balance(t, s, "alice"))
+	assert.Equal(t, 897, balance(t, s, "bob"))
+	assert.Equal(t, 3, balance(t, s, "system"))
+
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetSendHistory(ctx, &ir, bobID))
+	assert.Equal(t, []storage.TransactionOut{
+		{ToUser: strconv.Itoa(aliceID), Amount: 100, Memo: "за пиццу"},
+		{ToUser: strconv.Itoa(systemID), Amount: 3, Category: storage.FeeCategory},
+	}, ir.CoinHistory.Sent)
+}
+
+func testPaymentRequestAcceptFeeInsufficientFunds(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	systemID, err := s.EnsureSystemAccount(ctx, "system")
+	require.NoError(t, err)
+	pr := createPaymentRequest(t, s, aliceID, bobID, 999, time.Hour)
+
+	// Суммы хватает, а суммы с комиссией — нет.
+	fee := storage.TransferFee{AccountID: systemID, Amount: 3}
+	assert.ErrorIs(t, s.AcceptPaymentRequest(ctx, pr.ID, paymentRequestNow, storage.TransferLimits{}, fee, nil), storage.ErrInsufficientFunds)
+
+	assert.Equal(t, 1000, balance(t, s, "alice"))
+	assert.Equal(t, 1000, balance(t, s, "bob"))
+	assert.Equal(t, 0, balance(t, s, "system"))
+}
+
+func testScheduledTransferRunChargesFee(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	systemID, err := s.EnsureSystemAccount(ctx, "system")
+	require.NoError(t, err)
+	st := createScheduledTransfer(t, s, aliceID, bobID, 100, "", paymentRequestNow)
+
+	fee := storage.TransferFee{AccountID: systemID, Amount: 3}
+	require.NoError(t, s.RunScheduledTransfer(ctx, st, nil, paymentRequestNow, storage.TransferLimits{}, fee, nil))
+
+	assert.Equal(t, 897, balance(t, s, "alice"))
+	assert.Equal(t, 1100, balance(t, s, "bob"))
+	assert.Equal(t, 3, balance(t, s, "system"))
+
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetSendHistory(ctx, &ir, aliceID))
+	assert.Equal(t, []storage.TransactionOut{
+		{ToUser: strconv.Itoa(bobID), Amount: 100, Memo: "бонус", Category: "thanks"},
+		{ToUser: strconv.Itoa(systemID), Amount: 3, Category: storage.FeeCategory},
+	}, ir.CoinHistory.Sent)
+}
+
+func testScheduledTransferRunFeeInsufficientFunds(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	systemID, err := s.EnsureSystemAccount(ctx, "system")
+	require.NoError(t, err)
+	st := createScheduledTransfer(t, s, aliceID, bobID, 999, "", paymentRequestNow)
+
+	fee := storage.TransferFee{AccountID: systemID, Amount: 3}
+	assert.ErrorIs(t, s.RunScheduledTransfer(ctx, st, nil, paymentRequestNow, storage.TransferLimits{}, fee, nil), storage.ErrInsufficientFunds)
+
+	assert.Equal(t, storage.ScheduledTransferFailed, getScheduledTransfer(t, s, st.ID).Status)
+	assert.Equal(t, 1000, balance(t, s, "alice"))
+	assert.Equal(t, 0, balance(t, s, "system"))
+}
@@ -0,0 +1,218 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var groupTests = []struct {
+	name string
+	fn   func(t *testing.T, s storage.IStorage)
+}{
+	{"CreateGroup_GetAndList", testCreateGroup},
+	{"SetGroupMember_RemoveGroupMember", testGroupMembers},
+	{"ApproveGroupPurchase_BuysOnLastApproval", testApproveGroupPurchase},
+	{"ApproveGroupPurchase_InsufficientFunds", testApproveGroupPurchaseInsufficientFunds},
+	{"ApproveGroupPurchase_Concurrent", testApproveGroupPurchaseConcurrent},
+}
+
+var groupCreatedAt = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func createGroup(tb testing.TB, s storage.IStorage, name string, approvals, ownerID int) *storage.Group {
+	g := &storage.Group{Name: name, ApprovalsRequired: approvals, CreatedAt: groupCreatedAt}
+	require.NoError(tb, s.CreateGroup(context.Background(), g, ownerID))
+	require.NotZero(tb, g.ID)
+	return g
+}
+
+func createGroupPurchase(tb testing.TB, s storage.IStorage, g *storage.Group, item string, proposerID int) *storage.GroupPurchase {
+	p := &storage.GroupPurchase{GroupID: g.ID, Item: item, CreatedAt: groupCreatedAt.Add(time.Hour)}
+	require.NoError(tb, s.CreateGroupPurchase(context.Background(), p, proposerID))
+	require.NotZero(tb, p.ID)
+	return p
+}
+
+func testCreateGroup(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	addUser(t, s, "bob")
+	g := createGroup(t, s, "team", 2, aliceID)
+
+	got, err := s.GetGroup(ctx, "team")
+	require.NoError(t, err)
+	assert.Equal(t, &storage.Group{ID: g.ID, Name: "team", ApprovalsRequired: 2, CreatedAt: groupCreatedAt}, got)
+	_, err = s.GetGroup(ctx, "alice")
+	assert.ErrorIs(t, err, storage.ErrGroupNotFound, "a user is not a group")
+
+	assert.Equal(t, 0, balance(t, s, "team"), "a group wallet starts empty")
+	role, err := s.GetUserRole(ctx, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, storage.GroupRole, role)
+
+	assert.ErrorIs(t, s.CreateGroup(ctx, &storage.Group{Name: "team", ApprovalsRequired: 1, CreatedAt: groupCreatedAt}, aliceID), storage.ErrGroupExists)
+	assert.ErrorIs(t, s.CreateGroup(ctx, &storage.Group{Name: "bob", ApprovalsRequired: 1, CreatedAt: groupCreatedAt}, aliceID), storage.ErrGroupExists)
+
+	groups, err := s.ListUserGroups(ctx, aliceID)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.Group{{ID: g.ID, Name: "team", ApprovalsRequired: 2, Role: storage.GroupRoleAdmin, CreatedAt: groupCreatedAt}}, groups)
+
+	members, err := s.ListGroupMembers(ctx, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.GroupMember{{Username: "alice", Role: storage.GroupRoleAdmin, JoinedAt: groupCreatedAt}}, members)
+}
+
+func testGroupMembers(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	g := createGroup(t, s, "team", 1, aliceID)
+
+	_, err := s.GetGroupMemberRole(ctx, g.ID, bobID)
+	assert.ErrorIs(t, err, storage.ErrNotGroupMember)
+
+	require.NoError(t, s.SetGroupMember(ctx, g.ID, bobID, storage.GroupRoleMember, groupCreatedAt.Add(time.Hour)))
+	role, err := s.GetGroupMemberRole(ctx, g.ID, bobID)
+	require.NoError(t, err)
+	assert.Equal(t, storage.GroupRoleMember, role)
+
+	require.NoError(t, s.SetGroupMember(ctx, g.ID, bobID, storage.GroupRoleAdmin, groupCreatedAt.Add(2*time.Hour)))
+	members, err := s.ListGroupMembers(ctx, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.GroupMember{
+		{Username: "alice", Role: storage.GroupRoleAdmin, JoinedAt: groupCreatedAt},
+		{Username: "bob", Role: storage.GroupRoleAdmin, JoinedAt: groupCreatedAt.Add(time.Hour)},
+	}, members, "changing the role keeps the join time")
+
+	require.NoError(t, s.RemoveGroupMember(ctx, g.ID, bobID))
+	assert.ErrorIs(t, s.RemoveGroupMember(ctx, g.ID, bobID), storage.ErrNotGroupMember)
+	groups, err := s.ListUserGroups(ctx, bobID)
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+}
+
+func testApproveGroupPurchase(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	g := createGroup(t, s, "team", 2, aliceID)
+	require.NoError(t, s.SetGroupMember(ctx, g.ID, bobID, storage.GroupRoleMember, groupCreatedAt))
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, g.ID, &storage.SendCoinRequest{ToUser: "team", Amount: 100},
+		storage.TransferLimits{}, storage.TransferFee{}, nil))
+	p := createGroupPurchase(t, s, g, "cup", bobID)
+
+	at := groupCreatedAt.Add(2 * time.Hour)
+	got, err := s.ApproveGroupPurchase(ctx, p.ID, bobID, 30, 0, at, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &storage.GroupPurchase{
+		ID: p.ID, GroupID: g.ID, Group: "team", Item: "cup", ProposedBy: "bob", Status: storage.GroupPurchasePending,
+		ApprovalsRequired: 2, Approvals: []string{"bob"}, CreatedAt: p.CreatedAt,
+	}, got)
+	_, err = s.ApproveGroupPurchase(ctx, p.ID, bobID, 30, 0, at, nil)
+	assert.ErrorIs(t, err, storage.ErrGroupPurchaseAlreadyApproved)
+	assert.Equal(t, 100, balance(t, s, "team"), "nothing is bought before the last approval")
+
+	got, err = s.ApproveGroupPurchase(ctx, p.ID, aliceID, 30, 0, at.Add(time.Minute), nil)
+	require.NoError(t, err)
+	assert.Equal(t, storage.GroupPurchaseCompleted, got.Status)
+	assert.Equal(t, []string{"bob", "alice"}, got.Approvals)
+	assert.Equal(t, 30, got.Price)
+	require.NotNil(t, got.ResolvedAt)
+	assert.Equal(t, at.Add(time.Minute), *got.ResolvedAt)
+
+	assert.Equal(t, 70, balance(t, s, "team"))
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetInventory(ctx, &ir, g.ID))
+	assert.Equal(t, []storage.Inventory{{Type: "cup", Quantity: 1}}, ir.Inventory)
+	orders, err := s.ListOrders(ctx, g.ID)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, 30, orders[0].Paid)
+	lots := coinLots(t, s, g.ID)
+	require.Len(t, lots, 1)
+	assert.Equal(t, 70, lots[0].Amount, "the purchase spends the group's lots")
+
+	_, err = s.ApproveGroupPurchase(ctx, p.ID, aliceID, 30, 0, at, nil)
+	assert.ErrorIs(t, err, storage.ErrGroupPurchaseNotPending)
+	_, err = s.ApproveGroupPurchase(ctx, p.ID+100, aliceID, 30, 0, at, nil)
+	assert.ErrorIs(t, err, storage.ErrGroupPurchaseNotFound)
+	_, err = s.GetGroupPurchase(ctx, p.ID+100)
+	assert.ErrorIs(t, err, storage.ErrGroupPurchaseNotFound)
+
+	list, err := s.ListGroupPurchases(ctx, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.GroupPurchase{*got}, list)
+}
+
+func testApproveGroupPurchaseInsufficientFunds(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	g := createGroup(t, s, "team", 1, aliceID)
+	p := createGroupPurchase(t, s, g, "cup", aliceID)
+
+	_, err := s.ApproveGroupPurchase(ctx, p.ID, aliceID, 30, 0, groupCreatedAt.Add(2*time.Hour), nil)
+	assert.ErrorIs(t, err, storage.ErrInsufficientFunds)
+	got, err := s.GetGroupPurchase(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, storage.GroupPurchasePending, got.Status)
+	assert.Empty(t, got.Approvals, "the failed approval is rolled back")
+
+	require.NoError(t, s.CancelGroupPurchase(ctx, p.ID, groupCreatedAt.Add(3*time.Hour)))
+	assert.ErrorIs(t, s.CancelGroupPurchase(ctx, p.ID, groupCreatedAt.Add(3*time.Hour)), storage.ErrGroupPurchaseNotPending)
+	got, err = s.GetGroupPurchase(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, storage.GroupPurchaseCancelled, got.Status)
+}
+
+func testApproveGroupPurchaseConcurrent(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	ownerID := addUser(t, s, "owner")
+	g := createGroup(t, s, "team", 2, ownerID)
+	require.NoError(t, s.SendCoins(ctx, "owner", ownerID, g.ID, &storage.SendCoinRequest{ToUser: "team", Amount: 100},
+		storage.TransferLimits{}, storage.TransferFee{}, nil))
+
+	const workers = 5
+	ids := []int{ownerID}
+	for _, name := range []string{"u1", "u2", "u3", "u4"} {
+		id := addUser(t, s, name)
+		require.NoError(t, s.SetGroupMember(ctx, g.ID, id, storage.GroupRoleMember, groupCreatedAt))
+		ids = append(ids, id)
+	}
+	p := createGroupPurchase(t, s, g, "hoody", ownerID)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		approved int
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(userID int) {
+			defer wg.Done()
+			_, err := s.ApproveGroupPurchase(ctx, p.ID, userID, 60, 0, groupCreatedAt.Add(2*time.Hour), nil)
+			if err != nil && !errors.Is(err, storage.ErrGroupPurchaseNotPending) {
+				assert.NoError(t, err)
+			}
+			if err == nil {
+				mu.Lock()
+				approved++
+				mu.Unlock()
+			}
+		}(ids[i])
+	}
+	wg.Wait()
+
+	assert.Equal(t, 2, approved, "approvals stop once the purchase is completed")
+	assert.Equal(t, 40, balance(t, s, "team"), "the item is bought exactly once")
+	got, err := s.GetGroupPurchase(ctx, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, storage.GroupPurchaseCompleted, got.Status)
+	assert.Len(t, got.Approvals, 2)
+}
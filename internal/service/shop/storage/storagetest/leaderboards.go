@@ -0,0 +1,99 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var leaderboardTests = []struct {
+	name string
+	fn   func(t *testing.T, s storage.IStorage)
+}{
+	{"GetLeaderboard_Aggregates", testGetLeaderboard},
+	{"SetLeaderboardOptOut_HidesUser", testSetLeaderboardOptOut},
+}
+
+// seedLeaderboard делает переводы и покупки, после которых:
+// отправители — alice 150, bob 30; получатели — bob 100, carol 80; предметы — alice 2, bob 1.
+func seedLeaderboard(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	carolID := addUser(t, s, "carol")
+	systemID, err := s.EnsureSystemAccount(ctx, "system")
+	require.NoError(t, err)
+
+	send := func(from string, fromID, toID int, to string, amount int, fee storage.TransferFee) {
+		require.NoError(t, s.SendCoins(ctx, from, fromID, toID, &storage.SendCoinRequest{ToUser: to, Amount: amount}, storage.TransferLimits{}, fee, nil))
+	}
+	send("alice", aliceID, bobID, "bob", 100, storage.TransferFee{})
+	send("alice", aliceID, carolID, "carol", 50, storage.TransferFee{})
+	send("bob", bobID, carolID, "carol", 30, storage.TransferFee{AccountID: systemID, Amount: 5})
+
+	require.NoError(t, s.BuyItem(ctx, "alice", "cup", 20, 0, nil))
+	require.NoError(t, s.BuyItem(ctx, "alice", "cup", 20, 0, nil))
+	require.NoError(t, s.BuyItem(ctx, "bob", "pen", 10, 0, nil))
+}
+
+func testGetLeaderboard(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	seedLeaderboard(t, s)
+	hourAgo, later := time.Now().UTC().Add(-time.Hour), time.Now().UTC().Add(time.Hour)
+
+	for _, since := range []*time.Time{nil, &hourAgo} {
+		senders, err := s.GetLeaderboard(ctx, storage.BoardSenders, since, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []storage.LeaderboardEntry{{Username: "alice", Value: 150}, {Username: "bob", Value: 30}}, senders,
+			"fees are not counted and system accounts are not listed")
+
+		receivers, err := s.GetLeaderboard(ctx, storage.BoardReceivers, since, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []storage.LeaderboardEntry{{Username: "bob", Value: 100}, {Username: "carol", Value: 80}}, receivers)
+
+		collectors, err := s.GetLeaderboard(ctx, storage.BoardCollectors, since, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []storage.LeaderboardEntry{{Username: "alice", Value: 2}, {Username: "bob", Value: 1}}, collectors)
+	}
+
+	top, err := s.GetLeaderboard(ctx, storage.BoardSenders, nil, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.LeaderboardEntry{{Username: "alice", Value: 150}}, top)
+
+	for _, board := range storage.Boards {
+		entries, err := s.GetLeaderboard(ctx, board, &later, 10)
+		require.NoError(t, err)
+		assert.Empty(t, entries, board)
+	}
+
+	_, err = s.GetLeaderboard(ctx, "richest", nil, 10)
+	assert.ErrorIs(t, err, storage.ErrUnknownLeaderboard)
+}
+
+func testSetLeaderboardOptOut(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	seedLeaderboard(t, s)
+
+	var ir storage.InfoResponse
+	aliceID, err := s.GetInfo(ctx, &ir, "alice")
+	require.NoError(t, err)
+
+	require.NoError(t, s.SetLeaderboardOptOut(ctx, aliceID, true))
+	require.NoError(t, s.SetLeaderboardOptOut(ctx, aliceID, true), "opting out twice is not an error")
+	senders, err := s.GetLeaderboard(ctx, storage.BoardSenders, nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.LeaderboardEntry{{Username: "bob", Value: 30}}, senders)
+	collectors, err := s.GetLeaderboard(ctx, storage.BoardCollectors, nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.LeaderboardEntry{{Username: "bob", Value: 1}}, collectors)
+
+	require.NoError(t, s.SetLeaderboardOptOut(ctx, aliceID, false))
+	senders, err = s.GetLeaderboard(ctx, storage.BoardSenders, nil, 10)
+	require.NoError(t, err)
+	assert.Len(t, senders, 2)
+}
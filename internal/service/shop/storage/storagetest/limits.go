@@ -0,0 +1,209 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var limitTests = []struct {
+	name string
+	fn   func(t *testing.T, s storage.IStorage)
+}{
+	{"UserRole_DefaultAndSet", testUserRole},
+	{"TransferTotals_SentAndReceived", testTransferTotals},
+	{"SendCoins_DailySentLimit", testSendCoinsDailySentLimit},
+	{"SendCoins_DailyReceivedLimit", testSendCoinsDailyReceivedLimit},
+	{"SendCoins_LimitConcurrent", testSendCoinsLimitConcurrent},
+	{"PaymentRequest_AcceptLimit", testPaymentRequestAcceptLimit},
+	{"ScheduledTransfer_RunLimit", testScheduledTransferRunLimit},
+	{"ScheduledTransfer_RunPerTransferLimit", testScheduledTransferRunPerTransferLimit},
+	{"ScheduledTransfer_RunApprovalThreshold", testScheduledTransferRunApprovalThreshold},
+}
+
+// limitsSince — начало окна суточных лимитов в тестах: переводы получают created_at = текущее время.
+func limitsSince() time.Time {
+	return time.Now().UTC().Add(-time.Hour)
+}
+
+func testUserRole(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	id := addUser(t, s, "alice")
+
+	role, err := s.GetUserRole(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, storage.DefaultRole, role)
+
+	require.NoError(t, s.SetUserRole(ctx, "alice", "merchant"))
+	require.NoError(t, s.SetUserRole(ctx, "alice", "merchant"), "setting the same role again is not an error")
+	role, err = s.GetUserRole(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "merchant", role)
+
+	assert.ErrorIs(t, s.SetUserRole(ctx, "nobody", "merchant"), storage.ErrUserNotFound)
+	_, err = s.GetUserRole(ctx, id+1000)
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+}
+
+func testTransferTotals(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 30}, storage.TransferLimits{}, storage.TransferFee{}, nil))
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 20}, storage.TransferLimits{}, storage.TransferFee{}, nil))
+	require.NoError(t, s.SendCoins(ctx, "bob", bobID, aliceID, &storage.SendCoinRequest{ToUser: "alice", Amount: 5}, storage.TransferLimits{}, storage.TransferFee{}, nil))
+
+	sent, received, err := s.GetTransferTotals(ctx, aliceID, limitsSince())
+	require.NoError(t, err)
+	assert.Equal(t, 50, sent)
+	assert.Equal(t, 5, received)
+
+	sent, received, err = s.GetTransferTotals(ctx, aliceID, time.Now().UTC().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+	assert.Zero(t, received)
+}
+
+func testSendCoinsDailySentLimit(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	limits := storage.TransferLimits{Since: limitsSince(), MaxSent: 100}
+
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 60}, limits, storage.TransferFee{}, nil))
+	err := s.SendCoins(ctx, "alice", aliceID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 50}, limits, storage.TransferFee{}, nil)
+
+	var le *storage.LimitExceededError
+	require.True(t, errors.As(err, &le), "got %v", err)
+	assert.Equal(t, &storage.LimitExceededError{Limit: storage.LimitDailySent, Max: 100, Used: 60}, le)
+	assert.ErrorIs(t, err, storage.ErrLimitExceeded)
+	assert.Equal(t, 940, balance(t, s, "alice"))
+	assert.Equal(t, 1060, balance(t, s, "bob"))
+
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 40}, limits, storage.TransferFee{}, nil), "the limit is inclusive")
+}
+
+func testSendCoinsDailyReceivedLimit(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	carolID := addUser(t, s, "carol")
+	limits := storage.TransferLimits{Since: limitsSince(), MaxReceived: 100}
+
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, carolID, &storage.SendCoinRequest{ToUser: "carol", Amount: 70}, limits, storage.TransferFee{}, nil))
+	err := s.SendCoins(ctx, "bob", bobID, carolID, &storage.SendCoinRequest{ToUser: "carol", Amount: 40}, limits, storage.TransferFee{}, nil)
+
+	var le *storage.LimitExceededError
+	require.True(t, errors.As(err, &le), "got %v", err)
+	assert.Equal(t, &storage.LimitExceededError{Limit: storage.LimitDailyReceived, Max: 100, Used: 70}, le)
+	assert.Equal(t, 1000, balance(t, s, "bob"))
+	assert.Equal(t, 1070, balance(t, s, "carol"))
+}
+
+func testSendCoinsLimitConcurrent(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	limits := storage.TransferLimits{Since: limitsSince(), MaxSent: 100}
+
+	const workers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- s.SendCoins(ctx, "alice", aliceID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 30}, limits, storage.TransferFee{}, nil)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	sent := 0
+	for err := range errs {
+		if err == nil {
+			sent++
+			continue
+		}
+		assert.ErrorIs(t, err, storage.ErrLimitExceeded)
+	}
+	assert.Equal(t, 3, sent, "parallel transfers must not exceed the daily limit together")
+	assert.Equal(t, 910, balance(t, s, "alice"))
+}
+
+func testPaymentRequestAcceptLimit(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	pr := createPaymentRequest(t, s, aliceID, bobID, 30, time.Hour)
+
+	err := s.AcceptPaymentRequest(ctx, pr.ID, paymentRequestNow, storage.TransferLimits{Since: limitsSince(), MaxSent: 20}, storage.TransferFee{}, nil)
+	assert.ErrorIs(t, err, storage.ErrLimitExceeded)
+
+	assert.Equal(t, 1000, balance(t, s, "bob"))
+	got, err := s.GetPaymentRequest(ctx, pr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, storage.PaymentRequestPending, got.Status)
+}
+
+func testScheduledTransferRunLimit(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	once := createScheduledTransfer(t, s, aliceID, bobID, 30, "", paymentRequestNow)
+
+	err := s.RunScheduledTransfer(ctx, once, nil, paymentRequestNow, storage.TransferLimits{Since: limitsSince(), MaxReceived: 20}, storage.TransferFee{}, nil)
+	assert.ErrorIs(t, err, storage.ErrLimitExceeded)
+
+	got := getScheduledTransfer(t, s, once.ID)
+	assert.Equal(t, storage.ScheduledTransferFailed, got.Status)
+	assert.Equal(t, err.Error(), got.LastError)
+	assert.Equal(t, 1000, balance(t, s, "alice"))
+	assert.Equal(t, 1000, balance(t, s, "bob"))
+}
+
+func testScheduledTransferRunPerTransferLimit(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	daily := createScheduledTransfer(t, s, aliceID, bobID, 30, "@daily", paymentRequestNow)
+	next := paymentRequestNow.Add(24 * time.Hour)
+
+	err := s.RunScheduledTransfer(ctx, daily, &next, paymentRequestNow, storage.TransferLimits{Since: limitsSince(), MaxPerTransfer: 20}, storage.TransferFee{}, nil)
+	var le *storage.LimitExceededError
+	require.True(t, errors.As(err, &le), "got %v", err)
+	assert.Equal(t, &storage.LimitExceededError{Limit: storage.LimitPerTransfer, Max: 20}, le)
+
+	// Запуск пропущен, а расписание продолжается со следующего срока.
+	got := getScheduledTransfer(t, s, daily.ID)
+	assert.Equal(t, storage.ScheduledTransferActive, got.Status)
+	assert.Equal(t, 1, got.Runs)
+	assert.Equal(t, next, got.NextRunAt)
+	assert.Equal(t, err.Error(), got.LastError)
+	assert.Equal(t, 1000, balance(t, s, "alice"))
+}
+
+func testScheduledTransferRunApprovalThreshold(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	once := createScheduledTransfer(t, s, aliceID, bobID, 30, "", paymentRequestNow)
+
+	err := s.RunScheduledTransfer(ctx, once, nil, paymentRequestNow, storage.TransferLimits{MaxUnapproved: 20}, storage.TransferFee{}, nil)
+	var le *storage.LimitExceededError
+	require.True(t, errors.As(err, &le), "got %v", err)
+	assert.Equal(t, &storage.LimitExceededError{Limit: storage.LimitApproval, Max: 20}, le)
+
+	got := getScheduledTransfer(t, s, once.ID)
+	assert.Equal(t, storage.ScheduledTransferFailed, got.Status)
+	assert.Equal(t, err.Error(), got.LastError)
+	assert.Equal(t, 1000, balance(t, s, "alice"))
+	assert.Equal(t, 1000, balance(t, s, "bob"))
+}
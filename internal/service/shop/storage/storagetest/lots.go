@@ -0,0 +1,122 @@
+package storagetest
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var lotTests = []struct {
+	name string
+	fn   func(t *testing.T, s storage.IStorage)
+}{
+	{"AddNewUser_GrantsInitialLot", testAddNewUserGrantsLot},
+	{"CoinLots_SpentOldestFirst", testCoinLotsSpentOldestFirst},
+	{"ExpireCoins_BurnsExpiredLots", testExpireCoins},
+	{"ExpireCoins_NotCountedInLimits", testExpireCoinsNotCountedInLimits},
+}
+
+// nextSecond ждёт начала следующей секунды, чтобы партии получили разные даты выдачи.
+func nextSecond() {
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+}
+
+func coinLots(tb testing.TB, s storage.IStorage, userID int) []storage.CoinLot {
+	lots, err := s.GetCoinLots(context.Background(), userID)
+	require.NoError(tb, err)
+	return lots
+}
+
+// seedLots создаёт alice и bob с партиями разных дат и возвращает их id и дату выдачи партии alice:
+// у alice 990 монет своей партии и 100 монет партии bob, у bob 900 монет своей партии.
+func seedLots(tb testing.TB, s storage.IStorage) (aliceID, bobID int, aliceGrantedAt time.Time) {
+	ctx := context.Background()
+	aliceID = addUser(tb, s, "alice")
+	nextSecond()
+	bobID = addUser(tb, s, "bob")
+
+	require.NoError(tb, s.SendCoins(ctx, "bob", bobID, aliceID,
+		&storage.SendCoinRequest{ToUser: "alice", Amount: 100}, storage.TransferLimits{}, storage.TransferFee{}, nil))
+	require.NoError(tb, s.BuyItem(ctx, "alice", "pen", 10, 0, nil))
+
+	return aliceID, bobID, coinLots(tb, s, aliceID)[0].GrantedAt
+}
+
+func testAddNewUserGrantsLot(t *testing.T, s storage.IStorage) {
+	before := time.Now().UTC().Truncate(time.Second)
+	id := addUser(t, s, "alice")
+
+	lots := coinLots(t, s, id)
+	require.Len(t, lots, 1)
+	assert.Equal(t, 1000, lots[0].Amount)
+	assert.WithinDuration(t, before, lots[0].GrantedAt, 2*time.Second)
+}
+
+func testCoinLotsSpentOldestFirst(t *testing.T, s storage.IStorage) {
+	aliceID, bobID, _ := seedLots(t, s)
+
+	aliceLots, bobLots := coinLots(t, s, aliceID), coinLots(t, s, bobID)
+	require.Len(t, aliceLots, 2)
+	require.Len(t, bobLots, 1)
+	assert.Equal(t, 990, aliceLots[0].Amount, "the purchase is paid from the oldest lot")
+	assert.Equal(t, 100, aliceLots[1].Amount)
+	assert.Equal(t, bobLots[0].GrantedAt, aliceLots[1].GrantedAt, "received coins keep their grant date")
+	assert.Equal(t, 900, bobLots[0].Amount)
+	assert.True(t, aliceLots[0].GrantedAt.Before(aliceLots[1].GrantedAt))
+}
+
+func testExpireCoins(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID, _, cutoff := seedLots(t, s)
+	systemID, err := s.EnsureSystemAccount(ctx, "system")
+	require.NoError(t, err)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	expired, err := s.ListExpiredCoins(ctx, cutoff, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.ExpiredCoins{{UserID: aliceID, Username: "alice", Amount: 990}}, expired)
+
+	amount, err := s.ExpireCoins(ctx, aliceID, systemID, cutoff, now, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 990, amount)
+	assert.Equal(t, 100, balance(t, s, "alice"))
+	assert.Equal(t, 990, balance(t, s, "system"))
+	assert.Equal(t, []storage.CoinLot{{Amount: 100, GrantedAt: coinLots(t, s, aliceID)[0].GrantedAt}}, coinLots(t, s, aliceID))
+
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetCoinHistory(ctx, &ir, aliceID, storage.HistoryFilter{Category: storage.ExpiredCategory}))
+	assert.Equal(t, []storage.TransactionOut{{ToUser: strconv.Itoa(systemID), Amount: 990, Category: storage.ExpiredCategory}}, ir.CoinHistory.Sent)
+
+	amount, err = s.ExpireCoins(ctx, aliceID, systemID, cutoff, now, nil)
+	require.NoError(t, err)
+	assert.Zero(t, amount, "already burned lots are not burned twice")
+
+	expired, err = s.ListExpiredCoins(ctx, cutoff, 10)
+	require.NoError(t, err)
+	assert.Empty(t, expired, "coins on system accounts never expire")
+}
+
+func testExpireCoinsNotCountedInLimits(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID, bobID, cutoff := seedLots(t, s)
+	systemID, err := s.EnsureSystemAccount(ctx, "system")
+	require.NoError(t, err)
+
+	_, err = s.ExpireCoins(ctx, aliceID, systemID, cutoff, time.Now().UTC().Truncate(time.Second), nil)
+	require.NoError(t, err)
+
+	// Сгоревшие 990 монет не занимают суточный лимит: можно отправить его целиком.
+	limits := storage.TransferLimits{Since: limitsSince(), MaxSent: 100}
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 100}, limits, storage.TransferFee{}, nil))
+	assert.Zero(t, balance(t, s, "alice"))
+
+	sent, _, err := s.GetTransferTotals(ctx, aliceID, limitsSince())
+	require.NoError(t, err)
+	assert.Equal(t, 100, sent)
+}
@@ -0,0 +1,198 @@
+package storagetest
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var paymentRequestTests = []struct {
+	name string
+	fn   func(t *testing.T, s storage.IStorage)
+}{
+	{"PaymentRequest_CreateGet", testPaymentRequestCreateGet},
+	{"PaymentRequest_NotFound", testPaymentRequestNotFound},
+	{"PaymentRequest_ListPending", testPaymentRequestListPending},
+	{"PaymentRequest_Accept", testPaymentRequestAccept},
+	{"PaymentRequest_AcceptInsufficientFunds", testPaymentRequestAcceptInsufficientFunds},
+	{"PaymentRequest_AcceptExpired", testPaymentRequestAcceptExpired},
+	{"PaymentRequest_AcceptConcurrent", testPaymentRequestAcceptConcurrent},
+	{"PaymentRequest_Resolve", testPaymentRequestResolve},
+}
+
+// paymentRequestNow — фиксированное «текущее» время; секунды без дробной части, чтобы
+// значения одинаково сохранялись во всех БД.
+var paymentRequestNow = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func createPaymentRequest(tb testing.TB, s storage.IStorage, requesterID, payerID, amount int, ttl time.Duration) *storage.PaymentRequest {
+	pr := &storage.PaymentRequest{
+		RequesterID: requesterID,
+		PayerID:     payerID,
+		Amount:      amount,
+		Memo:        "за пиццу",
+		CreatedAt:   paymentRequestNow,
+		ExpiresAt:   paymentRequestNow.Add(ttl),
+	}
+	require.NoError(tb, s.CreatePaymentRequest(context.Background(), pr))
+	require.NotZero(tb, pr.ID)
+	return pr
+}
+
+func testPaymentRequestCreateGet(t *testing.T, s storage.IStorage) {
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+
+	created := createPaymentRequest(t, s, aliceID, bobID, 30, time.Hour)
+
+	got, err := s.GetPaymentRequest(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &storage.PaymentRequest{
+		ID:          created.ID,
+		RequesterID: aliceID,
+		Requester:   "alice",
+		PayerID:     bobID,
+		Payer:       "bob",
+		Amount:      30,
+		Memo:        "за пиццу",
+		Status:      storage.PaymentRequestPending,
+		CreatedAt:   paymentRequestNow,
+		ExpiresAt:   paymentRequestNow.Add(time.Hour),
+	}, got)
+}
+
+func testPaymentRequestNotFound(t *testing.T, s storage.IStorage) {
+	_, err := s.GetPaymentRequest(context.Background(), 12345)
+	assert.ErrorIs(t, err, storage.ErrPaymentRequestNotFound)
+}
+
+func testPaymentRequestListPending(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	carolID := addUser(t, s, "carol")
+
+	first := createPaymentRequest(t, s, aliceID, bobID, 10, time.Hour)
+	createPaymentRequest(t, s, aliceID, bobID, 20, -time.Minute)
+	declined := createPaymentRequest(t, s, aliceID, bobID, 30, time.Hour)
+	require.NoError(t, s.ResolvePaymentRequest(ctx, declined.ID, storage.PaymentRequestDeclined, paymentRequestNow))
+	fromCarol := createPaymentRequest(t, s, carolID, bobID, 40, time.Hour)
+	createPaymentRequest(t, s, bobID, aliceID, 50, time.Hour)
+
+	incoming, err := s.ListPendingPaymentRequests(ctx, bobID, true, paymentRequestNow)
+	require.NoError(t, err)
+	ids := make([]int, 0, len(incoming))
+	for _, pr := range incoming {
+		ids = append(ids, pr.ID)
+	}
+	assert.Equal(t, []int{first.ID, fromCarol.ID}, ids)
+
+	outgoing, err := s.ListPendingPaymentRequests(ctx, aliceID, false, paymentRequestNow)
+	require.NoError(t, err)
+	require.Len(t, outgoing, 1)
+	assert.Equal(t, first.ID, outgoing[0].ID)
+	assert.Equal(t, "bob", outgoing[0].Payer)
+}
+
+func testPaymentRequestAccept(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	pr := createPaymentRequest(t, s, aliceID, bobID, 30, time.Hour)
+
+	require.NoError(t, s.AcceptPaymentRequest(ctx, pr.ID, paymentRequestNow, storage.TransferLimits{}, storage.TransferFee{}, nil))
+
+	assert.Equal(t, 1030, balance(t, s, "alice"))
+	assert.Equal(t, 970, balance(t, s, "bob"))
+
+	got, err := s.GetPaymentRequest(ctx, pr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, storage.PaymentRequestAccepted, got.Status)
+
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetReceivedHistory(ctx, &ir, aliceID))
+	assert.Equal(t, []storage.TransactionIn{{FromUser: strconv.Itoa(bobID), Amount: 30, Memo: "за пиццу"}}, ir.CoinHistory.Received)
+
+	assert.ErrorIs(t, s.AcceptPaymentRequest(ctx, pr.ID, paymentRequestNow, storage.TransferLimits{}, storage.TransferFee{}, nil), storage.ErrPaymentRequestNotPending)
+	assert.Equal(t, 970, balance(t, s, "bob"))
+}
+
+func testPaymentRequestAcceptInsufficientFunds(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	pr := createPaymentRequest(t, s, aliceID, bobID, 1001, time.Hour)
+
+	assert.ErrorIs(t, s.AcceptPaymentRequest(ctx, pr.ID, paymentRequestNow, storage.TransferLimits{}, storage.TransferFee{}, nil), storage.ErrInsufficientFunds)
+
+	assert.Equal(t, 1000, balance(t, s, "alice"))
+	assert.Equal(t, 1000, balance(t, s, "bob"))
+	got, err := s.GetPaymentRequest(ctx, pr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, storage.PaymentRequestPending, got.Status, "failed accept must roll back the status change")
+}
+
+func testPaymentRequestAcceptExpired(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	pr := createPaymentRequest(t, s, aliceID, bobID, 30, time.Minute)
+
+	err := s.AcceptPaymentRequest(ctx, pr.ID, paymentRequestNow.Add(time.Minute), storage.TransferLimits{}, storage.TransferFee{}, nil)
+	assert.ErrorIs(t, err, storage.ErrPaymentRequestNotPending)
+	assert.Equal(t, 1000, balance(t, s, "bob"))
+}
+
+func testPaymentRequestAcceptConcurrent(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	pr := createPaymentRequest(t, s, aliceID, bobID, 30, time.Hour)
+
+	const workers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- s.AcceptPaymentRequest(ctx, pr.ID, paymentRequestNow, storage.TransferLimits{}, storage.TransferFee{}, nil)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	accepted := 0
+	for err := range errs {
+		if err == nil {
+			accepted++
+			continue
+		}
+		assert.ErrorIs(t, err, storage.ErrPaymentRequestNotPending)
+	}
+	assert.Equal(t, 1, accepted)
+	assert.Equal(t, 970, balance(t, s, "bob"))
+}
+
+func testPaymentRequestResolve(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	pr := createPaymentRequest(t, s, aliceID, bobID, 30, time.Hour)
+
+	require.NoError(t, s.ResolvePaymentRequest(ctx, pr.ID, storage.PaymentRequestCancelled, paymentRequestNow))
+	assert.ErrorIs(t, s.ResolvePaymentRequest(ctx, pr.ID, storage.PaymentRequestDeclined, paymentRequestNow),
+		storage.ErrPaymentRequestNotPending)
+	assert.ErrorIs(t, s.AcceptPaymentRequest(ctx, pr.ID, paymentRequestNow, storage.TransferLimits{}, storage.TransferFee{}, nil), storage.ErrPaymentRequestNotPending)
+
+	got, err := s.GetPaymentRequest(ctx, pr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, storage.PaymentRequestCancelled, got.Status)
+	assert.Equal(t, 1000, balance(t, s, "bob"))
+}
@@ -0,0 +1,210 @@
+package storagetest
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pendingTransferTests = []struct {
+	name string
+	fn   func(t *testing.T, s storage.IStorage)
+}{
+	{"CreatePendingTransfer_HoldsCoins", testCreatePendingTransfer},
+	{"CreatePendingTransfer_InsufficientFunds", testCreatePendingTransferInsufficientFunds},
+	{"ResolvePendingTransfer_Approve", testApprovePendingTransfer},
+	{"ResolvePendingTransfer_RejectReturnsLots", testRejectPendingTransfer},
+	{"ResolvePendingTransfer_Expired", testExpirePendingTransfer},
+	{"ResolvePendingTransfer_Concurrent", testResolvePendingTransferConcurrent},
+}
+
+var pendingTransferNow = time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC)
+
+func createPendingTransfer(tb testing.TB, s storage.IStorage, senderID, recipientID, amount int, fee storage.TransferFee) *storage.PendingTransfer {
+	pt := &storage.PendingTransfer{
+		SenderID:     senderID,
+		RecipientID:  recipientID,
+		Amount:       amount,
+		Fee:          fee.Amount,
+		FeeAccountID: fee.AccountID,
+		Memo:         "rent",
+		CreatedAt:    pendingTransferNow,
+		ExpiresAt:    pendingTransferNow.Add(time.Hour),
+	}
+	require.NoError(tb, s.CreatePendingTransfer(context.Background(), pt, storage.TransferLimits{}, nil))
+	require.NotZero(tb, pt.ID)
+	return pt
+}
+
+func testCreatePendingTransfer(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	feeID, err := s.EnsureSystemAccount(ctx, "system")
+	require.NoError(t, err)
+	pt := createPendingTransfer(t, s, aliceID, bobID, 300, storage.TransferFee{AccountID: feeID, Amount: 10})
+
+	assert.Equal(t, 690, balance(t, s, "alice"), "the amount and the fee are held")
+	assert.Equal(t, 1000, balance(t, s, "bob"))
+	assert.Equal(t, 690, coinLots(t, s, aliceID)[0].Amount)
+
+	got, err := s.GetPendingTransfer(ctx, pt.ID)
+	require.NoError(t, err)
+	assert.Equal(t, &storage.PendingTransfer{
+		ID: pt.ID, SenderID: aliceID, Sender: "alice", RecipientID: bobID, Recipient: "bob", Amount: 300, Fee: 10,
+		FeeAccountID: feeID, Memo: "rent", Status: storage.PendingTransferPending,
+		CreatedAt: pendingTransferNow, ExpiresAt: pendingTransferNow.Add(time.Hour),
+	}, got)
+
+	all, err := s.ListPendingTransfers(ctx, 0, pendingTransferNow)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.PendingTransfer{*got}, all)
+	own, err := s.ListPendingTransfers(ctx, bobID, pendingTransferNow)
+	require.NoError(t, err)
+	assert.Empty(t, own, "only the sender's transfers are listed")
+	later, err := s.ListPendingTransfers(ctx, aliceID, pendingTransferNow.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, later, "expired transfers are not listed")
+
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetSendHistory(ctx, &ir, aliceID))
+	assert.Empty(t, ir.CoinHistory.Sent, "a held transfer is not in the history yet")
+
+	_, err = s.GetPendingTransfer(ctx, pt.ID+100)
+	assert.ErrorIs(t, err, storage.ErrPendingTransferNotFound)
+}
+
+func testCreatePendingTransferInsufficientFunds(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+
+	pt := &storage.PendingTransfer{SenderID: aliceID, RecipientID: bobID, Amount: 1001,
+		CreatedAt: pendingTransferNow, ExpiresAt: pendingTransferNow.Add(time.Hour)}
+	assert.ErrorIs(t, s.CreatePendingTransfer(ctx, pt, storage.TransferLimits{}, nil), storage.ErrInsufficientFunds)
+
+	assert.Equal(t, 1000, balance(t, s, "alice"))
+	all, err := s.ListPendingTransfers(ctx, 0, pendingTransferNow)
+	require.NoError(t, err)
+	assert.Empty(t, all, "the failed hold is rolled back")
+}
+
+func testApprovePendingTransfer(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	nextSecond()
+	bobID := addUser(t, s, "bob")
+	adminID := addUser(t, s, "admin")
+	feeID, err := s.EnsureSystemAccount(ctx, "system")
+	require.NoError(t, err)
+	pt := createPendingTransfer(t, s, aliceID, bobID, 300, storage.TransferFee{AccountID: feeID, Amount: 10})
+	grantedAt := coinLots(t, s, aliceID)[0].GrantedAt
+
+	at := pendingTransferNow.Add(time.Minute)
+	got, err := s.ResolvePendingTransfer(ctx, pt.ID, storage.PendingTransferApproved, adminID, at, storage.TransferLimits{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, storage.PendingTransferApproved, got.Status)
+	assert.Equal(t, "admin", got.ResolvedBy)
+	require.NotNil(t, got.ResolvedAt)
+	assert.Equal(t, at, *got.ResolvedAt)
+
+	assert.Equal(t, 690, balance(t, s, "alice"))
+	assert.Equal(t, 1300, balance(t, s, "bob"))
+	assert.Equal(t, 10, balance(t, s, "system"))
+	assert.Equal(t, storage.CoinLot{Amount: 300, GrantedAt: grantedAt}, coinLots(t, s, bobID)[0], "the recipient gets the held lots")
+
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetReceivedHistory(ctx, &ir, bobID))
+	assert.Equal(t, []storage.TransactionIn{{FromUser: strconv.Itoa(aliceID), Amount: 300, Memo: "rent"}}, ir.CoinHistory.Received)
+
+	_, err = s.ResolvePendingTransfer(ctx, pt.ID, storage.PendingTransferRejected, adminID, at, storage.TransferLimits{}, nil)
+	assert.ErrorIs(t, err, storage.ErrPendingTransferNotPending)
+	_, err = s.ResolvePendingTransfer(ctx, pt.ID+100, storage.PendingTransferApproved, adminID, at, storage.TransferLimits{}, nil)
+	assert.ErrorIs(t, err, storage.ErrPendingTransferNotFound)
+}
+
+func testRejectPendingTransfer(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID, bobID, _ := seedLots(t, s)
+	lots := coinLots(t, s, aliceID)
+	pt := createPendingTransfer(t, s, aliceID, bobID, 1090, storage.TransferFee{})
+	assert.Empty(t, coinLots(t, s, aliceID))
+
+	got, err := s.ResolvePendingTransfer(ctx, pt.ID, storage.PendingTransferRejected, bobID, pendingTransferNow, storage.TransferLimits{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, storage.PendingTransferRejected, got.Status)
+
+	assert.Equal(t, 1090, balance(t, s, "alice"))
+	assert.Equal(t, 900, balance(t, s, "bob"))
+	assert.Equal(t, lots, coinLots(t, s, aliceID), "the sender gets back the same lots")
+}
+
+func testExpirePendingTransfer(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	adminID := addUser(t, s, "admin")
+	pt := createPendingTransfer(t, s, aliceID, bobID, 300, storage.TransferFee{})
+
+	expired, err := s.ListExpiredPendingTransfers(ctx, pt.ExpiresAt.Add(-time.Second), 10)
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+	expired, err = s.ListExpiredPendingTransfers(ctx, pt.ExpiresAt, 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, pt.ID, expired[0].ID)
+
+	_, err = s.ResolvePendingTransfer(ctx, pt.ID, storage.PendingTransferApproved, adminID, pt.ExpiresAt, storage.TransferLimits{}, nil)
+	assert.ErrorIs(t, err, storage.ErrPendingTransferNotPending, "an expired transfer cannot be approved")
+
+	got, err := s.ResolvePendingTransfer(ctx, pt.ID, storage.PendingTransferExpired, 0, pt.ExpiresAt, storage.TransferLimits{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, storage.PendingTransferExpired, got.Status)
+	assert.Empty(t, got.ResolvedBy)
+	assert.Equal(t, 1000, balance(t, s, "alice"))
+	assert.Equal(t, 1000, balance(t, s, "bob"))
+}
+
+func testResolvePendingTransferConcurrent(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	adminID := addUser(t, s, "admin")
+	pt := createPendingTransfer(t, s, aliceID, bobID, 300, storage.TransferFee{})
+
+	const workers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		status := storage.PendingTransferApproved
+		if i%2 == 1 {
+			status = storage.PendingTransferRejected
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.ResolvePendingTransfer(ctx, pt.ID, status, adminID, pendingTransferNow, storage.TransferLimits{}, nil)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	resolved := 0
+	for err := range errs {
+		if err == nil {
+			resolved++
+			continue
+		}
+		assert.ErrorIs(t, err, storage.ErrPendingTransferNotPending)
+	}
+	assert.Equal(t, 1, resolved)
+	assert.Equal(t, 2000, balance(t, s, "alice")+balance(t, s, "bob"), "the held coins are settled exactly once")
+}
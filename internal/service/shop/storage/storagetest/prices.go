@@ -0,0 +1,86 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var priceTests = []struct {
+	name string
+	fn   func(t *testing.T, s storage.IStorage)
+}{
+	{"CreateItemPrice_RoundTrip", testCreateItemPrice},
+	{"BuyItem_RecordsOrder", testBuyItemRecordsOrder},
+}
+
+func testCreateItemPrice(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	saleEnd := now.Add(time.Hour)
+
+	prices := []storage.ItemPrice{
+		{Item: "hoody", Price: 250, StartsAt: now.Add(24 * time.Hour)},
+		{Item: "hoody", Price: 150, StartsAt: now.Add(-time.Hour), EndsAt: &saleEnd},
+		{Item: "cup", Price: 10, StartsAt: now.Add(-2 * time.Hour), EndsAt: &now},
+	}
+	for i := range prices {
+		prices[i].CreatedAt = now
+		require.NoError(t, s.CreateItemPrice(ctx, &prices[i]))
+		assert.NotZero(t, prices[i].ID)
+	}
+
+	got, err := s.GetItemPrice(ctx, prices[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, &prices[1], got)
+
+	list, err := s.ListItemPrices(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.ItemPrice{prices[1], prices[0]}, list, "ended prices are skipped, the rest are ordered by start")
+
+	require.NoError(t, s.DeleteItemPrice(ctx, prices[0].ID))
+	assert.ErrorIs(t, s.DeleteItemPrice(ctx, prices[0].ID), storage.ErrItemPriceNotFound)
+	_, err = s.GetItemPrice(ctx, prices[0].ID)
+	assert.ErrorIs(t, err, storage.ErrItemPriceNotFound)
+
+	list, err = s.ListItemPrices(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.ItemPrice{prices[1]}, list)
+}
+
+func testBuyItemRecordsOrder(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	pc := createPromoCode(t, s, storage.PromoCode{Code: "MINUS5", Kind: storage.PromoFixed, Value: 5})
+
+	hoodyPrice := &storage.ItemPrice{Item: "hoody", Price: 150, StartsAt: time.Now().UTC().Add(-time.Hour).Truncate(time.Second),
+		CreatedAt: time.Now().UTC().Truncate(time.Second)}
+	require.NoError(t, s.CreateItemPrice(ctx, hoodyPrice))
+
+	orders, err := s.ListOrders(ctx, aliceID)
+	require.NoError(t, err)
+	assert.Empty(t, orders)
+
+	require.NoError(t, s.BuyItem(ctx, "alice", "hoody", 150, hoodyPrice.ID, nil))
+	r := redemption(pc, 20)
+	require.NoError(t, s.BuyItemWithPromo(ctx, "alice", "cup", r, nil))
+	require.NoError(t, s.BuyItem(ctx, "bob", "pen", 10, 0, nil))
+
+	orders, err = s.ListOrders(ctx, aliceID)
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+	assert.Equal(t, storage.Order{ID: orders[0].ID, Item: "hoody", Price: 150, Paid: 150, PriceID: hoodyPrice.ID, CreatedAt: orders[0].CreatedAt}, orders[0])
+	assert.WithinDuration(t, time.Now(), orders[0].CreatedAt, time.Minute)
+	assert.Equal(t, storage.Order{ID: orders[1].ID, Item: "cup", Price: 20, Paid: 15, CreatedAt: r.RedeemedAt}, orders[1])
+
+	orders, err = s.ListOrders(ctx, bobID)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, "pen", orders[0].Item)
+}
@@ -0,0 +1,161 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var promoTests = []struct {
+	name string
+	fn   func(t *testing.T, s storage.IStorage)
+}{
+	{"CreatePromoCode_RoundTrip", testCreatePromoCode},
+	{"BuyItemWithPromo_RecordsDiscount", testBuyItemWithPromo},
+	{"BuyItemWithPromo_EnforcesUsageCaps", testBuyItemWithPromoCaps},
+	{"BuyItemWithPromo_Concurrent", testBuyItemWithPromoConcurrent},
+}
+
+func createPromoCode(tb testing.TB, s storage.IStorage, pc storage.PromoCode) *storage.PromoCode {
+	if pc.CreatedAt.IsZero() {
+		pc.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	}
+	require.NoError(tb, s.CreatePromoCode(context.Background(), &pc))
+	return &pc
+}
+
+func redemption(pc *storage.PromoCode, price int) *storage.PromoRedemption {
+	return &storage.PromoRedemption{PromoCodeID: pc.ID, Price: price, Discount: pc.Discount(price),
+		RedeemedAt: time.Now().UTC().Truncate(time.Second)}
+}
+
+func testCreatePromoCode(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	startsAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	endsAt := startsAt.AddDate(0, 1, 0)
+
+	created := createPromoCode(t, s, storage.PromoCode{Code: "HOODY20", Kind: storage.PromoPercent, Value: 20,
+		Items: []string{"hoody", "pink-hoody"}, MaxUses: 100, MaxUsesPerUser: 1, StartsAt: &startsAt, EndsAt: &endsAt})
+	createPromoCode(t, s, storage.PromoCode{Code: "MINUS10", Kind: storage.PromoFixed, Value: 10})
+	assert.NotZero(t, created.ID)
+
+	got, err := s.GetPromoCode(ctx, "HOODY20")
+	require.NoError(t, err)
+	assert.Equal(t, created, got)
+
+	err = s.CreatePromoCode(ctx, &storage.PromoCode{Code: "HOODY20", Kind: storage.PromoFixed, Value: 1, CreatedAt: created.CreatedAt})
+	assert.ErrorIs(t, err, storage.ErrPromoCodeExists)
+
+	_, err = s.GetPromoCode(ctx, "UNKNOWN")
+	assert.ErrorIs(t, err, storage.ErrPromoCodeNotFound)
+
+	require.NoError(t, s.DisablePromoCode(ctx, "MINUS10", endsAt))
+	require.NoError(t, s.DisablePromoCode(ctx, "MINUS10", endsAt.Add(time.Hour)))
+
+	codes, err := s.ListPromoCodes(ctx)
+	require.NoError(t, err)
+	require.Len(t, codes, 2)
+	assert.Equal(t, "HOODY20", codes[0].Code)
+	assert.Nil(t, codes[0].DisabledAt)
+	assert.Nil(t, codes[1].Items)
+	require.NotNil(t, codes[1].DisabledAt)
+	assert.Equal(t, endsAt, *codes[1].DisabledAt, "disabling twice keeps the first time")
+}
+
+func testBuyItemWithPromo(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	pc := createPromoCode(t, s, storage.PromoCode{Code: "HOODY20", Kind: storage.PromoPercent, Value: 20})
+
+	require.NoError(t, s.BuyItemWithPromo(ctx, "alice", "hoody", redemption(pc, 300), nil))
+
+	assert.Equal(t, 760, balance(t, s, "alice"))
+	assert.Equal(t, []storage.CoinLot{{Amount: 760, GrantedAt: coinLots(t, s, aliceID)[0].GrantedAt}}, coinLots(t, s, aliceID))
+
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetInventory(ctx, &ir, aliceID))
+	assert.Equal(t, []storage.Inventory{{Type: "hoody", Quantity: 1}}, ir.Inventory)
+
+	used, err := s.CountPromoRedemptions(ctx, pc.ID, aliceID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, used)
+
+	got, err := s.GetPromoCode(ctx, "HOODY20")
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Uses)
+}
+
+func testBuyItemWithPromoCaps(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	addUser(t, s, "bob")
+	addUser(t, s, "carol")
+	pc := createPromoCode(t, s, storage.PromoCode{Code: "ONCE", Kind: storage.PromoFixed, Value: 15, MaxUses: 2, MaxUsesPerUser: 1})
+
+	require.NoError(t, s.BuyItemWithPromo(ctx, "alice", "cup", redemption(pc, 20), nil))
+	assert.ErrorIs(t, s.BuyItemWithPromo(ctx, "alice", "cup", redemption(pc, 20), nil), storage.ErrPromoCodeUserLimit)
+	assert.Equal(t, 995, balance(t, s, "alice"), "a rejected purchase changes nothing")
+
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetInventory(ctx, &ir, aliceID))
+	assert.Equal(t, []storage.Inventory{{Type: "cup", Quantity: 1}}, ir.Inventory)
+
+	require.NoError(t, s.BuyItemWithPromo(ctx, "bob", "cup", redemption(pc, 20), nil))
+	assert.ErrorIs(t, s.BuyItemWithPromo(ctx, "carol", "cup", redemption(pc, 20), nil), storage.ErrPromoCodeUnavailable)
+	assert.Equal(t, 1000, balance(t, s, "carol"))
+
+	other := createPromoCode(t, s, storage.PromoCode{Code: "OFF", Kind: storage.PromoFixed, Value: 5})
+	require.NoError(t, s.DisablePromoCode(ctx, "OFF", time.Now().UTC()))
+	assert.ErrorIs(t, s.BuyItemWithPromo(ctx, "carol", "cup", redemption(other, 20), nil), storage.ErrPromoCodeUnavailable)
+
+	future := time.Now().UTC().Add(time.Hour)
+	later := createPromoCode(t, s, storage.PromoCode{Code: "LATER", Kind: storage.PromoFixed, Value: 5, StartsAt: &future})
+	assert.ErrorIs(t, s.BuyItemWithPromo(ctx, "carol", "cup", redemption(later, 20), nil), storage.ErrPromoCodeUnavailable)
+}
+
+func testBuyItemWithPromoConcurrent(t *testing.T, s storage.IStorage) {
+	const (
+		buyers  = 8
+		maxUses = 3
+	)
+	ctx := context.Background()
+	for i := 0; i < buyers; i++ {
+		addUser(t, s, fmt.Sprintf("buyer%d", i))
+	}
+	pc := createPromoCode(t, s, storage.PromoCode{Code: "FIRST3", Kind: storage.PromoPercent, Value: 50, MaxUses: maxUses})
+
+	var (
+		wg   sync.WaitGroup
+		errs = make(chan error, buyers)
+	)
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- s.BuyItemWithPromo(ctx, fmt.Sprintf("buyer%d", i), "book", redemption(pc, 50), nil)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.ErrorIs(t, err, storage.ErrPromoCodeUnavailable)
+	}
+	assert.Equal(t, maxUses, succeeded)
+
+	got, err := s.GetPromoCode(ctx, "FIRST3")
+	require.NoError(t, err)
+	assert.Equal(t, maxUses, got.Uses)
+}
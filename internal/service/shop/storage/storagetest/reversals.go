@@ -0,0 +1,242 @@
+package storagetest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var reversalTests = []struct {
+	name string
+	fn   func(t *testing.T, s storage.IStorage)
+}{
+	{"ReverseTransaction_ReturnsLots", testReverseTransaction},
+	{"ReverseTransaction_Partial", testReverseTransactionPartial},
+	{"ReverseTransaction_Negative", testReverseTransactionNegative},
+	{"ReverseTransaction_NegativeRefillExpire", testReverseTransactionNegativeRefillExpire},
+	{"ReverseTransaction_NothingToReverse", testReverseTransactionNothingToReverse},
+	{"ReverseTransaction_Concurrent", testReverseTransactionConcurrent},
+	{"ReverseTransaction_NotBetweenUsers", testReverseTransactionNotBetweenUsers},
+}
+
+var reversalNow = time.Date(2026, 10, 3, 12, 0, 0, 0, time.UTC)
+
+// seedReversal создаёт alice, bob и admin, переводит 300 монет от alice к bob
+// и возвращает их id и id перевода.
+func seedReversal(tb testing.TB, s storage.IStorage) (aliceID, bobID, adminID, transactionID int) {
+	ctx := context.Background()
+	aliceID = addUser(tb, s, "alice")
+	nextSecond()
+	bobID = addUser(tb, s, "bob")
+	adminID = addUser(tb, s, "admin")
+
+	require.NoError(tb, s.SendCoins(ctx, "alice", aliceID, bobID,
+		&storage.SendCoinRequest{ToUser: "bob", Amount: 300, Memo: "rent"}, storage.TransferLimits{}, storage.TransferFee{}, nil))
+	txs, err := s.ListUserTransactions(ctx, aliceID, 10)
+	require.NoError(tb, err)
+	require.Len(tb, txs, 1)
+
+	return aliceID, bobID, adminID, txs[0].ID
+}
+
+func reversal(transactionID, adminID int, policy string) *storage.TransferReversal {
+	return &storage.TransferReversal{TransactionID: transactionID, ReversedByID: adminID, Policy: policy,
+		Reason: "wrong recipient", CreatedAt: reversalNow}
+}
+
+func testReverseTransaction(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID, bobID, adminID, txID := seedReversal(t, s)
+	grantedAt := coinLots(t, s, aliceID)[0].GrantedAt
+
+	rv := reversal(txID, adminID, storage.ReversalPartial)
+	require.NoError(t, s.ReverseTransaction(ctx, rv, nil))
+	assert.NotZero(t, rv.ID)
+	assert.Equal(t, &storage.TransferReversal{
+		ID: rv.ID, TransactionID: txID, FromUserID: aliceID, FromUser: "alice", ToUserID: bobID, ToUser: "bob",
+		Amount: 300, Reversed: 300, Policy: storage.ReversalPartial, RecipientBefore: 1300, RecipientAfter: 1000,
+		Reason: "wrong recipient", ReversedByID: adminID, ReversedBy: "admin", CreatedAt: reversalNow,
+	}, rv)
+
+	assert.Equal(t, 1000, balance(t, s, "alice"))
+	assert.Equal(t, 1000, balance(t, s, "bob"))
+	assert.Equal(t, []storage.CoinLot{{Amount: 1000, GrantedAt: grantedAt}}, coinLots(t, s, aliceID), "the sender gets back own lots")
+
+	txs, err := s.ListUserTransactions(ctx, aliceID, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 2, "the original transfer is kept")
+	assert.Equal(t, storage.Transaction{ID: txs[0].ID, FromUser: "bob", ToUser: "alice", Amount: 300, Memo: "wrong recipient",
+		Category: storage.ReversalCategory, CreatedAt: &reversalNow, ReversalOf: txID}, txs[0])
+	assert.Equal(t, txID, txs[1].ID)
+	assert.True(t, txs[1].Reversed)
+	assert.Equal(t, "rent", txs[1].Memo)
+
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetSendHistory(ctx, &ir, aliceID))
+	assert.Len(t, ir.CoinHistory.Sent, 1)
+
+	assert.ErrorIs(t, s.ReverseTransaction(ctx, reversal(txID, adminID, storage.ReversalPartial), nil), storage.ErrTransactionAlreadyReversed)
+	assert.ErrorIs(t, s.ReverseTransaction(ctx, reversal(txs[0].ID, adminID, storage.ReversalPartial), nil), storage.ErrTransactionNotReversible,
+		"a reversal cannot be reversed")
+	assert.ErrorIs(t, s.ReverseTransaction(ctx, reversal(txID+100, adminID, storage.ReversalPartial), nil), storage.ErrTransactionNotFound)
+	assert.Equal(t, 1000, balance(t, s, "alice"))
+	assert.Equal(t, 1000, balance(t, s, "bob"))
+}
+
+func testReverseTransactionPartial(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	_, bobID, adminID, txID := seedReversal(t, s)
+	require.NoError(t, s.BuyItem(ctx, "bob", "pen", 1200, 0, nil))
+
+	rv := reversal(txID, adminID, storage.ReversalPartial)
+	require.NoError(t, s.ReverseTransaction(ctx, rv, nil))
+	assert.Equal(t, 100, rv.Reversed, "only what is left is returned")
+	assert.Equal(t, 100, rv.RecipientBefore)
+	assert.Zero(t, rv.RecipientAfter)
+
+	assert.Equal(t, 800, balance(t, s, "alice"))
+	assert.Equal(t, 0, balance(t, s, "bob"))
+	assert.Empty(t, coinLots(t, s, bobID))
+}
+
+func testReverseTransactionNegative(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID, bobID, adminID, txID := seedReversal(t, s)
+	require.NoError(t, s.BuyItem(ctx, "bob", "pen", 1200, 0, nil))
+
+	rv := reversal(txID, adminID, storage.ReversalNegative)
+	require.NoError(t, s.ReverseTransaction(ctx, rv, nil))
+	assert.Equal(t, 300, rv.Reversed)
+	assert.Equal(t, -200, rv.RecipientAfter)
+
+	assert.Equal(t, 1000, balance(t, s, "alice"))
+	assert.Equal(t, -200, balance(t, s, "bob"), "the recipient owes the spent coins")
+	assert.Empty(t, coinLots(t, s, bobID))
+
+	total := 0
+	for _, lot := range coinLots(t, s, aliceID) {
+		total += lot.Amount
+	}
+	assert.Equal(t, 1000, total, "the shortfall is granted as a new lot")
+}
+
+// Долг после отмены с ReversalNegative гасится следующим зачислением: партии остаются равны
+// балансу, и сгорание не уводит баланс снова в минус.
+func testReverseTransactionNegativeRefillExpire(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	_, bobID, adminID, txID := seedReversal(t, s)
+	require.NoError(t, s.BuyItem(ctx, "bob", "pen", 1200, 0, nil))
+	require.NoError(t, s.ReverseTransaction(ctx, reversal(txID, adminID, storage.ReversalNegative), nil))
+	require.Equal(t, -200, balance(t, s, "bob"))
+
+	require.NoError(t, s.SendCoins(ctx, "admin", adminID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 300},
+		storage.TransferLimits{}, storage.TransferFee{}, nil))
+	assert.Equal(t, 100, balance(t, s, "bob"))
+	lots := coinLots(t, s, bobID)
+	require.Len(t, lots, 1)
+	assert.Equal(t, 100, lots[0].Amount, "the refill repays the debt first")
+
+	systemID, err := s.EnsureSystemAccount(ctx, "system")
+	require.NoError(t, err)
+	now := time.Now().UTC().Truncate(time.Second)
+	amount, err := s.ExpireCoins(ctx, bobID, systemID, now.Add(time.Minute), now, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 100, amount)
+	assert.Zero(t, balance(t, s, "bob"))
+	assert.Empty(t, coinLots(t, s, bobID))
+	assert.Equal(t, 100, balance(t, s, "system"))
+}
+
+func testReverseTransactionNothingToReverse(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID, _, adminID, txID := seedReversal(t, s)
+	require.NoError(t, s.BuyItem(ctx, "bob", "pen", 1300, 0, nil))
+
+	assert.ErrorIs(t, s.ReverseTransaction(ctx, reversal(txID, adminID, storage.ReversalPartial), nil), storage.ErrInsufficientFunds)
+
+	assert.Equal(t, 700, balance(t, s, "alice"))
+	assert.Equal(t, 0, balance(t, s, "bob"))
+	txs, err := s.ListUserTransactions(ctx, aliceID, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	assert.False(t, txs[0].Reversed, "the failed reversal is rolled back")
+}
+
+func testReverseTransactionConcurrent(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	_, _, adminID, txID := seedReversal(t, s)
+
+	const workers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- s.ReverseTransaction(ctx, reversal(txID, adminID, storage.ReversalNegative), nil)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	reversed := 0
+	for err := range errs {
+		if err == nil {
+			reversed++
+			continue
+		}
+		assert.ErrorIs(t, err, storage.ErrTransactionAlreadyReversed)
+	}
+	assert.Equal(t, 1, reversed)
+	assert.Equal(t, 1000, balance(t, s, "alice"))
+	assert.Equal(t, 1000, balance(t, s, "bob"))
+}
+
+// Комиссии, сгорания и переводы со служебным счётом или кошельком группы отменить нельзя.
+func testReverseTransactionNotBetweenUsers(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID, bobID, adminID, _ := seedReversal(t, s)
+	systemID, err := s.EnsureSystemAccount(ctx, "system")
+	require.NoError(t, err)
+	g := &storage.Group{Name: "team", ApprovalsRequired: 1, CreatedAt: reversalNow}
+	require.NoError(t, s.CreateGroup(ctx, g, aliceID))
+
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 10},
+		storage.TransferLimits{}, storage.TransferFee{AccountID: systemID, Amount: 3}, nil))
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, g.ID, &storage.SendCoinRequest{ToUser: "team", Amount: 50},
+		storage.TransferLimits{}, storage.TransferFee{}, nil))
+	require.NoError(t, s.SendCoins(ctx, "team", g.ID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 20},
+		storage.TransferLimits{}, storage.TransferFee{}, nil))
+	require.NoError(t, s.SendCoins(ctx, "system", systemID, aliceID, &storage.SendCoinRequest{ToUser: "alice", Amount: 1},
+		storage.TransferLimits{}, storage.TransferFee{}, nil))
+	now := time.Now().UTC().Truncate(time.Second)
+	_, err = s.ExpireCoins(ctx, bobID, systemID, now.Add(time.Minute), now, nil)
+	require.NoError(t, err)
+
+	txs, err := s.ListUserTransactions(ctx, bobID, 20)
+	require.NoError(t, err)
+	aliceTxs, err := s.ListUserTransactions(ctx, aliceID, 20)
+	require.NoError(t, err)
+	txs = append(txs, aliceTxs...)
+
+	systemBalance, teamBalance := balance(t, s, "system"), balance(t, s, "team")
+	var checked []string
+	for _, tx := range txs {
+		plain := tx.Category == "" && tx.FromUser != "team" && tx.ToUser != "team" && tx.FromUser != "system"
+		if plain && tx.FromUser != "" {
+			continue
+		}
+		checked = append(checked, tx.FromUser+">"+tx.ToUser+":"+tx.Category)
+		err = s.ReverseTransaction(ctx, reversal(tx.ID, adminID, storage.ReversalNegative), nil)
+		assert.ErrorIs(t, err, storage.ErrTransactionNotReversible, "transaction %+v", tx)
+	}
+	assert.Subset(t, checked, []string{"alice>system:fee", "bob>system:expired", "alice>team:", "team>bob:", "system>alice:"})
+	assert.Equal(t, systemBalance, balance(t, s, "system"))
+	assert.Equal(t, teamBalance, balance(t, s, "team"))
+}
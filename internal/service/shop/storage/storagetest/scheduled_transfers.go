@@ -0,0 +1,221 @@
+package storagetest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var scheduledTransferTests = []struct {
+	name string
+	fn   func(t *testing.T, s storage.IStorage)
+}{
+	{"ScheduledTransfer_CreateGet", testScheduledTransferCreateGet},
+	{"ScheduledTransfer_ListDue", testScheduledTransferListDue},
+	{"ScheduledTransfer_RunRecurring", testScheduledTransferRunRecurring},
+	{"ScheduledTransfer_RunOnce", testScheduledTransferRunOnce},
+	{"ScheduledTransfer_RunInsufficientFunds", testScheduledTransferRunInsufficientFunds},
+	{"ScheduledTransfer_RunConcurrent", testScheduledTransferRunConcurrent},
+	{"ScheduledTransfer_Cancel", testScheduledTransferCancel},
+}
+
+func createScheduledTransfer(tb testing.TB, s storage.IStorage, senderID, recipientID, amount int, schedule string, runAt time.Time) *storage.ScheduledTransfer {
+	st := &storage.ScheduledTransfer{
+		SenderID:    senderID,
+		RecipientID: recipientID,
+		Amount:      amount,
+		Memo:        "бонус",
+		Category:    "thanks",
+		Schedule:    schedule,
+		NextRunAt:   runAt,
+		CreatedAt:   paymentRequestNow,
+	}
+	require.NoError(tb, s.CreateScheduledTransfer(context.Background(), st))
+	require.NotZero(tb, st.ID)
+	return st
+}
+
+func getScheduledTransfer(tb testing.TB, s storage.IStorage, id int) *storage.ScheduledTransfer {
+	st, err := s.GetScheduledTransfer(context.Background(), id)
+	require.NoError(tb, err)
+	return st
+}
+
+func testScheduledTransferCreateGet(t *testing.T, s storage.IStorage) {
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	created := createScheduledTransfer(t, s, aliceID, bobID, 25, "0 9 * * 1", paymentRequestNow.Add(time.Hour))
+
+	assert.Equal(t, &storage.ScheduledTransfer{
+		ID:          created.ID,
+		SenderID:    aliceID,
+		Sender:      "alice",
+		RecipientID: bobID,
+		Recipient:   "bob",
+		Amount:      25,
+		Memo:        "бонус",
+		Category:    "thanks",
+		Schedule:    "0 9 * * 1",
+		Status:      storage.ScheduledTransferActive,
+		NextRunAt:   paymentRequestNow.Add(time.Hour),
+		CreatedAt:   paymentRequestNow,
+	}, getScheduledTransfer(t, s, created.ID))
+
+	_, err := s.GetScheduledTransfer(context.Background(), created.ID+100)
+	assert.ErrorIs(t, err, storage.ErrScheduledTransferNotFound)
+
+	list, err := s.ListScheduledTransfers(context.Background(), aliceID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, created.ID, list[0].ID)
+}
+
+func testScheduledTransferListDue(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+
+	late := createScheduledTransfer(t, s, aliceID, bobID, 10, "", paymentRequestNow.Add(-time.Minute))
+	early := createScheduledTransfer(t, s, aliceID, bobID, 10, "", paymentRequestNow.Add(-time.Hour))
+	exact := createScheduledTransfer(t, s, aliceID, bobID, 10, "", paymentRequestNow)
+	createScheduledTransfer(t, s, aliceID, bobID, 10, "", paymentRequestNow.Add(time.Minute))
+	cancelled := createScheduledTransfer(t, s, aliceID, bobID, 10, "", paymentRequestNow.Add(-time.Hour))
+	require.NoError(t, s.CancelScheduledTransfer(ctx, cancelled.ID))
+
+	due, err := s.ListDueScheduledTransfers(ctx, paymentRequestNow, 10)
+	require.NoError(t, err)
+	ids := make([]int, 0, len(due))
+	for _, st := range due {
+		ids = append(ids, st.ID)
+	}
+	assert.Equal(t, []int{early.ID, late.ID, exact.ID}, ids)
+
+	due, err = s.ListDueScheduledTransfers(ctx, paymentRequestNow, 1)
+	require.NoError(t, err)
+	assert.Len(t, due, 1)
+}
+
+func testScheduledTransferRunRecurring(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	st := createScheduledTransfer(t, s, aliceID, bobID, 25, "0 9 * * 1", paymentRequestNow)
+
+	next := paymentRequestNow.Add(7 * 24 * time.Hour)
+	require.NoError(t, s.RunScheduledTransfer(ctx, st, &next, paymentRequestNow, storage.TransferLimits{}, storage.TransferFee{}, nil))
+
+	got := getScheduledTransfer(t, s, st.ID)
+	assert.Equal(t, storage.ScheduledTransferActive, got.Status)
+	assert.Equal(t, next, got.NextRunAt)
+	assert.Equal(t, 1, got.Runs)
+	require.NotNil(t, got.LastRunAt)
+	assert.Equal(t, paymentRequestNow, *got.LastRunAt)
+	assert.Equal(t, 975, balance(t, s, "alice"))
+	assert.Equal(t, 1025, balance(t, s, "bob"))
+
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetReceivedHistory(ctx, &ir, bobID))
+	require.Len(t, ir.CoinHistory.Received, 1)
+	assert.Equal(t, "бонус", ir.CoinHistory.Received[0].Memo)
+	assert.Equal(t, "thanks", ir.CoinHistory.Received[0].Category)
+
+	// Повтор с устаревшим счётчиком запусков не выполняется.
+	assert.ErrorIs(t, s.RunScheduledTransfer(ctx, st, &next, paymentRequestNow, storage.TransferLimits{}, storage.TransferFee{}, nil), storage.ErrScheduledTransferClaimed)
+	assert.Equal(t, 975, balance(t, s, "alice"))
+}
+
+func testScheduledTransferRunOnce(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	st := createScheduledTransfer(t, s, aliceID, bobID, 25, "", paymentRequestNow)
+
+	require.NoError(t, s.RunScheduledTransfer(ctx, st, nil, paymentRequestNow, storage.TransferLimits{}, storage.TransferFee{}, nil))
+
+	got := getScheduledTransfer(t, s, st.ID)
+	assert.Equal(t, storage.ScheduledTransferCompleted, got.Status)
+	due, err := s.ListDueScheduledTransfers(ctx, paymentRequestNow.Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	assert.ErrorIs(t, s.CancelScheduledTransfer(ctx, st.ID), storage.ErrScheduledTransferNotActive)
+}
+
+func testScheduledTransferRunInsufficientFunds(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	recurring := createScheduledTransfer(t, s, aliceID, bobID, 5000, "@daily", paymentRequestNow)
+	once := createScheduledTransfer(t, s, aliceID, bobID, 5000, "", paymentRequestNow)
+
+	next := paymentRequestNow.Add(24 * time.Hour)
+	assert.ErrorIs(t, s.RunScheduledTransfer(ctx, recurring, &next, paymentRequestNow, storage.TransferLimits{}, storage.TransferFee{}, nil), storage.ErrInsufficientFunds)
+	assert.ErrorIs(t, s.RunScheduledTransfer(ctx, once, nil, paymentRequestNow, storage.TransferLimits{}, storage.TransferFee{}, nil), storage.ErrInsufficientFunds)
+
+	got := getScheduledTransfer(t, s, recurring.ID)
+	assert.Equal(t, storage.ScheduledTransferActive, got.Status)
+	assert.Equal(t, next, got.NextRunAt)
+	assert.Equal(t, 1, got.Runs)
+	assert.Equal(t, storage.ErrInsufficientFunds.Error(), got.LastError)
+
+	got = getScheduledTransfer(t, s, once.ID)
+	assert.Equal(t, storage.ScheduledTransferFailed, got.Status)
+	assert.Equal(t, storage.ErrInsufficientFunds.Error(), got.LastError)
+
+	assert.Equal(t, 1000, balance(t, s, "alice"))
+	assert.Equal(t, 1000, balance(t, s, "bob"))
+}
+
+func testScheduledTransferRunConcurrent(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	st := createScheduledTransfer(t, s, aliceID, bobID, 30, "@hourly", paymentRequestNow)
+	next := paymentRequestNow.Add(time.Hour)
+
+	// Каждый исполнитель работает со своей копией, как отдельная реплика после ListDueScheduledTransfers.
+	const workers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(st storage.ScheduledTransfer) {
+			defer wg.Done()
+			errs <- s.RunScheduledTransfer(ctx, &st, &next, paymentRequestNow, storage.TransferLimits{}, storage.TransferFee{}, nil)
+		}(*st)
+	}
+	wg.Wait()
+	close(errs)
+
+	executed := 0
+	for err := range errs {
+		if err == nil {
+			executed++
+			continue
+		}
+		assert.ErrorIs(t, err, storage.ErrScheduledTransferClaimed)
+	}
+	assert.Equal(t, 1, executed)
+	assert.Equal(t, 970, balance(t, s, "alice"))
+	assert.Equal(t, 1, getScheduledTransfer(t, s, st.ID).Runs)
+}
+
+func testScheduledTransferCancel(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	st := createScheduledTransfer(t, s, aliceID, bobID, 30, "@daily", paymentRequestNow)
+
+	require.NoError(t, s.CancelScheduledTransfer(ctx, st.ID))
+	assert.ErrorIs(t, s.CancelScheduledTransfer(ctx, st.ID), storage.ErrScheduledTransferNotActive)
+
+	next := paymentRequestNow.Add(24 * time.Hour)
+	assert.ErrorIs(t, s.RunScheduledTransfer(ctx, st, &next, paymentRequestNow, storage.TransferLimits{}, storage.TransferFee{}, nil), storage.ErrScheduledTransferClaimed)
+	assert.Equal(t, storage.ScheduledTransferCancelled, getScheduledTransfer(t, s, st.ID).Status)
+	assert.Equal(t, 1000, balance(t, s, "alice"))
+}
@@ -0,0 +1,505 @@
+// Package storagetest содержит общий набор проверок контракта storage.IStorage,
+// который запускается для каждой реализации хранилища.
+package storagetest
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"avito-shop/internal/service/shop/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory возвращает пустое хранилище для одного теста или бенчмарка.
+type Factory func(tb testing.TB) storage.IStorage
+
+// Run запускает все проверки контракта для хранилища, создаваемого newStorage.
+//
+// Ожидаемый контракт:
+//   - CheckAuth и GetInfo для неизвестного пользователя возвращают storage.ErrUserNotFound;
+//   - GetInventory и история для пользователя без записей ничего не добавляют в InfoResponse и не возвращают ошибку;
+//   - GetFullInfo возвращает то же, что GetInfo, GetInventory и обе истории вместе;
+//   - BuyItem увеличивает количество уже купленного предмета, а не добавляет новую строку;
+//   - memo и category перевода возвращаются во всех запросах истории, пустые значения — пустыми строками;
+//   - GetCoinHistory с фильтром по категории отдаёт только переводы этой категории в порядке создания;
+//   - SendCoins атомарен: при ошибке не меняются ни балансы, ни история;
+//   - SendCoins и BuyItem проверяют баланс в транзакции списания и при нехватке монет возвращают
+//     storage.ErrInsufficientFunds, в том числе при параллельных тратах;
+//   - параллельные переводы не теряют обновлений баланса;
+//   - запрос на перевод принимается ровно один раз и только пока он pending и не истёк,
+//     а при нехватке монет не меняется ни баланс, ни статус запроса;
+//   - каждый запуск отложенного перевода выполняется ровно один раз даже при параллельных исполнителях,
+//     а запуск без денег фиксируется в last_error и не повторяется;
+//   - суточные лимиты проверяются в транзакции перевода, и параллельные переводы не превышают их вместе;
+//   - комиссия списывается в той же транзакции отдельной записью на служебный счёт и в лимиты не входит,
+//     в том числе при оплате запроса и запуске отложенного перевода;
+//   - монеты хранятся партиями с датой выдачи: траты расходуют старые партии первыми, перевод сохраняет
+//     даты выдачи, а сгорание переносит просроченные партии на служебный счёт ровно один раз;
+//   - покупка по промокоду засчитывает применение в той же транзакции, и параллельные покупки
+//     не превышают ни общий лимит кода, ни лимит на пользователя;
+//   - ListItemPrices не возвращает закончившиеся цены, а каждая покупка записывает заказ с ценой, id записи цены и списанной суммой;
+//   - таблицы лидеров не учитывают комиссии, сгорания, служебные счета и отказавшихся от участия пользователей;
+//   - достижение выдаётся пользователю не больше одного раза, в том числе при одновременной выдаче;
+//   - покупка группы выполняется ровно один раз на последнем нужном одобрении, в той же транзакции,
+//     а одобрение, на которое не хватило монет кошелька, не записывается;
+//   - перевод на одобрении удерживает сумму с комиссией вместе с партиями и закрывается ровно один раз:
+//     одобрение отдаёт удержанные партии получателю и пишет историю, отказ и истечение возвращают их отправителю;
+//   - отмена перевода не меняет исходную запись, а добавляет компенсирующую и выполняется для записи ровно один раз;
+//     с политикой partial возвращается не больше баланса получателя, с negative — вся сумма;
+//   - журнал аудита только дополняется, и параллельные записи образуют одну цепочку хэшей.
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s storage.IStorage)
+	}{
+		{"AddNewUser_CheckAuth", testAddNewUserCheckAuth},
+		{"AddNewUser_Duplicate", testAddNewUserDuplicate},
+		{"CheckAuth_UserNotFound", testCheckAuthUserNotFound},
+		{"GetInfo_DefaultBalance", testGetInfoDefaultBalance},
+		{"GetInfo_UserNotFound", testGetInfoUserNotFound},
+		{"GetInventory_Empty", testGetInventoryEmpty},
+		{"GetHistory_Empty", testGetHistoryEmpty},
+		{"GetFullInfo_MatchesSeparateQueries", testGetFullInfo},
+		{"GetFullInfo_NewUser", testGetFullInfoNewUser},
+		{"GetFullInfo_UserNotFound", testGetFullInfoUserNotFound},
+		{"BuyItem_UpsertsInventory", testBuyItemUpsertsInventory},
+		{"BuyItem_UserNotFound", testBuyItemUserNotFound},
+		{"BuyItem_InsufficientFunds", testBuyItemInsufficientFunds},
+		{"SendCoins_UpdatesBalancesAndHistory", testSendCoins},
+		{"SendCoins_HistoryKeepsEveryTransfer", testSendCoinsHistory},
+		{"SendCoins_RollbackOnFailure", testSendCoinsRollback},
+		{"SendCoins_MemoAndCategory", testSendCoinsMemoAndCategory},
+		{"GetCoinHistory_FilterByCategory", testGetCoinHistoryFilter},
+		{"SendCoins_Concurrent", testSendCoinsConcurrent},
+		{"SendCoins_InsufficientFundsConcurrent", testSendCoinsInsufficientFundsConcurrent},
+	}
+
+	for _, tt := range append(append(append(append(append(append(append(append(append(append(append(append(append(tests, paymentRequestTests...), scheduledTransferTests...), limitTests...), feeTests...), lotTests...), promoTests...), priceTests...), leaderboardTests...), achievementTests...), groupTests...), pendingTransferTests...), reversalTests...), auditTests...) {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStorage(t))
+		})
+	}
+}
+
+func addUser(tb testing.TB, s storage.IStorage, username string) int {
+	ctx := context.Background()
+	require.NoError(tb, s.AddNewUser(ctx, username, "hashed_password"))
+
+	var ir storage.InfoResponse
+	id, err := s.GetInfo(ctx, &ir, username)
+	require.NoError(tb, err)
+	return id
+}
+
+func testAddNewUserCheckAuth(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	require.NoError(t, s.AddNewUser(ctx, "test_user", "hashed_password"))
+
+	hash, err := s.CheckAuth(ctx, "test_user")
+	require.NoError(t, err)
+	assert.Equal(t, "hashed_password", hash)
+}
+
+func testAddNewUserDuplicate(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	require.NoError(t, s.AddNewUser(ctx, "test_user", "hashed_password"))
+	assert.Error(t, s.AddNewUser(ctx, "test_user", "hashed_password"))
+}
+
+func testCheckAuthUserNotFound(t *testing.T, s storage.IStorage) {
+	_, err := s.CheckAuth(context.Background(), "non_existent_user")
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+}
+
+func testGetInfoDefaultBalance(t *testing.T, s storage.IStorage) {
+	addUser(t, s, "test_user")
+
+	var ir storage.InfoResponse
+	_, err := s.GetInfo(context.Background(), &ir, "test_user")
+	require.NoError(t, err)
+	assert.Equal(t, 1000, ir.Coins)
+}
+
+func testGetInfoUserNotFound(t *testing.T, s storage.IStorage) {
+	var ir storage.InfoResponse
+	_, err := s.GetInfo(context.Background(), &ir, "non_existent_user")
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+}
+
+func balance(tb testing.TB, s storage.IStorage, username string) int {
+	var ir storage.InfoResponse
+	_, err := s.GetInfo(context.Background(), &ir, username)
+	require.NoError(tb, err)
+	return ir.Coins
+}
+
+func testGetInventoryEmpty(t *testing.T, s storage.IStorage) {
+	id := addUser(t, s, "test_user")
+
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetInventory(context.Background(), &ir, id))
+	assert.Empty(t, ir.Inventory)
+}
+
+func testGetHistoryEmpty(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	id := addUser(t, s, "test_user")
+
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetSendHistory(ctx, &ir, id))
+	require.NoError(t, s.GetReceivedHistory(ctx, &ir, id))
+	assert.Empty(t, ir.CoinHistory.Sent)
+	assert.Empty(t, ir.CoinHistory.Received)
+}
+
+// seedActivity создаёт пользователя alice с покупками и переводами в обе стороны и возвращает её id.
+func seedActivity(tb testing.TB, s storage.IStorage, transfers int) int {
+	ctx := context.Background()
+	aliceID := addUser(tb, s, "alice")
+	bobID := addUser(tb, s, "bob")
+
+	for _, item := range []string{"cup", "pen", "cup"} {
+		require.NoError(tb, s.BuyItem(ctx, "alice", item, 1, 0, nil))
+	}
+	for i := 0; i < transfers; i++ {
+		require.NoError(tb, s.SendCoins(ctx, "alice", aliceID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 2}, storage.TransferLimits{}, storage.TransferFee{}, nil))
+		require.NoError(tb, s.SendCoins(ctx, "bob", bobID, aliceID, &storage.SendCoinRequest{ToUser: "alice", Amount: 1}, storage.TransferLimits{}, storage.TransferFee{}, nil))
+	}
+	return aliceID
+}
+
+func testGetFullInfo(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := seedActivity(t, s, 3)
+
+	var want storage.InfoResponse
+	_, err := s.GetInfo(ctx, &want, "alice")
+	require.NoError(t, err)
+	require.NoError(t, s.GetInventory(ctx, &want, aliceID))
+	require.NoError(t, s.GetSendHistory(ctx, &want, aliceID))
+	require.NoError(t, s.GetReceivedHistory(ctx, &want, aliceID))
+
+	var got storage.InfoResponse
+	id, err := s.GetFullInfo(ctx, &got, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, aliceID, id)
+	assert.Equal(t, want.Coins, got.Coins)
+	assert.ElementsMatch(t, want.Inventory, got.Inventory)
+	assert.ElementsMatch(t, want.CoinHistory.Sent, got.CoinHistory.Sent)
+	assert.ElementsMatch(t, want.CoinHistory.Received, got.CoinHistory.Received)
+	assert.Len(t, got.CoinHistory.Sent, 3)
+}
+
+func testGetFullInfoNewUser(t *testing.T, s storage.IStorage) {
+	id := addUser(t, s, "test_user")
+
+	var ir storage.InfoResponse
+	gotID, err := s.GetFullInfo(context.Background(), &ir, "test_user")
+	require.NoError(t, err)
+	assert.Equal(t, id, gotID)
+	assert.Equal(t, storage.InfoResponse{Coins: 1000}, ir)
+}
+
+func testGetFullInfoUserNotFound(t *testing.T, s storage.IStorage) {
+	var ir storage.InfoResponse
+	_, err := s.GetFullInfo(context.Background(), &ir, "non_existent_user")
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+}
+
+func testBuyItemUpsertsInventory(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	id := addUser(t, s, "test_user")
+
+	require.NoError(t, s.BuyItem(ctx, "test_user", "t-shirt", 80, 0, nil))
+	require.NoError(t, s.BuyItem(ctx, "test_user", "t-shirt", 80, 0, nil))
+	require.NoError(t, s.BuyItem(ctx, "test_user", "cup", 20, 0, nil))
+
+	var ir storage.InfoResponse
+	_, err := s.GetInfo(ctx, &ir, "test_user")
+	require.NoError(t, err)
+	assert.Equal(t, 1000-80-80-20, ir.Coins)
+
+	require.NoError(t, s.GetInventory(ctx, &ir, id))
+	assert.ElementsMatch(t, []storage.Inventory{
+		{Type: "t-shirt", Quantity: 2},
+		{Type: "cup", Quantity: 1},
+	}, ir.Inventory)
+}
+
+func testBuyItemUserNotFound(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	addUser(t, s, "test_user")
+
+	assert.Error(t, s.BuyItem(ctx, "non_existent_user", "cup", 20, 0, nil))
+	assert.Equal(t, 1000, balance(t, s, "test_user"))
+}
+
+func testBuyItemInsufficientFunds(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	id := addUser(t, s, "test_user")
+
+	assert.ErrorIs(t, s.BuyItem(ctx, "test_user", "pink-hoody", 1001, 0, nil), storage.ErrInsufficientFunds)
+	assert.Equal(t, 1000, balance(t, s, "test_user"))
+
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetInventory(ctx, &ir, id))
+	assert.Empty(t, ir.Inventory)
+}
+
+func testSendCoins(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	senderID := addUser(t, s, "sender")
+	recipientID := addUser(t, s, "recipient")
+
+	err := s.SendCoins(ctx, "sender", senderID, recipientID, &storage.SendCoinRequest{ToUser: "recipient", Amount: 50}, storage.TransferLimits{}, storage.TransferFee{}, nil)
+	require.NoError(t, err)
+
+	var sender, recipient storage.InfoResponse
+	_, err = s.GetInfo(ctx, &sender, "sender")
+	require.NoError(t, err)
+	_, err = s.GetInfo(ctx, &recipient, "recipient")
+	require.NoError(t, err)
+	assert.Equal(t, 950, sender.Coins)
+	assert.Equal(t, 1050, recipient.Coins)
+
+	require.NoError(t, s.GetSendHistory(ctx, &sender, senderID))
+	assert.Equal(t, []storage.TransactionOut{{ToUser: strconv.Itoa(recipientID), Amount: 50}}, sender.CoinHistory.Sent)
+
+	require.NoError(t, s.GetReceivedHistory(ctx, &recipient, recipientID))
+	assert.Equal(t, []storage.TransactionIn{{FromUser: strconv.Itoa(senderID), Amount: 50}}, recipient.CoinHistory.Received)
+}
+
+func testSendCoinsHistory(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+	carolID := addUser(t, s, "carol")
+
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 10}, storage.TransferLimits{}, storage.TransferFee{}, nil))
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 10}, storage.TransferLimits{}, storage.TransferFee{}, nil))
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, carolID, &storage.SendCoinRequest{ToUser: "carol", Amount: 30}, storage.TransferLimits{}, storage.TransferFee{}, nil))
+	require.NoError(t, s.SendCoins(ctx, "carol", carolID, aliceID, &storage.SendCoinRequest{ToUser: "alice", Amount: 5}, storage.TransferLimits{}, storage.TransferFee{}, nil))
+
+	var alice, bob storage.InfoResponse
+	require.NoError(t, s.GetSendHistory(ctx, &alice, aliceID))
+	require.NoError(t, s.GetReceivedHistory(ctx, &alice, aliceID))
+	assert.ElementsMatch(t, []storage.TransactionOut{
+		{ToUser: strconv.Itoa(bobID), Amount: 10},
+		{ToUser: strconv.Itoa(bobID), Amount: 10},
+		{ToUser: strconv.Itoa(carolID), Amount: 30},
+	}, alice.CoinHistory.Sent)
+	assert.Equal(t, []storage.TransactionIn{{FromUser: strconv.Itoa(carolID), Amount: 5}}, alice.CoinHistory.Received)
+
+	require.NoError(t, s.GetSendHistory(ctx, &bob, bobID))
+	assert.Empty(t, bob.CoinHistory.Sent)
+
+	assert.Equal(t, 1000-10-10-30+5, balance(t, s, "alice"))
+	assert.Equal(t, 1000+20, balance(t, s, "bob"))
+	assert.Equal(t, 1000+30-5, balance(t, s, "carol"))
+}
+
+func testSendCoinsRollback(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	senderID := addUser(t, s, "sender")
+	missingID := senderID + 1000
+
+	// Получателя нет: перевод не проходит, и транзакция должна откатиться целиком.
+	err := s.SendCoins(ctx, "sender", senderID, missingID, &storage.SendCoinRequest{ToUser: "non_existent_user", Amount: 50}, storage.TransferLimits{}, storage.TransferFee{}, nil)
+	require.Error(t, err)
+
+	assert.Equal(t, 1000, balance(t, s, "sender"))
+
+	var ir storage.InfoResponse
+	require.NoError(t, s.GetSendHistory(ctx, &ir, senderID))
+	assert.Empty(t, ir.CoinHistory.Sent)
+}
+
+func testSendCoinsMemoAndCategory(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, bobID,
+		&storage.SendCoinRequest{ToUser: "bob", Amount: 10, Memo: "за обед 🍜", Category: "lunch"}, storage.TransferLimits{}, storage.TransferFee{}, nil))
+	require.NoError(t, s.SendCoins(ctx, "alice", aliceID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 5}, storage.TransferLimits{}, storage.TransferFee{}, nil))
+
+	wantSent := []storage.TransactionOut{
+		{ToUser: strconv.Itoa(bobID), Amount: 10, Memo: "за обед 🍜", Category: "lunch"},
+		{ToUser: strconv.Itoa(bobID), Amount: 5},
+	}
+	wantReceived := []storage.TransactionIn{
+		{FromUser: strconv.Itoa(aliceID), Amount: 10, Memo: "за обед 🍜", Category: "lunch"},
+		{FromUser: strconv.Itoa(aliceID), Amount: 5},
+	}
+
+	var alice, bob storage.InfoResponse
+	require.NoError(t, s.GetSendHistory(ctx, &alice, aliceID))
+	require.NoError(t, s.GetReceivedHistory(ctx, &bob, bobID))
+	assert.ElementsMatch(t, wantSent, alice.CoinHistory.Sent)
+	assert.ElementsMatch(t, wantReceived, bob.CoinHistory.Received)
+
+	var full storage.InfoResponse
+	_, err := s.GetFullInfo(ctx, &full, "bob")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, wantReceived, full.CoinHistory.Received)
+}
+
+func testGetCoinHistoryFilter(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+
+	send := func(from string, fromID, toID int, to string, amount int, category string) {
+		require.NoError(t, s.SendCoins(ctx, from, fromID, toID,
+			&storage.SendCoinRequest{ToUser: to, Amount: amount, Category: category}, storage.TransferLimits{}, storage.TransferFee{}, nil))
+	}
+	send("alice", aliceID, bobID, "bob", 1, "lunch")
+	send("alice", aliceID, bobID, "bob", 2, "bet")
+	send("bob", bobID, aliceID, "alice", 3, "lunch")
+	send("alice", aliceID, bobID, "bob", 4, "")
+	send("alice", aliceID, bobID, "bob", 5, "lunch")
+
+	var lunch storage.InfoResponse
+	require.NoError(t, s.GetCoinHistory(ctx, &lunch, aliceID, storage.HistoryFilter{Category: "lunch"}))
+	assert.Equal(t, []storage.TransactionOut{
+		{ToUser: strconv.Itoa(bobID), Amount: 1, Category: "lunch"},
+		{ToUser: strconv.Itoa(bobID), Amount: 5, Category: "lunch"},
+	}, lunch.CoinHistory.Sent)
+	assert.Equal(t, []storage.TransactionIn{
+		{FromUser: strconv.Itoa(bobID), Amount: 3, Category: "lunch"},
+	}, lunch.CoinHistory.Received)
+
+	var all storage.InfoResponse
+	require.NoError(t, s.GetCoinHistory(ctx, &all, aliceID, storage.HistoryFilter{}))
+	amounts := make([]int, 0, len(all.CoinHistory.Sent))
+	for _, tr := range all.CoinHistory.Sent {
+		amounts = append(amounts, tr.Amount)
+	}
+	assert.Equal(t, []int{1, 2, 4, 5}, amounts)
+	assert.Len(t, all.CoinHistory.Received, 1)
+
+	var none storage.InfoResponse
+	require.NoError(t, s.GetCoinHistory(ctx, &none, aliceID, storage.HistoryFilter{Category: "gift"}))
+	assert.Empty(t, none.CoinHistory.Sent)
+	assert.Empty(t, none.CoinHistory.Received)
+}
+
+func testSendCoinsConcurrent(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+
+	const workers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*2)
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			errs <- s.SendCoins(ctx, "alice", aliceID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 7}, storage.TransferLimits{}, storage.TransferFee{}, nil)
+		}()
+		go func() {
+			defer wg.Done()
+			errs <- s.SendCoins(ctx, "bob", bobID, aliceID, &storage.SendCoinRequest{ToUser: "alice", Amount: 3}, storage.TransferLimits{}, storage.TransferFee{}, nil)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 1000-workers*7+workers*3, balance(t, s, "alice"))
+	assert.Equal(t, 1000+workers*7-workers*3, balance(t, s, "bob"))
+
+	var alice storage.InfoResponse
+	require.NoError(t, s.GetSendHistory(ctx, &alice, aliceID))
+	require.NoError(t, s.GetReceivedHistory(ctx, &alice, aliceID))
+	assert.Len(t, alice.CoinHistory.Sent, workers)
+	assert.Len(t, alice.CoinHistory.Received, workers)
+}
+
+// BenchmarkInfo сравнивает чтение информации пользователя одним запросом GetFullInfo
+// с прежней схемой: GetInfo и три параллельных запроса инвентаря и историй.
+func BenchmarkInfo(b *testing.B, newStorage Factory) {
+	s := newStorage(b)
+	seedActivity(b, s, 50)
+	ctx := context.Background()
+
+	b.Run("FanOut", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var ir storage.InfoResponse
+			id, err := s.GetInfo(ctx, &ir, "alice")
+			if err != nil {
+				b.Fatal(err)
+			}
+			var (
+				wg sync.WaitGroup
+				mu sync.Mutex
+			)
+			// Каждая горутина пишет в своё поле InfoResponse, как и в прежней реализации сервиса.
+			errs := make([]error, 0, 3)
+			for _, fn := range []func(context.Context, *storage.InfoResponse, int) error{
+				s.GetInventory, s.GetSendHistory, s.GetReceivedHistory,
+			} {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if err := fn(ctx, &ir, id); err != nil {
+						mu.Lock()
+						errs = append(errs, err)
+						mu.Unlock()
+					}
+				}()
+			}
+			wg.Wait()
+			if len(errs) > 0 {
+				b.Fatal(errs[0])
+			}
+		}
+	})
+
+	b.Run("SingleQuery", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var ir storage.InfoResponse
+			if _, err := s.GetFullInfo(ctx, &ir, "alice"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func testSendCoinsInsufficientFundsConcurrent(t *testing.T, s storage.IStorage) {
+	ctx := context.Background()
+	aliceID := addUser(t, s, "alice")
+	bobID := addUser(t, s, "bob")
+
+	const workers = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- s.SendCoins(ctx, "alice", aliceID, bobID, &storage.SendCoinRequest{ToUser: "bob", Amount: 300}, storage.TransferLimits{}, storage.TransferFee{}, nil)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	sent := 0
+	for err := range errs {
+		if err == nil {
+			sent++
+			continue
+		}
+		assert.ErrorIs(t, err, storage.ErrInsufficientFunds)
+	}
+	assert.Equal(t, 3, sent, "parallel transfers must not overdraw the sender")
+	assert.Equal(t, 100, balance(t, s, "alice"))
+	assert.Equal(t, 1900, balance(t, s, "bob"))
+}
@@ -0,0 +1,434 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"avito-shop/internal/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "avito-shop/internal/service/shop/storage"
+
+// TracedStorage оборачивает IStorage и открывает клиентский спан на каждый запрос к хранилищу.
+type TracedStorage struct {
+	next   IStorage
+	system string
+	tracer trace.Tracer
+}
+
+var _ IStorage = &TracedStorage{}
+
+// NewTracedStorage создаёт обёртку; system — значение атрибута db.system (например, "mysql").
+func NewTracedStorage(next IStorage, system string) *TracedStorage {
+	return &TracedStorage{next: next, system: system, tracer: otel.Tracer(tracerName)}
+}
+
+func (t *TracedStorage) start(ctx context.Context, operation string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, semconv.DBSystemKey.String(t.system), semconv.DBOperationName(operation))
+	return t.tracer.Start(ctx, "storage."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func (t *TracedStorage) CheckAuth(ctx context.Context, username string) (hash string, err error) {
+	ctx, span := t.start(ctx, "CheckAuth")
+	defer func() { tracing.End(span, err) }()
+	return t.next.CheckAuth(ctx, username)
+}
+
+func (t *TracedStorage) AddNewUser(ctx context.Context, username, password string) (err error) {
+	ctx, span := t.start(ctx, "AddNewUser")
+	defer func() { tracing.End(span, err) }()
+	return t.next.AddNewUser(ctx, username, password)
+}
+
+func (t *TracedStorage) GetInfo(ctx context.Context, ir *InfoResponse, username string) (id int, err error) {
+	ctx, span := t.start(ctx, "GetInfo")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetInfo(ctx, ir, username)
+}
+
+func (t *TracedStorage) GetFullInfo(ctx context.Context, ir *InfoResponse, username string) (id int, err error) {
+	ctx, span := t.start(ctx, "GetFullInfo")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetFullInfo(ctx, ir, username)
+}
+
+func (t *TracedStorage) GetInventory(ctx context.Context, ir *InfoResponse, id int) (err error) {
+	ctx, span := t.start(ctx, "GetInventory", attribute.Int("user.id", id))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetInventory(ctx, ir, id)
+}
+
+func (t *TracedStorage) GetReceivedHistory(ctx context.Context, ir *InfoResponse, id int) (err error) {
+	ctx, span := t.start(ctx, "GetReceivedHistory", attribute.Int("user.id", id))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetReceivedHistory(ctx, ir, id)
+}
+
+func (t *TracedStorage) GetSendHistory(ctx context.Context, ir *InfoResponse, id int) (err error) {
+	ctx, span := t.start(ctx, "GetSendHistory", attribute.Int("user.id", id))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetSendHistory(ctx, ir, id)
+}
+
+func (t *TracedStorage) GetCoinHistory(ctx context.Context, ir *InfoResponse, id int, filter HistoryFilter) (err error) {
+	ctx, span := t.start(ctx, "GetCoinHistory", attribute.Int("user.id", id))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetCoinHistory(ctx, ir, id, filter)
+}
+
+func (t *TracedStorage) BuyItem(ctx context.Context, name, item string, amount, priceID int, audit *TxAudit) (err error) {
+	ctx, span := t.start(ctx, "BuyItem", attribute.String("shop.item", item))
+	defer func() { tracing.End(span, err) }()
+	return t.next.BuyItem(ctx, name, item, amount, priceID, audit)
+}
+
+func (t *TracedStorage) BuyItemWithPromo(ctx context.Context, name, item string, r *PromoRedemption, audit *TxAudit) (err error) {
+	ctx, span := t.start(ctx, "BuyItemWithPromo", attribute.String("shop.item", item), attribute.Int("shop.discount", r.Discount))
+	defer func() { tracing.End(span, err) }()
+	return t.next.BuyItemWithPromo(ctx, name, item, r, audit)
+}
+
+func (t *TracedStorage) SendCoins(ctx context.Context, username string, fromUserID int, toUserID int, scr *SendCoinRequest, limits TransferLimits, fee TransferFee, audit *TxAudit) (err error) {
+	ctx, span := t.start(ctx, "SendCoins", attribute.Int("shop.amount", scr.Amount), attribute.Int("shop.fee", fee.Amount))
+	defer func() { tracing.End(span, err) }()
+	return t.next.SendCoins(ctx, username, fromUserID, toUserID, scr, limits, fee, audit)
+}
+
+func (t *TracedStorage) CreatePaymentRequest(ctx context.Context, pr *PaymentRequest) (err error) {
+	ctx, span := t.start(ctx, "CreatePaymentRequest")
+	defer func() { tracing.End(span, err) }()
+	return t.next.CreatePaymentRequest(ctx, pr)
+}
+
+func (t *TracedStorage) GetPaymentRequest(ctx context.Context, id int) (pr *PaymentRequest, err error) {
+	ctx, span := t.start(ctx, "GetPaymentRequest", attribute.Int("payment_request.id", id))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetPaymentRequest(ctx, id)
+}
+
+func (t *TracedStorage) ListPendingPaymentRequests(ctx context.Context, userID int, incoming bool, now time.Time) (prs []PaymentRequest, err error) {
+	ctx, span := t.start(ctx, "ListPendingPaymentRequests", attribute.Int("user.id", userID), attribute.Bool("incoming", incoming))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListPendingPaymentRequests(ctx, userID, incoming, now)
+}
+
+func (t *TracedStorage) AcceptPaymentRequest(ctx context.Context, id int, now time.Time, limits TransferLimits, fee TransferFee, audit *TxAudit) (err error) {
+	ctx, span := t.start(ctx, "AcceptPaymentRequest", attribute.Int("payment_request.id", id), attribute.Int("shop.fee", fee.Amount))
+	defer func() { tracing.End(span, err) }()
+	return t.next.AcceptPaymentRequest(ctx, id, now, limits, fee, audit)
+}
+
+func (t *TracedStorage) ResolvePaymentRequest(ctx context.Context, id int, status string, now time.Time) (err error) {
+	ctx, span := t.start(ctx, "ResolvePaymentRequest", attribute.Int("payment_request.id", id), attribute.String("status", status))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ResolvePaymentRequest(ctx, id, status, now)
+}
+
+func (t *TracedStorage) CreateScheduledTransfer(ctx context.Context, st *ScheduledTransfer) (err error) {
+	ctx, span := t.start(ctx, "CreateScheduledTransfer")
+	defer func() { tracing.End(span, err) }()
+	return t.next.CreateScheduledTransfer(ctx, st)
+}
+
+func (t *TracedStorage) GetScheduledTransfer(ctx context.Context, id int) (st *ScheduledTransfer, err error) {
+	ctx, span := t.start(ctx, "GetScheduledTransfer", attribute.Int("scheduled_transfer.id", id))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetScheduledTransfer(ctx, id)
+}
+
+func (t *TracedStorage) ListScheduledTransfers(ctx context.Context, senderID int) (sts []ScheduledTransfer, err error) {
+	ctx, span := t.start(ctx, "ListScheduledTransfers", attribute.Int("user.id", senderID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListScheduledTransfers(ctx, senderID)
+}
+
+func (t *TracedStorage) ListDueScheduledTransfers(ctx context.Context, now time.Time, limit int) (sts []ScheduledTransfer, err error) {
+	ctx, span := t.start(ctx, "ListDueScheduledTransfers", attribute.Int("limit", limit))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListDueScheduledTransfers(ctx, now, limit)
+}
+
+func (t *TracedStorage) CancelScheduledTransfer(ctx context.Context, id int) (err error) {
+	ctx, span := t.start(ctx, "CancelScheduledTransfer", attribute.Int("scheduled_transfer.id", id))
+	defer func() { tracing.End(span, err) }()
+	return t.next.CancelScheduledTransfer(ctx, id)
+}
+
+func (t *TracedStorage) RunScheduledTransfer(ctx context.Context, st *ScheduledTransfer, next *time.Time, now time.Time, limits TransferLimits, fee TransferFee, audit *TxAudit) (err error) {
+	ctx, span := t.start(ctx, "RunScheduledTransfer", attribute.Int("scheduled_transfer.id", st.ID), attribute.Int("runs", st.Runs),
+		attribute.Int("shop.fee", fee.Amount))
+	defer func() { tracing.End(span, err) }()
+	return t.next.RunScheduledTransfer(ctx, st, next, now, limits, fee, audit)
+}
+
+func (t *TracedStorage) GetUserRole(ctx context.Context, id int) (role string, err error) {
+	ctx, span := t.start(ctx, "GetUserRole", attribute.Int("user.id", id))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetUserRole(ctx, id)
+}
+
+func (t *TracedStorage) SetUserRole(ctx context.Context, username, role string) (err error) {
+	ctx, span := t.start(ctx, "SetUserRole", attribute.String("role", role))
+	defer func() { tracing.End(span, err) }()
+	return t.next.SetUserRole(ctx, username, role)
+}
+
+func (t *TracedStorage) EnsureSystemAccount(ctx context.Context, username string) (_ int, err error) {
+	ctx, span := t.start(ctx, "EnsureSystemAccount")
+	defer func() { tracing.End(span, err) }()
+	return t.next.EnsureSystemAccount(ctx, username)
+}
+
+func (t *TracedStorage) GetTransferTotals(ctx context.Context, userID int, since time.Time) (sent, received int, err error) {
+	ctx, span := t.start(ctx, "GetTransferTotals", attribute.Int("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetTransferTotals(ctx, userID, since)
+}
+
+func (t *TracedStorage) GetCoinLots(ctx context.Context, userID int) (_ []CoinLot, err error) {
+	ctx, span := t.start(ctx, "GetCoinLots", attribute.Int("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetCoinLots(ctx, userID)
+}
+
+func (t *TracedStorage) ListExpiredCoins(ctx context.Context, cutoff time.Time, limit int) (_ []ExpiredCoins, err error) {
+	ctx, span := t.start(ctx, "ListExpiredCoins", attribute.Int("limit", limit))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListExpiredCoins(ctx, cutoff, limit)
+}
+
+func (t *TracedStorage) ExpireCoins(ctx context.Context, userID, sinkID int, cutoff, now time.Time, audit *TxAudit) (_ int, err error) {
+	ctx, span := t.start(ctx, "ExpireCoins", attribute.Int("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ExpireCoins(ctx, userID, sinkID, cutoff, now, audit)
+}
+
+func (t *TracedStorage) CreatePromoCode(ctx context.Context, pc *PromoCode) (err error) {
+	ctx, span := t.start(ctx, "CreatePromoCode", attribute.String("shop.promo_code", pc.Code))
+	defer func() { tracing.End(span, err) }()
+	return t.next.CreatePromoCode(ctx, pc)
+}
+
+func (t *TracedStorage) GetPromoCode(ctx context.Context, code string) (_ *PromoCode, err error) {
+	ctx, span := t.start(ctx, "GetPromoCode", attribute.String("shop.promo_code", code))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetPromoCode(ctx, code)
+}
+
+func (t *TracedStorage) ListPromoCodes(ctx context.Context) (_ []PromoCode, err error) {
+	ctx, span := t.start(ctx, "ListPromoCodes")
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListPromoCodes(ctx)
+}
+
+func (t *TracedStorage) DisablePromoCode(ctx context.Context, code string, now time.Time) (err error) {
+	ctx, span := t.start(ctx, "DisablePromoCode", attribute.String("shop.promo_code", code))
+	defer func() { tracing.End(span, err) }()
+	return t.next.DisablePromoCode(ctx, code, now)
+}
+
+func (t *TracedStorage) CountPromoRedemptions(ctx context.Context, promoCodeID, userID int) (_ int, err error) {
+	ctx, span := t.start(ctx, "CountPromoRedemptions", attribute.Int("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.CountPromoRedemptions(ctx, promoCodeID, userID)
+}
+
+func (t *TracedStorage) CreateItemPrice(ctx context.Context, p *ItemPrice) (err error) {
+	ctx, span := t.start(ctx, "CreateItemPrice", attribute.String("shop.item", p.Item))
+	defer func() { tracing.End(span, err) }()
+	return t.next.CreateItemPrice(ctx, p)
+}
+
+func (t *TracedStorage) GetItemPrice(ctx context.Context, id int) (_ *ItemPrice, err error) {
+	ctx, span := t.start(ctx, "GetItemPrice", attribute.Int("item_price.id", id))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetItemPrice(ctx, id)
+}
+
+func (t *TracedStorage) ListItemPrices(ctx context.Context, now time.Time) (_ []ItemPrice, err error) {
+	ctx, span := t.start(ctx, "ListItemPrices")
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListItemPrices(ctx, now)
+}
+
+func (t *TracedStorage) DeleteItemPrice(ctx context.Context, id int) (err error) {
+	ctx, span := t.start(ctx, "DeleteItemPrice", attribute.Int("item_price.id", id))
+	defer func() { tracing.End(span, err) }()
+	return t.next.DeleteItemPrice(ctx, id)
+}
+
+func (t *TracedStorage) ListOrders(ctx context.Context, userID int) (_ []Order, err error) {
+	ctx, span := t.start(ctx, "ListOrders", attribute.Int("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListOrders(ctx, userID)
+}
+
+func (t *TracedStorage) GetLeaderboard(ctx context.Context, board string, since *time.Time, limit int) (_ []LeaderboardEntry, err error) {
+	ctx, span := t.start(ctx, "GetLeaderboard", attribute.String("shop.board", board))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetLeaderboard(ctx, board, since, limit)
+}
+
+func (t *TracedStorage) SetLeaderboardOptOut(ctx context.Context, userID int, optOut bool) (err error) {
+	ctx, span := t.start(ctx, "SetLeaderboardOptOut", attribute.Int("user.id", userID), attribute.Bool("opt_out", optOut))
+	defer func() { tracing.End(span, err) }()
+	return t.next.SetLeaderboardOptOut(ctx, userID, optOut)
+}
+
+func (t *TracedStorage) GetAchievementProgress(ctx context.Context, userID int) (_ *AchievementProgress, err error) {
+	ctx, span := t.start(ctx, "GetAchievementProgress", attribute.Int("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetAchievementProgress(ctx, userID)
+}
+
+func (t *TracedStorage) AwardAchievement(ctx context.Context, userID int, a *Achievement) (_ bool, err error) {
+	ctx, span := t.start(ctx, "AwardAchievement", attribute.Int("user.id", userID), attribute.String("achievement.code", a.Code))
+	defer func() { tracing.End(span, err) }()
+	return t.next.AwardAchievement(ctx, userID, a)
+}
+
+func (t *TracedStorage) ListAchievements(ctx context.Context, userID int) (_ []Achievement, err error) {
+	ctx, span := t.start(ctx, "ListAchievements", attribute.Int("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListAchievements(ctx, userID)
+}
+
+func (t *TracedStorage) CreateGroup(ctx context.Context, g *Group, ownerID int) (err error) {
+	ctx, span := t.start(ctx, "CreateGroup", attribute.String("group.name", g.Name), attribute.Int("user.id", ownerID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.CreateGroup(ctx, g, ownerID)
+}
+
+func (t *TracedStorage) GetGroup(ctx context.Context, name string) (_ *Group, err error) {
+	ctx, span := t.start(ctx, "GetGroup", attribute.String("group.name", name))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetGroup(ctx, name)
+}
+
+func (t *TracedStorage) ListUserGroups(ctx context.Context, userID int) (_ []Group, err error) {
+	ctx, span := t.start(ctx, "ListUserGroups", attribute.Int("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListUserGroups(ctx, userID)
+}
+
+func (t *TracedStorage) ListGroupMembers(ctx context.Context, groupID int) (_ []GroupMember, err error) {
+	ctx, span := t.start(ctx, "ListGroupMembers", attribute.Int("group.id", groupID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListGroupMembers(ctx, groupID)
+}
+
+func (t *TracedStorage) GetGroupMemberRole(ctx context.Context, groupID, userID int) (_ string, err error) {
+	ctx, span := t.start(ctx, "GetGroupMemberRole", attribute.Int("group.id", groupID), attribute.Int("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetGroupMemberRole(ctx, groupID, userID)
+}
+
+func (t *TracedStorage) SetGroupMember(ctx context.Context, groupID, userID int, role string, at time.Time) (err error) {
+	ctx, span := t.start(ctx, "SetGroupMember", attribute.Int("group.id", groupID), attribute.Int("user.id", userID), attribute.String("group.role", role))
+	defer func() { tracing.End(span, err) }()
+	return t.next.SetGroupMember(ctx, groupID, userID, role, at)
+}
+
+func (t *TracedStorage) RemoveGroupMember(ctx context.Context, groupID, userID int) (err error) {
+	ctx, span := t.start(ctx, "RemoveGroupMember", attribute.Int("group.id", groupID), attribute.Int("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.RemoveGroupMember(ctx, groupID, userID)
+}
+
+func (t *TracedStorage) CreateGroupPurchase(ctx context.Context, p *GroupPurchase, proposerID int) (err error) {
+	ctx, span := t.start(ctx, "CreateGroupPurchase", attribute.Int("group.id", p.GroupID), attribute.String("shop.item", p.Item))
+	defer func() { tracing.End(span, err) }()
+	return t.next.CreateGroupPurchase(ctx, p, proposerID)
+}
+
+func (t *TracedStorage) GetGroupPurchase(ctx context.Context, id int) (_ *GroupPurchase, err error) {
+	ctx, span := t.start(ctx, "GetGroupPurchase", attribute.Int("group_purchase.id", id))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetGroupPurchase(ctx, id)
+}
+
+func (t *TracedStorage) ListGroupPurchases(ctx context.Context, groupID int) (_ []GroupPurchase, err error) {
+	ctx, span := t.start(ctx, "ListGroupPurchases", attribute.Int("group.id", groupID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListGroupPurchases(ctx, groupID)
+}
+
+func (t *TracedStorage) ApproveGroupPurchase(ctx context.Context, id, userID, price, priceID int, at time.Time, audit *TxAudit) (_ *GroupPurchase, err error) {
+	ctx, span := t.start(ctx, "ApproveGroupPurchase", attribute.Int("group_purchase.id", id), attribute.Int("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ApproveGroupPurchase(ctx, id, userID, price, priceID, at, audit)
+}
+
+func (t *TracedStorage) CancelGroupPurchase(ctx context.Context, id int, at time.Time) (err error) {
+	ctx, span := t.start(ctx, "CancelGroupPurchase", attribute.Int("group_purchase.id", id))
+	defer func() { tracing.End(span, err) }()
+	return t.next.CancelGroupPurchase(ctx, id, at)
+}
+
+func (t *TracedStorage) CreatePendingTransfer(ctx context.Context, pt *PendingTransfer, limits TransferLimits, audit *TxAudit) (err error) {
+	ctx, span := t.start(ctx, "CreatePendingTransfer", attribute.Int("shop.amount", pt.Amount), attribute.Int("shop.fee", pt.Fee))
+	defer func() { tracing.End(span, err) }()
+	return t.next.CreatePendingTransfer(ctx, pt, limits, audit)
+}
+
+func (t *TracedStorage) GetPendingTransfer(ctx context.Context, id int) (_ *PendingTransfer, err error) {
+	ctx, span := t.start(ctx, "GetPendingTransfer", attribute.Int("pending_transfer.id", id))
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetPendingTransfer(ctx, id)
+}
+
+func (t *TracedStorage) ListPendingTransfers(ctx context.Context, senderID int, now time.Time) (_ []PendingTransfer, err error) {
+	ctx, span := t.start(ctx, "ListPendingTransfers", attribute.Int("user.id", senderID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListPendingTransfers(ctx, senderID, now)
+}
+
+func (t *TracedStorage) ListExpiredPendingTransfers(ctx context.Context, now time.Time, limit int) (_ []PendingTransfer, err error) {
+	ctx, span := t.start(ctx, "ListExpiredPendingTransfers", attribute.Int("limit", limit))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListExpiredPendingTransfers(ctx, now, limit)
+}
+
+func (t *TracedStorage) ResolvePendingTransfer(ctx context.Context, id int, status string, resolverID int, at time.Time, limits TransferLimits, audit *TxAudit) (_ *PendingTransfer, err error) {
+	ctx, span := t.start(ctx, "ResolvePendingTransfer", attribute.Int("pending_transfer.id", id), attribute.String("status", status))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ResolvePendingTransfer(ctx, id, status, resolverID, at, limits, audit)
+}
+
+func (t *TracedStorage) ReverseTransaction(ctx context.Context, rv *TransferReversal, audit *TxAudit) (err error) {
+	ctx, span := t.start(ctx, "ReverseTransaction", attribute.Int("transaction.id", rv.TransactionID), attribute.String("policy", rv.Policy))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ReverseTransaction(ctx, rv, audit)
+}
+
+func (t *TracedStorage) ListUserTransactions(ctx context.Context, userID, limit int) (_ []Transaction, err error) {
+	ctx, span := t.start(ctx, "ListUserTransactions", attribute.Int("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListUserTransactions(ctx, userID, limit)
+}
+
+func (t *TracedStorage) AppendAudit(ctx context.Context, e *AuditEntry) (err error) {
+	ctx, span := t.start(ctx, "AppendAudit", attribute.String("audit.action", e.Action))
+	defer func() { tracing.End(span, err) }()
+	return t.next.AppendAudit(ctx, e)
+}
+
+func (t *TracedStorage) ListAudit(ctx context.Context, filter AuditFilter) (_ []AuditEntry, err error) {
+	ctx, span := t.start(ctx, "ListAudit", attribute.Int("limit", filter.Limit))
+	defer func() { tracing.End(span, err) }()
+	return t.next.ListAudit(ctx, filter)
+}
+
+func (t *TracedStorage) GetAuditHead(ctx context.Context) (_ *AuditHead, err error) {
+	ctx, span := t.start(ctx, "GetAuditHead")
+	defer func() { tracing.End(span, err) }()
+	return t.next.GetAuditHead(ctx)
+}
@@ -0,0 +1,298 @@
+package shop
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"avito-shop/internal/schedule"
+	"avito-shop/internal/service/shop/storage"
+)
+
+const (
+	// MaxTransferAmount — верхняя граница одного перевода, отсекает заведомо ошибочные суммы.
+	MaxTransferAmount = 1_000_000
+	// MaxUsernameLength совпадает с размером колонки users.username.
+	MaxUsernameLength = 255
+	// MaxMemoLength совпадает с размером колонки transactions.memo (в символах).
+	MaxMemoLength = 255
+	// MaxScheduleLength совпадает с размером колонки scheduled_transfers.schedule.
+	MaxScheduleLength = 64
+	// MaxGroupApprovals — верхняя граница числа одобрений, нужных для покупки группы.
+	MaxGroupApprovals = 100
+)
+
+// promoCodePattern — допустимый промокод после приведения к верхнему регистру;
+// длина ограничена размером колонки promo_codes.code.
+var promoCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+// TransferCategories — допустимые категории перевода.
+var TransferCategories = []string{"thanks", "bet", "lunch", "gift", "other"}
+
+var usernamePattern = regexp.MustCompile(`^[\p{L}\p{N}_.@-]+$`)
+
+// FieldError описывает нарушение для одного поля запроса.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError содержит все нарушения, найденные в запросе.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", f.Field, f.Message))
+	}
+	return fmt.Sprintf("%s: %s", ErrValidation.Error(), strings.Join(msgs, "; "))
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func (e *ValidationError) add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+func (e *ValidationError) orNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func validateUsername(ve *ValidationError, field, username string) {
+	switch {
+	case username == "":
+		ve.add(field, "имя пользователя не указано")
+	case len(username) > MaxUsernameLength:
+		ve.add(field, fmt.Sprintf("имя пользователя длиннее %d символов", MaxUsernameLength))
+	case !usernamePattern.MatchString(username):
+		ve.add(field, "имя пользователя может содержать только буквы, цифры и символы _ . @ -")
+	}
+}
+
+// ValidateUsername проверяет имя нового пользователя по тем же правилам, что и получателя перевода,
+// чтобы любой зарегистрированный пользователь мог получать монеты.
+func ValidateUsername(username string) error {
+	ve := &ValidationError{}
+	validateUsername(ve, "username", username)
+	return ve.orNil()
+}
+
+// ValidateSendCoinRequest проверяет перевод и возвращает *ValidationError со всеми найденными нарушениями.
+func ValidateSendCoinRequest(fromUsername string, scr *storage.SendCoinRequest) error {
+	ve := &ValidationError{}
+	validateTransfer(ve, fromUsername, scr)
+	return ve.orNil()
+}
+
+// ValidateScheduledTransferInput проверяет отложенный перевод: те же правила, что у перевода,
+// плюс время первого запуска в будущем и корректное cron-расписание.
+func ValidateScheduledTransferInput(fromUsername string, in *ScheduledTransferInput, now time.Time) error {
+	ve := &ValidationError{}
+	validateTransfer(ve, fromUsername, &in.SendCoinRequest)
+
+	if in.RunAt == nil && in.Schedule == "" {
+		ve.add("runAt", "укажите время запуска runAt или расписание schedule")
+	}
+	if in.RunAt != nil && !in.RunAt.After(now) {
+		ve.add("runAt", "время запуска должно быть в будущем")
+	}
+	if in.Schedule != "" {
+		if len(in.Schedule) > MaxScheduleLength {
+			ve.add("schedule", fmt.Sprintf("расписание длиннее %d символов", MaxScheduleLength))
+		} else if sched, err := schedule.Parse(in.Schedule); err != nil {
+			ve.add("schedule", fmt.Sprintf("некорректное расписание: %v", err))
+		} else if _, err = sched.Next(now); err != nil {
+			ve.add("schedule", err.Error())
+		}
+	}
+
+	return ve.orNil()
+}
+
+func validateTransfer(ve *ValidationError, fromUsername string, scr *storage.SendCoinRequest) {
+	validateUsername(ve, "toUser", scr.ToUser)
+	if scr.ToUser != "" && strings.EqualFold(scr.ToUser, fromUsername) {
+		ve.add("toUser", "нельзя отправить монеты самому себе")
+	}
+
+	switch {
+	case scr.Amount <= 0:
+		ve.add("amount", "сумма перевода должна быть положительной")
+	case scr.Amount > MaxTransferAmount:
+		ve.add("amount", fmt.Sprintf("сумма перевода не может превышать %d", MaxTransferAmount))
+	}
+
+	if utf8.RuneCountInString(scr.Memo) > MaxMemoLength {
+		ve.add("memo", fmt.Sprintf("комментарий длиннее %d символов", MaxMemoLength))
+	}
+	validateCategory(ve, "category", scr.Category)
+}
+
+// ValidatePaymentRequestInput проверяет запрос на перевод так же, как перевод в обратную сторону.
+func ValidatePaymentRequestInput(requester string, req *PaymentRequestInput) error {
+	ve := &ValidationError{}
+
+	validateUsername(ve, "fromUser", req.FromUser)
+	if req.FromUser != "" && strings.EqualFold(req.FromUser, requester) {
+		ve.add("fromUser", "нельзя запросить монеты у самого себя")
+	}
+
+	switch {
+	case req.Amount <= 0:
+		ve.add("amount", "сумма запроса должна быть положительной")
+	case req.Amount > MaxTransferAmount:
+		ve.add("amount", fmt.Sprintf("сумма запроса не может превышать %d", MaxTransferAmount))
+	}
+
+	if utf8.RuneCountInString(req.Memo) > MaxMemoLength {
+		ve.add("memo", fmt.Sprintf("комментарий длиннее %d символов", MaxMemoLength))
+	}
+
+	return ve.orNil()
+}
+
+// ValidateHistoryFilter проверяет фильтр истории; кроме категорий переводов допускаются
+// служебные storage.FeeCategory, storage.ExpiredCategory и storage.ReversalCategory, чтобы получить
+// только комиссии, сгорания или отмены.
+func ValidateHistoryFilter(filter storage.HistoryFilter) error {
+	switch filter.Category {
+	case storage.FeeCategory, storage.ExpiredCategory, storage.ReversalCategory:
+		return nil
+	}
+	ve := &ValidationError{}
+	validateCategory(ve, "category", filter.Category)
+	return ve.orNil()
+}
+
+// ValidatePromoCodeInput проверяет новый промокод; in.Code должен быть уже нормализован NormalizePromoCode.
+func ValidatePromoCodeInput(in *PromoCodeInput) error {
+	ve := &ValidationError{}
+
+	switch {
+	case in.Code == "":
+		ve.add("code", "промокод не указан")
+	case !promoCodePattern.MatchString(in.Code):
+		ve.add("code", "промокод — от 3 до 32 латинских букв, цифр и символов _ -")
+	}
+
+	switch in.Kind {
+	case storage.PromoPercent:
+		if in.Value < 1 || in.Value > 100 {
+			ve.add("value", "скидка в процентах должна быть от 1 до 100")
+		}
+	case storage.PromoFixed:
+		if in.Value < 1 || in.Value > MaxTransferAmount {
+			ve.add("value", fmt.Sprintf("скидка должна быть от 1 до %d монет", MaxTransferAmount))
+		}
+	default:
+		ve.add("kind", fmt.Sprintf("вид скидки должен быть %s или %s", storage.PromoPercent, storage.PromoFixed))
+	}
+
+	for i, item := range in.Items {
+		if _, ok := storage.MerchItems[item]; !ok {
+			ve.add("items", fmt.Sprintf("неизвестный предмет '%s'", item))
+		} else if slices.Contains(in.Items[:i], item) {
+			ve.add("items", fmt.Sprintf("предмет '%s' указан дважды", item))
+		}
+	}
+
+	if in.MaxUses < 0 {
+		ve.add("maxUses", "лимит не может быть отрицательным")
+	}
+	if in.MaxUsesPerUser < 0 {
+		ve.add("maxUsesPerUser", "лимит не может быть отрицательным")
+	}
+	if in.StartsAt != nil && in.EndsAt != nil && !in.EndsAt.After(*in.StartsAt) {
+		ve.add("endsAt", "окончание действия должно быть позже начала")
+	}
+
+	return ve.orNil()
+}
+
+// ValidateItemPriceInput проверяет запланированную цену; in.StartsAt должен быть уже заполнен.
+func ValidateItemPriceInput(in *ItemPriceInput) error {
+	ve := &ValidationError{}
+
+	if _, ok := storage.MerchItems[in.Item]; !ok {
+		ve.add("item", fmt.Sprintf("неизвестный предмет '%s'", in.Item))
+	}
+	if in.Price < 1 || in.Price > MaxTransferAmount {
+		ve.add("price", fmt.Sprintf("цена должна быть от 1 до %d монет", MaxTransferAmount))
+	}
+	if in.EndsAt != nil && !in.EndsAt.After(*in.StartsAt) {
+		ve.add("endsAt", "окончание распродажи должно быть позже начала")
+	}
+
+	return ve.orNil()
+}
+
+// ValidateGroupInput проверяет новую группу: имя подчиняется тем же правилам, что имя пользователя,
+// потому что кошелёк группы — счёт в таблице пользователей.
+func ValidateGroupInput(in *GroupInput) error {
+	ve := &ValidationError{}
+	validateUsername(ve, "name", in.Name)
+	if in.ApprovalsRequired < 1 || in.ApprovalsRequired > MaxGroupApprovals {
+		ve.add("approvalsRequired", fmt.Sprintf("число одобрений должно быть от 1 до %d", MaxGroupApprovals))
+	}
+	return ve.orNil()
+}
+
+// ValidateGroupMemberInput проверяет участника группы и его роль.
+func ValidateGroupMemberInput(in *GroupMemberInput) error {
+	ve := &ValidationError{}
+	validateUsername(ve, "username", in.Username)
+	if in.Role != storage.GroupRoleAdmin && in.Role != storage.GroupRoleMember {
+		ve.add("role", fmt.Sprintf("роль должна быть %s или %s", storage.GroupRoleAdmin, storage.GroupRoleMember))
+	}
+	return ve.orNil()
+}
+
+// ValidateReversalInput проверяет отмену перевода: пустая политика означает storage.ReversalPartial,
+// причина обязательна и попадает в memo компенсирующей записи.
+func ValidateReversalInput(in *ReversalInput) error {
+	ve := &ValidationError{}
+	if in.TransactionID < 1 {
+		ve.add("id", "идентификатор перевода должен быть положительным")
+	}
+	if in.Policy != storage.ReversalPartial && in.Policy != storage.ReversalNegative {
+		ve.add("policy", fmt.Sprintf("политика должна быть %s или %s", storage.ReversalPartial, storage.ReversalNegative))
+	}
+	switch {
+	case strings.TrimSpace(in.Reason) == "":
+		ve.add("reason", "причина отмены не указана")
+	case utf8.RuneCountInString(in.Reason) > MaxMemoLength:
+		ve.add("reason", fmt.Sprintf("причина длиннее %d символов", MaxMemoLength))
+	}
+	return ve.orNil()
+}
+
+// ValidateAuditFilter проверяет фильтр журнала аудита.
+func ValidateAuditFilter(filter storage.AuditFilter) error {
+	ve := &ValidationError{}
+	if filter.Limit < 1 || filter.Limit > MaxAuditLimit {
+		ve.add("limit", fmt.Sprintf("лимит должен быть от 1 до %d", MaxAuditLimit))
+	}
+	if filter.AfterID < 0 {
+		ve.add("afterId", "идентификатор записи не может быть отрицательным")
+	}
+	if filter.From != nil && filter.To != nil && !filter.To.After(*filter.From) {
+		ve.add("to", "конец периода должен быть позже начала")
+	}
+	return ve.orNil()
+}
+
+func validateCategory(ve *ValidationError, field, category string) {
+	if category != "" && !slices.Contains(TransferCategories, category) {
+		ve.add(field, fmt.Sprintf("неизвестная категория, допустимы: %s", strings.Join(TransferCategories, ", ")))
+	}
+}
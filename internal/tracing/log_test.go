@@ -0,0 +1,40 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"avito-shop/internal/tracing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestLogHandler_InjectsTraceIDs(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(tracing.NewLogHandler(slog.NewJSONHandler(&buf, nil))).With(slog.String("component", "test"))
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	log.InfoContext(ctx, "with span")
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["trace_id"])
+	require.Equal(t, "00f067aa0ba902b7", record["span_id"])
+	require.Equal(t, "test", record["component"])
+
+	buf.Reset()
+	log.InfoContext(context.Background(), "without span")
+	record = nil
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	require.NotContains(t, record, "trace_id")
+}